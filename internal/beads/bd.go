@@ -0,0 +1,442 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// TraceEnv enables call tracing: "1" or "stderr" prints a line per bd call
+// to stderr, any other value is a file that gets one JSON object per call.
+const TraceEnv = "GT_BD_TRACE"
+
+// DefaultTimeout is the Timeout of a new Cmd. Calls that follow a stream
+// or wait on a gate set Timeout to zero.
+const DefaultTimeout = 5 * time.Minute
+
+// DefaultLockRetries is the LockRetries of a new Cmd. A call that hit the
+// lock made no change, so writes retry too.
+const DefaultLockRetries = 3
+
+// Cmd is one bd invocation. Every bd call gt makes goes through Cmd, so
+// environment handling, timeouts, lock retries and tracing live in one
+// place. It mirrors exec.Cmd: set fields, then call Run, Output,
+// CombinedOutput or Start/Wait once.
+type Cmd struct {
+	Args []string // Arguments after "bd"
+	Dir  string
+	Env  []string // As exec.Cmd: nil inherits gt's environment
+
+	// BeadsDir and Actor set BEADS_DIR and BD_ACTOR, overriding Env and
+	// inherited values. Empty leaves them alone.
+	BeadsDir string
+	Actor    string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Timeout kills bd after this long; DefaultTimeout unless changed.
+	// Zero means no limit beyond the context's.
+	Timeout time.Duration
+
+	// LockRetries reruns a call that failed on database lock contention,
+	// with backoff; DefaultLockRetries unless changed. Calls that stream
+	// stdin or stdout are never retried. When a call can retry, Stderr
+	// gets only the last attempt's output, after bd exits.
+	LockRetries int
+
+	Process      *os.Process      // Set by Start
+	ProcessState *os.ProcessState // Set when bd exits
+
+	parent   context.Context // The caller's context, for observers
+	ctx      context.Context
+	cancel   context.CancelFunc
+	proc     *exec.Cmd
+	stderr   bytes.Buffer // Copy of stderr for errors and tracing
+	hold     bool         // Keep stderr from Stderr until the last attempt
+	start    time.Time
+	attempts int
+}
+
+// Call describes a finished bd invocation, as passed to call observers.
+type Call struct {
+	Context  context.Context `json:"-"`
+	Args     []string        `json:"args"`
+	Dir      string          `json:"dir,omitempty"`
+	BeadsDir string          `json:"beads_dir,omitempty"` // Effective BEADS_DIR
+	Start    time.Time       `json:"start"`
+	Duration time.Duration   `json:"duration_ns"`
+	Attempts int             `json:"attempts"`
+	ExitCode int             `json:"exit_code"` // -1 if bd didn't run or was killed
+	Error    string          `json:"error,omitempty"`
+	Stderr   string          `json:"stderr,omitempty"`
+}
+
+// Command returns a Cmd that runs bd with args.
+func Command(args ...string) *Cmd {
+	return CommandContext(context.Background(), args...)
+}
+
+// CommandContext returns a Cmd that is killed when ctx is done.
+func CommandContext(ctx context.Context, args ...string) *Cmd {
+	return &Cmd{
+		Args:        args,
+		Timeout:     DefaultTimeout,
+		LockRetries: DefaultLockRetries,
+		parent:      ctx,
+		ctx:         ctx,
+	}
+}
+
+// String returns the command line, for messages.
+func (c *Cmd) String() string {
+	return strings.TrimSpace("bd " + strings.Join(c.Args, " "))
+}
+
+// Run runs bd and waits for it to exit.
+func (c *Cmd) Run() error {
+	return c.run(c.Stdout, nil)
+}
+
+// Output runs bd and returns its stdout. As with exec.Cmd, when Stderr is
+// unset a returned *exec.ExitError carries bd's stderr.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("beads: Stdout already set")
+	}
+	var stdout bytes.Buffer
+	err := c.run(&stdout, stdout.Reset)
+	var exitErr *exec.ExitError
+	if c.Stderr == nil && errors.As(err, &exitErr) {
+		exitErr.Stderr = c.stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs bd and returns stdout and stderr together.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil || c.Stderr != nil {
+		return nil, errors.New("beads: Stdout or Stderr already set")
+	}
+	var out syncBuffer
+	c.Stderr = &out
+	err := c.run(&out, out.Reset)
+	return out.Bytes(), err
+}
+
+// StdoutPipe returns a pipe connected to bd's stdout once Start runs.
+func (c *Cmd) StdoutPipe() (io.ReadCloser, error) {
+	return c.prepare().StdoutPipe()
+}
+
+// Start starts bd without waiting for it. Call Wait to release it.
+func (c *Cmd) Start() error {
+	p := c.prepare()
+	if p.Stdout == nil {
+		p.Stdout = c.Stdout
+	}
+	c.start = time.Now()
+	c.attempts = 1
+	if err := p.Start(); err != nil {
+		return c.finish(err)
+	}
+	c.Process = p.Process
+	return nil
+}
+
+// Wait waits for a started bd to exit.
+func (c *Cmd) Wait() error {
+	if c.proc == nil || c.Process == nil {
+		return errors.New("beads: not started")
+	}
+	return c.finish(c.proc.Wait())
+}
+
+// run runs bd to completion, retrying on lock contention when the caller
+// doesn't see partial output. reset discards output before a retry.
+func (c *Cmd) run(stdout io.Writer, reset func()) error {
+	c.start = time.Now()
+	canRetry := c.LockRetries > 0 && c.Stdin == nil && (stdout == nil || reset != nil)
+	c.hold = canRetry && c.Stderr != nil
+	for {
+		c.stderr.Reset()
+		c.proc = nil
+		p := c.prepare()
+		p.Stdout = stdout
+		c.attempts++
+		err := p.Run()
+		c.Process, c.ProcessState = p.Process, p.ProcessState
+
+		if err == nil || !canRetry || c.attempts > c.LockRetries || !isLockContention(c.stderr.String()) {
+			if c.hold {
+				_, _ = c.Stderr.Write(c.stderr.Bytes())
+			}
+			return c.finish(err)
+		}
+		if reset != nil {
+			reset()
+		}
+		select {
+		case <-time.After(lockBackoff(c.attempts)):
+		case <-c.context().Done():
+			if c.hold {
+				_, _ = c.Stderr.Write(c.stderr.Bytes())
+			}
+			return c.finish(c.context().Err())
+		}
+	}
+}
+
+// Exec replaces gt with bd, for commands that hand the terminal to bd for
+// good (`gt feed`). bd is resolved and given its environment as Run would;
+// with gt gone there is no timeout, retry or observer. It returns only if
+// bd could not be started.
+func (c *Cmd) Exec() error {
+	path, err := exec.LookPath("bd")
+	if err != nil {
+		return fmt.Errorf("bd not found in PATH: %w", err)
+	}
+	if c.Dir != "" {
+		if err := os.Chdir(c.Dir); err != nil {
+			return fmt.Errorf("changing to directory %s: %w", c.Dir, err)
+		}
+	}
+	env := c.environ()
+	if env == nil {
+		env = os.Environ()
+	}
+	return syscall.Exec(path, append([]string{"bd"}, c.Args...), env) //nolint:gosec // G204: bd is a trusted internal tool
+}
+
+// prepare builds the exec.Cmd for one attempt, or returns the one
+// StdoutPipe already built.
+func (c *Cmd) prepare() *exec.Cmd {
+	if c.proc != nil {
+		return c.proc
+	}
+	if c.ctx == nil {
+		c.ctx = context.Background()
+	}
+	if c.Timeout > 0 && c.cancel == nil {
+		c.ctx, c.cancel = context.WithTimeout(c.ctx, c.Timeout)
+	}
+	p := exec.CommandContext(c.ctx, "bd", c.Args...) //nolint:gosec // G204: bd is a trusted internal tool
+	p.Dir = c.Dir
+	p.Env = c.environ()
+	p.Stdin = c.Stdin
+	p.Stderr = &c.stderr
+	if c.Stderr != nil && !c.hold {
+		p.Stderr = io.MultiWriter(&c.stderr, c.Stderr)
+	}
+	c.proc = p
+	return p
+}
+
+func (c *Cmd) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// environ applies BeadsDir and Actor over Env or the inherited environment.
+func (c *Cmd) environ() []string {
+	if c.BeadsDir == "" && c.Actor == "" {
+		return c.Env
+	}
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	if c.BeadsDir != "" {
+		env = setEnv(env, "BEADS_DIR", c.BeadsDir)
+	}
+	if c.Actor != "" {
+		env = setEnv(env, "BD_ACTOR", c.Actor)
+	}
+	return env
+}
+
+func setEnv(env []string, key, value string) []string {
+	out := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			out = append(out, kv)
+		}
+	}
+	return append(out, key+"="+value)
+}
+
+// finish releases the timeout, reports the call to observers and returns
+// err, reworded if the timeout killed bd.
+func (c *Cmd) finish(err error) error {
+	if err != nil && c.Timeout > 0 && errors.Is(c.context().Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%s: timed out after %s: %w", c.String(), c.Timeout, context.DeadlineExceeded)
+	}
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	if c.proc != nil && c.ProcessState == nil {
+		c.ProcessState = c.proc.ProcessState
+	}
+	parent := c.parent
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	call := Call{
+		Context:  parent,
+		Args:     c.Args,
+		Dir:      c.Dir,
+		BeadsDir: c.effectiveBeadsDir(),
+		Start:    c.start,
+		Duration: time.Since(c.start),
+		Attempts: c.attempts,
+		ExitCode: -1,
+		Stderr:   strings.TrimSpace(c.stderr.String()),
+	}
+	if c.ProcessState != nil {
+		call.ExitCode = c.ProcessState.ExitCode()
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	notify(call)
+	return err
+}
+
+func (c *Cmd) effectiveBeadsDir() string {
+	if c.BeadsDir != "" {
+		return c.BeadsDir
+	}
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	for i := len(env) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(env[i], "BEADS_DIR="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// isLockContention reports whether bd failed because another process held
+// the database. ZFC: stderr is matched here only to decide a retry, like
+// ErrNotFound; the error itself still goes to the caller.
+func isLockContention(stderr string) bool {
+	s := strings.ToLower(stderr)
+	return strings.Contains(s, "database is locked") ||
+		strings.Contains(s, "sqlite_busy") ||
+		strings.Contains(s, "lock held")
+}
+
+func lockBackoff(attempt int) time.Duration {
+	d := 100 * time.Millisecond << (attempt - 1)
+	if d > 2*time.Second {
+		d = 2 * time.Second
+	}
+	return d
+}
+
+// syncBuffer is a bytes.Buffer shared by stdout and stderr.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+var (
+	observersMu sync.RWMutex
+	observers   []func(Call)
+	traceOnce   sync.Once
+)
+
+// OnCall registers fn to receive every finished bd call, e.g. for latency
+// logging. fn must be fast and must not run bd.
+func OnCall(fn func(Call)) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	observers = append(observers, fn)
+}
+
+func notify(call Call) {
+	traceOnce.Do(func() {
+		if v := os.Getenv(TraceEnv); v != "" && v != "0" {
+			OnCall(traceWriter(v))
+		}
+	})
+	observersMu.RLock()
+	defer observersMu.RUnlock()
+	for _, fn := range observers {
+		fn(call)
+	}
+}
+
+// traceWriter dumps calls for GT_BD_TRACE: readable lines on stderr, or
+// JSON lines appended to a file.
+func traceWriter(dest string) func(Call) {
+	var mu sync.Mutex
+	if dest == "1" || dest == "stderr" {
+		return func(call Call) {
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(os.Stderr, "[bd %s] exit=%d %s", call.Duration.Round(time.Millisecond), call.ExitCode, strings.Join(call.Args, " "))
+			if call.Dir != "" {
+				fmt.Fprintf(os.Stderr, " (dir=%s)", call.Dir)
+			}
+			if call.BeadsDir != "" {
+				fmt.Fprintf(os.Stderr, " (BEADS_DIR=%s)", call.BeadsDir)
+			}
+			if call.Attempts > 1 {
+				fmt.Fprintf(os.Stderr, " (attempts=%d)", call.Attempts)
+			}
+			fmt.Fprintln(os.Stderr)
+			if call.Error != "" && call.Stderr != "" {
+				fmt.Fprintf(os.Stderr, "    %s\n", strings.ReplaceAll(call.Stderr, "\n", "\n    "))
+			}
+		}
+	}
+	return func(call Call) {
+		line, err := json.Marshal(call)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		f, err := os.OpenFile(dest, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		_, _ = f.Write(append(line, '\n'))
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,198 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBd puts a bd script first on PATH.
+func fakeBd(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bd"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+// recordCalls collects calls seen by observers while the test runs.
+func recordCalls(t *testing.T) func() []Call {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []Call
+	active := true
+	OnCall(func(c Call) {
+		mu.Lock()
+		defer mu.Unlock()
+		if active {
+			calls = append(calls, c)
+		}
+	})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		active = false
+	})
+	return func() []Call {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Call(nil), calls...)
+	}
+}
+
+func TestCmd_EnvAndOutput(t *testing.T) {
+	fakeBd(t, `echo "$BEADS_DIR $BD_ACTOR $*"; echo oops >&2; [ "$1" = ok ]`)
+	t.Setenv("BEADS_DIR", "/inherited")
+	calls := recordCalls(t)
+
+	cmd := Command("ok", "--json")
+	cmd.BeadsDir = "/town/.beads"
+	cmd.Actor = "mayor"
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "/town/.beads mayor ok --json" {
+		t.Errorf("Output = %q", got)
+	}
+
+	_, err = Command("fail").Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || strings.TrimSpace(string(exitErr.Stderr)) != "oops" {
+		t.Errorf("Output error = %v, want an ExitError carrying stderr", err)
+	}
+
+	out, _ = Command("fail").CombinedOutput()
+	if got := string(out); !strings.Contains(got, "/inherited") || !strings.Contains(got, "oops") {
+		t.Errorf("CombinedOutput = %q", got)
+	}
+
+	got := calls()
+	if len(got) != 3 {
+		t.Fatalf("observed %d calls, want 3", len(got))
+	}
+	if got[0].BeadsDir != "/town/.beads" || got[0].ExitCode != 0 || got[0].Attempts != 1 {
+		t.Errorf("first call = %+v", got[0])
+	}
+	if got[1].BeadsDir != "/inherited" || got[1].ExitCode != 1 || got[1].Stderr != "oops" || got[1].Error == "" {
+		t.Errorf("failed call = %+v", got[1])
+	}
+}
+
+func TestCmd_LockRetries(t *testing.T) {
+	dir := fakeBd(t, `echo x >> "$(dirname "$0")/count"
+n=$(wc -l < "$(dirname "$0")/count")
+if [ "$n" -lt 3 ]; then echo "Error: database is locked" >&2; exit 1; fi
+echo done; echo "warning: slow" >&2`)
+	calls := recordCalls(t)
+
+	// Retries are on by default; Stderr sees only the attempt that counted
+	cmd := Command("list")
+	if cmd.LockRetries != DefaultLockRetries || cmd.Timeout != DefaultTimeout {
+		t.Errorf("defaults = %d retries, %s timeout", cmd.LockRetries, cmd.Timeout)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil || strings.TrimSpace(string(out)) != "done" {
+		t.Fatalf("Output = %q, %v", out, err)
+	}
+	if got := strings.TrimSpace(stderr.String()); got != "warning: slow" {
+		t.Errorf("Stderr = %q, want only the last attempt's", got)
+	}
+	if c := calls(); len(c) != 1 || c[0].Attempts != 3 {
+		t.Errorf("calls = %+v, want one call taking 3 attempts", c)
+	}
+
+	// Without retries the lock error is returned as is
+	_ = os.Remove(filepath.Join(dir, "count"))
+	cmd = Command("list")
+	cmd.LockRetries = 0
+	if _, err := cmd.Output(); err == nil {
+		t.Error("locked call without retries succeeded")
+	}
+}
+
+func TestCmd_TimeoutAndCancel(t *testing.T) {
+	fakeBd(t, `exec sleep 5`)
+
+	cmd := Command("slow")
+	cmd.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	err := cmd.Run()
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run = %v, want a timeout", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("timeout didn't kill bd")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd = CommandContext(ctx, "slow")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Error("canceled bd exited cleanly")
+	}
+}
+
+func TestCmd_StdoutPipe(t *testing.T) {
+	fakeBd(t, `echo one; echo two`)
+	cmd := Command("activity", "--follow")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	var got []byte
+	for {
+		n, err := stdout.Read(buf)
+		got = append(got, buf[:n]...)
+		if err != nil {
+			break
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "one\ntwo\n" {
+		t.Errorf("read %q", got)
+	}
+}
+
+func TestTraceWriter_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bd-trace.jsonl")
+	w := traceWriter(path)
+	w(Call{Args: []string{"show", "gt-1"}, ExitCode: 0, Duration: time.Millisecond})
+	w(Call{Args: []string{"close", "gt-1"}, ExitCode: 1, Error: "exit status 1"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("trace has %d lines, want 2", len(lines))
+	}
+	var call Call
+	if err := json.Unmarshal([]byte(lines[1]), &call); err != nil {
+		t.Fatal(err)
+	}
+	if call.Args[0] != "close" || call.ExitCode != 1 || call.Error == "" {
+		t.Errorf("traced call = %+v", call)
+	}
+}
@@ -3,6 +3,7 @@ package beads
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -41,6 +42,8 @@ type Issue struct {
 	Blocks      []string `json:"blocks,omitempty"`
 	BlockedBy   []string `json:"blocked_by,omitempty"`
 	Labels      []string `json:"labels,omitempty"`
+	MolType     string   `json:"mol_type,omitempty"`
+	IsTemplate  bool     `json:"is_template,omitempty"`
 
 	// Agent bead slots (type=agent only)
 	HookBead   string `json:"hook_bead,omitempty"`   // Current work attached to agent's hook
@@ -64,6 +67,7 @@ type IssueDep struct {
 	Status         string `json:"status"`
 	Priority       int    `json:"priority"`
 	Type           string `json:"issue_type"`
+	Assignee       string `json:"assignee,omitempty"`
 	DependencyType string `json:"dependency_type,omitempty"`
 }
 
@@ -76,6 +80,16 @@ type ListOptions struct {
 	Parent     string // filter by parent ID
 	Assignee   string // filter by assignee (e.g., "gastown/Toast")
 	NoAssignee bool   // filter for issues with no assignee
+
+	IssueType    string   // filter by issue type (e.g., "convoy", "agent"); not a label
+	MolType      string   // filter by molecule type (e.g., "swarm")
+	Labels       []string // more label filters; issues must carry all of them
+	All          bool     // include closed issues
+	Ephemeral    bool     // only ephemeral issues (wisps)
+	Limit        int      // at most this many issues; 0 keeps bd's default
+	NoLimit      bool     // return every match instead of bd's default page
+	Sort         string   // sort key, e.g. "-created" for newest first
+	CreatedAfter string   // e.g. "-24h"
 }
 
 // CreateOptions specifies options for creating an issue.
@@ -87,6 +101,11 @@ type CreateOptions struct {
 	Parent      string
 	Actor       string // Who is creating this issue (populates created_by)
 	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+
+	IssueType string   // Issue type (e.g., "convoy", "epic"); not a label
+	MolType   string   // Molecule type (e.g., "swarm")
+	Labels    []string // Labels besides the one Type adds
+	Assignee  string
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -112,7 +131,11 @@ type SyncStatus struct {
 // Beads wraps bd CLI operations for a working directory.
 type Beads struct {
 	workDir  string
-	beadsDir string // Optional BEADS_DIR override for cross-database access
+	beadsDir string          // Optional BEADS_DIR override for cross-database access
+	routed   bool            // Leave BEADS_DIR alone so bd routes IDs by prefix
+	ctx      context.Context // Cancels calls; nil means context.Background()
+	env      []string        // Extra KEY=value pairs for bd
+	actor    string          // BD_ACTOR for calls; empty inherits it
 }
 
 // New creates a new Beads wrapper for the given directory.
@@ -126,6 +149,35 @@ func NewWithBeadsDir(workDir, beadsDir string) *Beads {
 	return &Beads{workDir: workDir, beadsDir: beadsDir}
 }
 
+// NewRouted creates a Beads wrapper that doesn't set BEADS_DIR, so bd finds
+// the database from workDir and routes issue IDs to their rig by prefix via
+// routes.jsonl. Use it to look up beads that may live in any rig; an empty
+// workDir runs bd in the current directory.
+func NewRouted(workDir string) *Beads {
+	return &Beads{workDir: workDir, routed: true}
+}
+
+// WithContext returns a copy of b whose bd calls are killed when ctx is done.
+func (b *Beads) WithContext(ctx context.Context) *Beads {
+	c := *b
+	c.ctx = ctx
+	return &c
+}
+
+// WithEnv returns a copy of b whose bd calls also get env (KEY=value pairs).
+func (b *Beads) WithEnv(env ...string) *Beads {
+	c := *b
+	c.env = append(append([]string(nil), b.env...), env...)
+	return &c
+}
+
+// WithActor returns a copy of b whose bd calls run as actor.
+func (b *Beads) WithActor(actor string) *Beads {
+	c := *b
+	c.actor = actor
+	return &c
+}
+
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
@@ -133,23 +185,31 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	// Use --allow-stale to prevent failures when db is out of sync with JSONL
 	// (e.g., after daemon is killed during shutdown before syncing).
 	fullArgs := append([]string{"--no-daemon", "--allow-stale"}, args...)
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := CommandContext(ctx, fullArgs...)
 	cmd.Dir = b.workDir
-
-	// Always explicitly set BEADS_DIR to prevent inherited env vars from
-	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
-	// resolve from working directory.
-	beadsDir := b.beadsDir
-	if beadsDir == "" {
-		beadsDir = ResolveBeadsDir(b.workDir)
+	if len(b.env) > 0 {
+		cmd.Env = append(os.Environ(), b.env...)
+	}
+	cmd.Actor = b.actor
+
+	// Unless routing, always explicitly set BEADS_DIR to prevent inherited
+	// env vars from causing prefix mismatches. Use explicit beadsDir if set,
+	// otherwise resolve from working directory.
+	if !b.routed {
+		cmd.BeadsDir = b.beadsDir
+		if cmd.BeadsDir == "" {
+			cmd.BeadsDir = ResolveBeadsDir(b.workDir)
+		}
 	}
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	stdout, err := cmd.Output()
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
 	}
@@ -157,11 +217,11 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	// Handle bd --no-daemon exit code 0 bug: when issue not found,
 	// --no-daemon exits 0 but writes error to stderr with empty stdout.
 	// Detect this case and treat as error to avoid JSON parse failures.
-	if stdout.Len() == 0 && stderr.Len() > 0 {
+	if len(stdout) == 0 && stderr.Len() > 0 {
 		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
 	}
 
-	return stdout.Bytes(), nil
+	return stdout, nil
 }
 
 // Run executes a bd command and returns stdout.
@@ -222,6 +282,32 @@ func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
 	if opts.NoAssignee {
 		args = append(args, "--no-assignee")
 	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
+	}
+	if opts.MolType != "" {
+		args = append(args, "--mol-type="+opts.MolType)
+	}
+	for _, label := range opts.Labels {
+		args = append(args, "--label="+label)
+	}
+	if opts.All {
+		args = append(args, "--all")
+	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
+	if opts.NoLimit {
+		args = append(args, "--limit=0")
+	} else if opts.Limit > 0 {
+		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
+	}
+	if opts.Sort != "" {
+		args = append(args, "--sort="+opts.Sort)
+	}
+	if opts.CreatedAfter != "" {
+		args = append(args, "--created-after="+opts.CreatedAfter)
+	}
 
 	out, err := b.run(args...)
 	if err != nil {
@@ -376,36 +462,17 @@ func (b *Beads) Blocked() ([]*Issue, error) {
 // If opts.Actor is empty, it defaults to the BD_ACTOR environment variable.
 // This ensures created_by is populated for issue provenance tracking.
 func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json"}
+	return b.create(append([]string{"create", "--json"}, opts.args()...))
+}
 
-	if opts.Title != "" {
-		args = append(args, "--title="+opts.Title)
-	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
-	}
-	if opts.Description != "" {
-		args = append(args, "--description="+opts.Description)
-	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
-	}
-	if opts.Ephemeral {
-		args = append(args, "--ephemeral")
-	}
-	// Default Actor from BD_ACTOR env var if not specified
-	actor := opts.Actor
-	if actor == "" {
-		actor = os.Getenv("BD_ACTOR")
-	}
-	if actor != "" {
-		args = append(args, "--actor="+actor)
-	}
+// CreateWithID creates an issue with a specific ID.
+// This is useful for agent beads, role beads, and other beads that need
+// deterministic IDs rather than auto-generated ones.
+func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
+	return b.create(append([]string{"create", "--json", "--id=" + id}, opts.args()...))
+}
 
+func (b *Beads) create(args []string) (*Issue, error) {
 	out, err := b.run(args...)
 	if err != nil {
 		return nil, err
@@ -419,18 +486,25 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	return &issue, nil
 }
 
-// CreateWithID creates an issue with a specific ID.
-// This is useful for agent beads, role beads, and other beads that need
-// deterministic IDs rather than auto-generated ones.
-func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json", "--id=" + id}
-
+// args returns the bd create flags for opts.
+func (opts CreateOptions) args() []string {
+	var args []string
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
 	// Type is deprecated: convert to gt:<type> label
+	labels := opts.Labels
 	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+		labels = append([]string{"gt:" + opts.Type}, labels...)
+	}
+	if len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
+	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
+	}
+	if opts.MolType != "" {
+		args = append(args, "--mol-type="+opts.MolType)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -441,6 +515,12 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Parent != "" {
 		args = append(args, "--parent="+opts.Parent)
 	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
+	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
 	// Default Actor from BD_ACTOR env var if not specified
 	actor := opts.Actor
 	if actor == "" {
@@ -449,18 +529,7 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if actor != "" {
 		args = append(args, "--actor="+actor)
 	}
-
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
-	}
-
-	var issue Issue
-	if err := json.Unmarshal(out, &issue); err != nil {
-		return nil, fmt.Errorf("parsing bd create output: %w", err)
-	}
-
-	return &issue, nil
+	return args
 }
 
 // Update updates an existing issue.
@@ -539,6 +608,35 @@ func (b *Beads) CloseWithReason(reason string, ids ...string) error {
 	return err
 }
 
+// ForceCloseWithReason is CloseWithReason for issues bd would otherwise
+// refuse to close, such as pinned ones.
+func (b *Beads) ForceCloseWithReason(reason string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := append([]string{"close"}, ids...)
+	args = append(args, "--force", "--reason="+reason)
+
+	// Pass session ID for work attribution if available
+	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
+		args = append(args, "--session="+sessionID)
+	}
+
+	_, err := b.run(args...)
+	return err
+}
+
+// Delete deletes issues, leaving tombstones.
+func (b *Beads) Delete(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := append([]string{"delete"}, ids...)
+	_, err := b.run(append(args, "--force")...)
+	return err
+}
+
 // Release moves an in_progress issue back to open status.
 // This is used to recover stuck steps when a worker dies mid-task.
 // It clears the assignee so the step can be claimed by another worker.
@@ -566,12 +664,50 @@ func (b *Beads) AddDependency(issue, dependsOn string) error {
 	return err
 }
 
+// AddDependencyWithType adds a dependency of the given type, e.g. "tracks"
+// for a convoy's non-blocking relation to its issues.
+func (b *Beads) AddDependencyWithType(issue, dependsOn, depType string) error {
+	_, err := b.run("dep", "add", issue, dependsOn, "--type="+depType)
+	return err
+}
+
 // RemoveDependency removes a dependency.
 func (b *Beads) RemoveDependency(issue, dependsOn string) error {
 	_, err := b.run("dep", "remove", issue, dependsOn)
 	return err
 }
 
+// AddLabels adds labels to an issue.
+func (b *Beads) AddLabels(id string, labels ...string) error {
+	_, err := b.run(append([]string{"label", "add", id}, labels...)...)
+	return err
+}
+
+// RemoveLabels removes labels from an issue.
+func (b *Beads) RemoveLabels(id string, labels ...string) error {
+	_, err := b.run(append([]string{"label", "remove", id}, labels...)...)
+	return err
+}
+
+// SetLabels replaces all labels on an issue. No labels clears them.
+func (b *Beads) SetLabels(id string, labels ...string) error {
+	args := []string{"update", id}
+	for _, label := range labels {
+		args = append(args, "--set-labels="+label)
+	}
+	if len(labels) == 0 {
+		args = append(args, "--set-labels=")
+	}
+	_, err := b.run(args...)
+	return err
+}
+
+// AddComment appends a comment to an issue.
+func (b *Beads) AddComment(id, text string) error {
+	_, err := b.run("comment", id, text)
+	return err
+}
+
 // Sync syncs beads with remote.
 func (b *Beads) Sync() error {
 	_, err := b.run("sync")
@@ -603,6 +739,34 @@ func (b *Beads) GetSyncStatus() (*SyncStatus, error) {
 	return &status, nil
 }
 
+// Init creates the beads database with the given issue prefix.
+func (b *Beads) Init(prefix string) error {
+	_, err := b.run("init", "--prefix", prefix)
+	return err
+}
+
+// ConfigSet sets a bd config key, e.g. types.custom.
+func (b *Beads) ConfigSet(key, value string) error {
+	_, err := b.run("config", "set", key, value)
+	return err
+}
+
+// ConfigGet returns the value of a bd config key.
+func (b *Beads) ConfigGet(key string) (string, error) {
+	out, err := b.run("config", "get", key)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// UpdateRepoID stores the repository fingerprint in the database (GH #25).
+// It is idempotent; without a fingerprint the bd daemon fails to start.
+func (b *Beads) UpdateRepoID() error {
+	_, err := b.run("migrate", "--update-repo-id", "--yes")
+	return err
+}
+
 // Stats returns repository statistics.
 func (b *Beads) Stats() (string, error) {
 	out, err := b.run("stats")
@@ -612,6 +776,15 @@ func (b *Beads) Stats() (string, error) {
 	return string(out), nil
 }
 
+// Prime returns bd's workflow context for agents.
+func (b *Beads) Prime() (string, error) {
+	out, err := b.run("prime")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // IsBeadsRepo checks if the working directory is a beads repository.
 // ZFC: Check file existence directly instead of parsing bd errors.
 func (b *Beads) IsBeadsRepo() bool {
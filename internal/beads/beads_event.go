@@ -0,0 +1,137 @@
+// Package beads provides event bead and wisp operations.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event is an event bead: a record of something that happened, such as a
+// session ending. Payload is the event's JSON payload.
+type Event struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	EventKind string    `json:"event_kind"`
+	Actor     string    `json:"actor"`
+	Target    string    `json:"target"`
+	Payload   string    `json:"payload"`
+}
+
+// EventOptions specifies an event bead to create.
+type EventOptions struct {
+	Title       string
+	Category    string // Event kind, e.g. "session.ended"
+	Actor       string
+	Target      string
+	Payload     string // JSON
+	Description string
+	Ephemeral   bool // Create as a wisp - not exported to JSONL
+}
+
+// CreateEvent creates an event bead and returns its ID.
+func (b *Beads) CreateEvent(opts EventOptions) (string, error) {
+	args := []string{"create", "--type=event", "--silent",
+		"--title=" + opts.Title,
+		"--event-category=" + opts.Category,
+	}
+	if opts.Actor != "" {
+		args = append(args, "--event-actor="+opts.Actor)
+	}
+	if opts.Target != "" {
+		args = append(args, "--event-target="+opts.Target)
+	}
+	if opts.Payload != "" {
+		args = append(args, "--event-payload="+opts.Payload)
+	}
+	if opts.Description != "" {
+		args = append(args, "--description="+opts.Description)
+	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
+
+	out, err := b.run(args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ListEvents returns every event bead, open or closed. bd list leaves out
+// the event fields, so the events are fetched again with bd show.
+func (b *Beads) ListEvents() ([]*Event, error) {
+	out, err := b.run("list", "--type=event", "--all", "--limit=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var items []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(out, &items); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return b.ShowEvents(ids...)
+}
+
+// ShowEvents returns the event beads with the given IDs in one bd call.
+func (b *Beads) ShowEvents(ids ...string) ([]*Event, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	out, err := b.run(append([]string{"show", "--json"}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	if err := json.Unmarshal(out, &events); err != nil {
+		return nil, fmt.Errorf("parsing bd show output: %w", err)
+	}
+
+	return events, nil
+}
+
+// Wisp is an ephemeral bead as listed by bd mol wisp list.
+type Wisp struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListWisps returns every wisp, including closed ones.
+func (b *Beads) ListWisps() ([]*Wisp, error) {
+	out, err := b.run("mol", "wisp", "list", "--all", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Wisps []*Wisp `json:"wisps"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parsing bd mol wisp list output: %w", err)
+	}
+
+	return list.Wisps, nil
+}
+
+// Burn deletes molecules or wisps outright, leaving no digest.
+func (b *Beads) Burn(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := b.run(append([]string{"mol", "burn", "--force"}, ids...)...)
+	return err
+}
@@ -0,0 +1,161 @@
+// Package beads provides formula and molecule commands: cook, wisp, bond.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Cook compiles a formula into its proto. Cooking is idempotent.
+func (b *Beads) Cook(formula string) error {
+	_, err := b.run("cook", formula)
+	return err
+}
+
+// FormulaExists reports whether bd can find the named formula.
+func (b *Beads) FormulaExists(name string) bool {
+	out, err := b.run("formula", "show", name)
+	return err == nil && len(out) > 0
+}
+
+// PourWisp instantiates a formula as an ephemeral wisp and returns its root
+// ID. vars are key=value template variables.
+func (b *Beads) PourWisp(formula string, vars ...string) (string, error) {
+	args := []string{"mol", "wisp", formula}
+	for _, v := range vars {
+		args = append(args, "--var", v)
+	}
+	out, err := b.run(append(args, "--json")...)
+	if err != nil {
+		return "", err
+	}
+	return parseWispID(out)
+}
+
+// SpawnWisp creates a wisp from a proto in the catalog and returns its ID.
+func (b *Beads) SpawnWisp(protoID, actor string) (string, error) {
+	out, err := b.run("mol", "wisp", "create", protoID, "--actor", actor)
+	if err != nil {
+		return "", err
+	}
+
+	// bd prints the ID on a "Root issue:" or "Created" line.
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.Contains(line, "Root issue:") && !strings.Contains(line, "Created") {
+			continue
+		}
+		for _, p := range strings.Fields(line) {
+			if strings.HasPrefix(p, "wisp-") || strings.HasPrefix(p, "gt-") {
+				return p, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("created wisp but could not parse ID from output")
+}
+
+// Bond attaches molecule to issue, making a compound, and returns the
+// compound's root. bd keeps the molecule as the root when it doesn't say.
+func (b *Beads) Bond(molecule, issue string) (string, error) {
+	out, err := b.run("mol", "bond", molecule, issue, "--json")
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		RootID string `json:"root_id"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil || result.RootID == "" {
+		return molecule, nil
+	}
+	return result.RootID, nil
+}
+
+// FindProto returns the ID of the catalog proto whose entry mentions name.
+func (b *Beads) FindProto(name string) (string, error) {
+	out, err := b.run("mol", "catalog")
+	if err != nil {
+		return "", fmt.Errorf("listing molecule catalog: %w", err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.Contains(line, name) {
+			continue
+		}
+		if parts := strings.Fields(line); len(parts) > 0 {
+			// Catalog lines are "gt-xxx: title"
+			return strings.TrimSuffix(parts[0], ":"), nil
+		}
+	}
+	return "", fmt.Errorf("proto %s not found in catalog", name)
+}
+
+// MolProgress is a molecule's progress as reported by bd mol current.
+type MolProgress struct {
+	MoleculeID    string `json:"molecule_id"`
+	MoleculeTitle string `json:"molecule_title"`
+	NextStep      *struct {
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Status      string `json:"status"`
+	} `json:"next_step"`
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// MolCurrent returns the progress of a molecule and the step to run next.
+func (b *Beads) MolCurrent(moleculeID string) (*MolProgress, error) {
+	out, err := b.run("mol", "current", moleculeID, "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	// bd returns an array with one element
+	var progress []*MolProgress
+	if err := json.Unmarshal(out, &progress); err != nil {
+		return nil, fmt.Errorf("parsing bd mol current output: %w", err)
+	}
+	if len(progress) == 0 {
+		return nil, ErrNotFound
+	}
+	return progress[0], nil
+}
+
+// WispGC deletes abandoned wisps.
+func (b *Beads) WispGC() error {
+	_, err := b.run("mol", "wisp", "gc")
+	return err
+}
+
+type wispCreateJSON struct {
+	NewEpicID string `json:"new_epic_id"`
+	RootID    string `json:"root_id"`
+	ResultID  string `json:"result_id"`
+}
+
+// parseWispID returns the root ID from bd mol wisp --json output.
+func parseWispID(jsonOutput []byte) (string, error) {
+	var result wispCreateJSON
+	if err := json.Unmarshal(jsonOutput, &result); err != nil {
+		return "", fmt.Errorf("parsing wisp JSON: %w (output: %s)", err, trimJSONForError(jsonOutput))
+	}
+
+	switch {
+	case result.NewEpicID != "":
+		return result.NewEpicID, nil
+	case result.RootID != "":
+		return result.RootID, nil
+	case result.ResultID != "":
+		return result.ResultID, nil
+	default:
+		return "", fmt.Errorf("wisp JSON missing id field (expected one of new_epic_id, root_id, result_id); output: %s", trimJSONForError(jsonOutput))
+	}
+}
+
+func trimJSONForError(jsonOutput []byte) string {
+	s := strings.TrimSpace(string(jsonOutput))
+	const maxLen = 500
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
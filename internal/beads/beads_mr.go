@@ -2,6 +2,7 @@
 package beads
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -43,3 +44,31 @@ func (b *Beads) AddGateWaiter(gateID, waiter string) error {
 	}
 	return nil
 }
+
+// Gate is a gate bead as reported by bd gate show.
+type Gate struct {
+	ID          string   `json:"id"`
+	Status      string   `json:"status"`
+	CloseReason string   `json:"close_reason"`
+	Waiters     []string `json:"waiters"`
+}
+
+// ShowGate returns the gate with the given ID.
+func (b *Beads) ShowGate(gateID string) (*Gate, error) {
+	out, err := b.run("gate", "show", gateID, "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var gate Gate
+	if err := json.Unmarshal(out, &gate); err != nil {
+		return nil, fmt.Errorf("parsing bd gate show output: %w", err)
+	}
+	return &gate, nil
+}
+
+// WaitGate asks bd to notify agent when the gate closes.
+func (b *Beads) WaitGate(gateID, agent string) error {
+	_, err := b.run("gate", "wait", gateID, "--notify", agent)
+	return err
+}
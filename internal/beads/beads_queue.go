@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // QueueFields holds structured fields for queue beads.
@@ -24,6 +25,13 @@ type QueueFields struct {
 	FailedCount     int    // Number of items that failed
 	CreatedBy       string // Who created this queue
 	CreatedAt       string // ISO 8601 timestamp of creation
+
+	// Claim leases. A claimed message must be renewed before the visibility
+	// timeout elapses or it returns to the queue. After MaxDeliveries claims
+	// it is moved to the queue's dead-letter queue instead.
+	VisibilityTimeout time.Duration // Claim lease duration (default: 15m)
+	MaxDeliveries     int           // Claims before dead-lettering (0 = unlimited, default: 5)
+	DeadLetterCount   int           // Number of items moved to the dead-letter queue
 }
 
 // Queue status constants
@@ -33,6 +41,12 @@ const (
 	QueueStatusClosed = "closed"
 )
 
+// Queue lease defaults, applied when a queue bead doesn't set them.
+const (
+	DefaultQueueVisibilityTimeout = 15 * time.Minute
+	DefaultQueueMaxDeliveries     = 5
+)
+
 // Queue processing order constants
 const (
 	QueueOrderFIFO     = "fifo"
@@ -80,6 +94,14 @@ func FormatQueueDescription(title string, fields *QueueFields) string {
 	lines = append(lines, fmt.Sprintf("completed_count: %d", fields.CompletedCount))
 	lines = append(lines, fmt.Sprintf("failed_count: %d", fields.FailedCount))
 
+	visibility := fields.VisibilityTimeout
+	if visibility <= 0 {
+		visibility = DefaultQueueVisibilityTimeout
+	}
+	lines = append(lines, fmt.Sprintf("visibility_timeout: %s", formatQueueDuration(visibility)))
+	lines = append(lines, fmt.Sprintf("max_deliveries: %d", fields.MaxDeliveries))
+	lines = append(lines, fmt.Sprintf("dead_letter_count: %d", fields.DeadLetterCount))
+
 	if fields.CreatedBy != "" {
 		lines = append(lines, fmt.Sprintf("created_by: %s", fields.CreatedBy))
 	}
@@ -96,6 +118,9 @@ func ParseQueueFields(description string) *QueueFields {
 		Status:          QueueStatusActive,
 		ProcessingOrder: QueueOrderFIFO,
 		ClaimPattern:    "*", // Default: anyone can claim
+
+		VisibilityTimeout: DefaultQueueVisibilityTimeout,
+		MaxDeliveries:     DefaultQueueMaxDeliveries,
 	}
 
 	for _, line := range strings.Split(description, "\n") {
@@ -146,6 +171,18 @@ func ParseQueueFields(description string) *QueueFields {
 			if v, err := strconv.Atoi(value); err == nil {
 				fields.FailedCount = v
 			}
+		case "visibility_timeout":
+			if v, err := time.ParseDuration(value); err == nil && v > 0 {
+				fields.VisibilityTimeout = v
+			}
+		case "max_deliveries":
+			if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+				fields.MaxDeliveries = v
+			}
+		case "dead_letter_count":
+			if v, err := strconv.Atoi(value); err == nil {
+				fields.DeadLetterCount = v
+			}
 		case "created_by":
 			fields.CreatedBy = value
 		case "created_at":
@@ -156,6 +193,19 @@ func ParseQueueFields(description string) *QueueFields {
 	return fields
 }
 
+// formatQueueDuration renders a duration without trailing zero units
+// ("15m" rather than "15m0s").
+func formatQueueDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
 // QueueBeadID returns the queue bead ID for a given queue name.
 // Format: hq-q-<name> for town-level queues, gt-q-<name> for rig-level queues.
 func QueueBeadID(name string, isTownLevel bool) string {
@@ -3,6 +3,7 @@ package beads
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestMatchClaimPattern(t *testing.T) {
@@ -182,6 +183,21 @@ func TestFormatQueueDescription(t *testing.T) {
 				"failed_count: 1",
 			},
 		},
+		{
+			name:  "queue with lease settings",
+			title: "Queue: builds",
+			fields: &QueueFields{
+				Name:              "builds",
+				VisibilityTimeout: 90 * time.Minute,
+				MaxDeliveries:     3,
+				DeadLetterCount:   2,
+			},
+			want: []string{
+				"visibility_timeout: 1h30m",
+				"max_deliveries: 3",
+				"dead_letter_count: 2",
+			},
+		},
 		{
 			name:   "nil fields",
 			title:  "Just Title",
@@ -268,6 +284,47 @@ processing_count: 2`,
 	}
 }
 
+func TestParseQueueFieldsLeaseSettings(t *testing.T) {
+	t.Run("defaults when absent", func(t *testing.T) {
+		got := ParseQueueFields("Queue: minimal\n\nname: minimal")
+		if got.VisibilityTimeout != DefaultQueueVisibilityTimeout {
+			t.Errorf("VisibilityTimeout = %v, want %v", got.VisibilityTimeout, DefaultQueueVisibilityTimeout)
+		}
+		if got.MaxDeliveries != DefaultQueueMaxDeliveries {
+			t.Errorf("MaxDeliveries = %d, want %d", got.MaxDeliveries, DefaultQueueMaxDeliveries)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		in := &QueueFields{
+			Name:              "builds",
+			VisibilityTimeout: 5 * time.Minute,
+			MaxDeliveries:     0, // unlimited
+			DeadLetterCount:   4,
+		}
+		got := ParseQueueFields(FormatQueueDescription("Queue: builds", in))
+		if got.VisibilityTimeout != 5*time.Minute {
+			t.Errorf("VisibilityTimeout = %v, want 5m", got.VisibilityTimeout)
+		}
+		if got.MaxDeliveries != 0 {
+			t.Errorf("MaxDeliveries = %d, want 0 (unlimited)", got.MaxDeliveries)
+		}
+		if got.DeadLetterCount != 4 {
+			t.Errorf("DeadLetterCount = %d, want 4", got.DeadLetterCount)
+		}
+	})
+
+	t.Run("invalid values keep defaults", func(t *testing.T) {
+		got := ParseQueueFields("visibility_timeout: soon\nmax_deliveries: -1")
+		if got.VisibilityTimeout != DefaultQueueVisibilityTimeout {
+			t.Errorf("VisibilityTimeout = %v, want default", got.VisibilityTimeout)
+		}
+		if got.MaxDeliveries != DefaultQueueMaxDeliveries {
+			t.Errorf("MaxDeliveries = %d, want default", got.MaxDeliveries)
+		}
+	})
+}
+
 func TestQueueBeadID(t *testing.T) {
 	tests := []struct {
 		name        string
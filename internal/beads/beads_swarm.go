@@ -0,0 +1,39 @@
+// Package beads provides swarm status queries.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SwarmTask is an issue in one front of a swarm's status.
+type SwarmTask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// SwarmStatus is a swarm epic's progress as reported by bd swarm status.
+type SwarmStatus struct {
+	EpicID      string      `json:"epic_id"`
+	Ready       []SwarmTask `json:"ready"`  // Unblocked and unclaimed
+	Active      []SwarmTask `json:"active"` // In progress
+	Blocked     []SwarmTask `json:"blocked"`
+	Completed   []SwarmTask `json:"completed"`
+	TotalIssues int         `json:"total_issues"`
+}
+
+// SwarmStatus returns the status of the swarm rooted at epicID.
+func (b *Beads) SwarmStatus(epicID string) (*SwarmStatus, error) {
+	out, err := b.run("swarm", "status", epicID, "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var status SwarmStatus
+	if err := json.Unmarshal(out, &status); err != nil {
+		return nil, fmt.Errorf("parsing bd swarm status output: %w", err)
+	}
+
+	return &status, nil
+}
@@ -381,6 +381,47 @@ source_issue: gt-pqr`,
 	}
 }
 
+// TestMRFieldsHeldRoundTrip tests that atomic-landing fields survive
+// SetMRFields and are cleared when emptied.
+func TestMRFieldsHeldRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Some notes\n\nbranch: polecat/Nux/gt-abc\ntarget: main"}
+	fields := ParseMRFields(issue)
+	fields.HeldCommit = "abc123"
+	fields.HeldAt = "2026-10-18T12:00:00Z"
+
+	issue.Description = SetMRFields(issue, fields)
+	got := ParseMRFields(issue)
+	if got.HeldCommit != "abc123" || got.HeldAt != "2026-10-18T12:00:00Z" {
+		t.Errorf("held fields = %q, %q", got.HeldCommit, got.HeldAt)
+	}
+
+	got.HeldCommit, got.HeldAt = "", ""
+	issue.Description = SetMRFields(issue, got)
+	if strings.Contains(issue.Description, "held_") {
+		t.Errorf("held fields not cleared:\n%s", issue.Description)
+	}
+	if !strings.Contains(issue.Description, "Some notes") {
+		t.Errorf("prose lost:\n%s", issue.Description)
+	}
+}
+
+// TestMRFieldsTraceParent tests that the submitting span's traceparent
+// survives SetMRFields alongside other fields.
+func TestMRFieldsTraceParent(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	issue := &Issue{Description: "branch: polecat/Nux/gt-abc\ntarget: main\ntrace-parent: " + tp}
+	fields := ParseMRFields(issue)
+	if fields == nil || fields.TraceParent != tp {
+		t.Fatalf("ParseMRFields = %+v", fields)
+	}
+
+	fields.MergeCommit = "abc123"
+	issue.Description = SetMRFields(issue, fields)
+	if strings.Count(issue.Description, tp) != 1 || !strings.Contains(issue.Description, "trace_parent: "+tp) {
+		t.Errorf("trace_parent not rewritten once:\n%s", issue.Description)
+	}
+}
+
 // TestFormatMRFields tests formatting MR fields to string.
 func TestFormatMRFields(t *testing.T) {
 	tests := []struct {
@@ -885,6 +926,7 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
 		AttachedMolecule: "mol-roundtrip",
 		AttachedAt:       "2025-12-21T15:30:00Z",
+		TraceParent:      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
 	}
 
 	// Format to string
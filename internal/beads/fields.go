@@ -21,6 +21,7 @@ type AttachmentFields struct {
 	AttachedAt       string // ISO 8601 timestamp when attached
 	AttachedArgs     string // Natural language args passed via gt sling --args (no-tmux mode)
 	DispatchedBy     string // Agent ID that dispatched this work (for completion notification)
+	TraceParent      string // W3C traceparent of the sling that dispatched this work
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -65,6 +66,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "dispatched_by", "dispatched-by", "dispatchedby":
 			fields.DispatchedBy = value
 			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -95,6 +99,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.DispatchedBy != "" {
 		lines = append(lines, "dispatched_by: "+fields.DispatchedBy)
 	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -117,6 +124,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"dispatched_by":     true,
 		"dispatched-by":     true,
 		"dispatchedby":      true,
+		"trace_parent":      true,
+		"trace-parent":      true,
+		"traceparent":       true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -187,6 +197,19 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Atomic convoy landing: set when the refinery has verified the MR but
+	// holds it for the convoy coordinator instead of pushing.
+	HeldCommit string // SHA of the verified, unpushed merge result
+	HeldAt     string // When the MR was held (ISO 8601)
+
+	// Gates is the refinery's pre-merge gate results as a JSON array
+	// (one object per gate: name, status, failure type, summary, duration).
+	Gates string
+
+	// TraceParent is the W3C traceparent of the span that submitted the MR,
+	// so the refinery's gate and merge spans join the work's trace.
+	TraceParent string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -261,6 +284,18 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "held_commit", "held-commit", "heldcommit":
+			fields.HeldCommit = value
+			hasFields = true
+		case "held_at", "held-at", "heldat":
+			fields.HeldAt = value
+			hasFields = true
+		case "gates":
+			fields.Gates = value
+			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -325,6 +360,18 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.HeldCommit != "" {
+		lines = append(lines, "held_commit: "+fields.HeldCommit)
+	}
+	if fields.HeldAt != "" {
+		lines = append(lines, "held_at: "+fields.HeldAt)
+	}
+	if fields.Gates != "" {
+		lines = append(lines, "gates: "+fields.Gates)
+	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -371,6 +418,16 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"held_commit":        true,
+		"held-commit":        true,
+		"heldcommit":         true,
+		"held_at":            true,
+		"held-at":            true,
+		"heldat":             true,
+		"gates":              true,
+		"trace_parent":       true,
+		"trace-parent":       true,
+		"traceparent":        true,
 	}
 
 	// Collect non-MR lines from existing description
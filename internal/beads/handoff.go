@@ -14,6 +14,18 @@ const StatusPinned = "pinned"
 // This is distinct from pinned - hooked beads are active work, not permanent records.
 const StatusHooked = "hooked"
 
+// Hook puts an issue on assignee's hook.
+func (b *Beads) Hook(id, assignee string) error {
+	status := StatusHooked
+	return b.Update(id, UpdateOptions{Status: &status, Assignee: &assignee})
+}
+
+// Pin pins an issue to assignee.
+func (b *Beads) Pin(id, assignee string) error {
+	status := StatusPinned
+	return b.Update(id, UpdateOptions{Status: &status, Assignee: &assignee})
+}
+
 // HandoffBeadTitle returns the well-known title for a role's handoff bead.
 func HandoffBeadTitle(role string) string {
 	return role + " Handoff"
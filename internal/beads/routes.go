@@ -3,6 +3,7 @@ package beads
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -113,26 +114,29 @@ func RemoveRoute(townRoot string, prefix string) error {
 func WriteRoutes(beadsDir string, routes []Route) error {
 	routesPath := filepath.Join(beadsDir, RoutesFileName)
 
-	file, err := os.Create(routesPath)
+	data, err := FormatRoutes(routes)
 	if err != nil {
-		return fmt.Errorf("creating routes file: %w", err)
+		return err
 	}
-	defer file.Close()
+	if err := os.WriteFile(routesPath, data, 0644); err != nil { //nolint:gosec // G306: routes are not secret
+		return fmt.Errorf("writing routes file: %w", err)
+	}
+
+	return nil
+}
 
+// FormatRoutes renders routes in routes.jsonl format, one JSON object per line.
+func FormatRoutes(routes []Route) ([]byte, error) {
+	var buf bytes.Buffer
 	for _, r := range routes {
 		data, err := json.Marshal(r)
 		if err != nil {
-			return fmt.Errorf("marshaling route: %w", err)
-		}
-		if _, err := file.Write(data); err != nil {
-			return fmt.Errorf("writing route: %w", err)
-		}
-		if _, err := file.WriteString("\n"); err != nil {
-			return fmt.Errorf("writing newline: %w", err)
+			return nil, fmt.Errorf("marshaling route: %w", err)
 		}
+		buf.Write(data)
+		buf.WriteByte('\n')
 	}
-
-	return nil
+	return buf.Bytes(), nil
 }
 
 // GetTownBeadsPath returns the path to town-level beads directory.
@@ -58,6 +58,21 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 		return fmt.Errorf("creating settings directory: %w", err)
 	}
 
+	content, err := SettingsTemplate(roleType)
+	if err != nil {
+		return err
+	}
+
+	// Write settings file
+	if err := os.WriteFile(settingsPath, content, 0600); err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+
+	return nil
+}
+
+// SettingsTemplate returns the settings.json template for a role type.
+func SettingsTemplate(roleType RoleType) ([]byte, error) {
 	// Select template based on role type
 	var templateName string
 	switch roleType {
@@ -67,18 +82,11 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 		templateName = "config/settings-interactive.json"
 	}
 
-	// Read template
 	content, err := configFS.ReadFile(templateName)
 	if err != nil {
-		return fmt.Errorf("reading template %s: %w", templateName, err)
+		return nil, fmt.Errorf("reading template %s: %w", templateName, err)
 	}
-
-	// Write settings file
-	if err := os.WriteFile(settingsPath, content, 0600); err != nil {
-		return fmt.Errorf("writing settings: %w", err)
-	}
-
-	return nil
+	return content, nil
 }
 
 // EnsureSettingsForRole is a convenience function that combines RoleTypeFor and EnsureSettings.
@@ -34,7 +34,12 @@ Commands:
   gt account list              List registered accounts
   gt account add <handle>      Add a new account
   gt account default <handle>  Set the default account
-  gt account status            Show current account info`,
+  gt account status            Show current account info
+  gt account rotate <session>  Restart one session under another account
+  gt account limits            Show usage-limited accounts and parked sessions
+
+When a session hits a provider usage limit, the daemon restarts it under
+the next account that isn't limited, or parks it until the limit resets.`,
 }
 
 var accountListCmd = &cobra.Command{
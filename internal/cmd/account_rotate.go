@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Account rotation flags
+var (
+	accountRotateTo     string
+	accountRotateDryRun bool
+	accountLimitsJSON   bool
+	accountLimitsClear  string
+)
+
+var accountRotateCmd = &cobra.Command{
+	Use:   "rotate <session>",
+	Short: "Restart a session under another account",
+	Long: `Restart an agent session under a different Claude Code account.
+
+Unlike 'gt account switch', this affects only the given session: its pane
+is respawned with CLAUDE_CONFIG_DIR pointing at the new account. Hooked
+work continues from the hook as with a handoff.
+
+Without --to, the next account (in handle order) that isn't currently
+usage-limited is chosen. The daemon runs this automatically when it sees
+a session hit a provider usage limit.
+
+Examples:
+  gt account rotate gt-gastown-Toast
+  gt account rotate gt-gastown-crew-max --to work`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAccountRotate,
+}
+
+var accountLimitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show usage-limited accounts and parked sessions",
+	Long: `Show which accounts have hit a provider usage limit and when they reset,
+and which sessions are parked waiting for a reset because no other account
+was available.
+
+Examples:
+  gt account limits
+  gt account limits --json
+  gt account limits --clear work   # Forget a limit (e.g., after upgrading the plan)`,
+	RunE: runAccountLimits,
+}
+
+func runAccountRotate(cmd *cobra.Command, args []string) error {
+	sessionName := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	accounts, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading accounts config: %w", err)
+	}
+	state, err := quota.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	if exists, err := t.HasSession(sessionName); err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	} else if !exists {
+		return fmt.Errorf("session '%s' not found", sessionName)
+	}
+
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return fmt.Errorf("cannot parse session name %q: %w", sessionName, err)
+	}
+	rigPath := ""
+	if identity.Rig != "" {
+		rigPath = filepath.Join(townRoot, identity.Rig)
+	}
+	if rc := config.ResolveAgentConfig(townRoot, rigPath); !quota.UsesAccounts(rc) {
+		return fmt.Errorf("%s runs %s, which does not use Claude Code accounts", sessionName, rc.Command)
+	}
+
+	configDir, _ := t.GetEnvironment(sessionName, "CLAUDE_CONFIG_DIR")
+	current := quota.AccountForConfigDir(accounts, configDir)
+	if current == "" && configDir == "" {
+		current = accounts.Default // session inherited the default ~/.claude
+	}
+
+	target := accountRotateTo
+	if target == "" {
+		next, ok := state.NextAvailable(accounts, current, time.Now())
+		if !ok {
+			return fmt.Errorf("no other account available (all usage-limited); see 'gt account limits'")
+		}
+		target = next
+	}
+	acct := accounts.GetAccount(target)
+	if acct == nil {
+		return fmt.Errorf("account '%s' not found", target)
+	}
+	if target == current {
+		fmt.Printf("%s is already on account '%s'\n", sessionName, target)
+		return nil
+	}
+	targetDir := expandHomePath(acct.ConfigDir)
+
+	restartCmd, err := buildRestartCommandWithEnv(sessionName, rigPath, "account-rotation",
+		map[string]string{"CLAUDE_CONFIG_DIR": targetDir})
+	if err != nil {
+		return err
+	}
+
+	if accountRotateDryRun {
+		fmt.Printf("Would rotate %s: %s → %s\n", sessionName, displayHandle(current), target)
+		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", sessionName, restartCmd)
+		return nil
+	}
+
+	pane, err := getSessionPane(sessionName)
+	if err != nil {
+		return fmt.Errorf("getting session pane: %w", err)
+	}
+
+	// Keep the session env in sync so later detection maps it to the new account
+	if err := t.SetEnvironment(sessionName, "CLAUDE_CONFIG_DIR", targetDir); err != nil {
+		style.PrintWarning("could not set session environment: %v", err)
+	}
+	if err := t.ClearHistory(pane); err != nil {
+		style.PrintWarning("could not clear history: %v", err)
+	}
+	if err := t.RespawnPane(pane, restartCmd); err != nil {
+		return fmt.Errorf("respawning pane: %w", err)
+	}
+
+	_ = events.LogFeed(events.TypeAccountRotated, sessionName,
+		events.UsageLimitPayload(sessionName, current, string(quota.ActionRotate), target))
+
+	fmt.Printf("%s Rotated %s: %s → %s\n", style.Bold.Render("✓"), sessionName, displayHandle(current), target)
+	return nil
+}
+
+func runAccountLimits(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	state, err := quota.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	state.Expire(now)
+
+	if accountLimitsClear != "" {
+		if _, ok := state.Accounts[accountLimitsClear]; !ok {
+			return fmt.Errorf("account '%s' is not usage-limited", accountLimitsClear)
+		}
+		delete(state.Accounts, accountLimitsClear)
+		if err := quota.SaveState(townRoot, state); err != nil {
+			return err
+		}
+		fmt.Printf("Cleared usage limit for '%s'\n", accountLimitsClear)
+		return nil
+	}
+
+	if accountLimitsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	if len(state.Accounts) == 0 && len(state.Parked) == 0 {
+		fmt.Println("No accounts are usage-limited.")
+		return nil
+	}
+
+	if len(state.Accounts) > 0 {
+		fmt.Printf("%s\n", style.Bold.Render("Limited accounts:"))
+		handles := make([]string, 0, len(state.Accounts))
+		for h := range state.Accounts {
+			handles = append(handles, h)
+		}
+		sort.Strings(handles)
+		for _, h := range handles {
+			limit := state.Accounts[h]
+			fmt.Printf("  %-16s resets %s (in %s)\n", h,
+				limit.ResetAt.Local().Format("Jan 2 15:04"), limit.ResetAt.Sub(now).Round(time.Minute))
+			if limit.Message != "" {
+				fmt.Printf("  %-16s %s\n", "", style.Dim.Render(limit.Message))
+			}
+		}
+	}
+
+	if len(state.Parked) > 0 {
+		if len(state.Accounts) > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", style.Bold.Render("Parked sessions:"))
+		names := make([]string, 0, len(state.Parked))
+		for n := range state.Parked {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			p := state.Parked[n]
+			fmt.Printf("  %-24s resumes %s (account %s)\n", n,
+				p.ResumeAt.Local().Format("Jan 2 15:04"), displayHandle(p.Account))
+		}
+	}
+	return nil
+}
+
+// displayHandle renders an account handle, or a placeholder when unknown.
+func displayHandle(handle string) string {
+	if handle == "" {
+		return "(unregistered)"
+	}
+	return handle
+}
+
+// expandHomePath expands a leading ~/ to the home directory.
+func expandHomePath(path string) string {
+	if len(path) >= 2 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return home + path[1:]
+		}
+	}
+	return path
+}
+
+func init() {
+	accountRotateCmd.Flags().StringVar(&accountRotateTo, "to", "", "Account handle to rotate to (default: next available)")
+	accountRotateCmd.Flags().BoolVarP(&accountRotateDryRun, "dry-run", "n", false, "Show what would be done")
+
+	accountLimitsCmd.Flags().BoolVar(&accountLimitsJSON, "json", false, "Output as JSON")
+	accountLimitsCmd.Flags().StringVar(&accountLimitsClear, "clear", "", "Forget the usage limit recorded for an account")
+
+	accountCmd.AddCommand(accountRotateCmd)
+	accountCmd.AddCommand(accountLimitsCmd)
+}
@@ -1,11 +1,10 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
@@ -205,29 +204,8 @@ func modifyAgentState(agentBead, beadsDir string, hasIncr bool) error {
 		finalLabels = append(finalLabels, key+":"+value)
 	}
 
-	// Build update command with --set-labels to replace all
-	args := []string{"update", agentBead}
-	for _, label := range finalLabels {
-		args = append(args, "--set-labels="+label)
-	}
-
-	// If no labels, clear all
-	if len(finalLabels) == 0 {
-		args = append(args, "--set-labels=")
-	}
-
-	// Execute bd update
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return fmt.Errorf("%s", errMsg)
-		}
+	// Replace all labels; none clears them
+	if err := beads.NewWithBeadsDir("", beadsDir).SetLabels(agentBead, finalLabels...); err != nil {
 		return fmt.Errorf("updating agent state: %w", err)
 	}
 
@@ -259,38 +237,12 @@ func getAgentLabels(agentBead, beadsDir string) (map[string]string, error) {
 
 // getAllAgentLabels retrieves all labels (including non-state) from an agent bead.
 func getAllAgentLabels(agentBead, beadsDir string) ([]string, error) {
-	args := []string{"show", agentBead, "--json"}
-
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if strings.Contains(errMsg, "not found") {
-			return nil, fmt.Errorf("agent bead not found: %s", agentBead)
-		}
-		if errMsg != "" {
-			return nil, fmt.Errorf("%s", errMsg)
-		}
-		return nil, fmt.Errorf("querying agent bead: %w", err)
-	}
-
-	// Parse JSON output - bd show --json returns an array
-	var issues []struct {
-		Labels []string `json:"labels"`
-	}
-
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return nil, fmt.Errorf("parsing agent bead: %w", err)
-	}
-
-	if len(issues) == 0 {
+	issue, err := beads.NewWithBeadsDir("", beadsDir).Show(agentBead)
+	if errors.Is(err, beads.ErrNotFound) {
 		return nil, fmt.Errorf("agent bead not found: %s", agentBead)
 	}
-
-	return issues[0].Labels, nil
+	if err != nil {
+		return nil, fmt.Errorf("querying agent bead: %w", err)
+	}
+	return issue.Labels, nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/retention"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -404,7 +405,7 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 	var entries []AuditEntry
 
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
+	file, err := retention.Open(eventsPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No events file yet
@@ -16,6 +16,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/estimate"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -63,6 +65,7 @@ var (
 	convoyMolecule     string
 	convoyNotify       string
 	convoyOwner        string
+	convoyAtomic       bool
 	convoyStatusJSON   bool
 	convoyListJSON     bool
 	convoyListStatus   string
@@ -111,7 +114,9 @@ COMMANDS:
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (manually, regardless of tracked issue status)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  land      Land an atomic convoy's held MRs together across rigs
+  template  Create convoys from reusable (optionally scheduled) templates`,
 }
 
 var convoyCreateCmd = &cobra.Command{
@@ -126,12 +131,19 @@ The --owner flag specifies who requested the convoy (receives completion
 notification by default). If not specified, defaults to created_by.
 The --notify flag adds additional subscribers beyond the owner.
 
+The --atomic flag makes the convoy land all at once: each rig's refinery
+verifies the convoy's MRs but holds them instead of pushing, and
+'gt convoy land' pushes them together once every one is verified. Use it
+when changes in different rigs must not ship separately (e.g. an API
+change and its client).
+
 Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+  gt convoy create "Auth API v2" gt-api bd-client --atomic`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runConvoyCreate,
 }
@@ -142,7 +154,13 @@ var convoyStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a convoy.
 
 Displays convoy metadata, tracked issues, and completion progress.
-Without an ID, shows status of all active convoys.`,
+Without an ID, shows status of all active convoys.
+
+Open convoys also show an ETA: a median estimate and a 90% band built
+from how long similar closed work took (by issue type, size:<s> label,
+and the assigned polecat's record), merge-queue latency, and the
+dependency graph among tracked issues. Issues on the critical path are
+marked ◆. Work history is rescanned at most every 15 minutes.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
 }
@@ -234,6 +252,7 @@ func init() {
 	convoyCreateCmd.Flags().StringVar(&convoyOwner, "owner", "", "Owner who requested convoy (gets completion notification)")
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Additional address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
+	convoyCreateCmd.Flags().BoolVar(&convoyAtomic, "atomic", false, "Hold verified MRs until all can land together")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -275,6 +294,11 @@ func getTownBeadsDir() (string, error) {
 	return filepath.Join(townRoot, ".beads"), nil
 }
 
+// townBeadsClient returns a bd client for the town beads at townBeads.
+func townBeadsClient(townBeads string) *beads.Beads {
+	return beads.NewWithBeadsDir(filepath.Dir(townBeads), townBeads)
+}
+
 func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	trackedIssues := args[1:]
@@ -307,45 +331,13 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
-
-	// Generate convoy ID with cv- prefix
-	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
-
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + name,
-		"--description=" + description,
-		"--json",
-	}
-
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	createCmd.Stdout = &stdout
-	createCmd.Stderr = &stderr
-
-	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	if convoyAtomic {
+		description += "\n" + atomicLandingLine
 	}
 
-	// Notify address is stored in description (line 166-168) and read from there
-
-	// Add 'tracks' relations for each tracked issue
-	trackedCount := 0
-	for _, issueID := range trackedIssues {
-		// Use --type=tracks for non-blocking tracking relation
-		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
-		depCmd := exec.Command("bd", depArgs...)
-		depCmd.Dir = townBeads
-
-		if err := depCmd.Run(); err != nil {
-			style.PrintWarning("couldn't track %s: %v", issueID, err)
-		} else {
-			trackedCount++
-		}
+	convoyID, trackedCount, err := createConvoy(townBeads, name, description, trackedIssues)
+	if err != nil {
+		return err
 	}
 
 	// Output
@@ -364,12 +356,48 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
+	if convoyAtomic {
+		fmt.Printf("  Landing:  atomic (MRs are held until all are verified)\n")
+	}
 
 	fmt.Printf("\n  %s\n", style.Dim.Render("Convoy auto-closes when all tracked issues complete"))
 
 	return nil
 }
 
+// createConvoy creates a convoy bead in town beads and adds a 'tracks'
+// relation to each issue. Issues that can't be tracked are warned about and
+// skipped; the count of tracked issues is returned.
+func createConvoy(townBeads, name, description string, trackedIssues []string) (string, int, error) {
+	// Generate convoy ID with cv- prefix
+	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
+
+	b := townBeadsClient(townBeads)
+	if _, err := b.CreateWithID(convoyID, beads.CreateOptions{
+		Title:       name,
+		IssueType:   "convoy",
+		Priority:    -1,
+		Description: description,
+	}); err != nil {
+		return "", 0, fmt.Errorf("creating convoy: %w", err)
+	}
+
+	// Notify address is stored in description (line 166-168) and read from there
+
+	// Add 'tracks' relations for each tracked issue
+	trackedCount := 0
+	for _, issueID := range trackedIssues {
+		// Use a tracks dependency for a non-blocking tracking relation
+		if err := b.AddDependencyWithType(convoyID, issueID, "tracks"); err != nil {
+			style.PrintWarning("couldn't track %s: %v", issueID, err)
+		} else {
+			trackedCount++
+		}
+	}
+
+	return convoyID, trackedCount, nil
+}
+
 func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	convoyID := args[0]
 	issuesToAdd := args[1:]
@@ -380,32 +408,12 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate convoy exists and get its status
-	showArgs := []string{"show", convoyID, "--json"}
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return fmt.Errorf("convoy '%s' not found", convoyID)
-	}
-
-	var convoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-		Type   string `json:"issue_type"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return fmt.Errorf("parsing convoy data: %w", err)
-	}
-
-	if len(convoys) == 0 {
+	b := townBeadsClient(townBeads)
+	convoy, err := b.Show(convoyID)
+	if err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
-	convoy := convoys[0]
-
 	// Verify it's actually a convoy type
 	if convoy.Type != "convoy" {
 		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
@@ -414,10 +422,8 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	// If convoy is closed, reopen it
 	reopened := false
 	if convoy.Status == "closed" {
-		reopenArgs := []string{"update", convoyID, "--status=open"}
-		reopenCmd := exec.Command("bd", reopenArgs...)
-		reopenCmd.Dir = townBeads
-		if err := reopenCmd.Run(); err != nil {
+		status := "open"
+		if err := b.Update(convoyID, beads.UpdateOptions{Status: &status}); err != nil {
 			return fmt.Errorf("couldn't reopen convoy: %w", err)
 		}
 		reopened = true
@@ -427,11 +433,7 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	// Add 'tracks' relations for each issue
 	addedCount := 0
 	for _, issueID := range issuesToAdd {
-		depArgs := []string{"dep", "add", convoyID, issueID, "--type=tracks"}
-		depCmd := exec.Command("bd", depArgs...)
-		depCmd.Dir = townBeads
-
-		if err := depCmd.Run(); err != nil {
+		if err := b.AddDependencyWithType(convoyID, issueID, "tracks"); err != nil {
 			style.PrintWarning("couldn't add %s: %v", issueID, err)
 		} else {
 			addedCount++
@@ -482,33 +484,12 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get convoy details
-	showArgs := []string{"show", convoyID, "--json"}
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return fmt.Errorf("convoy '%s' not found", convoyID)
-	}
-
-	var convoys []struct {
-		ID          string `json:"id"`
-		Title       string `json:"title"`
-		Status      string `json:"status"`
-		Type        string `json:"issue_type"`
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return fmt.Errorf("parsing convoy data: %w", err)
-	}
-
-	if len(convoys) == 0 {
+	b := townBeadsClient(townBeads)
+	convoy, err := b.Show(convoyID)
+	if err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
-	convoy := convoys[0]
-
 	// Verify it's actually a convoy type
 	if convoy.Type != "convoy" {
 		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
@@ -527,11 +508,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	// Close the convoy
-	closeArgs := []string{"close", convoyID, "-r", reason}
-	closeCmd := exec.Command("bd", closeArgs...)
-	closeCmd.Dir = townBeads
-
-	if err := closeCmd.Run(); err != nil {
+	if err := b.CloseWithReason(reason, convoyID); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -628,24 +605,12 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 	blockedIssues := getBlockedIssueIDs()
 
 	// List all open convoys
-	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	b := townBeadsClient(townBeads)
+	convoys, err := b.List(beads.ListOptions{IssueType: "convoy", Status: "open", Priority: -1})
+	if err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy list: %w", err)
-	}
-
 	// Check each convoy for stranded state
 	for _, convoy := range convoys {
 		tracked := getTrackedIssues(townBeads, convoy.ID)
@@ -678,22 +643,11 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 func getBlockedIssueIDs() map[string]bool {
 	blocked := make(map[string]bool)
 
-	// Run bd blocked --json
-	blockedCmd := exec.Command("bd", "blocked", "--json")
-	var stdout bytes.Buffer
-	blockedCmd.Stdout = &stdout
-
-	if err := blockedCmd.Run(); err != nil {
+	issues, err := routedBeads().Blocked()
+	if err != nil {
 		return blocked // Return empty set on error
 	}
 
-	var issues []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return blocked
-	}
-
 	for _, issue := range issues {
 		blocked[issue.ID] = true
 	}
@@ -744,24 +698,12 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 	var closed []struct{ ID, Title string }
 
 	// List all open convoys
-	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	b := townBeadsClient(townBeads)
+	convoys, err := b.List(beads.ListOptions{IssueType: "convoy", Status: "open", Priority: -1})
+	if err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy list: %w", err)
-	}
-
 	// Check each convoy
 	for _, convoy := range convoys {
 		tracked := getTrackedIssues(townBeads, convoy.ID)
@@ -780,11 +722,7 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 
 		if allClosed {
 			// Close the convoy
-			closeArgs := []string{"close", convoy.ID, "-r", "All tracked issues completed"}
-			closeCmd := exec.Command("bd", closeArgs...)
-			closeCmd.Dir = townBeads
-
-			if err := closeCmd.Run(); err != nil {
+			if err := b.CloseWithReason("All tracked issues completed", convoy.ID); err != nil {
 				style.PrintWarning("couldn't close convoy %s: %v", convoy.ID, err)
 				continue
 			}
@@ -802,25 +740,13 @@ func checkAndCloseCompletedConvoys(townBeads string) ([]struct{ ID, Title string
 // notifyConvoyCompletion sends notifications to owner and any notify addresses.
 func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	// Get convoy description to find owner and notify addresses
-	showArgs := []string{"show", convoyID, "--json"}
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return
-	}
-
-	var convoys []struct {
-		Description string `json:"description"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil || len(convoys) == 0 {
+	convoy, err := townBeadsClient(townBeads).Show(convoyID)
+	if err != nil {
 		return
 	}
 
 	// Parse owner and notify addresses from description
-	desc := convoys[0].Description
+	desc := convoy.Description
 	notified := make(map[string]bool) // Track who we've notified to avoid duplicates
 
 	for _, line := range strings.Split(desc, "\n") {
@@ -866,36 +792,11 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get convoy details
-	showArgs := []string{"show", convoyID, "--json"}
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return fmt.Errorf("convoy '%s' not found", convoyID)
-	}
-
-	// Parse convoy data
-	var convoys []struct {
-		ID          string   `json:"id"`
-		Title       string   `json:"title"`
-		Status      string   `json:"status"`
-		Description string   `json:"description"`
-		CreatedAt   string   `json:"created_at"`
-		ClosedAt    string   `json:"closed_at,omitempty"`
-		DependsOn   []string `json:"depends_on,omitempty"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return fmt.Errorf("parsing convoy data: %w", err)
-	}
-
-	if len(convoys) == 0 {
+	convoy, err := townBeadsClient(townBeads).Show(convoyID)
+	if err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
-	convoy := convoys[0]
-
 	// Get tracked issues by querying SQLite directly
 	// (bd dep list doesn't properly show cross-rig external dependencies)
 	type trackedIssue struct {
@@ -916,6 +817,13 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Estimate landing time for open convoys (best-effort)
+	var eta *estimate.Estimate
+	var etaErr error
+	if convoy.Status != "closed" && completed < len(tracked) {
+		eta, etaErr = estimateConvoy(filepath.Dir(townBeads), tracked)
+	}
+
 	if convoyStatusJSON {
 		type jsonStatus struct {
 			ID        string             `json:"id"`
@@ -924,6 +832,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked   []trackedIssueInfo `json:"tracked"`
 			Completed int                `json:"completed"`
 			Total     int                `json:"total"`
+			Estimate  *estimate.Estimate `json:"estimate,omitempty"`
 		}
 		out := jsonStatus{
 			ID:        convoy.ID,
@@ -932,6 +841,7 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:   tracked,
 			Completed: completed,
 			Total:     len(tracked),
+			Estimate:  eta,
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -946,6 +856,14 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
 	}
+	if isAtomicConvoy(convoy.Description) {
+		fmt.Printf("  Landing:   atomic %s\n", style.Dim.Render("(gt convoy land)"))
+	}
+	if eta != nil {
+		printConvoyEstimate(eta)
+	} else if etaErr != nil {
+		fmt.Printf("  ETA:       %s\n", style.Dim.Render("unavailable: "+etaErr.Error()))
+	}
 
 	if len(tracked) > 0 {
 		fmt.Printf("\n  %s\n", style.Bold.Render("Tracked Issues:"))
@@ -969,6 +887,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			}
 
 			line := fmt.Sprintf("    %s %s: %s [%s]", status, t.ID, t.Title, bracketContent)
+			if eta != nil && eta.CriticalPath.Contains(t.ID) {
+				line += " " + style.Warning.Render("◆")
+			}
 			if t.Worker != "" {
 				workerDisplay := "@" + t.Worker
 				if t.WorkerAge != "" {
@@ -983,25 +904,44 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// estimateConvoy predicts when a convoy's tracked issues will have landed.
+func estimateConvoy(townRoot string, tracked []trackedIssueInfo) (*estimate.Estimate, error) {
+	ids := make([]string, 0, len(tracked))
+	for _, t := range tracked {
+		ids = append(ids, t.ID)
+	}
+	return estimate.ForIssues(townRoot, ids)
+}
+
+// printConvoyEstimate prints the ETA block of convoy status.
+func printConvoyEstimate(eta *estimate.Estimate) {
+	fmt.Printf("  ETA:       %s %s\n", eta.Summary(),
+		style.Dim.Render(fmt.Sprintf("→ %s, 90%% by %s (%s confidence)",
+			eta.ETA50.Local().Format("Mon 15:04"), eta.ETA90.Local().Format("Mon 15:04"), eta.Confidence())))
+	if len(eta.CriticalPath) > 0 {
+		note := ""
+		if eta.CapacityBound {
+			note = style.Dim.Render(" (worker capacity is the bottleneck)")
+		}
+		fmt.Printf("  Critical:  %s%s\n", strings.Join(eta.CriticalPath, " → "), note)
+	}
+}
+
 func showAllConvoyStatus(townBeads string) error {
 	// List all convoy-type issues
-	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	issues, err := townBeadsClient(townBeads).List(beads.ListOptions{IssueType: "convoy", Status: "open", Priority: -1})
+	if err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
+	type convoyStatus struct {
 		ID     string `json:"id"`
 		Title  string `json:"title"`
 		Status string `json:"status"`
 	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return fmt.Errorf("parsing convoy list: %w", err)
+	convoys := make([]convoyStatus, len(issues))
+	for i, issue := range issues {
+		convoys[i] = convoyStatus{ID: issue.ID, Title: issue.Title, Status: issue.Status}
 	}
 
 	if len(convoys) == 0 {
@@ -1032,31 +972,22 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 	}
 
 	// List convoy-type issues
-	listArgs := []string{"list", "--type=convoy", "--json"}
+	opts := beads.ListOptions{IssueType: "convoy", Priority: -1}
 	if convoyListStatus != "" {
-		listArgs = append(listArgs, "--status="+convoyListStatus)
+		opts.Status = convoyListStatus
 	} else if convoyListAll {
-		listArgs = append(listArgs, "--all")
+		opts.All = true
 	}
 	// Default (no flags) = open only (bd's default behavior)
 
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	issues, err := townBeadsClient(townBeads).List(opts)
+	if err != nil {
 		return fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		CreatedAt string `json:"created_at"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return fmt.Errorf("parsing convoy list: %w", err)
+	convoys := make([]convoyListEntry, len(issues))
+	for i, issue := range issues {
+		convoys[i] = convoyListEntry{ID: issue.ID, Title: issue.Title, Status: issue.Status, CreatedAt: issue.CreatedAt}
 	}
 
 	if convoyListJSON {
@@ -1086,13 +1017,16 @@ func runConvoyList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// printConvoyTree displays convoys with their child issues in a tree format.
-func printConvoyTree(townBeads string, convoys []struct {
+// convoyListEntry is a convoy as shown by gt convoy list.
+type convoyListEntry struct {
 	ID        string `json:"id"`
 	Title     string `json:"title"`
 	Status    string `json:"status"`
 	CreatedAt string `json:"created_at"`
-}) error {
+}
+
+// printConvoyTree displays convoys with their child issues in a tree format.
+func printConvoyTree(townBeads string, convoys []convoyListEntry) error {
 	for _, c := range convoys {
 		// Get tracked issues for this convoy
 		tracked := getTrackedIssues(townBeads, c.ID)
@@ -1269,44 +1203,14 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 		return result
 	}
 
-	// Build args: bd --no-daemon show id1 id2 id3 ... --json
-	// Use --no-daemon to ensure fresh data (avoid stale cache from daemon)
-	args := append([]string{"--no-daemon", "show"}, issueIDs...)
-	args = append(args, "--json")
-
-	showCmd := exec.Command("bd", args...)
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		// Batch failed - fall back to individual lookups for robustness
-		// This handles cases where some IDs are invalid/missing
-		for _, id := range issueIDs {
-			if details := getIssueDetails(id); details != nil {
-				result[id] = details
-			}
-		}
-		return result
-	}
-
-	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Assignee  string `json:"assignee"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return result
-	}
-
-	for _, issue := range issues {
-		result[issue.ID] = &issueDetails{
-			ID:        issue.ID,
-			Title:     issue.Title,
-			Status:    issue.Status,
-			IssueType: issue.IssueType,
-			Assignee:  issue.Assignee,
+	// bd fails the whole batch if one ID is invalid or missing, so look
+	// up whatever the batch didn't return one at a time
+	issues, _ := routedBeads().ShowMultiple(issueIDs)
+	for _, id := range issueIDs {
+		if issue, ok := issues[id]; ok {
+			result[id] = newIssueDetails(issue)
+		} else if details := getIssueDetails(id); details != nil {
+			result[id] = details
 		}
 	}
 
@@ -1317,39 +1221,31 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 // Prefer getIssueDetailsBatch for multiple issues to avoid N+1 subprocess calls.
 func getIssueDetails(issueID string) *issueDetails {
 	// Use bd show with routing - it should find the issue in the right rig
-	// Use --no-daemon to ensure fresh data (avoid stale cache)
-	showCmd := exec.Command("bd", "--no-daemon", "show", issueID, "--json")
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return nil
-	}
-	// Handle bd --no-daemon exit 0 bug: empty stdout means not found
-	if stdout.Len() == 0 {
-		return nil
-	}
-
-	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Assignee  string `json:"assignee"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
+	issue, err := routedBeads().Show(issueID)
+	if err != nil {
 		return nil
 	}
+	return newIssueDetails(issue)
+}
 
+func newIssueDetails(issue *beads.Issue) *issueDetails {
 	return &issueDetails{
-		ID:        issues[0].ID,
-		Title:     issues[0].Title,
-		Status:    issues[0].Status,
-		IssueType: issues[0].IssueType,
-		Assignee:  issues[0].Assignee,
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Status:    issue.Status,
+		IssueType: issue.Type,
+		Assignee:  issue.Assignee,
 	}
 }
 
+// routedBeads returns a bd client that routes issue IDs to their rig by
+// prefix from the town root, or runs in the current directory outside a
+// town.
+func routedBeads() *beads.Beads {
+	townRoot, _ := workspace.FindFromCwd()
+	return beads.NewRouted(townRoot)
+}
+
 // workerInfo holds info about a worker assigned to an issue.
 type workerInfo struct {
 	Worker string // Agent identity (e.g., gastown/nux)
@@ -1521,23 +1417,11 @@ func runConvoyTUI() error {
 // Numbers correspond to the order shown in 'gt convoy list'.
 func resolveConvoyNumber(townBeads string, n int) (string, error) {
 	// Get convoy list (same query as runConvoyList)
-	listArgs := []string{"list", "--type=convoy", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	convoys, err := townBeadsClient(townBeads).List(beads.ListOptions{IssueType: "convoy", Priority: -1})
+	if err != nil {
 		return "", fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var convoys []struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return "", fmt.Errorf("parsing convoy list: %w", err)
-	}
-
 	if n < 1 || n > len(convoys) {
 		return "", fmt.Errorf("convoy %d not found (have %d convoys)", n, len(convoys))
 	}
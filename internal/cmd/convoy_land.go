@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// atomicLandingLine marks a convoy whose MRs land together.
+const atomicLandingLine = "Landing: atomic"
+
+var (
+	convoyLandReady  bool
+	convoyLandDryRun bool
+)
+
+var convoyLandCmd = &cobra.Command{
+	Use:   "land [convoy-id]",
+	Short: "Land an atomic convoy's held merge requests together",
+	Long: `Land every merge request of an atomic convoy at once.
+
+In an atomic convoy (gt convoy create --atomic), each rig's refinery runs
+the tests for the convoy's MRs as usual but, instead of pushing, parks the
+verified result on a held/<mr-id> branch (gt mq hold). When every open
+tracked issue has a held MR, this command lands them:
+
+  1. Each rig's held commits must form one line of history that still
+     fast-forwards the rig's target branch. If any rig fails this check,
+     nothing is pushed: the stale MRs are released back to their queue
+     for re-verification and the convoy owner is notified.
+  2. Each rig's held tip is pushed in turn. Git can't push to several
+     repositories transactionally, so if a push is still rejected, landing
+     stops there and the report names which rigs landed and which didn't;
+     the rest are released for re-verification.
+  3. Landed MRs and their source issues are closed as for a normal merge.
+
+With --ready, every open atomic convoy whose MRs are all held is landed;
+the daemon runs this on each heartbeat.
+
+Examples:
+  gt convoy land hq-cv-abc
+  gt convoy land hq-cv-abc --dry-run
+  gt convoy land --ready`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConvoyLand,
+}
+
+func init() {
+	convoyLandCmd.Flags().BoolVar(&convoyLandReady, "ready", false, "Land every atomic convoy that is fully verified")
+	convoyLandCmd.Flags().BoolVarP(&convoyLandDryRun, "dry-run", "n", false, "Check readiness and show what would be pushed")
+	convoyCmd.AddCommand(convoyLandCmd)
+}
+
+// isAtomicConvoy reports whether a convoy description opts into atomic
+// landing.
+func isAtomicConvoy(description string) bool {
+	for _, line := range strings.Split(description, "\n") {
+		if strings.TrimSpace(line) == atomicLandingLine {
+			return true
+		}
+	}
+	return false
+}
+
+// atomicConvoyFor returns the atomic convoy tracking an issue, or "".
+func atomicConvoyFor(issueID string) string {
+	convoyID := isTrackedByConvoy(issueID)
+	if convoyID == "" {
+		return ""
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return ""
+	}
+	convoy, err := beads.New(filepath.Join(townRoot, ".beads")).Show(convoyID)
+	if err != nil || !isAtomicConvoy(convoy.Description) {
+		return ""
+	}
+	return convoyID
+}
+
+func runConvoyLand(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	if convoyLandReady {
+		if len(args) > 0 {
+			return fmt.Errorf("--ready lands every ready convoy; don't pass a convoy ID")
+		}
+		return landReadyConvoys(townBeads)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("convoy ID required (or use --ready)")
+	}
+
+	convoy, err := beads.New(townBeads).Show(args[0])
+	if err != nil {
+		return fmt.Errorf("convoy '%s' not found", args[0])
+	}
+	if !isAtomicConvoy(convoy.Description) {
+		return fmt.Errorf("%s is not an atomic convoy; its MRs land through each rig's refinery", convoy.ID)
+	}
+	_, err = landConvoy(townBeads, convoy, false)
+	return err
+}
+
+// landReadyConvoys lands every open atomic convoy whose MRs are all held.
+// Convoys still waiting on MRs are skipped silently.
+func landReadyConvoys(townBeads string) error {
+	convoys, err := townBeadsClient(townBeads).List(beads.ListOptions{
+		IssueType: "convoy",
+		Status:    "open",
+		Priority:  -1,
+	})
+	if err != nil {
+		return fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var failed []string
+	for _, convoy := range convoys {
+		if !isAtomicConvoy(convoy.Description) {
+			continue
+		}
+		if _, err := landConvoy(townBeads, convoy, true); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", convoy.ID, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("atomic landing failed:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// rigLanding is one rig's share of an atomic convoy.
+type rigLanding struct {
+	rig    string
+	eng    *refinery.Engineer
+	mrs    []*refinery.HeldMR
+	tip    string
+	target string
+}
+
+// landConvoy lands an atomic convoy if all its MRs are held. It returns
+// whether anything was pushed. With quiet set, a convoy that isn't ready yet
+// produces no output.
+func landConvoy(townBeads string, convoy *beads.Issue, quiet bool) (bool, error) {
+	tracked := getTrackedIssues(townBeads, convoy.ID)
+	open := make(map[string]bool)
+	for _, t := range tracked {
+		if t.Status != "closed" {
+			open[t.ID] = true
+		}
+	}
+	if len(open) == 0 {
+		if !quiet {
+			fmt.Printf("%s has no open tracked issues; nothing to land\n", convoy.ID)
+		}
+		return false, nil
+	}
+
+	rigs, _, err := getAllRigs()
+	if err != nil {
+		return false, err
+	}
+	var all []*refinery.HeldMR
+	landingFor := make(map[*refinery.HeldMR]*rigLanding)
+	for _, r := range rigs {
+		eng := refinery.NewEngineer(r)
+		eng.SetOutput(&bytes.Buffer{}) // Engineer chatter stays out of the report
+		mrs, err := eng.ListMRsForIssues(open)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		l := &rigLanding{rig: r.Name, eng: eng}
+		for _, mr := range mrs {
+			all = append(all, mr)
+			landingFor[mr] = l
+		}
+	}
+
+	// One MR per issue lands; superseded submissions are left alone
+	var landings []*rigLanding
+	mrFor := make(map[string]*refinery.HeldMR)
+	for _, mr := range refinery.SelectMRs(all) {
+		mrFor[mr.Fields.SourceIssue] = mr
+		l := landingFor[mr]
+		if len(l.mrs) == 0 {
+			landings = append(landings, l)
+		}
+		l.mrs = append(l.mrs, mr)
+	}
+
+	// Every open tracked issue needs a held MR
+	var pending []string
+	for _, t := range tracked {
+		if !open[t.ID] {
+			continue
+		}
+		mr := mrFor[t.ID]
+		switch {
+		case mr == nil:
+			pending = append(pending, fmt.Sprintf("%s: no merge request yet", t.ID))
+		case mr.Fields.HeldCommit == "":
+			pending = append(pending, fmt.Sprintf("%s: %s awaiting verification", t.ID, mr.Issue.ID))
+		}
+	}
+	if len(pending) > 0 {
+		if !quiet {
+			fmt.Printf("%s Convoy %s is not ready to land (%d/%d verified):\n",
+				style.Dim.Render("○"), convoy.ID, len(open)-len(pending), len(open))
+			for _, p := range pending {
+				fmt.Printf("  %s\n", p)
+			}
+		}
+		return false, nil
+	}
+
+	// Preflight: nothing is pushed unless every rig can fast-forward
+	var stale []string
+	for _, l := range landings {
+		tip, target, err := l.eng.CheckHeld(l.mrs)
+		if err != nil {
+			stale = append(stale, fmt.Sprintf("%s: %v", l.rig, err))
+			releaseHeld(l)
+			continue
+		}
+		l.tip, l.target = tip, target
+	}
+	if len(stale) > 0 {
+		report := fmt.Sprintf("Atomic landing of %s aborted before pushing anything.\n\n%s\n\nThe affected MRs were released for re-verification; the convoy lands once they are held again.",
+			convoy.ID, strings.Join(stale, "\n"))
+		notifyAtomicLanding(convoy, "aborted", report)
+		return false, fmt.Errorf("%s not landed: %s", convoy.ID, strings.Join(stale, "; "))
+	}
+
+	fmt.Printf("%s Landing convoy %s across %d rig(s)\n", style.Bold.Render("🚚"), convoy.ID, len(landings))
+	if convoyLandDryRun {
+		for _, l := range landings {
+			fmt.Printf("  %s: would push %s to %s (%s)\n", l.rig, shortSHA(l.tip), l.target, heldIDs(l))
+		}
+		return false, nil
+	}
+
+	// Push each rig; stop at the first rejection
+	var landed []string
+	for i, l := range landings {
+		if err := l.eng.PushHeld(l.tip, l.target); err != nil {
+			var notLanded []string
+			for _, rest := range landings[i:] {
+				notLanded = append(notLanded, fmt.Sprintf("%s (%s)", rest.rig, heldIDs(rest)))
+				releaseHeld(rest)
+			}
+			report := fmt.Sprintf("Atomic landing of %s stopped: %s: %v\n\nLanded: %s\nNot landed (released for re-verification): %s",
+				convoy.ID, l.rig, err, orNone(landed), strings.Join(notLanded, ", "))
+			fmt.Printf("  %s %s: %v\n", style.Bold.Render("✗"), l.rig, err)
+			notifyAtomicLanding(convoy, "partially landed", report)
+			return len(landed) > 0, fmt.Errorf("%s partially landed: %s failed; landed: %s", convoy.ID, l.rig, orNone(landed))
+		}
+		for _, mr := range l.mrs {
+			l.eng.CompleteHeldMR(mr)
+		}
+		landed = append(landed, fmt.Sprintf("%s (%s)", l.rig, heldIDs(l)))
+		fmt.Printf("  %s %s: pushed %s to %s (%s)\n", style.Bold.Render("✓"), l.rig, shortSHA(l.tip), l.target, heldIDs(l))
+	}
+	return true, nil
+}
+
+// releaseHeld returns a rig's held MRs to its queue.
+func releaseHeld(l *rigLanding) {
+	for _, mr := range l.mrs {
+		if mr.Fields.HeldCommit == "" {
+			continue
+		}
+		if err := l.eng.ReleaseHeldMR(mr.Issue.ID); err != nil {
+			style.PrintWarning("couldn't release %s: %v", mr.Issue.ID, err)
+		}
+	}
+}
+
+// notifyAtomicLanding mails a landing failure report to the convoy's owner,
+// or the mayor if it has none, and prints it.
+func notifyAtomicLanding(convoy *beads.Issue, outcome, report string) {
+	fmt.Println(report)
+	addr := "mayor/"
+	for _, line := range strings.Split(convoy.Description, "\n") {
+		if strings.HasPrefix(line, "Owner: ") {
+			addr = strings.TrimPrefix(line, "Owner: ")
+			break
+		}
+	}
+	subject := fmt.Sprintf("🚚 Convoy %s: %s", outcome, convoy.Title)
+	if err := exec.Command("gt", "mail", "send", addr, "-s", subject, "-m", report).Run(); err != nil {
+		style.PrintWarning("couldn't notify %s: %v", addr, err)
+	}
+}
+
+func heldIDs(l *rigLanding) string {
+	ids := make([]string, len(l.mrs))
+	for i, mr := range l.mrs {
+		ids[i] = mr.Issue.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+func orNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
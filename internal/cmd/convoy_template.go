@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoytmpl"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Convoy template flags
+var (
+	convoyTemplateSling     bool
+	convoyTemplateDryRun    bool
+	convoyTemplateVars      []string
+	convoyTemplateScheduled bool
+	convoyTemplateJSON      bool
+)
+
+var convoyTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Create convoys from reusable templates",
+	Long: `Create convoys from TOML templates in settings/convoys/.
+
+A template describes a convoy and the issues it creates: which rig each
+issue goes to (or a list of rigs to fan out across), titles, types,
+priorities, labels, and dependencies between issues. Running a template
+creates the issues, wires their dependencies, creates a convoy tracking
+them, and optionally slings the ready ones.
+
+Templates with a 'schedule' (cron syntax) are run by the daemon each time
+they come due. Every run is numbered and linked to the previous run so
+'gt convoy template history' can compare them.
+
+Example settings/convoys/deps.toml:
+
+  title = "Dependency bumps {{week}}"
+  labels = ["deps"]
+  sling = true
+  schedule = "0 9 * * mon"
+
+  [[issue]]
+  key = "bump"
+  rigs = ["gastown", "beads"]
+  title = "Bump dependencies in {{rig}}"
+  labels = ["size:s"]
+
+  [[issue]]
+  key = "release"
+  rig = "gastown"
+  title = "Cut release after dependency bumps"
+  depends_on = ["bump"]
+
+Placeholders: {{date}}, {{week}}, {{run}}, {{rig}} (per-rig issues), and
+any --var name=value.`,
+	RunE: requireSubcommand,
+}
+
+var convoyTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List convoy templates and their schedules",
+	Args:  cobra.NoArgs,
+	RunE:  runConvoyTemplateList,
+}
+
+var convoyTemplateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the issues a template would create",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConvoyTemplateShow,
+}
+
+var convoyTemplateRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Create a convoy and its issues from a template",
+	Long: `Create a convoy and its issues from a template.
+
+Issues are created in dependency order in each issue's rig (town beads
+when no rig is given), blocking dependencies are added between them, and
+a convoy is created tracking all of them. The convoy description links to
+the template and the previous run. If creating an issue or the convoy
+fails, the issues already created are closed again.
+
+With --sling (or sling = true in the template), issues with no
+dependencies inside the template are slung to their rig immediately.
+Blocked issues wait for their dependencies.
+
+Examples:
+  gt convoy template run deps
+  gt convoy template run release --var version=2.1 --sling
+  gt convoy template run deps --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyTemplateRun,
+}
+
+var convoyTemplateHistoryCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "Compare past runs of a template",
+	Long: `Show past runs of a template with their convoys, progress and how
+long each took to land, so consecutive runs can be compared.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyTemplateHistory,
+}
+
+func init() {
+	convoyTemplateRunCmd.Flags().BoolVar(&convoyTemplateSling, "sling", false, "Sling ready issues immediately (overrides the template)")
+	convoyTemplateRunCmd.Flags().BoolVarP(&convoyTemplateDryRun, "dry-run", "n", false, "Show what would be created without creating anything")
+	convoyTemplateRunCmd.Flags().StringArrayVar(&convoyTemplateVars, "var", nil, "Template variable as name=value (repeatable)")
+	convoyTemplateRunCmd.Flags().BoolVar(&convoyTemplateScheduled, "scheduled", false, "Mark the run as started by the daemon schedule")
+	_ = convoyTemplateRunCmd.Flags().MarkHidden("scheduled")
+
+	convoyTemplateShowCmd.Flags().StringArrayVar(&convoyTemplateVars, "var", nil, "Template variable as name=value (repeatable)")
+	convoyTemplateHistoryCmd.Flags().BoolVar(&convoyTemplateJSON, "json", false, "Output as JSON")
+
+	convoyTemplateCmd.AddCommand(convoyTemplateListCmd)
+	convoyTemplateCmd.AddCommand(convoyTemplateShowCmd)
+	convoyTemplateCmd.AddCommand(convoyTemplateRunCmd)
+	convoyTemplateCmd.AddCommand(convoyTemplateHistoryCmd)
+	convoyCmd.AddCommand(convoyTemplateCmd)
+}
+
+func runConvoyTemplateList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	templates, errs := convoytmpl.List(townRoot)
+	for _, err := range errs {
+		style.PrintWarning("%v", err)
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No convoy templates in %s\n", convoytmpl.Dir(townRoot))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tISSUES\tSCHEDULE\tNEXT RUN\tLAST RUN")
+	for _, t := range templates {
+		planned, _ := t.Expand(nil)
+		schedule, next, last := "-", "-", "-"
+		hist, err := convoytmpl.LoadHistory(townRoot, t.Name)
+		if err != nil {
+			hist = &convoytmpl.History{}
+		}
+		if t.Schedule != "" {
+			schedule = t.Schedule
+			if due := hist.NextDue(t); !due.IsZero() {
+				next = due.Format("Mon Jan 2 15:04")
+			} else {
+				next = "when daemon arms it"
+			}
+		}
+		if r := hist.Last(); r != nil {
+			last = fmt.Sprintf("#%d %s (%s)", r.Number, r.ConvoyID, r.At.Format("Jan 2"))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", t.Name, len(planned), schedule, next, last)
+	}
+	return w.Flush()
+}
+
+func runConvoyTemplateShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	t, err := convoytmpl.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	hist, err := convoytmpl.LoadHistory(townRoot, t.Name)
+	if err != nil {
+		return err
+	}
+	vars, err := convoyTemplateRunVars(time.Now(), hist.NextNumber())
+	if err != nil {
+		return err
+	}
+	planned, err := t.Expand(vars)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(t.Name+":"), vars.Apply(t.Title))
+	fmt.Printf("  File:      %s\n", t.Path())
+	if t.Schedule != "" {
+		fmt.Printf("  Schedule:  %s\n", t.Schedule)
+	}
+	fmt.Println()
+	printTemplatePlan(planned, t.Sling || convoyTemplateSling)
+	return nil
+}
+
+// convoyTemplateRunVars returns the standard variables plus --var values.
+func convoyTemplateRunVars(now time.Time, run int) (convoytmpl.Vars, error) {
+	vars := convoytmpl.StandardVars(now, run)
+	for _, kv := range convoyTemplateVars {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected name=value", kv)
+		}
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// printTemplatePlan prints the issues a run will create.
+func printTemplatePlan(planned []convoytmpl.PlannedIssue, slingAll bool) {
+	for _, p := range planned {
+		where := p.Rig
+		if where == "" {
+			where = "town"
+		}
+		line := fmt.Sprintf("  %-16s [%s] %s", p.Ref, where, p.Title)
+		if len(p.DependsOn) > 0 {
+			line += style.Dim.Render("  after " + strings.Join(p.DependsOn, ", "))
+		} else if (p.Sling || slingAll) && p.Rig != "" {
+			line += style.Dim.Render("  (sling)")
+		}
+		fmt.Println(line)
+	}
+}
+
+func runConvoyTemplateRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	t, err := convoytmpl.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	hist, err := convoytmpl.LoadHistory(townRoot, t.Name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	number := hist.NextNumber()
+	vars, err := convoyTemplateRunVars(now, number)
+	if err != nil {
+		return err
+	}
+	planned, err := t.Expand(vars)
+	if err != nil {
+		return err
+	}
+
+	if convoyTemplateDryRun {
+		fmt.Printf("Would create convoy %q (run #%d) with:\n\n", vars.Apply(t.Title), number)
+		printTemplatePlan(planned, convoyTemplateSling)
+		return nil
+	}
+
+	// Resolve every rig before creating anything
+	dirs, err := templateRigDirs(townRoot, planned)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]string, len(planned))
+	var created []string
+	for _, p := range planned {
+		id, err := createTemplateIssue(dirs[p.Rig], p)
+		if err != nil {
+			discardTemplateIssues(dirs, planned, ids, "creating "+p.Ref+" failed")
+			return fmt.Errorf("creating %s: %w", p.Ref, err)
+		}
+		ids[p.Ref] = id
+		created = append(created, id)
+
+		for _, dep := range p.DependsOn {
+			if err := beads.New(dirs[p.Rig]).AddDependency(id, ids[dep]); err != nil {
+				style.PrintWarning("couldn't make %s depend on %s: %v", id, ids[dep], err)
+			}
+		}
+	}
+
+	// Link the convoy to the template and the previous run
+	description := vars.Apply(t.Description)
+	if description != "" {
+		description += "\n\n"
+	}
+	description += fmt.Sprintf("Template: %s\nRun: %d", t.Name, number)
+	if last := hist.Last(); last != nil {
+		description += fmt.Sprintf("\nPrevious: %s", last.ConvoyID)
+	}
+	if t.Owner != "" {
+		description += fmt.Sprintf("\nOwner: %s", t.Owner)
+	}
+	if t.Notify != "" {
+		description += fmt.Sprintf("\nNotify: %s", t.Notify)
+	}
+
+	convoyID, tracked, err := createConvoy(filepath.Join(townRoot, ".beads"), vars.Apply(t.Title), description, created)
+	if err != nil {
+		discardTemplateIssues(dirs, planned, ids, "creating the convoy failed")
+		return err
+	}
+
+	hist.Runs = append(hist.Runs, convoytmpl.Run{
+		Number:    number,
+		ConvoyID:  convoyID,
+		At:        now,
+		Scheduled: convoyTemplateScheduled,
+		Issues:    ids,
+	})
+	if err := convoytmpl.SaveHistory(townRoot, t.Name, hist); err != nil {
+		style.PrintWarning("couldn't record run: %v", err)
+	}
+
+	fmt.Printf("%s Created convoy 🚚 %s from template %s (run #%d)\n\n", style.Bold.Render("✓"), convoyID, t.Name, number)
+	fmt.Printf("  Tracking: %d issues\n", tracked)
+	for _, p := range planned {
+		fmt.Printf("    %s  %s\n", ids[p.Ref], p.Title)
+	}
+
+	// Sling ready issues
+	slung := 0
+	for _, p := range planned {
+		if !(p.Sling || convoyTemplateSling) || p.Rig == "" || len(p.DependsOn) > 0 {
+			continue
+		}
+		slingCmd := exec.Command("gt", "sling", ids[p.Ref], p.Rig) //nolint:gosec // G204: IDs come from bd
+		slingCmd.Dir = townRoot
+		if out, err := slingCmd.CombinedOutput(); err != nil {
+			style.PrintWarning("couldn't sling %s to %s: %s", ids[p.Ref], p.Rig, strings.TrimSpace(string(out)))
+			continue
+		}
+		slung++
+	}
+	if slung > 0 {
+		fmt.Printf("\n  Slung %d ready issue(s)\n", slung)
+	}
+	return nil
+}
+
+// discardTemplateIssues closes the issues a failed template run created,
+// so a partial run doesn't leave untracked work behind for a rerun to
+// duplicate. Issues that can't be closed are reported.
+func discardTemplateIssues(dirs map[string]string, planned []convoytmpl.PlannedIssue, ids map[string]string, reason string) {
+	var left []string
+	for _, p := range planned {
+		id, ok := ids[p.Ref]
+		if !ok {
+			continue
+		}
+		if err := beads.New(dirs[p.Rig]).CloseWithReason("template run abandoned: "+reason, id); err != nil {
+			left = append(left, id)
+		}
+	}
+	if len(left) > 0 {
+		style.PrintWarning("couldn't close issues from the failed run: %s", strings.Join(left, ", "))
+	}
+}
+
+// templateRigDirs maps each rig in the plan to the directory bd runs in.
+// The empty rig is town beads.
+func templateRigDirs(townRoot string, planned []convoytmpl.PlannedIssue) (map[string]string, error) {
+	dirs := map[string]string{"": townRoot}
+	var rigsByName map[string]string
+	for _, p := range planned {
+		if _, ok := dirs[p.Rig]; ok {
+			continue
+		}
+		if rigsByName == nil {
+			rigs, _, err := getAllRigs()
+			if err != nil {
+				return nil, err
+			}
+			rigsByName = make(map[string]string, len(rigs))
+			for _, r := range rigs {
+				rigsByName[r.Name] = r.BeadsPath()
+			}
+		}
+		dir, ok := rigsByName[p.Rig]
+		if !ok {
+			return nil, fmt.Errorf("issue %s: rig %q not found", p.Ref, p.Rig)
+		}
+		dirs[p.Rig] = dir
+	}
+	return dirs, nil
+}
+
+// createTemplateIssue creates one planned issue and returns its ID.
+func createTemplateIssue(dir string, p convoytmpl.PlannedIssue) (string, error) {
+	issue, err := beads.New(dir).Create(beads.CreateOptions{
+		Title:       p.Title,
+		IssueType:   p.Type,
+		Priority:    p.Priority,
+		Description: p.Description,
+		Labels:      p.Labels,
+	})
+	if err != nil {
+		return "", err
+	}
+	if issue.ID == "" {
+		return "", fmt.Errorf("bd create returned no ID")
+	}
+	return issue.ID, nil
+}
+
+// templateRunStatus is a past run with its convoy's current state.
+type templateRunStatus struct {
+	convoytmpl.Run
+	Status    string `json:"status"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+	LandedIn  string `json:"landed_in,omitempty"`
+}
+
+func runConvoyTemplateHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name := args[0]
+	hist, err := convoytmpl.LoadHistory(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if len(hist.Runs) == 0 {
+		fmt.Printf("Template %s has not been run yet.\n", name)
+		return nil
+	}
+
+	convoyIDs := make([]string, len(hist.Runs))
+	for i, r := range hist.Runs {
+		convoyIDs[i] = r.ConvoyID
+	}
+	convoys := getConvoyDetailsBatch(convoyIDs)
+
+	runs := make([]templateRunStatus, len(hist.Runs))
+	for i, r := range hist.Runs {
+		rs := templateRunStatus{Run: r, Status: "unknown", Total: len(r.Issues)}
+		if c, ok := convoys[r.ConvoyID]; ok {
+			rs.Status = c.Status
+			if closed, err := time.Parse(time.RFC3339, c.ClosedAt); err == nil {
+				rs.LandedIn = formatDuration(closed.Sub(r.At))
+			}
+		}
+		issueIDs := make([]string, 0, len(r.Issues))
+		for _, id := range r.Issues {
+			issueIDs = append(issueIDs, id)
+		}
+		for _, d := range getIssueDetailsBatch(issueIDs) {
+			if d.Status == "closed" {
+				rs.Completed++
+			}
+		}
+		runs[i] = rs
+	}
+
+	if convoyTemplateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(runs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tCONVOY\tSTARTED\tSTATUS\tDONE\tLANDED IN")
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		started := r.At.Format("2006-01-02 15:04")
+		if r.Scheduled {
+			started += " (sched)"
+		}
+		landed := r.LandedIn
+		if landed == "" {
+			landed = "-"
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%d/%d\t%s\n", r.Number, r.ConvoyID, started, r.Status, r.Completed, r.Total, landed)
+	}
+	return w.Flush()
+}
+
+// getConvoyDetailsBatch fetches convoys from town beads in one bd call.
+func getConvoyDetailsBatch(ids []string) map[string]*beads.Issue {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return nil
+	}
+	convoys, err := townBeadsClient(townBeads).ShowMultiple(ids)
+	if err != nil {
+		return nil
+	}
+	return convoys
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
@@ -325,18 +326,6 @@ func runCostsFromLedger() error {
 	return outputLedgerHuman(output, entries)
 }
 
-// SessionEvent represents a session.ended event from beads.
-type SessionEvent struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	EventKind string    `json:"event_kind"`
-	Actor     string    `json:"actor"`
-	Target    string    `json:"target"`
-	Payload   string    `json:"payload"`
-}
-
 // SessionPayload represents the JSON payload of a session event.
 type SessionPayload struct {
 	CostUSD   float64 `json:"cost_usd"`
@@ -347,11 +336,6 @@ type SessionPayload struct {
 	EndedAt   string  `json:"ended_at"`
 }
 
-// EventListItem represents an event from bd list (minimal fields).
-type EventListItem struct {
-	ID string `json:"id"`
-}
-
 // querySessionEvents queries beads for session.ended events and converts them to CostEntry.
 // It queries both town-level beads and all rig-level beads to find all session events.
 // Errors from individual locations are logged (if verbose) but don't fail the query.
@@ -409,51 +393,12 @@ func querySessionEvents() []CostEntry {
 
 // querySessionEventsFromLocation queries a single beads location for session.ended events.
 func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
-	// Step 1: Get list of event IDs
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
-	}
-
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = location
-	listOutput, err := listCmd.Output()
+	events, err := beads.New(location).ListEvents()
 	if err != nil {
 		// If bd fails (e.g., no beads database), return empty list
 		return nil, nil
 	}
 
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return nil, fmt.Errorf("parsing event list: %w", err)
-	}
-
-	if len(listItems) == 0 {
-		return nil, nil
-	}
-
-	// Step 2: Get full details for all events using bd show
-	// (bd list doesn't include event_kind, actor, payload)
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("bd", showArgs...)
-	showCmd.Dir = location
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing event details: %w", err)
-	}
-
 	var entries []CostEntry
 	for _, event := range events {
 		// Filter for session.ended events only
@@ -493,47 +438,11 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 // queryDigestBeads queries costs.digest events from the past N days and extracts session entries.
 func queryDigestBeads(days int) ([]CostEntry, error) {
-	// Get list of event IDs
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
-	}
-
-	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	events, err := beads.NewRouted("").ListEvents()
 	if err != nil {
 		return nil, nil
 	}
 
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return nil, fmt.Errorf("parsing event list: %w", err)
-	}
-
-	if len(listItems) == 0 {
-		return nil, nil
-	}
-
-	// Get full details for all events
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing event details: %w", err)
-	}
-
 	// Calculate date range
 	now := time.Now()
 	cutoff := now.AddDate(0, 0, -days)
@@ -781,45 +690,31 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("marshaling payload: %w", err)
 	}
 
-	// Build bd create command for ephemeral wisp
-	// Using --ephemeral creates a wisp that:
+	// Create the event as an ephemeral wisp, which:
 	// - Is stored locally only (not exported to JSONL)
 	// - Won't pollute git history with O(sessions/day) events
 	// - Will be aggregated into daily digests by 'gt costs digest'
-	bdArgs := []string{
-		"create",
-		"--ephemeral",
-		"--type=event",
-		"--title=" + title,
-		"--event-category=session.ended",
-		"--event-actor=" + agentPath,
-		"--event-payload=" + string(payloadJSON),
-		"--silent",
-	}
-
-	// Add work item as event target if specified
-	if recordWorkItem != "" {
-		bdArgs = append(bdArgs, "--event-target="+recordWorkItem)
-	}
-
+	//
 	// NOTE: We intentionally don't use --rig flag here because it causes
 	// event fields (event_kind, actor, payload) to not be stored properly.
 	// The bd command will auto-detect the correct rig from cwd.
-
-	// Execute bd create
-	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	bd := beads.NewRouted("")
+	wispID, err := bd.CreateEvent(beads.EventOptions{
+		Title:     title,
+		Category:  "session.ended",
+		Actor:     agentPath,
+		Target:    recordWorkItem,
+		Payload:   string(payloadJSON),
+		Ephemeral: true,
+	})
 	if err != nil {
-		return fmt.Errorf("creating session cost wisp: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("creating session cost wisp: %w", err)
 	}
 
-	wispID := strings.TrimSpace(string(output))
-
 	// Auto-close session cost wisps immediately after creation.
 	// These are informational records that don't need to stay open.
 	// The wisp data is preserved and queryable until digested.
-	closeCmd := exec.Command("bd", "close", wispID, "--reason=auto-closed session cost wisp")
-	if closeErr := closeCmd.Run(); closeErr != nil {
+	if closeErr := bd.CloseWithReason("auto-closed session cost wisp", wispID); closeErr != nil {
 		// Non-fatal: wisp was created, just couldn't auto-close
 		fmt.Fprintf(os.Stderr, "warning: could not auto-close session cost wisp %s: %v\n", wispID, closeErr)
 	}
@@ -944,20 +839,6 @@ type CostDigest struct {
 	ByRig        map[string]float64 `json:"by_rig,omitempty"`
 }
 
-// WispListOutput represents the JSON output from bd mol wisp list.
-type WispListOutput struct {
-	Wisps []WispItem `json:"wisps"`
-	Count int        `json:"count"`
-}
-
-// WispItem represents a single wisp from bd mol wisp list.
-type WispItem struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-}
-
 // runCostsDigest aggregates session cost wisps into a daily digest bead.
 func runCostsDigest(cmd *cobra.Command, args []string) error {
 	// Determine target date
@@ -1046,8 +927,8 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 // querySessionCostWisps queries ephemeral session.ended events for a target date.
 func querySessionCostWisps(targetDate time.Time) ([]CostEntry, error) {
 	// List all wisps including closed ones
-	listCmd := exec.Command("bd", "mol", "wisp", "list", "--all", "--json")
-	listOutput, err := listCmd.Output()
+	bd := beads.NewRouted("")
+	wisps, err := bd.ListWisps()
 	if err != nil {
 		// No wisps database or command failed
 		if costsVerbose {
@@ -1056,32 +937,21 @@ func querySessionCostWisps(targetDate time.Time) ([]CostEntry, error) {
 		return nil, nil
 	}
 
-	var wispList WispListOutput
-	if err := json.Unmarshal(listOutput, &wispList); err != nil {
-		return nil, fmt.Errorf("parsing wisp list: %w", err)
-	}
-
-	if wispList.Count == 0 {
+	if len(wisps) == 0 {
 		return nil, nil
 	}
 
 	// Batch all wisp IDs into a single bd show call to avoid N+1 queries
-	showArgs := []string{"show", "--json"}
-	for _, wisp := range wispList.Wisps {
-		showArgs = append(showArgs, wisp.ID)
+	wispIDs := make([]string, len(wisps))
+	for i, wisp := range wisps {
+		wispIDs[i] = wisp.ID
 	}
 
-	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	events, err := bd.ShowEvents(wispIDs...)
 	if err != nil {
 		return nil, fmt.Errorf("showing wisps: %w", err)
 	}
 
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return nil, fmt.Errorf("parsing wisp details: %w", err)
-	}
-
 	var sessionCostWisps []CostEntry
 	targetDay := targetDate.Format("2006-01-02")
 
@@ -1171,27 +1041,19 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 
 	// Create the digest bead (NOT ephemeral - this is permanent)
 	title := fmt.Sprintf("Cost Report %s", digest.Date)
-	bdArgs := []string{
-		"create",
-		"--type=event",
-		"--title=" + title,
-		"--event-category=costs.digest",
-		"--event-payload=" + string(payloadJSON),
-		"--description=" + desc.String(),
-		"--silent",
-	}
-
-	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	bd := beads.NewRouted("")
+	digestID, err := bd.CreateEvent(beads.EventOptions{
+		Title:       title,
+		Category:    "costs.digest",
+		Payload:     string(payloadJSON),
+		Description: desc.String(),
+	})
 	if err != nil {
-		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
+		return "", fmt.Errorf("creating digest bead: %w", err)
 	}
 
-	digestID := strings.TrimSpace(string(output))
-
 	// Auto-close the digest (it's an audit record, not work)
-	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily cost digest")
-	_ = closeCmd.Run() // Best effort
+	_ = bd.CloseWithReason("daily cost digest", digestID) // Best effort
 
 	return digestID, nil
 }
@@ -1199,8 +1061,8 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 // deleteSessionCostWisps deletes ephemeral session.ended wisps for a target date.
 func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 	// List all wisps
-	listCmd := exec.Command("bd", "mol", "wisp", "list", "--all", "--json")
-	listOutput, err := listCmd.Output()
+	bd := beads.NewRouted("")
+	wisps, err := bd.ListWisps()
 	if err != nil {
 		if costsVerbose {
 			fmt.Fprintf(os.Stderr, "[costs] wisp list failed in deletion: %v\n", err)
@@ -1208,20 +1070,14 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 		return 0, nil
 	}
 
-	var wispList WispListOutput
-	if err := json.Unmarshal(listOutput, &wispList); err != nil {
-		return 0, fmt.Errorf("parsing wisp list: %w", err)
-	}
-
 	targetDay := targetDate.Format("2006-01-02")
 
 	// Collect all wisp IDs that match our criteria
 	var wispIDsToDelete []string
 
-	for _, wisp := range wispList.Wisps {
+	for _, wisp := range wisps {
 		// Get full wisp details to check if it's a session.ended event
-		showCmd := exec.Command("bd", "show", wisp.ID, "--json")
-		showOutput, err := showCmd.Output()
+		events, err := bd.ShowEvents(wisp.ID)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] bd show failed for wisp %s: %v\n", wisp.ID, err)
@@ -1229,14 +1085,6 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 			continue
 		}
 
-		var events []SessionEvent
-		if err := json.Unmarshal(showOutput, &events); err != nil {
-			if costsVerbose {
-				fmt.Fprintf(os.Stderr, "[costs] JSON unmarshal failed for wisp %s: %v\n", wisp.ID, err)
-			}
-			continue
-		}
-
 		if len(events) == 0 {
 			continue
 		}
@@ -1279,9 +1127,7 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 	}
 
 	// Batch delete all wisps in a single subprocess call
-	burnArgs := append([]string{"mol", "burn", "--force"}, wispIDsToDelete...)
-	burnCmd := exec.Command("bd", burnArgs...)
-	if burnErr := burnCmd.Run(); burnErr != nil {
+	if burnErr := bd.Burn(wispIDsToDelete...); burnErr != nil {
 		return 0, fmt.Errorf("batch burn failed: %w", burnErr)
 	}
 
@@ -1291,50 +1137,20 @@ func deleteSessionCostWisps(targetDate time.Time) (int, error) {
 // runCostsMigrate migrates legacy session.ended beads to the new architecture.
 func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	// Query all session.ended events (both open and closed)
-	listArgs := []string{
-		"list",
-		"--type=event",
-		"--all",
-		"--limit=0",
-		"--json",
-	}
-
-	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	bd := beads.NewRouted("")
+	events, err := bd.ListEvents()
 	if err != nil {
 		fmt.Println(style.Dim.Render("No events found or bd command failed"))
 		return nil
 	}
 
-	var listItems []EventListItem
-	if err := json.Unmarshal(listOutput, &listItems); err != nil {
-		return fmt.Errorf("parsing event list: %w", err)
-	}
-
-	if len(listItems) == 0 {
+	if len(events) == 0 {
 		fmt.Println(style.Dim.Render("No events found"))
 		return nil
 	}
 
-	// Get full details for all events
-	showArgs := []string{"show", "--json"}
-	for _, item := range listItems {
-		showArgs = append(showArgs, item.ID)
-	}
-
-	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
-	if err != nil {
-		return fmt.Errorf("showing events: %w", err)
-	}
-
-	var events []SessionEvent
-	if err := json.Unmarshal(showOutput, &events); err != nil {
-		return fmt.Errorf("parsing event details: %w", err)
-	}
-
 	// Find open session.ended events
-	var openEvents []SessionEvent
+	var openEvents []*beads.Event
 	var closedCount int
 	for _, event := range events {
 		if event.EventKind != "session.ended" {
@@ -1367,8 +1183,7 @@ func runCostsMigrate(cmd *cobra.Command, args []string) error {
 	// Close all open session.ended events
 	closedMigrated := 0
 	for _, event := range openEvents {
-		closeCmd := exec.Command("bd", "close", event.ID, "--reason=migrated to wisp architecture")
-		if err := closeCmd.Run(); err != nil {
+		if err := bd.CloseWithReason("migrated to wisp architecture", event.ID); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: could not close %s: %v\n", event.ID, err)
 			continue
 		}
@@ -16,7 +16,6 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/mail"
-	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -124,15 +123,12 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 		prefix := beads.GetPrefixForRig(townRoot, r.Name)
 		agentBeadID := beads.CrewBeadIDWithPrefix(prefix, r.Name, name)
 
+		bd := beads.New(r.Path)
 		if crewPurge {
 			// --purge: DELETE the agent bead entirely (obliterate)
-			deleteArgs := []string{"delete", agentBeadID, "--force"}
-			deleteCmd := exec.Command("bd", deleteArgs...)
-			deleteCmd.Dir = r.Path
-			if output, err := deleteCmd.CombinedOutput(); err != nil {
+			if err := bd.Delete(agentBeadID); err != nil {
 				// Non-fatal: bead might not exist
-				if !strings.Contains(string(output), "no issue found") &&
-					!strings.Contains(string(output), "not found") {
+				if !errors.Is(err, beads.ErrNotFound) {
 					style.PrintWarning("could not delete agent bead %s: %v", agentBeadID, err)
 				}
 			} else {
@@ -141,19 +137,11 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 
 			// Unassign any beads assigned to this crew member
 			agentAddr := fmt.Sprintf("%s/crew/%s", r.Name, name)
-			unassignArgs := []string{"list", "--assignee=" + agentAddr, "--format=id"}
-			unassignCmd := exec.Command("bd", unassignArgs...)
-			unassignCmd.Dir = r.Path
-			if output, err := unassignCmd.CombinedOutput(); err == nil {
-				ids := strings.Fields(strings.TrimSpace(string(output)))
-				for _, id := range ids {
-					if id == "" {
-						continue
-					}
-					updateCmd := exec.Command("bd", "update", id, "--unassign")
-					updateCmd.Dir = r.Path
-					if _, err := updateCmd.CombinedOutput(); err == nil {
-						fmt.Printf("Unassigned: %s\n", id)
+			if assigned, err := bd.List(beads.ListOptions{Assignee: agentAddr, Priority: -1}); err == nil {
+				unassigned := ""
+				for _, issue := range assigned {
+					if err := bd.Update(issue.ID, beads.UpdateOptions{Assignee: &unassigned}); err == nil {
+						fmt.Printf("Unassigned: %s\n", issue.ID)
 					}
 				}
 			}
@@ -166,16 +154,9 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			}
 		} else {
 			// Default: CLOSE the agent bead (preserves CV history)
-			closeArgs := []string{"close", agentBeadID, "--reason=Crew workspace removed"}
-			if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-				closeArgs = append(closeArgs, "--session="+sessionID)
-			}
-			closeCmd := exec.Command("bd", closeArgs...)
-			closeCmd.Dir = r.Path
-			if output, err := closeCmd.CombinedOutput(); err != nil {
+			if err := bd.CloseWithReason("Crew workspace removed", agentBeadID); err != nil {
 				// Non-fatal: bead might not exist or already be closed
-				if !strings.Contains(string(output), "no issue found") &&
-					!strings.Contains(string(output), "already closed") {
+				if !errors.Is(err, beads.ErrNotFound) && !strings.Contains(err.Error(), "already closed") {
 					style.PrintWarning("could not close agent bead %s: %v", agentBeadID, err)
 				}
 			} else {
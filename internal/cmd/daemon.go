@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/readmodel"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -201,6 +202,14 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+
+		var stats readmodel.Stats
+		if err := readmodel.Query(townRoot, "stats", nil, &stats); err == nil {
+			fmt.Printf("  Read model: %d/%d databases loaded, %d issues, %d events applied\n",
+				stats.Loaded, stats.Databases, stats.Issues, stats.Events)
+		} else {
+			fmt.Printf("  Read model: %s\n", style.Dim.Render("not serving (status reads go to bd)"))
+		}
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
@@ -2,12 +2,12 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -191,6 +191,20 @@ This helps the Deacon understand which agents may need attention.`,
 	RunE: runDeaconHealthState,
 }
 
+var deaconProgressStateCmd = &cobra.Command{
+	Use:   "progress-state",
+	Short: "Show progress scores for polecats with hooked work",
+	Long: `Display the progress assessment the daemon keeps for each polecat with
+work on its hook.
+
+Each heartbeat samples several signals: pane output changes, worktree file
+edits, new commits, child-process CPU, and agent bead updates. They combine
+into a score from 0 (no progress for a full window) to 1 (just progressed).
+Low scores and repeated identical output trigger a nudge; no progress for
+the whole GUPP window escalates to the rig's Witness.`,
+	RunE: runDeaconProgressState,
+}
+
 var deaconStaleHooksCmd = &cobra.Command{
 	Use:   "stale-hooks",
 	Short: "Find and unhook stale hooked beads",
@@ -267,6 +281,7 @@ func init() {
 	deaconCmd.AddCommand(deaconHealthCheckCmd)
 	deaconCmd.AddCommand(deaconForceKillCmd)
 	deaconCmd.AddCommand(deaconHealthStateCmd)
+	deaconCmd.AddCommand(deaconProgressStateCmd)
 	deaconCmd.AddCommand(deaconStaleHooksCmd)
 	deaconCmd.AddCommand(deaconPauseCmd)
 	deaconCmd.AddCommand(deaconResumeCmd)
@@ -892,6 +907,49 @@ func runDeaconHealthState(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runDeaconProgressState(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	state, err := deacon.LoadProgressState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading progress state: %w", err)
+	}
+
+	if len(state.Agents) == 0 {
+		fmt.Printf("%s No progress state recorded yet\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Progress State (updated %s)\n\n",
+		style.Bold.Render("●"),
+		state.LastUpdated.Format(time.RFC3339))
+
+	ids := make([]string, 0, len(state.Agents))
+	for id := range state.Agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		p := state.Agents[id]
+		fmt.Printf("Agent: %s\n", style.Bold.Render(id))
+		fmt.Printf("  Score: %.2f (%s)\n", p.Score, p.Action)
+		fmt.Printf("  Reason: %s\n", p.Reason)
+		if !p.LastNudge.IsZero() {
+			fmt.Printf("  Last nudge: %s ago\n", time.Since(p.LastNudge).Round(time.Second))
+		}
+		if !p.LastEscalation.IsZero() {
+			fmt.Printf("  Last escalation: %s ago\n", time.Since(p.LastEscalation).Round(time.Second))
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
 // agentAddressToIDs converts an agent address to bead ID and session name.
 // Supports formats: "gastown/polecats/max", "gastown/witness", "deacon", "mayor"
 // Note: Town-level agents (Mayor, Deacon) use hq- prefix bead IDs stored in town beads.
@@ -934,26 +992,11 @@ func agentAddressToIDs(address string) (beadID, sessionName string, err error) {
 
 // getAgentBeadUpdateTime gets the update time from an agent bead.
 func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
-	cmd := exec.Command("bd", "show", beadID, "--json")
-	cmd.Dir = townRoot
-
-	output, err := cmd.Output()
+	issue, err := beads.New(townRoot).Show(beadID)
 	if err != nil {
 		return time.Time{}, err
 	}
-
-	var issues []struct {
-		UpdatedAt string `json:"updated_at"`
-	}
-	if err := json.Unmarshal(output, &issues); err != nil {
-		return time.Time{}, err
-	}
-
-	if len(issues) == 0 {
-		return time.Time{}, fmt.Errorf("bead not found: %s", beadID)
-	}
-
-	return time.Parse(time.RFC3339, issues[0].UpdatedAt)
+	return time.Parse(time.RFC3339, issue.UpdatedAt)
 }
 
 // sendMail sends a mail message using gt mail send.
@@ -970,10 +1013,7 @@ func updateAgentBeadState(townRoot, agent, state, _ string) { // reason unused b
 		return
 	}
 
-	// Use bd agent state command
-	cmd := exec.Command("bd", "agent", "state", beadID, state)
-	cmd.Dir = townRoot
-	_ = cmd.Run() // Best effort
+	_ = beads.New(townRoot).UpdateAgentState(beadID, state, nil) // Best effort
 }
 
 // runDeaconStaleHooks finds and unhooks stale hooked beads.
@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/doctor"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -14,6 +23,17 @@ var (
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
+	doctorJSON            bool
+	doctorJUnit           string
+	doctorJobs            int
+	doctorTimeout         time.Duration
+	doctorList            bool
+	doctorDryRun          bool
+	doctorRollbackForce   bool
+	doctorRollbackDryRun  bool
+	doctorChecks          []string
+	doctorHistoryJSON     bool
+	doctorHistoryLimit    int
 )
 
 var doctorCmd = &cobra.Command{
@@ -81,15 +101,81 @@ Patrol checks:
   - patrol-roles-have-prompts Verify role prompts exist
 
 Use --fix to attempt automatic fixes for issues that support it.
-Use --rig to check a specific rig instead of the entire workspace.`,
+Use --fix --dry-run to print the changes as a diff without applying them.
+Fixes are journaled under .runtime/doctor/journal/; undo a run's file and
+git config changes with 'gt doctor rollback <run-id>'. Only fixes written
+as plans can be previewed and rolled back. The others (most that create
+beads, run bd, git or gt, or restart sessions) are applied directly, and
+--fix lists them before it starts.
+Use --rig to check a specific rig instead of the entire workspace.
+Use --check to run only some checks (rig checks also need --rig).
+
+Checks run concurrently (--jobs) with a per-check --timeout. Checks that
+depend on a failing check (e.g., beads checks when the beads database is
+broken) are skipped rather than reported as further failures.
+
+External checks:
+  Executables in doctor.d/ directories (town root, rigs, and town or rig
+  plugins) are run as additional checks. They receive a JSON request on
+  stdin and print a JSON result (see "External Doctor Checks" in
+  docs/reference.md). Use --list to see every check that would run.
+
+For CI, --json prints a machine-readable report and --junit writes JUnit
+XML (use - for stdout). The exit status is non-zero when any check errors.`,
 	RunE: runDoctor,
 }
 
+var doctorRollbackCmd = &cobra.Command{
+	Use:   "rollback [run-id]",
+	Short: "Undo the changes made by a 'gt doctor --fix' run",
+	Long: `Undo the changes made by a 'gt doctor --fix' run.
+
+Each fix run is journaled with the prior contents of every file it wrote or
+deleted and every git config key it set. Rollback restores them in reverse
+order. Files edited since the fix are left alone unless --force is given.
+Bead updates, session restarts and fixes applied without a plan can't be
+undone and are reported.
+
+Without a run ID, lists recent fix runs.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctorRollback,
+}
+
+var doctorHistoryCmd = &cobra.Command{
+	Use:   "history [check]",
+	Short: "Show when doctor checks last changed state",
+	Long: `Show when doctor checks last changed state.
+
+When enabled under "doctor" in mayor/daemon.json, the daemon runs a set of
+cheap checks on an interval and records each result. Without a check,
+shows every recorded check's current status and when it entered it. With a
+check, lists that check's state changes, newest first. Rig checks are named
+<rig>/<check>.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctorHistory,
+}
+
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output report as JSON")
+	doctorCmd.Flags().StringVar(&doctorJUnit, "junit", "", "Write report as JUnit XML to file (- for stdout)")
+	doctorCmd.Flags().IntVarP(&doctorJobs, "jobs", "j", doctor.DefaultWorkers, "Number of checks to run concurrently")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", doctor.DefaultCheckTimeout, "Per-check timeout (0 for none)")
+	doctorCmd.Flags().BoolVar(&doctorList, "list", false, "List registered checks (including doctor.d checks) without running them")
+	doctorCmd.Flags().BoolVar(&doctorDryRun, "dry-run", false, "Show the changes --fix would make as a diff without applying them")
+	doctorCmd.Flags().StringSliceVar(&doctorChecks, "check", nil, "Run only the named checks (comma-separated or repeated)")
+
+	doctorRollbackCmd.Flags().BoolVar(&doctorRollbackForce, "force", false, "Restore files even if they were modified after the fix")
+	doctorRollbackCmd.Flags().BoolVar(&doctorRollbackDryRun, "dry-run", false, "Show what would be restored without changing anything")
+
+	doctorHistoryCmd.Flags().BoolVar(&doctorHistoryJSON, "json", false, "Output as JSON")
+	doctorHistoryCmd.Flags().IntVarP(&doctorHistoryLimit, "limit", "n", 20, "Maximum state changes to show for a check (0 for all)")
+
+	doctorCmd.AddCommand(doctorRollbackCmd)
+	doctorCmd.AddCommand(doctorHistoryCmd)
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -110,6 +196,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Create doctor and register checks
 	d := doctor.NewDoctor()
+	d.SetWorkers(doctorJobs)
+	d.SetTimeout(doctorTimeout)
 
 	// Register workspace-level checks first (fundamental)
 	d.RegisterAll(doctor.WorkspaceChecks()...)
@@ -185,16 +273,57 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
 
+	// External checks from doctor.d directories
+	d.RegisterAll(doctor.DiscoverExternalChecks(townRoot, doctorExternalRigs(townRoot), d.Checks())...)
+
+	if len(doctorChecks) > 0 {
+		if err := d.Select(doctorChecks); err != nil {
+			return err
+		}
+	}
+
+	if doctorList {
+		return listDoctorChecks(d)
+	}
+
+	if doctorDryRun && !doctorFix {
+		return fmt.Errorf("--dry-run requires --fix")
+	}
+	if doctorJSON && doctorJUnit == "-" {
+		return fmt.Errorf("--json and --junit - both write to stdout; give --junit a file")
+	}
+
 	// Run checks
 	var report *doctor.Report
+	var journal *doctor.Journal
 	if doctorFix {
+		if unplanned := d.Unplanned(); len(unplanned) > 0 {
+			fmt.Fprintf(os.Stderr, "%s %d check(s) fix without a plan; their changes can't be previewed with --dry-run or undone with 'gt doctor rollback': %s\n",
+				style.Warning.Render("⚠"), len(unplanned), strings.Join(unplanned, ", "))
+		}
+		if doctorDryRun {
+			d.SetDryRun(true)
+		} else {
+			journal = doctor.NewJournal(townRoot)
+			d.SetJournal(journal)
+		}
 		report = d.Fix(ctx)
 	} else {
 		report = d.Run(ctx)
 	}
 
 	// Print report
-	report.Print(os.Stdout, doctorVerbose)
+	if err := writeDoctorReport(report); err != nil {
+		return err
+	}
+	if doctorDryRun && !doctorJSON && doctorJUnit != "-" {
+		if err := printDoctorPlans(report, townRoot); err != nil {
+			return err
+		}
+	}
+	if !journal.Empty() && !doctorJSON && doctorJUnit != "-" {
+		fmt.Printf("\nFixes journaled as run %s. Undo with: gt doctor rollback %s\n", journal.RunID, journal.RunID)
+	}
 
 	// Exit with error code if there are errors
 	if report.HasErrors() {
@@ -203,3 +332,266 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// writeDoctorReport outputs the report in the requested formats. JUnit
+// written to a file can accompany either of the stdout formats; JUnit on
+// stdout can't be combined with --json (rejected before checks run).
+func writeDoctorReport(report *doctor.Report) error {
+	if doctorJUnit == "-" {
+		return report.WriteJUnit(os.Stdout)
+	}
+	if doctorJUnit != "" {
+		f, err := os.Create(doctorJUnit)
+		if err != nil {
+			return fmt.Errorf("creating JUnit report: %w", err)
+		}
+		if err := report.WriteJUnit(f); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("writing JUnit report: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing JUnit report: %w", err)
+		}
+	}
+
+	if doctorJSON {
+		return report.WriteJSON(os.Stdout)
+	}
+	report.Print(os.Stdout, doctorVerbose)
+	return nil
+}
+
+// printDoctorPlans prints the fixes a --dry-run would have applied.
+func printDoctorPlans(report *doctor.Report, townRoot string) error {
+	printed := false
+	for _, result := range report.Checks {
+		if result.Plan == nil {
+			continue
+		}
+		if !printed {
+			fmt.Printf("\n%s\n", style.Bold.Render("Planned fixes (dry run, nothing changed):"))
+			printed = true
+		}
+		fmt.Printf("\n%s\n", style.Bold.Render("== "+result.Name))
+		if result.Plan.Empty() && len(result.Plan.Notes) == 0 {
+			fmt.Println("# no changes")
+			continue
+		}
+		if err := result.Plan.Diff(os.Stdout, townRoot); err != nil {
+			return fmt.Errorf("diffing %s fix: %w", result.Name, err)
+		}
+	}
+	if !printed {
+		fmt.Println("\nNo fixes to apply.")
+	}
+	return nil
+}
+
+// runDoctorRollback undoes a journaled fix run, or lists runs.
+func runDoctorRollback(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 0 {
+		journals, err := doctor.ListJournals(townRoot)
+		if err != nil {
+			return fmt.Errorf("listing fix runs: %w", err)
+		}
+		if len(journals) == 0 {
+			fmt.Println("No journaled fix runs.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "RUN\tSTARTED\tCHANGES\tSTATUS")
+		for _, j := range journals {
+			status := ""
+			switch {
+			case j.RolledBackAt != nil:
+				status = "rolled back"
+			case j.Poisoned != "":
+				status = "incomplete: " + j.Poisoned
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", j.RunID, j.StartedAt.Local().Format("2006-01-02 15:04:05"), len(j.Entries), status)
+		}
+		return w.Flush()
+	}
+
+	journal, err := doctor.LoadJournal(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	result, err := journal.Rollback(doctorRollbackForce, doctorRollbackDryRun)
+	if result != nil {
+		verb := "Restored"
+		if doctorRollbackDryRun {
+			verb = "Would restore"
+		}
+		for _, r := range result.Restored {
+			fmt.Printf("  %s %s: %s\n", style.Success.Render("✓"), verb, r)
+		}
+		for _, s := range result.Skipped {
+			fmt.Printf("  %s Skipped: %s\n", style.Warning.Render("⚠"), s)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !doctorRollbackDryRun {
+		fmt.Printf("Rolled back run %s\n", journal.RunID)
+	}
+	return nil
+}
+
+// runDoctorHistory shows the continuous doctor's recorded check states.
+func runDoctorHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if len(args) == 1 {
+		return showCheckHistory(townRoot, args[0])
+	}
+
+	state, err := health.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(state.Checks))
+	for name := range state.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if doctorHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+	if len(names) == 0 {
+		fmt.Println("No health history yet. The daemon records it while running (see 'gt daemon status').")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CHECK\tSTATUS\tSINCE\tLAST RUN\tMESSAGE")
+	for _, name := range names {
+		cs := state.Checks[name]
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, cs.Status,
+			formatHealthTime(cs.Since), formatHealthTime(cs.LastRun), cs.Message)
+	}
+	return w.Flush()
+}
+
+// showCheckHistory lists one check's state changes, newest first.
+func showCheckHistory(townRoot, check string) error {
+	samples, err := health.LoadHistory(townRoot, check)
+	if err != nil {
+		return err
+	}
+	transitions := health.Transitions(samples)
+	for i, k := 0, len(transitions)-1; i < k; i, k = i+1, k-1 {
+		transitions[i], transitions[k] = transitions[k], transitions[i]
+	}
+	if doctorHistoryLimit > 0 && len(transitions) > doctorHistoryLimit {
+		transitions = transitions[:doctorHistoryLimit]
+	}
+
+	if doctorHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(transitions)
+	}
+	if len(transitions) == 0 {
+		fmt.Printf("No recorded results for %s.\n", check)
+		return nil
+	}
+
+	fmt.Printf("%s: %d samples, last %s\n\n", style.Bold.Render(check), len(samples),
+		formatHealthTime(samples[len(samples)-1].Time))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CHANGED\tFROM\tTO\tMESSAGE")
+	for _, t := range transitions {
+		from := t.From
+		if from == "" {
+			from = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", formatHealthTime(t.At), from, t.To, t.Message)
+	}
+	return w.Flush()
+}
+
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// doctorExternalRigs returns the rigs whose doctor.d checks should run:
+// just --rig if given, otherwise every registered rig.
+func doctorExternalRigs(townRoot string) []string {
+	if doctorRig != "" {
+		return []string{doctorRig}
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	rigs := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		rigs = append(rigs, name)
+	}
+	sort.Strings(rigs)
+	return rigs
+}
+
+// doctorCheckInfo describes a registered check for --list.
+type doctorCheckInfo struct {
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	Description string `json:"description"`
+	Fixable     bool   `json:"fixable"`
+	Source      string `json:"source"` // "builtin" or the external executable
+}
+
+// listDoctorChecks prints the registered checks instead of running them.
+func listDoctorChecks(d *doctor.Doctor) error {
+	var infos []doctorCheckInfo
+	for _, check := range d.Checks() {
+		info := doctorCheckInfo{
+			Name:        check.Name(),
+			Category:    doctor.CategoryOf(check),
+			Description: check.Description(),
+			Fixable:     check.CanFix(),
+			Source:      "builtin",
+		}
+		if ext, ok := check.(*doctor.ExternalCheck); ok {
+			info.Source = ext.Source()
+		}
+		infos = append(infos, info)
+	}
+
+	if doctorJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tCATEGORY\tFIX\tDESCRIPTION")
+	for _, info := range infos {
+		fix := ""
+		if info.Fixable {
+			fix = "yes"
+		}
+		desc := info.Description
+		if info.Source != "builtin" {
+			desc += " [" + info.Source + "]"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Name, info.Category, fix, desc)
+	}
+	return w.Flush()
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -80,7 +82,7 @@ func init() {
 	rootCmd.AddCommand(doneCmd)
 }
 
-func runDone(cmd *cobra.Command, args []string) error {
+func runDone(cmd *cobra.Command, args []string) (err error) {
 	// Handle --phase-complete flag (overrides --status)
 	var exitType string
 	if donePhaseComplete {
@@ -195,6 +197,13 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 	worker := info.Worker
 
+	// Continue the trace the sling started for this work
+	span := telemetry.StartRemote(doneTraceParent(cwd, issueID), "polecat.done",
+		telemetry.String("gt.bead", issueID), telemetry.String("gt.exit", exitType),
+		telemetry.String("gt.branch", branch))
+	defer func() { span.End(err) }()
+	traceCtx := telemetry.ContextWithSpan(context.Background(), span)
+
 	// Determine polecat name from sender detection
 	sender := detectSender()
 	polecatName := ""
@@ -279,7 +288,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 		}
 
 		// Initialize beads
-		bd := beads.New(beads.ResolveBeadsDir(cwd))
+		bd := beads.New(beads.ResolveBeadsDir(cwd)).WithContext(traceCtx)
 
 		// Determine target branch (auto-detect integration branch if applicable)
 		target := defaultBranch
@@ -331,7 +340,14 @@ func runDone(cmd *cobra.Command, args []string) error {
 			description += "\nlast_conflict_sha: null"
 			description += "\nconflict_task_id: null"
 
+			// The refinery's gate and merge spans join this trace
+			if tp := span.TraceParent(); tp != "" {
+				description += "\ntrace_parent: " + tp
+			}
+
 			// Create MR bead (ephemeral wisp - will be cleaned up after merge)
+			submitSpan := telemetry.StartRemote(span.TraceParent(), "mr.submit",
+				telemetry.String("gt.branch", branch), telemetry.String("gt.target", target))
 			mrIssue, err := bd.Create(beads.CreateOptions{
 				Title:       title,
 				Type:        "merge-request",
@@ -339,10 +355,12 @@ func runDone(cmd *cobra.Command, args []string) error {
 				Description: description,
 				Ephemeral:   true,
 			})
+			submitSpan.End(err)
 			if err != nil {
 				return fmt.Errorf("creating merge request bead: %w", err)
 			}
 			mrID = mrIssue.ID
+			span.SetAttributes(telemetry.String("gt.mr", mrID))
 
 			// Update agent bead with active_mr reference (for traceability)
 			if agentBeadID != "" {
@@ -376,7 +394,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s\n", style.Dim.Render("Witness will dispatch new polecat when gate closes."))
 
 		// Register this polecat as a waiter on the gate
-		bd := beads.New(beads.ResolveBeadsDir(cwd))
+		bd := beads.New(beads.ResolveBeadsDir(cwd)).WithContext(traceCtx)
 		if err := bd.AddGateWaiter(doneGate, sender); err != nil {
 			style.PrintWarning("could not register as gate waiter: %v", err)
 		} else {
@@ -448,6 +466,10 @@ func runDone(cmd *cobra.Command, args []string) error {
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
 
+	// End and export the span now: self-cleaning kills this process
+	span.End(nil)
+	telemetry.Flush()
+
 	// Self-cleaning: Nuke our own sandbox and session (if we're a polecat)
 	// This is the self-cleaning model - polecats clean up after themselves
 	// "done means gone" - both worktree and session are terminated
@@ -617,6 +639,19 @@ func updateAgentStateOnDone(cwd, townRoot, exitType, _ string) { // issueID unus
 	}
 }
 
+// doneTraceParent returns the traceparent of the sling that dispatched the
+// issue, so gt done's spans join its trace. Skips the lookup when tracing is off.
+func doneTraceParent(cwd, issueID string) string {
+	if issueID == "" || !telemetry.Enabled() {
+		return os.Getenv(telemetry.ParentEnv)
+	}
+	issue, err := beads.New(beads.ResolveBeadsDir(cwd)).Show(issueID)
+	if err != nil {
+		return os.Getenv(telemetry.ParentEnv)
+	}
+	return workTraceParent(issue)
+}
+
 // getDispatcherFromBead retrieves the dispatcher agent ID from the bead's attachment fields.
 // Returns empty string if no dispatcher is recorded.
 func getDispatcherFromBead(cwd, issueID string) string {
@@ -5,10 +5,10 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/tui/feed"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -179,20 +179,10 @@ func buildFeedArgs() []string {
 
 // runFeedDirect runs bd activity in the current terminal.
 func runFeedDirect(workDir string, bdArgs []string) error {
-	bdPath, err := exec.LookPath("bd")
-	if err != nil {
-		return fmt.Errorf("bd not found in PATH: %w", err)
-	}
-
-	// Prepend argv[0] for exec
-	fullArgs := append([]string{"bd", "activity"}, bdArgs...)
-
-	// Change to the target directory before exec
-	if err := os.Chdir(workDir); err != nil {
-		return fmt.Errorf("changing to directory %s: %w", workDir, err)
-	}
-
-	return syscall.Exec(bdPath, fullArgs, os.Environ())
+	cmd := beads.Command(append([]string{"activity"}, bdArgs...)...)
+	cmd.Dir = workDir
+	cmd.BeadsDir = beads.ResolveBeadsDir(workDir)
+	return cmd.Exec()
 }
 
 // runFeedTUI runs the interactive TUI feed.
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -174,7 +175,7 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 		bdArgs = append(bdArgs, "--json")
 	}
 
-	bdCmd := exec.Command("bd", bdArgs...)
+	bdCmd := beads.Command(bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 	return bdCmd.Run()
@@ -188,7 +189,7 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 		bdArgs = append(bdArgs, "--json")
 	}
 
-	bdCmd := exec.Command("bd", bdArgs...)
+	bdCmd := beads.Command(bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 	return bdCmd.Run()
@@ -312,7 +313,7 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 	if err != nil {
 		return fmt.Errorf("finding town root: %w", err)
 	}
-	townBeads := filepath.Join(townRoot, ".beads")
+	bd := townBeadsClient(filepath.Join(townRoot, ".beads"))
 
 	// Step 1: Create convoy bead
 	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
@@ -328,18 +329,12 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
 	}
 
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + convoyTitle,
-		"--description=" + description,
-	}
-
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
+	if _, err := bd.CreateWithID(convoyID, beads.CreateOptions{
+		Title:       convoyTitle,
+		IssueType:   "convoy",
+		Priority:    -1,
+		Description: description,
+	}); err != nil {
 		return fmt.Errorf("creating convoy bead: %w", err)
 	}
 
@@ -358,28 +353,19 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 			}
 		}
 
-		legArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + legBeadID,
-			"--title=" + leg.Title,
-			"--description=" + legDesc,
-		}
-
-		legCmd := exec.Command("bd", legArgs...)
-		legCmd.Dir = townBeads
-		legCmd.Stderr = os.Stderr
-		if err := legCmd.Run(); err != nil {
+		if _, err := bd.CreateWithID(legBeadID, beads.CreateOptions{
+			Title:       leg.Title,
+			IssueType:   "task",
+			Priority:    -1,
+			Description: legDesc,
+		}); err != nil {
 			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 			continue
 		}
 
 		// Track the leg with the convoy
-		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
-		trackCmd := exec.Command("bd", trackArgs...)
-		trackCmd.Dir = townBeads
-		if err := trackCmd.Run(); err != nil {
+		if err := bd.AddDependencyWithType(convoyID, legBeadID, "tracks"); err != nil {
 			fmt.Printf("%s Failed to track leg %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 		}
@@ -398,33 +384,21 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 			synDesc = "Synthesize findings from all legs into unified output"
 		}
 
-		synArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + synthesisBeadID,
-			"--title=" + f.Synthesis.Title,
-			"--description=" + synDesc,
-		}
-
-		synCmd := exec.Command("bd", synArgs...)
-		synCmd.Dir = townBeads
-		synCmd.Stderr = os.Stderr
-		if err := synCmd.Run(); err != nil {
+		if _, err := bd.CreateWithID(synthesisBeadID, beads.CreateOptions{
+			Title:       f.Synthesis.Title,
+			IssueType:   "task",
+			Priority:    -1,
+			Description: synDesc,
+		}); err != nil {
 			fmt.Printf("%s Failed to create synthesis bead: %v\n",
 				style.Dim.Render("Warning:"), err)
 		} else {
 			// Track synthesis with convoy
-			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
-			trackCmd := exec.Command("bd", trackArgs...)
-			trackCmd.Dir = townBeads
-			_ = trackCmd.Run()
+			_ = bd.AddDependencyWithType(convoyID, synthesisBeadID, "tracks")
 
 			// Add dependencies: synthesis depends on all legs
 			for _, legBeadID := range legBeads {
-				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
-				depCmd := exec.Command("bd", depArgs...)
-				depCmd.Dir = townBeads
-				_ = depCmd.Run()
+				_ = bd.AddDependency(synthesisBeadID, legBeadID)
 			}
 
 			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
@@ -459,10 +433,7 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 			fmt.Printf("%s Failed to sling leg %s: %v\n",
 				style.Dim.Render("Warning:"), leg.ID, err)
 			// Add comment to bead about failure
-			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
-			commentCmd := exec.Command("bd", commentArgs...)
-			commentCmd.Dir = townBeads
-			_ = commentCmd.Run()
+			_ = bd.AddComment(legBeadID, fmt.Sprintf("Failed to sling: %v", err))
 			continue
 		}
 
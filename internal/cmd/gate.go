@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -85,22 +85,11 @@ func runGateWake(cmd *cobra.Command, args []string) error {
 	gateID := args[0]
 
 	// Get gate info
-	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gateInfo, err := beads.NewRouted("").ShowGate(gateID)
 	if err != nil {
 		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
 	}
 
-	var gateInfo struct {
-		ID          string   `json:"id"`
-		Status      string   `json:"status"`
-		CloseReason string   `json:"close_reason"`
-		Waiters     []string `json:"waiters"`
-	}
-	if err := json.Unmarshal(gateOutput, &gateInfo); err != nil {
-		return fmt.Errorf("parsing gate info: %w", err)
-	}
-
 	if gateInfo.Status != "closed" {
 		return fmt.Errorf("gate '%s' is not closed (status: %s) - wake mail only sent for closed gates", gateID, gateInfo.Status)
 	}
@@ -5,9 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
@@ -349,6 +351,14 @@ var claudeEnvVars = []string{
 // This needs to be the actual command to execute (e.g., claude), not a session attach command.
 // The command includes a cd to the correct working directory for the role.
 func buildRestartCommand(sessionName string) (string, error) {
+	return buildRestartCommandWithEnv(sessionName, "", "handoff", nil)
+}
+
+// buildRestartCommandWithEnv is buildRestartCommand with a startup topic and
+// extra environment exports (e.g., CLAUDE_CONFIG_DIR for account rotation).
+// rigPath selects the rig whose runtime is started; "" resolves it from the
+// current directory.
+func buildRestartCommandWithEnv(sessionName, rigPath, topic string, extraEnv map[string]string) (string, error) {
 	// Detect town root from current directory
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
@@ -374,7 +384,7 @@ func buildRestartCommand(sessionName string) (string, error) {
 	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
 		Recipient: identity.Address(),
 		Sender:    "self",
-		Topic:     "handoff",
+		Topic:     topic,
 	})
 
 	// For respawn-pane, we:
@@ -383,12 +393,12 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// 3. export Claude-related env vars (not inherited by fresh shell)
 	// 4. run claude with the startup beacon (triggers immediate context loading)
 	// Use exec to ensure clean process replacement.
-	runtimeCmd := config.GetRuntimeCommandWithPrompt("", beacon)
+	runtimeCmd := config.GetRuntimeCommandWithPrompt(rigPath, beacon)
 
 	// Build environment exports - role vars first, then Claude vars
 	var exports []string
 	if gtRole != "" {
-		runtimeConfig := config.LoadRuntimeConfig("")
+		runtimeConfig := config.LoadRuntimeConfig(rigPath)
 		exports = append(exports, "GT_ROLE="+gtRole)
 		exports = append(exports, "BD_ACTOR="+gtRole)
 		exports = append(exports, "GIT_AUTHOR_NAME="+gtRole)
@@ -404,6 +414,14 @@ func buildRestartCommand(sessionName string) (string, error) {
 			exports = append(exports, fmt.Sprintf("%s=%q", name, val))
 		}
 	}
+	extraNames := make([]string, 0, len(extraEnv))
+	for name := range extraEnv {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+	for _, name := range extraNames {
+		exports = append(exports, fmt.Sprintf("%s=%q", name, extraEnv[name]))
+	}
 
 	if len(exports) > 0 {
 		return fmt.Sprintf("cd %s && export %s && exec %s", workDir, strings.Join(exports, " "), runtimeCmd), nil
@@ -583,51 +601,28 @@ func sendHandoffMail(subject, message string) (string, error) {
 		return "", fmt.Errorf("cannot detect town root")
 	}
 
-	// Build labels for mail metadata (matches mail router format)
-	labels := fmt.Sprintf("from:%s", agentID)
-
-	// Create mail bead directly using bd create with --silent to get the ID
-	// Mail goes to town-level beads (hq- prefix)
-	args := []string{
-		"create", subject,
-		"--type", "message",
-		"--assignee", agentID,
-		"-d", message,
-		"--priority", "2",
-		"--labels", labels,
-		"--actor", agentID,
-		"--ephemeral", // Handoff mail is ephemeral
-		"--silent",    // Output only the bead ID
-	}
-
-	cmd := exec.Command("bd", args...)
-	cmd.Dir = townRoot // Run from town root for town-level beads
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+filepath.Join(townRoot, ".beads"))
-
-	var stdout, stderr strings.Builder
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return "", fmt.Errorf("creating handoff mail: %s", errMsg)
-		}
+	// Create mail bead directly in town-level beads (hq- prefix)
+	bd := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads"))
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       subject,
+		IssueType:   "message",
+		Assignee:    agentID,
+		Description: message,
+		Priority:    2,
+		Labels:      []string{"from:" + agentID}, // Matches mail router format
+		Actor:       agentID,
+		Ephemeral:   true, // Handoff mail is ephemeral
+	})
+	if err != nil {
 		return "", fmt.Errorf("creating handoff mail: %w", err)
 	}
-
-	beadID := strings.TrimSpace(stdout.String())
+	beadID := issue.ID
 	if beadID == "" {
 		return "", fmt.Errorf("bd create did not return bead ID")
 	}
 
 	// Auto-hook the created mail bead
-	hookCmd := exec.Command("bd", "update", beadID, "--status=hooked", "--assignee="+agentID)
-	hookCmd.Dir = townRoot
-	hookCmd.Env = append(os.Environ(), "BEADS_DIR="+filepath.Join(townRoot, ".beads"))
-	hookCmd.Stderr = os.Stderr
-
-	if err := hookCmd.Run(); err != nil {
+	if err := bd.Hook(beadID, agentID); err != nil {
 		// Non-fatal: mail was created, just couldn't hook
 		style.PrintWarning("created mail %s but failed to auto-hook: %v", beadID, err)
 		return beadID, nil
@@ -636,6 +631,20 @@ func sendHandoffMail(subject, message string) (string, error) {
 	return beadID, nil
 }
 
+// issueSummaryLines lists up to limit issues one per line, ending with
+// more when some were left out.
+func issueSummaryLines(issues []*beads.Issue, limit int, more string) string {
+	var lines []string
+	for i, issue := range issues {
+		if i == limit {
+			lines = append(lines, more)
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s [P%d] %s", issue.ID, issue.Priority, issue.Title))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // looksLikeBeadID checks if a string looks like a bead ID.
 // Bead IDs have format: prefix-xxxx where prefix is 1-5 lowercase letters and xxxx is alphanumeric.
 // Examples: "gt-abc123", "bd-ka761", "hq-cv-abc", "beads-xyz", "ap-qtsup.16"
@@ -673,8 +682,8 @@ func looksLikeBeadID(s string) bool {
 // hookBeadForHandoff attaches a bead to the current agent's hook.
 func hookBeadForHandoff(beadID string) error {
 	// Verify the bead exists first
-	verifyCmd := exec.Command("bd", "show", beadID, "--json")
-	if err := verifyCmd.Run(); err != nil {
+	bd := beads.NewRouted("")
+	if _, err := bd.Show(beadID); err != nil {
 		return fmt.Errorf("bead '%s' not found", beadID)
 	}
 
@@ -692,9 +701,7 @@ func hookBeadForHandoff(beadID string) error {
 	}
 
 	// Pin the bead using bd update (discovery-based approach)
-	pinCmd := exec.Command("bd", "update", beadID, "--status=pinned", "--assignee="+agentID)
-	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := bd.Pin(beadID, agentID); err != nil {
 		return fmt.Errorf("pinning bead: %w", err)
 	}
 
@@ -730,31 +737,16 @@ func collectHandoffState() string {
 		}
 	}
 
-	// Get ready beads
-	readyOutput, err := exec.Command("bd", "ready").Output()
-	if err == nil {
-		readyStr := strings.TrimSpace(string(readyOutput))
-		if readyStr != "" && !strings.Contains(readyStr, "No issues ready") {
-			// Limit to first 10 lines
-			lines := strings.Split(readyStr, "\n")
-			if len(lines) > 10 {
-				lines = append(lines[:10], "... (more issues)")
-			}
-			parts = append(parts, "## Ready Work\n"+strings.Join(lines, "\n"))
-		}
+	bd := beads.NewRouted("")
+
+	// Get ready beads (first 10)
+	if ready, err := bd.Ready(); err == nil && len(ready) > 0 {
+		parts = append(parts, "## Ready Work\n"+issueSummaryLines(ready, 10, "... (more issues)"))
 	}
 
-	// Get in-progress beads
-	inProgressOutput, err := exec.Command("bd", "list", "--status=in_progress").Output()
-	if err == nil {
-		ipStr := strings.TrimSpace(string(inProgressOutput))
-		if ipStr != "" && !strings.Contains(ipStr, "No issues") {
-			lines := strings.Split(ipStr, "\n")
-			if len(lines) > 5 {
-				lines = append(lines[:5], "... (more)")
-			}
-			parts = append(parts, "## In Progress\n"+strings.Join(lines, "\n"))
-		}
+	// Get in-progress beads (first 5)
+	if inProgress, err := bd.List(beads.ListOptions{Status: "in_progress", Priority: -1}); err == nil && len(inProgress) > 0 {
+		parts = append(parts, "## In Progress\n"+issueSummaryLines(inProgress, 5, "... (more)"))
 	}
 
 	if len(parts) == 0 {
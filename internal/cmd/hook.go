@@ -10,7 +10,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
-	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -248,15 +247,8 @@ func runHook(_ *cobra.Command, args []string) error {
 			}
 			if !hookDryRun {
 				if hasAttachment {
-					// Close completed molecule bead (use bd close --force for pinned)
-					closeArgs := []string{"close", existing.ID, "--force",
-						"--reason=Auto-replaced by gt hook (molecule complete)"}
-					if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-						closeArgs = append(closeArgs, "--session="+sessionID)
-					}
-					closeCmd := exec.Command("bd", closeArgs...)
-					closeCmd.Stderr = os.Stderr
-					if err := closeCmd.Run(); err != nil {
+					// Close completed molecule bead (forced, since it is pinned)
+					if err := beads.NewRouted("").ForceCloseWithReason("Auto-replaced by gt hook (molecule complete)", existing.ID); err != nil {
 						return fmt.Errorf("closing completed bead %s: %w", existing.ID, err)
 					}
 				} else {
@@ -316,9 +308,7 @@ func runHook(_ *cobra.Command, args []string) error {
 // doHook performs the actual hook operation and logs the event.
 // It uses the bd CLI for discovery-based bead routing.
 func doHook(beadID, agentID string) error {
-	hookCmd := exec.Command("bd", "update", beadID, "--status=hooked", "--assignee="+agentID)
-	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	if err := beads.NewRouted("").Hook(beadID, agentID); err != nil {
 		return fmt.Errorf("hooking bead: %w", err)
 	}
 
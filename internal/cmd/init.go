@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -156,17 +157,13 @@ func registerCustomTypes(workDir string) error {
 	}
 
 	// Try to set custom types
-	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	if err := beads.New(workDir).ConfigSet("types.custom", constants.BeadsCustomTypes); err != nil {
 		// Check for common expected errors
-		outStr := string(output)
-		if strings.Contains(outStr, "not initialized") ||
-			strings.Contains(outStr, "no such file") {
+		if strings.Contains(err.Error(), "not initialized") ||
+			strings.Contains(err.Error(), "no such file") {
 			return nil // DB not initialized, skip silently
 		}
-		return fmt.Errorf("%s", strings.TrimSpace(outStr))
+		return err
 	}
 	return nil
 }
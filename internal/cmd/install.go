@@ -356,26 +356,21 @@ func writeJSON(path string, data interface{}) error {
 // Town beads use the "hq-" prefix for mayor mail and cross-rig coordination.
 func initTownBeads(townPath string) error {
 	// Run: bd init --prefix hq
-	cmd := exec.Command("bd", "init", "--prefix", "hq")
-	cmd.Dir = townPath
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	bd := beads.New(townPath)
+	if err := bd.Init("hq"); err != nil {
 		// Check if beads is already initialized
-		if strings.Contains(string(output), "already initialized") {
+		if strings.Contains(err.Error(), "already initialized") {
 			// Already initialized - still need to ensure fingerprint exists
 		} else {
-			return fmt.Errorf("bd init failed: %s", strings.TrimSpace(string(output)))
+			return fmt.Errorf("bd init failed: %w", err)
 		}
 	}
 
 	// Configure custom types for Gas Town (agent, role, rig, convoy, slot).
 	// These were extracted from beads core in v0.46.0 and now require explicit config.
-	configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-	configCmd.Dir = townPath
-	if configOutput, configErr := configCmd.CombinedOutput(); configErr != nil {
+	if err := bd.ConfigSet("types.custom", constants.BeadsCustomTypes); err != nil {
 		// Non-fatal: older beads versions don't need this, newer ones do
-		fmt.Printf("   %s Could not set custom types: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(configOutput)))
+		fmt.Printf("   %s Could not set custom types: %v\n", style.Dim.Render("⚠"), err)
 	}
 
 	// Ensure database has repository fingerprint (GH #25).
@@ -417,13 +412,7 @@ func initTownBeads(townPath string) error {
 // has a repository fingerprint. Legacy databases (pre-0.17.5) lack this, which
 // prevents the daemon from starting properly.
 func ensureRepoFingerprint(beadsPath string) error {
-	cmd := exec.Command("bd", "migrate", "--update-repo-id")
-	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("bd migrate --update-repo-id: %s", strings.TrimSpace(string(output)))
-	}
-	return nil
+	return beads.New(beadsPath).UpdateRepoID()
 }
 
 // ensureCustomTypes registers Gas Town custom issue types with beads.
@@ -431,13 +420,7 @@ func ensureRepoFingerprint(beadsPath string) error {
 // Gas Town needs custom types: agent, role, rig, convoy, slot.
 // This is idempotent - safe to call multiple times.
 func ensureCustomTypes(beadsPath string) error {
-	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
-	}
-	return nil
+	return beads.New(beadsPath).ConfigSet("types.custom", constants.BeadsCustomTypes)
 }
 
 // initTownAgentBeads creates town-level agent and role beads using hq- prefix.
@@ -550,11 +533,8 @@ func ensureBeadsCustomTypes(workDir string, types []string) error {
 		return nil
 	}
 
-	cmd := exec.Command("bd", "config", "set", "types.custom", strings.Join(types, ","))
-	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("bd config set types.custom failed: %s", strings.TrimSpace(string(output)))
+	if err := beads.New(workDir).ConfigSet("types.custom", strings.Join(types, ",")); err != nil {
+		return fmt.Errorf("bd config set types.custom failed: %w", err)
 	}
 	return nil
 }
@@ -32,6 +32,7 @@ var logCmd = &cobra.Command{
 	GroupID: GroupDiag,
 	Short:   "View town activity log",
 	Long: `View the centralized log of Gas Town agent lifecycle events.
+To query every log source as one timeline, use 'gt logs'.
 
 Events logged include:
   spawn   - new agent created
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/logs"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Logs command flags
+var (
+	logsSince   string
+	logsUntil   string
+	logsActor   string
+	logsRig     string
+	logsRole    string
+	logsBead    string
+	logsTypes   []string
+	logsSources []string
+	logsTail    int
+	logsFollow  bool
+	logsJSON    bool
+	logsPanes   int
+)
+
+var logsCmd = &cobra.Command{
+	Use:     "logs",
+	GroupID: GroupDiag,
+	Short:   "Query all town logs as one timeline",
+	Long: `Query Gas Town's log sources merged into one timeline.
+
+Sources:
+  events   .events.jsonl       activity events (sling, done, merged, ...)
+  townlog  logs/town.log       agent lifecycle (spawn, crash, kill, ...)
+  daemon   daemon/daemon.log   daemon heartbeat and recovery
+  feed     .feed.jsonl         curated feed (derived from events; not
+                               queried unless named with --source)
+
+With --bead, the timeline is everything that ever happened to a bead:
+every log line mentioning it or its merge requests, plus the beads' own
+records (created, attached, held, merged, closed).
+
+With --panes N, the last N lines of each matching live agent session are
+appended, for output that never reaches a log file. Agents (the refinery
+included) run in tmux sessions, so their stdout is only in their panes.
+
+Times are local. --since and --until take a duration back from now
+(30m, 2h, 7d), a date (2025-12-26) or an RFC3339 time.
+
+Output is one line per entry; --json writes one JSON object per line.
+
+Examples:
+  gt logs                          # Last 50 entries across sources
+  gt logs --since 1h --rig gastown # Last hour in one rig
+  gt logs --role refinery -f       # Follow refinery activity
+  gt logs --bead gt-abc            # Full history of a bead
+  gt logs --type sling,done --json # Sling and done events as JSONL
+  gt logs --panes 20 --rig gastown # Include live session output`,
+	Args: cobra.NoArgs,
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show entries since a duration ago or time")
+	logsCmd.Flags().StringVar(&logsUntil, "until", "", "Show entries until a duration ago or time")
+	logsCmd.Flags().StringVarP(&logsActor, "actor", "a", "", "Filter by actor prefix (e.g., gastown/, gastown/polecats/Toast)")
+	logsCmd.Flags().StringVar(&logsRig, "rig", "", "Filter by rig")
+	logsCmd.Flags().StringVar(&logsRole, "role", "", "Filter by role (mayor, deacon, dog, witness, refinery, crew, polecat, daemon)")
+	logsCmd.Flags().StringVar(&logsBead, "bead", "", "Reconstruct everything that happened to a bead")
+	logsCmd.Flags().StringSliceVarP(&logsTypes, "type", "t", nil, "Filter by event type (comma-separated)")
+	logsCmd.Flags().StringSliceVar(&logsSources, "source", nil, "Sources to query (events,townlog,daemon,feed)")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 50, "Number of entries to show (0 for all; default all with --bead)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow new entries")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "Output as JSON lines")
+	logsCmd.Flags().IntVar(&logsPanes, "panes", 0, "Include the last N lines of matching live agent sessions")
+
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	filter := logs.Filter{
+		Actor: logsActor,
+		Rig:   logsRig,
+		Role:  logsRole,
+		Types: logsTypes,
+	}
+	if logsSince != "" {
+		if filter.Since, err = parseLogsTime(logsSince); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if logsUntil != "" {
+		if filter.Until, err = parseLogsTime(logsUntil); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	sources, err := logs.SelectSources(logs.Sources(townRoot), logsSources)
+	if err != nil {
+		return err
+	}
+
+	var extra []logs.Entry
+	if logsBead != "" {
+		history, ids, err := beadHistory(townRoot, logsBead)
+		if err != nil {
+			return err
+		}
+		filter.Beads = ids
+		for _, e := range history {
+			if filter.Match(e) {
+				extra = append(extra, e)
+			}
+		}
+		if !cmd.Flags().Changed("tail") {
+			logsTail = 0
+		}
+	}
+
+	entries, err := logs.Query(sources, filter, logsTail)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, extra...)
+	if logsPanes > 0 {
+		entries = append(entries, capturePanes(filter, logsPanes)...)
+	}
+	logs.Sort(entries)
+	if logsTail > 0 && len(entries) > logsTail {
+		entries = entries[len(entries)-logsTail:]
+	}
+
+	if len(entries) == 0 && !logsFollow && !logsJSON {
+		fmt.Printf("%s No log entries match\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range entries {
+		printLogsEntry(e)
+	}
+
+	if !logsFollow {
+		return nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return logs.Follow(ctx, sources, filter, 500*time.Millisecond, printLogsEntry)
+}
+
+// parseLogsTime parses a duration back from now, a date, or an RFC3339 time.
+func parseLogsTime(s string) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration, date or RFC3339 time", s)
+}
+
+// beadHistory returns a bead's own history entries, and the IDs whose log
+// lines belong to its timeline: the bead and the merge requests for it.
+func beadHistory(townRoot, id string) ([]logs.Entry, []string, error) {
+	dir := beads.ResolveHookDir(townRoot, id, "")
+	b := beads.New(dir)
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching %s: %w", id, err)
+	}
+	history := logs.BeadEntries(issue)
+	ids := []string{id}
+
+	// MRs live in the same rig's beads; listing them is best-effort
+	mrs, err := b.List(beads.ListOptions{Status: "all", Label: "gt:merge-request", Priority: -1})
+	if err != nil {
+		return history, ids, nil
+	}
+	for _, mr := range mrs {
+		if f := beads.ParseMRFields(mr); f != nil && f.SourceIssue == id {
+			history = append(history, logs.BeadEntries(mr)...)
+			ids = append(ids, mr.ID)
+		}
+	}
+	return history, ids, nil
+}
+
+// capturePanes returns the recent output of each live agent session the
+// filter's actor, rig and role select.
+func capturePanes(filter logs.Filter, lines int) []logs.Entry {
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil
+	}
+	now := time.Now()
+	paneFilter := logs.Filter{Actor: filter.Actor, Rig: filter.Rig, Role: filter.Role}
+	var entries []logs.Entry
+	for _, name := range sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		e := logs.NewEntry(now, logs.SourcePane, "", id.Address(), "")
+		if !paneFilter.Match(e) {
+			continue
+		}
+		out, err := t.CapturePane(name, lines)
+		if err != nil {
+			continue
+		}
+		e.Message = strings.TrimRight(out, "\n")
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func printLogsEntry(e logs.Entry) {
+	if logsJSON {
+		data, err := json.Marshal(e)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	ts := e.Time.Local().Format("2006-01-02 15:04:05")
+	source := fmt.Sprintf("%-7s", e.Source)
+	typ := ""
+	if e.Type != "" {
+		typ = logsTypeLabel(e.Type) + " "
+	}
+	msg := strings.ReplaceAll(e.Message, "\n", "\n    ")
+	fmt.Printf("%s %s %s%s %s\n", style.Dim.Render(ts), style.Dim.Render(source), typ, e.Actor, msg)
+}
+
+// logsTypeLabel renders an event type, colored by outcome.
+func logsTypeLabel(typ string) string {
+	label := "[" + typ + "]"
+	switch typ {
+	case "crash", "escalation_sent", "session_death", "mass_death", "merge_failed":
+		return style.Error.Render(label)
+	case "kill", "polecat_nudged", "merge_skipped", "usage_limit":
+		return style.Warning.Render(label)
+	case "spawn", "done", "merged", "closed", "patrol_complete":
+		return style.Success.Render(label)
+	default:
+		return style.Bold.Render(label)
+	}
+}
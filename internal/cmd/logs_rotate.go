@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/retention"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var logsRotateForce bool
+
+var logsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate, compress and prune logs now",
+	Long: `Apply the town's log retention policies now.
+
+The daemon does this every heartbeat. Each log (events, feed, town log,
+daemon log, mail archives and the session recordings index) is rotated
+to <file>.<UTC stamp> once it passes its size or age limit. Rotated
+segments are gzipped after ten minutes without writes, and deleted once
+older than the source's 'keep'. Session recordings (gt replay) are
+deleted once last written longer ago than the "recordings" keep.
+
+Policies are set per source in settings/config.json:
+
+  "retention": {
+    "events": {"max_size_mb": 50, "max_age": "7d", "keep": "90d"},
+    "mail":   {"keep": "365d", "keep_segments": 20, "compress": true}
+  }
+
+Readers (gt logs, gt audit, gt seance, the feed, mail archive) read
+across rotated segments.
+
+Examples:
+  gt logs rotate            # Rotate whatever is due
+  gt logs rotate --force    # Rotate every non-empty log`,
+	Args: cobra.NoArgs,
+	RunE: runLogsRotate,
+}
+
+func init() {
+	logsRotateCmd.Flags().BoolVar(&logsRotateForce, "force", false, "Rotate every non-empty log regardless of limits")
+	logsCmd.AddCommand(logsRotateCmd)
+}
+
+func runLogsRotate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	results, runErr := retention.Run(townRoot, time.Now(), logsRotateForce)
+	changed := 0
+	for _, r := range results {
+		if !r.Changed() {
+			continue
+		}
+		changed++
+		rel, err := filepath.Rel(townRoot, r.Path)
+		if err != nil {
+			rel = r.Path
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), rel)
+		if r.Rotated != "" {
+			fmt.Printf("    rotated to %s\n", filepath.Base(r.Rotated))
+		}
+		for _, gz := range r.Compressed {
+			fmt.Printf("    compressed %s\n", filepath.Base(gz))
+		}
+		for _, seg := range r.Removed {
+			fmt.Printf("    removed %s\n", style.Dim.Render(filepath.Base(seg)))
+		}
+	}
+	if changed == 0 && runErr == nil {
+		fmt.Printf("%s No logs due for rotation\n", style.Dim.Render("○"))
+	}
+	return runErr
+}
@@ -18,6 +18,12 @@ var (
 	mailNotify        bool
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailSendAt        string   // Deliver at absolute time
+	mailSendIn        string   // Deliver after relative delay
+	mailSendTTL       string   // Expire after duration
+	mailSendAck       bool     // Require explicit ack
+	mailSendAckWithin string   // Ack timeout
+	mailAckNote       string   // Note sent back to the sender on ack
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -35,19 +41,31 @@ var (
 	mailSearchBody    bool
 	mailSearchArchive bool
 	mailSearchJSON    bool
+	mailSearchAll     bool
+	mailSearchLimit   int
+	mailSearchRebuild bool
 
 	// Announces flags
 	mailAnnouncesJSON bool
 
 	// Clear flags
 	mailClearAll bool
+
+	// Interactive flags
+	mailInteractive         bool
+	mailInteractiveIdentity string
 )
 
 var mailCmd = &cobra.Command{
 	Use:     "mail",
 	GroupID: GroupComm,
 	Short:   "Agent messaging system",
-	RunE:    requireSubcommand,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mailInteractive {
+			return runMailTUI()
+		}
+		return requireSubcommand(cmd, args)
+	},
 	Long: `Send and receive messages between agents.
 
 The mail system allows Mayor, polecats, and the Refinery to communicate.
@@ -82,7 +100,13 @@ COMMANDS:
   inbox     View your inbox
   send      Send a message
   read      Read a specific message
-  mark      Mark messages read/unread`,
+  ack       Acknowledge a message that requires an ack
+  mark      Mark messages read/unread
+
+INTERACTIVE:
+  gt mail -i opens a mail client with folders (inbox, archive, queues,
+  channels, announces), threaded conversations, reply/compose, and bulk
+  archive/mark-read. It refreshes as new mail arrives.`,
 }
 
 var mailSendCmd = &cobra.Command{
@@ -125,7 +149,28 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send greenplace/Toast -s "Progress?" -m "Any update?" --in 30m
+  gt mail send mayor/ -s "Standup" -m "Post status" --at 09:00
+  gt mail send announce:alerts -s "Deploy freeze" -m "No merges" --ttl 2h
+
+Scheduling:
+  --at <time>       Deliver at a time (RFC3339, "2006-01-02 15:04", or "15:04")
+  --in <duration>   Deliver after a delay (e.g., 30m, 2h)
+  --ttl <duration>  Expire after a duration, measured from delivery
+
+Scheduled messages stay hidden from the recipient's inbox until due; the
+daemon releases them and notifies the recipient. Expired messages are
+hidden and auto-archived by the daemon (pinned messages are kept).
+
+Acknowledgement:
+  --ack                 Require the recipient to run 'gt mail ack <id>'
+  --ack-timeout <dur>   Time allowed before re-delivery (default 30m)
+
+Reading or archiving does not count as an ack. If the deadline passes,
+the daemon re-delivers the message via interrupt. If it is still not
+acked one timeout later, the daemon escalates to the recipient's witness
+(or the mayor for town-level agents).`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -158,6 +203,25 @@ The message ID can be found from 'gt mail inbox'.`,
 	RunE: runMailRead,
 }
 
+var mailAckCmd = &cobra.Command{
+	Use:   "ack <message-id>",
+	Short: "Acknowledge a message",
+	Long: `Acknowledge a message that requires an ack.
+
+Records who acknowledged it and when, stops the daemon's re-delivery and
+escalation tracking, and archives the message. With --note, the note is
+sent back to the original sender as a reply.
+
+Unlike archive or mark-read, ack is an explicit signal that you acted on
+the message.
+
+Examples:
+  gt mail ack hq-abc123
+  gt mail ack hq-abc123 --note "Rebased and resubmitted"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailAck,
+}
+
 var mailPeekCmd = &cobra.Command{
 	Use:   "peek",
 	Short: "Show preview of first unread message",
@@ -287,13 +351,20 @@ SYNTAX:
 BEHAVIOR:
 1. If queue specified, claim from that queue
 2. If no queue specified, claim from any eligible queue
-3. Add claimed-by and claimed-at labels to the message
+3. Add claimed-by, claimed-at and lease-until labels to the message
 4. Print claimed message details
 
 ELIGIBILITY:
 The caller must match the queue's claim_pattern (stored in the queue bead).
 Pattern examples: "*" (anyone), "gastown/polecats/*" (specific rig crew).
 
+LEASES:
+A claim lasts for the queue's visibility timeout (default 15m). Renew it with
+'gt mail renew <id>' while working. If the lease lapses, or the claimant's
+session dies, the daemon returns the message to the queue. Each claim counts
+as a delivery; after max_deliveries the message goes to the dead-letter queue
+('gt mail queue dlq <queue>').
+
 Examples:
   gt mail claim work-requests   # Claim from specific queue
   gt mail claim                 # Claim from any eligible queue`,
@@ -327,6 +398,21 @@ Examples:
 	RunE: runMailRelease,
 }
 
+var mailRenewCmd = &cobra.Command{
+	Use:     "renew <message-id>",
+	Aliases: []string{"heartbeat"},
+	Short:   "Renew the lease on a claimed queue message",
+	Long: `Extend your claim on a queue message by the queue's visibility timeout.
+
+Long-running work should renew well before the lease lapses, e.g. every
+few minutes. Only the claimant can renew.
+
+Examples:
+  gt mail renew hq-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailRenew,
+}
+
 var mailClearCmd = &cobra.Command{
 	Use:   "clear [target]",
 	Short: "Clear all messages from an inbox",
@@ -354,29 +440,52 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search town mail using an incrementally built full-text index.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+Free-text terms are case-insensitive and match by prefix ("handoff" also
+matches "handoffs"). All terms and filters must match. Results are ranked
+by relevance (subject matches count more than body matches), then recency.
+
+QUERY FILTERS:
+  from:<addr>       Sender contains addr
+  to:<addr>         Recipient or CC contains addr
+  type:<type>       task, scavenge, notification, reply
+  thread:<id>       Messages in a thread
+  priority:<p>      urgent, high, normal, low (or 0-3)
+  before:<when>     Sent before a date (2006-01-02, RFC3339) or age (7d, 12h)
+  after:<when>      Sent after a date or age
+  is:<state>        unread, read, archived, inbox
+  "exact phrase"    Phrase must appear verbatim
 
 FLAGS:
-  --from <sender>   Filter by sender address (substring match)
+  --from <sender>   Filter by sender address (same as from:)
   --subject         Only search subject lines
   --body            Only search message body
-  --archive         Include archived (closed) messages
+  --all-mailboxes   Search every agent's mail, not just yours
+  --limit <n>       Maximum results (default 50, 0 = unlimited)
+  --rebuild         Discard and rebuild the index before searching
   --json            Output as JSON
 
-By default, searches both subject and body text.
+Searches cover both inbox and archived messages. The index lives in
+<town>/.runtime/mail-index.json and only re-reads messages updated since
+the last search; once an hour it re-lists all mail to drop deleted messages.
+
+--all-mailboxes searches all mail in the town, whoever runs it. Mail has no
+read permissions: like 'gt mail inbox <address>', it can show any agent's
+messages.
 
 Examples:
-  gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
-  gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
-	Args: cobra.ExactArgs(1),
+  gt mail search "urgent"                        # Find messages with "urgent"
+  gt mail search "merge failed" --subject        # Terms in subjects only
+  gt mail search "error from:witness after:7d"   # Recent errors from witnesses
+  gt mail search "type:task is:unread"           # Unread tasks
+  gt mail search "thread:thread-abc123"          # Everything in a thread
+  gt mail search "to:greenplace/ before:2026-01-01" --all-mailboxes
+  gt mail search "" --from mayor/                # All messages from mayor`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runMailSearch,
 }
 
@@ -400,7 +509,7 @@ BEHAVIOR for 'gt mail announces':
 
 BEHAVIOR for 'gt mail announces <channel>':
 - Validates channel exists
-- Queries beads for messages with announce_channel=<channel>
+- Queries beads for messages labeled announce:<channel>
 - Displays in reverse chronological order (newest first)
 - Does NOT mark as read or remove messages
 
@@ -413,6 +522,10 @@ Examples:
 }
 
 func init() {
+	// Interactive flags
+	mailCmd.Flags().BoolVarP(&mailInteractive, "interactive", "i", false, "Interactive mail client (folders, threads, compose)")
+	mailCmd.Flags().StringVar(&mailInteractiveIdentity, "identity", "", "Mailbox to open with -i (default: auto-detect)")
+
 	// Send flags
 	mailSendCmd.Flags().StringVarP(&mailSubject, "subject", "s", "", "Message subject (required)")
 	mailSendCmd.Flags().StringVarP(&mailBody, "message", "m", "", "Message body")
@@ -426,6 +539,11 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailSendAt, "at", "", "Deliver at this time (RFC3339, \"2006-01-02 15:04\", or \"15:04\")")
+	mailSendCmd.Flags().StringVar(&mailSendIn, "in", "", "Deliver after this delay (e.g., 30m, 2h)")
+	mailSendCmd.Flags().StringVar(&mailSendTTL, "ttl", "", "Expire this long after delivery (e.g., 1h, 24h)")
+	mailSendCmd.Flags().BoolVar(&mailSendAck, "ack", false, "Require the recipient to acknowledge with 'gt mail ack'")
+	mailSendCmd.Flags().StringVar(&mailSendAckWithin, "ack-timeout", "", "Time allowed to ack before re-delivery (default 30m, implies --ack)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -434,6 +552,9 @@ func init() {
 	mailInboxCmd.Flags().StringVar(&mailInboxIdentity, "identity", "", "Explicit identity for inbox (e.g., greenplace/Toast)")
 	mailInboxCmd.Flags().StringVar(&mailInboxIdentity, "address", "", "Alias for --identity")
 
+	// Ack flags
+	mailAckCmd.Flags().StringVar(&mailAckNote, "note", "", "Note sent back to the sender as a reply")
+
 	// Read flags
 	mailReadCmd.Flags().BoolVar(&mailReadJSON, "json", false, "Output as JSON")
 
@@ -455,8 +576,12 @@ func init() {
 	mailSearchCmd.Flags().StringVar(&mailSearchFrom, "from", "", "Filter by sender address")
 	mailSearchCmd.Flags().BoolVar(&mailSearchSubject, "subject", false, "Only search subject lines")
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
-	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
+	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages (always on; kept for compatibility)")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
+	mailSearchCmd.Flags().BoolVar(&mailSearchAll, "all-mailboxes", false, "Search all agents' mail, not just yours")
+	mailSearchCmd.Flags().IntVar(&mailSearchLimit, "limit", 50, "Maximum results (0 = unlimited)")
+	mailSearchCmd.Flags().BoolVar(&mailSearchRebuild, "rebuild", false, "Rebuild the mail index before searching")
+	_ = mailSearchCmd.Flags().MarkHidden("archive")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
@@ -468,6 +593,7 @@ func init() {
 	mailCmd.AddCommand(mailSendCmd)
 	mailCmd.AddCommand(mailInboxCmd)
 	mailCmd.AddCommand(mailReadCmd)
+	mailCmd.AddCommand(mailAckCmd)
 	mailCmd.AddCommand(mailPeekCmd)
 	mailCmd.AddCommand(mailDeleteCmd)
 	mailCmd.AddCommand(mailArchiveCmd)
@@ -478,6 +604,7 @@ func init() {
 	mailCmd.AddCommand(mailReplyCmd)
 	mailCmd.AddCommand(mailClaimCmd)
 	mailCmd.AddCommand(mailReleaseCmd)
+	mailCmd.AddCommand(mailRenewCmd)
 	mailCmd.AddCommand(mailClearCmd)
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// runMailAck acknowledges a message and optionally replies with a note.
+func runMailAck(cmd *cobra.Command, args []string) error {
+	msgID := args[0]
+
+	// Determine which inbox
+	address := detectSender()
+
+	mailbox, err := getMailbox(address)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mailbox.Ack(msgID)
+	if errors.Is(err, mail.ErrAlreadyAcked) {
+		fmt.Printf("%s Message already acknowledged by %s\n", style.Dim.Render("○"), msg.AckedBy)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("acknowledging message: %w", err)
+	}
+
+	_ = events.LogFeed(events.TypeMail, address, events.MailPayload(msg.From, "ACK: "+msg.Subject))
+
+	// Send the note back to the sender as a threaded reply
+	if mailAckNote != "" && msg.From != "" {
+		subject := msg.Subject
+		if !strings.HasPrefix(subject, "Re: ") {
+			subject = "Re: " + subject
+		}
+		reply := &mail.Message{
+			From:     address,
+			To:       msg.From,
+			Subject:  subject,
+			Body:     fmt.Sprintf("ACK %s\n\n%s", msg.ID, mailAckNote),
+			Type:     mail.TypeReply,
+			Priority: mail.PriorityNormal,
+			ReplyTo:  msg.ID,
+			ThreadID: msg.ThreadID,
+		}
+		workDir, err := findMailWorkDir()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		if err := mail.NewRouter(workDir).Send(reply); err != nil {
+			style.PrintWarning("acknowledged, but failed to send note to %s: %v", msg.From, err)
+		}
+	}
+
+	fmt.Printf("%s Acknowledged %s\n", style.Bold.Render("✓"), msgID)
+	if !msg.AckRequired {
+		fmt.Printf("  %s\n", style.Dim.Render("(message did not require an ack)"))
+	}
+	return nil
+}
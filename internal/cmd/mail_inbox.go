@@ -90,8 +90,12 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 		if msg.Wisp {
 			wispMarker = " " + style.Dim.Render("(wisp)")
 		}
+		ackMarker := ""
+		if msg.AckRequired && msg.AckedBy == "" {
+			ackMarker = " " + style.Warning.Render("[ack required]")
+		}
 
-		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
+		fmt.Printf("  %s %s%s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker, ackMarker)
 		fmt.Printf("    %s from %s\n",
 			style.Dim.Render(msg.ID),
 			msg.From)
@@ -157,6 +161,21 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if msg.ReplyTo != "" {
 		fmt.Printf("Reply-To: %s\n", style.Dim.Render(msg.ReplyTo))
 	}
+	if msg.AckRequired {
+		if msg.AckedBy != "" {
+			fmt.Printf("Acked: by %s", msg.AckedBy)
+			if msg.AckedAt != nil {
+				fmt.Printf(" at %s", msg.AckedAt.Local().Format("2006-01-02 15:04"))
+			}
+			fmt.Println()
+		} else {
+			deadline := ""
+			if msg.AckDeadline != nil {
+				deadline = fmt.Sprintf(" by %s", msg.AckDeadline.Local().Format("2006-01-02 15:04"))
+			}
+			fmt.Printf("%s run 'gt mail ack %s'%s\n", style.Warning.Render("Ack required:"), msg.ID, deadline)
+		}
+	}
 
 	if msg.Body != "" {
 		fmt.Printf("\n%s\n", msg.Body)
@@ -1,17 +1,17 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -39,21 +39,9 @@ func runMailClaim(cmd *cobra.Command, args []string) error {
 		queueName = args[0]
 
 		// Look up the queue bead
-		queueID := beads.QueueBeadID(queueName, true) // Try town-level first
-		issue, fields, err := bd.GetQueueBead(queueID)
+		fields, err := lookupQueueFields(bd, queueName)
 		if err != nil {
-			return fmt.Errorf("looking up queue: %w", err)
-		}
-		if issue == nil {
-			// Try rig-level
-			queueID = beads.QueueBeadID(queueName, false)
-			issue, fields, err = bd.GetQueueBead(queueID)
-			if err != nil {
-				return fmt.Errorf("looking up queue: %w", err)
-			}
-			if issue == nil {
-				return fmt.Errorf("unknown queue: %s", queueName)
-			}
+			return err
 		}
 		queueFields = fields
 
@@ -90,16 +78,25 @@ func runMailClaim(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing queue messages: %w", err)
 	}
 
-	if len(messages) == 0 {
+	// Messages out of deliveries are waiting for the daemon to dead-letter them
+	var claimable []queueMessage
+	for _, m := range messages {
+		if queueFields.MaxDeliveries == 0 || m.Deliveries < queueFields.MaxDeliveries {
+			claimable = append(claimable, m)
+		}
+	}
+
+	if len(claimable) == 0 {
 		fmt.Printf("%s No messages to claim in queue %s\n", style.Dim.Render("○"), queueName)
 		return nil
 	}
 
 	// Pick the oldest unclaimed message (first in list, sorted by created)
-	oldest := messages[0]
+	oldest := claimable[0]
 
-	// Claim the message: add claimed-by and claimed-at labels
-	if err := claimQueueMessage(beadsDir, oldest.ID, caller); err != nil {
+	// Claim the message: add claim and lease labels
+	leaseUntil, err := claimQueueMessage(beadsDir, oldest, caller, queueFields.VisibilityTimeout)
+	if err != nil {
 		return fmt.Errorf("claiming message: %w", err)
 	}
 
@@ -118,10 +115,31 @@ func runMailClaim(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("  From: %s\n", oldest.From)
 	fmt.Printf("  Created: %s\n", oldest.Created.Format("2006-01-02 15:04"))
+	fmt.Printf("  Delivery: %d", oldest.Deliveries+1)
+	if queueFields.MaxDeliveries > 0 {
+		fmt.Printf(" of %d", queueFields.MaxDeliveries)
+	}
+	fmt.Println()
+	fmt.Printf("  Lease until: %s %s\n", leaseUntil.Local().Format("15:04:05"),
+		style.Dim.Render(fmt.Sprintf("(renew with: gt mail renew %s)", oldest.ID)))
 
 	return nil
 }
 
+// lookupQueueFields finds a queue bead by name, town-level first, then rig-level.
+func lookupQueueFields(bd *beads.Beads, queueName string) (*beads.QueueFields, error) {
+	for _, townLevel := range []bool{true, false} {
+		issue, fields, err := bd.GetQueueBead(beads.QueueBeadID(queueName, townLevel))
+		if err != nil {
+			return nil, fmt.Errorf("looking up queue: %w", err)
+		}
+		if issue != nil {
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown queue: %s", queueName)
+}
+
 // queueMessage represents a message in a queue.
 type queueMessage struct {
 	ID          string
@@ -132,60 +150,32 @@ type queueMessage struct {
 	Priority    int
 	ClaimedBy   string
 	ClaimedAt   *time.Time
+	Deliveries  int
 }
 
 // listUnclaimedQueueMessages lists unclaimed messages in a queue.
 // Unclaimed messages have queue:<name> label but no claimed-by label.
 func listUnclaimedQueueMessages(beadsDir, queueName string) ([]queueMessage, error) {
-	// Use bd list to find messages with queue:<name> label and status=open
-	args := []string{"list",
-		"--label", "queue:" + queueName,
-		"--status", "open",
-		"--type", "message",
-		"--json",
-	}
-
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return nil, fmt.Errorf("%s", errMsg)
-		}
+	// Find messages with queue:<name> label and status=open
+	issues, err := beads.NewWithBeadsDir("", beadsDir).List(beads.ListOptions{
+		Label:     "queue:" + queueName,
+		Status:    "open",
+		IssueType: "message",
+		Priority:  -1,
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Parse JSON output
-	var issues []struct {
-		ID          string    `json:"id"`
-		Title       string    `json:"title"`
-		Description string    `json:"description"`
-		Labels      []string  `json:"labels"`
-		CreatedAt   time.Time `json:"created_at"`
-		Priority    int       `json:"priority"`
-	}
-
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		// If no messages, bd might output empty or error
-		if strings.TrimSpace(stdout.String()) == "" || strings.TrimSpace(stdout.String()) == "[]" {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("parsing bd output: %w", err)
-	}
-
 	// Convert to queueMessage, filtering out already claimed messages
 	var messages []queueMessage
 	for _, issue := range issues {
+		created, _ := time.Parse(time.RFC3339, issue.CreatedAt)
 		msg := queueMessage{
 			ID:          issue.ID,
 			Title:       issue.Title,
 			Description: issue.Description,
-			Created:     issue.CreatedAt,
+			Created:     created,
 			Priority:    issue.Priority,
 		}
 
@@ -200,6 +190,10 @@ func listUnclaimedQueueMessages(beadsDir, queueName string) ([]queueMessage, err
 				if t, err := time.Parse(time.RFC3339, ts); err == nil {
 					msg.ClaimedAt = &t
 				}
+			} else if strings.HasPrefix(label, "deliveries:") {
+				if n, err := strconv.Atoi(strings.TrimPrefix(label, "deliveries:")); err == nil {
+					msg.Deliveries = n
+				}
 			}
 		}
 
@@ -217,32 +211,32 @@ func listUnclaimedQueueMessages(beadsDir, queueName string) ([]queueMessage, err
 	return messages, nil
 }
 
-// claimQueueMessage claims a message by adding claimed-by and claimed-at labels.
-func claimQueueMessage(beadsDir, messageID, claimant string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+// claimQueueMessage claims a message by adding claim and lease labels and
+// bumping its delivery count. Returns when the lease lapses.
+func claimQueueMessage(beadsDir string, msg queueMessage, claimant string, lease time.Duration) (time.Time, error) {
+	now := time.Now()
 
-	args := []string{"label", "add", messageID,
-		"claimed-by:" + claimant,
-		"claimed-at:" + now,
+	bd := beads.NewWithBeadsDir("", beadsDir).WithActor(claimant)
+	if err := bd.AddLabels(msg.ID, mail.QueueClaimLabels(claimant, now, lease, msg.Deliveries+1)...); err != nil {
+		return time.Time{}, err
 	}
 
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(),
-		"BEADS_DIR="+beadsDir,
-		"BD_ACTOR="+claimant,
-	)
+	// Drop the previous delivery count so only the current one remains
+	if msg.Deliveries > 0 {
+		if err := removeQueueLabel(bd, msg.ID, "deliveries:"+strconv.Itoa(msg.Deliveries)); err != nil {
+			return time.Time{}, err
+		}
+	}
 
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+	return now.Add(lease), nil
+}
 
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return fmt.Errorf("%s", errMsg)
-		}
+// removeQueueLabel removes a label from a queue message.
+// A missing label is not an error.
+func removeQueueLabel(bd *beads.Beads, id, label string) error {
+	if err := bd.RemoveLabels(id, label); err != nil && !strings.Contains(err.Error(), "does not have label") {
 		return err
 	}
-
 	return nil
 }
 
@@ -292,55 +286,65 @@ func runMailRelease(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// queueMessageInfo holds details about a queue message.
-type queueMessageInfo struct {
-	ID        string
-	Title     string
-	QueueName string
-	ClaimedBy string
-	ClaimedAt *time.Time
-	Status    string
-}
-
-// getQueueMessageInfo retrieves information about a queue message.
-func getQueueMessageInfo(beadsDir, messageID string) (*queueMessageInfo, error) {
-	args := []string{"show", messageID, "--json"}
+// runMailRenew extends the caller's lease on a claimed queue message.
+func runMailRenew(cmd *cobra.Command, args []string) error {
+	messageID := args[0]
 
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	// Find workspace
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	beadsDir := beads.ResolveBeadsDir(townRoot)
+	caller := detectSender()
 
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if strings.Contains(errMsg, "not found") {
-			return nil, fmt.Errorf("message not found: %s", messageID)
-		}
-		if errMsg != "" {
-			return nil, fmt.Errorf("%s", errMsg)
-		}
-		return nil, err
+	msgInfo, err := getQueueMessageInfo(beadsDir, messageID)
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+	if msgInfo.QueueName == "" {
+		return fmt.Errorf("message %s is not a queue message (no queue label)", messageID)
 	}
 
-	// Parse JSON output - bd show --json returns an array
-	var issues []struct {
-		ID       string   `json:"id"`
-		Title    string   `json:"title"`
-		Labels   []string `json:"labels"`
-		Status   string   `json:"status"`
+	fields, err := lookupQueueFields(beads.NewWithBeadsDir(townRoot, beadsDir), msgInfo.QueueName)
+	if err != nil {
+		return err
 	}
 
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return nil, fmt.Errorf("parsing message: %w", err)
+	router := mail.NewRouter(townRoot)
+	msg, err := router.RenewLease(messageID, caller, fields.VisibilityTimeout, time.Now())
+	if err != nil {
+		return fmt.Errorf("renewing lease: %w", err)
 	}
 
-	if len(issues) == 0 {
+	fmt.Printf("%s Renewed lease on %s\n", style.Bold.Render("✓"), messageID)
+	fmt.Printf("  Lease until: %s\n", msg.LeaseUntil.Local().Format("15:04:05"))
+
+	return nil
+}
+
+// queueMessageInfo holds details about a queue message.
+type queueMessageInfo struct {
+	ID         string
+	Title      string
+	QueueName  string
+	ClaimedBy  string
+	ClaimedAt  *time.Time
+	LeaseUntil *time.Time
+	Status     string
+}
+
+// getQueueMessageInfo retrieves information about a queue message.
+func getQueueMessageInfo(beadsDir, messageID string) (*queueMessageInfo, error) {
+	issue, err := beads.NewWithBeadsDir("", beadsDir).Show(messageID)
+	if errors.Is(err, beads.ErrNotFound) {
 		return nil, fmt.Errorf("message not found: %s", messageID)
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	issue := issues[0]
 	info := &queueMessageInfo{
 		ID:     issue.ID,
 		Title:  issue.Title,
@@ -358,6 +362,11 @@ func getQueueMessageInfo(beadsDir, messageID string) (*queueMessageInfo, error)
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				info.ClaimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "lease-until:") {
+			ts := strings.TrimPrefix(label, "lease-until:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				info.LeaseUntil = &t
+			}
 		}
 	}
 
@@ -372,44 +381,26 @@ func releaseQueueMessage(beadsDir, messageID, actor string) error {
 		return err
 	}
 
+	bd := beads.NewWithBeadsDir("", beadsDir).WithActor(actor)
+
 	// Remove claimed-by label
 	if info.ClaimedBy != "" {
-		args := []string{"label", "remove", messageID, "claimed-by:" + info.ClaimedBy}
-		cmd := exec.Command("bd", args...)
-		cmd.Env = append(os.Environ(),
-			"BEADS_DIR="+beadsDir,
-			"BD_ACTOR="+actor,
-		)
-
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-
-		if err := cmd.Run(); err != nil {
-			errMsg := strings.TrimSpace(stderr.String())
-			if errMsg != "" && !strings.Contains(errMsg, "does not have label") {
-				return fmt.Errorf("%s", errMsg)
-			}
+		if err := removeQueueLabel(bd, messageID, "claimed-by:"+info.ClaimedBy); err != nil {
+			return err
 		}
 	}
 
 	// Remove claimed-at label if present
 	if info.ClaimedAt != nil {
-		claimedAtStr := info.ClaimedAt.Format(time.RFC3339)
-		args := []string{"label", "remove", messageID, "claimed-at:" + claimedAtStr}
-		cmd := exec.Command("bd", args...)
-		cmd.Env = append(os.Environ(),
-			"BEADS_DIR="+beadsDir,
-			"BD_ACTOR="+actor,
-		)
-
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-
-		if err := cmd.Run(); err != nil {
-			errMsg := strings.TrimSpace(stderr.String())
-			if errMsg != "" && !strings.Contains(errMsg, "does not have label") {
-				return fmt.Errorf("%s", errMsg)
-			}
+		if err := removeQueueLabel(bd, messageID, "claimed-at:"+info.ClaimedAt.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
+	// Remove lease-until label if present
+	if info.LeaseUntil != nil {
+		if err := removeQueueLabel(bd, messageID, "lease-until:"+info.LeaseUntil.Format(time.RFC3339)); err != nil {
+			return err
 		}
 	}
 
@@ -419,8 +410,11 @@ func releaseQueueMessage(beadsDir, messageID, actor string) error {
 // Queue management commands (beads-native)
 
 var (
-	mailQueueClaimers string
-	mailQueueJSON     bool
+	mailQueueClaimers          string
+	mailQueueJSON              bool
+	mailQueueVisibilityTimeout time.Duration
+	mailQueueMaxDeliveries     int
+	mailQueueDLQRequeue        string
 )
 
 var mailQueueCmd = &cobra.Command{
@@ -436,12 +430,14 @@ COMMANDS:
   show      Show queue details
   list      List all queues
   delete    Delete a queue
+  dlq       Inspect a queue's dead-letter queue
 
 Examples:
   gt mail queue create work --claimers 'gastown/polecats/*'
   gt mail queue show work
   gt mail queue list
-  gt mail queue delete work`,
+  gt mail queue delete work
+  gt mail queue dlq work`,
 	RunE: requireSubcommand,
 }
 
@@ -453,10 +449,16 @@ var mailQueueCreateCmd = &cobra.Command{
 The --claimers flag specifies a pattern for who can claim messages from this queue.
 Patterns support wildcards: 'gastown/polecats/*' matches any polecat in gastown rig.
 
+Claims are leases. A claimant must renew with 'gt mail renew' within the
+visibility timeout, or the daemon returns the message to the queue. The
+daemon also releases claims held by agents whose session has died. After
+--max-deliveries claims, the message moves to the queue's dead-letter queue.
+
 Examples:
   gt mail queue create work --claimers 'gastown/polecats/*'
   gt mail queue create dispatch --claimers 'gastown/crew/*'
-  gt mail queue create urgent --claimers '*'`,
+  gt mail queue create urgent --claimers '*'
+  gt mail queue create builds --claimers '*' --visibility-timeout 1h --max-deliveries 3`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailQueueCreate,
 }
@@ -501,20 +503,43 @@ Examples:
 	RunE: runMailQueueDelete,
 }
 
+var mailQueueDLQCmd = &cobra.Command{
+	Use:   "dlq <name>",
+	Short: "Inspect a queue's dead-letter queue",
+	Long: `List messages that exceeded the queue's max deliveries.
+
+A message is dead-lettered when its lease lapses (or its claimant dies)
+after it was already claimed max_deliveries times. Dead-lettered messages
+are no longer claimable. Use --requeue to put one back in the queue with a
+fresh delivery count.
+
+Examples:
+  gt mail queue dlq work
+  gt mail queue dlq work --json
+  gt mail queue dlq work --requeue hq-abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailQueueDLQ,
+}
+
 func init() {
 	// Queue create flags
 	mailQueueCreateCmd.Flags().StringVar(&mailQueueClaimers, "claimers", "", "Pattern for who can claim from this queue (required)")
+	mailQueueCreateCmd.Flags().DurationVar(&mailQueueVisibilityTimeout, "visibility-timeout", beads.DefaultQueueVisibilityTimeout, "How long a claim lasts without renewal")
+	mailQueueCreateCmd.Flags().IntVar(&mailQueueMaxDeliveries, "max-deliveries", beads.DefaultQueueMaxDeliveries, "Claims before a message is dead-lettered (0 = unlimited)")
 	_ = mailQueueCreateCmd.MarkFlagRequired("claimers")
 
-	// Queue show/list flags
+	// Queue show/list/dlq flags
 	mailQueueShowCmd.Flags().BoolVar(&mailQueueJSON, "json", false, "Output as JSON")
 	mailQueueListCmd.Flags().BoolVar(&mailQueueJSON, "json", false, "Output as JSON")
+	mailQueueDLQCmd.Flags().BoolVar(&mailQueueJSON, "json", false, "Output as JSON")
+	mailQueueDLQCmd.Flags().StringVar(&mailQueueDLQRequeue, "requeue", "", "Move a dead-lettered message back into the queue")
 
 	// Add queue subcommands
 	mailQueueCmd.AddCommand(mailQueueCreateCmd)
 	mailQueueCmd.AddCommand(mailQueueShowCmd)
 	mailQueueCmd.AddCommand(mailQueueListCmd)
 	mailQueueCmd.AddCommand(mailQueueDeleteCmd)
+	mailQueueCmd.AddCommand(mailQueueDLQCmd)
 
 	// Add queue command to mail
 	mailCmd.AddCommand(mailQueueCmd)
@@ -524,6 +549,13 @@ func init() {
 func runMailQueueCreate(cmd *cobra.Command, args []string) error {
 	queueName := args[0]
 
+	if mailQueueVisibilityTimeout <= 0 {
+		return fmt.Errorf("--visibility-timeout must be positive")
+	}
+	if mailQueueMaxDeliveries < 0 {
+		return fmt.Errorf("--max-deliveries must be 0 (unlimited) or more")
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -555,6 +587,9 @@ func runMailQueueCreate(cmd *cobra.Command, args []string) error {
 		Status:       beads.QueueStatusActive,
 		CreatedBy:    caller,
 		CreatedAt:    time.Now().Format(time.RFC3339),
+
+		VisibilityTimeout: mailQueueVisibilityTimeout,
+		MaxDeliveries:     mailQueueMaxDeliveries,
 	}
 
 	title := fmt.Sprintf("Queue: %s", queueName)
@@ -566,10 +601,20 @@ func runMailQueueCreate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s Created queue %s\n", style.Bold.Render("✓"), queueName)
 	fmt.Printf("  ID: %s\n", queueID)
 	fmt.Printf("  Claimers: %s\n", mailQueueClaimers)
+	fmt.Printf("  Visibility timeout: %s\n", mailQueueVisibilityTimeout)
+	fmt.Printf("  Max deliveries: %s\n", formatMaxDeliveries(mailQueueMaxDeliveries))
 
 	return nil
 }
 
+// formatMaxDeliveries renders a max-deliveries setting for display.
+func formatMaxDeliveries(n int) string {
+	if n == 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(n)
+}
+
 // runMailQueueShow shows details about a queue.
 func runMailQueueShow(cmd *cobra.Command, args []string) error {
 	queueName := args[0]
@@ -604,6 +649,10 @@ func runMailQueueShow(cmd *cobra.Command, args []string) error {
 			"failed_count":     fields.FailedCount,
 			"created_by":       fields.CreatedBy,
 			"created_at":       fields.CreatedAt,
+
+			"visibility_timeout": fields.VisibilityTimeout.String(),
+			"max_deliveries":     fields.MaxDeliveries,
+			"dead_letter_count":  fields.DeadLetterCount,
 		}
 		jsonBytes, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -624,6 +673,12 @@ func runMailQueueShow(cmd *cobra.Command, args []string) error {
 	if fields.FailedCount > 0 {
 		fmt.Printf("  Failed: %d\n", fields.FailedCount)
 	}
+	fmt.Printf("  Visibility timeout: %s\n", fields.VisibilityTimeout)
+	fmt.Printf("  Max deliveries: %s\n", formatMaxDeliveries(fields.MaxDeliveries))
+	if fields.DeadLetterCount > 0 {
+		fmt.Printf("  Dead-lettered: %d %s\n", fields.DeadLetterCount,
+			style.Dim.Render(fmt.Sprintf("(gt mail queue dlq %s)", queueName)))
+	}
 	if fields.CreatedBy != "" {
 		fmt.Printf("  Created by: %s\n", fields.CreatedBy)
 	}
@@ -718,3 +773,57 @@ func runMailQueueDelete(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runMailQueueDLQ lists a queue's dead-lettered messages, or requeues one.
+func runMailQueueDLQ(cmd *cobra.Command, args []string) error {
+	queueName := args[0]
+
+	// Find workspace
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(townRoot)
+
+	if mailQueueDLQRequeue != "" {
+		msg, err := router.RequeueDeadLetter(queueName, mailQueueDLQRequeue)
+		if err != nil {
+			return fmt.Errorf("requeueing message: %w", err)
+		}
+		fmt.Printf("%s Requeued %s to %s\n", style.Bold.Render("✓"), msg.ID, queueName)
+		return nil
+	}
+
+	messages, err := router.ListDeadLetters(queueName)
+	if err != nil {
+		return fmt.Errorf("listing dead letters: %w", err)
+	}
+
+	if mailQueueJSON {
+		if messages == nil {
+			messages = []*mail.Message{}
+		}
+		jsonBytes, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(messages) == 0 {
+		fmt.Printf("%s No dead-lettered messages in queue %s\n", style.Dim.Render("○"), queueName)
+		return nil
+	}
+
+	fmt.Printf("%s Dead letters for %s (%d)\n\n", style.Bold.Render("☠"), queueName, len(messages))
+	for _, msg := range messages {
+		fmt.Printf("  %s %s\n", style.Bold.Render(msg.ID), msg.Subject)
+		fmt.Printf("    From: %s  Deliveries: %d  Created: %s\n",
+			msg.From, msg.Deliveries, msg.Timestamp.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Requeue with: gt mail queue dlq %s --requeue <id>", queueName)))
+
+	return nil
+}
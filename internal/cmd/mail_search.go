@@ -4,18 +4,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-// runMailSearch searches for messages matching a pattern.
+// runMailSearch searches town mail via the mail index.
 func runMailSearch(cmd *cobra.Command, args []string) error {
-	query := args[0]
+	query, err := mail.ParseSearchQuery(strings.Join(args, " "), time.Now())
+	if err != nil {
+		return fmt.Errorf("parsing query: %w", err)
+	}
+	if mailSearchFrom != "" {
+		query.From = mailSearchFrom
+	}
 
-	// Determine which inbox to search
+	// Determine which mailbox to search (empty = all mailboxes)
 	address := detectSender()
+	scope := address
+	if mailSearchAll {
+		scope = ""
+	}
 
 	// Get workspace for mail operations
 	workDir, err := findMailWorkDir()
@@ -23,44 +35,48 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Get mailbox
+	// Bring the index up to date; fall back to a stale index if bd is unavailable
 	router := mail.NewRouter(workDir)
-	mailbox, err := router.GetMailbox(address)
+	index, _, err := router.RefreshIndex(mailSearchRebuild)
 	if err != nil {
-		return fmt.Errorf("getting mailbox: %w", err)
+		if index == nil || index.Len() == 0 {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s %v (searching stale index)\n", style.Warning.Render("⚠"), err)
 	}
 
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
+	hits := index.Search(query, mail.IndexSearchOptions{
+		Mailbox:     scope,
 		SubjectOnly: mailSearchSubject,
 		BodyOnly:    mailSearchBody,
-	}
-
-	// Execute search
-	messages, err := mailbox.Search(opts)
-	if err != nil {
-		return fmt.Errorf("searching messages: %w", err)
-	}
+		Limit:       mailSearchLimit,
+	})
 
 	// JSON output
 	if mailSearchJSON {
+		if hits == nil {
+			hits = []*mail.SearchHit{}
+		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(messages)
+		return enc.Encode(hits)
 	}
 
 	// Human-readable output
+	target := address
+	if scope == "" {
+		target = "all mailboxes"
+	}
 	fmt.Printf("%s Search results for %s: %d message(s)\n\n",
-		style.Bold.Render("🔍"), address, len(messages))
+		style.Bold.Render("🔍"), target, len(hits))
 
-	if len(messages) == 0 {
+	if len(hits) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
 		return nil
 	}
 
-	for _, msg := range messages {
+	for _, hit := range hits {
+		msg := hit.Message
 		readMarker := "●"
 		if msg.Read {
 			readMarker = "○"
@@ -77,11 +93,17 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		if msg.Wisp {
 			wispMarker = " " + style.Dim.Render("(wisp)")
 		}
+		archivedMarker := ""
+		if hit.Archived {
+			archivedMarker = " " + style.Dim.Render("(archived)")
+		}
 
-		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
-		fmt.Printf("    %s from %s\n",
-			style.Dim.Render(msg.ID),
-			msg.From)
+		fmt.Printf("  %s %s%s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker, archivedMarker)
+		if scope == "" {
+			fmt.Printf("    %s from %s to %s\n", style.Dim.Render(msg.ID), msg.From, msg.To)
+		} else {
+			fmt.Printf("    %s from %s\n", style.Dim.Render(msg.ID), msg.From)
+		}
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -88,6 +89,24 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Set deferred delivery and expiry
+	deliverAfter, expiresAt, err := parseMailSchedule(mailSendAt, mailSendIn, mailSendTTL, time.Now())
+	if err != nil {
+		return err
+	}
+	msg.DeliverAfter = deliverAfter
+	msg.ExpiresAt = expiresAt
+
+	// Require explicit acknowledgement (--ack-timeout implies --ack)
+	if mailSendAck || mailSendAckWithin != "" {
+		deadline, err := parseAckDeadline(mailSendAckWithin, deliverAfter, time.Now())
+		if err != nil {
+			return err
+		}
+		msg.AckRequired = true
+		msg.AckDeadline = &deadline
+	}
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -128,6 +147,15 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Acks are tracked per recipient; refuse before sending anything
+	if msg.AckRequired {
+		for _, rec := range recipients {
+			if rec.Type == mail.RecipientQueue || rec.Type == mail.RecipientChannel {
+				return fmt.Errorf("--ack: %w: %s", mail.ErrAckUnsupported, rec.Address)
+			}
+		}
+	}
+
 	// Route based on recipient type
 	router := mail.NewRouter(workDir)
 	var recipientAddrs []string
@@ -178,10 +206,104 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if msg.AckDeadline != nil {
+		fmt.Printf("  Ack required by: %s\n", msg.AckDeadline.Local().Format("2006-01-02 15:04"))
+	}
+	if msg.DeliverAfter != nil {
+		fmt.Printf("  Deliver at: %s\n", msg.DeliverAfter.Local().Format("2006-01-02 15:04"))
+	}
+	if msg.ExpiresAt != nil {
+		fmt.Printf("  Expires at: %s\n", msg.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
 
 	return nil
 }
 
+// parseMailSchedule converts the --at, --in and --ttl flags into delivery and
+// expiry times. --at and --in are mutually exclusive. --ttl is measured from
+// the delivery time (or now for immediate delivery).
+func parseMailSchedule(at, in, ttl string, now time.Time) (deliverAfter, expiresAt *time.Time, err error) {
+	if at != "" && in != "" {
+		return nil, nil, fmt.Errorf("--at and --in are mutually exclusive")
+	}
+
+	deliverAt := now
+	if at != "" {
+		t, err := parseDeliveryTime(at, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		deliverAt = t
+	} else if in != "" {
+		d, err := time.ParseDuration(in)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --in duration %q: %w", in, err)
+		}
+		if d <= 0 {
+			return nil, nil, fmt.Errorf("--in must be positive")
+		}
+		deliverAt = now.Add(d)
+	}
+	if deliverAt.After(now) {
+		deliverAfter = &deliverAt
+	}
+
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --ttl duration %q: %w", ttl, err)
+		}
+		if d <= 0 {
+			return nil, nil, fmt.Errorf("--ttl must be positive")
+		}
+		exp := deliverAt.Add(d)
+		expiresAt = &exp
+	}
+
+	return deliverAfter, expiresAt, nil
+}
+
+// parseAckDeadline computes when an ack-required message is re-delivered if
+// not acknowledged: the timeout (default mail.DefaultAckTimeout) measured
+// from delivery.
+func parseAckDeadline(timeout string, deliverAfter *time.Time, now time.Time) (time.Time, error) {
+	d := mail.DefaultAckTimeout
+	if timeout != "" {
+		parsed, err := time.ParseDuration(timeout)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --ack-timeout duration %q: %w", timeout, err)
+		}
+		if parsed <= 0 {
+			return time.Time{}, fmt.Errorf("--ack-timeout must be positive")
+		}
+		d = parsed
+	}
+	delivered := now
+	if deliverAfter != nil {
+		delivered = *deliverAfter
+	}
+	return delivered.Add(d), nil
+}
+
+// parseDeliveryTime parses an --at value. Accepts RFC3339, "2006-01-02 15:04"
+// (local time), or "15:04" (the next occurrence of that local time).
+func parseDeliveryTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at time %q (use RFC3339, \"2006-01-02 15:04\", or \"15:04\")", s)
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -260,17 +261,17 @@ func TestAnnounceMessageParsing(t *testing.T) {
 	}{
 		{
 			name:   "extracts from label",
-			labels: []string{"from:mayor/", "announce_channel:alerts"},
+			labels: []string{"from:mayor/", "announce:alerts"},
 			want:   "mayor/",
 		},
 		{
 			name:   "extracts from with rig path",
-			labels: []string{"announce_channel:alerts", "from:gastown/witness"},
+			labels: []string{"announce:alerts", "from:gastown/witness"},
 			want:   "gastown/witness",
 		},
 		{
 			name:   "no from label",
-			labels: []string{"announce_channel:alerts"},
+			labels: []string{"announce:alerts"},
 			want:   "",
 		},
 		{
@@ -296,3 +297,89 @@ func TestAnnounceMessageParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMailSchedule(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	t.Run("immediate", func(t *testing.T) {
+		deliver, expires, err := parseMailSchedule("", "", "", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deliver != nil || expires != nil {
+			t.Errorf("got deliver=%v expires=%v, want both nil", deliver, expires)
+		}
+	})
+
+	t.Run("in with ttl", func(t *testing.T) {
+		deliver, expires, err := parseMailSchedule("", "30m", "1h", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deliver == nil || !deliver.Equal(now.Add(30*time.Minute)) {
+			t.Errorf("deliver = %v, want %v", deliver, now.Add(30*time.Minute))
+		}
+		if expires == nil || !expires.Equal(now.Add(90*time.Minute)) {
+			t.Errorf("expires = %v, want %v", expires, now.Add(90*time.Minute))
+		}
+	})
+
+	t.Run("ttl only", func(t *testing.T) {
+		deliver, expires, err := parseMailSchedule("", "", "2h", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deliver != nil {
+			t.Errorf("deliver = %v, want nil", deliver)
+		}
+		if expires == nil || !expires.Equal(now.Add(2*time.Hour)) {
+			t.Errorf("expires = %v, want %v", expires, now.Add(2*time.Hour))
+		}
+	})
+
+	t.Run("clock time rolls to tomorrow", func(t *testing.T) {
+		deliver, _, err := parseMailSchedule("09:00", "", "", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC)
+		if deliver == nil || !deliver.Equal(want) {
+			t.Errorf("deliver = %v, want %v", deliver, want)
+		}
+	})
+
+	errCases := []struct{ name, at, in, ttl string }{
+		{"at and in", "11:00", "5m", ""},
+		{"bad in", "", "soon", ""},
+		{"negative ttl", "", "", "-1h"},
+		{"bad at", "tomorrow", "", ""},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := parseMailSchedule(tc.at, tc.in, tc.ttl, now); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseAckDeadline(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	got, err := parseAckDeadline("", nil, now)
+	if err != nil || !got.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("default deadline = %v, %v", got, err)
+	}
+
+	deliver := now.Add(time.Hour)
+	got, err = parseAckDeadline("10m", &deliver, now)
+	if err != nil || !got.Equal(deliver.Add(10*time.Minute)) {
+		t.Errorf("scheduled deadline = %v, %v", got, err)
+	}
+
+	for _, bad := range []string{"soon", "0s", "-5m"} {
+		if _, err := parseAckDeadline(bad, nil, now); err == nil {
+			t.Errorf("parseAckDeadline(%q) expected error", bad)
+		}
+	}
+}
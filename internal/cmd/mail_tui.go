@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/tui/inbox"
+)
+
+// runMailTUI launches the interactive mail client.
+func runMailTUI() error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	address := mailInteractiveIdentity
+	if address == "" {
+		address = detectSender()
+	}
+
+	m := inbox.New(townRoot, address)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -238,8 +237,9 @@ func calculateEffectiveTimeout(idleCycles int) (time.Duration, error) {
 // Returns immediately when a line is received, or when context is canceled.
 func waitForActivitySignal(ctx context.Context, workDir string) (*AwaitSignalResult, error) {
 	// Start bd activity --follow
-	cmd := exec.CommandContext(ctx, "bd", "activity", "--follow")
+	cmd := beads.CommandContext(ctx, "activity", "--follow")
 	cmd.Dir = workDir
+	cmd.Timeout = 0 // Runs until a signal arrives or ctx is canceled
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -341,16 +341,7 @@ func setAgentIdleCycles(agentBead, beadsDir string, cycles int) error {
 	// Add new idle value
 	newLabels = append(newLabels, fmt.Sprintf("idle:%d", cycles))
 
-	// Use bd update with --set-labels to replace all labels
-	args := []string{"update", agentBead}
-	for _, label := range newLabels {
-		args = append(args, "--set-labels="+label)
-	}
-
-	cmd := exec.Command("bd", args...)
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-
-	if err := cmd.Run(); err != nil {
+	if err := beads.NewWithBeadsDir("", beadsDir).SetLabels(agentBead, newLabels...); err != nil {
 		return fmt.Errorf("setting idle label: %w", err)
 	}
 
@@ -6,10 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -109,10 +111,22 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
 	} else {
+		// Time the step from when it was claimed, if it was
+		span := telemetry.StartRemote(workTraceParent(step), "molecule.step",
+			telemetry.String("gt.step", stepID), telemetry.String("gt.molecule", moleculeID),
+			telemetry.String("gt.step_title", step.Title))
+		if step.Status == "in_progress" {
+			if claimed, err := time.Parse(time.RFC3339, step.UpdatedAt); err == nil {
+				span.SetStart(claimed)
+			}
+		}
+
 		if err := b.Close(stepID); err != nil {
+			span.End(err)
 			return fmt.Errorf("closing step: %w", err)
 		}
 		result.StepClosed = true
+		span.End(nil)
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
 	}
 
@@ -285,10 +299,7 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 	}
 
 	// Pin the next step bead
-	pinCmd := exec.Command("bd", "update", nextStep.ID, "--status=pinned", "--assignee="+agentID)
-	pinCmd.Dir = gitRoot
-	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := beads.New(gitRoot).Pin(nextStep.ID, agentID); err != nil {
 		return fmt.Errorf("pinning next step: %w", err)
 	}
 
@@ -373,10 +384,8 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 		})
 		if err == nil && len(pinnedBeads) > 0 {
 			// Unpin by setting status to open
-			unpinCmd := exec.Command("bd", "update", pinnedBeads[0].ID, "--status=open")
-			unpinCmd.Dir = gitRoot
-			unpinCmd.Stderr = os.Stderr
-			if err := unpinCmd.Run(); err != nil {
+			status := "open"
+			if err := beads.New(gitRoot).Update(pinnedBeads[0].ID, beads.UpdateOptions{Status: &status}); err != nil {
 				style.PrintWarning("could not unpin bead: %v", err)
 			} else {
 				fmt.Printf("%s Work unpinned\n", style.Bold.Render("✓"))
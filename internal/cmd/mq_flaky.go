@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ flaky command flags
+var (
+	mqFlakyAll    bool
+	mqFlakyJSON   bool
+	mqFlakyReason string
+)
+
+var mqFlakyCmd = &cobra.Command{
+	Use:   "flaky",
+	Short: "Manage the rig's flaky test history and quarantine list",
+	Long: `Manage the refinery's per-test history and quarantine list.
+
+When a tests gate's output parses as a test report (go test -json,
+JUnit XML or TAP), the refinery records each test's outcome in
+<rig>/.runtime/test-history.json. A test whose outcome flips often
+(merge_queue.flaky.threshold of its recent runs, after min_runs) is
+classified flaky. Retries rerun only the failing tests, and a flaky test
+that keeps failing (flaky.bug_after times) gets a bug filed once.
+
+Quarantined tests never block a merge; their failures are still recorded.
+Set "ignore_flaky": true on a tests gate to also let failures of tests
+classified flaky through.
+
+Test IDs are "<suite>.<name>", e.g. "example.com/pkg.TestFoo".`,
+	RunE: requireSubcommand,
+}
+
+var mqFlakyListCmd = &cobra.Command{
+	Use:   "list <rig>",
+	Short: "List flaky and quarantined tests",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMQFlakyList,
+}
+
+var mqFlakyQuarantineCmd = &cobra.Command{
+	Use:   "quarantine <rig> <test-id>",
+	Short: "Stop a test's failures from blocking merges",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runMQFlakyQuarantine,
+}
+
+var mqFlakyUnquarantineCmd = &cobra.Command{
+	Use:   "unquarantine <rig> <test-id>",
+	Short: "Make a quarantined test's failures block merges again",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runMQFlakyUnquarantine,
+}
+
+func init() {
+	mqFlakyListCmd.Flags().BoolVar(&mqFlakyAll, "all", false, "List every tracked test")
+	mqFlakyListCmd.Flags().BoolVar(&mqFlakyJSON, "json", false, "Output as JSON")
+	mqFlakyQuarantineCmd.Flags().StringVar(&mqFlakyReason, "reason", "", "Why the test is quarantined")
+
+	mqFlakyCmd.AddCommand(mqFlakyListCmd)
+	mqFlakyCmd.AddCommand(mqFlakyQuarantineCmd)
+	mqFlakyCmd.AddCommand(mqFlakyUnquarantineCmd)
+	mqCmd.AddCommand(mqFlakyCmd)
+}
+
+// flakyStore returns the rig's test history store.
+func flakyStore(rigName string) (*refinery.FlakyStore, error) {
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return nil, err
+	}
+	return refinery.NewFlakyStore(r.Path, eng.Config().Flaky), nil
+}
+
+func runMQFlakyList(cmd *cobra.Command, args []string) error {
+	store, err := flakyStore(args[0])
+	if err != nil {
+		return err
+	}
+	db, err := store.Load()
+	if err != nil {
+		return err
+	}
+	list := db.List(mqFlakyAll)
+
+	if mqFlakyJSON {
+		if list == nil {
+			list = []*refinery.TestHistory{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No flaky or quarantined tests"))
+		return nil
+	}
+	for _, h := range list {
+		var state string
+		switch {
+		case h.Quarantined:
+			state = style.Warning.Render("quarantined")
+		case h.Flaky:
+			state = style.Error.Render("flaky")
+		default:
+			state = style.Dim.Render("stable")
+		}
+		line := fmt.Sprintf("%s  %s  flip %3.0f%%  %d/%d failed", state, style.Bold.Render(h.ID), h.FlipRate*100, h.Failures(), len(h.Runs))
+		if h.BugID != "" {
+			line += "  bug " + h.BugID
+		}
+		fmt.Println(line)
+		if h.Quarantined && h.QuarantineReason != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(h.QuarantineReason))
+		}
+	}
+	return nil
+}
+
+func runMQFlakyQuarantine(cmd *cobra.Command, args []string) error {
+	store, err := flakyStore(args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.Quarantine(args[1], mqFlakyReason); err != nil {
+		return err
+	}
+	fmt.Printf("%s Quarantined %s\n", style.Bold.Render("✓"), args[1])
+	return nil
+}
+
+func runMQFlakyUnquarantine(cmd *cobra.Command, args []string) error {
+	store, err := flakyStore(args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.Unquarantine(args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Unquarantined %s\n", style.Bold.Render("✓"), args[1])
+	return nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// MQ gates command flags
+var (
+	mqGatesMR      string
+	mqGatesBase    string
+	mqGatesHead    string
+	mqGatesJSON    bool
+	mqGatesNoCache bool
+)
+
+var mqGatesCmd = &cobra.Command{
+	Use:   "gates <rig>",
+	Short: "Run the rig's pre-merge gates on the refinery worktree",
+	Long: `Run the rig's pre-merge quality gates against the checked-out merge.
+
+Gates run in order in the refinery worktree, each under its own timeout,
+and stop at the first blocking failure. They are configured as
+merge_queue.gates in the rig's config.json:
+
+  "gates": [
+    {"name": "vet", "command": "go vet ./...", "timeout": "5m"},
+    {"name": "lint", "command": "golangci-lint run", "advisory": true},
+    {"name": "no-deploy", "type": "forbidden_paths", "paths": ["deploy/**"]},
+    {"name": "size", "type": "diff_size", "max_lines": 1500, "max_files": 40},
+    {"name": "secrets", "type": "secrets"},
+    {"name": "tests", "type": "tests"},
+    {"name": "coverage", "type": "coverage", "min_coverage": 70}
+  ]
+
+Gate types: command (default; any shell check such as lint, vet or a
+license scan), tests (test_command with flaky retries), coverage,
+diff_size, forbidden_paths and secrets. Advisory gates report but never
+block. With no gates configured, test_command is the only gate.
+
+Diff-based gates compare --head against --base. --base defaults to
+origin/<target> of the MR, or of the rig. With --mr, the results are
+recorded on the MR bead's gates field.
+
+Tests gates reuse a passing run when the worktree's tree, test command
+and environment fingerprint (merge_queue.test_cache_env) match one
+already tested, e.g. when an MR is retried after an unrelated conflict.
+A worktree with unstaged changes or untracked files is never cached.
+Cached passes show as "(cached)" here and in 'gt mq status'. Only passes
+are cached. Disable with merge_queue.test_cache=false or --no-cache.
+
+When a tests gate's output is a test report (go test -json, TAP, JUnit),
+retries rerun only the failing tests and quarantined tests don't block.
+See 'gt mq flaky'.
+
+On a blocking failure the last line is "failed <failure-type>" and the
+exit code is 1. Failure types: tests_fail, gate_fail, forbidden_path,
+diff_too_large, secret_detected, coverage_low.
+
+Examples:
+  gt mq gates gastown --mr gt-mr-abc123
+  gt mq gates gastown --base origin/main --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMQGates,
+}
+
+func init() {
+	mqGatesCmd.Flags().StringVar(&mqGatesMR, "mr", "", "MR bead to record results on (and take the target from)")
+	mqGatesCmd.Flags().StringVar(&mqGatesBase, "base", "", "Base ref for diff-based gates (default: origin/<target>)")
+	mqGatesCmd.Flags().StringVar(&mqGatesHead, "head", "HEAD", "Ref being merged")
+	mqGatesCmd.Flags().BoolVar(&mqGatesJSON, "json", false, "Output as JSON")
+	mqGatesCmd.Flags().BoolVar(&mqGatesNoCache, "no-cache", false, "Rerun tests even if this tree already passed")
+
+	mqCmd.AddCommand(mqGatesCmd)
+}
+
+// MQGatesOutput is the JSON output of gt mq gates.
+type MQGatesOutput struct {
+	Passed  bool                  `json:"passed"`
+	Failure string                `json:"failure,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Gates   []refinery.GateResult `json:"gates"`
+}
+
+func runMQGates(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return err
+	}
+
+	if mqGatesNoCache {
+		eng.Config().TestCache = false
+	}
+
+	b := beads.New(r.BeadsPath())
+	target := eng.Config().TargetBranch
+	var traceParent string
+	if mqGatesMR != "" {
+		issue, err := b.Show(mqGatesMR)
+		if err != nil {
+			return fmt.Errorf("merge request '%s' not found", mqGatesMR)
+		}
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			if fields.Target != "" {
+				target = fields.Target
+			}
+			traceParent = fields.TraceParent
+		}
+	}
+	base := mqGatesBase
+	if base == "" {
+		base = "origin/" + target
+	}
+
+	// Progress goes to stderr so --json stays parseable
+	eng.SetOutput(os.Stderr)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Gate spans join the work's trace under one refinery.gates span
+	span := telemetry.StartRemote(traceParent, "refinery.gates",
+		telemetry.String("gt.mr", mqGatesMR), telemetry.String("gt.target", target))
+	ctx = telemetry.ContextWithSpan(ctx, span)
+	results, result, err := eng.CheckGates(ctx, base, mqGatesHead, mqGatesMR)
+	if err != nil {
+		span.End(err)
+		return fmt.Errorf("invalid gates: %w", err)
+	}
+	if result.Success {
+		span.End(nil)
+	} else {
+		span.End(fmt.Errorf("failed %s", result.Failure))
+	}
+
+	if mqGatesMR != "" && len(results) > 0 {
+		if err := refinery.RecordGateResults(b, mqGatesMR, results); err != nil {
+			style.PrintWarning("could not record gate results on %s: %v", mqGatesMR, err)
+		}
+	}
+
+	if mqGatesJSON {
+		out := MQGatesOutput{Passed: result.Success, Failure: string(result.Failure), Error: result.Error, Gates: results}
+		if out.Gates == nil {
+			out.Gates = []refinery.GateResult{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printGateResults(results, result)
+	}
+
+	if !result.Success {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printGateResults(results []refinery.GateResult, result refinery.ProcessResult) {
+	if len(results) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No gates configured"))
+		return
+	}
+	for _, g := range results {
+		took := (time.Duration(g.Duration) * time.Millisecond).Round(100 * time.Millisecond)
+		switch g.Status {
+		case refinery.GatePass:
+			line := fmt.Sprintf("%s %s", style.Bold.Render("✓"), g.Gate)
+			if g.Cached {
+				line += style.Dim.Render(" (cached)")
+			} else if g.Summary != "" {
+				line += style.Dim.Render(" - " + g.Summary)
+			}
+			fmt.Printf("%s %s\n", line, style.Dim.Render("("+took.String()+")"))
+		case refinery.GateWarn:
+			fmt.Printf("%s %s: %s %s\n", style.Warning.Render("⚠"), g.Gate, g.Summary, style.Dim.Render("(advisory)"))
+		default:
+			fmt.Printf("%s %s: %s\n", style.Error.Render("✗"), g.Gate, g.Summary)
+			if g.Output != "" {
+				for _, line := range strings.Split(g.Output, "\n") {
+					fmt.Printf("    %s\n", line)
+				}
+			}
+		}
+	}
+	if !result.Success {
+		if result.Failure != "" {
+			fmt.Printf("failed %s\n", result.Failure)
+		} else {
+			fmt.Printf("failed %s\n", result.Error)
+		}
+	}
+}
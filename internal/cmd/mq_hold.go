@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var mqLandingCmd = &cobra.Command{
+	Use:   "landing <rig> <mr-id>",
+	Short: "Show how a merge request lands (normal, pull_request or atomic)",
+	Long: `Show how the refinery should land a merge request.
+
+Prints one line:
+  normal                        Rebase on origin/<target>, test, merge and push
+  pull_request                  Rebase on origin/<target>, test, then 'gt mq pr'
+  atomic <convoy-id> <base>     Rebase onto <base>, test, then 'gt mq hold'
+
+MRs whose source issue is tracked by an atomic convoy are not pushed by
+the refinery. <base> is the convoy's held tip in this rig when another of
+its MRs is already held here, so the held commits stack and land
+together; otherwise it is origin/<target>. Atomic landing takes
+precedence over the rig's merge_mode.
+
+Example:
+  gt mq landing gastown gt-mr-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQLanding,
+}
+
+var mqHoldCmd = &cobra.Command{
+	Use:   "hold <rig> <mr-id> [ref]",
+	Short: "Park a verified merge request for atomic convoy landing",
+	Long: `Park a verified merge request instead of pushing it.
+
+Used by the refinery for MRs in atomic convoys once tests pass. The
+tested merge result (ref, default HEAD) is saved on a held/<mr-id>
+branch, the MR records it as held_commit, and the MR stays claimed so it
+leaves the ready queue. 'gt convoy land' pushes it together with the
+rest of the convoy, then closes the MR and notifies the witness.
+
+Example:
+  gt mq hold gastown gt-mr-abc123 temp`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runMQHold,
+}
+
+func init() {
+	mqCmd.AddCommand(mqLandingCmd)
+	mqCmd.AddCommand(mqHoldCmd)
+}
+
+func runMQLanding(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	mr, err := beads.New(r.BeadsPath()).Show(args[1])
+	if err != nil {
+		return fmt.Errorf("merge request '%s' not found", args[1])
+	}
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		return fmt.Errorf("%s has no MR fields", mr.ID)
+	}
+
+	convoyID := atomicConvoyFor(fields.SourceIssue)
+	if convoyID == "" {
+		eng := refinery.NewEngineer(r)
+		if err := eng.LoadConfig(); err != nil {
+			return err
+		}
+		if eng.Config().MergeMode == refinery.MergeModePullRequest {
+			fmt.Println("pull_request")
+		} else {
+			fmt.Println("normal")
+		}
+		return nil
+	}
+
+	// Stack on the convoy's MRs already held in this rig
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	siblings := make(map[string]bool)
+	for _, t := range getTrackedIssues(filepath.Join(townRoot, ".beads"), convoyID) {
+		if t.ID != fields.SourceIssue {
+			siblings[t.ID] = true
+		}
+	}
+	eng := refinery.NewEngineer(r)
+	held, err := eng.ListMRsForIssues(siblings)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("atomic %s %s\n", convoyID, eng.HeldBase(held, fields.Target))
+	return nil
+}
+
+func runMQHold(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	ref := "HEAD"
+	if len(args) > 2 {
+		ref = args[2]
+	}
+
+	sha, err := refinery.NewEngineer(r).HoldMR(args[1], ref)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Held %s at %s (%s)\n", style.Bold.Render("✓"), args[1], shortSHA(sha), refinery.HeldBranch(args[1]))
+	fmt.Printf("  %s\n", style.Dim.Render("Lands with its convoy via 'gt convoy land'"))
+	return nil
+}
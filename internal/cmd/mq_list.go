@@ -145,7 +145,9 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		// Determine display status
 		displayStatus := issue.Status
 		if issue.Status == "open" {
-			if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
+			if fields != nil && fields.HeldCommit != "" {
+				displayStatus = "held"
+			} else if len(issue.BlockedBy) > 0 || issue.BlockedByCount > 0 {
 				displayStatus = "blocked"
 			} else {
 				displayStatus = "ready"
@@ -161,6 +163,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 			styledStatus = style.Warning.Render("active")
 		case "blocked":
 			styledStatus = style.Dim.Render("blocked")
+		case "held":
+			styledStatus = style.Warning.Render("held")
 		case "closed":
 			styledStatus = style.Dim.Render("closed")
 		}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqMergedCmd = &cobra.Command{
+	Use:   "merged <rig> <mr-id> [ref]",
+	Short: "Record a pushed merge request as merged and close it",
+	Long: `Record that the refinery merged and pushed a merge request.
+
+Used by the refinery once the merge is on the target branch. Records ref
+(default HEAD; pass the sha 'gt mq pr' printed in pull_request mode) as
+the MR's merge_commit, sets close_reason to merged, and closes the MR
+bead with "Merged to <target> at <sha>".
+
+When tracing is on, this ends the work's trace with a refinery.merge
+span under the trace the MR was submitted in.
+
+Example:
+  gt mq merged gastown gt-mr-abc123`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runMQMerged,
+}
+
+func init() {
+	mqCmd.AddCommand(mqMergedCmd)
+}
+
+func runMQMerged(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	ref := "HEAD"
+	if len(args) > 2 {
+		ref = args[2]
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return err
+	}
+	_, reason, err := eng.MarkMerged(args[1], ref)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: %s\n", style.Bold.Render("✓"), args[1], reason)
+	return nil
+}
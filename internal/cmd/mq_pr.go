@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+var mqPRCmd = &cobra.Command{
+	Use:   "pr <rig> <mr-id> [ref]",
+	Short: "Land a merge request through a forge pull request",
+	Long: `Land a verified merge request through the rig's forge.
+
+Used by the refinery in merge_mode "pull_request" once the branch is
+rebased and tested. Pushes ref (default: the MR branch) as the MR
+branch, opens (or updates) its pull request, waits for the forge's checks and required reviews, and merges
+it via the API when green.
+
+Prints one line:
+  merged <sha>          Merged; continue with the post-merge steps
+  waiting <reason>      Still pending after pr_timeout; leave the MR queued
+  failed <type> <why>   conflict, tests_fail, rejected or push_fail
+
+The forge is configured under merge_queue in the rig's config.json:
+  "merge_mode": "pull_request",
+  "forge": {"type": "gitea", "url": "https://git.example.com/api/v1"},
+  "pr_merge_method": "squash",
+  "pr_timeout": "10m"
+
+Type, URL and repo are derived from the origin remote when unset (GitHub
+and GitLab hosts only). The API token is read from GITHUB_TOKEN/GH_TOKEN,
+GITLAB_TOKEN or GITEA_TOKEN, or the variable named by forge.token_env.
+
+Example:
+  gt mq pr gastown gt-mr-abc123 temp`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runMQPR,
+}
+
+func init() {
+	mqCmd.AddCommand(mqPRCmd)
+}
+
+func runMQPR(cmd *cobra.Command, args []string) error {
+	_, r, _, err := getRefineryManager(args[0])
+	if err != nil {
+		return err
+	}
+	issue, err := beads.New(r.BeadsPath()).Show(args[1])
+	if err != nil {
+		return fmt.Errorf("merge request '%s' not found", args[1])
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil || fields.Branch == "" {
+		return fmt.Errorf("%s has no branch field", issue.ID)
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return err
+	}
+	eng.SetOutput(os.Stderr)
+	target := fields.Target
+	if target == "" {
+		target = eng.Config().TargetBranch
+	}
+
+	ref := ""
+	if len(args) > 2 {
+		ref = args[2]
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	span := telemetry.StartRemote(fields.TraceParent, "refinery.pr",
+		telemetry.String("gt.mr", issue.ID), telemetry.String("gt.target", target))
+	result := eng.LandViaPR(telemetry.ContextWithSpan(ctx, span), &refinery.MRInfo{
+		ID:          issue.ID,
+		Branch:      fields.Branch,
+		Target:      target,
+		SourceIssue: fields.SourceIssue,
+		TraceParent: fields.TraceParent,
+	}, ref)
+	span.SetAttributes(telemetry.Bool("gt.pending", result.Pending))
+	if result.Success || result.Pending {
+		span.End(nil)
+	} else {
+		span.End(fmt.Errorf("%s: %s", result.Failure, result.Error))
+	}
+
+	switch {
+	case result.Success:
+		fmt.Printf("merged %s\n", result.MergeCommit)
+	case result.Pending:
+		fmt.Printf("waiting %s\n", result.Error)
+	default:
+		if result.Failure == "" {
+			// Not the MR's fault (config, auth, forge outage): retry later
+			return fmt.Errorf("landing %s: %s", issue.ID, result.Error)
+		}
+		fmt.Printf("failed %s %s\n", result.Failure, result.Error)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -33,6 +34,11 @@ type MRStatusOutput struct {
 	Rig         string `json:"rig,omitempty"`
 	MergeCommit string `json:"merge_commit,omitempty"`
 	CloseReason string `json:"close_reason,omitempty"`
+	HeldCommit  string `json:"held_commit,omitempty"`
+	HeldAt      string `json:"held_at,omitempty"`
+
+	// Pre-merge gate results from the last refinery run
+	Gates []refinery.GateResult `json:"gates,omitempty"`
 
 	// Dependencies
 	DependsOn []DependencyInfo `json:"depends_on,omitempty"`
@@ -95,6 +101,9 @@ func runMqStatus(cmd *cobra.Command, args []string) error {
 		output.Rig = mrFields.Rig
 		output.MergeCommit = mrFields.MergeCommit
 		output.CloseReason = mrFields.CloseReason
+		output.HeldCommit = mrFields.HeldCommit
+		output.HeldAt = mrFields.HeldAt
+		output.Gates, _ = refinery.ParseGateResults(mrFields.Gates)
 	}
 
 	// Add dependency info from the issue's Dependencies field
@@ -184,6 +193,24 @@ func printMqStatus(issue *beads.Issue, mrFields *beads.MRFields) error {
 		if mrFields.CloseReason != "" {
 			fmt.Printf("   Close Reason: %s\n", mrFields.CloseReason)
 		}
+		if mrFields.HeldCommit != "" {
+			fmt.Printf("   Held:         %s since %s %s\n", shortSHA(mrFields.HeldCommit), mrFields.HeldAt,
+				style.Dim.Render("(awaiting atomic convoy landing)"))
+		}
+		if gates, _ := refinery.ParseGateResults(mrFields.Gates); len(gates) > 0 {
+			var parts []string
+			for _, g := range gates {
+				part := g.Gate + " " + string(g.Status)
+				if g.Status != refinery.GatePass && g.Failure != "" {
+					part += " (" + string(g.Failure) + ")"
+				}
+				if g.Cached {
+					part += " (cached)"
+				}
+				parts = append(parts, part)
+			}
+			fmt.Printf("   Gates:        %s\n", strings.Join(parts, ", "))
+		}
 	}
 
 	// Dependencies (what this MR is waiting on)
@@ -1,12 +1,11 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -27,71 +26,34 @@ type PatrolConfig struct {
 // findActivePatrol finds an active patrol molecule for the role.
 // Returns the patrol ID, display line, and whether one was found.
 func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool) {
+	bd := beads.New(cfg.BeadsDir)
+
 	// Check for in-progress patrol first (if configured)
 	if cfg.CheckInProgress {
-		cmdList := exec.Command("bd", "--no-daemon", "list", "--status=in_progress", "--type=epic")
-		cmdList.Dir = cfg.BeadsDir
-		var stdoutList, stderrList bytes.Buffer
-		cmdList.Stdout = &stdoutList
-		cmdList.Stderr = &stderrList
-
-		if err := cmdList.Run(); err != nil {
-			if errMsg := strings.TrimSpace(stderrList.String()); errMsg != "" {
-				fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
-			}
-		} else {
-			lines := strings.Split(stdoutList.String(), "\n")
-			for _, line := range lines {
-				if strings.Contains(line, cfg.PatrolMolName) && !strings.Contains(line, "[template]") {
-					parts := strings.Fields(line)
-					if len(parts) > 0 {
-						return parts[0], line, true
-					}
-				}
-			}
+		patrols, err := listPatrols(bd, cfg, "in_progress")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bd list: %v\n", err)
+		} else if len(patrols) > 0 {
+			return patrols[0].ID, patrolDisplayLine(patrols[0]), true
 		}
 	}
 
 	// Check for open patrols with open children (active wisp)
-	cmdOpen := exec.Command("bd", "--no-daemon", "list", "--status=open", "--type=epic")
-	cmdOpen.Dir = cfg.BeadsDir
-	var stdoutOpen, stderrOpen bytes.Buffer
-	cmdOpen.Stdout = &stdoutOpen
-	cmdOpen.Stderr = &stderrOpen
-
-	if err := cmdOpen.Run(); err != nil {
-		if errMsg := strings.TrimSpace(stderrOpen.String()); errMsg != "" {
-			fmt.Fprintf(os.Stderr, "bd list: %s\n", errMsg)
+	patrols, err := listPatrols(bd, cfg, "open")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bd list: %v\n", err)
+		return "", "", false
+	}
+	for _, patrol := range patrols {
+		mol, err := bd.Show(patrol.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bd show: %v\n", err)
+			continue
 		}
-	} else {
-		lines := strings.Split(stdoutOpen.String(), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, cfg.PatrolMolName) && !strings.Contains(line, "[template]") {
-				parts := strings.Fields(line)
-				if len(parts) > 0 {
-					molID := parts[0]
-					// Check if this molecule has open children
-					cmdShow := exec.Command("bd", "--no-daemon", "show", molID)
-					cmdShow.Dir = cfg.BeadsDir
-					var stdoutShow, stderrShow bytes.Buffer
-					cmdShow.Stdout = &stdoutShow
-					cmdShow.Stderr = &stderrShow
-					if err := cmdShow.Run(); err != nil {
-						if errMsg := strings.TrimSpace(stderrShow.String()); errMsg != "" {
-							fmt.Fprintf(os.Stderr, "bd show: %s\n", errMsg)
-						}
-					} else {
-						showOutput := stdoutShow.String()
-						// Deacon only checks "- open]", witness/refinery also check "- in_progress]"
-						hasOpenChildren := strings.Contains(showOutput, "- open]")
-						if cfg.CheckInProgress {
-							hasOpenChildren = hasOpenChildren || strings.Contains(showOutput, "- in_progress]")
-						}
-						if hasOpenChildren {
-							return molID, line, true
-						}
-					}
-				}
+		// Deacon only checks open children, witness/refinery also check in_progress
+		for _, child := range mol.Dependents {
+			if child.Status == "open" || (cfg.CheckInProgress && child.Status == "in_progress") {
+				return patrol.ID, patrolDisplayLine(patrol), true
 			}
 		}
 	}
@@ -99,76 +61,45 @@ func findActivePatrol(cfg PatrolConfig) (patrolID, patrolLine string, found bool
 	return "", "", false
 }
 
-// autoSpawnPatrol creates and pins a new patrol wisp.
-// Returns the patrol ID or an error.
-func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
-	// Find the proto ID for the patrol molecule
-	cmdCatalog := exec.Command("bd", "--no-daemon", "mol", "catalog")
-	cmdCatalog.Dir = cfg.BeadsDir
-	var stdoutCatalog, stderrCatalog bytes.Buffer
-	cmdCatalog.Stdout = &stdoutCatalog
-	cmdCatalog.Stderr = &stderrCatalog
-
-	if err := cmdCatalog.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderrCatalog.String())
-		if errMsg != "" {
-			return "", fmt.Errorf("failed to list molecule catalog: %s", errMsg)
-		}
-		return "", fmt.Errorf("failed to list molecule catalog: %w", err)
+// listPatrols returns the role's patrol epics with the given status,
+// skipping templates.
+func listPatrols(bd *beads.Beads, cfg PatrolConfig, status string) ([]*beads.Issue, error) {
+	epics, err := bd.List(beads.ListOptions{Status: status, IssueType: "epic", Priority: -1})
+	if err != nil {
+		return nil, err
 	}
-
-	// Find patrol molecule in catalog
-	var protoID string
-	catalogLines := strings.Split(stdoutCatalog.String(), "\n")
-	for _, line := range catalogLines {
-		if strings.Contains(line, cfg.PatrolMolName) {
-			parts := strings.Fields(line)
-			if len(parts) > 0 {
-				// Strip trailing colon from ID (catalog format: "gt-xxx: title")
-				protoID = strings.TrimSuffix(parts[0], ":")
-				break
-			}
+	var patrols []*beads.Issue
+	for _, epic := range epics {
+		if strings.Contains(epic.Title, cfg.PatrolMolName) && !epic.IsTemplate {
+			patrols = append(patrols, epic)
 		}
 	}
+	return patrols, nil
+}
 
-	if protoID == "" {
-		return "", fmt.Errorf("proto %s not found in catalog", cfg.PatrolMolName)
-	}
+func patrolDisplayLine(patrol *beads.Issue) string {
+	return fmt.Sprintf("%s [%s] %s", patrol.ID, patrol.Status, patrol.Title)
+}
 
-	// Create the patrol wisp
-	cmdSpawn := exec.Command("bd", "--no-daemon", "mol", "wisp", "create", protoID, "--actor", cfg.RoleName)
-	cmdSpawn.Dir = cfg.BeadsDir
-	var stdoutSpawn, stderrSpawn bytes.Buffer
-	cmdSpawn.Stdout = &stdoutSpawn
-	cmdSpawn.Stderr = &stderrSpawn
-
-	if err := cmdSpawn.Run(); err != nil {
-		return "", fmt.Errorf("failed to create patrol wisp: %s", stderrSpawn.String())
-	}
+// autoSpawnPatrol creates and pins a new patrol wisp.
+// Returns the patrol ID or an error.
+func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
+	bd := beads.New(cfg.BeadsDir)
 
-	// Parse the created molecule ID from output
-	var patrolID string
-	spawnOutput := stdoutSpawn.String()
-	for _, line := range strings.Split(spawnOutput, "\n") {
-		if strings.Contains(line, "Root issue:") || strings.Contains(line, "Created") {
-			parts := strings.Fields(line)
-			for _, p := range parts {
-				if strings.HasPrefix(p, "wisp-") || strings.HasPrefix(p, "gt-") {
-					patrolID = p
-					break
-				}
-			}
-		}
+	// Find the proto ID for the patrol molecule
+	protoID, err := bd.FindProto(cfg.PatrolMolName)
+	if err != nil {
+		return "", err
 	}
 
-	if patrolID == "" {
-		return "", fmt.Errorf("created wisp but could not parse ID from output")
+	// Create the patrol wisp
+	patrolID, err := bd.SpawnWisp(protoID, cfg.RoleName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create patrol wisp: %w", err)
 	}
 
 	// Hook the wisp to the agent so gt mol status sees it
-	cmdPin := exec.Command("bd", "--no-daemon", "update", patrolID, "--status=hooked", "--assignee="+cfg.Assignee)
-	cmdPin.Dir = cfg.BeadsDir
-	if err := cmdPin.Run(); err != nil {
+	if err := bd.Hook(patrolID, cfg.Assignee); err != nil {
 		return patrolID, fmt.Errorf("created wisp %s but failed to hook", patrolID)
 	}
 
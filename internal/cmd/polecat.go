@@ -15,7 +15,6 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
-	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -572,22 +571,15 @@ func runPolecatSync(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		// Build sync command
-		syncArgs := []string{"sync"}
-		if polecatSyncFromMain {
-			syncArgs = append(syncArgs, "--from-main")
-		}
-
 		fmt.Printf("Syncing %s/%s...\n", rigName, name)
 
-		syncCmd := exec.Command("bd", syncArgs...)
-		syncCmd.Dir = p.ClonePath
-		output, err := syncCmd.CombinedOutput()
-		if err != nil {
+		bd := beads.New(p.ClonePath)
+		sync := bd.Sync
+		if polecatSyncFromMain {
+			sync = bd.SyncFromMain
+		}
+		if err := sync(); err != nil {
 			syncErrors = append(syncErrors, fmt.Sprintf("%s: %v", name, err))
-			if len(output) > 0 {
-				fmt.Printf("  %s\n", style.Dim.Render(string(output)))
-			}
 		} else {
 			fmt.Printf("  %s\n", style.Success.Render("✓ synced"))
 		}
@@ -1215,13 +1207,7 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 
 		// Step 5: Close agent bead (if exists)
 		agentBeadID := beads.PolecatBeadID(p.rigName, p.polecatName)
-		closeArgs := []string{"close", agentBeadID, "--reason=nuked"}
-		if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-			closeArgs = append(closeArgs, "--session="+sessionID)
-		}
-		closeCmd := exec.Command("bd", closeArgs...)
-		closeCmd.Dir = filepath.Join(p.r.Path, "mayor", "rig")
-		if err := closeCmd.Run(); err != nil {
+		if err := beads.New(filepath.Join(p.r.Path, "mayor", "rig")).CloseWithReason("nuked", agentBeadID); err != nil {
 			// Non-fatal - agent bead might not exist
 			fmt.Printf("  %s agent bead not found or already closed\n", style.Dim.Render("○"))
 		} else {
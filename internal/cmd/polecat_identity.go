@@ -799,26 +799,24 @@ type IssueInfo struct {
 
 // queryAssignedIssues queries beads for issues assigned to a specific agent.
 func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error) {
-	// Use bd list with filters
-	args := []string{"list", "--assignee=" + assignee, "--json"}
-	if status != "" {
-		args = append(args, "--status="+status)
-	}
-
-	cmd := exec.Command("bd", args...)
-	cmd.Dir = rigPath
-	out, err := cmd.Output()
+	list, err := beads.New(rigPath).List(beads.ListOptions{
+		Assignee: assignee,
+		Status:   status,
+		Priority: -1,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(out) == 0 {
-		return []IssueInfo{}, nil
-	}
-
-	var issues []IssueInfo
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, err
+	issues := make([]IssueInfo, 0, len(list))
+	for _, issue := range list {
+		issues = append(issues, IssueInfo{
+			ID:      issue.ID,
+			Title:   issue.Title,
+			Type:    issue.Type,
+			Status:  issue.Status,
+			Updated: issue.UpdatedAt,
+		})
 	}
 
 	// Sort by updated date (most recent first)
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -15,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -194,9 +194,11 @@ func runPrime(cmd *cobra.Command, args []string) error {
 		ensureBeadsRedirect(ctx)
 	}
 
-	// Emit session_start event for seance discovery
+	// Emit session_start event for seance discovery, and start recording
+	// the session's pane if the town records this role
 	if !primeDryRun {
 		emitSessionEvent(ctx)
+		startSessionRecording(ctx)
 	}
 
 	// Output session metadata for seance discovery
@@ -347,23 +349,14 @@ func detectRole(cwd, townRoot string) RoleInfo {
 // runBdPrime runs `bd prime` and outputs the result.
 // This provides beads workflow context to the agent.
 func runBdPrime(workDir string) {
-	cmd := exec.Command("bd", "prime")
-	cmd.Dir = workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	output, err := beads.New(workDir).Prime()
+	if err != nil {
 		// Skip if bd prime fails (beads might not be available)
-		// But log stderr if present for debugging
-		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
-			fmt.Fprintf(os.Stderr, "bd prime: %s\n", errMsg)
-		}
+		// But log the error for debugging
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
 	}
 
-	output := strings.TrimSpace(stdout.String())
 	if output != "" {
 		fmt.Println()
 		fmt.Println(output)
@@ -433,6 +426,11 @@ func checkSlungWork(ctx RoleContext) bool {
 
 	// Use the first hooked bead (agents typically have one)
 	hookedBead := hookedBeads[0]
+	tagSessionRecording(ctx, hookedBead.ID)
+
+	span := telemetry.StartRemote(workTraceParent(hookedBead), "agent.prime",
+		telemetry.String("gt.bead", hookedBead.ID), telemetry.String("gt.agent", agentID))
+	defer span.End(nil)
 
 	// Build the role announcement string
 	roleAnnounce := buildRoleAnnouncement(ctx)
@@ -482,18 +480,10 @@ func checkSlungWork(ctx RoleContext) bool {
 
 	// Show bead preview using bd show
 	fmt.Println("**Bead details:**")
-	cmd := exec.Command("bd", "show", hookedBead.ID)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
-			fmt.Fprintf(os.Stderr, "  bd show %s: %s\n", hookedBead.ID, errMsg)
-		} else {
-			fmt.Fprintf(os.Stderr, "  bd show %s: %v\n", hookedBead.ID, err)
-		}
+	if out, err := beads.NewRouted("").Run("show", hookedBead.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
 	} else {
-		lines := strings.Split(stdout.String(), "\n")
+		lines := strings.Split(string(out), "\n")
 		maxLines := 15
 		if len(lines) > maxLines {
 			lines = lines[:maxLines]
@@ -677,30 +667,9 @@ func ensureBeadsRedirect(ctx RoleContext) {
 // checkPendingEscalations queries for open escalation beads and displays them prominently.
 // This is called on Mayor startup to surface issues needing human attention.
 func checkPendingEscalations(ctx RoleContext) {
-	// Query for open escalations using bd list with tag filter
-	cmd := exec.Command("bd", "list", "--status=open", "--tag=escalation", "--json")
-	cmd.Dir = ctx.WorkDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		// Silently skip - escalation check is best-effort
-		return
-	}
-
-	// Parse JSON output
-	var escalations []struct {
-		ID          string `json:"id"`
-		Title       string `json:"title"`
-		Priority    int    `json:"priority"`
-		Description string `json:"description"`
-		Created     string `json:"created"`
-	}
-
-	if err := json.Unmarshal(stdout.Bytes(), &escalations); err != nil || len(escalations) == 0 {
-		// No escalations or parse error
+	// Silently skip on error - escalation check is best-effort
+	escalations, err := beads.New(ctx.WorkDir).ListEscalations()
+	if err != nil || len(escalations) == 0 {
 		return
 	}
 
@@ -756,7 +725,7 @@ func checkPendingEscalations(ctx RoleContext) {
 	}
 	fmt.Println()
 
-	fmt.Println("**Action required:** Review escalations with `bd list --tag=escalation`")
+	fmt.Println("**Action required:** Review escalations with `bd list --label=gt:escalation`")
 	fmt.Println("Close resolved ones with `bd close <id> --reason \"resolution\"`")
 	fmt.Println()
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/retention"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// recordingEnv is the tmux session variable holding the ID of the
+// recording its pane is piped to.
+const recordingEnv = "GT_RECORDING"
+
+// sessionRecordingID is the recording of this agent's pane, set by
+// startSessionRecording. Beads found on the hook are added to its index.
+var sessionRecordingID string
+
+// startSessionRecording pipes the agent's tmux pane into a recording when
+// the town has recording enabled for its role. A pane that is already
+// being recorded (gt prime re-run after compaction or handoff) keeps its
+// recording, which is tagged with the new agent session instead.
+func startSessionRecording(ctx RoleContext) {
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" || ctx.Role == RoleUnknown || ctx.TownRoot == "" {
+		return
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil || !recordingEnabled(settings.Recording, string(ctx.Role)) {
+		return
+	}
+	actor := getAgentIdentity(ctx)
+	if actor == "" {
+		return
+	}
+	sessionID := resolveSessionIDForPrime(actor)
+
+	t := tmux.NewTmux()
+	info, err := t.GetPaneInfo(pane)
+	if err != nil {
+		return
+	}
+	if info.Piped {
+		// Don't take over a pipe someone else set up
+		id, err := t.GetEnvironment(info.Session, recordingEnv)
+		if err != nil || id == "" {
+			return
+		}
+		sessionRecordingID = id
+		_ = recording.Append(ctx.TownRoot, recording.Recording{ID: id, SessionIDs: []string{sessionID}})
+		return
+	}
+
+	rec := recording.New(ctx.TownRoot, info.Session, actor, time.Now())
+	rec.SessionIDs = []string{sessionID}
+	if err := recording.Append(ctx.TownRoot, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "%s could not index session recording: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+
+	// The pipe command runs under the tmux server, whose PATH may not
+	// include gt
+	gtPath, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s could not start session recording: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+	command := fmt.Sprintf("%s replay recorder --width %d --height %d --title %s",
+		shellSingleQuote(gtPath), info.Width, info.Height, shellSingleQuote(rec.ID))
+	if p, err := retention.PolicyFor(settings, retention.SourceRecordings); err == nil && p.Compress {
+		command += " --gzip"
+	}
+	command += " " + shellSingleQuote(rec.Path)
+	if err := t.PipePane(pane, command); err != nil {
+		fmt.Fprintf(os.Stderr, "%s could not start session recording: %v\n", style.Warning.Render("⚠"), err)
+		return
+	}
+	_ = t.SetEnvironment(info.Session, recordingEnv, rec.ID)
+	sessionRecordingID = rec.ID
+}
+
+// tagSessionRecording indexes this session's recording under a bead.
+func tagSessionRecording(ctx RoleContext, beadID string) {
+	if sessionRecordingID == "" {
+		return
+	}
+	_ = recording.Append(ctx.TownRoot, recording.Recording{ID: sessionRecordingID, Beads: []string{beadID}})
+}
+
+// recordingEnabled reports whether a role's sessions are recorded.
+func recordingEnabled(cfg *config.RecordingConfig, role string) bool {
+	if cfg == nil || !cfg.Enabled {
+		return false
+	}
+	if len(cfg.Roles) == 0 {
+		return true
+	}
+	for _, r := range cfg.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// shellSingleQuote quotes s for a POSIX shell.
+func shellSingleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
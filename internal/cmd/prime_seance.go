@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/retention"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/wisp"
 )
@@ -230,7 +231,8 @@ const maxEventsToScan = 10000
 func checkColdRig(townRoot, rigName string, threshold time.Duration) (bool, time.Time) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
 
-	file, err := os.Open(eventsPath)
+	// Rotated segments older than the threshold can't hold recent activity
+	file, err := retention.OpenSince(eventsPath, time.Now().Add(-threshold))
 	if err != nil {
 		// No events file means new rig - definitely cold
 		return true, time.Time{}
@@ -370,7 +372,7 @@ const maxSessionsToTrack = 100
 func findPredecessorSession(townRoot, rigName, currentSessionID string, minAge time.Duration) *seanceEvent {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
 
-	file, err := os.Open(eventsPath)
+	file, err := retention.Open(eventsPath)
 	if err != nil {
 		return nil
 	}
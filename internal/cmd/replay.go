@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/logs"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/retention"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Replay command flags
+var (
+	replaySpeed     float64
+	replayIdleLimit time.Duration
+	replayFrom      string
+	replaySearch    string
+	replayList      bool
+
+	replayRecorderWidth  int
+	replayRecorderHeight int
+	replayRecorderTitle  string
+	replayRecorderGzip   bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:     "replay [session|bead|agent]",
+	GroupID: GroupDiag,
+	Short:   "Replay recorded agent sessions",
+	Long: `Play back a recording of an agent's tmux pane in the terminal.
+
+When recording is enabled, gt prime pipes each agent's pane into an
+asciicast v2 file (logs/recordings/<id>.cast), so the full session can
+be watched after the fact, not just the last lines CapturePane keeps.
+Enable it in settings/config.json:
+
+  "recording": {"enabled": true, "roles": ["polecat", "crew"]}
+
+Recordings are kept per the "recordings" retention policy (14 days by
+default) and can also be played with any asciicast player (asciinema).
+
+A recording is found by its ID, its tmux session (gt-gastown-Toast), an
+agent session ID (as listed by gt seance), the agent's address, or a bead
+the agent worked on. When several match, the latest is played.
+
+With --search, the recording is not played; the lines of its output
+containing the text are listed with their time offsets, for use with
+--from.
+
+Examples:
+  gt replay                              # List recordings
+  gt replay gt-abc                       # Watch the session that worked a bead
+  gt replay gastown/polecats/Toast -l    # List an agent's recordings
+  gt replay gt-gastown-Toast --speed 4   # Four times as fast
+  gt replay gt-abc --search "rm -rf"     # Find where something happened
+  gt replay gt-abc --from 12:30          # Start 12.5 minutes in`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReplay,
+}
+
+var replayRecorderCmd = &cobra.Command{
+	Use:    "recorder <file>",
+	Short:  "Record stdin as an asciicast file",
+	Hidden: true, // Internal command run by tmux pipe-pane
+	Args:   cobra.ExactArgs(1),
+	RunE:   runReplayRecorder,
+}
+
+func init() {
+	replayCmd.Flags().Float64VarP(&replaySpeed, "speed", "s", 1, "Playback speed multiplier")
+	replayCmd.Flags().DurationVarP(&replayIdleLimit, "idle-limit", "i", 2*time.Second, "Cap pauses between output (0 = as recorded)")
+	replayCmd.Flags().StringVar(&replayFrom, "from", "", "Start at an offset (90s, 1m30s or 1:30)")
+	replayCmd.Flags().StringVar(&replaySearch, "search", "", "List output lines containing text instead of playing")
+	replayCmd.Flags().BoolVarP(&replayList, "list", "l", false, "List matching recordings instead of playing")
+
+	replayRecorderCmd.Flags().IntVar(&replayRecorderWidth, "width", 80, "Terminal width")
+	replayRecorderCmd.Flags().IntVar(&replayRecorderHeight, "height", 24, "Terminal height")
+	replayRecorderCmd.Flags().StringVar(&replayRecorderTitle, "title", "", "Recording title")
+	replayRecorderCmd.Flags().BoolVar(&replayRecorderGzip, "gzip", false, "Gzip the recording when it ends")
+
+	replayCmd.AddCommand(replayRecorderCmd)
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	recs, err := recording.List(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading recordings: %w", err)
+	}
+
+	if len(args) == 0 {
+		printRecordings(recs)
+		return nil
+	}
+	matches, err := findRecordings(townRoot, recs, args[0])
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no recording found for %q (is recording enabled? see 'gt replay --help')", args[0])
+	}
+
+	switch {
+	case replayList:
+		printRecordings(matches)
+		return nil
+	case replaySearch != "":
+		return searchRecordings(matches, replaySearch)
+	}
+
+	from, err := parseReplayOffset(replayFrom)
+	if err != nil {
+		return err
+	}
+	rec := matches[len(matches)-1]
+	if len(matches) > 1 {
+		fmt.Printf("%s %d recordings match; playing the latest (list them with -l)\n",
+			style.Dim.Render("○"), len(matches))
+	}
+	return playRecording(rec, from)
+}
+
+// findRecordings returns the recordings a query names. A bead that was
+// never on the hook at prime (slung to a running session) is found from
+// its events: the recordings of the agents it was slung or hooked to,
+// running at the time.
+func findRecordings(townRoot string, recs []recording.Recording, query string) ([]recording.Recording, error) {
+	var matches []recording.Recording
+	for _, r := range recs {
+		if r.Matches(query) {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) > 0 {
+		return matches, nil
+	}
+
+	sources, err := logs.SelectSources(logs.Sources(townRoot), []string{logs.SourceEvents})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := logs.Query(sources, logs.Filter{Beads: []string{query}}, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		agent := e.Actor
+		if target, ok := e.Payload["target"].(string); ok && e.Type == "sling" {
+			agent = target
+		}
+		for _, r := range recs {
+			if seen[r.ID] || !recording.SameAgent(agent, r.Actor) {
+				continue
+			}
+			// A slung polecat's session starts just after the sling
+			spawned := r.Started.After(e.Time) && r.Started.Sub(e.Time) < 5*time.Minute
+			if r.Covers(e.Time) || spawned {
+				seen[r.ID] = true
+				matches = append(matches, r)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func printRecordings(recs []recording.Recording) {
+	if len(recs) == 0 {
+		fmt.Printf("%s No recordings\n", style.Dim.Render("○"))
+		return
+	}
+	for _, r := range recs {
+		duration := r.LastWrite.Sub(r.Started).Round(time.Second)
+		fmt.Printf("%s  %s  %s\n", style.Bold.Render(r.ID),
+			r.Started.Local().Format("2006-01-02 15:04"), style.Dim.Render(duration.String()))
+		var details []string
+		if r.Actor != "" {
+			details = append(details, r.Actor)
+		}
+		if len(r.Beads) > 0 {
+			details = append(details, "beads: "+strings.Join(r.Beads, ", "))
+		}
+		if len(r.SessionIDs) > 0 {
+			details = append(details, "sessions: "+strings.Join(r.SessionIDs, ", "))
+		}
+		if len(details) > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render(strings.Join(details, "  ")))
+		}
+	}
+}
+
+func searchRecordings(recs []recording.Recording, query string) error {
+	total := 0
+	for _, r := range recs {
+		matches, err := searchRecording(r, query)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.ID, err)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		total += len(matches)
+		fmt.Printf("%s\n", style.Bold.Render(r.ID))
+		for _, m := range matches {
+			fmt.Printf("  %s  %s\n", style.Dim.Render(formatReplayOffset(m.Time)), m.Line)
+		}
+	}
+	if total == 0 {
+		fmt.Printf("%s No output matching %q\n", style.Dim.Render("○"), query)
+	}
+	return nil
+}
+
+func searchRecording(r recording.Recording, query string) ([]recording.Match, error) {
+	f, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	d, err := recording.NewDecoder(f)
+	if err != nil {
+		return nil, err
+	}
+	return recording.Search(d, query)
+}
+
+func playRecording(r recording.Recording, from time.Duration) error {
+	f, err := r.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	d, err := recording.NewDecoder(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.ID, err)
+	}
+
+	fmt.Printf("%s %s  %s  %s\n", style.Bold.Render("▶"), r.ID,
+		r.Started.Local().Format("2006-01-02 15:04:05"), style.Dim.Render("Ctrl-C to stop"))
+	if d.Header.Width > 0 {
+		fmt.Println(style.Dim.Render(fmt.Sprintf("  Recorded at %dx%d", d.Header.Width, d.Header.Height)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = recording.Play(ctx, d, os.Stdout, recording.PlayOptions{
+		Speed:     replaySpeed,
+		IdleLimit: replayIdleLimit,
+		From:      from,
+	})
+	// Undo any attributes the recording left set
+	fmt.Printf("\x1b[0m\n%s\n", style.Dim.Render("■ End of replay"))
+	return err
+}
+
+// parseReplayOffset parses a duration (90s, 1m30s) or a clock offset
+// (1:30, 1:02:30) as printed by --search.
+func parseReplayOffset(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if !strings.Contains(s, ":") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --from %q: %w", s, err)
+		}
+		return d, nil
+	}
+	var d time.Duration
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --from %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, nil
+}
+
+// formatReplayOffset formats an offset as h:mm:ss or m:ss.
+func formatReplayOffset(d time.Duration) string {
+	secs := int(d / time.Second)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// runReplayRecorder writes the pane output tmux pipes to stdin into an
+// asciicast file, until the pane closes the pipe.
+func runReplayRecorder(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	// Recordings may show secrets an agent printed; keep them private
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) //nolint:gosec // G304: path is chosen by gt prime
+	if err != nil {
+		return err
+	}
+	env := map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")}
+	out := &stoppableWriter{w: f}
+	w, err := recording.NewWriter(out, recording.Header{
+		Width:     replayRecorderWidth,
+		Height:    replayRecorderHeight,
+		Timestamp: time.Now().Unix(),
+		Title:     replayRecorderTitle,
+		Env:       env,
+	})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	// tmux closes the pipe when the pane exits or is piped elsewhere; a
+	// signal ends the recording early but still finishes the file. Record
+	// may be blocked reading stdin then, so its writes are stopped rather
+	// than waited for: the file ends at the last whole event.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGHUP, os.Interrupt)
+	done := make(chan error, 1)
+	go func() { done <- w.Record(os.Stdin) }()
+	select {
+	case err = <-done:
+	case <-sigs:
+		out.stop()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && replayRecorderGzip {
+		_, err = retention.Compress(path)
+	}
+	return err
+}
+
+// stoppableWriter passes writes through until stopped, after which it
+// refuses them. Each write completes before stop returns.
+type stoppableWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	stopped bool
+}
+
+func (s *stoppableWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return 0, os.ErrClosed
+	}
+	return s.w.Write(p)
+}
+
+func (s *stoppableWriter) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+}
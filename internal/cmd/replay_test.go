@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/recording"
+)
+
+func TestParseReplayOffset(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"90s", 90 * time.Second, false},
+		{"1m30s", 90 * time.Second, false},
+		{"1:30", 90 * time.Second, false},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"1:x", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseReplayOffset(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("parseReplayOffset(%q) = %v, %v", tt.input, got, err)
+		}
+	}
+
+	// --search prints offsets that --from reads back
+	for _, d := range []time.Duration{75 * time.Second, 3723 * time.Second} {
+		if got, _ := parseReplayOffset(formatReplayOffset(d)); got != d {
+			t.Errorf("round trip of %v = %v", d, got)
+		}
+	}
+}
+
+func TestRecordingEnabled(t *testing.T) {
+	if recordingEnabled(nil, "polecat") || recordingEnabled(&config.RecordingConfig{}, "polecat") {
+		t.Error("recording enabled without config")
+	}
+	all := &config.RecordingConfig{Enabled: true}
+	some := &config.RecordingConfig{Enabled: true, Roles: []string{"polecat"}}
+	if !recordingEnabled(all, "mayor") || !recordingEnabled(some, "polecat") || recordingEnabled(some, "crew") {
+		t.Error("roles not applied")
+	}
+}
+
+func TestStoppableWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &stoppableWriter{w: &buf}
+	if _, err := w.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	w.stop()
+	if _, err := w.Write([]byte("b")); err == nil {
+		t.Error("write after stop accepted")
+	}
+	if buf.String() != "a" {
+		t.Errorf("wrote %q, want %q", buf.String(), "a")
+	}
+}
+
+func TestFindRecordings_BeadFromEvents(t *testing.T) {
+	town := t.TempDir()
+	slung := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	// The bead was slung to Toast, whose session started a minute later
+	// and was never tagged with it
+	rec := recording.New(town, "gt-gastown-Toast", "gastown/polecats/Toast", slung.Add(time.Minute))
+	if err := recording.Append(town, rec); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(rec.Path, []byte(`{"version":2}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	other := recording.New(town, "gt-gastown-Nux", "gastown/polecats/Nux", slung)
+	_ = recording.Append(town, other)
+	_ = os.WriteFile(other.Path, []byte(`{"version":2}`+"\n"), 0600)
+
+	line := `{"ts":"` + slung.Format(time.RFC3339) + `","source":"gt","type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/Toast"},"visibility":"feed"}` + "\n"
+	if err := os.WriteFile(filepath.Join(town, events.EventsFile), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := recording.List(town)
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("List = %v, %v", recs, err)
+	}
+	matches, err := findRecordings(town, recs, "gt-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].ID != rec.ID {
+		t.Errorf("findRecordings(gt-abc) = %+v, want Toast's recording", matches)
+	}
+	if matches, _ := findRecordings(town, recs, "gt-gastown-Nux"); len(matches) != 1 || matches[0].ID != other.ID {
+		t.Errorf("findRecordings(session) = %+v", matches)
+	}
+}
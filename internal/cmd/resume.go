@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -93,8 +94,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check gate status
-	gateCheck := exec.Command("bd", "gate", "show", parked.GateID, "--json")
-	gateOutput, err := gateCheck.Output()
+	gate, err := beads.NewRouted("").ShowGate(parked.GateID)
 	gateNotFound := false
 	if err != nil {
 		// Gate might have been deleted (wisp cleanup) or is inaccessible
@@ -103,15 +103,8 @@ func runResume(cmd *cobra.Command, args []string) error {
 		status.GateClosed = true // Treat as closed so user can clear it
 		status.CloseReason = "Gate no longer exists (may have been cleaned up)"
 	} else {
-		var gateInfo struct {
-			ID          string `json:"id"`
-			Status      string `json:"status"`
-			CloseReason string `json:"close_reason"`
-		}
-		if err := json.Unmarshal(gateOutput, &gateInfo); err == nil {
-			status.GateClosed = gateInfo.Status == "closed"
-			status.CloseReason = gateInfo.CloseReason
-		}
+		status.GateClosed = gate.Status == "closed"
+		status.CloseReason = gate.CloseReason
 	}
 
 	status.CanResume = status.GateClosed
@@ -155,10 +148,7 @@ func runResume(cmd *cobra.Command, args []string) error {
 
 	// Pin the bead to restore work
 	if parked.BeadID != "" {
-		pinCmd := exec.Command("bd", "update", parked.BeadID, "--status=pinned", "--assignee="+agentID)
-		pinCmd.Dir = cloneRoot
-		pinCmd.Stderr = os.Stderr
-		if err := pinCmd.Run(); err != nil {
+		if err := beads.New(cloneRoot).Pin(parked.BeadID, agentID); err != nil {
 			return fmt.Errorf("pinning bead: %w", err)
 		}
 
@@ -146,10 +146,8 @@ func runRigDock(cmd *cobra.Command, args []string) error {
 
 	// Sync beads to propagate to other clones
 	fmt.Printf("  Syncing beads...\n")
-	syncCmd := exec.Command("bd", "sync")
-	syncCmd.Dir = r.BeadsPath()
-	if output, err := syncCmd.CombinedOutput(); err != nil {
-		fmt.Printf("  %s bd sync warning: %v\n%s", style.Warning.Render("!"), err, string(output))
+	if err := beads.New(r.BeadsPath()).Sync(); err != nil {
+		fmt.Printf("  %s bd sync warning: %v\n", style.Warning.Render("!"), err)
 	}
 
 	// Output
@@ -212,10 +210,8 @@ func runRigUndock(cmd *cobra.Command, args []string) error {
 
 	// Sync beads to propagate to other clones
 	fmt.Printf("  Syncing beads...\n")
-	syncCmd := exec.Command("bd", "sync")
-	syncCmd.Dir = r.BeadsPath()
-	if output, err := syncCmd.CombinedOutput(); err != nil {
-		fmt.Printf("  %s bd sync warning: %v\n%s", style.Warning.Render("!"), err, string(output))
+	if err := beads.New(r.BeadsPath()).Sync(); err != nil {
+		fmt.Printf("  %s bd sync warning: %v\n", style.Warning.Render("!"), err)
 	}
 
 	fmt.Printf("%s Rig %s undocked\n", style.Success.Render("✓"), rigName)
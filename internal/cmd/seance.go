@@ -13,6 +13,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/recording"
+	"github.com/steveyegge/gastown/internal/retention"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -55,7 +57,10 @@ This loads the predecessor's full context without modifying their session.
 
 Sessions are discovered from:
   1. Events emitted by SessionStart hooks (~/gt/.events.jsonl)
-  2. The [GAS TOWN] beacon makes sessions searchable in /resume`,
+  2. The [GAS TOWN] beacon makes sessions searchable in /resume
+
+If session recording is enabled, 'gt replay <session-id>' plays back
+what the session's pane showed.`,
 	RunE: runSeance,
 }
 
@@ -184,9 +189,32 @@ func runSeanceList() error {
 	fmt.Printf("  gt seance --talk <session-id>\n")
 	fmt.Printf("  gt seance --talk <session-id> -p \"Where did you put X?\"\n")
 
+	if hasRecordedSession(townRoot, filtered) {
+		fmt.Printf("\n%s\n", style.Bold.Render("Watch a predecessor's session (recorded):"))
+		fmt.Printf("  gt replay <session-id>\n")
+	}
+
 	return nil
 }
 
+// hasRecordedSession reports whether any of the sessions has a recording
+// that gt replay can play.
+func hasRecordedSession(townRoot string, sessions []sessionEvent) bool {
+	recs, err := recording.List(townRoot)
+	if err != nil || len(recs) == 0 {
+		return false
+	}
+	for _, s := range sessions {
+		id := getPayloadString(s.Payload, "session_id")
+		for _, r := range recs {
+			if id != "" && r.Matches(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func runSeanceTalk(sessionID, prompt string) error {
 	// Expand short IDs if needed (user might provide partial)
 	// For now, require full ID or let claude --resume handle it
@@ -236,7 +264,7 @@ func runSeanceTalk(sessionID, prompt string) error {
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
 	eventsPath := filepath.Join(townRoot, events.EventsFile)
 
-	file, err := os.Open(eventsPath)
+	file, err := retention.Open(eventsPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -115,7 +115,7 @@ func init() {
 	rootCmd.AddCommand(slingCmd)
 }
 
-func runSling(cmd *cobra.Command, args []string) error {
+func runSling(cmd *cobra.Command, args []string) (err error) {
 	// Polecats cannot sling - check early before writing anything
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
 		return fmt.Errorf("polecats cannot sling (use gt done for handoff)")
@@ -185,6 +185,18 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Each sling starts a trace; the spawn, the polecat's work and the
+	// refinery's merge all hang off it via the bead's trace_parent.
+	var span *telemetry.Span
+	if !slingDryRun {
+		span = telemetry.StartRoot("sling", telemetry.String("gt.bead", beadID))
+		if formulaName != "" {
+			span.SetAttributes(telemetry.String("gt.formula", formulaName))
+		}
+		defer func() { span.End(err) }()
+	}
+	traceCtx := telemetry.ContextWithSpan(context.Background(), span)
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -237,6 +249,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Create:   slingCreate,
 					HookBead: beadID, // Set atomically at spawn time
 					Agent:    slingAgent,
+
+					TraceParent: span.TraceParent(),
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -268,6 +282,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 							Create:   slingCreate,
 							HookBead: beadID,
 							Agent:    slingAgent,
+
+							TraceParent: span.TraceParent(),
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 						if spawnErr != nil {
@@ -381,10 +397,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 		// Step 1: Cook the formula (ensures proto exists)
 		// Run from rig directory for consistency with other bd commands
-		cookCmd := exec.Command("bd", "--no-daemon", "cook", formulaName)
-		cookCmd.Dir = formulaWorkDir
-		cookCmd.Stderr = os.Stderr
-		if err := cookCmd.Run(); err != nil {
+		bd := beads.New(formulaWorkDir).WithEnv("GT_ROOT=" + townRoot).WithContext(traceCtx)
+		if err := bd.Cook(formulaName); err != nil {
 			return fmt.Errorf("cooking formula %s: %w", formulaName, err)
 		}
 
@@ -392,46 +406,19 @@ func runSling(cmd *cobra.Command, args []string) error {
 		// Run from rig directory so wisp is created in correct database
 		featureVar := fmt.Sprintf("feature=%s", info.Title)
 		issueVar := fmt.Sprintf("issue=%s", beadID)
-		wispArgs := []string{"--no-daemon", "mol", "wisp", formulaName, "--var", featureVar, "--var", issueVar, "--json"}
-		wispCmd := exec.Command("bd", wispArgs...)
-		wispCmd.Dir = formulaWorkDir
-		wispCmd.Env = append(os.Environ(), "GT_ROOT="+townRoot)
-		wispCmd.Stderr = os.Stderr
-		wispOut, err := wispCmd.Output()
+		wispRootID, err := bd.PourWisp(formulaName, featureVar, issueVar)
 		if err != nil {
 			return fmt.Errorf("creating wisp for formula %s: %w", formulaName, err)
 		}
-
-		// Parse wisp output to get the root ID
-		wispRootID, err := parseWispIDFromJSON(wispOut)
-		if err != nil {
-			return fmt.Errorf("parsing wisp output: %w", err)
-		}
 		fmt.Printf("%s Formula wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
 		// Step 3: Bond wisp to original bead (creates compound)
-		// Use --no-daemon for mol bond (requires direct database access)
-		bondArgs := []string{"--no-daemon", "mol", "bond", wispRootID, beadID, "--json"}
-		bondCmd := exec.Command("bd", bondArgs...)
-		bondCmd.Dir = formulaWorkDir
-		bondCmd.Stderr = os.Stderr
-		bondOut, err := bondCmd.Output()
+		// After bonding, we hook the compound root (which now contains the original bead)
+		wispRootID, err = bd.Bond(wispRootID, beadID)
 		if err != nil {
 			return fmt.Errorf("bonding formula to bead: %w", err)
 		}
 
-		// Parse bond output - the wisp root becomes the compound root
-		// After bonding, we hook the wisp root (which now contains the original bead)
-		var bondResult struct {
-			RootID string `json:"root_id"`
-		}
-		if err := json.Unmarshal(bondOut, &bondResult); err != nil {
-			// Fallback: use wisp root as the compound root
-			fmt.Printf("%s Could not parse bond output, using wisp root\n", style.Dim.Render("Warning:"))
-		} else if bondResult.RootID != "" {
-			wispRootID = bondResult.RootID
-		}
-
 		fmt.Printf("%s Formula bonded to %s\n", style.Bold.Render("✓"), beadID)
 
 		// Update beadID to hook the compound root instead of bare bead
@@ -440,10 +427,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Hook the bead using bd update.
 	// See: https://github.com/steveyegge/gastown/issues/148
-	hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
-	hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	hookBeads := beads.New(beads.ResolveHookDir(townRoot, beadID, hookWorkDir)).WithContext(traceCtx)
+	if err := hookBeads.Hook(beadID, targetAgent); err != nil {
 		return fmt.Errorf("hooking bead: %w", err)
 	}
 
@@ -465,8 +450,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Store dispatcher in bead description (enables completion notification to dispatcher)
-	if err := storeDispatcherInBead(beadID, actor); err != nil {
+	// Store dispatcher and trace in bead description (enables completion
+	// notification to dispatcher, and lets the polecat's spans join the trace)
+	span.SetAttributes(telemetry.String("gt.target", targetAgent))
+	if err := storeDispatcherInBead(beadID, actor, span.TraceParent()); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
@@ -504,5 +491,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if span != nil {
+		fmt.Printf("%s Trace: %s\n", style.Dim.Render("○"), span.TraceID())
+	}
+
 	return nil
 }
@@ -1,14 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
@@ -58,6 +58,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Each bead is its own unit of work, so gets its own trace
+		span := telemetry.StartRoot("sling", telemetry.String("gt.bead", beadID), telemetry.String("gt.rig", rigName))
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
@@ -65,9 +68,12 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
+
+			TraceParent: span.TraceParent(),
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
+			span.End(err)
 			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
 			fmt.Printf("  %s Failed to spawn polecat: %v\n", style.Dim.Render("✗"), err)
 			continue
@@ -93,10 +99,10 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Hook the bead. See: https://github.com/steveyegge/gastown/issues/148
 		townRoot := filepath.Dir(townBeadsDir)
-		hookCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
-		hookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
-		hookCmd.Stderr = os.Stderr
-		if err := hookCmd.Run(); err != nil {
+		hookBeads := beads.New(beads.ResolveHookDir(townRoot, beadID, hookWorkDir)).
+			WithContext(telemetry.ContextWithSpan(context.Background(), span))
+		if err := hookBeads.Hook(beadID, targetAgent); err != nil {
+			span.End(err)
 			results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: false, errMsg: "hook failed"})
 			fmt.Printf("  %s Failed to hook bead: %v\n", style.Dim.Render("✗"), err)
 			continue
@@ -116,6 +122,12 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  %s Could not attach work molecule: %v\n", style.Dim.Render("Warning:"), err)
 		}
 
+		// Store trace so the polecat's spans join it
+		span.SetAttributes(telemetry.String("gt.target", targetAgent))
+		if err := storeDispatcherInBead(beadID, "", span.TraceParent()); err != nil {
+			fmt.Printf("  %s Could not store trace in bead: %v\n", style.Dim.Render("Warning:"), err)
+		}
+
 		// Store args if provided
 		if slingArgs != "" {
 			if err := storeArgsInBead(beadID, slingArgs); err != nil {
@@ -132,6 +144,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
+		span.End(nil)
 		results = append(results, slingResult{beadID: beadID, polecat: spawnInfo.PolecatName, success: true})
 	}
 
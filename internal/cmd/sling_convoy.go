@@ -4,11 +4,11 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -81,30 +81,19 @@ func createAutoConvoy(beadID, beadTitle string) (string, error) {
 	convoyTitle := fmt.Sprintf("Work: %s", beadTitle)
 	description := fmt.Sprintf("Auto-created convoy tracking %s", beadID)
 
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + convoyTitle,
-		"--description=" + description,
-	}
-
-	createCmd := exec.Command("bd", append([]string{"--no-daemon"}, createArgs...)...)
-	createCmd.Dir = townBeads
-	createCmd.Stderr = os.Stderr
-
-	if err := createCmd.Run(); err != nil {
+	bd := townBeadsClient(townBeads)
+	if _, err := bd.CreateWithID(convoyID, beads.CreateOptions{
+		IssueType:   "convoy",
+		Title:       convoyTitle,
+		Description: description,
+		Priority:    -1,
+	}); err != nil {
 		return "", fmt.Errorf("creating convoy: %w", err)
 	}
 
 	// Add tracking relation: convoy tracks the issue
 	trackBeadID := formatTrackBeadID(beadID)
-	depArgs := []string{"--no-daemon", "dep", "add", convoyID, trackBeadID, "--type=tracks"}
-	depCmd := exec.Command("bd", depArgs...)
-	depCmd.Dir = townBeads
-	depCmd.Stderr = os.Stderr
-
-	if err := depCmd.Run(); err != nil {
+	if err := bd.AddDependencyWithType(convoyID, trackBeadID, "tracks"); err != nil {
 		// Convoy was created but tracking failed - log warning but continue
 		fmt.Printf("%s Could not add tracking relation: %v\n", style.Dim.Render("Warning:"), err)
 	}
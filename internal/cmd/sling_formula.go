@@ -1,68 +1,26 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-type wispCreateJSON struct {
-	NewEpicID string `json:"new_epic_id"`
-	RootID    string `json:"root_id"`
-	ResultID  string `json:"result_id"`
-}
-
-func parseWispIDFromJSON(jsonOutput []byte) (string, error) {
-	var result wispCreateJSON
-	if err := json.Unmarshal(jsonOutput, &result); err != nil {
-		return "", fmt.Errorf("parsing wisp JSON: %w (output: %s)", err, trimJSONForError(jsonOutput))
-	}
-
-	switch {
-	case result.NewEpicID != "":
-		return result.NewEpicID, nil
-	case result.RootID != "":
-		return result.RootID, nil
-	case result.ResultID != "":
-		return result.ResultID, nil
-	default:
-		return "", fmt.Errorf("wisp JSON missing id field (expected one of new_epic_id, root_id, result_id); output: %s", trimJSONForError(jsonOutput))
-	}
-}
-
-func trimJSONForError(jsonOutput []byte) string {
-	s := strings.TrimSpace(string(jsonOutput))
-	const maxLen = 500
-	if len(s) > maxLen {
-		return s[:maxLen] + "..."
-	}
-	return s
-}
-
 // verifyFormulaExists checks that the formula exists using bd formula show.
 // Formulas are TOML files (.formula.toml).
 // Uses --no-daemon with --allow-stale for consistency with verifyBeadExists.
 func verifyFormulaExists(formulaName string) error {
-	// Try bd formula show (handles all formula file formats)
-	// Use Output() instead of Run() to detect bd --no-daemon exit 0 bug:
-	// when formula not found, --no-daemon may exit 0 but produce empty stdout.
-	cmd := exec.Command("bd", "--no-daemon", "formula", "show", formulaName, "--allow-stale")
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
-		return nil
-	}
-
-	// Try with mol- prefix
-	cmd = exec.Command("bd", "--no-daemon", "formula", "show", "mol-"+formulaName, "--allow-stale")
-	if out, err := cmd.Output(); err == nil && len(out) > 0 {
+	// Try bd formula show (handles all formula file formats), then with
+	// the mol- prefix
+	bd := beads.NewRouted("")
+	if bd.FormulaExists(formulaName) || bd.FormulaExists("mol-"+formulaName) {
 		return nil
 	}
 
@@ -71,7 +29,7 @@ func verifyFormulaExists(formulaName string) error {
 
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
-func runSlingFormula(args []string) error {
+func runSlingFormula(args []string) (err error) {
 	formulaName := args[0]
 
 	// Get town root early - needed for BEADS_DIR when running bd commands
@@ -87,6 +45,13 @@ func runSlingFormula(args []string) error {
 		target = args[1]
 	}
 
+	var span *telemetry.Span
+	if !slingDryRun {
+		span = telemetry.StartRoot("sling", telemetry.String("gt.formula", formulaName))
+		defer func() { span.End(err) }()
+	}
+	traceCtx := telemetry.ContextWithSpan(context.Background(), span)
+
 	// Resolve target agent and pane
 	var targetAgent string
 	var targetPane string
@@ -135,6 +100,8 @@ func runSlingFormula(args []string) error {
 					Account: slingAccount,
 					Create:  slingCreate,
 					Agent:   slingAgent,
+
+					TraceParent: span.TraceParent(),
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -180,42 +147,24 @@ func runSlingFormula(args []string) error {
 
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	cookArgs := []string{"--no-daemon", "cook", formulaName}
-	cookCmd := exec.Command("bd", cookArgs...)
-	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
+	bd := beads.NewRouted("").WithContext(traceCtx)
+	if err := bd.Cook(formulaName); err != nil {
 		return fmt.Errorf("cooking formula: %w", err)
 	}
 
 	// Step 2: Create wisp instance (ephemeral)
 	fmt.Printf("  Creating wisp...\n")
-	wispArgs := []string{"--no-daemon", "mol", "wisp", formulaName}
-	for _, v := range slingVars {
-		wispArgs = append(wispArgs, "--var", v)
-	}
-	wispArgs = append(wispArgs, "--json")
-
-	wispCmd := exec.Command("bd", wispArgs...)
-	wispCmd.Stderr = os.Stderr // Show wisp errors to user
-	wispOut, err := wispCmd.Output()
+	wispRootID, err := bd.PourWisp(formulaName, slingVars...)
 	if err != nil {
 		return fmt.Errorf("creating wisp: %w", err)
 	}
 
-	// Parse wisp output to get the root ID
-	wispRootID, err := parseWispIDFromJSON(wispOut)
-	if err != nil {
-		return fmt.Errorf("parsing wisp output: %w", err)
-	}
-
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
 
 	// Step 3: Hook the wisp bead using bd update.
 	// See: https://github.com/steveyegge/gastown/issues/148
-	hookCmd := exec.Command("bd", "--no-daemon", "update", wispRootID, "--status=hooked", "--assignee="+targetAgent)
-	hookCmd.Dir = beads.ResolveHookDir(townRoot, wispRootID, "")
-	hookCmd.Stderr = os.Stderr
-	if err := hookCmd.Run(); err != nil {
+	hookBeads := beads.New(beads.ResolveHookDir(townRoot, wispRootID, "")).WithContext(traceCtx)
+	if err := hookBeads.Hook(wispRootID, targetAgent); err != nil {
 		return fmt.Errorf("hooking wisp bead: %w", err)
 	}
 	fmt.Printf("%s Attached to hook (status=hooked)\n", style.Bold.Render("✓"))
@@ -230,8 +179,10 @@ func runSlingFormula(args []string) error {
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
 	updateAgentHookBead(targetAgent, wispRootID, "", townBeadsDir)
 
-	// Store dispatcher in bead description (enables completion notification to dispatcher)
-	if err := storeDispatcherInBead(wispRootID, actor); err != nil {
+	// Store dispatcher and trace in bead description (enables completion
+	// notification to dispatcher, and lets the agent's spans join the trace)
+	span.SetAttributes(telemetry.String("gt.bead", wispRootID), telemetry.String("gt.target", targetAgent))
+	if err := storeDispatcherInBead(wispRootID, actor, span.TraceParent()); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,95 +11,49 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// beadInfo holds status and assignee for a bead.
-type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+// routedFromTown returns a client that runs bd from the town root, so bd
+// can find routes.jsonl and resolve rig-level beads by prefix. It doesn't
+// set BEADS_DIR, which would override routing.
+func routedFromTown() *beads.Beads {
+	townRoot, _ := workspace.FindFromCwd()
+	return beads.NewRouted(townRoot)
 }
 
 // verifyBeadExists checks that the bead exists using bd show.
-// Uses bd's native prefix-based routing via routes.jsonl - do NOT set BEADS_DIR
-// as that overrides routing and breaks resolution of rig-level beads.
+// Uses bd's native prefix-based routing via routes.jsonl.
 //
-// Uses --no-daemon with --allow-stale to avoid daemon socket timing issues
-// while still finding beads when database is out of sync with JSONL.
 // For existence checks, stale data is acceptable - we just need to know it exists.
 func verifyBeadExists(beadID string) error {
-	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
-	// Run from town root so bd can find routes.jsonl for prefix-based routing.
-	// Do NOT set BEADS_DIR - that overrides routing and breaks rig bead resolution.
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		cmd.Dir = townRoot
-	}
-	// Use Output() instead of Run() to detect bd --no-daemon exit 0 bug:
-	// when issue not found, --no-daemon exits 0 but produces empty stdout.
-	out, err := cmd.Output()
-	if err != nil {
+	if _, err := routedFromTown().Show(beadID); err != nil {
 		return fmt.Errorf("bead '%s' not found (bd show failed)", beadID)
 	}
-	if len(out) == 0 {
-		return fmt.Errorf("bead '%s' not found", beadID)
-	}
 	return nil
 }
 
 // getBeadInfo returns status and assignee for a bead.
 // Uses bd's native prefix-based routing via routes.jsonl.
-// Uses --no-daemon with --allow-stale for consistency with verifyBeadExists.
-func getBeadInfo(beadID string) (*beadInfo, error) {
-	cmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
-	// Run from town root so bd can find routes.jsonl for prefix-based routing.
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		cmd.Dir = townRoot
-	}
-	out, err := cmd.Output()
+func getBeadInfo(beadID string) (*beads.Issue, error) {
+	issue, err := routedFromTown().Show(beadID)
 	if err != nil {
 		return nil, fmt.Errorf("bead '%s' not found", beadID)
 	}
-	// Handle bd --no-daemon exit 0 bug: when issue not found,
-	// --no-daemon exits 0 but produces empty stdout (error goes to stderr).
-	if len(out) == 0 {
-		return nil, fmt.Errorf("bead '%s' not found", beadID)
-	}
-	// bd show --json returns an array (issue + dependents), take first element
-	var infos []beadInfo
-	if err := json.Unmarshal(out, &infos); err != nil {
-		return nil, fmt.Errorf("parsing bead info: %w", err)
-	}
-	if len(infos) == 0 {
-		return nil, fmt.Errorf("bead '%s' not found", beadID)
-	}
-	return &infos[0], nil
+	return issue, nil
 }
 
 // storeArgsInBead stores args in the bead's description using attached_args field.
 // This enables no-tmux mode where agents discover args via gt prime / bd show.
 func storeArgsInBead(beadID, args string) error {
 	// Get the bead to preserve existing description content
-	showCmd := exec.Command("bd", "--no-daemon", "show", beadID, "--json", "--allow-stale")
-	out, err := showCmd.Output()
+	bd := beads.NewRouted("")
+	issue, err := bd.Show(beadID)
 	if err != nil {
 		return fmt.Errorf("fetching bead: %w", err)
 	}
-	// Handle bd --no-daemon exit 0 bug: empty stdout means not found
-	if len(out) == 0 {
-		return fmt.Errorf("bead not found")
-	}
-
-	// Parse the bead
-	var issues []beads.Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return fmt.Errorf("parsing bead: %w", err)
-	}
-	if len(issues) == 0 {
-		return fmt.Errorf("bead not found")
-	}
-	issue := &issues[0]
 
 	// Get or create attachment fields
 	fields := beads.ParseAttachmentFields(issue)
@@ -115,55 +68,56 @@ func storeArgsInBead(beadID, args string) error {
 	newDesc := beads.SetAttachmentFields(issue, fields)
 
 	// Update the bead
-	updateCmd := exec.Command("bd", "--no-daemon", "update", beadID, "--description="+newDesc)
-	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := bd.Update(beadID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
 	return nil
 }
 
-// storeDispatcherInBead stores the dispatcher agent ID in the bead's description.
-// This enables polecats to notify the dispatcher when work is complete.
-func storeDispatcherInBead(beadID, dispatcher string) error {
-	if dispatcher == "" {
+// workTraceParent returns the traceparent of the sling that dispatched the
+// bead, falling back to the one the agent's session was started with.
+func workTraceParent(issue *beads.Issue) string {
+	if fields := beads.ParseAttachmentFields(issue); fields != nil && fields.TraceParent != "" {
+		return fields.TraceParent
+	}
+	return os.Getenv(telemetry.ParentEnv)
+}
+
+// storeDispatcherInBead stores the dispatcher agent ID and the sling's
+// traceparent in the bead's description. This enables polecats to notify the
+// dispatcher when work is complete, and to continue the sling's trace.
+func storeDispatcherInBead(beadID, dispatcher, traceParent string) error {
+	if dispatcher == "" && traceParent == "" {
 		return nil
 	}
 
 	// Get the bead to preserve existing description content
-	showCmd := exec.Command("bd", "show", beadID, "--json")
-	out, err := showCmd.Output()
+	bd := beads.NewRouted("")
+	issue, err := bd.Show(beadID)
 	if err != nil {
 		return fmt.Errorf("fetching bead: %w", err)
 	}
 
-	// Parse the bead
-	var issues []beads.Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return fmt.Errorf("parsing bead: %w", err)
-	}
-	if len(issues) == 0 {
-		return fmt.Errorf("bead not found")
-	}
-	issue := &issues[0]
-
 	// Get or create attachment fields
 	fields := beads.ParseAttachmentFields(issue)
 	if fields == nil {
 		fields = &beads.AttachmentFields{}
 	}
 
-	// Set the dispatcher
-	fields.DispatchedBy = dispatcher
+	// Set the dispatcher and trace
+	if dispatcher != "" {
+		fields.DispatchedBy = dispatcher
+	}
+	if traceParent != "" {
+		fields.TraceParent = traceParent
+	}
 
 	// Update the description
 	newDesc := beads.SetAttachmentFields(issue, fields)
 
 	// Update the bead
-	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
-	updateCmd.Stderr = os.Stderr
-	if err := updateCmd.Run(); err != nil {
+	if err := bd.Update(beadID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 		return fmt.Errorf("updating bead description: %w", err)
 	}
 
@@ -421,10 +375,7 @@ func attachPolecatWorkMolecule(targetAgent, hookWorkDir, townRoot string) error
 
 	// Cook the mol-polecat-work formula to ensure the proto exists
 	// This is safe to run multiple times - cooking is idempotent
-	cookCmd := exec.Command("bd", "--no-daemon", "cook", "mol-polecat-work")
-	cookCmd.Dir = rigDir
-	cookCmd.Stderr = os.Stderr
-	if err := cookCmd.Run(); err != nil {
+	if err := b.Cook("mol-polecat-work"); err != nil {
 		return fmt.Errorf("cooking mol-polecat-work formula: %w", err)
 	}
 
@@ -18,6 +18,7 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/readmodel"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -230,73 +231,13 @@ func runStatusOnce(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("discovering rigs: %w", err)
 	}
 
-	// Pre-fetch agent beads across all rig-specific beads DBs.
+	// Pre-fetch agent beads (and their hooked beads) across the town and
+	// rig beads DBs, from the daemon's read model when it is running.
 	allAgentBeads := make(map[string]*beads.Issue)
 	allHookBeads := make(map[string]*beads.Issue)
-
-	// Fetch town-level agent beads (Mayor, Deacon) from town beads
-	townBeadsPath := beads.GetTownBeadsPath(townRoot)
-	townBeadsClient := beads.New(townBeadsPath)
-	townAgentBeads, _ := townBeadsClient.ListAgentBeads()
-	for id, issue := range townAgentBeads {
-		allAgentBeads[id] = issue
-	}
-
-	// Fetch hook beads from town beads
-	var townHookIDs []string
-	for _, issue := range townAgentBeads {
-		hookID := issue.HookBead
-		if hookID == "" {
-			fields := beads.ParseAgentFields(issue.Description)
-			if fields != nil {
-				hookID = fields.HookBead
-			}
-		}
-		if hookID != "" {
-			townHookIDs = append(townHookIDs, hookID)
-		}
-	}
-	if len(townHookIDs) > 0 {
-		townHookBeads, _ := townBeadsClient.ShowMultiple(townHookIDs)
-		for id, issue := range townHookBeads {
-			allHookBeads[id] = issue
-		}
-	}
-
-	// Fetch rig-level agent beads
-	for _, r := range rigs {
-		rigBeadsPath := filepath.Join(r.Path, "mayor", "rig")
-		rigBeads := beads.New(rigBeadsPath)
-		rigAgentBeads, _ := rigBeads.ListAgentBeads()
-		if rigAgentBeads == nil {
-			continue
-		}
-		for id, issue := range rigAgentBeads {
-			allAgentBeads[id] = issue
-		}
-
-		var hookIDs []string
-		for _, issue := range rigAgentBeads {
-			// Use the HookBead field from the database column; fall back for legacy beads.
-			hookID := issue.HookBead
-			if hookID == "" {
-				fields := beads.ParseAgentFields(issue.Description)
-				if fields != nil {
-					hookID = fields.HookBead
-				}
-			}
-			if hookID != "" {
-				hookIDs = append(hookIDs, hookID)
-			}
-		}
-
-		if len(hookIDs) == 0 {
-			continue
-		}
-		hookBeads, _ := rigBeads.ShowMultiple(hookIDs)
-		for id, issue := range hookBeads {
-			allHookBeads[id] = issue
-		}
+	if index, err := readmodel.Agents(townRoot); err == nil && index != nil {
+		allAgentBeads = index.Agents
+		allHookBeads = index.Hooks
 	}
 
 	// Create mail router for inbox lookups
@@ -1137,41 +1078,17 @@ func getMQSummary(r *rig.Rig) *MQSummary {
 		return nil
 	}
 
-	// Create beads instance for the rig
-	b := beads.New(r.BeadsPath())
-
-	// Query for all open merge-request type issues
-	opts := beads.ListOptions{
-		Type:     "merge-request",
-		Status:   "open",
-		Priority: -1, // No priority filter
-	}
-	openMRs, err := b.List(opts)
-	if err != nil {
+	// Count open (pending vs blocked) and in-progress merge requests,
+	// from the daemon's read model when it is running
+	counts, err := readmodel.MQ(filepath.Dir(r.Path), r.BeadsPath())
+	if err != nil || counts == nil {
 		return nil
 	}
-
-	// Query for in-progress merge-requests
-	opts.Status = "in_progress"
-	inProgressMRs, err := b.List(opts)
-	if err != nil {
-		return nil
-	}
-
-	// Count pending (open with no blockers) vs blocked
-	pending := 0
-	blocked := 0
-	for _, mr := range openMRs {
-		if len(mr.BlockedBy) > 0 || mr.BlockedByCount > 0 {
-			blocked++
-		} else {
-			pending++
-		}
-	}
+	pending, blocked, inFlight := counts.Pending, counts.Blocked, counts.InFlight
 
 	// Determine queue state
 	state := "idle"
-	if len(inProgressMRs) > 0 {
+	if inFlight > 0 {
 		state = "processing"
 	} else if pending > 0 {
 		state = "idle" // Has work but not processing yet
@@ -1181,24 +1098,24 @@ func getMQSummary(r *rig.Rig) *MQSummary {
 
 	// Determine queue health
 	health := "empty"
-	total := pending + len(inProgressMRs) + blocked
+	total := pending + inFlight + blocked
 	if total > 0 {
 		health = "healthy"
 		// Check for potential issues
-		if pending > 10 && len(inProgressMRs) == 0 {
+		if pending > 10 && inFlight == 0 {
 			// Large queue but nothing processing - may be stuck
 			health = "stale"
 		}
 	}
 
 	// Only return summary if there's something to show
-	if pending == 0 && len(inProgressMRs) == 0 && blocked == 0 {
+	if pending == 0 && inFlight == 0 && blocked == 0 {
 		return nil
 	}
 
 	return &MQSummary{
 		Pending:  pending,
-		InFlight: len(inProgressMRs),
+		InFlight: inFlight,
 		Blocked:  blocked,
 		State:    state,
 		Health:   health,
@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/readmodel"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -562,33 +563,13 @@ func runRefineryStatusLine(t *tmux.Tmux, rigName string) error {
 		townRoot, _ = workspace.Find(paneDir)
 	}
 
-	// Get refinery manager using shared helper
-	mgr, _, _, err := getRefineryManager(rigName)
-	if err != nil {
-		// Fallback to simple status if we can't access refinery
-		fmt.Printf("%s MQ: ? |", AgentTypeIcons[AgentRefinery])
-		return nil
-	}
-
-	// Get queue
-	queue, err := mgr.Queue()
+	queue, err := refineryQueueSummary(townRoot, rigName)
 	if err != nil {
 		// Fallback to simple status if we can't read queue
 		fmt.Printf("%s MQ: ? |", AgentTypeIcons[AgentRefinery])
 		return nil
 	}
-
-	// Count pending items and find current item
-	pending := 0
-	var currentItem string
-	for _, item := range queue {
-		if item.Position == 0 && item.MR != nil {
-			// Currently processing - show issue ID
-			currentItem = item.MR.IssueID
-		} else {
-			pending++
-		}
-	}
+	pending, currentItem := queue.Pending, queue.Current
 
 	identity := fmt.Sprintf("%s/refinery", rigName)
 
@@ -629,6 +610,32 @@ func runRefineryStatusLine(t *tmux.Tmux, rigName string) error {
 	return nil
 }
 
+// refineryQueueSummary returns the refinery's current MR and pending count,
+// from the daemon's read model when the town is known.
+func refineryQueueSummary(townRoot, rigName string) (*readmodel.QueueSummary, error) {
+	if townRoot != "" {
+		return readmodel.Queue(townRoot, rigName)
+	}
+	mgr, _, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := mgr.Queue()
+	if err != nil {
+		return nil, err
+	}
+	summary := &readmodel.QueueSummary{}
+	for _, item := range queue {
+		if item.Position == 0 && item.MR != nil {
+			// Currently processing - show issue ID
+			summary.Current = item.MR.IssueID
+		} else {
+			summary.Pending++
+		}
+	}
+	return summary, nil
+}
+
 // isSessionWorking detects if a Claude Code session is actively working.
 // Returns true if the ✻ symbol is visible in the pane (indicates Claude is processing).
 // Returns false for idle sessions (showing ❯ prompt) or if state cannot be determined.
@@ -697,23 +704,20 @@ func getMailPreview(identity string, maxLen int) (int, string) {
 }
 
 // getMailPreviewWithRoot is like getMailPreview but uses an explicit town root.
+// It reads through the daemon's read model when the daemon is running.
 func getMailPreviewWithRoot(identity string, maxLen int, townRoot string) (int, string) {
-	// Use NewMailboxFromAddress to normalize identity (e.g., gastown/crew/gus -> gastown/gus)
-	mailbox := mail.NewMailboxFromAddress(identity, townRoot)
-
-	// Get unread messages
-	messages, err := mailbox.ListUnread()
-	if err != nil || len(messages) == 0 {
+	preview, err := readmodel.Mail(townRoot, identity)
+	if err != nil || preview == nil || preview.Unread == 0 {
 		return 0, ""
 	}
 
 	// Get first message subject, truncated
-	subject := messages[0].Subject
+	subject := preview.Subject
 	if len(subject) > maxLen {
 		subject = subject[:maxLen-1] + "…"
 	}
 
-	return len(messages), subject
+	return preview.Unread, subject
 }
 
 // getHookedWork returns a truncated title of the hooked bead for an agent.
@@ -730,20 +734,15 @@ func getHookedWork(identity string, maxLen int, beadsDir string) string {
 		}
 	}
 
-	b := beads.New(beadsDir)
-
-	// Query for hooked beads assigned to this agent
-	hookedBeads, err := b.List(beads.ListOptions{
-		Status:   beads.StatusHooked,
-		Assignee: identity,
-		Priority: -1,
-	})
-	if err != nil || len(hookedBeads) == 0 {
+	// Query for hooked beads assigned to this agent, through the daemon's
+	// read model when it is running
+	townRoot, _ := workspace.Find(beadsDir)
+	bead, err := readmodel.Hooked(townRoot, identity, beadsDir)
+	if err != nil || bead == nil {
 		return ""
 	}
 
 	// Return first hooked bead's ID and title, truncated
-	bead := hookedBeads[0]
 	display := fmt.Sprintf("%s: %s", bead.ID, bead.Title)
 	if len(display) > maxLen {
 		display = display[:maxLen-1] + "…"
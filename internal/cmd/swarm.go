@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,11 +10,11 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
-	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/swarm"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -79,7 +78,10 @@ var swarmStatusCmd = &cobra.Command{
 	Long: `Show detailed status for a swarm.
 
 Displays swarm metadata, task progress, worker assignments, and integration
-branch status.`,
+branch status.
+
+With --watch, shows a live view of the swarm's dependency waves, blocked
+tasks and critical path.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSwarmStatus,
 }
@@ -234,23 +236,15 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 	// Use beads to create the swarm molecule
 	// First check if the epic already exists (it may be pre-created)
 	// Use BeadsPath() to ensure we read from git-synced beads location
-	beadsPath := r.BeadsPath()
-	checkCmd := exec.Command("bd", "show", swarmEpic, "--json")
-	checkCmd.Dir = beadsPath
-	if err := checkCmd.Run(); err != nil {
+	bd := beads.New(r.BeadsPath())
+	if _, err := bd.Show(swarmEpic); err != nil {
 		// Epic doesn't exist, create it as a swarm molecule
-		createArgs := []string{
-			"create",
-			"--type=epic",
-			"--mol-type=swarm",
-			"--title", swarmEpic,
-			"--silent",
-		}
-		createCmd := exec.Command("bd", createArgs...)
-		createCmd.Dir = beadsPath
-		var stdout bytes.Buffer
-		createCmd.Stdout = &stdout
-		if err := createCmd.Run(); err != nil {
+		if _, err := bd.Create(beads.CreateOptions{
+			Title:     swarmEpic,
+			IssueType: "epic",
+			MolType:   "swarm",
+			Priority:  -1,
+		}); err != nil {
 			return fmt.Errorf("creating swarm epic: %w", err)
 		}
 	}
@@ -282,22 +276,13 @@ func runSwarmCreate(cmd *cobra.Command, args []string) error {
 	// Start if requested
 	if swarmStart {
 		// Get swarm status to find ready tasks
-		statusCmd := exec.Command("bd", "swarm", "status", swarmEpic, "--json")
-		statusCmd.Dir = beadsPath
-		var statusOut bytes.Buffer
-		statusCmd.Stdout = &statusOut
-		if err := statusCmd.Run(); err != nil {
+		status, err := bd.SwarmStatus(swarmEpic)
+		if err != nil {
 			return fmt.Errorf("getting swarm status: %w", err)
 		}
 
-		// Parse status to dispatch workers
-		var status struct {
-			Ready []struct {
-				ID    string `json:"id"`
-				Title string `json:"title"`
-			} `json:"ready"`
-		}
-		if err := json.Unmarshal(statusOut.Bytes(), &status); err == nil && len(status.Ready) > 0 {
+		// Dispatch workers to the ready front
+		if len(status.Ready) > 0 {
 			fmt.Printf("\nReady front has %d tasks available\n", len(status.Ready))
 			if len(swarmWorkers) > 0 {
 				// Spawn workers for ready tasks
@@ -316,52 +301,17 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 	swarmID := args[0]
 
 	// Find the swarm's rig
-	rigs, townRoot, err := getAllRigs()
+	foundRig, townRoot, err := findSwarmRig(swarmID, "")
 	if err != nil {
 		return err
 	}
 
-	var foundRig *rig.Rig
-	for _, r := range rigs {
-		// Check if swarm exists in this rig by querying beads
-		// Use BeadsPath() to ensure we read from git-synced location
-		checkCmd := exec.Command("bd", "show", swarmID, "--json")
-		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
-			foundRig = r
-			break
-		}
-	}
-
-	if foundRig == nil {
-		return fmt.Errorf("swarm '%s' not found", swarmID)
-	}
-
 	// Get swarm status from beads
-	statusCmd := exec.Command("bd", "swarm", "status", swarmID, "--json")
-	statusCmd.Dir = foundRig.BeadsPath()
-	var stdout bytes.Buffer
-	statusCmd.Stdout = &stdout
-
-	if err := statusCmd.Run(); err != nil {
+	status, err := beads.New(foundRig.BeadsPath()).SwarmStatus(swarmID)
+	if err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
-	var status struct {
-		EpicID string `json:"epic_id"`
-		Ready  []struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-		} `json:"ready"`
-		Active []struct {
-			ID       string `json:"id"`
-			Assignee string `json:"assignee"`
-		} `json:"active"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
-		return fmt.Errorf("parsing swarm status: %w", err)
-	}
-
 	if len(status.Active) > 0 {
 		fmt.Printf("Swarm already has %d active tasks\n", len(status.Active))
 	}
@@ -391,55 +341,18 @@ func runSwarmStart(cmd *cobra.Command, args []string) error {
 func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 	epicID := args[0]
 
-	// Find the epic's rig by trying to show it in each rig
-	rigs, townRoot, err := getAllRigs()
+	// Find the epic's rig (only --rig, if specified)
+	foundRig, townRoot, err := findSwarmRig(epicID, swarmDispatchRig)
 	if err != nil {
 		return err
 	}
 
-	var foundRig *rig.Rig
-	for _, r := range rigs {
-		// If --rig specified, only check that rig
-		if swarmDispatchRig != "" && r.Name != swarmDispatchRig {
-			continue
-		}
-		// Use BeadsPath() to ensure we read from git-synced location
-		checkCmd := exec.Command("bd", "show", epicID, "--json")
-		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
-			foundRig = r
-			break
-		}
-	}
-
-	if foundRig == nil {
-		if swarmDispatchRig != "" {
-			return fmt.Errorf("epic '%s' not found in rig '%s'", epicID, swarmDispatchRig)
-		}
-		return fmt.Errorf("epic '%s' not found in any rig", epicID)
-	}
-
 	// Get swarm/epic status to find ready tasks
-	statusCmd := exec.Command("bd", "swarm", "status", epicID, "--json")
-	statusCmd.Dir = foundRig.BeadsPath()
-	var stdout bytes.Buffer
-	statusCmd.Stdout = &stdout
-
-	if err := statusCmd.Run(); err != nil {
+	status, err := beads.New(foundRig.BeadsPath()).SwarmStatus(epicID)
+	if err != nil {
 		return fmt.Errorf("getting epic status: %w", err)
 	}
 
-	var status struct {
-		Ready []struct {
-			ID       string `json:"id"`
-			Title    string `json:"title"`
-			Assignee string `json:"assignee"`
-		} `json:"ready"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
-		return fmt.Errorf("parsing epic status: %w", err)
-	}
-
 	// Filter to unassigned ready tasks
 	var unassigned []struct {
 		ID    string
@@ -487,10 +400,7 @@ func runSwarmDispatch(cmd *cobra.Command, args []string) error {
 }
 
 // spawnSwarmWorkersFromBeads spawns sessions for swarm workers using beads task list.
-func spawnSwarmWorkersFromBeads(r *rig.Rig, townRoot string, swarmID string, workers []string, tasks []struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-}) error { //nolint:unparam // error return kept for future use
+func spawnSwarmWorkersFromBeads(r *rig.Rig, townRoot string, swarmID string, workers []string, tasks []beads.SwarmTask) error { //nolint:unparam // error return kept for future use
 	t := tmux.NewTmux()
 	polecatSessMgr := polecat.NewSessionManager(t, r)
 	polecatGit := git.NewGit(r.Path)
@@ -548,30 +458,14 @@ func spawnSwarmWorkersFromBeads(r *rig.Rig, townRoot string, swarmID string, wor
 
 func runSwarmStatus(cmd *cobra.Command, args []string) error {
 	swarmID := args[0]
-
-	// Find the swarm's rig by trying to show it in each rig
-	rigs, _, err := getAllRigs()
-	if err != nil {
-		return err
-	}
-	if len(rigs) == 0 {
-		return fmt.Errorf("no rigs found")
+	if swarmStatusWatch {
+		return runSwarmStatusWatch(swarmID)
 	}
 
 	// Find which rig has this swarm
-	var foundRig *rig.Rig
-	for _, r := range rigs {
-		// Use BeadsPath() to ensure we read from git-synced location
-		checkCmd := exec.Command("bd", "show", swarmID, "--json")
-		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
-			foundRig = r
-			break
-		}
-	}
-
-	if foundRig == nil {
-		return fmt.Errorf("swarm '%s' not found in any rig", swarmID)
+	foundRig, _, err := findSwarmRig(swarmID, "")
+	if err != nil {
+		return err
 	}
 
 	// Use bd swarm status to get swarm info from beads
@@ -580,7 +474,7 @@ func runSwarmStatus(cmd *cobra.Command, args []string) error {
 		bdArgs = append(bdArgs, "--json")
 	}
 
-	bdCmd := exec.Command("bd", bdArgs...)
+	bdCmd := beads.Command(bdArgs...)
 	bdCmd.Dir = foundRig.BeadsPath()
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
@@ -614,12 +508,6 @@ func runSwarmList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Use bd list --mol-type=swarm to find swarm molecules
-	bdArgs := []string{"list", "--mol-type=swarm", "--type=epic"}
-	if swarmListJSON {
-		bdArgs = append(bdArgs, "--json")
-	}
-
 	// Collect swarms from all rigs
 	type swarmListEntry struct {
 		ID     string `json:"id"`
@@ -630,48 +518,27 @@ func runSwarmList(cmd *cobra.Command, args []string) error {
 	var allSwarms []swarmListEntry
 
 	for _, r := range rigs {
-		bdCmd := exec.Command("bd", bdArgs...)
-		bdCmd.Dir = r.BeadsPath() // Use BeadsPath() for git-synced beads
-		var stdout bytes.Buffer
-		bdCmd.Stdout = &stdout
-
-		if err := bdCmd.Run(); err != nil {
+		// Swarms are epics with mol_type=swarm; use BeadsPath() for git-synced beads
+		issues, err := beads.New(r.BeadsPath()).List(beads.ListOptions{
+			IssueType: "epic",
+			MolType:   "swarm",
+			Priority:  -1,
+		})
+		if err != nil {
 			continue
 		}
 
-		if swarmListJSON {
-			// Parse JSON output
-			var issues []struct {
-				ID     string `json:"id"`
-				Title  string `json:"title"`
-				Status string `json:"status"`
-			}
-			if err := json.Unmarshal(stdout.Bytes(), &issues); err == nil {
-				for _, issue := range issues {
-					allSwarms = append(allSwarms, swarmListEntry{
-						ID:     issue.ID,
-						Title:  issue.Title,
-						Status: issue.Status,
-						Rig:    r.Name,
-					})
-				}
-			}
-		} else {
-			// Parse line output - each line is an issue
-			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-			for _, line := range lines {
-				if line == "" {
-					continue
-				}
-				// Filter by status if specified
-				if swarmListStatus != "" && !strings.Contains(strings.ToLower(line), swarmListStatus) {
-					continue
-				}
-				allSwarms = append(allSwarms, swarmListEntry{
-					ID:  line,
-					Rig: r.Name,
-				})
+		for _, issue := range issues {
+			// Filter by status if specified
+			if !swarmListJSON && swarmListStatus != "" && !strings.EqualFold(issue.Status, swarmListStatus) {
+				continue
 			}
+			allSwarms = append(allSwarms, swarmListEntry{
+				ID:     issue.ID,
+				Title:  issue.Title,
+				Status: issue.Status,
+				Rig:    r.Name,
+			})
 		}
 	}
 
@@ -702,47 +569,18 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	swarmID := args[0]
 
 	// Find the swarm's rig
-	rigs, townRoot, err := getAllRigs()
+	foundRig, townRoot, err := findSwarmRig(swarmID, "")
 	if err != nil {
 		return err
 	}
 
-	var foundRig *rig.Rig
-	for _, r := range rigs {
-		// Use BeadsPath() for git-synced beads
-		checkCmd := exec.Command("bd", "show", swarmID, "--json")
-		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
-			foundRig = r
-			break
-		}
-	}
-
-	if foundRig == nil {
-		return fmt.Errorf("swarm '%s' not found", swarmID)
-	}
-
 	// Check swarm status - all children should be closed
-	statusCmd := exec.Command("bd", "swarm", "status", swarmID, "--json")
-	statusCmd.Dir = foundRig.BeadsPath()
-	var stdout bytes.Buffer
-	statusCmd.Stdout = &stdout
-
-	if err := statusCmd.Run(); err != nil {
+	bd := beads.New(foundRig.BeadsPath())
+	status, err := bd.SwarmStatus(swarmID)
+	if err != nil {
 		return fmt.Errorf("getting swarm status: %w", err)
 	}
 
-	var status struct {
-		Ready       []struct{ ID string } `json:"ready"`
-		Active      []struct{ ID string } `json:"active"`
-		Blocked     []struct{ ID string } `json:"blocked"`
-		Completed   []struct{ ID string } `json:"completed"`
-		TotalIssues int                   `json:"total_issues"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
-		return fmt.Errorf("parsing swarm status: %w", err)
-	}
-
 	// Check if all tasks are complete
 	if len(status.Ready) > 0 || len(status.Active) > 0 || len(status.Blocked) > 0 {
 		return fmt.Errorf("swarm has incomplete tasks: %d ready, %d active, %d blocked",
@@ -772,13 +610,7 @@ func runSwarmLand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Close the swarm epic in beads
-	closeArgs := []string{"close", swarmID, "--reason", "Swarm landed to main"}
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		closeArgs = append(closeArgs, "--session="+sessionID)
-	}
-	closeCmd := exec.Command("bd", closeArgs...)
-	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := bd.CloseWithReason("Swarm landed to main", swarmID); err != nil {
 		style.PrintWarning("couldn't close swarm epic in beads: %v", err)
 	}
 
@@ -792,52 +624,23 @@ func runSwarmCancel(cmd *cobra.Command, args []string) error {
 	swarmID := args[0]
 
 	// Find the swarm's rig
-	rigs, _, err := getAllRigs()
+	foundRig, _, err := findSwarmRig(swarmID, "")
 	if err != nil {
 		return err
 	}
 
-	var foundRig *rig.Rig
-	for _, r := range rigs {
-		// Use BeadsPath() for git-synced beads
-		checkCmd := exec.Command("bd", "show", swarmID, "--json")
-		checkCmd.Dir = r.BeadsPath()
-		if err := checkCmd.Run(); err == nil {
-			foundRig = r
-			break
-		}
-	}
-
-	if foundRig == nil {
-		return fmt.Errorf("swarm '%s' not found", swarmID)
-	}
-
 	// Check if swarm is already closed
-	checkCmd := exec.Command("bd", "show", swarmID, "--json")
-	checkCmd.Dir = foundRig.BeadsPath()
-	var stdout bytes.Buffer
-	checkCmd.Stdout = &stdout
-	if err := checkCmd.Run(); err != nil {
+	bd := beads.New(foundRig.BeadsPath())
+	issue, err := bd.Show(swarmID)
+	if err != nil {
 		return fmt.Errorf("checking swarm status: %w", err)
 	}
-
-	var issue struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issue); err == nil {
-		if issue.Status == "closed" {
-			return fmt.Errorf("swarm already closed")
-		}
+	if issue.Status == "closed" {
+		return fmt.Errorf("swarm already closed")
 	}
 
 	// Close the swarm epic in beads with canceled reason
-	closeArgs := []string{"close", swarmID, "--reason", "Swarm canceled"}
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		closeArgs = append(closeArgs, "--session="+sessionID)
-	}
-	closeCmd := exec.Command("bd", closeArgs...)
-	closeCmd.Dir = foundRig.BeadsPath()
-	if err := closeCmd.Run(); err != nil {
+	if err := bd.CloseWithReason("Swarm canceled", swarmID); err != nil {
 		return fmt.Errorf("closing swarm: %w", err)
 	}
 
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/swarm"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"golang.org/x/term"
+)
+
+// Swarm run/watch flags
+var (
+	swarmRunRig        string
+	swarmRunMaxWorkers int
+	swarmRunRigMax     int
+	swarmRunInterval   time.Duration
+	swarmRunOnce       bool
+	swarmRunDryRun     bool
+	swarmStatusWatch   bool
+	swarmStatusEvery   int
+)
+
+var swarmRunCmd = &cobra.Command{
+	Use:   "run <epic-id>",
+	Short: "Dispatch an epic's tasks wave by wave as dependencies complete",
+	Long: `Coordinate a swarm until its work is done.
+
+Plans the epic's tasks into dependency waves, then repeatedly dispatches
+ready tasks (all dependencies merged) to fresh polecats via 'gt sling'.
+As tasks close, the next wave's tasks become ready and are dispatched on
+the following pass. Tasks on the critical path are dispatched first.
+
+Concurrency is capped per swarm (--max-workers) and per rig (--rig-max,
+defaulting to the rig's max_polecats setting). The rig cap counts every
+working polecat in the rig, including ones busy outside this swarm.
+
+Exits when every task is complete, or when the remaining tasks are blocked
+behind failed ones.
+
+Examples:
+  gt swarm run gt-abc --max-workers 3
+  gt swarm run gt-abc --once --dry-run     # Show what would be dispatched now`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSwarmRun,
+}
+
+func init() {
+	swarmRunCmd.Flags().StringVar(&swarmRunRig, "rig", "", "Rig the epic lives in (auto-detected if not specified)")
+	swarmRunCmd.Flags().IntVar(&swarmRunMaxWorkers, "max-workers", 0, "Maximum concurrent tasks for this swarm (0 for no cap)")
+	swarmRunCmd.Flags().IntVar(&swarmRunRigMax, "rig-max", 0, "Maximum working polecats in the rig (default: rig max_polecats)")
+	swarmRunCmd.Flags().DurationVar(&swarmRunInterval, "interval", 30*time.Second, "How often to check for newly ready tasks")
+	swarmRunCmd.Flags().BoolVar(&swarmRunOnce, "once", false, "Dispatch one pass and exit")
+	swarmRunCmd.Flags().BoolVarP(&swarmRunDryRun, "dry-run", "n", false, "Show what would be dispatched without slinging")
+
+	swarmStatusCmd.Flags().BoolVarP(&swarmStatusWatch, "watch", "w", false, "Live view of waves, blocked tasks and critical path")
+	swarmStatusCmd.Flags().IntVarP(&swarmStatusEvery, "interval", "n", 5, "Refresh interval in seconds (with --watch)")
+
+	swarmCmd.AddCommand(swarmRunCmd)
+}
+
+// findSwarmRig returns the rig whose beads contain the epic, restricted
+// to rigName when given.
+func findSwarmRig(epicID, rigName string) (*rig.Rig, string, error) {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, r := range rigs {
+		if rigName != "" && r.Name != rigName {
+			continue
+		}
+		// Use BeadsPath() to ensure we read from git-synced location
+		if _, err := beads.New(r.BeadsPath()).Show(epicID); err == nil {
+			return r, townRoot, nil
+		}
+	}
+	if rigName != "" {
+		return nil, "", fmt.Errorf("swarm '%s' not found in rig '%s'", epicID, rigName)
+	}
+	return nil, "", fmt.Errorf("swarm '%s' not found in any rig", epicID)
+}
+
+// loadSwarmPlan loads a swarm with its task dependencies and plans waves.
+func loadSwarmPlan(r *rig.Rig, epicID string) (*swarm.Swarm, *swarm.WavePlan, error) {
+	mgr := swarm.NewManager(r)
+	sw, err := mgr.LoadSwarm(epicID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := mgr.LoadTaskDependencies(sw); err != nil {
+		return nil, nil, err
+	}
+	plan, err := swarm.PlanWaves(sw.Tasks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sw, plan, nil
+}
+
+// rigWorkingPolecats counts polecats currently working in a rig.
+func rigWorkingPolecats(r *rig.Rig) int {
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux())
+	polecats, err := mgr.List()
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, p := range polecats {
+		if p.State.IsActive() {
+			n++
+		}
+	}
+	return n
+}
+
+func runSwarmRun(cmd *cobra.Command, args []string) error {
+	epicID := args[0]
+	r, townRoot, err := findSwarmRig(epicID, swarmRunRig)
+	if err != nil {
+		return err
+	}
+
+	limits := swarm.Limits{MaxPerSwarm: swarmRunMaxWorkers, MaxPerRig: swarmRunRigMax}
+	if limits.MaxPerRig == 0 {
+		limits.MaxPerRig = r.GetIntConfig("max_polecats")
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	fmt.Printf("%s Coordinating swarm %s in %s (swarm cap %s, rig cap %s)\n",
+		style.Bold.Render("⚡"), epicID, r.Name, capString(limits.MaxPerSwarm), capString(limits.MaxPerRig))
+
+	lastWave := -1
+	for {
+		_, plan, err := loadSwarmPlan(r, epicID)
+		if err != nil {
+			return err
+		}
+
+		if plan.IsFinished() {
+			fmt.Printf("%s Swarm %s has nothing left to dispatch\n", style.Bold.Render("✓"), epicID)
+			return nil
+		}
+		if wave := plan.CurrentWave(); wave != lastWave {
+			fmt.Printf("\nWave %d/%d: %d task(s); critical path %d task(s) remaining\n",
+				wave+1, len(plan.Waves), len(plan.Waves[wave]), len(plan.CriticalPath))
+			lastWave = wave
+		}
+
+		for _, task := range swarm.SelectDispatch(plan, rigWorkingPolecats(r), limits) {
+			marker := ""
+			if plan.CriticalPath.Contains(task.IssueID) {
+				marker = " (critical path)"
+			}
+			if swarmRunDryRun {
+				fmt.Printf("  would dispatch %s: %s%s\n", task.IssueID, task.Title, marker)
+				continue
+			}
+			slingCmd := exec.Command("gt", "sling", task.IssueID, r.Name) //nolint:gosec // G204: IDs come from beads
+			slingCmd.Dir = townRoot
+			if out, err := slingCmd.CombinedOutput(); err != nil {
+				style.PrintWarning("couldn't dispatch %s: %v: %s", task.IssueID, err, strings.TrimSpace(string(out)))
+				continue
+			}
+			fmt.Printf("  %s dispatched %s: %s%s\n", style.Success.Render("→"), task.IssueID, task.Title, marker)
+		}
+
+		if swarmRunOnce {
+			return nil
+		}
+		select {
+		case <-sigChan:
+			fmt.Println("\nStopped. Dispatched work continues; run again to resume coordinating.")
+			return nil
+		case <-time.After(swarmRunInterval):
+		}
+	}
+}
+
+func capString(n int) string {
+	if n <= 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// runSwarmStatusWatch redraws the wave view until interrupted.
+func runSwarmStatusWatch(swarmID string) error {
+	if swarmStatusJSON {
+		return fmt.Errorf("--json and --watch cannot be used together")
+	}
+	if swarmStatusEvery <= 0 {
+		return fmt.Errorf("interval must be positive, got %d", swarmStatusEvery)
+	}
+	r, _, err := findSwarmRig(swarmID, "")
+	if err != nil {
+		return err
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(time.Duration(swarmStatusEvery) * time.Second)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	for {
+		if isTTY {
+			fmt.Print("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+		header := fmt.Sprintf("[%s] gt swarm status --watch (every %ds, Ctrl+C to stop)",
+			time.Now().Format("15:04:05"), swarmStatusEvery)
+		fmt.Printf("%s\n\n", style.Dim.Render(header))
+
+		if sw, plan, err := loadSwarmPlan(r, swarmID); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		} else {
+			printSwarmWaves(sw, plan)
+		}
+
+		select {
+		case <-sigChan:
+			if isTTY {
+				fmt.Println("\nStopped.")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printSwarmWaves renders a swarm's waves, blocked tasks and critical path.
+func printSwarmWaves(sw *swarm.Swarm, plan *swarm.WavePlan) {
+	tasks := make(map[string]swarm.SwarmTask, len(sw.Tasks))
+	for _, t := range sw.Tasks {
+		tasks[t.IssueID] = t
+	}
+	summary := sw.Summary()
+	fmt.Printf("%s %s [%s]  %d/%d merged, %d active, %d failed\n",
+		style.Bold.Render("Swarm"), sw.ID, sw.RigName,
+		summary.MergedTasks, summary.TotalTasks, summary.ActiveTasks, summary.FailedTasks)
+
+	if len(plan.CriticalPath) > 0 {
+		fmt.Printf("Critical path (%d remaining): %s\n", len(plan.CriticalPath), strings.Join(plan.CriticalPath, " → "))
+	} else {
+		fmt.Println("Critical path: done")
+	}
+
+	ready := make(map[string]bool)
+	for _, t := range plan.Ready() {
+		ready[t.IssueID] = true
+	}
+	current := plan.CurrentWave()
+	blocked := 0
+	for w, wave := range plan.Waves {
+		label := fmt.Sprintf("Wave %d", w+1)
+		if w == current {
+			label += " (current)"
+		}
+		fmt.Printf("\n%s\n", style.Bold.Render(label))
+		for _, id := range wave {
+			t := tasks[id]
+			icon, note := "○", "ready"
+			switch {
+			case t.State == swarm.TaskMerged:
+				icon, note = style.Success.Render("✓"), "merged"
+			case t.State == swarm.TaskFailed:
+				icon, note = style.Error.Render("✗"), "failed"
+			case t.Assignee != "" || t.State != swarm.TaskPending:
+				icon, note = style.Warning.Render("●"), string(t.State)
+				if t.Assignee != "" {
+					note += " · " + t.Assignee
+				}
+			case !ready[id]:
+				icon, note = style.Dim.Render("⏸"), "blocked by "+strings.Join(plan.BlockedBy(id), ", ")
+				blocked++
+			}
+			crit := ""
+			if plan.CriticalPath.Contains(id) {
+				crit = style.Bold.Render(" ◆")
+			}
+			fmt.Printf("  %s %s%s  %s  %s\n", icon, id, crit, t.Title, style.Dim.Render(note))
+		}
+	}
+	fmt.Printf("\n%d ready, %d blocked, %d in flight  (◆ = critical path)\n", len(ready), blocked, plan.InFlight())
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,8 +8,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/formula"
-	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -322,15 +320,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	}
 
 	// Close the convoy
-	closeArgs := []string{"close", convoyID, "--reason=synthesis complete"}
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		closeArgs = append(closeArgs, "--session="+sessionID)
-	}
-	closeCmd := exec.Command("bd", closeArgs...)
-	closeCmd.Dir = townBeads
-	closeCmd.Stderr = os.Stderr
-
-	if err := closeCmd.Run(); err != nil {
+	if err := townBeadsClient(townBeads).CloseWithReason("synthesis complete", convoyID); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -349,32 +339,14 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 		return nil, err
 	}
 
-	showCmd := exec.Command("bd", "show", convoyID, "--json")
-	showCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
+	convoy, err := townBeadsClient(townBeads).Show(convoyID)
+	if err != nil {
 		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
 	}
-
-	var convoys []struct {
-		ID          string `json:"id"`
-		Title       string `json:"title"`
-		Status      string `json:"status"`
-		Description string `json:"description"`
-		Type        string `json:"issue_type"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy data: %w", err)
-	}
-
-	if len(convoys) == 0 || convoys[0].Type != "convoy" {
+	if convoy.Type != "convoy" {
 		return nil, fmt.Errorf("'%s' is not a convoy", convoyID)
 	}
 
-	convoy := convoys[0]
-
 	// Parse formula and review ID from description
 	meta := &ConvoyMeta{
 		ID:     convoy.ID,
@@ -526,49 +498,26 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	}
 
 	// Create the bead
-	createArgs := []string{
-		"create",
-		"--type=task",
-		"--title=" + title,
-		"--description=" + desc.String(),
-		"--json",
-	}
-
 	townBeads, err := getTownBeadsDir()
 	if err != nil {
 		return "", err
 	}
 
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	createCmd.Stdout = &stdout
-	createCmd.Stderr = os.Stderr
-
-	if err := createCmd.Run(); err != nil {
+	bd := townBeadsClient(townBeads)
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       title,
+		IssueType:   "task",
+		Priority:    -1,
+		Description: desc.String(),
+	})
+	if err != nil {
 		return "", fmt.Errorf("creating synthesis bead: %w", err)
 	}
 
-	// Parse created bead ID
-	var result struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
-		// Try to extract ID from non-JSON output
-		out := strings.TrimSpace(stdout.String())
-		if strings.HasPrefix(out, "hq-") || strings.HasPrefix(out, "gt-") {
-			return out, nil
-		}
-		return "", fmt.Errorf("parsing created bead: %w", err)
-	}
-
 	// Add tracking relation: convoy tracks synthesis
-	depArgs := []string{"dep", "add", convoyID, result.ID, "--type=tracks"}
-	depCmd := exec.Command("bd", depArgs...)
-	depCmd.Dir = townBeads
-	_ = depCmd.Run() // Non-fatal if this fails
+	_ = bd.AddDependencyWithType(convoyID, issue.ID, "tracks") // Non-fatal if this fails
 
-	return result.ID, nil
+	return issue.ID, nil
 }
 
 // slingSynthesis slings the synthesis bead to a rig.
@@ -69,6 +69,20 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// UsageLimitPatterns are regular expressions matching the runtime's
+	// usage-limit and quota messages. Used to detect rate-limited sessions
+	// from pane output and transcripts so they can move to another account.
+	UsageLimitPatterns []string `json:"usage_limit_patterns,omitempty"`
+}
+
+// genericUsageLimitPatterns match common provider limit messages. Used for
+// presets that don't define their own.
+var genericUsageLimitPatterns = []string{
+	`(?i)usage limit (reached|exceeded)`,
+	`(?i)quota (exceeded|exhausted)`,
+	`(?i)rate[- ]limit(ed)? (reached|exceeded)`,
+	`(?i)\b429\b.*too many requests`,
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -109,6 +123,12 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       true,
 		SupportsForkSession: true,
 		NonInteractive:      nil, // Claude is native non-interactive
+		UsageLimitPatterns: []string{
+			`(?i)claude (ai )?usage limit reached`,
+			`(?i)(5-hour|weekly|opus) limit reached`,
+			`(?i)you've (hit|reached) your (usage )?limit`,
+			`(?i)"type":\s*"rate_limit_error"`,
+		},
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -124,6 +144,11 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
 		},
+		UsageLimitPatterns: []string{
+			`(?i)quota exceeded`,
+			`RESOURCE_EXHAUSTED`,
+			`(?i)you have reached your daily .* limit`,
+		},
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -139,6 +164,11 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 			Subcommand: "exec",
 			OutputFlag: "--json",
 		},
+		UsageLimitPatterns: []string{
+			`(?i)you've hit your usage limit`,
+			`(?i)rate limit reached`,
+			`(?i)\b429\b.*too many requests`,
+		},
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -391,6 +421,28 @@ func GetProcessNames(agentName string) []string {
 	return info.ProcessNames
 }
 
+// GetUsageLimitPatterns returns the usage-limit message patterns for an agent.
+// The agent may be named by preset or by command (e.g., "cursor-agent").
+// Falls back to generic patterns for unknown agents or presets without any.
+func GetUsageLimitPatterns(agentName string) []string {
+	info := GetAgentPresetByName(agentName)
+	if info == nil {
+		ensureRegistry()
+		registryMu.RLock()
+		for _, preset := range globalRegistry.Agents {
+			if preset.Command == agentName {
+				info = preset
+				break
+			}
+		}
+		registryMu.RUnlock()
+	}
+	if info == nil || len(info.UsageLimitPatterns) == 0 {
+		return genericUsageLimitPatterns
+	}
+	return info.UsageLimitPatterns
+}
+
 // MergeWithPreset applies preset defaults to a RuntimeConfig.
 // User-specified values take precedence over preset defaults.
 // Returns a new RuntimeConfig without modifying the original.
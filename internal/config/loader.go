@@ -218,6 +218,9 @@ func validateRigSettings(c *RigSettings) error {
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeMode indicates an invalid merge_mode.
+var ErrInvalidMergeMode = errors.New("invalid merge_mode")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -226,6 +229,70 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	// Validate merge mode and its PR settings
+	if c.MergeMode != "" && c.MergeMode != MergeModeDirect && c.MergeMode != MergeModePullRequest {
+		return fmt.Errorf("%w: got '%s', want '%s' or '%s'",
+			ErrInvalidMergeMode, c.MergeMode, MergeModeDirect, MergeModePullRequest)
+	}
+	switch c.PRMergeMethod {
+	case "", "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("invalid pr_merge_method '%s': want merge, squash or rebase", c.PRMergeMethod)
+	}
+	if c.PRTimeout != "" {
+		if _, err := time.ParseDuration(c.PRTimeout); err != nil {
+			return fmt.Errorf("invalid pr_timeout: %w", err)
+		}
+	}
+	if c.Forge != nil {
+		switch c.Forge.Type {
+		case "", "github", "gitlab", "gitea":
+		default:
+			return fmt.Errorf("invalid forge type '%s': want github, gitlab or gitea", c.Forge.Type)
+		}
+	}
+
+	if c.TestCacheTTL != "" {
+		if _, err := time.ParseDuration(c.TestCacheTTL); err != nil {
+			return fmt.Errorf("invalid test_cache_ttl: %w", err)
+		}
+	}
+
+	// Validate gates (types are checked by the refinery, which may register more)
+	gateNames := make(map[string]bool)
+	for i, g := range c.Gates {
+		name := g.Name
+		if name == "" {
+			name = g.Type
+		}
+		if name == "" {
+			name = "command"
+		}
+		if gateNames[name] {
+			return fmt.Errorf("%w: gate %d has duplicate name '%s'", ErrMissingField, i+1, name)
+		}
+		gateNames[name] = true
+		if g.Timeout != "" {
+			if _, err := time.ParseDuration(g.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout for gate '%s': %w", name, err)
+			}
+		}
+		switch g.Report {
+		case "", "go-json", "junit", "tap", "none":
+		default:
+			return fmt.Errorf("invalid report format '%s' for gate '%s': want go-json, junit, tap or none", g.Report, name)
+		}
+	}
+
+	if c.Flaky != nil {
+		if c.Flaky.Threshold < 0 || c.Flaky.Threshold > 1 {
+			return fmt.Errorf("%w: flaky.threshold must be between 0 and 1", ErrMissingField)
+		}
+		if c.Flaky.MinRuns < 0 || c.Flaky.Window < 0 || c.Flaky.BugAfter < 0 {
+			return fmt.Errorf("%w: flaky settings must be non-negative", ErrMissingField)
+		}
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
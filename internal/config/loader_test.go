@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -235,6 +236,79 @@ func TestRigSettingsWithCustomMergeQueue(t *testing.T) {
 	}
 }
 
+func TestMergeQueuePullRequestMode(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+
+	original := &RigSettings{
+		Type:    "rig-settings",
+		Version: 1,
+		MergeQueue: &MergeQueueConfig{
+			Enabled:       true,
+			TargetBranch:  "main",
+			MergeMode:     MergeModePullRequest,
+			Forge:         &ForgeConfig{Type: "gitea", URL: "https://git.example.com/api/v1"},
+			PRMergeMethod: "squash",
+			PRTimeout:     "15m",
+		},
+	}
+	if err := SaveRigSettings(path, original); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	loaded, err := LoadRigSettings(path)
+	if err != nil {
+		t.Fatalf("LoadRigSettings: %v", err)
+	}
+	mq := loaded.MergeQueue
+	if mq.MergeMode != MergeModePullRequest || mq.Forge == nil || mq.Forge.Type != "gitea" || mq.PRMergeMethod != "squash" {
+		t.Errorf("loaded = %+v, want pull_request via gitea with squash", mq)
+	}
+
+	for _, bad := range []*MergeQueueConfig{
+		{MergeMode: "yolo"},
+		{PRMergeMethod: "fast-forward"},
+		{PRTimeout: "soon"},
+		{Forge: &ForgeConfig{Type: "bitbucket"}},
+	} {
+		if err := validateMergeQueueConfig(bad); err == nil {
+			t.Errorf("validateMergeQueueConfig(%+v) = nil, want error", bad)
+		}
+	}
+	if err := validateMergeQueueConfig(&MergeQueueConfig{MergeMode: "yolo"}); !errors.Is(err, ErrInvalidMergeMode) {
+		t.Errorf("bad merge_mode error = %v, want ErrInvalidMergeMode", err)
+	}
+}
+
+func TestMergeQueueGatesValidation(t *testing.T) {
+	t.Parallel()
+	ok := &MergeQueueConfig{Gates: []GateConfig{
+		{Name: "vet", Command: "go vet ./...", Timeout: "5m"},
+		{Type: "forbidden_paths", Paths: []string{"deploy/**"}},
+		{Type: "secrets"},
+	}}
+	if err := validateMergeQueueConfig(ok); err != nil {
+		t.Errorf("valid gates: %v", err)
+	}
+
+	for _, bad := range [][]GateConfig{
+		{{Name: "lint", Timeout: "whenever"}},
+		{{Name: "lint"}, {Name: "lint"}},
+		{{Type: "secrets"}, {Type: "secrets"}},
+		{{Type: "tests", Report: "xunit"}},
+	} {
+		if err := validateMergeQueueConfig(&MergeQueueConfig{Gates: bad}); err == nil {
+			t.Errorf("validateMergeQueueConfig(gates %+v) = nil, want error", bad)
+		}
+	}
+	if err := validateMergeQueueConfig(&MergeQueueConfig{TestCacheTTL: "a week"}); err == nil {
+		t.Error("bad test_cache_ttl accepted")
+	}
+	if err := validateMergeQueueConfig(&MergeQueueConfig{Flaky: &FlakyConfig{Threshold: 1.5}}); err == nil {
+		t.Error("flaky threshold above 1 accepted")
+	}
+}
+
 func TestRigConfigValidation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
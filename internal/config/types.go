@@ -61,6 +61,65 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Tracing enables OpenTelemetry tracing of work from sling to merge.
+	// OTEL_EXPORTER_OTLP_ENDPOINT and GT_TRACE_FILE override it.
+	Tracing *TracingConfig `json:"tracing,omitempty"`
+
+	// Retention sets log rotation and retention per source: "events",
+	// "feed", "townlog", "daemon", "mail" (mail archives) and "recordings"
+	// (session recordings). Unset fields
+	// fall back to DefaultRetentionPolicies.
+	Retention map[string]*RetentionPolicy `json:"retention,omitempty"`
+
+	// Recording records agent panes continuously for 'gt replay'.
+	// Recordings are kept per the "recordings" retention policy.
+	Recording *RecordingConfig `json:"recording,omitempty"`
+}
+
+// TracingConfig selects where trace spans are exported. Set one field.
+type TracingConfig struct {
+	// Endpoint is an OTLP/HTTP collector base URL (e.g. "http://localhost:4318").
+	// Spans are posted as JSON to <endpoint>/v1/traces.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// File appends spans as OTLP/JSON lines. Relative paths are resolved
+	// against the town root.
+	File string `json:"file,omitempty"`
+}
+
+// RecordingConfig enables session recording of agent panes.
+type RecordingConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Roles limits recording to these roles (e.g. ["polecat", "crew"]).
+	// Empty records every role.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// RetentionPolicy controls when a log file is rotated and how long its
+// rotated segments are kept. Durations accept a "d" suffix for days.
+type RetentionPolicy struct {
+	MaxSizeMB    int    `json:"max_size_mb,omitempty"`   // rotate once the live file is this large
+	MaxAge       string `json:"max_age,omitempty"`       // rotate once the live file's first entry is this old
+	Keep         string `json:"keep,omitempty"`          // delete segments rotated longer ago than this
+	KeepSegments int    `json:"keep_segments,omitempty"` // keep at most this many segments (0 = no limit)
+	Compress     *bool  `json:"compress,omitempty"`      // gzip rotated segments (default true)
+}
+
+// DefaultRetentionPolicies returns the built-in policy for each log source.
+func DefaultRetentionPolicies() map[string]*RetentionPolicy {
+	return map[string]*RetentionPolicy{
+		"events":  {MaxSizeMB: 50, MaxAge: "7d", Keep: "90d"},
+		"feed":    {MaxSizeMB: 10, MaxAge: "7d", Keep: "30d"},
+		"townlog": {MaxSizeMB: 10, MaxAge: "7d", Keep: "90d"},
+		"daemon":  {MaxSizeMB: 10, MaxAge: "7d", Keep: "30d"},
+		"mail":    {MaxSizeMB: 10, MaxAge: "30d", Keep: "365d"},
+
+		// Recordings are whole files, pruned by last write; the size and
+		// age limits rotate their index.
+		"recordings": {MaxSizeMB: 10, MaxAge: "30d", Keep: "14d"},
+	}
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -87,6 +146,52 @@ type DaemonPatrolConfig struct {
 	Version   int                     `json:"version"`             // schema version
 	Heartbeat *HeartbeatConfig        `json:"heartbeat,omitempty"` // heartbeat settings
 	Patrols   map[string]PatrolConfig `json:"patrols,omitempty"`   // named patrol configurations
+	Doctor    *DoctorPatrolConfig     `json:"doctor,omitempty"`    // continuous doctor checks
+}
+
+// DoctorPatrolConfig configures the daemon's continuous doctor runs.
+// Checks run town-wide; RigChecks run once per rig and are recorded as
+// <rig>/<check>. Only cheap checks belong here. Continuous doctor is off
+// unless Enabled; with no checks listed it runs the default set.
+type DoctorPatrolConfig struct {
+	Enabled   bool     `json:"enabled"`              // whether continuous doctor runs
+	Interval  string   `json:"interval,omitempty"`   // e.g., "15m"
+	Checks    []string `json:"checks,omitempty"`     // town-level check names
+	RigChecks []string `json:"rig_checks,omitempty"` // per-rig check names
+}
+
+// DefaultDoctorPatrolInterval is how often continuous doctor runs by default.
+const DefaultDoctorPatrolInterval = 15 * time.Minute
+
+// NewDoctorPatrolConfig returns the default continuous doctor config:
+// disabled, with a handful of cheap checks that catch configuration drift.
+func NewDoctorPatrolConfig() *DoctorPatrolConfig {
+	return &DoctorPatrolConfig{
+		Enabled:  false,
+		Interval: DefaultDoctorPatrolInterval.String(),
+		Checks: []string{
+			"town-config-valid",
+			"rigs-registry-valid",
+			"routes-config",
+			"stale-binary",
+		},
+		RigChecks: []string{
+			"sparse-checkout",
+			"hooks-path-configured",
+			"git-exclude-configured",
+		},
+	}
+}
+
+// GetInterval returns the configured interval, or the default if unset
+// or invalid.
+func (c *DoctorPatrolConfig) GetInterval() time.Duration {
+	if c != nil && c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultDoctorPatrolInterval
 }
 
 // HeartbeatConfig represents heartbeat settings for daemon.
@@ -134,6 +239,7 @@ func NewDaemonPatrolConfig() *DaemonPatrolConfig {
 				Agent:    "refinery",
 			},
 		},
+		Doctor: NewDoctorPatrolConfig(),
 	}
 }
 
@@ -661,6 +767,116 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// MergeMode is how verified MRs land: "direct" pushes to the target
+	// branch, "pull_request" opens a PR on the forge and merges it via the
+	// API once its checks and reviews pass. Default: "direct".
+	MergeMode string `json:"merge_mode,omitempty"`
+
+	// Forge configures the code host used in pull_request mode.
+	Forge *ForgeConfig `json:"forge,omitempty"`
+
+	// PRMergeMethod is the forge merge method: "merge", "squash" or "rebase".
+	PRMergeMethod string `json:"pr_merge_method,omitempty"`
+
+	// PRTimeout is how long one pass waits on a PR before leaving it
+	// queued for the next (e.g., "10m").
+	PRTimeout string `json:"pr_timeout,omitempty"`
+
+	// Gates is the ordered pre-merge gate pipeline. When empty, TestCommand
+	// is the only gate.
+	Gates []GateConfig `json:"gates,omitempty"`
+
+	// TestCache reuses a passing test run when the merged tree, test command
+	// and environment match one already tested. Nil means enabled.
+	TestCache *bool `json:"test_cache,omitempty"`
+
+	// TestCacheTTL is how long a cached pass stays valid (e.g., "168h").
+	TestCacheTTL string `json:"test_cache_ttl,omitempty"`
+
+	// TestCacheEnv names the environment variables in the cache key.
+	TestCacheEnv []string `json:"test_cache_env,omitempty"`
+
+	// Flaky tunes flaky test classification from per-test history.
+	Flaky *FlakyConfig `json:"flaky,omitempty"`
+}
+
+// FlakyConfig tunes how the refinery classifies flaky tests. Zero values
+// take the defaults.
+type FlakyConfig struct {
+	// Threshold is the flip rate (0-1) at which a test is flaky. Default: 0.3.
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// MinRuns is the history needed before classifying. Default: 5.
+	MinRuns int `json:"min_runs,omitempty"`
+
+	// Window is how many recent runs are kept per test. Default: 30.
+	Window int `json:"window,omitempty"`
+
+	// BugAfter files a bug after this many flaky failures. Default: 3.
+	BugAfter int `json:"bug_after,omitempty"`
+}
+
+// GateConfig configures one pre-merge quality gate. Gates run in order in
+// the merge worktree; the first blocking failure goes back to the worker.
+type GateConfig struct {
+	// Name identifies the gate in results (defaults to the type).
+	Name string `json:"name,omitempty"`
+
+	// Type is "command" (default), "tests", "coverage", "diff_size",
+	// "forbidden_paths" or "secrets".
+	Type string `json:"type,omitempty"`
+
+	// Command is the shell command for command, tests and coverage gates.
+	Command string `json:"command,omitempty"`
+
+	// Timeout bounds the gate (e.g., "5m"). Default: 10m.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Advisory gates report failures without blocking the merge.
+	Advisory bool `json:"advisory,omitempty"`
+
+	// Paths are glob patterns a forbidden_paths gate rejects (e.g., "deploy/**").
+	Paths []string `json:"paths,omitempty"`
+
+	// MaxLines and MaxFiles limit a diff_size gate.
+	MaxLines int `json:"max_lines,omitempty"`
+	MaxFiles int `json:"max_files,omitempty"`
+
+	// MinCoverage is the coverage gate's threshold in percent.
+	MinCoverage float64 `json:"min_coverage,omitempty"`
+
+	// Patterns are extra regular expressions for a secrets gate.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Report is a tests gate's report format: "go-json", "junit", "tap" or
+	// "none". Empty detects it from the output.
+	Report string `json:"report,omitempty"`
+
+	// ReportFile reads the report from a file in the worktree.
+	ReportFile string `json:"report_file,omitempty"`
+
+	// RerunCommand reruns only failing tests ({tests}, {names}, {packages}).
+	RerunCommand string `json:"rerun_command,omitempty"`
+
+	// IgnoreFlaky lets failures of tests classified flaky pass.
+	IgnoreFlaky bool `json:"ignore_flaky,omitempty"`
+}
+
+// ForgeConfig selects and authenticates the forge for pull_request mode.
+// Unset fields are derived from the rig's origin remote.
+type ForgeConfig struct {
+	// Type is "github", "gitlab" or "gitea".
+	Type string `json:"type,omitempty"`
+
+	// URL is the API base URL (e.g., "https://git.example.com/api/v1").
+	URL string `json:"url,omitempty"`
+
+	// Repo is the owner/name (GitLab: project path).
+	Repo string `json:"repo,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	TokenEnv string `json:"token_env,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -669,6 +885,12 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// Merge mode constants.
+const (
+	MergeModeDirect      = "direct"
+	MergeModePullRequest = "pull_request"
+)
+
 // DefaultMergeQueueConfig returns a MergeQueueConfig with sensible defaults.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
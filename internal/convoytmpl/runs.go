@@ -0,0 +1,104 @@
+package convoytmpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Run records one instantiation of a template.
+type Run struct {
+	Number    int               `json:"number"`
+	ConvoyID  string            `json:"convoy_id"`
+	At        time.Time         `json:"at"`
+	Scheduled bool              `json:"scheduled,omitempty"` // Started by the daemon
+	Issues    map[string]string `json:"issues"`              // Ref → issue ID
+}
+
+// History is the run record of one template.
+type History struct {
+	Runs []Run `json:"runs,omitempty"`
+
+	// ArmedAt is when the daemon first saw the template's schedule. A new
+	// schedule fires at its next occurrence after this, not retroactively.
+	ArmedAt time.Time `json:"armed_at,omitempty"`
+
+	// ClaimedAt is when the daemon last started a scheduled run. It is
+	// saved before the run starts, so the occurrence is spent even if the
+	// run fails or its result can't be recorded, and is never run twice.
+	ClaimedAt time.Time `json:"claimed_at,omitempty"`
+}
+
+// Last returns the most recent run, or nil.
+func (h *History) Last() *Run {
+	if len(h.Runs) == 0 {
+		return nil
+	}
+	return &h.Runs[len(h.Runs)-1]
+}
+
+// NextNumber returns the number the next run will get.
+func (h *History) NextNumber() int {
+	if last := h.Last(); last != nil {
+		return last.Number + 1
+	}
+	return 1
+}
+
+// NextDue returns when a scheduled template next comes due: the first
+// occurrence after the latest of its last run, its last claimed scheduled
+// run, and when it was armed. It returns the zero time if the template has no schedule or has
+// not been armed.
+func (h *History) NextDue(t *Template) time.Time {
+	sched := t.ScheduleSpec()
+	if sched == nil {
+		return time.Time{}
+	}
+	base := h.ArmedAt
+	if last := h.Last(); last != nil && last.At.After(base) {
+		base = last.At
+	}
+	if h.ClaimedAt.After(base) {
+		base = h.ClaimedAt
+	}
+	if base.IsZero() {
+		return time.Time{}
+	}
+	return sched.Next(base.Local())
+}
+
+// runsPath returns where a template's run history is kept.
+func runsPath(townRoot, name string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "convoy-templates", name+".json")
+}
+
+// LoadHistory loads a template's run history. A missing file yields an
+// empty history.
+func LoadHistory(townRoot, name string) (*History, error) {
+	data, err := os.ReadFile(runsPath(townRoot, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &History{}, nil
+		}
+		return nil, err
+	}
+	var h History
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("parsing run history for %s: %w", name, err)
+	}
+	return &h, nil
+}
+
+// SaveHistory writes a template's run history.
+func SaveHistory(townRoot, name string, h *History) error {
+	path := runsPath(townRoot, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, h)
+}
@@ -0,0 +1,381 @@
+// Package convoytmpl defines convoy templates: TOML files describing a
+// recurring batch of work (issues per rig, their dependencies and labels)
+// that `gt convoy template run` turns into real issues tracked by a new
+// convoy, on demand or on a cron schedule driven by the daemon.
+package convoytmpl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/cron"
+)
+
+// FileExt is the extension of template files.
+const FileExt = ".toml"
+
+// Template describes a convoy and the issues it creates.
+//
+// Titles and descriptions may use {{date}}, {{week}}, {{run}} and, in
+// per-rig issues, {{rig}}, plus any variables passed with --var.
+type Template struct {
+	// Name identifies the template; it is the file name without .toml.
+	Name string `toml:"-"`
+
+	// Title is the convoy title.
+	Title       string `toml:"title"`
+	Description string `toml:"description,omitempty"`
+
+	Owner  string `toml:"owner,omitempty"`  // Receives the landing notification
+	Notify string `toml:"notify,omitempty"` // Additional subscriber
+
+	// Labels are added to every created issue.
+	Labels []string `toml:"labels,omitempty"`
+
+	// Sling dispatches ready issues (those with no dependencies inside the
+	// template) to their rigs as soon as they're created.
+	Sling bool `toml:"sling,omitempty"`
+
+	// Schedule is an optional cron expression; when set, the daemon runs
+	// the template each time it comes due.
+	Schedule string `toml:"schedule,omitempty"`
+
+	Issues []IssueSpec `toml:"issue"`
+
+	path string
+}
+
+// IssueSpec is one issue in a template. With Rigs set, it expands into one
+// issue per rig.
+type IssueSpec struct {
+	// Key names the issue for depends_on references; defaults to the
+	// 1-based position, e.g. "3".
+	Key string `toml:"key,omitempty"`
+
+	Rig  string   `toml:"rig,omitempty"`
+	Rigs []string `toml:"rigs,omitempty"`
+
+	Title       string   `toml:"title"`
+	Description string   `toml:"description,omitempty"`
+	Type        string   `toml:"type,omitempty"` // Default: task
+	Priority    *int     `toml:"priority,omitempty"`
+	Labels      []string `toml:"labels,omitempty"`
+
+	// DependsOn lists keys of issues this one is blocked by. A per-rig
+	// issue depending on another per-rig key is blocked only by the
+	// instance in its own rig when there is one.
+	DependsOn []string `toml:"depends_on,omitempty"`
+
+	// Sling overrides the template's Sling for this issue.
+	Sling *bool `toml:"sling,omitempty"`
+}
+
+// Path returns the file the template was loaded from, if any.
+func (t *Template) Path() string {
+	return t.path
+}
+
+// Dir returns the town's convoy template directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "convoys")
+}
+
+// Parse parses and validates template TOML.
+func Parse(data []byte) (*Template, error) {
+	var t Template
+	if _, err := toml.Decode(string(data), &t); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ParseFile reads a template file, naming it after the file.
+func ParseFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the town template directory
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	var t Template
+	if _, err := toml.Decode(string(data), &t); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	t.Name = strings.TrimSuffix(filepath.Base(path), FileExt)
+	t.path = path
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return &t, nil
+}
+
+// Load loads a template by name from the town's template directory.
+func Load(townRoot, name string) (*Template, error) {
+	path := filepath.Join(Dir(townRoot), name+FileExt)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("convoy template %q not found (looked in %s)", name, Dir(townRoot))
+	}
+	return ParseFile(path)
+}
+
+// List loads every template in the town's template directory, sorted by
+// name. Templates that fail to parse are returned in errs rather than
+// hiding the others.
+func List(townRoot string) (templates []*Template, errs []error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), FileExt) || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		t, err := ParseFile(filepath.Join(Dir(townRoot), e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, errs
+}
+
+// Validate checks the template for missing fields, unknown dependency keys,
+// dependency cycles and a bad schedule.
+func (t *Template) Validate() error {
+	if t.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(t.Issues) == 0 {
+		return fmt.Errorf("at least one [[issue]] is required")
+	}
+	if t.Schedule != "" {
+		if _, err := cron.Parse(t.Schedule); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+	}
+
+	keys := make(map[string]bool, len(t.Issues))
+	for i := range t.Issues {
+		spec := &t.Issues[i]
+		if spec.Key == "" {
+			spec.Key = fmt.Sprintf("%d", i+1)
+		}
+		if keys[spec.Key] {
+			return fmt.Errorf("duplicate issue key %q", spec.Key)
+		}
+		keys[spec.Key] = true
+		if spec.Title == "" {
+			return fmt.Errorf("issue %q: title is required", spec.Key)
+		}
+		if spec.Rig != "" && len(spec.Rigs) > 0 {
+			return fmt.Errorf("issue %q: set rig or rigs, not both", spec.Key)
+		}
+	}
+	for _, spec := range t.Issues {
+		for _, dep := range spec.DependsOn {
+			if !keys[dep] {
+				return fmt.Errorf("issue %q depends on unknown key %q", spec.Key, dep)
+			}
+		}
+	}
+
+	// Expansion catches dependency cycles
+	_, err := t.Expand(Vars{})
+	return err
+}
+
+// ScheduleSpec returns the parsed schedule, or nil if the template isn't
+// recurring.
+func (t *Template) ScheduleSpec() *cron.Schedule {
+	if t.Schedule == "" {
+		return nil
+	}
+	s, err := cron.Parse(t.Schedule)
+	if err != nil {
+		return nil // Validate rejects bad schedules
+	}
+	return s
+}
+
+// Vars are the substitutions applied to titles and descriptions.
+type Vars map[string]string
+
+// StandardVars returns the built-in variables for a run at the given time.
+func StandardVars(now time.Time, run int) Vars {
+	year, week := now.ISOWeek()
+	return Vars{
+		"date": now.Format("2006-01-02"),
+		"week": fmt.Sprintf("%d-W%02d", year, week),
+		"run":  fmt.Sprintf("%d", run),
+	}
+}
+
+// Apply substitutes {{name}} placeholders.
+func (v Vars) Apply(s string) string {
+	if len(v) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+	pairs := make([]string, 0, 2*len(v))
+	for k, val := range v {
+		pairs = append(pairs, "{{"+k+"}}", val)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// with returns a copy of v with one more variable.
+func (v Vars) with(k, val string) Vars {
+	out := make(Vars, len(v)+1)
+	for key, value := range v {
+		out[key] = value
+	}
+	out[k] = val
+	return out
+}
+
+// PlannedIssue is one concrete issue a template run will create.
+type PlannedIssue struct {
+	Ref         string // Key, or key/rig for per-rig issues
+	Rig         string // Empty means town beads
+	Title       string
+	Description string
+	Type        string
+	Priority    int // -1 means the bd default
+	Labels      []string
+	DependsOn   []string // Refs of blocking issues
+	Sling       bool
+}
+
+// Expand turns the template into concrete issues in dependency order
+// (every issue follows the issues it depends on).
+func (t *Template) Expand(vars Vars) ([]PlannedIssue, error) {
+	byKey := make(map[string][]int) // Key → indexes into planned
+	var planned []PlannedIssue
+
+	for _, spec := range t.Issues {
+		rigs := spec.Rigs
+		if len(rigs) == 0 {
+			rigs = []string{spec.Rig}
+		}
+		for _, rig := range rigs {
+			ref := spec.Key
+			v := vars
+			if len(spec.Rigs) > 0 {
+				ref = spec.Key + "/" + rig
+				v = vars.with("rig", rig)
+			}
+			p := PlannedIssue{
+				Ref:         ref,
+				Rig:         rig,
+				Title:       v.Apply(spec.Title),
+				Description: v.Apply(spec.Description),
+				Type:        spec.Type,
+				Priority:    -1,
+				Labels:      mergeLabels(t.Labels, spec.Labels),
+				Sling:       t.Sling,
+			}
+			if p.Type == "" {
+				p.Type = "task"
+			}
+			if spec.Priority != nil {
+				p.Priority = *spec.Priority
+			}
+			if spec.Sling != nil {
+				p.Sling = *spec.Sling
+			}
+			byKey[spec.Key] = append(byKey[spec.Key], len(planned))
+			planned = append(planned, p)
+		}
+	}
+
+	// Resolve key dependencies to refs
+	i := 0
+	for _, spec := range t.Issues {
+		n := len(byKey[spec.Key])
+		for j := 0; j < n; j++ {
+			p := &planned[i]
+			for _, dep := range spec.DependsOn {
+				p.DependsOn = append(p.DependsOn, resolveDep(planned, byKey[dep], p.Rig, len(spec.Rigs) > 0)...)
+			}
+			i++
+		}
+	}
+
+	return sortPlanned(planned)
+}
+
+// resolveDep returns the refs a dependency key stands for: the same-rig
+// instance for per-rig issues when there is one, otherwise every instance.
+func resolveDep(planned []PlannedIssue, idxs []int, rig string, perRig bool) []string {
+	if perRig {
+		for _, i := range idxs {
+			if planned[i].Rig == rig {
+				return []string{planned[i].Ref}
+			}
+		}
+	}
+	refs := make([]string, 0, len(idxs))
+	for _, i := range idxs {
+		refs = append(refs, planned[i].Ref)
+	}
+	return refs
+}
+
+// sortPlanned orders issues so dependencies come first, keeping template
+// order otherwise. A cycle is an error naming the issues involved.
+func sortPlanned(planned []PlannedIssue) ([]PlannedIssue, error) {
+	done := make(map[string]bool, len(planned))
+	out := make([]PlannedIssue, 0, len(planned))
+	for len(out) < len(planned) {
+		progressed := false
+		for _, p := range planned {
+			if done[p.Ref] {
+				continue
+			}
+			ready := true
+			for _, dep := range p.DependsOn {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[p.Ref] = true
+				out = append(out, p)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var cycle []string
+			for _, p := range planned {
+				if !done[p.Ref] {
+					cycle = append(cycle, p.Ref)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle among issues: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return out, nil
+}
+
+func mergeLabels(a, b []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, l := range append(append([]string(nil), a...), b...) {
+		if !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package convoytmpl
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const depsTemplate = `
+title = "Dependency bumps {{week}}"
+labels = ["deps"]
+sling = true
+schedule = "0 9 * * mon"
+
+[[issue]]
+key = "bump"
+rigs = ["gastown", "beads"]
+title = "Bump dependencies in {{rig}}"
+labels = ["size:s"]
+
+[[issue]]
+key = "verify"
+rigs = ["gastown", "beads"]
+title = "Verify {{rig}} after bump"
+depends_on = ["bump"]
+sling = false
+
+[[issue]]
+key = "release"
+rig = "gastown"
+title = "Release {{version}}"
+type = "chore"
+priority = 1
+depends_on = ["verify"]
+`
+
+func TestExpand(t *testing.T) {
+	tmpl, err := Parse([]byte(depsTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := StandardVars(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), 3)
+	vars["version"] = "2.1"
+	planned, err := tmpl.Expand(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var refs []string
+	byRef := make(map[string]PlannedIssue)
+	for _, p := range planned {
+		refs = append(refs, p.Ref)
+		byRef[p.Ref] = p
+	}
+	want := []string{"bump/gastown", "bump/beads", "verify/gastown", "verify/beads", "release"}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("refs = %v, want %v", refs, want)
+	}
+
+	if got := byRef["bump/beads"].Title; got != "Bump dependencies in beads" {
+		t.Errorf("title = %q", got)
+	}
+	if got := byRef["bump/beads"].Labels; !reflect.DeepEqual(got, []string{"deps", "size:s"}) {
+		t.Errorf("labels = %v", got)
+	}
+	// Per-rig dependency resolves to the same rig's instance
+	if got := byRef["verify/beads"].DependsOn; !reflect.DeepEqual(got, []string{"bump/beads"}) {
+		t.Errorf("verify/beads deps = %v", got)
+	}
+	// Single issue depends on every instance
+	if got := byRef["release"].DependsOn; !reflect.DeepEqual(got, []string{"verify/gastown", "verify/beads"}) {
+		t.Errorf("release deps = %v", got)
+	}
+	if r := byRef["release"]; r.Title != "Release 2.1" || r.Type != "chore" || r.Priority != 1 {
+		t.Errorf("release = %+v", r)
+	}
+	if !byRef["bump/gastown"].Sling || byRef["verify/gastown"].Sling {
+		t.Error("sling override not applied")
+	}
+	if byRef["bump/gastown"].Type != "task" || byRef["bump/gastown"].Priority != -1 {
+		t.Errorf("defaults not applied: %+v", byRef["bump/gastown"])
+	}
+	if got := vars.Apply(tmpl.Title); got != "Dependency bumps 2026-W43" {
+		t.Errorf("title = %q", got)
+	}
+}
+
+func TestExpand_OrdersDependenciesFirst(t *testing.T) {
+	tmpl, err := Parse([]byte(`
+title = "t"
+[[issue]]
+key = "b"
+title = "B"
+depends_on = ["a"]
+[[issue]]
+key = "a"
+title = "A"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	planned, _ := tmpl.Expand(nil)
+	if planned[0].Ref != "a" || planned[1].Ref != "b" {
+		t.Errorf("order = %v, %v", planned[0].Ref, planned[1].Ref)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]string{
+		"no title":     "[[issue]]\ntitle = \"x\"",
+		"no issues":    "title = \"t\"",
+		"unknown dep":  "title = \"t\"\n[[issue]]\ntitle = \"x\"\ndepends_on = [\"nope\"]",
+		"bad schedule": "title = \"t\"\nschedule = \"every day\"\n[[issue]]\ntitle = \"x\"",
+		"rig and rigs": "title = \"t\"\n[[issue]]\ntitle = \"x\"\nrig = \"a\"\nrigs = [\"b\"]",
+		"cycle": `title = "t"
+[[issue]]
+key = "a"
+title = "A"
+depends_on = ["b"]
+[[issue]]
+key = "b"
+title = "B"
+depends_on = ["a"]`,
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	_, err := Parse([]byte("title = \"t\"\n[[issue]]\ntitle = \"x\"\n[[issue]]\ntitle = \"y\"\ndepends_on = [\"1\"]"))
+	if err != nil {
+		t.Errorf("positional keys should resolve: %v", err)
+	}
+}
+
+func TestListAndLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := Dir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "deps.toml"), []byte(depsTemplate), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "broken.toml"), []byte("title = "), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0644)
+
+	templates, errs := List(townRoot)
+	if len(templates) != 1 || templates[0].Name != "deps" {
+		t.Errorf("templates = %v", templates)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken.toml") {
+		t.Errorf("errs = %v", errs)
+	}
+
+	if _, err := Load(townRoot, "missing"); err == nil {
+		t.Error("expected error for missing template")
+	}
+	if tmpl, err := Load(townRoot, "deps"); err != nil || tmpl.ScheduleSpec() == nil {
+		t.Errorf("Load(deps) = %v, %v", tmpl, err)
+	}
+}
+
+func TestHistoryNextDue(t *testing.T) {
+	townRoot := t.TempDir()
+	tmpl, err := Parse([]byte(depsTemplate)) // Mondays at 09:00
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := LoadHistory(townRoot, "deps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h.NextDue(tmpl).IsZero() {
+		t.Error("unarmed template should not be due")
+	}
+	if h.NextNumber() != 1 {
+		t.Errorf("NextNumber = %d", h.NextNumber())
+	}
+
+	// Armed on a Wednesday: due the following Monday
+	h.ArmedAt = time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	if due := h.NextDue(tmpl); !due.Equal(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("NextDue = %v", due)
+	}
+
+	// After a run, due a week later
+	h.Runs = append(h.Runs, Run{Number: 1, ConvoyID: "hq-cv-a", At: time.Date(2026, 10, 19, 9, 1, 0, 0, time.Local)})
+	if due := h.NextDue(tmpl); !due.Equal(time.Date(2026, 10, 26, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("NextDue after run = %v", due)
+	}
+
+	// A claimed run spends its occurrence even if it never recorded a run
+	h.ClaimedAt = time.Date(2026, 10, 26, 9, 0, 30, 0, time.Local)
+	if due := h.NextDue(tmpl); !due.Equal(time.Date(2026, 11, 2, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("NextDue after claim = %v", due)
+	}
+
+	if err := SaveHistory(townRoot, "deps", h); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadHistory(townRoot, "deps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Last().ConvoyID != "hq-cv-a" || loaded.NextNumber() != 2 {
+		t.Errorf("loaded = %+v", loaded)
+	}
+}
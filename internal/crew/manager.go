@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -410,9 +409,7 @@ func (m *Manager) Pristine(name string) (*PristineResult, error) {
 
 // runBdSync runs bd sync in the given directory.
 func (m *Manager) runBdSync(dir string) error {
-	cmd := exec.Command("bd", "sync")
-	cmd.Dir = dir
-	return cmd.Run()
+	return beads.New(dir).Sync()
 }
 
 // PristineResult captures the results of a pristine operation.
@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// atomicLandTimeout bounds one `gt convoy land --ready` pass, which may
+// push to several rigs.
+const atomicLandTimeout = 10 * time.Minute
+
+// landAtomicConvoys lands atomic convoys whose merge requests have all been
+// verified and held by their refineries. Landing pushes to several rigs, so
+// it runs off the heartbeat. The land command reports aborted or partial
+// landings to the convoy owner itself; the daemon only logs.
+func (d *Daemon) landAtomicConvoys() {
+	d.runInBackground("Atomic convoys", d.landAtomicConvoysPass)
+}
+
+// landAtomicConvoysPass runs one `gt convoy land --ready`.
+func (d *Daemon) landAtomicConvoysPass() {
+	ctx, cancel := context.WithTimeout(context.Background(), atomicLandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", "convoy", "land", "--ready")
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	if output := strings.TrimSpace(string(out)); output != "" {
+		d.logger.Printf("Atomic convoys: %s", output)
+	}
+	if err != nil {
+		d.logger.Printf("Atomic convoys: land failed: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// backgroundShutdownWait bounds how long shutdown waits for background
+// tasks to finish.
+const backgroundShutdownWait = 10 * time.Second
+
+// backgroundTasks runs slow heartbeat work (doctor runs, convoy creation
+// and landing, log compression) off the heartbeat, so it can't delay the
+// recovery checks. Each named task runs at most once at a time: a
+// heartbeat that finds the previous run still going skips it.
+type backgroundTasks struct {
+	mu      sync.Mutex
+	running map[string]chan struct{} // Closed when the run finishes
+}
+
+// start runs fn in a goroutine unless the named task is already running,
+// and reports whether it was started.
+func (b *backgroundTasks) start(name string, fn func()) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.running[name] != nil {
+		return false
+	}
+	if b.running == nil {
+		b.running = make(map[string]chan struct{})
+	}
+	done := make(chan struct{})
+	b.running[name] = done
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			delete(b.running, name)
+			b.mu.Unlock()
+			close(done)
+		}()
+		fn()
+	}()
+	return true
+}
+
+// wait blocks until the running tasks finish or timeout passes, and
+// reports whether they all finished.
+func (b *backgroundTasks) wait(timeout time.Duration) bool {
+	b.mu.Lock()
+	pending := make([]chan struct{}, 0, len(b.running))
+	for _, done := range b.running {
+		pending = append(pending, done)
+	}
+	b.mu.Unlock()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for _, done := range pending {
+		select {
+		case <-done:
+		case <-deadline.C:
+			return false
+		}
+	}
+	return true
+}
+
+// runInBackground starts a heartbeat task off the heartbeat, logging when
+// it is skipped because its previous run hasn't finished.
+func (d *Daemon) runInBackground(name string, fn func()) {
+	if !d.tasks.start(name, fn) {
+		d.logger.Printf("%s: previous run still in progress, skipping", name)
+	}
+}
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackgroundTasks_SingleFlight(t *testing.T) {
+	var tasks backgroundTasks
+	release := make(chan struct{})
+	if !tasks.start("doctor", func() { <-release }) {
+		t.Fatal("first run not started")
+	}
+	if tasks.start("doctor", func() { t.Error("second run of a running task started") }) {
+		t.Error("start reported a run while one is in flight")
+	}
+
+	other := make(chan struct{})
+	if !tasks.start("logs", func() { close(other) }) {
+		t.Fatal("other task blocked by doctor")
+	}
+	<-other
+
+	if tasks.wait(20 * time.Millisecond) {
+		t.Error("wait returned with doctor still running")
+	}
+	close(release)
+	if !tasks.wait(time.Second) {
+		t.Fatal("doctor did not finish")
+	}
+	ran := make(chan struct{})
+	if !tasks.start("doctor", func() { close(ran) }) {
+		t.Error("finished task can't run again")
+	}
+	<-ran
+}
@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/convoytmpl"
+)
+
+// templateRunTimeout bounds one scheduled `gt convoy template run`.
+const templateRunTimeout = 5 * time.Minute
+
+// processRecurringConvoys runs convoy templates whose schedule has come
+// due, off the heartbeat since each run creates beads and may sling them.
+func (d *Daemon) processRecurringConvoys() {
+	d.runInBackground("Recurring convoys", d.recurringConvoysPass)
+}
+
+// recurringConvoysPass runs each due template once. A newly scheduled
+// template is armed on first sight and fires at its next occurrence;
+// occurrences missed while the daemon was down collapse into a single
+// catch-up run. Each run is claimed in the template's history before it
+// starts, and a template whose claim can't be saved is not run, so neither
+// a failed run nor a run whose result couldn't be recorded is repeated
+// before the following occurrence.
+func (d *Daemon) recurringConvoysPass() {
+	townRoot := d.config.TownRoot
+	templates, errs := convoytmpl.List(townRoot)
+	for _, err := range errs {
+		d.logger.Printf("Recurring convoys: %v", err)
+	}
+
+	now := time.Now()
+	for _, t := range templates {
+		if t.Schedule == "" {
+			continue
+		}
+		hist, err := convoytmpl.LoadHistory(townRoot, t.Name)
+		if err != nil {
+			d.logger.Printf("Recurring convoys: %v", err)
+			continue
+		}
+		if hist.ArmedAt.IsZero() && hist.Last() == nil {
+			hist.ArmedAt = now
+			if err := convoytmpl.SaveHistory(townRoot, t.Name, hist); err != nil {
+				d.logger.Printf("Recurring convoys: arming %s: %v", t.Name, err)
+			}
+			continue
+		}
+		due := hist.NextDue(t)
+		if due.IsZero() || now.Before(due) {
+			continue
+		}
+
+		hist.ClaimedAt = now
+		if err := convoytmpl.SaveHistory(townRoot, t.Name, hist); err != nil {
+			d.logger.Printf("Recurring convoys: %s due at %s but its claim couldn't be saved, not running: %v",
+				t.Name, due.Format(time.RFC3339), err)
+			continue
+		}
+		d.logger.Printf("Recurring convoys: %s due at %s, running", t.Name, due.Format(time.RFC3339))
+		if err := d.runConvoyTemplate(t.Name); err != nil {
+			d.logger.Printf("Recurring convoys: %s failed: %v", t.Name, err)
+		}
+	}
+}
+
+// runConvoyTemplate runs `gt convoy template run <name> --scheduled`.
+func (d *Daemon) runConvoyTemplate(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), templateRunTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", "convoy", "template", "run", name, "--scheduled") //nolint:gosec // G204: name is a template file name
+	cmd.Dir = d.config.TownRoot
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
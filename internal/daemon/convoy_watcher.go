@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // ConvoyWatcher monitors bd activity for issue closes and triggers convoy completion checks.
@@ -85,8 +87,9 @@ func (w *ConvoyWatcher) run() {
 
 // watchActivity starts bd activity and processes events until error or context cancellation.
 func (w *ConvoyWatcher) watchActivity() error {
-	cmd := exec.CommandContext(w.ctx, "bd", "activity", "--follow", "--town", "--json")
+	cmd := beads.CommandContext(w.ctx, "activity", "--follow", "--town", "--json")
 	cmd.Dir = w.townRoot
+	cmd.Timeout = 0 // Follows until canceled
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
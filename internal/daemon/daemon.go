@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,6 +25,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
 	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/readmodel"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -40,10 +42,13 @@ type Daemon struct {
 	config        *Config
 	tmux          *tmux.Tmux
 	logger        *log.Logger
+	logFile       *os.File // The logger's output, reopened after rotation
 	ctx           context.Context
 	cancel        context.CancelFunc
 	curator       *feed.Curator
 	convoyWatcher *ConvoyWatcher
+	readModel     *readmodel.Server
+	tasks         backgroundTasks // Slow heartbeat work, one run of each at a time
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -80,11 +85,12 @@ func New(config *Config) (*Daemon, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Daemon{
-		config: config,
-		tmux:   tmux.NewTmux(),
-		logger: logger,
-		ctx:    ctx,
-		cancel: cancel,
+		config:  config,
+		tmux:    tmux.NewTmux(),
+		logger:  logger,
+		logFile: logFile,
+		ctx:     ctx,
+		cancel:  cancel,
 	}, nil
 }
 
@@ -125,6 +131,14 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
+	// Log slow bd calls: every patrol and status read waits on bd
+	beads.OnCall(func(call beads.Call) {
+		if call.Duration >= slowBdCall && !slices.Contains(call.Args, "--follow") {
+			d.logger.Printf("Slow bd call (%s, %d attempt(s)): bd %s",
+				call.Duration.Round(time.Millisecond), call.Attempts, strings.Join(call.Args, " "))
+		}
+	})
+
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, daemonSignals()...)
@@ -144,6 +158,16 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Feed curator started")
 	}
 
+	// Start the read model server; it follows bd activity in every beads
+	// database to keep its snapshot current
+	d.readModel = readmodel.NewServer(d.config.TownRoot, d.logger.Printf)
+	if err := d.readModel.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start read model server: %v", err)
+		d.readModel = nil
+	} else {
+		d.logger.Println("Read model server started")
+	}
+
 	// Start convoy watcher for event-driven convoy completion
 	d.convoyWatcher = NewConvoyWatcher(d.config.TownRoot, d.logger.Printf)
 	if err := d.convoyWatcher.Start(); err != nil {
@@ -186,6 +210,9 @@ func (d *Daemon) Run() error {
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
 const recoveryHeartbeatInterval = 3 * time.Minute
 
+// slowBdCall is the bd call duration the daemon logs.
+const slowBdCall = 5 * time.Second
+
 // heartbeat performs one heartbeat cycle.
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
 // Normal wake is handled by feed subscription (bd activity --follow).
@@ -233,6 +260,30 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12. Release scheduled mail that is due and archive expired mail
+	d.processScheduledMail()
+
+	// 13. Re-deliver or escalate ack-required mail past its deadline
+	d.processMailAcks()
+
+	// 14. Release lapsed queue claims and dead-letter exhausted messages
+	d.processQueueLeases()
+
+	// 15. Rotate accounts for (or park) sessions stopped by a usage limit
+	d.processUsageLimits()
+
+	// 16. Run cheap doctor checks on their interval and report state changes
+	d.runContinuousDoctor()
+
+	// 17. Run convoy templates whose schedule has come due
+	d.processRecurringConvoys()
+
+	// 18. Land atomic convoys whose held MRs are all verified
+	d.landAtomicConvoys()
+
+	// 19. Rotate, compress and prune logs per the town's retention policies
+	d.rotateLogs()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -594,6 +645,16 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop read model server
+	if d.readModel != nil {
+		d.readModel.Stop()
+		d.logger.Println("Read model server stopped")
+	}
+
+	if !d.tasks.wait(backgroundShutdownWait) {
+		d.logger.Println("Warning: background tasks still running at shutdown")
+	}
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+// doctorRunTimeout bounds one `gt doctor` invocation.
+const doctorRunTimeout = 5 * time.Minute
+
+// doctorJSONReport is the part of `gt doctor --json` output the daemon uses.
+type doctorJSONReport struct {
+	Checks []struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"checks"`
+}
+
+// runContinuousDoctor runs the configured cheap doctor checks, off the
+// heartbeat, when the interval has elapsed; records the results in the
+// health history; and reports checks that start or stop erroring to the
+// feed. Checks that start erroring are also escalated. Continuous doctor
+// is opt-in: it runs only when enabled in mayor/daemon.json.
+func (d *Daemon) runContinuousDoctor() {
+	cfg := config.NewDoctorPatrolConfig()
+	if patrol, err := config.LoadDaemonPatrolConfig(config.DaemonPatrolConfigPath(d.config.TownRoot)); err == nil && patrol.Doctor != nil {
+		cfg = patrol.Doctor
+	}
+	if !cfg.Enabled {
+		return
+	}
+	d.runInBackground("Continuous doctor", func() { d.continuousDoctorPass(cfg) })
+}
+
+// continuousDoctorPass runs the checks if the interval has elapsed since
+// the last run. The run is recorded before the checks start, so a run that
+// fails (gt doctor crashing, every check timing out) still waits out the
+// interval rather than being retried on every heartbeat.
+func (d *Daemon) continuousDoctorPass(cfg *config.DoctorPatrolConfig) {
+	townRoot := d.config.TownRoot
+	checks, rigChecks := cfg.Checks, cfg.RigChecks
+	if len(checks) == 0 && len(rigChecks) == 0 {
+		defaults := config.NewDoctorPatrolConfig()
+		checks, rigChecks = defaults.Checks, defaults.RigChecks
+	}
+
+	state, err := health.LoadState(townRoot)
+	if err != nil {
+		d.logger.Printf("Continuous doctor: %v", err)
+		return
+	}
+	now := time.Now()
+	if now.Sub(state.LastRun) < cfg.GetInterval() {
+		return
+	}
+	state.LastRun = now
+	if err := health.SaveState(townRoot, state); err != nil {
+		d.logger.Printf("Continuous doctor: recording run: %v", err)
+		return
+	}
+
+	var samples []health.Sample
+	if len(checks) > 0 {
+		samples = append(samples, d.runDoctorChecks("", checks, now)...)
+	}
+	if len(rigChecks) > 0 {
+		for _, rigName := range d.getKnownRigs() {
+			samples = append(samples, d.runDoctorChecks(rigName, rigChecks, now)...)
+		}
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	transitions, err := health.Record(townRoot, samples)
+	if err != nil {
+		d.logger.Printf("Continuous doctor: recording results: %v", err)
+	}
+	for _, t := range transitions {
+		if !t.Failed() && !t.Recovered() {
+			continue
+		}
+		d.logger.Printf("Continuous doctor: %s %s → %s: %s", t.Check, orUnknown(t.From), t.To, t.Message)
+		_ = events.LogFeed(events.TypeHealthChanged, "daemon",
+			events.HealthChangedPayload(t.Check, t.From, t.To, t.Message))
+		if t.Failed() {
+			d.escalateHealthFailure(t)
+		}
+	}
+}
+
+// runDoctorChecks runs `gt doctor --json` for the named checks, town-wide
+// or for one rig, and converts the report into samples. Rig results are
+// named <rig>/<check>.
+func (d *Daemon) runDoctorChecks(rigName string, checks []string, now time.Time) []health.Sample {
+	args := []string{"doctor", "--json", "--check", strings.Join(checks, ",")}
+	if rigName != "" {
+		args = append(args, "--rig", rigName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorRunTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gt", args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+
+	// gt doctor exits non-zero when a check errors; the report is still valid
+	out, err := cmd.Output()
+	var report doctorJSONReport
+	if jsonErr := json.Unmarshal(out, &report); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		d.logger.Printf("Continuous doctor: gt %s failed: %v", strings.Join(args, " "), err)
+		return nil
+	}
+
+	samples := make([]health.Sample, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		name := c.Name
+		if rigName != "" {
+			name = rigName + "/" + name
+		}
+		samples = append(samples, health.Sample{
+			Time:    now,
+			Check:   name,
+			Status:  c.Status,
+			Message: c.Message,
+		})
+	}
+	return samples
+}
+
+// escalateHealthFailure raises an escalation for a check that started
+// erroring.
+func (d *Daemon) escalateHealthFailure(t health.Transition) {
+	description := fmt.Sprintf("Doctor check %s is failing", t.Check)
+	reason := t.Message
+	if reason == "" {
+		reason = "no message"
+	}
+	reason += fmt.Sprintf("\n\nWas %s. Run 'gt doctor' for details; 'gt doctor history %s' shows past state changes.",
+		orUnknown(t.From), t.Check)
+
+	cmd := exec.Command("gt", "escalate", description, //nolint:gosec // G204: args are constructed internally
+		"--severity", "high",
+		"--source", "doctor:"+t.Check,
+		"--reason", reason)
+	cmd.Dir = d.config.TownRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		d.logger.Printf("Continuous doctor: escalating %s failed: %v: %s", t.Check, err, strings.TrimSpace(string(out)))
+	}
+}
+
+func orUnknown(status string) string {
+	if status == "" {
+		return "unknown"
+	}
+	return status
+}
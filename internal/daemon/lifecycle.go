@@ -3,6 +3,7 @@ package daemon
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,8 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/quota"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -602,15 +605,8 @@ func (d *Daemon) syncWorkspace(workDir string) {
 	stderr.Reset()
 
 	// Sync beads
-	bdCmd := exec.Command("bd", "sync")
-	bdCmd.Dir = workDir
-	bdCmd.Stderr = &stderr
-	if err := bdCmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg == "" {
-			errMsg = err.Error()
-		}
-		d.logger.Printf("Warning: bd sync failed in %s: %s", workDir, errMsg)
+	if err := beads.New(workDir).Sync(); err != nil {
+		d.logger.Printf("Warning: bd sync failed in %s: %v", workDir, err)
 		// Don't fail - sync issues may be recoverable
 	}
 }
@@ -657,33 +653,15 @@ func (d *Daemon) getAgentBeadState(agentBeadID string) (string, error) {
 
 // getAgentBeadInfo fetches and parses an agent bead by ID.
 func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
-	cmd := exec.Command("bd", "show", agentBeadID, "--json")
-	cmd.Dir = d.config.TownRoot
-
-	output, err := cmd.Output()
+	// Routed from the town root: agent beads live in their rig's database
+	issue, err := beads.NewRouted(d.config.TownRoot).Show(agentBeadID)
+	if errors.Is(err, beads.ErrNotFound) {
+		return nil, fmt.Errorf("agent bead not found: %s", agentBeadID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("bd show %s: %w", agentBeadID, err)
 	}
 
-	// bd show --json returns an array with one element
-	var issues []struct {
-		ID          string `json:"id"`
-		Type        string `json:"issue_type"`
-		Description string `json:"description"`
-		UpdatedAt   string `json:"updated_at"`
-		HookBead    string `json:"hook_bead"`   // Read from database column
-		AgentState  string `json:"agent_state"` // Read from database column
-	}
-
-	if err := json.Unmarshal(output, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd show output: %w", err)
-	}
-
-	if len(issues) == 0 {
-		return nil, fmt.Errorf("agent bead not found: %s", agentBeadID)
-	}
-
-	issue := issues[0]
 	if issue.Type != "agent" {
 		return nil, fmt.Errorf("bead %s is not an agent bead (type=%s)", agentBeadID, issue.Type)
 	}
@@ -711,6 +689,12 @@ func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
 	return info, nil
 }
 
+// listAgentBeads lists the agent beads in the town database. HookBead and
+// AgentState come from the database columns, not the description.
+func (d *Daemon) listAgentBeads() ([]*beads.Issue, error) {
+	return beads.New(d.config.TownRoot).List(beads.ListOptions{IssueType: "agent", Priority: -1})
+}
+
 // identityToAgentBeadID maps a daemon identity to an agent bead ID.
 // Uses parseIdentity to extract components, then uses beads package helpers.
 func (d *Daemon) identityToAgentBeadID(identity string) string {
@@ -782,40 +766,48 @@ const GUPPViolationTimeout = 30 * time.Minute
 
 // checkGUPPViolations looks for agents that have work-on-hook but aren't
 // progressing. This is a GUPP violation: agents with hooked work must execute.
-// The daemon detects these and notifies the relevant Witness for remediation.
+//
+// Progress is judged from several signals (pane output, worktree edits, new
+// commits, child-process CPU, bead updates) rather than the bead timestamp
+// alone, so a polecat quietly running a long test suite isn't flagged and
+// one looping on the same error is. Agents losing momentum get a nudge;
+// agents with no progress signal for the whole window are escalated to the
+// relevant Witness for remediation.
 func (d *Daemon) checkGUPPViolations() {
+	state, err := deacon.LoadProgressState(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: %v (starting fresh)", err)
+		state = &deacon.ProgressState{}
+	}
+	now := time.Now()
+
 	// Check polecat agents - they're the ones with work-on-hook
 	rigs := d.getKnownRigs()
 	for _, rigName := range rigs {
-		d.checkRigGUPPViolations(rigName)
+		d.checkRigGUPPViolations(rigName, state, now)
+	}
+
+	// Forget agents that are gone or no longer hooked
+	state.Prune(now.Add(-2 * GUPPViolationTimeout))
+	if err := deacon.SaveProgressState(d.config.TownRoot, state); err != nil {
+		d.logger.Printf("Warning: saving progress state: %v", err)
 	}
 }
 
 // checkRigGUPPViolations checks polecats in a specific rig for GUPP violations.
-func (d *Daemon) checkRigGUPPViolations(rigName string) {
+func (d *Daemon) checkRigGUPPViolations(rigName string, state *deacon.ProgressState, now time.Time) {
 	// List polecat agent beads for this rig
 	// Pattern: <prefix>-<rig>-polecat-<name> (e.g., gt-gastown-polecat-Toast)
-	cmd := exec.Command("bd", "list", "--type=agent", "--json")
-	cmd.Dir = d.config.TownRoot
-
-	output, err := cmd.Output()
+	agents, err := d.listAgentBeads()
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for GUPP check: %v", err)
 		return
 	}
 
-	var agents []struct {
-		ID          string `json:"id"`
-		Type        string `json:"issue_type"`
-		Description string `json:"description"`
-		UpdatedAt   string `json:"updated_at"`
-		HookBead    string `json:"hook_bead"` // Read from database column, not description
-		AgentState  string `json:"agent_state"`
-	}
-
-	if err := json.Unmarshal(output, &agents); err != nil {
-		return
-	}
+	cfg := deacon.DefaultProgressConfig()
+	cfg.Window = GUPPViolationTimeout
+	limits, _ := quota.LoadState(d.config.TownRoot)
+	collector := deacon.NewProgressCollector(d.tmux)
 
 	// Use the rig's configured prefix (e.g., "gt" for gastown, "bd" for beads)
 	rigPrefix := config.GetRigPrefix(d.config.TownRoot, rigName)
@@ -838,37 +830,83 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 		polecatName := strings.TrimPrefix(agent.ID, prefix)
 		sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
-		// Check if tmux session exists and Claude is running
-		if d.tmux.IsClaudeRunning(sessionName) {
-			// Session is alive - check if it's been stuck too long
-			updatedAt, err := time.Parse(time.RFC3339, agent.UpdatedAt)
-			if err != nil {
-				continue
-			}
+		// Dead sessions are checkOrphanedWork's concern
+		if !d.tmux.IsClaudeRunning(sessionName) {
+			continue
+		}
 
-			age := time.Since(updatedAt)
-			if age > GUPPViolationTimeout {
-				d.logger.Printf("GUPP violation: agent %s has hook_bead=%s but hasn't updated in %v (timeout: %v)",
-					agent.ID, agent.HookBead, age.Round(time.Minute), GUPPViolationTimeout)
+		// Sessions parked on a usage limit can't make progress until it resets
+		if limits != nil && limits.IsParked(sessionName) {
+			continue
+		}
 
-				// Notify the witness for this rig
-				d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, age)
+		beadUpdated, _ := time.Parse(time.RFC3339, agent.UpdatedAt)
+		worktree := polecatWorktree(d.config.TownRoot, rigName, polecatName)
+		sample := collector.Sample(sessionName, worktree, beadUpdated, now)
+
+		progress := state.GetAgent(agent.ID)
+		switch progress.Observe(sample, cfg) {
+		case deacon.ProgressNudge:
+			d.logger.Printf("Progress check: nudging %s (%s)", agent.ID, progress.Reason)
+			msg := "PROGRESS_CHECK: no clear progress on your hooked work (" + progress.Reason +
+				"). If you're stuck or repeating yourself, step back and try a different approach, or escalate."
+			if err := d.tmux.NudgeSession(sessionName, msg); err != nil {
+				d.logger.Printf("Warning: failed to nudge %s: %v", sessionName, err)
+			} else {
+				progress.RecordNudge(now)
 			}
+
+		case deacon.ProgressEscalate:
+			stuckFor := now.Sub(latestChange(progress, now))
+			d.logger.Printf("GUPP violation: agent %s has hook_bead=%s but isn't progressing: %s",
+				agent.ID, agent.HookBead, progress.Reason)
+
+			// Notify the witness for this rig
+			d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, stuckFor, progress.Reason)
+			progress.RecordEscalation(now)
 		}
 	}
 }
 
+// latestChange returns when any of an agent's progress signals last changed,
+// falling back to when it was first observed.
+func latestChange(p *deacon.AgentProgress, now time.Time) time.Time {
+	latest := p.FirstSeen
+	for _, t := range p.LastChange {
+		if t.After(latest) && !t.After(now) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// polecatWorktree returns a polecat's worktree path, or "" if not found.
+// Mirrors polecat.Manager's layout: polecats/<name>/<rig>/, falling back to
+// the older polecats/<name>/.
+func polecatWorktree(townRoot, rigName, polecatName string) string {
+	for _, path := range []string{
+		filepath.Join(townRoot, rigName, "polecats", polecatName, rigName),
+		filepath.Join(townRoot, rigName, "polecats", polecatName),
+	} {
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
 // notifyWitnessOfGUPP sends a mail to the rig's witness about a GUPP violation.
-func (d *Daemon) notifyWitnessOfGUPP(rigName, agentID, hookBead string, stuckDuration time.Duration) {
+func (d *Daemon) notifyWitnessOfGUPP(rigName, agentID, hookBead string, stuckDuration time.Duration, reason string) {
 	witnessAddr := rigName + "/witness"
 	subject := fmt.Sprintf("GUPP_VIOLATION: %s stuck for %v", agentID, stuckDuration.Round(time.Minute))
 	body := fmt.Sprintf(`Agent %s has work on hook but isn't progressing.
 
 hook_bead: %s
 stuck_duration: %v
+signals: %s
 
 Action needed: Check if agent is alive and responsive. Consider restarting if stuck.`,
-		agentID, hookBead, stuckDuration.Round(time.Minute))
+		agentID, hookBead, stuckDuration.Round(time.Minute), reason)
 
 	cmd := exec.Command("gt", "mail", "send", witnessAddr, "-s", subject, "-m", body)
 	cmd.Dir = d.config.TownRoot
@@ -893,24 +931,12 @@ func (d *Daemon) checkOrphanedWork() {
 
 // checkRigOrphanedWork checks polecats in a specific rig for orphaned work.
 func (d *Daemon) checkRigOrphanedWork(rigName string) {
-	cmd := exec.Command("bd", "list", "--type=agent", "--json")
-	cmd.Dir = d.config.TownRoot
-
-	output, err := cmd.Output()
+	agents, err := d.listAgentBeads()
 	if err != nil {
 		d.logger.Printf("Warning: bd list failed for orphaned work check: %v", err)
 		return
 	}
 
-	var agents []struct {
-		ID       string `json:"id"`
-		HookBead string `json:"hook_bead"`
-	}
-
-	if err := json.Unmarshal(output, &agents); err != nil {
-		return
-	}
-
 	// Use the rig's configured prefix (e.g., "gt" for gastown, "bd" for beads)
 	rigPrefix := config.GetRigPrefix(d.config.TownRoot, rigName)
 	// Pattern: <prefix>-<rig>-polecat-<name>
@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/retention"
+)
+
+// rotateLogs applies the town's log retention policies, off the heartbeat
+// since gzipping a large segment can take a while.
+func (d *Daemon) rotateLogs() {
+	d.runInBackground("Log retention", d.logRetentionPass)
+}
+
+// logRetentionPass rotates logs past their size or age limit, gzips
+// settled segments, and deletes expired ones. The daemon's own log is
+// reopened once it has been rotated, whether by this pass or by
+// `gt logs rotate`.
+func (d *Daemon) logRetentionPass() {
+	results, err := retention.Run(d.config.TownRoot, time.Now(), false)
+	d.reopenLogIfRotated()
+	if err != nil {
+		d.logger.Printf("Log retention: %v", err)
+	}
+	for _, r := range results {
+		if !r.Changed() {
+			continue
+		}
+		rel, relErr := filepath.Rel(d.config.TownRoot, r.Path)
+		if relErr != nil {
+			rel = r.Path
+		}
+		d.logger.Printf("Log retention: %s: rotated=%t compressed=%d removed=%d",
+			rel, r.Rotated != "", len(r.Compressed), len(r.Removed))
+	}
+}
+
+// reopenLogIfRotated points the logger at a fresh daemon log when the file
+// it writes to was renamed away.
+func (d *Daemon) reopenLogIfRotated() {
+	if d.logFile == nil {
+		return
+	}
+	if _, err := os.Stat(d.config.LogFile); err == nil && !retention.Replaced(d.logFile, d.config.LogFile) {
+		return
+	}
+	f, err := os.OpenFile(d.config.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		d.logger.Printf("Log retention: reopening daemon log: %v", err)
+		return
+	}
+	d.logger.SetOutput(f)
+	_ = d.logFile.Close()
+	d.logFile = f
+}
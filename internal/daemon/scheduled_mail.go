@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// processScheduledMail releases scheduled mail that has come due and
+// auto-archives expired mail. Recipients of released direct mail are
+// notified the same way as on an immediate send.
+// Resolution is one heartbeat: a message scheduled for 10:00 is released
+// on the first heartbeat at or after 10:00.
+func (d *Daemon) processScheduledMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	result, err := router.ProcessSchedule(time.Now())
+	if err != nil {
+		d.logger.Printf("Scheduled mail: %v", err)
+	}
+	if result == nil {
+		return
+	}
+	for _, msg := range result.Released {
+		d.logger.Printf("Scheduled mail: released %s to %s (%q)", msg.ID, msg.To, msg.Subject)
+	}
+	for _, msg := range result.Expired {
+		d.logger.Printf("Scheduled mail: archived expired %s for %s (%q)", msg.ID, msg.To, msg.Subject)
+	}
+}
+
+// processMailAcks re-delivers ack-required mail whose deadline passed and
+// escalates mail that is still unacknowledged after re-delivery.
+func (d *Daemon) processMailAcks() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	result, err := router.ProcessAcks(time.Now())
+	if err != nil {
+		d.logger.Printf("Mail acks: %v", err)
+	}
+	if result == nil {
+		return
+	}
+	for _, msg := range result.Redelivered {
+		d.logger.Printf("Mail acks: re-delivered %s to %s (%q)", msg.ID, msg.To, msg.Subject)
+	}
+	for i, msg := range result.Escalated {
+		d.logger.Printf("Mail acks: escalated %s to %s as %s (%q)", msg.ID, msg.To, result.Escalations[i], msg.Subject)
+	}
+}
+
+// processQueueLeases returns claimed queue messages to their queue when the
+// claimant's lease lapsed or its session died, and dead-letters messages that
+// ran out of deliveries.
+func (d *Daemon) processQueueLeases() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	result, err := router.ProcessQueueLeases(time.Now())
+	if err != nil {
+		d.logger.Printf("Queue leases: %v", err)
+	}
+	if result == nil {
+		return
+	}
+	for _, msg := range result.Released {
+		d.logger.Printf("Queue leases: released %s from %s back to queue %s (%q)", msg.ID, msg.ClaimedBy, msg.Queue, msg.Subject)
+	}
+	for _, msg := range result.DeadLettered {
+		d.logger.Printf("Queue leases: dead-lettered %s from queue %s after %d deliveries (%q)", msg.ID, msg.Queue, msg.Deliveries, msg.Subject)
+	}
+}
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// usageLimitPaneLines is how much of the pane is captured for limit detection.
+const usageLimitPaneLines = 50
+
+// processUsageLimits finds sessions stopped by a provider usage limit and
+// applies the rotation policy: restart under the next account that isn't
+// limited, or park the session until the limit resets. Parked sessions are
+// nudged to continue once their reset time passes.
+func (d *Daemon) processUsageLimits() {
+	townRoot := d.config.TownRoot
+	now := time.Now()
+
+	state, err := quota.LoadState(townRoot)
+	if err != nil {
+		d.logger.Printf("Usage limits: %v", err)
+		return
+	}
+	state.Expire(now)
+
+	// No accounts config just means rotation isn't possible; parking still is
+	accounts, _ := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+
+	// Resumed sessions still show the limit message until they respond;
+	// don't re-detect it this pass.
+	resumed := make(map[string]bool)
+	for _, parked := range state.DueParked(now) {
+		resumed[parked.Session] = true
+		if alive, err := d.tmux.HasSession(parked.Session); err != nil || !alive {
+			continue
+		}
+		d.logger.Printf("Usage limits: resuming parked session %s", parked.Session)
+		msg := "USAGE_LIMIT_RESET: your account's usage limit has reset. Continue your hooked work."
+		if err := d.tmux.NudgeSession(parked.Session, msg); err != nil {
+			d.logger.Printf("Usage limits: failed to nudge %s: %v", parked.Session, err)
+		}
+	}
+
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		d.logger.Printf("Usage limits: listing sessions: %v", err)
+		return
+	}
+	state.KeepHandled(sessions)
+
+	for _, name := range sessions {
+		identity, err := session.ParseSessionName(name)
+		if err != nil || state.IsParked(name) || resumed[name] {
+			continue
+		}
+
+		rc := d.sessionRuntime(identity)
+		configDir, _ := d.tmux.GetEnvironment(name, "CLAUDE_CONFIG_DIR")
+		hit := d.detectUsageLimit(name, rc, configDir, now)
+		if hit == nil || state.IsHandled(name, hit) {
+			continue
+		}
+
+		// Only Claude sessions run under an account; others wait out
+		// their own limit without marking any account limited
+		var current string
+		var rotateAccounts *config.AccountsConfig
+		if quota.UsesAccounts(rc) && accounts != nil {
+			rotateAccounts = accounts
+			current = quota.AccountForConfigDir(accounts, configDir)
+			if current == "" && configDir == "" {
+				current = accounts.Default // session inherited the default ~/.claude
+			}
+		}
+		d.logger.Printf("Usage limits: %s hit a usage limit on account %q (%s), resets %s",
+			name, current, hit.Line, hit.ResetAt.Format(time.RFC3339))
+		state.MarkLimited(current, name, hit, now)
+		state.SetHandled(name, hit)
+
+		decision := state.Decide(rotateAccounts, current, hit, now)
+		if decision.Action == quota.ActionRotate {
+			cmd := exec.Command("gt", "account", "rotate", name, "--to", decision.Account) //nolint:gosec // G204: args are constructed internally
+			cmd.Dir = townRoot
+			if out, err := cmd.CombinedOutput(); err != nil {
+				d.logger.Printf("Usage limits: rotating %s to %s failed: %v: %s",
+					name, decision.Account, err, strings.TrimSpace(string(out)))
+				decision = quota.Decision{Action: quota.ActionPark, Until: hit.ResetAt}
+			} else {
+				d.logger.Printf("Usage limits: rotated %s to account %s", name, decision.Account)
+			}
+		}
+
+		target := decision.Account
+		if decision.Action == quota.ActionPark {
+			state.Park(name, current, decision.Until, now)
+			target = decision.Until.Format(time.RFC3339)
+			d.logger.Printf("Usage limits: parked %s until %s", name, target)
+		}
+		_ = events.LogFeed(events.TypeUsageLimit, identity.Address(),
+			events.UsageLimitPayload(name, current, string(decision.Action), target))
+	}
+
+	if err := quota.SaveState(townRoot, state); err != nil {
+		d.logger.Printf("Usage limits: saving state: %v", err)
+	}
+}
+
+// sessionRuntime resolves the runtime a session was started with, from
+// its rig's settings (town settings for town-level agents).
+func (d *Daemon) sessionRuntime(identity *session.AgentIdentity) *config.RuntimeConfig {
+	rigPath := ""
+	if identity.Rig != "" {
+		rigPath = filepath.Join(d.config.TownRoot, identity.Rig)
+	}
+	return config.ResolveAgentConfig(d.config.TownRoot, rigPath)
+}
+
+// detectUsageLimit checks a session's pane, then its transcript, for a
+// usage-limit message in the format of the session's runtime.
+func (d *Daemon) detectUsageLimit(name string, rc *config.RuntimeConfig, configDir string, now time.Time) *quota.Hit {
+	detector := quota.DetectorForAgent(filepath.Base(rc.Command))
+
+	if pane, err := d.tmux.CapturePane(name, usageLimitPaneLines); err == nil {
+		if hit := detector.DetectPane(pane, now); hit != nil {
+			return hit
+		}
+	}
+
+	// Transcripts are in Claude Code's format
+	if !quota.UsesAccounts(rc) {
+		return nil
+	}
+	workDir, err := d.tmux.GetPaneWorkDir(name)
+	if err != nil || workDir == "" {
+		return nil
+	}
+	transcript := quota.LatestTranscript(quota.ClaudeProjectDir(configDir, workDir))
+	if transcript == "" {
+		return nil
+	}
+	return detector.DetectTranscript(transcript, now)
+}
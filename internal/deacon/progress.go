@@ -0,0 +1,450 @@
+package deacon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default parameters for progress-signal detection.
+// A bead timestamp alone can't tell a polecat quietly running a long test
+// suite from one looping on the same error, so several signals are combined.
+const (
+	DefaultProgressWindow = 30 * time.Minute // Signal older than this counts for nothing
+	DefaultNudgeScore     = 0.3              // Score below which the agent is nudged
+	DefaultLoopSamples    = 3                // Consecutive repeating samples before "looping"
+	DefaultNudgeCooldown  = 10 * time.Minute // Minimum time between nudges
+
+	// cpuActiveThreshold is how much child-process CPU time between two
+	// samples counts as activity.
+	cpuActiveThreshold = 2 * time.Second
+
+	// repeatMinCount is how often one normalized output line must appear in
+	// a capture before it's considered repeated output.
+	repeatMinCount = 3
+
+	// shapeHistory is how many recent pane shapes are remembered per agent.
+	shapeHistory = 6
+)
+
+// ProgressSignal names one source of progress evidence.
+type ProgressSignal string
+
+const (
+	SignalCommits ProgressSignal = "commits" // New commits on the polecat branch
+	SignalFiles   ProgressSignal = "files"   // Worktree file modifications
+	SignalCPU     ProgressSignal = "cpu"     // Child-process CPU activity
+	SignalPane    ProgressSignal = "pane"    // Pane output changes
+	SignalBead    ProgressSignal = "bead"    // Agent bead updates
+)
+
+// signalWeights is how strongly each signal vouches for progress. Commits
+// and file edits are hard evidence; pane output and bead updates are cheap
+// to produce while spinning, so they weigh least.
+var signalWeights = map[ProgressSignal]float64{
+	SignalCommits: 1.0,
+	SignalFiles:   0.9,
+	SignalCPU:     0.8,
+	SignalPane:    0.6,
+	SignalBead:    0.5,
+}
+
+// ProgressAction is what the assessment recommends.
+type ProgressAction string
+
+const (
+	ProgressOK       ProgressAction = "ok"
+	ProgressNudge    ProgressAction = "nudge"
+	ProgressEscalate ProgressAction = "escalate"
+)
+
+// ProgressConfig holds thresholds for progress assessment.
+type ProgressConfig struct {
+	Window        time.Duration `json:"window"`
+	NudgeScore    float64       `json:"nudge_score"`
+	LoopSamples   int           `json:"loop_samples"`
+	NudgeCooldown time.Duration `json:"nudge_cooldown"`
+}
+
+// DefaultProgressConfig returns the default progress detection config.
+func DefaultProgressConfig() *ProgressConfig {
+	return &ProgressConfig{
+		Window:        DefaultProgressWindow,
+		NudgeScore:    DefaultNudgeScore,
+		LoopSamples:   DefaultLoopSamples,
+		NudgeCooldown: DefaultNudgeCooldown,
+	}
+}
+
+// ProgressSample is one observation of an agent. Zero values mean the
+// signal was unavailable.
+type ProgressSample struct {
+	At            time.Time
+	Pane          string        // Captured pane output
+	WorktreeMtime time.Time     // Newest file modification in the worktree
+	HeadCommit    string        // HEAD of the polecat branch
+	CPUTime       time.Duration // Cumulative CPU time of the agent's child processes
+	HasCPU        bool
+	BeadUpdated   time.Time // Agent bead updated_at
+}
+
+// AgentProgress is the remembered progress state of one agent.
+type AgentProgress struct {
+	AgentID    string    `json:"agent_id"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSample time.Time `json:"last_sample"`
+
+	// Last observed values, for change detection
+	PaneHash   string        `json:"pane_hash,omitempty"`
+	PaneShapes []string      `json:"pane_shapes,omitempty"` // Most recent last
+	RepeatLine string        `json:"repeat_line,omitempty"`
+	RepeatSeen int           `json:"repeat_seen,omitempty"`
+	HeadCommit string        `json:"head_commit,omitempty"`
+	CPUTime    time.Duration `json:"cpu_time,omitempty"`
+
+	// LastChange is when each signal last showed progress
+	LastChange map[ProgressSignal]time.Time `json:"last_change,omitempty"`
+
+	// LoopCount counts consecutive samples with repeated output
+	LoopCount int `json:"loop_count"`
+
+	// Latest assessment
+	Score  float64        `json:"score"`
+	Reason string         `json:"reason"`
+	Action ProgressAction `json:"action"`
+
+	LastNudge      time.Time `json:"last_nudge,omitempty"`
+	LastEscalation time.Time `json:"last_escalation,omitempty"`
+}
+
+// Looping reports whether the agent has produced repeated output for at
+// least loopSamples consecutive samples.
+func (p *AgentProgress) Looping(loopSamples int) bool {
+	return loopSamples > 0 && p.LoopCount >= loopSamples
+}
+
+// ProgressState holds progress state for all monitored agents.
+type ProgressState struct {
+	Agents      map[string]*AgentProgress `json:"agents"`
+	LastUpdated time.Time                 `json:"last_updated"`
+}
+
+// ProgressStateFile returns the path to the progress state file.
+func ProgressStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "progress-state.json")
+}
+
+// LoadProgressState loads progress state from disk.
+// Returns empty state if the file doesn't exist.
+func LoadProgressState(townRoot string) (*ProgressState, error) {
+	data, err := os.ReadFile(ProgressStateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ProgressState{Agents: make(map[string]*AgentProgress)}, nil
+		}
+		return nil, fmt.Errorf("reading progress state: %w", err)
+	}
+
+	var state ProgressState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing progress state: %w", err)
+	}
+	if state.Agents == nil {
+		state.Agents = make(map[string]*AgentProgress)
+	}
+	return &state, nil
+}
+
+// SaveProgressState saves progress state to disk.
+func SaveProgressState(townRoot string, state *ProgressState) error {
+	stateFile := ProgressStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0755); err != nil {
+		return fmt.Errorf("creating deacon directory: %w", err)
+	}
+
+	state.LastUpdated = time.Now().UTC()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling progress state: %w", err)
+	}
+	return os.WriteFile(stateFile, data, 0600)
+}
+
+// GetAgent returns the progress state for an agent, creating if needed.
+func (s *ProgressState) GetAgent(agentID string) *AgentProgress {
+	if s.Agents == nil {
+		s.Agents = make(map[string]*AgentProgress)
+	}
+	p, ok := s.Agents[agentID]
+	if !ok {
+		p = &AgentProgress{AgentID: agentID}
+		s.Agents[agentID] = p
+	}
+	return p
+}
+
+// Prune drops agents not sampled since cutoff (e.g. nuked polecats).
+func (s *ProgressState) Prune(cutoff time.Time) {
+	for id, p := range s.Agents {
+		if p.LastSample.Before(cutoff) {
+			delete(s.Agents, id)
+		}
+	}
+}
+
+// Observe folds a sample into the agent's state and re-assesses it.
+// The assessment is stored on the state and its action returned; callers
+// record nudges and escalations with RecordNudge and RecordEscalation.
+func (p *AgentProgress) Observe(sample ProgressSample, cfg *ProgressConfig) ProgressAction {
+	now := sample.At
+	if p.LastChange == nil {
+		p.LastChange = make(map[ProgressSignal]time.Time)
+	}
+	first := p.FirstSeen.IsZero()
+	if first {
+		p.FirstSeen = now
+	}
+
+	// Pane: output that differs in more than counters and spinners.
+	// Output that keeps coming back to something seen before is looping.
+	repeating := false
+	if sample.Pane != "" {
+		hash := hashString(sample.Pane)
+		shape := paneShape(sample.Pane)
+		prevShape := ""
+		if n := len(p.PaneShapes); n > 0 {
+			prevShape = p.PaneShapes[n-1]
+		}
+
+		if first || (hash != p.PaneHash && shape != prevShape) {
+			p.LastChange[SignalPane] = now
+		}
+		if !first && shape != prevShape && containsString(p.PaneShapes, shape) {
+			repeating = true // cycled back to earlier output
+		}
+
+		line, count := repeatedLine(sample.Pane)
+		if line != "" && line == p.RepeatLine && count > p.RepeatSeen {
+			repeating = true // same line printed yet again
+		}
+		p.RepeatLine, p.RepeatSeen = line, count
+
+		p.PaneHash = hash
+		if shape != prevShape {
+			p.PaneShapes = append(p.PaneShapes, shape)
+			if len(p.PaneShapes) > shapeHistory {
+				p.PaneShapes = p.PaneShapes[len(p.PaneShapes)-shapeHistory:]
+			}
+		}
+	}
+	if repeating {
+		p.LoopCount++
+	} else {
+		p.LoopCount = 0
+	}
+
+	// Commits: HEAD moved
+	if sample.HeadCommit != "" {
+		if first || sample.HeadCommit != p.HeadCommit {
+			p.LastChange[SignalCommits] = now
+		}
+		p.HeadCommit = sample.HeadCommit
+	}
+
+	// CPU: child processes burned CPU since the last sample
+	if sample.HasCPU {
+		if first || sample.CPUTime-p.CPUTime >= cpuActiveThreshold {
+			p.LastChange[SignalCPU] = now
+		}
+		p.CPUTime = sample.CPUTime
+	}
+
+	// Files and bead carry their own timestamps
+	if !sample.WorktreeMtime.IsZero() && sample.WorktreeMtime.After(p.LastChange[SignalFiles]) {
+		p.LastChange[SignalFiles] = sample.WorktreeMtime
+	}
+	if !sample.BeadUpdated.IsZero() && sample.BeadUpdated.After(p.LastChange[SignalBead]) {
+		p.LastChange[SignalBead] = sample.BeadUpdated
+	}
+
+	p.LastSample = now
+	p.assess(now, cfg)
+	return p.Action
+}
+
+// assess computes the score, reason and recommended action.
+//
+// Each signal's freshness decays linearly from 1 (just changed) to 0 (a full
+// window ago); the score is the best weighted freshness. While looping,
+// pane output and bead updates are churn rather than progress and are
+// ignored.
+func (p *AgentProgress) assess(now time.Time, cfg *ProgressConfig) {
+	looping := p.Looping(cfg.LoopSamples)
+
+	p.Score = 0
+	for signal, changed := range p.LastChange {
+		if looping && (signal == SignalPane || signal == SignalBead) {
+			continue
+		}
+		if s := signalWeights[signal] * freshness(now.Sub(changed), cfg.Window); s > p.Score {
+			p.Score = s
+		}
+	}
+
+	switch {
+	case looping && p.LoopCount >= 2*cfg.LoopSamples && p.Score < cfg.NudgeScore:
+		p.Action = ProgressEscalate
+	case looping:
+		p.Action = ProgressNudge
+	case p.Score == 0 && now.Sub(p.FirstSeen) >= cfg.Window:
+		p.Action = ProgressEscalate
+	case p.Score < cfg.NudgeScore:
+		p.Action = ProgressNudge
+	default:
+		p.Action = ProgressOK
+	}
+
+	// Respect cooldowns so a stuck agent isn't nagged every heartbeat
+	if p.Action == ProgressNudge && !p.LastNudge.IsZero() && now.Sub(p.LastNudge) < cfg.NudgeCooldown {
+		p.Action = ProgressOK
+	}
+	if p.Action == ProgressEscalate && !p.LastEscalation.IsZero() && now.Sub(p.LastEscalation) < cfg.Window {
+		p.Action = ProgressOK
+	}
+
+	p.Reason = p.reason(now, looping)
+}
+
+// reason summarizes the signals, freshest first.
+func (p *AgentProgress) reason(now time.Time, looping bool) string {
+	signals := make([]ProgressSignal, 0, len(p.LastChange))
+	for s := range p.LastChange {
+		signals = append(signals, s)
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		return p.LastChange[signals[i]].After(p.LastChange[signals[j]])
+	})
+
+	parts := make([]string, 0, len(signals))
+	for _, s := range signals {
+		parts = append(parts, fmt.Sprintf("%s %s ago", s, now.Sub(p.LastChange[s]).Round(time.Minute)))
+	}
+	summary := strings.Join(parts, ", ")
+	if summary == "" {
+		summary = "no signals available"
+	}
+
+	switch {
+	case looping:
+		what := "output keeps repeating"
+		if p.RepeatLine != "" {
+			what = fmt.Sprintf("output repeating %q (x%d)", truncateLine(p.RepeatLine, 60), p.RepeatSeen)
+		}
+		return fmt.Sprintf("looping for %d samples: %s; %s", p.LoopCount, what, summary)
+	case p.Score == 0:
+		return "no progress: " + summary
+	default:
+		return fmt.Sprintf("score %.2f: %s", p.Score, summary)
+	}
+}
+
+// RecordNudge records that the agent was nudged.
+func (p *AgentProgress) RecordNudge(at time.Time) {
+	p.LastNudge = at
+}
+
+// RecordEscalation records that the agent was escalated.
+func (p *AgentProgress) RecordEscalation(at time.Time) {
+	p.LastEscalation = at
+}
+
+// freshness maps a signal's age to [0,1].
+func freshness(age, window time.Duration) float64 {
+	if window <= 0 || age >= window {
+		return 0
+	}
+	if age < 0 {
+		return 1
+	}
+	return 1 - float64(age)/float64(window)
+}
+
+var (
+	digitsPattern  = regexp.MustCompile(`[0-9]+`)
+	spinnerPattern = regexp.MustCompile(`[✢✳✶✻✽·⠁-⣿◐◓◑◒|/\\-]+`)
+	letterPattern  = regexp.MustCompile(`[A-Za-z]`)
+)
+
+// normalizeLine strips counters, timers and spinner glyphs so output that
+// differs only in those compares equal.
+func normalizeLine(line string) string {
+	line = digitsPattern.ReplaceAllString(line, "#")
+	line = spinnerPattern.ReplaceAllString(line, " ")
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// paneShape hashes the normalized pane content.
+func paneShape(pane string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(pane, "\n") {
+		if n := normalizeLine(line); n != "" {
+			b.WriteString(n)
+			b.WriteByte('\n')
+		}
+	}
+	return hashString(b.String())
+}
+
+// repeatedLine returns the most frequent meaningful normalized line in the
+// pane and its count, if it appears at least repeatMinCount times.
+// Decoration (borders, blank lines) and very short lines are ignored.
+func repeatedLine(pane string) (string, int) {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(pane, "\n") {
+		n := normalizeLine(line)
+		if len(n) < 12 || len(letterPattern.FindAllString(n, -1)) < 6 {
+			continue
+		}
+		counts[n]++
+	}
+
+	best, bestCount := "", 0
+	for line, count := range counts {
+		if count > bestCount || (count == bestCount && line < best) {
+			best, bestCount = line, count
+		}
+	}
+	if bestCount < repeatMinCount {
+		return "", 0
+	}
+	return best, bestCount
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateLine(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,251 @@
+package deacon
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// paneCaptureLines is how much scrollback a progress sample captures.
+// Enough to see an error repeated across several attempts.
+const paneCaptureLines = 200
+
+// worktreeScanLimit bounds how many files a worktree scan stats, so a huge
+// checkout can't stall the heartbeat.
+const worktreeScanLimit = 20000
+
+// clockTicksPerSecond is USER_HZ, which is 100 on every Linux we run on.
+const clockTicksPerSecond = 100
+
+// worktreeSkipDirs are not scanned for file modifications: git metadata and
+// beads have their own signals, dependency trees are noise.
+var worktreeSkipDirs = map[string]bool{
+	".git":         true,
+	".beads":       true,
+	"node_modules": true,
+}
+
+// PaneSource is the tmux access progress sampling needs.
+type PaneSource interface {
+	CapturePane(session string, lines int) (string, error)
+	GetPanePID(session string) (string, error)
+}
+
+// ProgressCollector gathers progress samples for agent sessions.
+type ProgressCollector struct {
+	tmux PaneSource
+
+	// procRoot is the proc filesystem; overridable in tests.
+	procRoot string
+}
+
+// NewProgressCollector creates a collector using the given tmux access.
+func NewProgressCollector(tmux PaneSource) *ProgressCollector {
+	return &ProgressCollector{tmux: tmux, procRoot: "/proc"}
+}
+
+// Sample observes an agent's session and worktree. Signals that can't be
+// read are left zero; a sample is never an error.
+func (c *ProgressCollector) Sample(sessionName, worktree string, beadUpdated, now time.Time) ProgressSample {
+	sample := ProgressSample{At: now, BeadUpdated: beadUpdated}
+
+	if pane, err := c.tmux.CapturePane(sessionName, paneCaptureLines); err == nil {
+		sample.Pane = pane
+	}
+
+	if worktree != "" {
+		sample.WorktreeMtime = newestMtime(worktree)
+		sample.HeadCommit = headCommit(worktree)
+	}
+
+	if pidStr, err := c.tmux.GetPanePID(sessionName); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(pidStr)); err == nil {
+			if cpu, err := c.childCPUTime(pid); err == nil {
+				sample.CPUTime, sample.HasCPU = cpu, true
+			}
+		}
+	}
+
+	return sample
+}
+
+// newestMtime returns the most recent modification time of any file in
+// the worktree, skipping worktreeSkipDirs.
+func newestMtime(root string) time.Time {
+	var newest time.Time
+	scanned := 0
+	errLimit := errors.New("scan limit")
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries don't matter
+		}
+		if d.IsDir() {
+			if path != root && worktreeSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		scanned++
+		if scanned > worktreeScanLimit {
+			return errLimit
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest
+}
+
+// headCommit returns the worktree's HEAD commit, or "" if unavailable.
+func headCommit(worktree string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = worktree
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// procStat is the part of /proc/<pid>/stat progress sampling uses.
+type procStat struct {
+	pid  int
+	comm string
+	ppid int
+	cpu  time.Duration // utime + stime
+	// reaped is CPU of exited children that the process waited for
+	// (cutime + cstime).
+	reaped time.Duration
+}
+
+// parseProcStat parses a /proc/<pid>/stat line. The command name is in
+// parentheses and may itself contain spaces or parentheses.
+func parseProcStat(line string) (procStat, bool) {
+	open := strings.IndexByte(line, '(')
+	closing := strings.LastIndexByte(line, ')')
+	if open < 0 || closing < open {
+		return procStat{}, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:open]))
+	if err != nil {
+		return procStat{}, false
+	}
+
+	// Fields after comm start at field 3 (state); ppid is field 4,
+	// utime/stime 14-15, cutime/cstime 16-17.
+	fields := strings.Fields(line[closing+1:])
+	if len(fields) < 15 {
+		return procStat{}, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procStat{}, false
+	}
+	var ticks [4]int64
+	for i := range ticks {
+		if ticks[i], err = strconv.ParseInt(fields[11+i], 10, 64); err != nil {
+			return procStat{}, false
+		}
+	}
+
+	return procStat{
+		pid:    pid,
+		comm:   line[open+1 : closing],
+		ppid:   ppid,
+		cpu:    ticksToDuration(ticks[0] + ticks[1]),
+		reaped: ticksToDuration(ticks[2] + ticks[3]),
+	}, true
+}
+
+func ticksToDuration(ticks int64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicksPerSecond
+}
+
+// readProcTable reads every process's stat entry.
+func (c *ProgressCollector) readProcTable() ([]procStat, error) {
+	entries, err := os.ReadDir(c.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	var procs []procStat
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(c.procRoot, e.Name(), "stat")) //nolint:gosec // G304: /proc path
+		if err != nil {
+			continue // process exited
+		}
+		if st, ok := parseProcStat(string(data)); ok {
+			procs = append(procs, st)
+		}
+	}
+	if len(procs) == 0 {
+		return nil, errors.New("no processes readable")
+	}
+	return procs, nil
+}
+
+// childCPUTime returns the CPU time used by the processes the agent spawned.
+func (c *ProgressCollector) childCPUTime(panePID int) (time.Duration, error) {
+	procs, err := c.readProcTable()
+	if err != nil {
+		return 0, err
+	}
+	return agentChildCPU(procs, panePID), nil
+}
+
+// agentChildCPU sums CPU time of everything below the agent process,
+// including children that already exited (accounted to their parent when
+// reaped). The agent's own CPU is excluded: an agent thinking in circles
+// burns CPU too. When the pane runs a shell that launched the agent, the
+// shell's children are the agent processes.
+func agentChildCPU(procs []procStat, panePID int) time.Duration {
+	children := make(map[int][]procStat)
+	var pane *procStat
+	for i := range procs {
+		children[procs[i].ppid] = append(children[procs[i].ppid], procs[i])
+		if procs[i].pid == panePID {
+			pane = &procs[i]
+		}
+	}
+	if pane == nil {
+		return 0
+	}
+
+	agents := []procStat{*pane}
+	if isShell(pane.comm) {
+		agents = children[pane.pid]
+	}
+
+	var total time.Duration
+	var walk func(pid int)
+	walk = func(pid int) {
+		for _, child := range children[pid] {
+			total += child.cpu + child.reaped
+			walk(child.pid)
+		}
+	}
+	for _, agent := range agents {
+		total += agent.reaped
+		walk(agent.pid)
+	}
+	return total
+}
+
+func isShell(comm string) bool {
+	for _, shell := range constants.SupportedShells {
+		if comm == shell {
+			return true
+		}
+	}
+	return false
+}
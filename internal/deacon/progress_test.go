@@ -0,0 +1,236 @@
+package deacon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var progressStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// observeEvery feeds samples at 5-minute intervals and returns the actions.
+func observeEvery(p *AgentProgress, n int, sample func(i int, at time.Time) ProgressSample) []ProgressAction {
+	cfg := DefaultProgressConfig()
+	var actions []ProgressAction
+	for i := 0; i < n; i++ {
+		at := progressStart.Add(time.Duration(i) * 5 * time.Minute)
+		s := sample(i, at)
+		s.At = at
+		action := p.Observe(s, cfg)
+		switch action {
+		case ProgressNudge:
+			p.RecordNudge(at)
+		case ProgressEscalate:
+			p.RecordEscalation(at)
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func TestProgressQuietTestSuiteNotFlagged(t *testing.T) {
+	// A polecat running a 40-minute test suite: pane and bead unchanged,
+	// but its child processes keep burning CPU.
+	p := &AgentProgress{AgentID: "gt-gastown-polecat-Toast"}
+	actions := observeEvery(p, 9, func(i int, _ time.Time) ProgressSample {
+		return ProgressSample{
+			Pane:        "⏺ Bash(go test ./...)\n  ⎿  Running...",
+			HeadCommit:  "abc123",
+			CPUTime:     time.Duration(i) * time.Minute,
+			HasCPU:      true,
+			BeadUpdated: progressStart.Add(-time.Hour),
+		}
+	})
+	for i, a := range actions {
+		if a != ProgressOK {
+			t.Fatalf("sample %d: action = %s (%s), want ok", i, a, p.Reason)
+		}
+	}
+	if p.Score < 0.7 {
+		t.Errorf("Score = %.2f, want CPU-driven score >= 0.7", p.Score)
+	}
+}
+
+func TestProgressIdleAgentNudgedThenEscalated(t *testing.T) {
+	p := &AgentProgress{AgentID: "gt-gastown-polecat-Toast"}
+	actions := observeEvery(p, 8, func(int, time.Time) ProgressSample {
+		return ProgressSample{
+			Pane:       "> ",
+			HeadCommit: "abc123",
+			CPUTime:    time.Second,
+			HasCPU:     true,
+		}
+	})
+
+	want := []ProgressAction{
+		ProgressOK, ProgressOK, ProgressOK, ProgressOK, ProgressOK, // 0-20m
+		ProgressNudge,    // 25m: score below nudge threshold
+		ProgressEscalate, // 30m: nothing for a full window
+		ProgressOK,       // 35m: escalation cooldown
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("sample %d: action = %s, want %s (%s)", i, actions[i], want[i], p.Reason)
+		}
+	}
+	if !strings.HasPrefix(p.Reason, "no progress") {
+		t.Errorf("Reason = %q, want no-progress reason", p.Reason)
+	}
+}
+
+func TestProgressLoopingDespiteBeadUpdates(t *testing.T) {
+	// A polecat hitting the same error over and over while touching its
+	// bead: pane output and bead change every sample, nothing else does.
+	p := &AgentProgress{AgentID: "gt-gastown-polecat-Toast"}
+	actions := observeEvery(p, 7, func(i int, at time.Time) ProgressSample {
+		var pane strings.Builder
+		for j := 0; j < 3+i; j++ {
+			pane.WriteString("⏺ Bash(npm run build)\n")
+			pane.WriteString("  Error: Cannot find module 'left-pad' (attempt " + strings.Repeat("I", j+1) + ")\n")
+		}
+		return ProgressSample{
+			Pane:        pane.String(),
+			HeadCommit:  "abc123",
+			BeadUpdated: at,
+		}
+	})
+
+	if actions[3] != ProgressNudge {
+		t.Errorf("sample 3: action = %s, want nudge once looping (%s)", actions[3], p.Reason)
+	}
+	if actions[6] != ProgressEscalate {
+		t.Errorf("sample 6: action = %s, want escalate (%s)", actions[6], p.Reason)
+	}
+	if !p.Looping(DefaultLoopSamples) {
+		t.Errorf("Looping = false, LoopCount = %d", p.LoopCount)
+	}
+	if !strings.Contains(p.Reason, "looping") {
+		t.Errorf("Reason = %q, want looping reason", p.Reason)
+	}
+}
+
+func TestProgressCommitsResetScore(t *testing.T) {
+	p := &AgentProgress{AgentID: "gt-gastown-polecat-Toast"}
+	observeEvery(p, 6, func(i int, _ time.Time) ProgressSample {
+		head := "abc123"
+		if i == 5 {
+			head = "def456"
+		}
+		return ProgressSample{Pane: "> ", HeadCommit: head}
+	})
+	if p.Action != ProgressOK || p.Score != 1 {
+		t.Errorf("after new commit: action = %s, score = %.2f, want ok/1.00", p.Action, p.Score)
+	}
+}
+
+func TestPaneShapeIgnoresCountersAndSpinners(t *testing.T) {
+	a := "✻ Thinking… (12s · esc to interrupt)\n  tokens: 1024"
+	b := "✶ Thinking… (47s · esc to interrupt)\n  tokens: 9001"
+	if paneShape(a) != paneShape(b) {
+		t.Error("panes differing only in counters/spinner should share a shape")
+	}
+	if paneShape(a) == paneShape("✻ Writing tests…") {
+		t.Error("different output should have different shapes")
+	}
+}
+
+func TestRepeatedLine(t *testing.T) {
+	pane := strings.Repeat("Error: connection refused on port 5432\n────────\n", 4) + "> "
+	line, count := repeatedLine(pane)
+	if count != 4 || !strings.Contains(line, "connection refused") {
+		t.Errorf("repeatedLine() = %q, %d", line, count)
+	}
+
+	if line, count := repeatedLine("one line\nanother line\n────\n────\n────"); line != "" || count != 0 {
+		t.Errorf("decoration should not count as repetition, got %q x%d", line, count)
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	st, ok := parseProcStat("4242 (node (worker)) S 4200 4242 4200 0 -1 4194304 107 0 0 0 250 50 30 20 20 0 1 0")
+	if !ok {
+		t.Fatal("parseProcStat failed")
+	}
+	if st.pid != 4242 || st.ppid != 4200 || st.comm != "node (worker)" {
+		t.Errorf("got pid=%d ppid=%d comm=%q", st.pid, st.ppid, st.comm)
+	}
+	if st.cpu != 3*time.Second || st.reaped != 500*time.Millisecond {
+		t.Errorf("cpu = %v, reaped = %v", st.cpu, st.reaped)
+	}
+
+	if _, ok := parseProcStat("garbage"); ok {
+		t.Error("parseProcStat should reject malformed input")
+	}
+}
+
+func TestAgentChildCPU(t *testing.T) {
+	procs := []procStat{
+		{pid: 100, comm: "bash", ppid: 1, cpu: time.Second},
+		{pid: 101, comm: "claude", ppid: 100, cpu: time.Hour, reaped: 5 * time.Second},
+		{pid: 102, comm: "go", ppid: 101, cpu: 10 * time.Second},
+		{pid: 103, comm: "test.binary", ppid: 102, cpu: 20 * time.Second},
+		{pid: 200, comm: "unrelated", ppid: 1, cpu: time.Hour},
+	}
+
+	// Shell pane: claude is the agent, its descendants and reaped children count
+	if got := agentChildCPU(procs, 100); got != 35*time.Second {
+		t.Errorf("agentChildCPU(shell pane) = %v, want 35s", got)
+	}
+	// Agent directly in the pane
+	if got := agentChildCPU(procs, 101); got != 35*time.Second {
+		t.Errorf("agentChildCPU(agent pane) = %v, want 35s", got)
+	}
+	if got := agentChildCPU(procs, 999); got != 0 {
+		t.Errorf("agentChildCPU(missing) = %v, want 0", got)
+	}
+}
+
+func TestNewestMtimeSkipsGitDir(t *testing.T) {
+	dir := t.TempDir()
+	old := progressStart.Add(-time.Hour)
+	recent := progressStart
+
+	src := filepath.Join(dir, "main.go")
+	gitFile := filepath.Join(dir, ".git", "index")
+	if err := os.MkdirAll(filepath.Dir(gitFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, mtime := range map[string]time.Time{src: old, gitFile: recent} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := newestMtime(dir); !got.Equal(old) {
+		t.Errorf("newestMtime() = %v, want %v (.git ignored)", got, old)
+	}
+}
+
+func TestSaveAndLoadProgressState(t *testing.T) {
+	tmpDir := t.TempDir()
+	state := &ProgressState{}
+	p := state.GetAgent("gt-gastown-polecat-Toast")
+	p.Observe(ProgressSample{At: progressStart, Pane: "hello", HeadCommit: "abc"}, DefaultProgressConfig())
+
+	if err := SaveProgressState(tmpDir, state); err != nil {
+		t.Fatalf("SaveProgressState() error = %v", err)
+	}
+	loaded, err := LoadProgressState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadProgressState() error = %v", err)
+	}
+	got := loaded.Agents["gt-gastown-polecat-Toast"]
+	if got == nil || got.HeadCommit != "abc" || !got.LastChange[SignalPane].Equal(progressStart) {
+		t.Errorf("loaded agent = %+v", got)
+	}
+
+	loaded.Prune(progressStart.Add(time.Minute))
+	if len(loaded.Agents) != 0 {
+		t.Errorf("Prune left %d agents", len(loaded.Agents))
+	}
+}
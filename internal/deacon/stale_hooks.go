@@ -2,12 +2,11 @@
 package deacon
 
 import (
-	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -122,28 +121,27 @@ func ScanStaleHooks(townRoot string, cfg *StaleHookConfig) (*StaleHookScanResult
 
 // listHookedBeads returns all beads with status=hooked.
 func listHookedBeads(townRoot string) ([]*HookedBead, error) {
-	cmd := exec.Command("bd", "list", "--status=hooked", "--json", "--limit=0")
-	cmd.Dir = townRoot
-
-	output, err := cmd.Output()
+	issues, err := beads.New(townRoot).List(beads.ListOptions{
+		Status:   "hooked",
+		NoLimit:  true,
+		Priority: -1,
+	})
 	if err != nil {
-		// No hooked beads is not an error
-		if strings.Contains(string(output), "no issues found") {
-			return nil, nil
-		}
 		return nil, err
 	}
 
-	if len(output) == 0 || string(output) == "[]" || string(output) == "null\n" {
-		return nil, nil
+	hooked := make([]*HookedBead, 0, len(issues))
+	for _, issue := range issues {
+		updated, _ := time.Parse(time.RFC3339, issue.UpdatedAt)
+		hooked = append(hooked, &HookedBead{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Assignee:  issue.Assignee,
+			UpdatedAt: updated,
+		})
 	}
-
-	var beads []*HookedBead
-	if err := json.Unmarshal(output, &beads); err != nil {
-		return nil, fmt.Errorf("parsing hooked beads: %w", err)
-	}
-
-	return beads, nil
+	return hooked, nil
 }
 
 // assigneeToSessionName converts an assignee address to a tmux session name.
@@ -189,7 +187,6 @@ func assigneeToSessionName(assignee string) string {
 
 // unhookBead sets a bead's status back to 'open'.
 func unhookBead(townRoot, beadID string) error {
-	cmd := exec.Command("bd", "update", beadID, "--status=open")
-	cmd.Dir = townRoot
-	return cmd.Run()
+	status := "open"
+	return beads.New(townRoot).Update(beadID, beads.UpdateOptions{Status: &status})
 }
@@ -0,0 +1,161 @@
+// Package depgraph orders work by its blocking dependencies. It layers a
+// set of issues with Kahn's algorithm, reporting dependency cycles, and
+// finds the critical path: the chain with the most remaining work. Swarm
+// wave planning and convoy ETAs share it.
+package depgraph
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Graph is a set of nodes ordered by their dependencies.
+type Graph struct {
+	// Layers lists the nodes in dependency order. The first layer holds
+	// nodes with no dependencies in the set; every node in layer n
+	// depends only on nodes in earlier layers. Each layer is sorted.
+	Layers [][]string
+
+	layer      map[string]int      // Node → layer
+	deps       map[string][]string // Node → dependencies in the set, sorted
+	dependents map[string][]string // Node → nodes that depend on it, sorted
+}
+
+// CycleError reports nodes that depend on each other, directly or not,
+// and so can never be ordered. It also names nodes that wait on a cycle.
+type CycleError struct {
+	Nodes []string // Sorted
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle among " + strings.Join(e.Nodes, ", ")
+}
+
+// New orders nodes by the dependencies deps reports for each. Dependencies
+// on nodes outside the set, and on the node itself, are ignored, as are
+// repeated nodes. A cycle is a *CycleError.
+func New(nodes []string, deps func(id string) []string) (*Graph, error) {
+	g := &Graph{
+		layer:      make(map[string]int, len(nodes)),
+		deps:       make(map[string][]string, len(nodes)),
+		dependents: make(map[string][]string),
+	}
+	var ids []string
+	for _, id := range nodes {
+		if _, dup := g.deps[id]; !dup {
+			g.deps[id] = nil
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		for _, dep := range deps(id) {
+			if _, ok := g.deps[dep]; !ok || dep == id || slices.Contains(g.deps[id], dep) {
+				continue
+			}
+			g.deps[id] = append(g.deps[id], dep)
+			g.dependents[dep] = append(g.dependents[dep], id)
+		}
+	}
+	for _, id := range ids {
+		sort.Strings(g.deps[id])
+		sort.Strings(g.dependents[id])
+	}
+
+	// Kahn's algorithm, one layer at a time
+	indegree := make(map[string]int, len(ids))
+	var layer []string
+	for _, id := range ids {
+		indegree[id] = len(g.deps[id])
+		if indegree[id] == 0 {
+			layer = append(layer, id)
+		}
+	}
+	for len(layer) > 0 {
+		sort.Strings(layer)
+		n := len(g.Layers)
+		g.Layers = append(g.Layers, layer)
+		var next []string
+		for _, id := range layer {
+			g.layer[id] = n
+			for _, d := range g.dependents[id] {
+				indegree[d]--
+				if indegree[d] == 0 {
+					next = append(next, d)
+				}
+			}
+		}
+		layer = next
+	}
+
+	if len(g.layer) < len(ids) {
+		cycle := &CycleError{}
+		for _, id := range ids {
+			if _, ok := g.layer[id]; !ok {
+				cycle.Nodes = append(cycle.Nodes, id)
+			}
+		}
+		sort.Strings(cycle.Nodes)
+		return nil, cycle
+	}
+	return g, nil
+}
+
+// LayerOf returns the layer a node is in, or -1 if it isn't in the graph.
+func (g *Graph) LayerOf(id string) int {
+	if l, ok := g.layer[id]; ok {
+		return l
+	}
+	return -1
+}
+
+// Deps returns a node's dependencies in the graph, sorted.
+func (g *Graph) Deps(id string) []string {
+	return g.deps[id]
+}
+
+// Path is a chain of nodes, first to last, each depending on the one
+// before it.
+type Path []string
+
+// Contains reports whether a node is on the path.
+func (p Path) Contains(id string) bool {
+	return slices.Contains(p, id)
+}
+
+// CriticalPath returns the chain with the largest total weight, and for
+// each node the weight of the heaviest chain starting at it, the node
+// included. Ties go to the node first in layer order. Nodes of zero
+// weight (finished work) can link a chain but never start one, so when
+// nothing weighs anything the path is empty.
+func CriticalPath[W ~int | ~int64 | ~float64](g *Graph, weight func(id string) W) (Path, map[string]W) {
+	chain := make(map[string]W, len(g.layer))
+	next := make(map[string]string, len(g.layer))
+	// Last layer first, so every dependent is scored before its dependencies
+	for l := len(g.Layers) - 1; l >= 0; l-- {
+		for _, id := range g.Layers[l] {
+			var best W
+			for _, d := range g.dependents[id] {
+				if chain[d] > best {
+					best, next[id] = chain[d], d
+				}
+			}
+			chain[id] = best + weight(id)
+		}
+	}
+
+	var start string
+	var longest W
+	for _, layer := range g.Layers {
+		for _, id := range layer {
+			if chain[id] > longest {
+				start, longest = id, chain[id]
+			}
+		}
+	}
+	var path Path
+	for id := start; id != ""; id = next[id] {
+		path = append(path, id)
+	}
+	return path, chain
+}
@@ -0,0 +1,71 @@
+package depgraph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func deps(edges map[string][]string) func(string) []string {
+	return func(id string) []string { return edges[id] }
+}
+
+func TestNew(t *testing.T) {
+	edges := map[string][]string{
+		"d": {"b", "c", "b"},
+		"b": {"a"},
+		"c": {"a", "outside", "c"},
+	}
+	g, err := New([]string{"d", "b", "c", "a", "e", "a"}, deps(edges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "e"}, {"b", "c"}, {"d"}}
+	if !reflect.DeepEqual(g.Layers, want) {
+		t.Errorf("Layers = %v, want %v", g.Layers, want)
+	}
+	if got := g.Deps("d"); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("Deps(d) = %v", got)
+	}
+	if got := g.Deps("c"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Deps(c) = %v, want outside and self edges dropped", got)
+	}
+	if g.LayerOf("d") != 2 || g.LayerOf("outside") != -1 {
+		t.Errorf("LayerOf(d), LayerOf(outside) = %d, %d", g.LayerOf("d"), g.LayerOf("outside"))
+	}
+}
+
+func TestNew_Cycle(t *testing.T) {
+	edges := map[string][]string{"x": {"y"}, "y": {"x"}, "w": {"x"}}
+	_, err := New([]string{"y", "x", "w", "z"}, deps(edges))
+	var cycle *CycleError
+	if !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Nodes, []string{"w", "x", "y"}) {
+		t.Errorf("err = %v, want a cycle naming w, x and y", err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	// a → b → d and a → c → d; c is slow
+	edges := map[string][]string{"b": {"a"}, "c": {"a"}, "d": {"b", "c"}}
+	g, err := New([]string{"a", "b", "c", "d", "e"}, deps(edges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	weight := map[string]int{"a": 1, "b": 1, "c": 5, "d": 1, "e": 2}
+	path, chain := CriticalPath(g, func(id string) int { return weight[id] })
+	if !reflect.DeepEqual(path, Path{"a", "c", "d"}) {
+		t.Errorf("path = %v", path)
+	}
+	if chain["a"] != 7 || chain["b"] != 2 || chain["e"] != 2 {
+		t.Errorf("chain = %v", chain)
+	}
+	if !path.Contains("c") || path.Contains("b") {
+		t.Error("Contains wrong")
+	}
+
+	// Finished work links chains but starts none
+	path, _ = CriticalPath(g, func(string) int { return 0 })
+	if len(path) != 0 {
+		t.Errorf("path with nothing left = %v", path)
+	}
+}
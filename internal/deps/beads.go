@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // MinBeadsVersion is the minimum compatible beads version for this Gas Town release.
@@ -37,7 +39,7 @@ func CheckBeads() (BeadsStatus, string) {
 	_ = path // bd found
 
 	// Get version
-	cmd := exec.Command("bd", "version")
+	cmd := beads.Command("version")
 	output, err := cmd.Output()
 	if err != nil {
 		return BeadsUnknown, ""
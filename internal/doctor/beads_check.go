@@ -1,7 +1,6 @@
 package doctor
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -129,11 +128,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Run bd sync to rebuild from JSONL
-		cmd := exec.Command("bd", "sync", "--from-main")
-		cmd.Dir = ctx.TownRoot
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
+		if err := beads.New(ctx.TownRoot).SyncFromMain(); err != nil {
 			return err
 		}
 	}
@@ -152,11 +147,7 @@ func (c *BeadsDatabaseCheck) Fix(ctx *CheckContext) error {
 				return err
 			}
 
-			cmd := exec.Command("bd", "sync", "--from-main")
-			cmd.Dir = ctx.RigPath()
-			var stderr bytes.Buffer
-			cmd.Stderr = &stderr
-			if err := cmd.Run(); err != nil {
+			if err := beads.New(ctx.RigPath()).SyncFromMain(); err != nil {
 				return err
 			}
 		}
@@ -178,6 +169,7 @@ func NewPrefixConflictCheck() *PrefixConflictCheck {
 			CheckName:        "prefix-conflict",
 			CheckDescription: "Check for duplicate beads prefixes across rigs",
 			CheckCategory:    CategoryConfig,
+			CheckDependsOn:   []string{"beads-database"},
 		},
 	}
 }
@@ -246,6 +238,7 @@ func NewPrefixMismatchCheck() *PrefixMismatchCheck {
 				CheckName:        "prefix-mismatch",
 				CheckDescription: "Check for prefix mismatches between rigs.json and routes.jsonl",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"beads-database"},
 			},
 		},
 	}
@@ -345,19 +338,29 @@ func (c *PrefixMismatchCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix updates rigs.json to match the prefixes in routes.jsonl.
 func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans rewriting rigs.json with the prefixes from routes.jsonl.
+func (c *PrefixMismatchCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
 
 	// Load routes.jsonl
 	routes, err := beads.LoadRoutes(beadsDir)
 	if err != nil || len(routes) == 0 {
-		return nil // Nothing to fix
+		return plan, nil // Nothing to fix
 	}
 
 	// Load rigs.json
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
 	rigsConfig, err := loadRigsConfig(rigsPath)
 	if err != nil {
-		return nil // Nothing to fix
+		return plan, nil // Nothing to fix
 	}
 
 	// Build map of route path -> prefix from routes.jsonl
@@ -389,10 +392,14 @@ func (c *PrefixMismatchCheck) Fix(ctx *CheckContext) error {
 	}
 
 	if modified {
-		return saveRigsConfig(rigsPath, rigsConfig)
+		data, err := json.MarshalIndent(rigsConfig, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		plan.WriteFile(rigsPath, data, 0644)
 	}
 
-	return nil
+	return plan, nil
 }
 
 // rigsConfigEntry is a local type for loading rigs.json without importing config package
@@ -428,15 +435,6 @@ func loadRigsConfig(path string) (*rigsConfigFile, error) {
 	return &cfg, nil
 }
 
-func saveRigsConfig(path string, cfg *rigsConfigFile) error {
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(path, data, 0644)
-}
-
 // beadShower is an interface for fetching bead information.
 // Allows mocking in tests.
 type beadShower interface {
@@ -453,10 +451,8 @@ type labelAdder interface {
 type realLabelAdder struct{}
 
 func (r *realLabelAdder) AddLabel(townRoot, id, label string) error {
-	cmd := exec.Command("bd", "label", "add", id, label)
-	cmd.Dir = townRoot
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("adding %s label to %s: %s", label, id, strings.TrimSpace(string(output)))
+	if err := beads.New(townRoot).AddLabels(id, label); err != nil {
+		return fmt.Errorf("adding %s label to %s: %w", label, id, err)
 	}
 	return nil
 }
@@ -482,6 +478,7 @@ func NewRoleLabelCheck() *RoleLabelCheck {
 				CheckName:        "role-bead-labels",
 				CheckDescription: "Check that role beads have gt:role label",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"beads-database"},
 			},
 		},
 		labelAdder: &realLabelAdder{},
@@ -567,10 +564,20 @@ func (c *RoleLabelCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix adds the gt:role label to role beads that are missing it.
 func (c *RoleLabelCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans adding the gt:role label to each role bead missing it.
+func (c *RoleLabelCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	for _, roleID := range c.missingLabel {
-		if err := c.labelAdder.AddLabel(c.townRoot, roleID, "gt:role"); err != nil {
-			return err
-		}
+		plan.UpdateBead(roleID, "add label gt:role", func() error {
+			return c.labelAdder.AddLabel(c.townRoot, roleID, "gt:role")
+		})
 	}
-	return nil
+	return plan, nil
 }
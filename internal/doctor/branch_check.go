@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // BranchCheck detects persistent roles (crew, witness, refinery) that are
@@ -341,6 +342,7 @@ func NewCloneDivergenceCheck() *CloneDivergenceCheck {
 			CheckName:        "clone-divergence",
 			CheckDescription: "Detect emergency divergence between git clones",
 			CheckCategory:    CategoryCleanup,
+			CheckTimeout:     2 * time.Minute, // fetches from origin
 		},
 	}
 }
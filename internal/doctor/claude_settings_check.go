@@ -440,67 +440,83 @@ func (c *ClaudeSettingsCheck) hookHasPattern(hooks map[string]any, hookName, pat
 // Fix deletes stale settings files and restarts affected agents.
 // Files with local modifications are skipped to avoid losing user changes.
 func (c *ClaudeSettingsCheck) Fix(ctx *CheckContext) error {
-	var errors []string
-	var skipped []string
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	if err := plan.Apply(nil); err != nil {
+		return err
+	}
+	for _, note := range plan.Notes {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), note)
+	}
+	return nil
+}
+
+// PlanFix plans replacing stale settings with the role's template, moving
+// town-root files into mayor/, and (with --restart-sessions) cycling patrol
+// agents so they pick up the new settings.
+func (c *ClaudeSettingsCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	t := tmux.NewTmux()
+	movedTownRoot := false
 
 	for _, sf := range c.staleSettings {
 		// Skip files with local modifications - require manual review
 		if sf.wrongLocation && sf.gitStatus == gitStatusTrackedModified {
-			skipped = append(skipped, fmt.Sprintf("%s: has local modifications, skipping", sf.path))
-			continue
-		}
-
-		// Delete the stale settings file
-		if err := os.Remove(sf.path); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to delete %s: %v", sf.path, err))
+			plan.Note("%s: has local modifications, skipping", sf.path)
 			continue
 		}
 
-		// Also delete parent .claude directory if empty
 		claudeDir := filepath.Dir(sf.path)
-		_ = os.Remove(claudeDir) // Best-effort, will fail if not empty
 
 		// For files in wrong locations, delete and create at correct location
 		if sf.wrongLocation {
+			plan.DeleteFile(sf.path)
+			if filepath.Base(claudeDir) == ".claude" {
+				plan.RemoveEmptyDir(claudeDir)
+			}
 			mayorDir := filepath.Join(ctx.TownRoot, "mayor")
 
 			// For mayor settings.json at town root, create at mayor/.claude/
+			// unless the mayor already has its own.
 			if sf.agentType == "mayor" && strings.HasSuffix(claudeDir, ".claude") && !strings.Contains(sf.path, "/mayor/") {
-				if err := os.MkdirAll(mayorDir, 0755); err == nil {
-					_ = claude.EnsureSettingsForRole(mayorDir, "mayor")
+				mayorSettings := filepath.Join(mayorDir, ".claude", "settings.json")
+				if !fileExists(mayorSettings) {
+					content, err := claude.SettingsTemplate(claude.RoleTypeFor("mayor"))
+					if err != nil {
+						return nil, err
+					}
+					plan.WriteFile(mayorSettings, content, 0600)
 				}
 			}
 
 			// For mayor CLAUDE.md at town root, create at mayor/
 			if sf.agentType == "mayor" && strings.HasSuffix(sf.path, "CLAUDE.md") && !strings.Contains(sf.path, "/mayor/") {
 				townName, _ := workspace.GetTownName(ctx.TownRoot)
-				if err := templates.CreateMayorCLAUDEmd(
+				content, err := templates.RenderMayorCLAUDEmd(
 					mayorDir,
 					ctx.TownRoot,
 					townName,
 					session.MayorSessionName(),
 					session.DeaconSessionName(),
-				); err != nil {
-					errors = append(errors, fmt.Sprintf("failed to create mayor/CLAUDE.md: %v", err))
+				)
+				if err != nil {
+					return nil, fmt.Errorf("rendering mayor/CLAUDE.md: %w", err)
 				}
+				plan.WriteFile(filepath.Join(mayorDir, "CLAUDE.md"), []byte(content), 0644)
 			}
 
-			// Town-root files were inherited by ALL agents via directory traversal.
-			// Warn user to restart agents - don't auto-kill sessions as that's too disruptive,
-			// especially since deacon runs gt doctor automatically which would create a loop.
-			// Settings are only read at startup, so running agents already have config loaded.
-			fmt.Printf("\n  %s Town-root settings were moved. Restart agents to pick up new config:\n", style.Warning.Render("⚠"))
-			fmt.Printf("      gt up --restart\n\n")
+			movedTownRoot = true
 			continue
 		}
 
-		// Recreate settings using EnsureSettingsForRole
-		workDir := filepath.Dir(claudeDir) // agent work directory
-		if err := claude.EnsureSettingsForRole(workDir, sf.agentType); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to recreate settings for %s: %v", sf.path, err))
-			continue
+		// Replace the stale settings with the role's template
+		content, err := claude.SettingsTemplate(claude.RoleTypeFor(sf.agentType))
+		if err != nil {
+			return nil, err
 		}
+		plan.WriteFile(sf.path, content, 0600)
 
 		// Only cycle patrol roles if --restart-sessions was explicitly passed.
 		// This prevents unexpected session restarts during routine --fix operations.
@@ -511,23 +527,23 @@ func (c *ClaudeSettingsCheck) Fix(ctx *CheckContext) error {
 				running, _ := t.HasSession(sf.sessionName)
 				if running {
 					// Cycle the agent by killing and letting gt up restart it
-					_ = t.KillSession(sf.sessionName)
+					name := sf.sessionName
+					plan.RestartSession(name, "pick up new settings", func() error {
+						return t.KillSession(name)
+					})
 				}
 			}
 		}
 	}
 
-	// Report skipped files as warnings, not errors
-	if len(skipped) > 0 {
-		for _, s := range skipped {
-			fmt.Printf("  Warning: %s\n", s)
-		}
-	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	// Town-root files were inherited by ALL agents via directory traversal.
+	// Warn user to restart agents - don't auto-kill sessions as that's too disruptive,
+	// especially since deacon runs gt doctor automatically which would create a loop.
+	// Settings are only read at startup, so running agents already have config loaded.
+	if movedTownRoot {
+		plan.Note("Town-root settings were moved. Restart agents to pick up new config: gt up --restart")
 	}
-	return nil
+	return plan, nil
 }
 
 // fileExists checks if a file exists.
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 )
 
@@ -554,6 +555,7 @@ func NewCustomTypesCheck() *CustomTypesCheck {
 				CheckName:        "beads-custom-types",
 				CheckDescription: "Check that Gas Town custom types are registered with beads",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"beads-database"},
 			},
 		},
 	}
@@ -581,10 +583,7 @@ func (c *CustomTypesCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Get current custom types configuration
-	// Use Output() not CombinedOutput() to avoid capturing bd's stderr messages
-	cmd := exec.Command("bd", "config", "get", "types.custom")
-	cmd.Dir = ctx.TownRoot
-	output, err := cmd.Output()
+	output, err := beads.New(ctx.TownRoot).ConfigGet("types.custom")
 	if err != nil {
 		// If config key doesn't exist, types are not configured
 		c.townRoot = ctx.TownRoot
@@ -602,7 +601,7 @@ func (c *CustomTypesCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Parse configured types, filtering out bd "Note:" messages that may appear in stdout
-	configuredTypes := parseConfigOutput(output)
+	configuredTypes := parseConfigOutput([]byte(output))
 	configuredSet := make(map[string]bool)
 	for _, t := range strings.Split(configuredTypes, ",") {
 		configuredSet[strings.TrimSpace(t)] = true
@@ -655,11 +654,8 @@ func parseConfigOutput(output []byte) string {
 
 // Fix registers the missing custom types.
 func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
-	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-	cmd.Dir = c.townRoot
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
+	if err := beads.New(c.townRoot).ConfigSet("types.custom", constants.BeadsCustomTypes); err != nil {
+		return fmt.Errorf("bd config set types.custom: %w", err)
 	}
 	return nil
 }
@@ -143,34 +143,34 @@ func (c *CrewStateCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix regenerates invalid state.json files with correct values.
 func (c *CrewStateCheck) Fix(ctx *CheckContext) error {
-	if len(c.invalidCrews) == 0 {
-		return nil
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
 	}
+	return plan.Apply(nil)
+}
 
-	var lastErr error
+// PlanFix plans rewriting each invalid state.json.
+func (c *CrewStateCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
+	now := time.Now().Format(time.RFC3339)
 	for _, ic := range c.invalidCrews {
 		state := map[string]interface{}{
 			"name":       ic.crewName,
 			"rig":        ic.rigName,
 			"clone_path": ic.path,
 			"branch":     "main",
-			"created_at": time.Now().Format(time.RFC3339),
-			"updated_at": time.Now().Format(time.RFC3339),
+			"created_at": now,
+			"updated_at": now,
 		}
 
 		data, err := json.MarshalIndent(state, "", "  ")
 		if err != nil {
-			lastErr = fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
-			continue
-		}
-
-		if err := os.WriteFile(ic.stateFile, data, 0644); err != nil {
-			lastErr = fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
-			continue
+			return nil, fmt.Errorf("%s/%s: %w", ic.rigName, ic.crewName, err)
 		}
+		plan.WriteFile(ic.stateFile, data, 0644)
 	}
-
-	return lastErr
+	return plan, nil
 }
 
 type crewDir struct {
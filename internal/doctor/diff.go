@@ -0,0 +1,154 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// maxDiffCells bounds the LCS table; larger files are shown as a full
+// replacement rather than a minimal diff.
+const maxDiffCells = 4_000_000
+
+// diffLine is one line of an edit script.
+type diffLine struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// writeUnifiedDiff writes a unified diff between two texts. Nothing is
+// written when they are equal.
+func writeUnifiedDiff(w io.Writer, from, to, a, b string) {
+	if a == b {
+		return
+	}
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	_, _ = fmt.Fprintf(w, "--- %s\n+++ %s\n", from, to)
+	for start := 0; start < len(edits); {
+		// Find the next change
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// Extend the hunk while changes are within 2*context of each other
+		lo := max(start-diffContext, 0)
+		hi := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].op != ' ' {
+				hi = i
+			} else if i-hi > 2*diffContext {
+				break
+			}
+		}
+		hi = min(hi+diffContext+1, len(edits))
+
+		aStart, bStart := lineNumbers(edits, lo)
+		var aCount, bCount int
+		for _, e := range edits[lo:hi] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		_, _ = fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, e := range edits[lo:hi] {
+			_, _ = fmt.Fprintf(w, "%c%s\n", e.op, e.text)
+		}
+		start = hi
+	}
+}
+
+// lineNumbers returns the 1-based line numbers in a and b at edit index i.
+func lineNumbers(edits []diffLine, i int) (int, int) {
+	a, b := 1, 1
+	for _, e := range edits[:i] {
+		if e.op != '+' {
+			a++
+		}
+		if e.op != '-' {
+			b++
+		}
+	}
+	return a, b
+}
+
+// hunkRange formats a hunk range; empty ranges point at the line before.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes an edit script from a to b using the longest common
+// subsequence of lines.
+func diffLines(a, b []string) []diffLine {
+	if len(a)*len(b) > maxDiffCells {
+		edits := make([]diffLine, 0, len(a)+len(b))
+		for _, l := range a {
+			edits = append(edits, diffLine{'-', l})
+		}
+		for _, l := range b {
+			edits = append(edits, diffLine{'+', l})
+		}
+		return edits
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, diffLine{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, diffLine{'+', b[j]})
+	}
+	return edits
+}
@@ -1,14 +1,40 @@
 package doctor
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errFixTimedOut is returned for a fix that ran past its timeout. The fix
+// can't be stopped and keeps running in the background.
+var errFixTimedOut = errors.New("timed out")
+
+// DefaultWorkers is how many checks run concurrently by default.
+const DefaultWorkers = 8
+
+// DefaultCheckTimeout bounds a single check (and its fix) unless the check
+// declares its own timeout.
+const DefaultCheckTimeout = 30 * time.Second
+
 // Doctor manages and executes health checks.
 type Doctor struct {
-	checks []Check
+	checks  []Check
+	workers int
+	timeout time.Duration
+	journal *Journal // Records applied fixes for rollback
+	dryRun  bool     // Fix plans fixes without applying them
 }
 
 // NewDoctor creates a new Doctor with no registered checks.
 func NewDoctor() *Doctor {
 	return &Doctor{
-		checks: make([]Check, 0),
+		checks:  make([]Check, 0),
+		workers: DefaultWorkers,
+		timeout: DefaultCheckTimeout,
 	}
 }
 
@@ -27,73 +53,473 @@ func (d *Doctor) Checks() []Check {
 	return d.checks
 }
 
+// Unplanned returns the names of fixable checks whose fix doesn't implement
+// Planner. Their fixes run directly: a dry run can't show what they would
+// change, and rollback can't undo them.
+func (d *Doctor) Unplanned() []string {
+	var names []string
+	for _, check := range d.checks {
+		if _, ok := check.(Planner); check.CanFix() && !ok {
+			names = append(names, check.Name())
+		}
+	}
+	return names
+}
+
+// Select keeps only the named checks, in registration order. Naming a
+// check that isn't registered is an error so typos don't pass silently.
+func (d *Doctor) Select(names []string) error {
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		want[name] = true
+	}
+	var selected []Check
+	for _, check := range d.checks {
+		if want[check.Name()] {
+			selected = append(selected, check)
+			delete(want, check.Name())
+		}
+	}
+	if len(want) > 0 {
+		unknown := make([]string, 0, len(want))
+		for name := range want {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown check(s): %s", strings.Join(unknown, ", "))
+	}
+	d.checks = selected
+	return nil
+}
+
+// SetWorkers sets how many checks may run at once. Values below 1 run
+// checks one at a time.
+func (d *Doctor) SetWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	d.workers = n
+}
+
+// SetTimeout sets the default per-check timeout. Zero disables timeouts
+// for checks that don't declare their own.
+func (d *Doctor) SetTimeout(timeout time.Duration) {
+	d.timeout = timeout
+}
+
+// SetJournal records fixes applied by Fix in j.
+func (d *Doctor) SetJournal(j *Journal) {
+	d.journal = j
+}
+
+// SetDryRun makes Fix plan fixes without applying them. Each fixable
+// failing check's result carries its Plan.
+func (d *Doctor) SetDryRun(dryRun bool) {
+	d.dryRun = dryRun
+}
+
 // categoryGetter interface for checks that provide a category
 type categoryGetter interface {
 	Category() string
 }
 
+// CategoryOf returns a check's category, or "" if it doesn't declare one.
+func CategoryOf(check Check) string {
+	if cg, ok := check.(categoryGetter); ok {
+		return cg.Category()
+	}
+	return ""
+}
+
+// dependencyGetter interface for checks that must run after other checks.
+// A check whose dependency fails (or is skipped) is skipped.
+type dependencyGetter interface {
+	DependsOn() []string
+}
+
+// timeoutGetter interface for checks that need a different timeout than
+// the doctor's default.
+type timeoutGetter interface {
+	Timeout() time.Duration
+}
+
 // Run executes all registered checks and returns a report.
+// Independent checks run concurrently; the report lists results in
+// registration order.
 func (d *Doctor) Run(ctx *CheckContext) *Report {
-	report := NewReport()
+	return d.execute(ctx, false)
+}
 
-	for _, check := range d.checks {
-		result := check.Run(ctx)
-		// Ensure check name is populated
-		if result.Name == "" {
-			result.Name = check.Name()
+// Fix runs all checks with auto-fix enabled where possible.
+// Checks run first, concurrently. Fixes are then applied one at a time,
+// in registration order with each check's dependencies first, so a fix
+// can rely on the fixes of the checks before it. A check skipped because
+// a dependency failed runs once that dependency is fixed. Fixes expressed
+// as a Plan are recorded in the journal if one is set.
+func (d *Doctor) Fix(ctx *CheckContext) *Report {
+	return d.execute(ctx, true)
+}
+
+// execute schedules checks over a worker pool, honoring dependencies, then
+// in fix mode fixes failures in order.
+func (d *Doctor) execute(ctx *CheckContext, fix bool) *Report {
+	deps, cyclic := d.resolveDependencies()
+
+	results := make([]*CheckResult, len(d.checks))
+	waiting := make([]bool, len(d.checks)) // Skipped for a failed dependency
+	done := make([]chan struct{}, len(d.checks))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	workers := d.workers
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, check := range d.checks {
+		if cyclic[i] {
+			results[i] = d.annotate(check, &CheckResult{
+				Status:  StatusError,
+				Message: "dependency cycle",
+				FixHint: "Check registration error: " + check.Name() + " depends on itself through other checks",
+			})
+			close(done[i])
+			continue
 		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
+
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range deps[i] {
+				<-done[dep]
+			}
+			if r := failedDependency(deps[i], results); r != nil {
+				results[i] = d.skip(check, r)
+				waiting[i] = true
+				return
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = d.runOne(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	if fix {
+		fixes := &fixGate{}
+		for _, i := range fixOrder(deps, cyclic) {
+			check := d.checks[i]
+			if waiting[i] {
+				if r := failedDependency(deps[i], results); r != nil {
+					results[i] = d.skip(check, r)
+					continue
+				}
+				results[i] = d.runOne(ctx, check)
+			}
+			results[i] = d.fixOne(ctx, check, results[i], fixes)
 		}
-		report.Add(result)
 	}
 
+	report := NewReport()
+	for _, result := range results {
+		report.Add(result)
+	}
 	return report
 }
 
-// Fix runs all checks with auto-fix enabled where possible.
-// It first runs the check, then if it fails and can be fixed, attempts the fix.
-func (d *Doctor) Fix(ctx *CheckContext) *Report {
-	report := NewReport()
+// failedDependency returns the result of a dependency that failed or was
+// skipped, or nil when every dependency passed.
+func failedDependency(deps []int, results []*CheckResult) *CheckResult {
+	for _, dep := range deps {
+		if r := results[dep]; r.Status == StatusError || r.Status == StatusSkipped {
+			return r
+		}
+	}
+	return nil
+}
 
-	for _, check := range d.checks {
-		result := check.Run(ctx)
-		if result.Name == "" {
-			result.Name = check.Name()
-		}
-		// Set category from check if available
-		if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-			result.Category = cg.Category()
-		}
-
-		// Attempt fix if check failed and is fixable
-		if result.Status != StatusOK && check.CanFix() {
-			err := check.Fix(ctx)
-			if err == nil {
-				// Re-run check to verify fix worked
-				result = check.Run(ctx)
-				if result.Name == "" {
-					result.Name = check.Name()
-				}
-				// Set category again after re-run
-				if cg, ok := check.(categoryGetter); ok && result.Category == "" {
-					result.Category = cg.Category()
-				}
-				// Update message to indicate fix was applied
-				if result.Status == StatusOK {
-					result.Message = result.Message + " (fixed)"
-				}
-			} else {
-				// Fix failed, add error to details
-				result.Details = append(result.Details, "Fix failed: "+err.Error())
+// skip returns the result of a check skipped because dep failed.
+func (d *Doctor) skip(check Check, dep *CheckResult) *CheckResult {
+	return d.annotate(check, &CheckResult{
+		Status:  StatusSkipped,
+		Message: fmt.Sprintf("skipped: depends on %s", dep.Name),
+	})
+}
+
+// fixOrder lists the checks that can be fixed, in registration order with
+// each check's dependencies before it.
+func fixOrder(deps [][]int, cyclic []bool) []int {
+	var order []int
+	visited := make([]bool, len(deps))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] || cyclic[i] {
+			return
+		}
+		visited[i] = true
+		for _, dep := range deps[i] {
+			visit(dep)
+		}
+		order = append(order, i)
+	}
+	for i := range deps {
+		visit(i)
+	}
+	return order
+}
+
+// fixGate stops fixing once a fix times out: that fix is still running, so
+// any further fix could race with it.
+type fixGate struct {
+	stuck string // Check whose fix timed out
+}
+
+// timeoutFor returns the timeout for a check's run and fix.
+func (d *Doctor) timeoutFor(check Check) time.Duration {
+	if tg, ok := check.(timeoutGetter); ok && tg.Timeout() > 0 {
+		return tg.Timeout()
+	}
+	return d.timeout
+}
+
+// runOne runs a single check.
+func (d *Doctor) runOne(ctx *CheckContext, check Check) *CheckResult {
+	start := time.Now()
+	result := d.annotate(check, runCheck(check, ctx, d.timeoutFor(check)))
+	result.Duration = time.Since(start)
+	return result
+}
+
+// fixOne fixes a failed check and re-runs it, or in a dry run plans the
+// fix. The result's duration includes the fix.
+func (d *Doctor) fixOne(ctx *CheckContext, check Check, result *CheckResult, fixes *fixGate) *CheckResult {
+	if result.Status == StatusOK || result.TimedOut || !check.CanFix() {
+		return result
+	}
+	timeout := d.timeoutFor(check)
+	start := time.Now().Add(-result.Duration)
+
+	if d.dryRun {
+		result.Plan = d.planFix(ctx, check, timeout)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	notes, err := d.gatedFix(ctx, check, timeout, fixes)
+	if err == nil {
+		// Re-run check to verify fix worked
+		result = d.annotate(check, runCheck(check, ctx, timeout))
+		// Update message to indicate fix was applied
+		if result.Status == StatusOK {
+			result.Message = result.Message + " (fixed)"
+		}
+		result.Details = append(result.Details, notes...)
+	} else {
+		// Fix failed, add error to details
+		result.Details = append(result.Details, "Fix failed: "+err.Error())
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// gatedFix applies a check's fix unless an earlier fix timed out, and
+// poisons the journal when this one does.
+func (d *Doctor) gatedFix(ctx *CheckContext, check Check, timeout time.Duration, fixes *fixGate) ([]string, error) {
+	if fixes.stuck != "" {
+		return nil, fmt.Errorf("not attempted: the fix for %s timed out and may still be running", fixes.stuck)
+	}
+	notes, err := d.applyFix(ctx, check, timeout)
+	if errors.Is(err, errFixTimedOut) {
+		fixes.stuck = check.Name()
+		if d.journal != nil {
+			d.journal.Poison(fmt.Sprintf("fix for %s timed out and may have kept running", check.Name()))
+		}
+	}
+	return notes, err
+}
+
+// applyFix fixes a check, through its plan when it has one so the changes
+// are journaled.
+func (d *Doctor) applyFix(ctx *CheckContext, check Check, timeout time.Duration) ([]string, error) {
+	planner, ok := check.(Planner)
+	if !ok {
+		err := withTimeout(func() error { return check.Fix(ctx) }, timeout)
+		if d.journal != nil {
+			d.journal.RecordUnplanned(check.Name(), err)
+		}
+		return nil, err
+	}
+
+	var notes []string
+	err := withTimeout(func() error {
+		plan, err := planner.PlanFix(ctx)
+		if err != nil {
+			return err
+		}
+		plan.Check = check.Name()
+		notes = plan.Notes
+		return plan.Apply(d.journal)
+	}, timeout)
+	if err != nil {
+		return nil, err // notes may still be written by a timed-out fix
+	}
+	return notes, nil
+}
+
+// planFix returns the plan a check's fix would apply. Checks without
+// plans get a placeholder plan noting that the fix can't be previewed.
+func (d *Doctor) planFix(ctx *CheckContext, check Check, timeout time.Duration) *Plan {
+	plan := &Plan{Check: check.Name()}
+	planner, ok := check.(Planner)
+	if !ok {
+		plan.Ops = append(plan.Ops, &Operation{Kind: OpUnplanned, Description: "fix can't be previewed"})
+		return plan
+	}
+
+	err := withTimeout(func() error {
+		p, err := planner.PlanFix(ctx)
+		if err == nil && p != nil {
+			plan.Ops, plan.Notes = p.Ops, p.Notes
+		}
+		return err
+	}, timeout)
+	if err != nil {
+		plan.Ops = []*Operation{{Kind: OpUnplanned, Description: "planning fix failed: " + err.Error()}}
+	}
+	return plan
+}
+
+// annotate fills in the name and category a check left unset.
+func (d *Doctor) annotate(check Check, result *CheckResult) *CheckResult {
+	// Ensure check name is populated
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	// Set category from check if available
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
+	return result
+}
+
+// resolveDependencies maps each check's declared dependencies to check
+// indices and finds checks caught in a dependency cycle. Dependencies on
+// checks that aren't registered (e.g. rig checks without --rig) are ignored.
+func (d *Doctor) resolveDependencies() ([][]int, []bool) {
+	index := make(map[string]int, len(d.checks))
+	for i, check := range d.checks {
+		index[check.Name()] = i
+	}
+
+	deps := make([][]int, len(d.checks))
+	for i, check := range d.checks {
+		dg, ok := check.(dependencyGetter)
+		if !ok {
+			continue
+		}
+		for _, name := range dg.DependsOn() {
+			if j, ok := index[name]; ok && j != i {
+				deps[i] = append(deps[i], j)
 			}
 		}
+	}
 
-		report.Add(result)
+	// Kahn's algorithm: whatever can't be ordered is in (or behind) a cycle
+	pending := make([]int, len(d.checks))
+	dependents := make([][]int, len(d.checks))
+	for i, ds := range deps {
+		pending[i] = len(ds)
+		for _, j := range ds {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	var queue []int
+	for i, n := range pending {
+		if n == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, k := range dependents[i] {
+			if pending[k]--; pending[k] == 0 {
+				queue = append(queue, k)
+			}
+		}
+	}
+	cyclic := make([]bool, len(d.checks))
+	for i, n := range pending {
+		cyclic[i] = n > 0
+	}
+	return deps, cyclic
+}
+
+// runCheck runs a check with a timeout, converting panics into errors so
+// one broken check can't take down the whole run. A timed-out check keeps
+// running in the background; its result is discarded.
+func runCheck(check Check, ctx *CheckContext, timeout time.Duration) *CheckResult {
+	ch := make(chan *CheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- &CheckResult{Status: StatusError, Message: fmt.Sprintf("check panicked: %v", r)}
+			}
+		}()
+		result := check.Run(ctx)
+		if result == nil {
+			result = &CheckResult{Status: StatusError, Message: "check returned no result"}
+		}
+		ch <- result
+	}()
+
+	if timeout <= 0 {
+		return <-ch
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-ch:
+		return result
+	case <-timer.C:
+		return &CheckResult{
+			Status:   StatusError,
+			Message:  fmt.Sprintf("timed out after %s", timeout),
+			FixHint:  "Re-run with a longer --timeout, or check for a hung bd, git or tmux process",
+			TimedOut: true,
+		}
 	}
+}
 
-	return report
+// withTimeout runs fn (a fix) with a timeout, converting panics into errors.
+func withTimeout(fn func() error, timeout time.Duration) error {
+	ch := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				ch <- fmt.Errorf("fix panicked: %v", r)
+			}
+		}()
+		ch <- fn()
+	}()
+
+	if timeout <= 0 {
+		return <-ch
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-ch:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %s", errFixTimedOut, timeout)
+	}
 }
 
 // BaseCheck provides a base implementation for checks that don't support auto-fix.
@@ -101,7 +527,9 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 type BaseCheck struct {
 	CheckName        string
 	CheckDescription string
-	CheckCategory    string // Category for grouping (e.g., CategoryCore)
+	CheckCategory    string        // Category for grouping (e.g., CategoryCore)
+	CheckDependsOn   []string      // Checks that must pass first (by name)
+	CheckTimeout     time.Duration // Overrides the doctor's default timeout if set
 }
 
 // Category returns the check's category for grouping in output.
@@ -109,6 +537,16 @@ func (b *BaseCheck) Category() string {
 	return b.CheckCategory
 }
 
+// DependsOn returns the names of checks that must pass before this one runs.
+func (b *BaseCheck) DependsOn() []string {
+	return b.CheckDependsOn
+}
+
+// Timeout returns the check's own timeout, or zero for the doctor's default.
+func (b *BaseCheck) Timeout() time.Duration {
+	return b.CheckTimeout
+}
+
 // Name returns the check name.
 func (b *BaseCheck) Name() string {
 	return b.CheckName
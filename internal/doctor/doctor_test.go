@@ -2,7 +2,12 @@ package doctor
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // mockCheck is a test check that can be configured to return any status.
@@ -362,3 +367,256 @@ func TestFixableCheck(t *testing.T) {
 		t.Error("FixableCheck.CanFix() should return true")
 	}
 }
+
+// funcCheck runs an arbitrary function, for scheduling tests.
+type funcCheck struct {
+	BaseCheck
+	run func() *CheckResult
+}
+
+func newFuncCheck(name string, deps []string, run func() *CheckResult) *funcCheck {
+	return &funcCheck{
+		BaseCheck: BaseCheck{CheckName: name, CheckDependsOn: deps},
+		run:       run,
+	}
+}
+
+func (f *funcCheck) Run(ctx *CheckContext) *CheckResult {
+	return f.run()
+}
+
+func TestDoctor_RunDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, status CheckStatus) func() *CheckResult {
+		return func() *CheckResult {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return &CheckResult{Status: status}
+		}
+	}
+
+	d := NewDoctor()
+	// Registered before their dependencies to prove ordering comes from deps
+	d.Register(newFuncCheck("rig", []string{"beads"}, record("rig", StatusOK)))
+	d.Register(newFuncCheck("rig-child", []string{"rig"}, record("rig-child", StatusOK)))
+	d.Register(newFuncCheck("beads", nil, record("beads", StatusError)))
+	d.Register(newFuncCheck("independent", []string{"not-registered"}, record("independent", StatusOK)))
+
+	report := d.Run(&CheckContext{TownRoot: "/tmp"})
+
+	want := map[string]CheckStatus{
+		"rig":         StatusSkipped,
+		"rig-child":   StatusSkipped,
+		"beads":       StatusError,
+		"independent": StatusOK,
+	}
+	for i, name := range []string{"rig", "rig-child", "beads", "independent"} {
+		got := report.Checks[i]
+		if got.Name != name {
+			t.Errorf("result %d = %s, want registration order (%s)", i, got.Name, name)
+		}
+		if got.Status != want[name] {
+			t.Errorf("%s status = %v, want %v", name, got.Status, want[name])
+		}
+	}
+	if report.Summary.Skipped != 2 {
+		t.Errorf("Summary.Skipped = %d, want 2", report.Summary.Skipped)
+	}
+	for _, name := range order {
+		if name == "rig" || name == "rig-child" {
+			t.Errorf("%s ran despite failed dependency", name)
+		}
+	}
+}
+
+func TestDoctor_RunCycle(t *testing.T) {
+	ok := func() *CheckResult { return &CheckResult{Status: StatusOK} }
+	d := NewDoctor()
+	d.Register(newFuncCheck("a", []string{"b"}, ok))
+	d.Register(newFuncCheck("b", []string{"a"}, ok))
+	d.Register(newFuncCheck("c", []string{"a"}, ok))
+	d.Register(newFuncCheck("d", nil, ok))
+
+	report := d.Run(&CheckContext{TownRoot: "/tmp"})
+	want := []CheckStatus{StatusError, StatusError, StatusError, StatusOK}
+	for i, status := range want {
+		if report.Checks[i].Status != status {
+			t.Errorf("%s status = %v, want %v", report.Checks[i].Name, report.Checks[i].Status, status)
+		}
+	}
+}
+
+func TestDoctor_RunTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	d := NewDoctor()
+	d.SetTimeout(50 * time.Millisecond)
+	d.Register(newFuncCheck("hung", nil, func() *CheckResult {
+		<-release
+		return &CheckResult{Status: StatusOK}
+	}))
+	d.Register(newFuncCheck("after-hung", []string{"hung"}, func() *CheckResult {
+		return &CheckResult{Status: StatusOK}
+	}))
+	d.Register(newFuncCheck("panics", nil, func() *CheckResult {
+		panic("boom")
+	}))
+
+	report := d.Run(&CheckContext{TownRoot: "/tmp"})
+	if r := report.Checks[0]; r.Status != StatusError || !r.TimedOut {
+		t.Errorf("hung check = %+v, want timed-out error", r)
+	}
+	if r := report.Checks[1]; r.Status != StatusSkipped {
+		t.Errorf("dependent of hung check = %v, want skipped", r.Status)
+	}
+	if r := report.Checks[2]; r.Status != StatusError || !strings.Contains(r.Message, "panicked") {
+		t.Errorf("panicking check = %+v, want error", r)
+	}
+}
+
+// hungFixCheck fails and has a fix that never returns.
+type hungFixCheck struct {
+	BaseCheck
+	started *int32
+	release chan struct{}
+}
+
+func (c *hungFixCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{Status: StatusError}
+}
+
+func (c *hungFixCheck) CanFix() bool { return true }
+
+func (c *hungFixCheck) Fix(ctx *CheckContext) error {
+	atomic.AddInt32(c.started, 1)
+	<-c.release
+	return nil
+}
+
+func TestDoctor_FixTimeoutStopsFixing(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	var started int32
+
+	journal := NewJournal(t.TempDir())
+	d := NewDoctor()
+	d.SetTimeout(50 * time.Millisecond)
+	d.SetJournal(journal)
+	for _, name := range []string{"a", "b"} {
+		d.Register(&hungFixCheck{BaseCheck: BaseCheck{CheckName: name}, started: &started, release: release})
+	}
+
+	report := d.Fix(&CheckContext{TownRoot: "/tmp"})
+	if n := atomic.LoadInt32(&started); n != 1 {
+		t.Errorf("%d fixes started, want 1: none may start while a timed-out fix runs", n)
+	}
+	var skipped int
+	for _, r := range report.Checks {
+		for _, detail := range r.Details {
+			if strings.Contains(detail, "not attempted") {
+				skipped++
+			}
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("checks = %+v, want one fix not attempted", report.Checks)
+	}
+	if journal.Poisoned == "" {
+		t.Error("journal not poisoned by the timed-out fix")
+	}
+}
+
+// orderedFixCheck fails until fixed and records fixes in a shared log.
+type orderedFixCheck struct {
+	BaseCheck
+	fixed bool
+	log   *[]string
+}
+
+func (c *orderedFixCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.fixed {
+		return &CheckResult{Status: StatusOK}
+	}
+	return &CheckResult{Status: StatusError}
+}
+
+func (c *orderedFixCheck) CanFix() bool { return true }
+
+func (c *orderedFixCheck) Fix(ctx *CheckContext) error {
+	*c.log = append(*c.log, c.CheckName)
+	c.fixed = true
+	return nil
+}
+
+func TestDoctor_FixOrder(t *testing.T) {
+	var log []string
+	check := func(name string, deps ...string) *orderedFixCheck {
+		return &orderedFixCheck{BaseCheck: BaseCheck{CheckName: name, CheckDependsOn: deps}, log: &log}
+	}
+
+	d := NewDoctor()
+	d.SetWorkers(4)
+	// rig is registered before its dependency: fixes still go dependency first
+	d.RegisterAll(check("rig", "beads"), check("beads"), check("hooks"), check("config"))
+
+	report := d.Fix(&CheckContext{TownRoot: "/tmp"})
+	if want := []string{"beads", "rig", "hooks", "config"}; fmt.Sprint(log) != fmt.Sprint(want) {
+		t.Errorf("fix order = %v, want %v", log, want)
+	}
+	for _, r := range report.Checks {
+		if r.Status != StatusOK {
+			t.Errorf("%s = %v (%s), want fixed", r.Name, r.Status, r.Message)
+		}
+	}
+}
+
+func TestDoctor_RunConcurrent(t *testing.T) {
+	const n = 4
+	var running, peak int32
+	slow := func() *CheckResult {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return &CheckResult{Status: StatusOK}
+	}
+
+	d := NewDoctor()
+	d.SetWorkers(2)
+	for i := 0; i < n; i++ {
+		d.Register(newFuncCheck(fmt.Sprintf("slow-%d", i), nil, slow))
+	}
+	d.Run(&CheckContext{TownRoot: "/tmp"})
+
+	if peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestDoctor_Select(t *testing.T) {
+	ok := func() *CheckResult { return &CheckResult{Status: StatusOK} }
+	d := NewDoctor()
+	d.Register(newFuncCheck("a", nil, ok))
+	d.Register(newFuncCheck("b", nil, ok))
+	d.Register(newFuncCheck("c", nil, ok))
+
+	if err := d.Select([]string{"c", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	checks := d.Checks()
+	if len(checks) != 2 || checks[0].Name() != "a" || checks[1].Name() != "c" {
+		t.Errorf("selected %v", checks)
+	}
+
+	if err := d.Select([]string{"a", "nope"}); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("expected unknown check error, got %v", err)
+	}
+}
@@ -0,0 +1,419 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// External checks are executables in a doctor.d/ directory, at the town
+// root, in a rig, or in a town or rig plugin:
+//
+//	<town>/doctor.d/
+//	<town>/<rig>/doctor.d/
+//	<town>/plugins/<plugin>/doctor.d/
+//	<town>/<rig>/plugins/<plugin>/doctor.d/
+//
+// Each is invoked with a JSON request on stdin and answers with JSON on
+// stdout. The action is also passed as GT_DOCTOR_ACTION, with GT_TOWN_ROOT
+// and GT_RIG, so simple shell checks can ignore stdin:
+//
+//	describe → {"name": "...", "description": "...", "category": "...",
+//	            "fixable": true, "timeout": "2m", "depends_on": ["..."]}
+//	run      → {"status": "ok|warning|error", "message": "...",
+//	            "details": ["..."], "fix_hint": "..."}
+//	fix      → {"error": "..."} on failure, or a non-zero exit
+//
+// Every describe field is optional; checks that don't answer describe are
+// named after their file and listed under CategoryExternal. Names in
+// depends_on are qualified like the check's own: a check in the same
+// doctor.d is named without its <rig>/ or <plugin>/ prefix. A check whose
+// dependencies name no known check fails.
+
+// ExternalProtocolVersion is sent in every request to an external check.
+const ExternalProtocolVersion = 1
+
+// externalDescribeTimeout bounds the describe call made during discovery.
+const externalDescribeTimeout = 5 * time.Second
+
+// externalRequest is written to an external check's stdin.
+type externalRequest struct {
+	Protocol int    `json:"protocol"`
+	Action   string `json:"action"` // describe, run or fix
+	TownRoot string `json:"town_root"`
+	Rig      string `json:"rig,omitempty"`
+	RigPath  string `json:"rig_path,omitempty"`
+	Verbose  bool   `json:"verbose,omitempty"`
+}
+
+// externalDescription is an external check's answer to describe.
+type externalDescription struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Fixable     bool     `json:"fixable"`
+	Timeout     string   `json:"timeout"`
+	DependsOn   []string `json:"depends_on"`
+}
+
+// externalResult is an external check's answer to run or fix.
+type externalResult struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details"`
+	FixHint string   `json:"fix_hint"`
+	Error   string   `json:"error"`
+}
+
+// ExternalCheck runs an executable from a doctor.d/ directory.
+type ExternalCheck struct {
+	BaseCheck
+	Path    string // Executable
+	Rig     string // Rig the check belongs to; empty for town checks
+	fixable bool
+
+	scope      string   // Prefix of the check's name: <rig>, <plugin> or <rig>/<plugin>
+	unresolved []string // Dependencies naming no known check
+}
+
+// CanFix reports whether the executable declared fix support.
+func (c *ExternalCheck) CanFix() bool {
+	return c.fixable
+}
+
+// Source returns where the check was discovered, for listings.
+func (c *ExternalCheck) Source() string {
+	return c.Path
+}
+
+// Run invokes the executable with the run action.
+func (c *ExternalCheck) Run(ctx *CheckContext) *CheckResult {
+	if len(c.unresolved) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "unknown dependency: " + strings.Join(c.unresolved, ", "),
+			FixHint: "depends_on must name a check listed by 'gt doctor --list'; see " + c.Path,
+		}
+	}
+	out, err := c.invoke(ctx, "run", c.timeout())
+	var res externalResult
+	if jsonErr := decodeExternal(out, &res); jsonErr != nil {
+		result := &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "invalid output: " + jsonErr.Error(),
+			FixHint: "External check must print a JSON result; see " + c.Path,
+		}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		return result
+	}
+
+	status, ok := parseExternalStatus(res.Status)
+	if !ok {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("invalid status %q", res.Status),
+			FixHint: "Status must be ok, warning or error",
+		}
+	}
+	// A failing exit with a well-formed result keeps the reported status,
+	// but can't claim success.
+	if err != nil && status == StatusOK {
+		status = StatusError
+		res.Details = append(res.Details, err.Error())
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: res.Message,
+		Details: res.Details,
+		FixHint: res.FixHint,
+	}
+}
+
+// Fix invokes the executable with the fix action.
+func (c *ExternalCheck) Fix(ctx *CheckContext) error {
+	if !c.fixable || len(c.unresolved) > 0 {
+		return ErrCannotFix
+	}
+	out, err := c.invoke(ctx, "fix", c.timeout())
+	var res externalResult
+	if decodeExternal(out, &res) == nil && res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return err
+}
+
+// timeout is the check's declared timeout, or the doctor default. The
+// process is killed when it expires rather than left running.
+func (c *ExternalCheck) timeout() time.Duration {
+	if c.CheckTimeout > 0 {
+		return c.CheckTimeout
+	}
+	return DefaultCheckTimeout
+}
+
+// invoke runs the executable for one action and returns its stdout.
+func (c *ExternalCheck) invoke(ctx *CheckContext, action string, timeout time.Duration) ([]byte, error) {
+	rig := c.Rig
+	if rig == "" {
+		rig = ctx.RigName
+	}
+	req := externalRequest{
+		Protocol: ExternalProtocolVersion,
+		Action:   action,
+		TownRoot: ctx.TownRoot,
+		Rig:      rig,
+		Verbose:  ctx.Verbose,
+	}
+	dir := ctx.TownRoot
+	if rig != "" {
+		req.RigPath = filepath.Join(ctx.TownRoot, rig)
+		dir = req.RigPath
+	}
+	return runExternal(c.Path, dir, req, timeout)
+}
+
+// runExternal executes an external check with a JSON request on stdin.
+func runExternal(path, dir string, req externalRequest, timeout time.Duration) ([]byte, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(execCtx, path) //nolint:gosec // G204: doctor.d executables are trusted town configuration
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"GT_DOCTOR_ACTION="+req.Action,
+		"GT_TOWN_ROOT="+req.TownRoot,
+		"GT_RIG="+req.Rig,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	killProcessTree(cmd)
+	cmd.WaitDelay = time.Second // Don't wait on pipes held by orphaned helpers
+
+	err = cmd.Run()
+	if execCtx.Err() == context.DeadlineExceeded {
+		return stdout.Bytes(), fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if msg := lastLine(stderr.String()); msg != "" {
+			return stdout.Bytes(), fmt.Errorf("%v: %s", err, msg)
+		}
+		return stdout.Bytes(), err
+	}
+	return stdout.Bytes(), nil
+}
+
+// decodeExternal parses an external check's JSON answer.
+func decodeExternal(out []byte, v interface{}) error {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return errors.New("no output")
+	}
+	return json.Unmarshal(out, v)
+}
+
+// parseExternalStatus maps a protocol status onto CheckStatus.
+func parseExternalStatus(s string) (CheckStatus, bool) {
+	switch strings.ToLower(s) {
+	case "ok", "pass":
+		return StatusOK, true
+	case "warning", "warn":
+		return StatusWarning, true
+	case "error", "fail":
+		return StatusError, true
+	}
+	return StatusOK, false
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// DiscoverExternalChecks finds external checks for the town and the given
+// rigs. Rig and plugin checks are named <rig>/<check> or <plugin>/<check>
+// so the same script in two rigs yields two distinct checks. known are the
+// checks already registered, which external checks may depend on.
+func DiscoverExternalChecks(townRoot string, rigs []string, known []Check) []Check {
+	var external []*ExternalCheck
+
+	external = append(external, scanDoctorDir(townRoot, filepath.Join(townRoot, "doctor.d"), "", "")...)
+	for _, plugin := range pluginDirs(filepath.Join(townRoot, "plugins")) {
+		external = append(external, scanDoctorDir(townRoot, filepath.Join(plugin, "doctor.d"), "", filepath.Base(plugin))...)
+	}
+
+	for _, rig := range rigs {
+		rigPath := filepath.Join(townRoot, rig)
+		external = append(external, scanDoctorDir(townRoot, filepath.Join(rigPath, "doctor.d"), rig, rig)...)
+		for _, plugin := range pluginDirs(filepath.Join(rigPath, "plugins")) {
+			external = append(external, scanDoctorDir(townRoot, filepath.Join(plugin, "doctor.d"), rig, rig+"/"+filepath.Base(plugin))...)
+		}
+	}
+	resolveExternalDependencies(external, known)
+
+	checks := make([]Check, len(external))
+	for i, c := range external {
+		checks[i] = c
+	}
+	return checks
+}
+
+// resolveExternalDependencies qualifies the depends_on names of external
+// checks. A name is looked up in the check's own scope first, then in each
+// enclosing one (gastown/lint/x, gastown/x, x), so a sibling wins over a
+// town or built-in check of the same name.
+func resolveExternalDependencies(external []*ExternalCheck, known []Check) {
+	names := make(map[string]bool, len(known)+len(external))
+	for _, c := range known {
+		names[c.Name()] = true
+	}
+	for _, c := range external {
+		names[c.Name()] = true
+	}
+
+	for _, c := range external {
+		var resolved []string
+		for _, dep := range c.CheckDependsOn {
+			name, ok := "", false
+			for scope := c.scope; ; scope = parentScope(scope) {
+				if name = qualify(scope, dep); names[name] {
+					ok = true
+					break
+				}
+				if scope == "" {
+					break
+				}
+			}
+			if ok {
+				resolved = append(resolved, name)
+			} else {
+				c.unresolved = append(c.unresolved, dep)
+			}
+		}
+		c.CheckDependsOn = resolved
+	}
+}
+
+// qualify prefixes a check name with a scope.
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "/" + name
+}
+
+// parentScope drops the last element of a scope: gastown/lint → gastown.
+func parentScope(scope string) string {
+	if i := strings.LastIndex(scope, "/"); i >= 0 {
+		return scope[:i]
+	}
+	return ""
+}
+
+// pluginDirs lists plugin directories under a plugins/ directory.
+func pluginDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	return dirs
+}
+
+// scanDoctorDir loads the executables in one doctor.d/ directory.
+func scanDoctorDir(townRoot, dir, rig, prefix string) []*ExternalCheck {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil // No doctor.d is fine
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var checks []*ExternalCheck
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Mode()&0111 == 0 {
+			continue // Not executable (README, data files)
+		}
+		checks = append(checks, loadExternalCheck(townRoot, filepath.Join(dir, e.Name()), rig, prefix))
+	}
+	return checks
+}
+
+// loadExternalCheck asks an executable to describe itself, falling back to
+// defaults derived from its file name.
+func loadExternalCheck(townRoot, path, rig, prefix string) *ExternalCheck {
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	check := &ExternalCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        name,
+			CheckDescription: "External check " + path,
+			CheckCategory:    CategoryExternal,
+		},
+		Path:  path,
+		Rig:   rig,
+		scope: prefix,
+	}
+
+	dir := townRoot
+	if rig != "" {
+		dir = filepath.Join(townRoot, rig)
+	}
+	out, _ := runExternal(path, dir, externalRequest{
+		Protocol: ExternalProtocolVersion,
+		Action:   "describe",
+		TownRoot: townRoot,
+		Rig:      rig,
+	}, externalDescribeTimeout)
+
+	var desc externalDescription
+	if decodeExternal(out, &desc) == nil {
+		if desc.Name != "" {
+			check.CheckName = desc.Name
+		}
+		if desc.Description != "" {
+			check.CheckDescription = desc.Description
+		}
+		if desc.Category != "" {
+			check.CheckCategory = desc.Category
+		}
+		if d, err := time.ParseDuration(desc.Timeout); err == nil && d > 0 {
+			check.CheckTimeout = d
+		}
+		check.CheckDependsOn = desc.DependsOn
+		check.fixable = desc.Fixable
+	}
+
+	if prefix != "" {
+		check.CheckName = prefix + "/" + check.CheckName
+	}
+	return check
+}
@@ -0,0 +1,141 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscoverExternalChecks(t *testing.T) {
+	town := t.TempDir()
+
+	// Describes itself, fixable; fix creates a marker the run action looks for
+	writeScript(t, filepath.Join(town, "doctor.d"), "env-example.sh", `
+case "$GT_DOCTOR_ACTION" in
+describe) echo '{"name":"env-example","description":"Check .env.example","category":"Configuration","fixable":true,"timeout":"5s"}' ;;
+run) if [ -f "$GT_TOWN_ROOT/fixed" ]; then echo '{"status":"ok","message":"keys present"}'; else echo '{"status":"warning","message":"missing keys","details":["FOO"]}'; fi ;;
+fix) touch "$GT_TOWN_ROOT/fixed" ;;
+esac
+`)
+	// Doesn't speak describe: named after its file
+	writeScript(t, filepath.Join(town, "gastown", "doctor.d"), "pre-commit", `
+[ "$GT_DOCTOR_ACTION" = run ] && echo "{\"status\":\"error\",\"message\":\"no hook in $GT_RIG\"}"
+exit 0
+`)
+	// Plugin-contributed check
+	writeScript(t, filepath.Join(town, "plugins", "lint", "doctor.d"), "config", `echo '{"status":"ok"}'`)
+	// Not executable: ignored
+	if err := os.WriteFile(filepath.Join(town, "doctor.d", "README.md"), []byte("docs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := DiscoverExternalChecks(town, []string{"gastown"}, nil)
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name())
+	}
+	want := []string{"env-example", "lint/config", "gastown/pre-commit"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("discovered %v, want %v", names, want)
+	}
+
+	env := checks[0].(*ExternalCheck)
+	if !env.CanFix() || env.Category() != CategoryConfig || env.Timeout() != 5*time.Second {
+		t.Errorf("described check = %+v", env)
+	}
+	rig := checks[2].(*ExternalCheck)
+	if rig.CanFix() || rig.Category() != CategoryExternal || rig.Rig != "gastown" {
+		t.Errorf("undescribed check = %+v", rig)
+	}
+
+	d := NewDoctor()
+	d.RegisterAll(checks...)
+	report := d.Fix(&CheckContext{TownRoot: town})
+
+	if r := report.Checks[0]; r.Status != StatusOK || !strings.HasSuffix(r.Message, "(fixed)") {
+		t.Errorf("env-example after fix = %+v", r)
+	}
+	if r := report.Checks[2]; r.Status != StatusError || r.Message != "no hook in gastown" {
+		t.Errorf("rig check = %+v", r)
+	}
+}
+
+func TestDiscoverExternalChecks_DependsOn(t *testing.T) {
+	town := t.TempDir()
+	describe := func(deps string) string {
+		return `[ "$GT_DOCTOR_ACTION" = describe ] && echo '{"depends_on":[` + deps + `]}'
+[ "$GT_DOCTOR_ACTION" = run ] && echo '{"status":"ok"}'
+exit 0
+`
+	}
+	writeScript(t, filepath.Join(town, "doctor.d"), "base", describe(""))
+	writeScript(t, filepath.Join(town, "gastown", "doctor.d"), "base", describe(""))
+	writeScript(t, filepath.Join(town, "gastown", "doctor.d"), "hook", describe(`"base","beads"`))
+	writeScript(t, filepath.Join(town, "gastown", "plugins", "lint", "doctor.d"), "config", describe(`"hook","lint/missing"`))
+
+	builtin := newFuncCheck("beads", nil, func() *CheckResult { return &CheckResult{Status: StatusOK} })
+	byName := make(map[string]*ExternalCheck)
+	for _, c := range DiscoverExternalChecks(town, []string{"gastown"}, []Check{builtin}) {
+		byName[c.Name()] = c.(*ExternalCheck)
+	}
+
+	// The sibling wins over the town check of the same name
+	if got := byName["gastown/hook"].DependsOn(); strings.Join(got, ",") != "gastown/base,beads" {
+		t.Errorf("gastown/hook depends on %v", got)
+	}
+	// An enclosing scope resolves what the plugin's own doesn't
+	config := byName["gastown/lint/config"]
+	if got := config.DependsOn(); strings.Join(got, ",") != "gastown/hook" {
+		t.Errorf("gastown/lint/config depends on %v", got)
+	}
+	result := config.Run(&CheckContext{TownRoot: town})
+	if result.Status != StatusError || !strings.Contains(result.Message, "lint/missing") {
+		t.Errorf("check with an unknown dependency = %+v", result)
+	}
+}
+
+func TestExternalCheck_BadOutput(t *testing.T) {
+	town := t.TempDir()
+	dir := filepath.Join(town, "doctor.d")
+
+	tests := []struct {
+		name    string
+		body    string
+		status  CheckStatus
+		message string
+	}{
+		{"garbage", `echo not json`, StatusError, "invalid output"},
+		{"bad-status", `[ "$GT_DOCTOR_ACTION" = run ] && echo '{"status":"great"}'; exit 0`, StatusError, "invalid status"},
+		{"crash", `echo "boom" >&2; exit 3`, StatusError, "boom"},
+		{"ok-but-failed", `[ "$GT_DOCTOR_ACTION" = run ] && echo '{"status":"ok"}'; [ "$GT_DOCTOR_ACTION" = run ] && exit 1; exit 0`, StatusError, ""},
+		{"slow", `case "$GT_DOCTOR_ACTION" in describe) echo '{"timeout":"100ms"}';; run) sleep 5;; esac`, StatusError, "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeScript(t, dir, tt.name, tt.body)
+			check := loadExternalCheck(town, path, "", "")
+			start := time.Now()
+			result := check.Run(&CheckContext{TownRoot: town})
+			if result.Status != tt.status || !strings.Contains(result.Message, tt.message) {
+				t.Errorf("result = %+v, want %v containing %q", result, tt.status, tt.message)
+			}
+			if time.Since(start) > 3*time.Second {
+				t.Errorf("check ran %s; timeout should have killed it", time.Since(start))
+			}
+		})
+	}
+}
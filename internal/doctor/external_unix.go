@@ -0,0 +1,17 @@
+//go:build !windows
+
+package doctor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessTree puts an external check in its own process group and kills
+// the whole group on timeout, so helpers it spawned (git, bd, sleep) die too.
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
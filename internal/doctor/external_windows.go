@@ -0,0 +1,9 @@
+//go:build windows
+
+package doctor
+
+import "os/exec"
+
+// killProcessTree is a no-op on Windows; the check process itself is still
+// killed on timeout.
+func killProcessTree(cmd *exec.Cmd) {}
@@ -0,0 +1,370 @@
+package doctor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// JournalEntry records one applied fix operation and the state it replaced.
+type JournalEntry struct {
+	Check       string      `json:"check"`
+	Kind        OpKind      `json:"kind"`
+	Path        string      `json:"path,omitempty"`
+	Key         string      `json:"key,omitempty"`
+	Value       string      `json:"value,omitempty"`
+	Target      string      `json:"target,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Existed     bool        `json:"existed,omitempty"`      // File existed / config key was set / dir was empty
+	Before      []byte      `json:"before,omitempty"`       // Previous file contents
+	BeforeValue string      `json:"before_value,omitempty"` // Previous config value
+	Mode        os.FileMode `json:"mode,omitempty"`         // Previous file mode
+	AfterSHA256 string      `json:"after_sha256,omitempty"` // Written contents, to detect later edits
+	Applied     bool        `json:"applied"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// Journal records the operations of one `gt doctor --fix` run so they can
+// be rolled back. Journals live under .runtime/doctor/journal/.
+type Journal struct {
+	RunID        string          `json:"run_id"`
+	StartedAt    time.Time       `json:"started_at"`
+	Entries      []*JournalEntry `json:"entries"`
+	RolledBackAt *time.Time      `json:"rolled_back_at,omitempty"`
+
+	// Poisoned says why operations may have run after the journal last
+	// recorded them (a fix that timed out kept running).
+	Poisoned string `json:"poisoned,omitempty"`
+
+	townRoot string
+	mu       sync.Mutex // A timed-out fix may still be recording
+}
+
+// JournalDir returns where fix journals are kept.
+func JournalDir(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "doctor", "journal")
+}
+
+// NewJournal starts a journal for a fix run. Nothing is written until the
+// first operation is recorded.
+func NewJournal(townRoot string) *Journal {
+	now := time.Now().UTC()
+	return &Journal{
+		RunID:     now.Format("20060102-150405") + "-" + uuid.NewString()[:4],
+		StartedAt: now,
+		townRoot:  townRoot,
+	}
+}
+
+// LoadJournal reads the journal for a run.
+func LoadJournal(townRoot, runID string) (*Journal, error) {
+	if runID == "" || strings.ContainsAny(runID, `/\`) {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	data, err := os.ReadFile(filepath.Join(JournalDir(townRoot), runID+".json")) //nolint:gosec // G304: run ID validated above
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no doctor run %q (see 'gt doctor rollback' for recent runs)", runID)
+		}
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	j := &Journal{townRoot: townRoot}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("parsing journal %s: %w", runID, err)
+	}
+	return j, nil
+}
+
+// ListJournals returns recorded fix runs, newest first.
+func ListJournals(townRoot string) ([]*Journal, error) {
+	entries, err := os.ReadDir(JournalDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var journals []*Journal
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		j, err := LoadJournal(townRoot, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue // Skip unreadable journals
+		}
+		journals = append(journals, j)
+	}
+	sort.Slice(journals, func(i, k int) bool { return journals[i].StartedAt.After(journals[k].StartedAt) })
+	return journals, nil
+}
+
+// Save writes the journal.
+func (j *Journal) Save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.save()
+}
+
+func (j *Journal) save() error {
+	dir := JournalDir(j.townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, j.RunID+".json"), data, 0600)
+}
+
+// Empty reports whether anything was recorded.
+func (j *Journal) Empty() bool {
+	if j == nil {
+		return true
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.Entries) == 0
+}
+
+// Poison records that a fix of the run timed out and may still be
+// changing things, and saves the journal.
+func (j *Journal) Poison(reason string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Poisoned = reason
+	_ = j.save()
+}
+
+// record captures an operation's prior state and saves the journal before
+// the operation runs.
+func (j *Journal) record(check string, op *Operation) (*JournalEntry, error) {
+	entry := &JournalEntry{
+		Check:       check,
+		Kind:        op.Kind,
+		Path:        op.Path,
+		Key:         op.Key,
+		Value:       op.Value,
+		Target:      op.Target,
+		Description: op.Description,
+	}
+
+	switch op.Kind {
+	case OpWriteFile, OpDeleteFile:
+		info, err := os.Stat(op.Path)
+		switch {
+		case err == nil:
+			before, err := os.ReadFile(op.Path)
+			if err != nil {
+				return nil, err
+			}
+			entry.Existed, entry.Before, entry.Mode = true, before, info.Mode().Perm()
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+		if op.Kind == OpWriteFile {
+			entry.AfterSHA256 = checksum(op.Content)
+		}
+	case OpGitConfig:
+		old, set, err := getGitConfig(op.Path, op.Key)
+		if err != nil {
+			return nil, err
+		}
+		entry.Existed, entry.BeforeValue = set, old
+	case OpRemoveDir:
+		entries, err := os.ReadDir(op.Path)
+		entry.Existed = err == nil && len(entries) == 0 // Will actually be removed
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Entries = append(j.Entries, entry)
+	return entry, j.save()
+}
+
+// finish records the outcome of a journaled operation and saves the journal.
+func (j *Journal) finish(entry *JournalEntry, err error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Applied = true
+	}
+	return j.save()
+}
+
+// RecordUnplanned notes that a check's fix ran without a plan, so the run's
+// journal shows it even though it can't be rolled back. Most fixes that
+// create beads, run bd, git or gt, or restart sessions are unplanned; see
+// Doctor.Unplanned.
+func (j *Journal) RecordUnplanned(check string, err error) {
+	entry := &JournalEntry{
+		Check:       check,
+		Kind:        OpUnplanned,
+		Description: "fix applied directly; changes were not recorded",
+		Applied:     err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Entries = append(j.Entries, entry)
+	_ = j.save()
+}
+
+// RollbackResult reports what a rollback did.
+type RollbackResult struct {
+	Restored []string // Operations undone
+	Skipped  []string // Operations left alone, with the reason
+}
+
+// Rollback undoes the run's applied operations in reverse order. Files
+// edited since the fix are left alone unless force is set. Bead updates,
+// session restarts and unplanned fixes can't be undone and are reported
+// as skipped. In a poisoned run, operations journaled without an outcome
+// may have run, and are undone too.
+func (j *Journal) Rollback(force, dryRun bool) (*RollbackResult, error) {
+	if j.RolledBackAt != nil && !force {
+		return nil, fmt.Errorf("run %s was already rolled back at %s", j.RunID, j.RolledBackAt.Local().Format(time.RFC3339))
+	}
+
+	result := &RollbackResult{}
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := j.Entries[i]
+		desc := e.describe(j.townRoot)
+		if !e.Applied && e.Error == "" && j.Poisoned == "" {
+			continue // Never ran
+		}
+		switch e.Kind {
+		case OpWriteFile, OpDeleteFile:
+			if !force {
+				if reason := e.modifiedSince(); reason != "" {
+					result.Skipped = append(result.Skipped, desc+": "+reason+" (use --force to restore anyway)")
+					continue
+				}
+			}
+			if !dryRun {
+				if err := e.restoreFile(); err != nil {
+					return result, fmt.Errorf("restoring %s: %w", e.Path, err)
+				}
+			}
+		case OpRemoveDir:
+			if !e.Existed {
+				continue // Wasn't removed
+			}
+			if !dryRun {
+				if err := os.MkdirAll(e.Path, 0755); err != nil {
+					return result, fmt.Errorf("recreating %s: %w", e.Path, err)
+				}
+			}
+		case OpGitConfig:
+			if !dryRun {
+				if err := setGitConfig(e.Path, e.Key, e.BeforeValue, e.Existed); err != nil && e.Existed {
+					return result, fmt.Errorf("restoring %s: %w", desc, err)
+				}
+			}
+		default:
+			result.Skipped = append(result.Skipped, desc+": cannot be undone")
+			continue
+		}
+		result.Restored = append(result.Restored, desc)
+	}
+
+	if !dryRun {
+		now := time.Now().UTC()
+		j.RolledBackAt = &now
+		if err := j.save(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// modifiedSince explains why a file no longer matches what the fix left,
+// or returns "" if it does.
+func (e *JournalEntry) modifiedSince() string {
+	current, err := os.ReadFile(e.Path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err.Error()
+	}
+	switch e.Kind {
+	case OpWriteFile:
+		if !exists {
+			return "deleted since the fix"
+		}
+		if e.AfterSHA256 != "" && checksum(current) != e.AfterSHA256 {
+			return "modified since the fix"
+		}
+	case OpDeleteFile:
+		if exists && !(e.Existed && bytes.Equal(current, e.Before)) {
+			return "recreated since the fix"
+		}
+	}
+	return ""
+}
+
+// restoreFile puts a file back as it was before the fix.
+func (e *JournalEntry) restoreFile() error {
+	if !e.Existed {
+		if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+		return err
+	}
+	mode := e.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	if err := os.WriteFile(e.Path, e.Before, mode); err != nil {
+		return err
+	}
+	return os.Chmod(e.Path, mode)
+}
+
+// describe summarizes an entry for rollback output.
+func (e *JournalEntry) describe(townRoot string) string {
+	switch e.Kind {
+	case OpWriteFile:
+		if !e.Existed {
+			return "remove created " + relPath(townRoot, e.Path)
+		}
+		return "restore " + relPath(townRoot, e.Path)
+	case OpDeleteFile:
+		return "recreate " + relPath(townRoot, e.Path)
+	case OpRemoveDir:
+		return "recreate directory " + relPath(townRoot, e.Path)
+	case OpGitConfig:
+		if !e.Existed {
+			return fmt.Sprintf("unset %s in %s", e.Key, relPath(townRoot, e.Path))
+		}
+		return fmt.Sprintf("reset %s=%s in %s", e.Key, e.BeforeValue, relPath(townRoot, e.Path))
+	case OpBeadUpdate:
+		return fmt.Sprintf("bead %s (%s)", e.Target, e.Description)
+	case OpRestartSession:
+		return fmt.Sprintf("session restart %s", e.Target)
+	}
+	return fmt.Sprintf("%s fix", e.Check)
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
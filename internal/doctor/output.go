@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// jsonReport is the JSON form of a Report.
+type jsonReport struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Healthy   bool        `json:"healthy"`
+	Summary   jsonSummary `json:"summary"`
+	Checks    []jsonCheck `json:"checks"`
+}
+
+type jsonSummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	Skipped  int `json:"skipped"`
+}
+
+type jsonCheck struct {
+	Name       string   `json:"name"`
+	Category   string   `json:"category,omitempty"`
+	Status     string   `json:"status"`
+	Message    string   `json:"message,omitempty"`
+	Details    []string `json:"details,omitempty"`
+	FixHint    string   `json:"fix_hint,omitempty"`
+	DurationMs int64    `json:"duration_ms"`
+	TimedOut   bool     `json:"timed_out,omitempty"`
+	FixPlan    string   `json:"fix_plan,omitempty"` // Unified diff (--fix --dry-run)
+}
+
+// WriteJSON writes the report as indented JSON.
+// Statuses are lowercase strings: ok, warning, error, skipped.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Timestamp: r.Timestamp,
+		Healthy:   r.IsHealthy(),
+		Summary: jsonSummary{
+			Total:    r.Summary.Total,
+			OK:       r.Summary.OK,
+			Warnings: r.Summary.Warnings,
+			Errors:   r.Summary.Errors,
+			Skipped:  r.Summary.Skipped,
+		},
+		Checks: make([]jsonCheck, 0, len(r.Checks)),
+	}
+	for _, c := range r.Checks {
+		var plan strings.Builder // Paths are absolute in JSON
+		if err := c.Plan.Diff(&plan, ""); err != nil {
+			return err
+		}
+		out.Checks = append(out.Checks, jsonCheck{
+			Name:       c.Name,
+			Category:   c.Category,
+			Status:     strings.ToLower(c.Status.String()),
+			Message:    c.Message,
+			Details:    c.Details,
+			FixHint:    c.FixHint,
+			DurationMs: c.Duration.Milliseconds(),
+			TimedOut:   c.TimedOut,
+			FixPlan:    plan.String(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// JUnit XML elements, as understood by common CI systems.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML with one test suite per
+// category. Errors are failures and skipped checks are skipped; warnings
+// pass, with the warning recorded in system-out.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	root := junitTestSuites{Name: "gt doctor"}
+	var total time.Duration
+
+	suites := make(map[string]*junitTestSuite)
+	durations := make(map[string]time.Duration)
+	var order []string
+	for _, c := range r.Checks {
+		category := c.Category
+		if category == "" {
+			category = "Other"
+		}
+		suite := suites[category]
+		if suite == nil {
+			suite = &junitTestSuite{Name: category, Timestamp: r.Timestamp.UTC().Format("2006-01-02T15:04:05")}
+			suites[category] = suite
+			order = append(order, category)
+		}
+
+		tc := junitTestCase{
+			Name:      c.Name,
+			ClassName: "doctor." + category,
+			Time:      junitSeconds(c.Duration),
+		}
+		body := strings.Join(c.Details, "\n")
+		if c.FixHint != "" {
+			body = strings.TrimSpace(body + "\nFix: " + c.FixHint)
+		}
+		switch {
+		case c.Status == StatusError:
+			tc.Failure = &junitMessage{Message: c.Message, Type: "error", Body: body}
+			suite.Failures++
+		case c.Status == StatusSkipped:
+			tc.Skipped = &junitMessage{Message: c.Message}
+			suite.Skipped++
+		case c.Status == StatusWarning:
+			tc.SystemOut = strings.TrimSpace("WARNING: " + c.Message + "\n" + body)
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+		durations[category] += c.Duration
+		total += c.Duration
+	}
+
+	for _, name := range order {
+		suite := suites[name]
+		suite.Time = junitSeconds(durations[name])
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+		root.Suites = append(root.Suites, *suite)
+	}
+	root.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	r := NewReport()
+	r.Add(&CheckResult{Name: "beads-database", Category: CategoryConfig, Status: StatusError, Message: "corrupt", FixHint: "bd doctor", Duration: 1500 * time.Millisecond})
+	r.Add(&CheckResult{Name: "rig-beads-exist", Category: CategoryRig, Status: StatusSkipped, Message: "skipped: depends on beads-database"})
+	r.Add(&CheckResult{Name: "themes", Category: CategoryConfig, Status: StatusWarning, Message: "stale theme"})
+	r.Add(&CheckResult{Name: "daemon", Status: StatusOK})
+	return r
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.Healthy || out.Summary.Errors != 1 || out.Summary.Skipped != 1 || out.Summary.Total != 4 {
+		t.Errorf("summary = %+v (healthy %v)", out.Summary, out.Healthy)
+	}
+	if out.Checks[0].Status != "error" || out.Checks[0].DurationMs != 1500 {
+		t.Errorf("first check = %+v", out.Checks[0])
+	}
+	if out.Checks[1].Status != "skipped" {
+		t.Errorf("second check status = %q, want skipped", out.Checks[1].Status)
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if out.Tests != 4 || out.Failures != 1 || out.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped", out.Tests, out.Failures, out.Skipped)
+	}
+
+	// Suites follow first appearance: Configuration, Rig, Other
+	if len(out.Suites) != 3 || out.Suites[0].Name != CategoryConfig || out.Suites[2].Name != "Other" {
+		t.Fatalf("suites = %+v", out.Suites)
+	}
+	config := out.Suites[0]
+	if config.Tests != 2 || config.Failures != 1 || config.Time != "1.500" {
+		t.Errorf("config suite = %+v", config)
+	}
+	if f := config.Cases[0].Failure; f == nil || f.Message != "corrupt" {
+		t.Errorf("failure = %+v", f)
+	}
+	if config.Cases[1].Failure != nil || config.Cases[1].SystemOut == "" {
+		t.Errorf("warning should pass with system-out, got %+v", config.Cases[1])
+	}
+	if out.Suites[1].Cases[0].Skipped == nil {
+		t.Error("skipped check should be marked skipped")
+	}
+}
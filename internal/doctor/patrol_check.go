@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -92,19 +93,15 @@ func (c *PatrolMoleculesExistCheck) Run(ctx *CheckContext) *CheckResult {
 
 // checkPatrolMolecules returns missing patrol molecule titles for a rig.
 func (c *PatrolMoleculesExistCheck) checkPatrolMolecules(rigPath string) []string {
-	// List molecules using bd
-	cmd := exec.Command("bd", "list", "--type=molecule")
-	cmd.Dir = rigPath
-	output, err := cmd.Output()
+	mols, err := beads.New(rigPath).List(beads.ListOptions{IssueType: "molecule", Priority: -1})
 	if err != nil {
 		return patrolMolecules // Can't check, assume all missing
 	}
 
-	outputStr := string(output)
 	var missing []string
-	for _, mol := range patrolMolecules {
-		if !strings.Contains(outputStr, mol) {
-			missing = append(missing, mol)
+	for _, title := range patrolMolecules {
+		if !slices.ContainsFunc(mols, func(m *beads.Issue) bool { return strings.Contains(m.Title, title) }) {
+			missing = append(missing, title)
 		}
 	}
 	return missing
@@ -116,14 +113,12 @@ func (c *PatrolMoleculesExistCheck) Fix(ctx *CheckContext) error {
 		rigPath := filepath.Join(ctx.TownRoot, rigName)
 		for _, mol := range missing {
 			desc := getPatrolMoleculeDesc(mol)
-			cmd := exec.Command("bd", "create", //nolint:gosec // G204: args are constructed internally
-				"--type=molecule",
-				"--title="+mol,
-				"--description="+desc,
-				"--priority=2",
-			)
-			cmd.Dir = rigPath
-			if err := cmd.Run(); err != nil {
+			if _, err := beads.New(rigPath).Create(beads.CreateOptions{
+				IssueType:   "molecule",
+				Title:       mol,
+				Description: desc,
+				Priority:    2,
+			}); err != nil {
 				return fmt.Errorf("creating %s in %s: %w", mol, rigName, err)
 			}
 		}
@@ -493,34 +488,42 @@ func (c *PatrolRolesHavePromptsCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
+// Fix copies the missing role prompt templates into each rig.
 func (c *PatrolRolesHavePromptsCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans writing each missing template from the embedded copy.
+func (c *PatrolRolesHavePromptsCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
 	allTemplates, err := templates.GetAllRoleTemplates()
 	if err != nil {
-		return fmt.Errorf("getting embedded templates: %w", err)
+		return nil, fmt.Errorf("getting embedded templates: %w", err)
+	}
+
+	rigNames := make([]string, 0, len(c.missingByRig))
+	for rigName := range c.missingByRig {
+		rigNames = append(rigNames, rigName)
 	}
+	sort.Strings(rigNames)
 
-	for rigName, missingFiles := range c.missingByRig {
+	plan := &Plan{}
+	for _, rigName := range rigNames {
 		mayorRig := filepath.Join(ctx.TownRoot, rigName, "mayor", "rig")
 		templatesDir := filepath.Join(mayorRig, "internal", "templates", "roles")
 
-		if err := os.MkdirAll(templatesDir, 0755); err != nil {
-			return fmt.Errorf("creating %s: %w", templatesDir, err)
-		}
-
-		for _, roleFile := range missingFiles {
+		for _, roleFile := range c.missingByRig[rigName] {
 			content, ok := allTemplates[roleFile]
 			if !ok {
 				continue
 			}
-
-			destPath := filepath.Join(templatesDir, roleFile)
-			if err := os.WriteFile(destPath, content, 0644); err != nil {
-				return fmt.Errorf("writing %s in %s: %w", roleFile, rigName, err)
-			}
+			plan.WriteFile(filepath.Join(templatesDir, roleFile), content, 0644)
 		}
 	}
-
-	return nil
+	return plan, nil
 }
 
 // discoverRigs finds all registered rigs.
@@ -539,3 +539,30 @@ func TestPatrolHooksWiredCheck_FixPreservesExisting(t *testing.T) {
 		t.Error("existing custom patrol was overwritten")
 	}
 }
+
+func TestPatrolRolesHavePromptsCheck_PlanFix(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupRigConfig(t, tmpDir, []string{"myproject"})
+
+	check := NewPatrolRolesHavePromptsCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+	check.Run(ctx)
+
+	plan, err := check.PlanFix(ctx)
+	if err != nil {
+		t.Fatalf("PlanFix() error = %v", err)
+	}
+	if len(plan.Ops) != len(requiredRolePrompts) {
+		t.Fatalf("PlanFix() planned %d ops, want %d", len(plan.Ops), len(requiredRolePrompts))
+	}
+	for _, op := range plan.Ops {
+		if op.Kind != OpWriteFile {
+			t.Errorf("op kind = %s, want %s", op.Kind, OpWriteFile)
+		}
+	}
+
+	templatesDir := filepath.Join(tmpDir, "myproject", "mayor", "rig", "internal", "templates", "roles")
+	if _, err := os.Stat(templatesDir); !os.IsNotExist(err) {
+		t.Errorf("PlanFix() touched %s", templatesDir)
+	}
+}
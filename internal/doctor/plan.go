@@ -0,0 +1,268 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// OpKind identifies what a planned fix operation changes.
+type OpKind string
+
+const (
+	OpWriteFile      OpKind = "write_file"      // Create or replace a file
+	OpDeleteFile     OpKind = "delete_file"     // Remove a file
+	OpRemoveDir      OpKind = "remove_dir"      // Remove a directory if empty
+	OpGitConfig      OpKind = "git_config"      // Set a git config key in a repo
+	OpBeadUpdate     OpKind = "bead_update"     // Modify a bead (not reversible)
+	OpRestartSession OpKind = "restart_session" // Restart an agent session (not reversible)
+	OpUnplanned      OpKind = "unplanned_fix"   // A check's Fix() run without a plan
+)
+
+// Operation is one change in a fix plan.
+type Operation struct {
+	Kind        OpKind
+	Path        string      // File, or repository for OpGitConfig
+	Content     []byte      // New contents (OpWriteFile)
+	Mode        os.FileMode // Mode for new files (OpWriteFile)
+	Key         string      // Config key (OpGitConfig)
+	Value       string      // Config value (OpGitConfig)
+	Target      string      // Bead ID or session name
+	Description string      // Human-readable summary
+
+	apply func() error // Performs OpBeadUpdate and OpRestartSession
+}
+
+// Reversible reports whether rollback can undo the operation.
+func (op *Operation) Reversible() bool {
+	switch op.Kind {
+	case OpWriteFile, OpDeleteFile, OpRemoveDir, OpGitConfig:
+		return true
+	}
+	return false
+}
+
+// Plan is the set of changes a check's fix would make. Checks that
+// implement Planner describe their fix as a plan so it can be previewed
+// with --dry-run and journaled for rollback.
+type Plan struct {
+	Check string
+	Ops   []*Operation
+	Notes []string // Things the fix deliberately leaves alone, or follow-ups
+}
+
+// Planner is implemented by checks whose fix can be expressed as a plan.
+// The doctor prefers PlanFix over Fix when both exist.
+type Planner interface {
+	PlanFix(ctx *CheckContext) (*Plan, error)
+}
+
+// WriteFile plans creating or replacing path with content.
+func (p *Plan) WriteFile(path string, content []byte, mode os.FileMode) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpWriteFile, Path: path, Content: content, Mode: mode})
+}
+
+// DeleteFile plans removing path.
+func (p *Plan) DeleteFile(path string) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpDeleteFile, Path: path})
+}
+
+// RemoveEmptyDir plans removing dir if nothing is left in it. A directory
+// that still has entries is left alone without error.
+func (p *Plan) RemoveEmptyDir(dir string) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpRemoveDir, Path: dir})
+}
+
+// Note adds a message shown with the plan and after it is applied.
+func (p *Plan) Note(format string, args ...interface{}) {
+	p.Notes = append(p.Notes, fmt.Sprintf(format, args...))
+}
+
+// SetGitConfig plans `git -C repo config key value`.
+func (p *Plan) SetGitConfig(repo, key, value string) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpGitConfig, Path: repo, Key: key, Value: value})
+}
+
+// UpdateBead plans a bead change performed by apply.
+func (p *Plan) UpdateBead(id, description string, apply func() error) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpBeadUpdate, Target: id, Description: description, apply: apply})
+}
+
+// RestartSession plans restarting an agent session via apply.
+func (p *Plan) RestartSession(session, description string, apply func() error) {
+	p.Ops = append(p.Ops, &Operation{Kind: OpRestartSession, Target: session, Description: description, apply: apply})
+}
+
+// Empty reports whether the plan changes nothing.
+func (p *Plan) Empty() bool {
+	return p == nil || len(p.Ops) == 0
+}
+
+// Apply performs the plan in order, stopping at the first failure. With a
+// journal, each operation's prior state is recorded before it runs so the
+// plan can be rolled back, including after a partial failure.
+func (p *Plan) Apply(j *Journal) error {
+	if p == nil {
+		return nil
+	}
+	for _, op := range p.Ops {
+		var entry *JournalEntry
+		if j != nil {
+			var err error
+			if entry, err = j.record(p.Check, op); err != nil {
+				return fmt.Errorf("journaling %s: %w", op.summary(), err)
+			}
+		}
+
+		err := op.execute()
+		if j != nil {
+			if saveErr := j.finish(entry, err); saveErr != nil && err == nil {
+				err = fmt.Errorf("saving journal: %w", saveErr)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op.summary(), err)
+		}
+	}
+	return nil
+}
+
+// execute performs a single operation.
+func (op *Operation) execute() error {
+	switch op.Kind {
+	case OpWriteFile:
+		if err := os.MkdirAll(filepath.Dir(op.Path), 0755); err != nil {
+			return err
+		}
+		mode := op.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		return os.WriteFile(op.Path, op.Content, mode)
+	case OpDeleteFile:
+		if err := os.Remove(op.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case OpRemoveDir:
+		if entries, err := os.ReadDir(op.Path); err != nil || len(entries) > 0 {
+			return nil // Missing or not empty
+		}
+		return os.Remove(op.Path)
+	case OpGitConfig:
+		return setGitConfig(op.Path, op.Key, op.Value, true)
+	case OpBeadUpdate, OpRestartSession:
+		if op.apply == nil {
+			return errors.New("nothing to apply")
+		}
+		return op.apply()
+	}
+	return fmt.Errorf("unknown operation %q", op.Kind)
+}
+
+// summary is a one-line description of the operation.
+func (op *Operation) summary() string {
+	switch op.Kind {
+	case OpWriteFile:
+		return "write " + op.Path
+	case OpDeleteFile:
+		return "delete " + op.Path
+	case OpRemoveDir:
+		return "remove empty directory " + op.Path
+	case OpGitConfig:
+		return fmt.Sprintf("git -C %s config %s %s", op.Path, op.Key, op.Value)
+	case OpBeadUpdate:
+		return fmt.Sprintf("bead %s: %s", op.Target, op.Description)
+	case OpRestartSession:
+		return fmt.Sprintf("restart session %s: %s", op.Target, op.Description)
+	}
+	return op.Description
+}
+
+// getGitConfig returns a repo-local git config value and whether it is set.
+func getGitConfig(repo, key string) (string, bool, error) {
+	cmd := exec.Command("git", "-C", repo, "config", "--local", "--get", key)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", false, nil // Key not set
+		}
+		return "", false, err
+	}
+	return strings.TrimRight(string(out), "\n"), true, nil
+}
+
+// setGitConfig sets (or, with set false, unsets) a repo-local config key.
+func setGitConfig(repo, key, value string, set bool) error {
+	args := []string{"-C", repo, "config", "--local", key, value}
+	if !set {
+		args = []string{"-C", repo, "config", "--local", "--unset", key}
+	}
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Diff writes the plan as a unified diff. Paths are shown relative to
+// townRoot. Changes that aren't file edits are shown as comments.
+func (p *Plan) Diff(w io.Writer, townRoot string) error {
+	if p == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, op := range p.Ops {
+		rel := relPath(townRoot, op.Path)
+		switch op.Kind {
+		case OpWriteFile:
+			before, err := os.ReadFile(op.Path)
+			from := "a/" + rel
+			if os.IsNotExist(err) {
+				from = "/dev/null"
+			} else if err != nil {
+				return err
+			}
+			writeUnifiedDiff(&buf, from, "b/"+rel, string(before), string(op.Content))
+		case OpDeleteFile:
+			before, err := os.ReadFile(op.Path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			writeUnifiedDiff(&buf, "a/"+rel, "/dev/null", string(before), "")
+		case OpGitConfig:
+			old, set, err := getGitConfig(op.Path, op.Key)
+			if err != nil {
+				return fmt.Errorf("reading git config in %s: %w", op.Path, err)
+			}
+			name := fmt.Sprintf("%s [git config %s]", rel, op.Key)
+			before := ""
+			if set {
+				before = old + "\n"
+			}
+			writeUnifiedDiff(&buf, "a/"+name, "b/"+name, before, op.Value+"\n")
+		case OpRemoveDir:
+			fmt.Fprintf(&buf, "# remove %s if empty\n", rel)
+		default:
+			fmt.Fprintf(&buf, "# %s\n", op.summary())
+		}
+	}
+	for _, note := range p.Notes {
+		fmt.Fprintf(&buf, "# note: %s\n", note)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// relPath shows path relative to base when it is inside it.
+func relPath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,419 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteUnifiedDiff(t *testing.T) {
+	var buf bytes.Buffer
+	writeUnifiedDiff(&buf, "a/f", "b/f", "one\ntwo\nthree\n", "one\n2\nthree\nfour\n")
+	want := `--- a/f
++++ b/f
+@@ -1,3 +1,4 @@
+ one
+-two
++2
+ three
++four
+`
+	if buf.String() != want {
+		t.Errorf("diff =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeUnifiedDiff(&buf, "a/f", "b/f", "same\n", "same\n")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for equal texts, got %q", buf.String())
+	}
+}
+
+func TestWriteUnifiedDiff_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, "line")
+		b = append(b, "line")
+	}
+	b[1] = "first"
+	b[18] = "second"
+
+	var buf bytes.Buffer
+	writeUnifiedDiff(&buf, "a", "b", strings.Join(a, "\n")+"\n", strings.Join(b, "\n")+"\n")
+	if n := strings.Count(buf.String(), "@@ -"); n != 2 {
+		t.Errorf("expected 2 hunks, got %d:\n%s", n, buf.String())
+	}
+}
+
+func TestPlanDiff(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing.txt")
+	if err := os.WriteFile(existing, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &Plan{}
+	plan.WriteFile(existing, []byte("new\n"), 0644)
+	plan.WriteFile(filepath.Join(root, "created.txt"), []byte("hello\n"), 0644)
+	plan.UpdateBead("gt-1", "add label", func() error { return nil })
+	plan.Note("left something alone")
+
+	var buf bytes.Buffer
+	if err := plan.Diff(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"--- a/existing.txt\n+++ b/existing.txt\n",
+		"-old\n+new\n",
+		"--- /dev/null\n+++ b/created.txt\n",
+		"# bead gt-1: add label",
+		"# note: left something alone",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff missing %q:\n%s", want, out)
+		}
+	}
+
+	// Diff must not change anything
+	if data, _ := os.ReadFile(existing); string(data) != "old\n" {
+		t.Errorf("Diff modified file: %q", data)
+	}
+}
+
+func TestPlanApplyAndRollback(t *testing.T) {
+	root := t.TempDir()
+	modified := filepath.Join(root, "modified.txt")
+	deleted := filepath.Join(root, ".claude", "settings.json")
+	created := filepath.Join(root, "sub", "created.txt")
+	if err := os.WriteFile(modified, []byte("before\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(deleted), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(deleted, []byte("stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	beadUpdated := false
+	plan := &Plan{Check: "test"}
+	plan.WriteFile(modified, []byte("after\n"), 0644)
+	plan.DeleteFile(deleted)
+	plan.RemoveEmptyDir(filepath.Dir(deleted))
+	plan.WriteFile(created, []byte("new\n"), 0644)
+	plan.UpdateBead("gt-1", "add label", func() error { beadUpdated = true; return nil })
+
+	j := NewJournal(root)
+	if err := plan.Apply(j); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !beadUpdated {
+		t.Error("bead update not applied")
+	}
+	if _, err := os.Stat(filepath.Dir(deleted)); !os.IsNotExist(err) {
+		t.Error("expected empty .claude dir to be removed")
+	}
+
+	loaded, err := LoadJournal(root, j.RunID)
+	if err != nil {
+		t.Fatalf("LoadJournal: %v", err)
+	}
+	if len(loaded.Entries) != 5 {
+		t.Fatalf("expected 5 journal entries, got %d", len(loaded.Entries))
+	}
+
+	result, err := loaded.Rollback(false, false)
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if len(result.Restored) != 4 || len(result.Skipped) != 1 {
+		t.Errorf("restored %v, skipped %v", result.Restored, result.Skipped)
+	}
+
+	if data, _ := os.ReadFile(modified); string(data) != "before\n" {
+		t.Errorf("modified.txt = %q, want restored", data)
+	}
+	if info, _ := os.Stat(modified); info.Mode().Perm() != 0600 {
+		t.Errorf("modified.txt mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(deleted); string(data) != "stale\n" {
+		t.Errorf("deleted file = %q, want recreated", data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("created file should be removed by rollback")
+	}
+
+	if _, err := loaded.Rollback(false, false); err == nil {
+		t.Error("expected second rollback to fail without --force")
+	}
+}
+
+func TestRollbackSkipsFilesModifiedSinceFix(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+	if err := os.WriteFile(path, []byte("before\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &Plan{Check: "test"}
+	plan.WriteFile(path, []byte("fixed\n"), 0644)
+	j := NewJournal(root)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone edits the file after the fix
+	if err := os.WriteFile(path, []byte("user edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := j.Rollback(false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0], "modified since the fix") {
+		t.Errorf("expected modified file to be skipped, got %v", result.Skipped)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "user edit\n" {
+		t.Errorf("file = %q, want user edit kept", data)
+	}
+
+	// --force restores anyway
+	if _, err := j.Rollback(true, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "before\n" {
+		t.Errorf("file = %q, want restored with force", data)
+	}
+}
+
+func TestRollbackDryRun(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+
+	plan := &Plan{Check: "test"}
+	plan.WriteFile(path, []byte("created\n"), 0644)
+	j := NewJournal(root)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := j.Rollback(false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Restored) != 1 {
+		t.Errorf("expected 1 planned restore, got %v", result.Restored)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("dry-run rollback should not remove the file")
+	}
+	if j.RolledBackAt != nil {
+		t.Error("dry-run rollback should not mark the run rolled back")
+	}
+}
+
+func TestPlanApplyPartialFailure(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+
+	plan := &Plan{Check: "test"}
+	plan.WriteFile(path, []byte("written\n"), 0644)
+	plan.UpdateBead("gt-1", "fails", func() error { return errors.New("bd failed") })
+	plan.WriteFile(filepath.Join(root, "never.txt"), []byte("x"), 0644)
+
+	j := NewJournal(root)
+	if err := plan.Apply(j); err == nil {
+		t.Fatal("expected Apply to fail")
+	}
+	if len(j.Entries) != 2 {
+		t.Fatalf("expected 2 journal entries, got %d", len(j.Entries))
+	}
+	if j.Entries[1].Error == "" {
+		t.Error("expected failed operation's error to be journaled")
+	}
+
+	// The write before the failure can still be rolled back
+	if _, err := j.Rollback(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected partial fix to be rolled back")
+	}
+}
+
+func TestRollbackPoisonedRun(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+
+	// A fix that timed out journaled its write but the run ended before
+	// recording whether it happened
+	j := NewJournal(root)
+	if _, err := j.record("slow", &Operation{Kind: OpWriteFile, Path: path, Content: []byte("late\n")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("late\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	j.Poison("fix for slow timed out and may have kept running")
+
+	loaded, err := LoadJournal(root, j.RunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Poisoned == "" {
+		t.Fatal("poisoning not saved")
+	}
+	if _, err := loaded.Rollback(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("write of the timed-out fix not rolled back")
+	}
+}
+
+func TestPlanGitConfigRollback(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	plan := &Plan{Check: "test"}
+	plan.SetGitConfig(repo, "core.hooksPath", ".githooks")
+
+	var buf bytes.Buffer
+	if err := plan.Diff(&buf, repo); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "+.githooks") {
+		t.Errorf("expected config diff, got:\n%s", buf.String())
+	}
+
+	j := NewJournal(repo)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+	if v, set, _ := getGitConfig(repo, "core.hooksPath"); !set || v != ".githooks" {
+		t.Fatalf("core.hooksPath = %q (set=%v)", v, set)
+	}
+
+	if _, err := j.Rollback(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, set, _ := getGitConfig(repo, "core.hooksPath"); set {
+		t.Error("expected core.hooksPath to be unset after rollback")
+	}
+}
+
+func TestListJournals(t *testing.T) {
+	root := t.TempDir()
+	journals, err := ListJournals(root)
+	if err != nil || len(journals) != 0 {
+		t.Fatalf("expected no journals, got %v, %v", journals, err)
+	}
+
+	j := NewJournal(root)
+	plan := &Plan{Check: "test"}
+	plan.WriteFile(filepath.Join(root, "f"), []byte("x"), 0644)
+	if err := plan.Apply(j); err != nil {
+		t.Fatal(err)
+	}
+
+	journals, err = ListJournals(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 1 || journals[0].RunID != j.RunID {
+		t.Errorf("ListJournals = %v", journals)
+	}
+
+	if _, err := LoadJournal(root, "../escape"); err == nil {
+		t.Error("expected invalid run ID to be rejected")
+	}
+}
+
+// fileCheck wants a file to contain "fixed" and plans writing it.
+type fileCheck struct {
+	FixableCheck
+	path string
+}
+
+func (c *fileCheck) Run(ctx *CheckContext) *CheckResult {
+	if data, _ := os.ReadFile(c.path); string(data) == "fixed\n" {
+		return &CheckResult{Status: StatusOK, Message: "ok"}
+	}
+	return &CheckResult{Status: StatusError, Message: "not fixed"}
+}
+
+func (c *fileCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
+	plan.WriteFile(c.path, []byte("fixed\n"), 0644)
+	return plan, nil
+}
+
+func (c *fileCheck) Fix(ctx *CheckContext) error {
+	plan, _ := c.PlanFix(ctx)
+	return plan.Apply(nil)
+}
+
+func TestDoctorFix_DryRunAndJournal(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "target.txt")
+	if err := os.WriteFile(path, []byte("broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check := &fileCheck{FixableCheck: FixableCheck{BaseCheck: BaseCheck{CheckName: "file"}}, path: path}
+	ctx := &CheckContext{TownRoot: root}
+
+	d := NewDoctor()
+	d.Register(check)
+	d.SetDryRun(true)
+	report := d.Fix(ctx)
+	if report.Checks[0].Plan.Empty() {
+		t.Fatal("expected dry run to report a plan")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "broken\n" {
+		t.Fatalf("dry run modified file: %q", data)
+	}
+
+	d = NewDoctor()
+	d.Register(check)
+	j := NewJournal(root)
+	d.SetJournal(j)
+	report = d.Fix(ctx)
+	if report.Checks[0].Status != StatusOK {
+		t.Fatalf("expected fix to succeed, got %v: %s", report.Checks[0].Status, report.Checks[0].Message)
+	}
+	if len(j.Entries) != 1 || j.Entries[0].Check != "file" {
+		t.Fatalf("expected one journaled op for check, got %+v", j.Entries)
+	}
+
+	if _, err := j.Rollback(false, false); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "broken\n" {
+		t.Errorf("rollback left %q", data)
+	}
+}
+
+func TestDoctorUnplanned(t *testing.T) {
+	d := NewDoctor()
+	d.Register(&fileCheck{FixableCheck: FixableCheck{BaseCheck: BaseCheck{CheckName: "planned"}}})
+	unplanned := newMockCheck("unplanned", StatusError)
+	unplanned.fixable = true
+	d.Register(unplanned)
+	d.Register(newMockCheck("unfixable", StatusError))
+
+	got := d.Unplanned()
+	if len(got) != 1 || got[0] != "unplanned" {
+		t.Errorf("Unplanned() = %v, want [unplanned]", got)
+	}
+}
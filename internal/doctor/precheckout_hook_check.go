@@ -140,23 +140,18 @@ func (c *PreCheckoutHookCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix installs the pre-checkout hook.
 func (c *PreCheckoutHookCheck) Fix(ctx *CheckContext) error {
-	if !c.hookMissing {
-		return nil
-	}
-
-	hooksDir := filepath.Join(ctx.TownRoot, ".git", "hooks")
-
-	// Ensure hooks directory exists
-	if err := os.MkdirAll(hooksDir, 0755); err != nil {
-		return fmt.Errorf("creating hooks directory: %w", err)
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
 	}
+	return plan.Apply(nil)
+}
 
-	hookPath := filepath.Join(hooksDir, "pre-checkout")
-
-	// Install the hook
-	if err := os.WriteFile(hookPath, []byte(preCheckoutHookScript), 0755); err != nil {
-		return fmt.Errorf("writing hook: %w", err)
+// PlanFix plans writing the hook script.
+func (c *PreCheckoutHookCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
+	if c.hookMissing {
+		plan.WriteFile(filepath.Join(ctx.TownRoot, ".git", "hooks", "pre-checkout"), []byte(preCheckoutHookScript), 0755)
 	}
-
-	return nil
+	return plan, nil
 }
@@ -84,7 +84,7 @@ func (c *RepoFingerprintCheck) Run(ctx *CheckContext) *CheckResult {
 // checkBeadsDir checks a single beads directory for repo fingerprint using bd doctor.
 func (c *RepoFingerprintCheck) checkBeadsDir(workDir, location string) *CheckResult {
 	// Run bd doctor --json to get fingerprint status
-	cmd := exec.Command("bd", "doctor", "--json")
+	cmd := beads.Command("doctor", "--json")
 	cmd.Dir = workDir
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -162,13 +162,8 @@ func (c *RepoFingerprintCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	// Run bd migrate --update-repo-id
-	cmd := exec.Command("bd", "migrate", "--update-repo-id")
-	cmd.Dir = filepath.Dir(c.beadsDir) // Parent of .beads directory
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("bd migrate --update-repo-id failed: %v: %s", err, stderr.String())
+	if err := beads.NewWithBeadsDir(filepath.Dir(c.beadsDir), c.beadsDir).UpdateRepoID(); err != nil {
+		return fmt.Errorf("bd migrate --update-repo-id failed: %w", err)
 	}
 
 	// Restart daemon if running
@@ -2,13 +2,13 @@ package doctor
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 )
@@ -101,6 +101,7 @@ func NewGitExcludeConfiguredCheck() *GitExcludeConfiguredCheck {
 				CheckName:        "git-exclude-configured",
 				CheckDescription: "Check .git/info/exclude has Gas Town directories",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -198,43 +199,38 @@ func (c *GitExcludeConfiguredCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix appends missing entries to .git/info/exclude.
 func (c *GitExcludeConfiguredCheck) Fix(ctx *CheckContext) error {
-	if len(c.missingEntries) == 0 {
-		return nil
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
 	}
+	return plan.Apply(nil)
+}
 
-	// Ensure info directory exists
-	infoDir := filepath.Dir(c.excludePath)
-	if err := os.MkdirAll(infoDir, 0755); err != nil {
-		return fmt.Errorf("failed to create info directory: %w", err)
+// PlanFix plans appending missing entries to .git/info/exclude.
+func (c *GitExcludeConfiguredCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
+	if len(c.missingEntries) == 0 {
+		return plan, nil
 	}
 
-	// Append missing entries
-	f, err := os.OpenFile(c.excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open exclude file: %w", err)
+	existing, err := os.ReadFile(c.excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read exclude file: %w", err)
 	}
-	defer f.Close()
 
-	// Add a header comment if file is empty or new
-	info, _ := f.Stat()
-	if info.Size() == 0 {
-		if _, err := f.WriteString("# Gas Town directories\n"); err != nil {
-			return err
-		}
-	} else {
-		// Add newline before new entries
-		if _, err := f.WriteString("\n# Gas Town directories\n"); err != nil {
-			return err
-		}
+	var b strings.Builder
+	b.Write(existing)
+	// Add a header comment, separated from existing content
+	if len(existing) > 0 {
+		b.WriteString("\n")
 	}
-
+	b.WriteString("# Gas Town directories\n")
 	for _, entry := range c.missingEntries {
-		if _, err := f.WriteString(entry + "\n"); err != nil {
-			return err
-		}
+		b.WriteString(entry + "\n")
 	}
 
-	return nil
+	plan.WriteFile(c.excludePath, []byte(b.String()), 0600)
+	return plan, nil
 }
 
 // HooksPathConfiguredCheck verifies all clones have core.hooksPath set to .githooks.
@@ -252,6 +248,7 @@ func NewHooksPathConfiguredCheck() *HooksPathConfiguredCheck {
 				CheckName:        "hooks-path-configured",
 				CheckDescription: "Check core.hooksPath is set for all clones",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -349,13 +346,20 @@ func (c *HooksPathConfiguredCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix configures core.hooksPath for all unconfigured clones.
 func (c *HooksPathConfiguredCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans setting core.hooksPath in each unconfigured clone.
+func (c *HooksPathConfiguredCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	for _, clonePath := range c.unconfiguredClones {
-		cmd := exec.Command("git", "-C", clonePath, "config", "core.hooksPath", ".githooks")
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to configure hooks for %s: %w", clonePath, err)
-		}
+		plan.SetGitConfig(clonePath, "core.hooksPath", ".githooks")
 	}
-	return nil
+	return plan, nil
 }
 
 // WitnessExistsCheck verifies the witness directory structure exists.
@@ -832,9 +836,8 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Check if bd command works
-	cmd := exec.Command("bd", "stats", "--json")
-	cmd.Dir = c.rigPath
-	if err := cmd.Run(); err != nil {
+	bd := beads.New(c.rigPath)
+	if _, err := bd.Stats(); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -845,22 +848,26 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// Check sync status
-	cmd = exec.Command("bd", "sync", "--status")
-	cmd.Dir = c.rigPath
-	output, err := cmd.CombinedOutput()
+	status, err := bd.GetSyncStatus()
 	c.needsSync = false
-	if err != nil {
+	var detail string
+	switch {
+	case err != nil:
 		// sync --status may exit non-zero if out of sync
-		outputStr := string(output)
-		if strings.Contains(outputStr, "out of sync") || strings.Contains(outputStr, "behind") {
-			c.needsSync = true
-			return &CheckResult{
-				Name:    c.Name(),
-				Status:  StatusWarning,
-				Message: "Beads out of sync",
-				Details: []string{strings.TrimSpace(outputStr)},
-				FixHint: "Run 'gt doctor --fix' or 'bd sync' to synchronize",
-			}
+		if msg := err.Error(); strings.Contains(msg, "out of sync") || strings.Contains(msg, "behind") {
+			detail = msg
+		}
+	case status.Behind > 0:
+		detail = fmt.Sprintf("%d commits behind %s", status.Behind, status.Branch)
+	}
+	if detail != "" {
+		c.needsSync = true
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Beads out of sync",
+			Details: []string{detail},
+			FixHint: "Run 'gt doctor --fix' or 'bd sync' to synchronize",
 		}
 	}
 
@@ -877,11 +884,8 @@ func (c *BeadsConfigValidCheck) Fix(ctx *CheckContext) error {
 		return nil
 	}
 
-	cmd := exec.Command("bd", "sync")
-	cmd.Dir = c.rigPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("bd sync failed: %s", string(output))
+	if err := beads.New(c.rigPath).Sync(); err != nil {
+		return fmt.Errorf("bd sync failed: %w", err)
 	}
 
 	return nil
@@ -1043,9 +1047,8 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		}
 
 		// Run bd init with the configured prefix
-		cmd := exec.Command("bd", "init", "--prefix", prefix)
-		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		bd := beads.New(rigPath)
+		if err := bd.Init(prefix); err != nil {
 			// bd might not be installed - create minimal config.yaml
 			configPath := filepath.Join(rigBeadsDir, "config.yaml")
 			configContent := fmt.Sprintf("prefix: %s\n", prefix)
@@ -1054,11 +1057,8 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 			}
 			// Continue - minimal config created
 		} else {
-			_ = output // bd init succeeded
 			// Configure custom types for Gas Town (beads v0.46.0+)
-			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-			configCmd.Dir = rigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_ = bd.ConfigSet("types.custom", constants.BeadsCustomTypes) // Ignore errors - older beads don't need this
 		}
 		return nil
 	}
@@ -1115,6 +1115,7 @@ func NewBareRepoRefspecCheck() *BareRepoRefspecCheck {
 				CheckName:        "bare-repo-refspec",
 				CheckDescription: "Verify bare repo has correct refspec for worktrees",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -1180,22 +1181,26 @@ func (c *BareRepoRefspecCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix sets the correct refspec on the bare repo.
 func (c *BareRepoRefspecCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans setting the bare repo's fetch refspec.
+func (c *BareRepoRefspecCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	if ctx.RigName == "" {
-		return nil
+		return plan, nil
 	}
 
 	bareRepoPath := filepath.Join(ctx.RigPath(), ".repo.git")
 	if _, err := os.Stat(bareRepoPath); os.IsNotExist(err) {
-		return nil // No bare repo to fix
+		return plan, nil // No bare repo to fix
 	}
-
-	cmd := exec.Command("git", "-C", bareRepoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("setting refspec: %s", strings.TrimSpace(stderr.String()))
-	}
-	return nil
+	plan.SetGitConfig(bareRepoPath, "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	return plan, nil
 }
 
 // RigChecks returns all rig-level health checks.
@@ -103,21 +103,30 @@ func (c *RigRoutesJSONLCheck) Run(ctx *CheckContext) *CheckResult {
 // The SQLite database (beads.db) is the source of truth - bd will auto-export
 // to issues.jsonl on next run.
 func (c *RigRoutesJSONLCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans deleting the rig-level routes.jsonl files.
+func (c *RigRoutesJSONLCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
+
 	// Re-run check to populate affectedRigs if needed
 	if len(c.affectedRigs) == 0 {
 		result := c.Run(ctx)
 		if result.Status == StatusOK {
-			return nil // Nothing to fix
+			return plan, nil // Nothing to fix
 		}
 	}
 
 	for _, info := range c.affectedRigs {
-		if err := os.Remove(info.routesPath); err != nil {
-			return fmt.Errorf("deleting %s: %w", info.routesPath, err)
-		}
+		plan.DeleteFile(info.routesPath)
 	}
 
-	return nil
+	return plan, nil
 }
 
 // findRigDirectories finds all rig directories in the town.
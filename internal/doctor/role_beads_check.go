@@ -2,8 +2,6 @@ package doctor
 
 import (
 	"fmt"
-	"os/exec"
-	"strings"
 
 	"github.com/steveyegge/gastown/internal/beads"
 )
@@ -30,6 +28,7 @@ func NewRoleBeadsCheck() *RoleBeadsCheck {
 				CheckName:        "role-beads-exist",
 				CheckDescription: "Verify role definition beads exist",
 				CheckCategory:    CategoryConfig,
+				CheckDependsOn:   []string{"beads-database"},
 			},
 		},
 	}
@@ -99,16 +98,13 @@ func (c *RoleBeadsCheck) Fix(ctx *CheckContext) error {
 			continue // Shouldn't happen
 		}
 
-		// Create role bead using bd create --type=role
-		cmd := exec.Command("bd", "create",
-			"--type=role",
-			"--id="+role.ID,
-			"--title="+role.Title,
-			"--description="+role.Desc,
-		)
-		cmd.Dir = ctx.TownRoot
-		if output, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("creating %s: %s", role.ID, strings.TrimSpace(string(output)))
+		if _, err := beads.New(ctx.TownRoot).CreateWithID(role.ID, beads.CreateOptions{
+			IssueType:   "role",
+			Title:       role.Title,
+			Description: role.Desc,
+			Priority:    -1,
+		}); err != nil {
+			return fmt.Errorf("creating %s: %w", role.ID, err)
 		}
 	}
 
@@ -231,11 +231,21 @@ func (c *RoutesCheck) checkRoutesValid(ctx *CheckContext, routes []beads.Route)
 
 // Fix attempts to add missing routing entries.
 func (c *RoutesCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans rewriting routes.jsonl with the missing routing entries.
+func (c *RoutesCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	beadsDir := filepath.Join(ctx.TownRoot, ".beads")
 
 	// Ensure .beads directory exists
 	if _, err := os.Stat(beadsDir); os.IsNotExist(err) {
-		return fmt.Errorf(".beads directory does not exist; run 'bd init' first")
+		return nil, fmt.Errorf(".beads directory does not exist; run 'bd init' first")
 	}
 
 	// Load existing routes
@@ -273,9 +283,9 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	if err != nil {
 		// No rigs config - just write town root route if we added it
 		if modified {
-			return beads.WriteRoutes(beadsDir, routes)
+			return plan, planRoutes(plan, beadsDir, routes)
 		}
-		return nil
+		return plan, nil
 	}
 
 	// Add missing routes for each rig
@@ -301,8 +311,18 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	}
 
 	if modified {
-		return beads.WriteRoutes(beadsDir, routes)
+		return plan, planRoutes(plan, beadsDir, routes)
 	}
 
+	return plan, nil
+}
+
+// planRoutes plans writing routes to a beads directory's routes.jsonl.
+func planRoutes(plan *Plan, beadsDir string, routes []beads.Route) error {
+	data, err := beads.FormatRoutes(routes)
+	if err != nil {
+		return err
+	}
+	plan.WriteFile(filepath.Join(beadsDir, beads.RoutesFileName), data, 0644)
 	return nil
 }
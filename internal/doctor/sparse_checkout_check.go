@@ -27,6 +27,7 @@ func NewSparseCheckoutCheck() *SparseCheckoutCheck {
 				CheckName:        "sparse-checkout",
 				CheckDescription: "Verify sparse checkout excludes Claude context files (.claude/, CLAUDE.md, etc.)",
 				CheckCategory:    CategoryRig,
+				CheckDependsOn:   []string{"rig-is-git-repo"},
 			},
 		},
 	}
@@ -19,6 +19,7 @@ const (
 	CategoryConfig        = "Configuration"
 	CategoryCleanup       = "Cleanup"
 	CategoryHooks         = "Hooks"
+	CategoryExternal      = "External" // doctor.d checks that don't declare a category
 )
 
 // CategoryOrder defines the display order for categories
@@ -30,6 +31,7 @@ var CategoryOrder = []string{
 	CategoryConfig,
 	CategoryCleanup,
 	CategoryHooks,
+	CategoryExternal,
 }
 
 // CheckStatus represents the result status of a health check.
@@ -42,6 +44,9 @@ const (
 	StatusWarning
 	// StatusError indicates a critical problem.
 	StatusError
+	// StatusSkipped indicates the check didn't run because a check it
+	// depends on failed.
+	StatusSkipped
 )
 
 // String returns a human-readable status.
@@ -53,6 +58,8 @@ func (s CheckStatus) String() string {
 		return "Warning"
 	case StatusError:
 		return "Error"
+	case StatusSkipped:
+		return "Skipped"
 	default:
 		return "Unknown"
 	}
@@ -77,12 +84,15 @@ func (ctx *CheckContext) RigPath() string {
 
 // CheckResult represents the outcome of a health check.
 type CheckResult struct {
-	Name     string      // Check name
-	Status   CheckStatus // Result status
-	Message  string      // Primary result message
-	Details  []string    // Additional information
-	FixHint  string      // Suggestion if not auto-fixable
-	Category string      // Category for grouping (e.g., CategoryCore)
+	Name     string        // Check name
+	Status   CheckStatus   // Result status
+	Message  string        // Primary result message
+	Details  []string      // Additional information
+	FixHint  string        // Suggestion if not auto-fixable
+	Category string        // Category for grouping (e.g., CategoryCore)
+	Duration time.Duration // How long the check (and any fix) took
+	TimedOut bool          // Check was abandoned after its timeout
+	Plan     *Plan         // Fix that would be applied (dry-run only)
 }
 
 // Check defines the interface for a health check.
@@ -110,6 +120,7 @@ type ReportSummary struct {
 	OK       int
 	Warnings int
 	Errors   int
+	Skipped  int
 }
 
 // Report contains all check results and a summary.
@@ -139,6 +150,8 @@ func (r *Report) Add(result *CheckResult) {
 		r.Summary.Warnings++
 	case StatusError:
 		r.Summary.Errors++
+	case StatusSkipped:
+		r.Summary.Skipped++
 	}
 }
 
@@ -189,7 +202,26 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		// Print each check in this category
 		for _, check := range checks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status == StatusWarning || check.Status == StatusError {
+				warnings = append(warnings, check)
+			}
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	// Print categories declared by external checks, alphabetically
+	var extra []string
+	for category := range checksByCategory {
+		if category != "Other" && !slices.Contains(CategoryOrder, category) {
+			extra = append(extra, category)
+		}
+	}
+	slices.Sort(extra)
+	for _, category := range extra {
+		_, _ = fmt.Fprintln(w, ui.RenderCategory(category))
+		for _, check := range checksByCategory[category] {
+			r.printCheck(w, check, verbose)
+			if check.Status == StatusWarning || check.Status == StatusError {
 				warnings = append(warnings, check)
 			}
 		}
@@ -201,7 +233,7 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		_, _ = fmt.Fprintln(w, ui.RenderCategory("Other"))
 		for _, check := range otherChecks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status == StatusWarning || check.Status == StatusError {
 				warnings = append(warnings, check)
 			}
 		}
@@ -226,6 +258,8 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 		statusIcon = ui.RenderWarnIcon()
 	case StatusError:
 		statusIcon = ui.RenderFailIcon()
+	case StatusSkipped:
+		statusIcon = ui.RenderMuted(ui.IconSkip)
 	}
 
 	// Print check line: icon + name + muted message
@@ -233,10 +267,13 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 	if check.Message != "" {
 		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(" "+check.Message))
 	}
+	if verbose && check.Duration >= time.Second {
+		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(fmt.Sprintf(" [%s]", check.Duration.Round(100*time.Millisecond))))
+	}
 	_, _ = fmt.Fprintln(w)
 
 	// Print details in verbose mode or for non-OK results (with tree connector)
-	if len(check.Details) > 0 && (verbose || check.Status != StatusOK) {
+	if len(check.Details) > 0 && (verbose || check.Status == StatusWarning || check.Status == StatusError) {
 		for _, detail := range check.Details {
 			_, _ = fmt.Fprintf(w, "     %s%s\n", ui.MutedStyle.Render(ui.TreeLast), ui.RenderMuted(detail))
 		}
@@ -250,6 +287,9 @@ func (r *Report) printSummary(w io.Writer) {
 		ui.RenderWarnIcon(), r.Summary.Warnings,
 		ui.RenderFailIcon(), r.Summary.Errors,
 	)
+	if r.Summary.Skipped > 0 {
+		summary += fmt.Sprintf("  %s %d skipped", ui.RenderMuted(ui.IconSkip), r.Summary.Skipped)
+	}
 	_, _ = fmt.Fprintln(w, summary)
 }
 
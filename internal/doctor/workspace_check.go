@@ -55,6 +55,7 @@ func NewTownConfigValidCheck() *TownConfigValidCheck {
 			CheckName:        "town-config-valid",
 			CheckDescription: "Check that mayor/town.json is valid with required fields",
 			CheckCategory:    CategoryCore,
+			CheckDependsOn:   []string{"town-config-exists"},
 		},
 	}
 }
@@ -160,6 +161,15 @@ func (c *RigsRegistryExistsCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix creates an empty rigs.json file.
 func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans writing an empty rigs.json file.
+func (c *RigsRegistryExistsCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
 
 	emptyRigs := struct {
@@ -172,10 +182,12 @@ func (c *RigsRegistryExistsCheck) Fix(ctx *CheckContext) error {
 
 	data, err := json.MarshalIndent(emptyRigs, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling empty rigs.json: %w", err)
+		return nil, fmt.Errorf("marshaling empty rigs.json: %w", err)
 	}
 
-	return os.WriteFile(rigsPath, data, 0644)
+	plan := &Plan{}
+	plan.WriteFile(rigsPath, data, 0644)
+	return plan, nil
 }
 
 // RigsRegistryValidCheck verifies mayor/rigs.json is valid and rigs exist.
@@ -192,6 +204,7 @@ func NewRigsRegistryValidCheck() *RigsRegistryValidCheck {
 				CheckName:        "rigs-registry-valid",
 				CheckDescription: "Check that registered rigs exist on disk",
 				CheckCategory:    CategoryCore,
+				CheckDependsOn:   []string{"rigs-registry-exists"},
 			},
 		},
 	}
@@ -283,20 +296,30 @@ func (c *RigsRegistryValidCheck) Run(ctx *CheckContext) *CheckResult {
 
 // Fix removes missing rigs from the registry.
 func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
+	plan, err := c.PlanFix(ctx)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(nil)
+}
+
+// PlanFix plans rewriting rigs.json without the missing rigs.
+func (c *RigsRegistryValidCheck) PlanFix(ctx *CheckContext) (*Plan, error) {
+	plan := &Plan{}
 	if len(c.missingRigs) == 0 {
-		return nil
+		return plan, nil
 	}
 
 	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
 
 	data, err := os.ReadFile(rigsPath)
 	if err != nil {
-		return fmt.Errorf("reading rigs.json: %w", err)
+		return nil, fmt.Errorf("reading rigs.json: %w", err)
 	}
 
 	var config rigsConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("parsing rigs.json: %w", err)
+		return nil, fmt.Errorf("parsing rigs.json: %w", err)
 	}
 
 	// Remove missing rigs
@@ -307,10 +330,11 @@ func (c *RigsRegistryValidCheck) Fix(ctx *CheckContext) error {
 	// Write back
 	newData, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshaling rigs.json: %w", err)
+		return nil, fmt.Errorf("marshaling rigs.json: %w", err)
 	}
 
-	return os.WriteFile(rigsPath, newData, 0644)
+	plan.WriteFile(rigsPath, newData, 0644)
+	return plan, nil
 }
 
 // MayorExistsCheck verifies the mayor/ directory structure.
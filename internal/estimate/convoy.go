@@ -0,0 +1,103 @@
+package estimate
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// TasksFromIssues converts beads issues into estimator tasks. In-progress
+// and hooked issues count as started at their last dispatch, or at their
+// last update when the events log has no dispatch for them.
+func TasksFromIssues(issues []*beads.Issue, dispatches Dispatches) []Task {
+	now := time.Now()
+	tasks := make([]Task, 0, len(issues))
+	for _, issue := range issues {
+		t := Task{
+			ID:       issue.ID,
+			Type:     issue.Type,
+			Size:     SizeOf(issue.Labels),
+			Status:   issue.Status,
+			Assignee: issue.Assignee,
+		}
+		if issue.Status == "in_progress" || issue.Status == "hooked" {
+			t.StartedAt = dispatches.Last(issue.ID, now)
+			if t.StartedAt.IsZero() {
+				if ts, err := time.Parse(time.RFC3339, issue.UpdatedAt); err == nil {
+					t.StartedAt = ts
+				}
+			}
+		}
+		for _, dep := range issue.Dependencies {
+			if dep.DependencyType == "" || dep.DependencyType == "blocks" {
+				t.DependsOn = append(t.DependsOn, dep.ID)
+			}
+		}
+		t.DependsOn = append(t.DependsOn, issue.DependsOn...)
+		tasks = append(tasks, t)
+	}
+	return tasks
+}
+
+// RigCapacity is how many polecats can work the unfinished tasks at once:
+// the max_polecats setting of each rig that owns one of them, found from
+// the task ID's prefix in routes, summed. maxPolecats returns a rig's
+// setting. Town-level and unrouted tasks add no capacity.
+func RigCapacity(tasks []Task, routes []beads.Route, maxPolecats func(rigName string) int) int {
+	rigByPrefix := make(map[string]string, len(routes))
+	for _, r := range routes {
+		if r.Path != "." {
+			rigByPrefix[r.Prefix] = strings.SplitN(filepath.ToSlash(r.Path), "/", 2)[0]
+		}
+	}
+	capacity := 0
+	counted := make(map[string]bool)
+	for _, t := range tasks {
+		name := rigByPrefix[beads.ExtractPrefix(t.ID)]
+		if t.Done() || name == "" || counted[name] {
+			continue
+		}
+		counted[name] = true
+		capacity += maxPolecats(name)
+	}
+	return capacity
+}
+
+// ForIssues estimates when the given issues will have landed, looking them
+// up through the town beads (which route to each rig) and using the
+// town's cached work history and its dispatches. Worker capacity is the polecat limit of the
+// rigs the issues belong to.
+func ForIssues(townRoot string, ids []string) (*Estimate, error) {
+	issues, err := beads.New(townRoot).ShowMultiple(ids)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*beads.Issue, 0, len(issues))
+	for _, id := range ids {
+		if issue, ok := issues[id]; ok {
+			list = append(list, issue)
+		}
+	}
+
+	h, err := LoadHistory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	dispatches, err := LoadDispatches(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := beads.LoadRoutes(beads.GetTownBeadsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	tasks := TasksFromIssues(list, dispatches)
+	workers := RigCapacity(tasks, routes, func(name string) int {
+		r := &rig.Rig{Name: name, Path: filepath.Join(townRoot, name)}
+		return r.GetIntConfig("max_polecats")
+	})
+	return Compute(tasks, h, Options{Workers: workers})
+}
@@ -0,0 +1,209 @@
+// Package estimate predicts when a convoy will land. It combines historical
+// work durations from closed beads (per type and per assigned polecat), the
+// dependency graph among the convoy's issues, current worker assignments,
+// the rigs' polecat capacity, and merge-queue latency into a critical path
+// and an ETA with a confidence band.
+package estimate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/depgraph"
+)
+
+// Task is one unit of convoy work as seen by the estimator.
+type Task struct {
+	ID        string
+	Type      string // Issue type (task, bug, feature, ...)
+	Size      string // From a size:<s> label, empty if unsized
+	Status    string
+	Assignee  string
+	StartedAt time.Time // When the current worker picked it up; zero if unassigned
+	DependsOn []string  // Blocking dependencies; ones outside the task set are ignored
+}
+
+// Done reports whether the task needs no more work.
+func (t Task) Done() bool {
+	return t.Status == "closed" || t.Status == "tombstone"
+}
+
+// Options tunes an estimate.
+type Options struct {
+	// Now is the reference time for ETAs. Zero means time.Now().
+	Now time.Time
+
+	// Workers is how many tasks can progress at once. When positive, the
+	// ETA is at least the total remaining work divided across workers.
+	// Zero means no capacity bound.
+	Workers int
+}
+
+// Estimate is the predicted landing time for a set of tasks.
+type Estimate struct {
+	Remaining int `json:"remaining"` // Unfinished tasks
+
+	// CriticalPath is the chain of unfinished tasks with the longest
+	// expected duration, first to last.
+	CriticalPath depgraph.Path `json:"critical_path,omitempty"`
+
+	// P50 and P90 are the remaining time at which the work is expected to
+	// have landed with 50% and 90% confidence.
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+
+	ETA50 time.Time `json:"eta_p50"`
+	ETA90 time.Time `json:"eta_p90"`
+
+	// CapacityBound is set when worker capacity, not the critical path,
+	// dominates the estimate.
+	CapacityBound bool `json:"capacity_bound,omitempty"`
+
+	// Samples is the number of historical durations backing the estimate;
+	// fewer than a handful means the band is mostly defaults.
+	Samples int `json:"samples"`
+}
+
+// Done reports whether nothing remains.
+func (e *Estimate) Done() bool {
+	return e.Remaining == 0
+}
+
+// Confidence rates how much history backs the estimate.
+func (e *Estimate) Confidence() string {
+	switch {
+	case e.Samples >= 30:
+		return "high"
+	case e.Samples >= minSamples:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Summary renders the estimate on one line, e.g. "~3h (90%: 7h)".
+func (e *Estimate) Summary() string {
+	if e.Done() {
+		return "landed"
+	}
+	return fmt.Sprintf("~%s (90%%: %s)", FormatDuration(e.P50), FormatDuration(e.P90))
+}
+
+// taskCost is the expected remaining duration of one task.
+type taskCost struct {
+	p50, p90 time.Duration
+}
+
+// spread is the squared gap between the task's P90 and P50 in hours, used
+// to combine uncertainty along a chain.
+func (c taskCost) spread() float64 {
+	d := (c.p90 - c.p50).Hours()
+	return d * d
+}
+
+// Compute estimates when the unfinished tasks will have landed.
+//
+// Each task costs its historical duration plus merge-queue latency, less
+// time already spent by its worker. The critical path is the chain of
+// dependent tasks with the largest total P50 cost, and P50 is that total.
+// Along that chain, P90 adds the P90–P50 gaps in quadrature, since tasks
+// rarely all run long together.
+func Compute(tasks []Task, h *History, opts Options) (*Estimate, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if h == nil {
+		h = &History{}
+	}
+
+	open := make(map[string]Task)
+	var order []string
+	for _, t := range tasks {
+		if t.Done() {
+			continue
+		}
+		if _, dup := open[t.ID]; dup {
+			continue
+		}
+		open[t.ID] = t
+		order = append(order, t.ID)
+	}
+	sort.Strings(order)
+
+	est := &Estimate{Remaining: len(order), ETA50: now, ETA90: now}
+	if len(order) == 0 {
+		return est, nil
+	}
+
+	merge50, merge90, mergeN := h.Merge()
+	est.Samples = mergeN
+	cost := make(map[string]taskCost, len(order))
+	var total50 time.Duration
+	var totalSpread float64
+	for _, id := range order {
+		t := open[id]
+		w50, w90, n := h.Work(t)
+		est.Samples += n
+		c := taskCost{p50: w50 + merge50, p90: w90 + merge90}
+		if !t.StartedAt.IsZero() && now.After(t.StartedAt) {
+			c = c.less(now.Sub(t.StartedAt))
+		}
+		cost[id] = c
+		total50 += c.p50
+		totalSpread += c.spread()
+	}
+
+	g, err := depgraph.New(order, func(id string) []string { return open[id].DependsOn })
+	if err != nil {
+		return nil, err
+	}
+	// Longest chain by P50; P90 spreads along the chain it picks
+	est.CriticalPath, _ = depgraph.CriticalPath(g, func(id string) time.Duration { return cost[id].p50 })
+	var spread float64
+	for _, id := range est.CriticalPath {
+		est.P50 += cost[id].p50
+		spread += cost[id].spread()
+	}
+	est.P90 = est.P50 + hours(math.Sqrt(spread))
+	if opts.Workers > 0 {
+		workers := time.Duration(opts.Workers)
+		if capacity := total50 / workers; capacity > est.P50 {
+			est.P50 = capacity
+			est.CapacityBound = true
+		}
+		capacity90 := (total50 + hours(math.Sqrt(totalSpread))) / workers
+		est.P90 = max(est.P90, capacity90)
+	}
+	est.ETA50 = now.Add(est.P50)
+	est.ETA90 = now.Add(est.P90)
+	return est, nil
+}
+
+// less returns the cost remaining after elapsed time on the task. Work
+// that has overrun keeps a tenth of its estimate so it never reads as free.
+func (c taskCost) less(elapsed time.Duration) taskCost {
+	return taskCost{
+		p50: max(c.p50-elapsed, c.p50/10),
+		p90: max(c.p90-elapsed, c.p90/10),
+	}
+}
+
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
+
+// FormatDuration renders an estimate coarsely: minutes under an hour,
+// hours under two days, days beyond.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(1, int(d.Round(time.Minute).Minutes())))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Round(time.Hour).Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Round(24*time.Hour).Hours()/24))
+	}
+}
@@ -0,0 +1,273 @@
+package estimate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/depgraph"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// flatHistory returns a history where every task takes d (P50 and P90)
+// and merges are instant enough to ignore.
+func flatHistory(d time.Duration) *History {
+	h := &History{}
+	for i := 0; i < minSamples; i++ {
+		h.Add(Sample{Type: "task", Duration: d})
+		h.AddMerge(0)
+	}
+	return h
+}
+
+func TestCompute_CriticalPath(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: "a", Type: "task", Status: "open"},
+		{ID: "b", Type: "task", Status: "open", DependsOn: []string{"a"}},
+		{ID: "c", Type: "task", Status: "open", DependsOn: []string{"b", "outside"}},
+		{ID: "d", Type: "task", Status: "open"},
+		{ID: "z", Type: "task", Status: "closed"},
+	}
+
+	est, err := Compute(tasks, flatHistory(time.Hour), Options{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Remaining != 4 {
+		t.Errorf("Remaining = %d, want 4", est.Remaining)
+	}
+	if !reflect.DeepEqual(est.CriticalPath, depgraph.Path{"a", "b", "c"}) {
+		t.Errorf("CriticalPath = %v", est.CriticalPath)
+	}
+	if est.P50 != 3*time.Hour || est.P90 != 3*time.Hour {
+		t.Errorf("P50/P90 = %v/%v, want 3h/3h", est.P50, est.P90)
+	}
+	if !est.ETA50.Equal(now.Add(3 * time.Hour)) {
+		t.Errorf("ETA50 = %v", est.ETA50)
+	}
+	if !est.CriticalPath.Contains("b") || est.CriticalPath.Contains("d") {
+		t.Error("CriticalPath.Contains wrong")
+	}
+
+	// One worker must do all four hours of work
+	est, _ = Compute(tasks, flatHistory(time.Hour), Options{Now: now, Workers: 1})
+	if est.P50 != 4*time.Hour || !est.CapacityBound {
+		t.Errorf("with one worker P50 = %v (capacity bound %v), want 4h", est.P50, est.CapacityBound)
+	}
+}
+
+func TestCompute_InProgressAndBands(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := &History{}
+	for _, d := range []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 4 * time.Hour, 10 * time.Hour} {
+		h.Add(Sample{Type: "bug", Duration: d})
+		h.AddMerge(0)
+	}
+	p50, p90, n := h.Work(Task{Type: "bug"})
+	if p50 != 3*time.Hour || p90 != 10*time.Hour || n != 5 {
+		t.Fatalf("Work = %v/%v/%d", p50, p90, n)
+	}
+
+	tasks := []Task{
+		{ID: "a", Type: "bug", Status: "in_progress", Assignee: "gt/polecats/Toast", StartedAt: now.Add(-2 * time.Hour)},
+		{ID: "b", Type: "bug", Status: "open", DependsOn: []string{"a"}},
+	}
+	est, err := Compute(tasks, h, Options{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a has 1h (P50) / 8h (P90) left, b takes 3h / 10h:
+	// P50 = 4h, P90 = 4h + sqrt(7² + 7²)h
+	if est.P50 != 4*time.Hour {
+		t.Errorf("P50 = %v, want 4h", est.P50)
+	}
+	if est.P90 < 13*time.Hour || est.P90 > 14*time.Hour {
+		t.Errorf("P90 = %v, want ~13.9h", est.P90)
+	}
+	if est.P90 >= 4*time.Hour+18*time.Hour {
+		t.Error("P90 should be tighter than summing per-task P90s")
+	}
+}
+
+func TestCompute_Overrun(t *testing.T) {
+	now := time.Now()
+	est, err := Compute([]Task{
+		{ID: "a", Type: "task", Status: "hooked", StartedAt: now.Add(-48 * time.Hour)},
+	}, flatHistory(time.Hour), Options{Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.P50 != 6*time.Minute {
+		t.Errorf("overrun task should keep a tenth of its estimate, got %v", est.P50)
+	}
+}
+
+func TestCompute_DoneAndCycle(t *testing.T) {
+	est, err := Compute([]Task{{ID: "a", Status: "closed"}}, nil, Options{})
+	if err != nil || !est.Done() || est.Summary() != "landed" {
+		t.Errorf("expected landed estimate, got %+v, %v", est, err)
+	}
+
+	_, err = Compute([]Task{
+		{ID: "x", Status: "open", DependsOn: []string{"y"}},
+		{ID: "y", Status: "open", DependsOn: []string{"x"}},
+		{ID: "z", Status: "open"},
+	}, nil, Options{})
+	if err == nil || !strings.Contains(err.Error(), "x, y") {
+		t.Errorf("expected cycle error naming x and y, got %v", err)
+	}
+}
+
+func TestHistory_Fallbacks(t *testing.T) {
+	h := &History{}
+	if p50, _, n := h.Work(Task{Type: "task"}); p50 != defaultWork50 || n != 0 {
+		t.Errorf("empty history should use defaults, got %v (%d)", p50, n)
+	}
+
+	for i := 0; i < minSamples; i++ {
+		h.Add(Sample{Type: "task", Size: "s", Duration: time.Hour})
+		h.Add(Sample{Type: "task", Size: "l", Worker: "gt/polecats/Nux", Duration: 5 * time.Hour})
+	}
+	tests := []struct {
+		task Task
+		want time.Duration
+	}{
+		{Task{Type: "task", Size: "s"}, time.Hour},
+		{Task{Type: "task", Size: "l"}, 5 * time.Hour},
+		{Task{Type: "task", Size: "s", Assignee: "gt/polecats/Nux"}, 5 * time.Hour}, // CV wins
+		{Task{Type: "task", Size: "m"}, time.Hour},                                  // Falls back to type
+		{Task{Type: "chore"}, time.Hour},                                            // Falls back to all work
+	}
+	for _, tt := range tests {
+		if p50, _, _ := h.Work(tt.task); p50 != tt.want {
+			t.Errorf("Work(%+v) P50 = %v, want %v", tt.task, p50, tt.want)
+		}
+	}
+}
+
+func TestAddIssue(t *testing.T) {
+	h := &History{}
+	issue := func(id, typ, created, closed string, labels ...string) *beads.Issue {
+		return &beads.Issue{ID: id, Type: typ, Status: "closed", CreatedAt: created, ClosedAt: closed,
+			Labels: labels, Assignee: "gt/polecats/Toast"}
+	}
+	at := func(s string) time.Time {
+		ts, _ := time.Parse(time.RFC3339, s)
+		return ts
+	}
+	d := make(Dispatches)
+	d.Add("gt-1", at("2025-12-02T10:00:00Z")) // Abandoned attempt
+	d.Add("gt-1", at("2026-01-01T10:00:00Z"))
+	d.Add("gt-3", at("2026-01-01T10:00:00Z"))
+	d.Add("gt-4", at("2026-01-01T10:00:00Z"))
+
+	h.AddIssue(issue("gt-1", "task", "2025-12-01T10:00:00Z", "2026-01-01T12:00:00Z", "size:M"), d)
+	h.AddIssue(issue("gt-2", "task", "2026-01-01T10:00:00Z", "2026-01-01T10:20:00Z", "gt:merge-request"), d)
+	h.AddIssue(issue("gt-3", "task", "2026-01-01T10:00:00Z", "2026-01-01T11:00:00Z", "gt:agent"), d)
+	h.AddIssue(issue("gt-4", "convoy", "2026-01-01T10:00:00Z", "2026-01-01T11:00:00Z"), d)
+	h.AddIssue(issue("gt-5", "task", "2026-01-01T10:00:00Z", "2026-01-01T11:00:00Z"), d) // Never dispatched
+
+	if got := h.ByKind["task/m"]; len(got) != 1 || got[0] != 2*time.Hour {
+		t.Errorf("task/m = %v, want [2h] measured from the last dispatch", got)
+	}
+	if len(h.ByWorker["gt/polecats/Toast|task"]) != 1 {
+		t.Errorf("ByWorker = %v", h.ByWorker)
+	}
+	if len(h.MergeWait) != 1 || h.MergeWait[0] != 20*time.Minute {
+		t.Errorf("MergeWait = %v", h.MergeWait)
+	}
+	if len(h.ByKind[""]) != 1 {
+		t.Errorf("expected only one work sample, got %v", h.ByKind[""])
+	}
+}
+
+func TestTasksFromIssues_StartedAt(t *testing.T) {
+	d := make(Dispatches)
+	dispatched, _ := time.Parse(time.RFC3339, "2026-01-01T10:00:00Z")
+	d.Add("gt-1", dispatched)
+
+	tasks := TasksFromIssues([]*beads.Issue{
+		{ID: "gt-1", Status: "in_progress", UpdatedAt: "2026-01-01T11:30:00Z"},
+		{ID: "gt-2", Status: "hooked", UpdatedAt: "2026-01-01T11:30:00Z"}, // No dispatch recorded
+		{ID: "gt-3", Status: "open", UpdatedAt: "2026-01-01T11:30:00Z"},
+	}, d)
+	want := []string{"2026-01-01T10:00:00Z", "2026-01-01T11:30:00Z", ""}
+	for i, task := range tasks {
+		got := ""
+		if !task.StartedAt.IsZero() {
+			got = task.StartedAt.UTC().Format(time.RFC3339)
+		}
+		if got != want[i] {
+			t.Errorf("%s StartedAt = %q, want %q", task.ID, got, want[i])
+		}
+	}
+}
+
+func TestLoadDispatches(t *testing.T) {
+	townRoot := t.TempDir()
+	log := `{"ts":"2026-01-01T10:00:00Z","type":"sling","payload":{"bead":"gt-1","target":"gt/polecats/Toast"}}
+{"ts":"2026-01-01T10:05:00Z","type":"mail","payload":{"to":"mayor/"}}
+{"ts":"2026-01-01T11:00:00Z","type":"hook","payload":{"bead":"gt-1"}}
+not json
+`
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := LoadDispatches(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 1 || len(d["gt-1"]) != 2 {
+		t.Fatalf("dispatches = %v", d)
+	}
+	closed, _ := time.Parse(time.RFC3339, "2026-01-01T10:30:00Z")
+	if got := d.Last("gt-1", closed); got.Format(time.RFC3339) != "2026-01-01T10:00:00Z" {
+		t.Errorf("Last before %v = %v", closed, got)
+	}
+
+	if d, err := LoadDispatches(t.TempDir()); err != nil || len(d) != 0 {
+		t.Errorf("no events log: %v, %v", d, err)
+	}
+}
+
+func TestRigCapacity(t *testing.T) {
+	routes := []beads.Route{
+		{Prefix: "hq-", Path: "."},
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+		{Prefix: "bd-", Path: "beads/mayor/rig"},
+		{Prefix: "wy-", Path: "wyvern/mayor/rig"},
+	}
+	limits := map[string]int{"gastown": 4, "beads": 2, "wyvern": 8}
+	tasks := []Task{
+		{ID: "gt-1", Status: "open"},
+		{ID: "gt-2", Status: "in_progress"}, // Same rig counts once
+		{ID: "bd-1", Status: "open"},
+		{ID: "wy-1", Status: "closed"}, // Finished work needs no capacity
+		{ID: "hq-1", Status: "open"},   // Town-level
+		{ID: "xx-1", Status: "open"},   // Unrouted
+	}
+	if got := RigCapacity(tasks, routes, func(name string) int { return limits[name] }); got != 6 {
+		t.Errorf("RigCapacity = %d, want 6", got)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		30 * time.Second: "1m",
+		45 * time.Minute: "45m",
+		90 * time.Minute: "2h",
+		30 * time.Hour:   "30h",
+		72 * time.Hour:   "3d",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package estimate
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/retention"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// minSamples is the fewest durations a bucket needs before it is trusted
+// over a coarser one.
+const minSamples = 3
+
+// Fallbacks when there is no usable history at all.
+const (
+	defaultWork50  = 2 * time.Hour
+	defaultWork90  = 8 * time.Hour
+	defaultMerge50 = 15 * time.Minute
+	defaultMerge90 = time.Hour
+)
+
+// Durations outside this range are discarded as noise: instant closes are
+// usually duplicates, and month-old closes sat in a backlog rather than
+// being worked.
+const (
+	minSampleDuration = time.Minute
+	maxSampleDuration = 14 * 24 * time.Hour
+)
+
+// HistoryTTL is how long a cached history is reused before closed beads
+// are scanned again.
+const HistoryTTL = 15 * time.Minute
+
+// Sample is one historical work duration.
+type Sample struct {
+	Type     string
+	Size     string
+	Worker   string // Polecat the work was assigned to, if any
+	Duration time.Duration
+}
+
+// History holds historical durations bucketed for lookup. Kind buckets are
+// keyed "type/size", "type" and "" (everything); worker buckets, built from
+// the polecat each closed bead was assigned to, are keyed "worker|type".
+type History struct {
+	ByKind    map[string][]time.Duration `json:"by_kind,omitempty"`
+	ByWorker  map[string][]time.Duration `json:"by_worker,omitempty"`
+	MergeWait []time.Duration            `json:"merge_wait,omitempty"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// Add records a work duration in every bucket it belongs to.
+func (h *History) Add(s Sample) {
+	if h.ByKind == nil {
+		h.ByKind = make(map[string][]time.Duration)
+	}
+	h.ByKind[""] = append(h.ByKind[""], s.Duration)
+	if s.Type != "" {
+		h.ByKind[s.Type] = append(h.ByKind[s.Type], s.Duration)
+		if s.Size != "" {
+			h.ByKind[s.Type+"/"+s.Size] = append(h.ByKind[s.Type+"/"+s.Size], s.Duration)
+		}
+	}
+	if s.Worker != "" {
+		if h.ByWorker == nil {
+			h.ByWorker = make(map[string][]time.Duration)
+		}
+		key := s.Worker + "|" + s.Type
+		h.ByWorker[key] = append(h.ByWorker[key], s.Duration)
+	}
+}
+
+// AddMerge records how long a merge request waited in the queue.
+func (h *History) AddMerge(d time.Duration) {
+	h.MergeWait = append(h.MergeWait, d)
+}
+
+// Work returns the P50 and P90 duration for a task and how many samples
+// back them. The most specific bucket with enough samples wins: the
+// assigned polecat's own record for the type, then type and size, type,
+// and finally all work.
+func (h *History) Work(t Task) (p50, p90 time.Duration, n int) {
+	var buckets [][]time.Duration
+	if t.Assignee != "" {
+		buckets = append(buckets, h.ByWorker[t.Assignee+"|"+t.Type])
+	}
+	if t.Size != "" {
+		buckets = append(buckets, h.ByKind[t.Type+"/"+t.Size])
+	}
+	buckets = append(buckets, h.ByKind[t.Type], h.ByKind[""])
+	for _, b := range buckets {
+		if len(b) >= minSamples {
+			return quantile(b, 0.5), quantile(b, 0.9), len(b)
+		}
+	}
+	return defaultWork50, defaultWork90, 0
+}
+
+// Merge returns the P50 and P90 merge-queue latency and the sample count.
+func (h *History) Merge() (p50, p90 time.Duration, n int) {
+	if len(h.MergeWait) < minSamples {
+		return defaultMerge50, defaultMerge90, 0
+	}
+	return quantile(h.MergeWait, 0.5), quantile(h.MergeWait, 0.9), len(h.MergeWait)
+}
+
+// quantile returns the q-th quantile of ds by nearest rank.
+func quantile(ds []time.Duration, q float64) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(q*float64(len(sorted)) + 0.5)
+	idx = min(max(idx-1, 0), len(sorted)-1)
+	return sorted[idx]
+}
+
+// SizeOf returns the size from an issue's size:<s> label, or "".
+func SizeOf(labels []string) string {
+	for _, l := range labels {
+		if size, ok := strings.CutPrefix(l, "size:"); ok {
+			return strings.ToLower(size)
+		}
+	}
+	return ""
+}
+
+// skipTypes are bead types that aren't units of polecat work.
+var skipTypes = map[string]bool{
+	"convoy":   true,
+	"epic":     true,
+	"agent":    true,
+	"role":     true,
+	"message":  true,
+	"molecule": true,
+	"event":    true,
+	"gate":     true,
+}
+
+// Dispatches holds when each bead was slung or hooked, from the town's
+// events log, keyed by bead ID.
+type Dispatches map[string][]time.Time
+
+// Add records a dispatch of a bead.
+func (d Dispatches) Add(beadID string, at time.Time) {
+	d[beadID] = append(d[beadID], at)
+}
+
+// Last returns the latest dispatch of a bead at or before t, or the zero
+// time if there is none. Earlier dispatches belong to attempts that were
+// abandoned and re-slung, and time between them was spent waiting.
+func (d Dispatches) Last(beadID string, t time.Time) time.Time {
+	var last time.Time
+	for _, at := range d[beadID] {
+		if !at.After(t) && at.After(last) {
+			last = at
+		}
+	}
+	return last
+}
+
+// LoadDispatches reads sling and hook events from the town's events log,
+// including rotated segments. A town without an events log has none.
+func LoadDispatches(townRoot string) (Dispatches, error) {
+	d := make(Dispatches)
+	r, err := retention.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return d, nil
+		}
+		return nil, err
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if e.Type != events.TypeSling && e.Type != events.TypeHook {
+			continue
+		}
+		bead, _ := e.Payload["bead"].(string)
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if bead == "" || err != nil {
+			continue
+		}
+		d.Add(bead, at)
+	}
+	return d, scanner.Err()
+}
+
+// AddIssue records a closed issue: merge requests feed merge-queue latency,
+// measured from when the request was filed, and other work feeds the
+// duration buckets, measured from its last dispatch. Work with no recorded
+// dispatch is skipped, since its creation time includes time spent in the
+// backlog. Non-work beads and durations outside the plausible range are
+// ignored.
+func (h *History) AddIssue(issue *beads.Issue, dispatches Dispatches) {
+	if issue.Status != "closed" || skipTypes[issue.Type] {
+		return
+	}
+	closed, err := time.Parse(time.RFC3339, issue.ClosedAt)
+	if err != nil {
+		return
+	}
+
+	isMR := false
+	for _, l := range issue.Labels {
+		if l == "gt:merge-request" {
+			isMR = true
+		} else if strings.HasPrefix(l, "gt:") {
+			return // Infrastructure bead, not work
+		}
+	}
+
+	var started time.Time
+	if isMR {
+		started, err = time.Parse(time.RFC3339, issue.CreatedAt)
+		if err != nil {
+			return
+		}
+	} else if started = dispatches.Last(issue.ID, closed); started.IsZero() {
+		return
+	}
+	d := closed.Sub(started)
+	if d < minSampleDuration || d > maxSampleDuration {
+		return
+	}
+	if isMR {
+		h.AddMerge(d)
+		return
+	}
+
+	worker := ""
+	if strings.Contains(issue.Assignee, "/polecats/") {
+		worker = issue.Assignee
+	}
+	h.Add(Sample{Type: issue.Type, Size: SizeOf(issue.Labels), Worker: worker, Duration: d})
+}
+
+// historyPath is where the scanned history is cached.
+func historyPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "estimate", "history.json")
+}
+
+// LoadHistory returns the town's work history, reusing the cached copy if
+// it is younger than HistoryTTL and otherwise scanning closed beads in the
+// town and every routed rig.
+func LoadHistory(townRoot string) (*History, error) {
+	path := historyPath(townRoot)
+	if data, err := os.ReadFile(path); err == nil {
+		var h History
+		if json.Unmarshal(data, &h) == nil && time.Since(h.UpdatedAt) < HistoryTTL {
+			return &h, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	h, err := ScanHistory(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Cache write failures only cost a rescan next time
+	_ = os.MkdirAll(filepath.Dir(path), 0755)
+	_ = util.AtomicWriteJSON(path, h)
+	return h, nil
+}
+
+// ScanHistory builds a history from closed beads in the town beads and the
+// beads of every rig listed in routes.jsonl, timing work from the dispatches
+// in the events log. Rigs whose beads can't be listed are skipped.
+func ScanHistory(townRoot string) (*History, error) {
+	townBeads := beads.GetTownBeadsPath(townRoot)
+	routes, err := beads.LoadRoutes(townBeads)
+	if err != nil {
+		return nil, err
+	}
+	dispatches, err := LoadDispatches(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{townRoot}
+	seen := map[string]bool{townRoot: true}
+	for _, r := range routes {
+		dir := filepath.Join(townRoot, r.Path)
+		if r.Path == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+
+	h := &History{UpdatedAt: time.Now()}
+	for _, dir := range dirs {
+		issues, err := beads.New(dir).List(beads.ListOptions{Status: "closed", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			h.AddIssue(issue, dispatches)
+		}
+	}
+	return h, nil
+}
@@ -54,6 +54,13 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// Usage-limit events (emitted by daemon account rotation)
+	TypeUsageLimit     = "usage_limit"     // Session hit a provider usage limit
+	TypeAccountRotated = "account_rotated" // Session restarted under another account
+
+	// Continuous doctor events (emitted by the daemon)
+	TypeHealthChanged = "health_changed" // A doctor check started or stopped erroring
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -73,6 +80,10 @@ const (
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
+// FeedFile is the name of the curated feed the feed daemon derives from
+// the events log.
+const FeedFile = ".feed.jsonl"
+
 // mutex protects concurrent writes to the events file.
 var mutex sync.Mutex
 
@@ -311,6 +322,30 @@ func MassDeathPayload(count int, window string, sessions []string, possibleCause
 	return p
 }
 
+// UsageLimitPayload creates a payload for usage-limit events.
+// session: tmux session that hit the limit
+// account: account handle that is limited (may be empty)
+// action: what was done ("rotate" or "park")
+// target: account rotated to, or reset time when parked
+func UsageLimitPayload(session, account, action, target string) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"account": account,
+		"action":  action,
+		"target":  target,
+	}
+}
+
+// HealthChangedPayload creates a payload for doctor check state changes.
+func HealthChangedPayload(check, from, to, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"check":   check,
+		"from":    from,
+		"to":      to,
+		"message": message,
+	}
+}
+
 // SessionPayload creates a payload for session start/end events.
 // sessionID: Claude Code session UUID
 // role: Gas Town role (e.g., "gastown/crew/joe", "deacon")
@@ -21,11 +21,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/retention"
 )
 
-// FeedFile is the name of the curated feed file.
-const FeedFile = ".feed.jsonl"
-
 // FeedEvent is the structure of events written to the feed.
 type FeedEvent struct {
 	Timestamp string                 `json:"ts"`
@@ -88,7 +86,7 @@ func (c *Curator) Start() error {
 	}
 
 	c.wg.Add(1)
-	go c.run(file)
+	go c.run(file, eventsPath)
 
 	return nil
 }
@@ -101,9 +99,9 @@ func (c *Curator) Stop() {
 
 // run is the main curator loop.
 // ZFC: No in-memory state to clean up - state is derived from the events file.
-func (c *Curator) run(file *os.File) {
+func (c *Curator) run(file *os.File, eventsPath string) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer func() { _ = file.Close() }()
 
 	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -123,6 +121,18 @@ func (c *Curator) run(file *os.File) {
 				}
 				c.processLine(line)
 			}
+
+			// Once the rotated file is drained, continue from the top of
+			// the new one
+			if retention.Replaced(file, eventsPath) {
+				next, err := os.Open(eventsPath) //nolint:gosec // G304: path is constructed from trusted townRoot
+				if err != nil {
+					continue
+				}
+				_ = file.Close()
+				file = next
+				reader.Reset(file)
+			}
 		}
 	}
 }
@@ -176,7 +186,7 @@ func (c *Curator) shouldDedupe(event *events.Event) bool {
 // readRecentFeedEvents reads feed events from the feed file within the given time window.
 // ZFC: The feed file is the observable state of what we've already output.
 func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
-	feedPath := filepath.Join(c.townRoot, FeedFile)
+	feedPath := filepath.Join(c.townRoot, events.FeedFile)
 
 	data, err := os.ReadFile(feedPath)
 	if err != nil {
@@ -303,7 +313,7 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 	}
 	data = append(data, '\n')
 
-	feedPath := filepath.Join(c.townRoot, FeedFile)
+	feedPath := filepath.Join(c.townRoot, events.FeedFile)
 	f, err := os.OpenFile(feedPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: feed file is non-sensitive operational data
 	if err != nil {
 		return
@@ -387,6 +397,18 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Multiple sessions died simultaneously"
 
+	case events.TypeHealthChanged:
+		check, _ := event.Payload["check"].(string)
+		to, _ := event.Payload["to"].(string)
+		message, _ := event.Payload["message"].(string)
+		if to == "error" {
+			if message != "" {
+				return fmt.Sprintf("Health check %s failing: %s", check, message)
+			}
+			return fmt.Sprintf("Health check %s failing", check)
+		}
+		return fmt.Sprintf("Health check %s recovered (%s)", check, to)
+
 	default:
 		return fmt.Sprintf("%s: %s", event.Actor, event.Type)
 	}
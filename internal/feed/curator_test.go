@@ -20,7 +20,7 @@ func TestCurator_FiltersByVisibility(t *testing.T) {
 
 	// Create events file with test events
 	eventsPath := filepath.Join(tmpDir, events.EventsFile)
-	feedPath := filepath.Join(tmpDir, FeedFile)
+	feedPath := filepath.Join(tmpDir, events.FeedFile)
 
 	// Write a feed-visible event
 	feedEvent := events.Event{
@@ -102,7 +102,7 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	eventsPath := filepath.Join(tmpDir, events.EventsFile)
-	feedPath := filepath.Join(tmpDir, FeedFile)
+	feedPath := filepath.Join(tmpDir, events.FeedFile)
 
 	// Create events file
 	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
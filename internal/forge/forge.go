@@ -0,0 +1,370 @@
+// Package forge talks to code-hosting APIs (GitHub, GitLab, Gitea) so the
+// refinery can land merge requests through pull requests, with the
+// forge's required checks and reviews, instead of pushing to the target
+// branch directly.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Forge types.
+const (
+	TypeGitHub = "github"
+	TypeGitLab = "gitlab"
+	TypeGitea  = "gitea"
+)
+
+// Forge is a code host that can open, inspect and merge pull requests.
+// GitLab's merge requests are called pull requests here.
+type Forge interface {
+	// Name returns the forge type, e.g. "github".
+	Name() string
+
+	// FindPR returns the open PR from head into base, or nil if there is none.
+	FindPR(ctx context.Context, head, base string) (*PR, error)
+
+	// CreatePR opens a PR.
+	CreatePR(ctx context.Context, req PRRequest) (*PR, error)
+
+	// UpdatePR updates an open PR's title and body.
+	UpdatePR(ctx context.Context, number int, req PRRequest) error
+
+	// GetPR returns a PR with its mergeability and combined check status.
+	GetPR(ctx context.Context, number int) (*PR, error)
+
+	// MergePR merges a PR whose head is still headSHA and returns the
+	// resulting commit on the base branch. A PR that can't be merged yields
+	// an error wrapping ErrNotMergeable.
+	MergePR(ctx context.Context, number int, method MergeMethod, headSHA string) (string, error)
+}
+
+// PRRequest describes a PR to open or update.
+type PRRequest struct {
+	Head  string // Source branch
+	Base  string // Target branch
+	Title string
+	Body  string
+}
+
+// PRState is the lifecycle state of a PR.
+type PRState string
+
+const (
+	PROpen   PRState = "open"
+	PRMerged PRState = "merged"
+	PRClosed PRState = "closed" // Closed without merging
+)
+
+// Mergeability is whether the forge would accept a merge now.
+type Mergeability string
+
+const (
+	// Mergeable means the forge will merge it (checks and reviews permitting).
+	Mergeable Mergeability = "mergeable"
+	// Conflicting means the head conflicts with the base.
+	Conflicting Mergeability = "conflict"
+	// Behind means the head merges cleanly but branch protection requires
+	// it to be brought up to date with the base first.
+	Behind Mergeability = "behind"
+	// Blocked means required reviews or other protections aren't satisfied.
+	Blocked Mergeability = "blocked"
+	// MergeUnknown means the forge is still computing mergeability.
+	MergeUnknown Mergeability = "unknown"
+)
+
+// CheckState is the combined status of a PR's CI checks.
+type CheckState string
+
+const (
+	ChecksNone    CheckState = "none" // No checks reported
+	ChecksPending CheckState = "pending"
+	ChecksSuccess CheckState = "success"
+	ChecksFailure CheckState = "failure"
+)
+
+// PR is a pull request as seen by the refinery.
+type PR struct {
+	Number      int          `json:"number"`
+	URL         string       `json:"url"`
+	State       PRState      `json:"state"`
+	HeadSHA     string       `json:"head_sha"`
+	MergeCommit string       `json:"merge_commit,omitempty"`
+	Mergeable   Mergeability `json:"mergeable"`
+	Checks      CheckState   `json:"checks"`
+
+	// FailedChecks names the failing checks, if any.
+	FailedChecks []string `json:"failed_checks,omitempty"`
+}
+
+// MergeMethod is how the forge merges a PR.
+type MergeMethod string
+
+const (
+	MergeCommit MergeMethod = "merge"
+	MergeSquash MergeMethod = "squash"
+	MergeRebase MergeMethod = "rebase"
+)
+
+// ErrNotMergeable is returned by MergePR when the forge refuses the merge.
+var ErrNotMergeable = errors.New("not mergeable")
+
+// APIError is a non-2xx forge response.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("forge API: HTTP %d", e.Status)
+	}
+	return fmt.Sprintf("forge API: HTTP %d: %s", e.Status, e.Message)
+}
+
+// Config selects and authenticates a forge.
+type Config struct {
+	// Type is github, gitlab or gitea. Detected from the remote URL when
+	// empty (github.com and hosts containing "gitlab" only).
+	Type string `json:"type,omitempty"`
+
+	// URL is the API base URL, e.g. https://api.github.com or
+	// https://git.example.com/api/v1. Derived from the remote when empty.
+	URL string `json:"url,omitempty"`
+
+	// Repo is owner/name (GitLab: the project path). Derived from the
+	// remote when empty.
+	Repo string `json:"repo,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Default: GITHUB_TOKEN (then GH_TOKEN), GITLAB_TOKEN or GITEA_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// New returns the forge for cfg, filling unset fields from remoteURL (the
+// rig's origin URL).
+func New(cfg Config, remoteURL string) (Forge, error) {
+	host, repo := parseRemote(remoteURL)
+	if cfg.Repo == "" {
+		cfg.Repo = repo
+	}
+	if cfg.Type == "" {
+		switch {
+		case host == "github.com":
+			cfg.Type = TypeGitHub
+		case strings.Contains(host, "gitlab"):
+			cfg.Type = TypeGitLab
+		default:
+			return nil, fmt.Errorf("can't tell which forge %q is; set merge_queue.forge.type", host)
+		}
+	}
+	if cfg.Repo == "" {
+		return nil, fmt.Errorf("can't derive the repository from %q; set merge_queue.forge.repo", remoteURL)
+	}
+	if cfg.URL == "" {
+		if host == "" {
+			return nil, fmt.Errorf("can't derive the API URL from %q; set merge_queue.forge.url", remoteURL)
+		}
+		cfg.URL = defaultAPIURL(cfg.Type, host)
+	}
+
+	c := &client{base: strings.TrimSuffix(cfg.URL, "/"), http: &http.Client{Timeout: 30 * time.Second}}
+	switch cfg.Type {
+	case TypeGitHub:
+		c.token = tokenFrom(cfg.TokenEnv, "GITHUB_TOKEN", "GH_TOKEN")
+		c.auth = func(r *http.Request, t string) { r.Header.Set("Authorization", "Bearer "+t) }
+		return &gitHub{c: c, repo: cfg.Repo}, nil
+	case TypeGitLab:
+		c.token = tokenFrom(cfg.TokenEnv, "GITLAB_TOKEN")
+		c.auth = func(r *http.Request, t string) { r.Header.Set("PRIVATE-TOKEN", t) }
+		return &gitLab{c: c, project: url.PathEscape(cfg.Repo)}, nil
+	case TypeGitea:
+		c.token = tokenFrom(cfg.TokenEnv, "GITEA_TOKEN")
+		c.auth = func(r *http.Request, t string) { r.Header.Set("Authorization", "token "+t) }
+		return &gitea{c: c, repo: cfg.Repo}, nil
+	default:
+		return nil, fmt.Errorf("unknown forge type %q (want github, gitlab or gitea)", cfg.Type)
+	}
+}
+
+// defaultAPIURL returns the conventional API base for a forge host.
+func defaultAPIURL(typ, host string) string {
+	switch typ {
+	case TypeGitHub:
+		if host == "github.com" {
+			return "https://api.github.com"
+		}
+		return "https://" + host + "/api/v3" // GitHub Enterprise
+	case TypeGitLab:
+		return "https://" + host + "/api/v4"
+	default:
+		return "https://" + host + "/api/v1"
+	}
+}
+
+// parseRemote extracts the host and owner/repo path from a git remote URL
+// (https://host/owner/repo.git or git@host:owner/repo.git).
+func parseRemote(remote string) (host, repo string) {
+	remote = strings.TrimSpace(remote)
+	if remote == "" {
+		return "", ""
+	}
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		return u.Hostname(), strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	}
+	// scp-like: [user@]host:path
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	if colon := strings.Index(remote, ":"); colon > 0 {
+		return remote[:colon], strings.TrimSuffix(strings.Trim(remote[colon+1:], "/"), ".git")
+	}
+	return "", ""
+}
+
+func tokenFrom(explicit string, defaults ...string) string {
+	if explicit != "" {
+		return os.Getenv(explicit)
+	}
+	for _, name := range defaults {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// client is a minimal JSON REST client shared by the forge implementations.
+type client struct {
+	base  string
+	token string
+	auth  func(*http.Request, string)
+	http  *http.Client
+}
+
+// do sends a JSON request and decodes a JSON response into out (if non-nil).
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		c.auth(req, c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{Status: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// errorMessage pulls the message out of a forge error body.
+func errorMessage(data []byte) string {
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		if body.Message != "" {
+			return body.Message
+		}
+		if body.Error != "" {
+			return body.Error
+		}
+	}
+	s := strings.TrimSpace(string(data))
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+// isStatus reports whether err is an APIError with one of the statuses.
+func isStatus(err error, statuses ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, s := range statuses {
+		if apiErr.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// combineChecks folds individual check states into one: any failure fails,
+// otherwise any pending is pending, otherwise success (or none if empty).
+func combineChecks(states []CheckState) CheckState {
+	if len(states) == 0 {
+		return ChecksNone
+	}
+	result := ChecksSuccess
+	for _, s := range states {
+		switch s {
+		case ChecksFailure:
+			return ChecksFailure
+		case ChecksPending:
+			result = ChecksPending
+		}
+	}
+	return result
+}
+
+// EnsurePR returns the open PR from req.Head into req.Base. It opens one if
+// none exists, and otherwise refreshes its title and body. created reports
+// whether the PR is new.
+func EnsurePR(ctx context.Context, f Forge, req PRRequest) (pr *PR, created bool, err error) {
+	pr, err = f.FindPR(ctx, req.Head, req.Base)
+	if err != nil {
+		return nil, false, fmt.Errorf("finding PR for %s: %w", req.Head, err)
+	}
+	if pr == nil {
+		pr, err = f.CreatePR(ctx, req)
+		if err != nil {
+			return nil, false, fmt.Errorf("opening PR for %s: %w", req.Head, err)
+		}
+		return pr, true, nil
+	}
+	if err := f.UpdatePR(ctx, pr.Number, req); err != nil {
+		return nil, false, fmt.Errorf("updating PR #%d: %w", pr.Number, err)
+	}
+	return pr, false, nil
+}
@@ -0,0 +1,311 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote, host, repo string
+	}{
+		{"https://github.com/acme/widgets.git", "github.com", "acme/widgets"},
+		{"git@github.com:acme/widgets.git", "github.com", "acme/widgets"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/proj.git", "gitlab.example.com", "group/sub/proj"},
+		{"https://git.example.com/acme/widgets", "git.example.com", "acme/widgets"},
+		{"/srv/git/widgets.git", "", ""},
+	}
+	for _, tt := range tests {
+		host, repo := parseRemote(tt.remote)
+		if host != tt.host || repo != tt.repo {
+			t.Errorf("parseRemote(%q) = %q, %q; want %q, %q", tt.remote, host, repo, tt.host, tt.repo)
+		}
+	}
+}
+
+func TestNew_Detection(t *testing.T) {
+	f, err := New(Config{}, "git@github.com:acme/widgets.git")
+	if err != nil || f.Name() != TypeGitHub {
+		t.Fatalf("New(github remote) = %v, %v", f, err)
+	}
+	if gh := f.(*gitHub); gh.c.base != "https://api.github.com" || gh.repo != "acme/widgets" {
+		t.Errorf("github = %s %s", gh.c.base, gh.repo)
+	}
+
+	f, err = New(Config{}, "https://gitlab.example.com/group/proj.git")
+	if err != nil || f.Name() != TypeGitLab {
+		t.Fatalf("New(gitlab remote) = %v, %v", f, err)
+	}
+	if gl := f.(*gitLab); gl.c.base != "https://gitlab.example.com/api/v4" || gl.project != "group%2Fproj" {
+		t.Errorf("gitlab = %s %s", gl.c.base, gl.project)
+	}
+
+	if _, err := New(Config{}, "https://git.example.com/acme/widgets.git"); err == nil {
+		t.Error("unknown host should require an explicit type")
+	}
+	f, err = New(Config{Type: TypeGitea}, "https://git.example.com/acme/widgets.git")
+	if err != nil || f.(*gitea).c.base != "https://git.example.com/api/v1" {
+		t.Errorf("New(gitea) = %v, %v", f, err)
+	}
+}
+
+// fakeGitHub serves the slice of the GitHub API the forge uses, for one
+// repository with at most one PR.
+type fakeGitHub struct {
+	t         *testing.T
+	pull      *ghPull
+	title     string
+	statuses  []map[string]string
+	checkRuns []map[string]string
+	mergeCode int
+	merged    map[string]string
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	reply := func(v interface{}) { _ = json.NewEncoder(w).Encode(v) }
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodGet && path == "/repos/acme/widgets/pulls":
+		if got := r.URL.Query().Get("head"); got != "acme:polecat/nux" {
+			f.t.Errorf("head filter = %q", got)
+		}
+		if f.pull == nil {
+			reply([]ghPull{})
+		} else {
+			reply([]ghPull{*f.pull})
+		}
+	case r.Method == http.MethodPost && path == "/repos/acme/widgets/pulls":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.title = body["title"]
+		f.pull = &ghPull{Number: 7, HTMLURL: "https://github.com/acme/widgets/pull/7", State: "open", MergeableState: "unknown"}
+		f.pull.Head.SHA = "abc123"
+		w.WriteHeader(http.StatusCreated)
+		reply(f.pull)
+	case r.Method == http.MethodPatch && path == "/repos/acme/widgets/pulls/7":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.title = body["title"]
+		reply(f.pull)
+	case r.Method == http.MethodGet && path == "/repos/acme/widgets/pulls/7":
+		reply(f.pull)
+	case path == "/repos/acme/widgets/commits/abc123/status":
+		reply(map[string]interface{}{"statuses": f.statuses})
+	case path == "/repos/acme/widgets/commits/abc123/check-runs":
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if perPage <= 0 || page <= 0 {
+			f.t.Errorf("check-runs requested without paging: %s", r.URL.RawQuery)
+			perPage, page = len(f.checkRuns)+1, 1
+		}
+		start := min((page-1)*perPage, len(f.checkRuns))
+		end := min(start+perPage, len(f.checkRuns))
+		reply(map[string]interface{}{"total_count": len(f.checkRuns), "check_runs": f.checkRuns[start:end]})
+	case r.Method == http.MethodPut && path == "/repos/acme/widgets/pulls/7/merge":
+		_ = json.NewDecoder(r.Body).Decode(&f.merged)
+		if f.mergeCode != 0 {
+			w.WriteHeader(f.mergeCode)
+			reply(map[string]string{"message": "Required status check is expected"})
+			return
+		}
+		reply(map[string]interface{}{"sha": "merge456", "merged": true})
+	default:
+		f.t.Errorf("unexpected %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, Forge) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "secret")
+	fake := &fakeGitHub{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	f, err := New(Config{Type: TypeGitHub, URL: srv.URL}, "git@github.com:acme/widgets.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, f
+}
+
+func TestGitHub_EnsureAndMerge(t *testing.T) {
+	fake, f := newFakeGitHub(t)
+	ctx := context.Background()
+	req := PRRequest{Head: "polecat/nux", Base: "main", Title: "Fix login (gt-abc)"}
+
+	pr, created, err := EnsurePR(ctx, f, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || pr.Number != 7 || fake.title != "Fix login (gt-abc)" {
+		t.Errorf("EnsurePR = %+v, created=%v", pr, created)
+	}
+	if pr.Mergeable != MergeUnknown || pr.Checks != ChecksNone {
+		t.Errorf("fresh PR = %+v", pr)
+	}
+
+	// Second call finds and updates the same PR
+	req.Title = "Fix login v2 (gt-abc)"
+	if pr, created, err = EnsurePR(ctx, f, req); err != nil || created || pr.Number != 7 {
+		t.Fatalf("EnsurePR again = %+v, %v, %v", pr, created, err)
+	}
+	if fake.title != "Fix login v2 (gt-abc)" {
+		t.Errorf("title not updated: %q", fake.title)
+	}
+
+	// One status pending, one check run failed
+	fake.pull.MergeableState = "blocked"
+	fake.statuses = []map[string]string{{"context": "ci/lint", "state": "pending"}}
+	fake.checkRuns = []map[string]string{{"name": "test", "status": "completed", "conclusion": "failure"}}
+	pr, err = f.GetPR(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Checks != ChecksFailure || pr.Mergeable != Blocked || !reflect.DeepEqual(pr.FailedChecks, []string{"test"}) {
+		t.Errorf("failing PR = %+v", pr)
+	}
+
+	// Behind the base is a wait, not a conflict; a failed check run on a
+	// later page still counts
+	fake.pull.MergeableState = "behind"
+	fake.statuses = nil
+	fake.checkRuns = nil
+	for i := 0; i < checkRunsPerPage; i++ {
+		fake.checkRuns = append(fake.checkRuns, map[string]string{"name": "shard-" + strconv.Itoa(i), "status": "completed", "conclusion": "success"})
+	}
+	fake.checkRuns = append(fake.checkRuns, map[string]string{"name": "late", "status": "completed", "conclusion": "failure"})
+	pr, err = f.GetPR(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Mergeable != Behind || pr.Checks != ChecksFailure || !reflect.DeepEqual(pr.FailedChecks, []string{"late"}) {
+		t.Errorf("behind PR = %+v", pr)
+	}
+
+	// Refused merge wraps ErrNotMergeable
+	fake.mergeCode = http.StatusMethodNotAllowed
+	if _, err := f.MergePR(ctx, 7, MergeSquash, "abc123"); !errors.Is(err, ErrNotMergeable) {
+		t.Errorf("MergePR refused = %v", err)
+	}
+
+	fake.mergeCode = 0
+	sha, err := f.MergePR(ctx, 7, MergeSquash, "abc123")
+	if err != nil || sha != "merge456" {
+		t.Errorf("MergePR = %q, %v", sha, err)
+	}
+	if fake.merged["merge_method"] != "squash" || fake.merged["sha"] != "abc123" {
+		t.Errorf("merge body = %v", fake.merged)
+	}
+}
+
+func TestGitHub_AuthFailure(t *testing.T) {
+	_, f := newFakeGitHub(t)
+	t.Setenv("GITHUB_TOKEN", "")
+	f, _ = New(Config{Type: TypeGitHub, URL: f.(*gitHub).c.base}, "git@github.com:acme/widgets.git")
+	_, err := f.GetPR(context.Background(), 7)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized {
+		t.Errorf("err = %v, want 401", err)
+	}
+}
+
+func TestGitLab_MergeRequestMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		mr        string
+		state     PRState
+		mergeable Mergeability
+		checks    CheckState
+	}{
+		{"green", `{"iid":3,"state":"opened","detailed_merge_status":"mergeable","head_pipeline":{"status":"success"}}`, PROpen, Mergeable, ChecksSuccess},
+		{"running", `{"iid":3,"state":"opened","detailed_merge_status":"ci_still_running","head_pipeline":{"status":"running"}}`, PROpen, Mergeable, ChecksPending},
+		{"conflict", `{"iid":3,"state":"opened","has_conflicts":true,"detailed_merge_status":"mergeable"}`, PROpen, Conflicting, ChecksNone},
+		{"needs approval", `{"iid":3,"state":"opened","detailed_merge_status":"not_approved","head_pipeline":{"status":"success"}}`, PROpen, Blocked, ChecksSuccess},
+		{"failed", `{"iid":3,"state":"opened","detailed_merge_status":"ci_must_pass","head_pipeline":{"status":"failed"}}`, PROpen, Mergeable, ChecksFailure},
+		{"merged", `{"iid":3,"state":"merged","squash_commit_sha":"sq1"}`, PRMerged, MergeUnknown, ChecksNone},
+	}
+	for _, tt := range tests {
+		var mr glMergeRequest
+		if err := json.Unmarshal([]byte(tt.mr), &mr); err != nil {
+			t.Fatal(err)
+		}
+		pr := mr.toPR()
+		if pr.State != tt.state || pr.Mergeable != tt.mergeable || pr.Checks != tt.checks {
+			t.Errorf("%s: got %s/%s/%s", tt.name, pr.State, pr.Mergeable, pr.Checks)
+		}
+		if tt.state == PRMerged && pr.MergeCommit != "sq1" {
+			t.Errorf("%s: merge commit = %q", tt.name, pr.MergeCommit)
+		}
+	}
+}
+
+func TestGitLab_MergeRefused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasPrefix(r.URL.EscapedPath(), "/projects/group%2Fproj/merge_requests/3/merge") {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusNotAcceptable)
+		_, _ = w.Write([]byte(`{"message":"Branch cannot be merged"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("GITLAB_TOKEN", "glpat")
+	f, err := New(Config{Type: TypeGitLab, URL: srv.URL, Repo: "group/proj"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.MergePR(context.Background(), 3, MergeCommit, "abc")
+	if !errors.Is(err, ErrNotMergeable) || !strings.Contains(err.Error(), "Branch cannot be merged") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestGitea_FindAndStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token tea" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos/acme/widgets/pulls":
+			_, _ = w.Write([]byte(`[{"number":1,"head":{"ref":"other"},"base":{"ref":"main"}},
+				{"number":2,"head":{"ref":"polecat/nux"},"base":{"ref":"main"}}]`))
+		case "/repos/acme/widgets/pulls/2":
+			_, _ = w.Write([]byte(`{"number":2,"state":"open","mergeable":true,"head":{"ref":"polecat/nux","sha":"def"}}`))
+		case "/repos/acme/widgets/commits/def/status":
+			_, _ = w.Write([]byte(`{"statuses":[{"context":"ci","status":"success"},{"context":"lint","status":"pending"}]}`))
+		default:
+			t.Errorf("unexpected %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("GITEA_TOKEN", "tea")
+	f, err := New(Config{Type: TypeGitea, URL: srv.URL, Repo: "acme/widgets"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := f.FindPR(context.Background(), "polecat/nux", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr == nil || pr.Number != 2 || pr.Mergeable != Mergeable || pr.Checks != ChecksPending {
+		t.Errorf("pr = %+v", pr)
+	}
+	if pr, err := f.FindPR(context.Background(), "polecat/none", "main"); err != nil || pr != nil {
+		t.Errorf("missing PR = %+v, %v", pr, err)
+	}
+}
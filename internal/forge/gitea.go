@@ -0,0 +1,131 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// gitea implements Forge with the Gitea (and Forgejo) REST API.
+type gitea struct {
+	c    *client
+	repo string // owner/name
+}
+
+type giteaPull struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	Mergeable      bool   `json:"mergeable"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (g *gitea) Name() string { return TypeGitea }
+
+func (g *gitea) FindPR(ctx context.Context, head, base string) (*PR, error) {
+	// Gitea can't filter the list by branch, so scan open PRs
+	for page := 1; page <= 10; page++ {
+		var pulls []giteaPull
+		path := fmt.Sprintf("/repos/%s/pulls?state=open&limit=50&page=%d", g.repo, page)
+		if err := g.c.do(ctx, http.MethodGet, path, nil, &pulls); err != nil {
+			return nil, err
+		}
+		for _, p := range pulls {
+			if p.Head.Ref == head && p.Base.Ref == base {
+				return g.GetPR(ctx, p.Number)
+			}
+		}
+		if len(pulls) < 50 {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (g *gitea) CreatePR(ctx context.Context, req PRRequest) (*PR, error) {
+	body := map[string]string{"title": req.Title, "head": req.Head, "base": req.Base, "body": req.Body}
+	var pull giteaPull
+	if err := g.c.do(ctx, http.MethodPost, "/repos/"+g.repo+"/pulls", body, &pull); err != nil {
+		return nil, err
+	}
+	return g.GetPR(ctx, pull.Number)
+}
+
+func (g *gitea) UpdatePR(ctx context.Context, number int, req PRRequest) error {
+	body := map[string]string{"title": req.Title, "body": req.Body}
+	return g.c.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), body, nil)
+}
+
+func (g *gitea) GetPR(ctx context.Context, number int) (*PR, error) {
+	var pull giteaPull
+	if err := g.c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), nil, &pull); err != nil {
+		return nil, err
+	}
+	pr := &PR{Number: pull.Number, URL: pull.HTMLURL, HeadSHA: pull.Head.SHA}
+	switch {
+	case pull.Merged:
+		pr.State = PRMerged
+		pr.MergeCommit = pull.MergeCommitSHA
+	case pull.State == "closed":
+		pr.State = PRClosed
+	default:
+		pr.State = PROpen
+	}
+	// Gitea's mergeable flag only reflects conflicts; branch protection
+	// (reviews, required checks) surfaces as a refused merge.
+	pr.Mergeable = Conflicting
+	if pull.Mergeable {
+		pr.Mergeable = Mergeable
+	}
+
+	if pr.State == PROpen {
+		var status struct {
+			Statuses []struct {
+				Context string `json:"context"`
+				Status  string `json:"status"`
+			} `json:"statuses"`
+		}
+		if err := g.c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/status", g.repo, pr.HeadSHA), nil, &status); err != nil {
+			return nil, err
+		}
+		var states []CheckState
+		for _, s := range status.Statuses {
+			switch s.Status {
+			case "success", "warning":
+				states = append(states, ChecksSuccess)
+			case "pending":
+				states = append(states, ChecksPending)
+			default: // failure, error
+				states = append(states, ChecksFailure)
+				pr.FailedChecks = append(pr.FailedChecks, s.Context)
+			}
+		}
+		pr.Checks = combineChecks(states)
+	}
+	return pr, nil
+}
+
+func (g *gitea) MergePR(ctx context.Context, number int, method MergeMethod, headSHA string) (string, error) {
+	body := map[string]string{"Do": string(method), "head_commit_id": headSHA}
+	err := g.c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls/%d/merge", g.repo, number), body, nil)
+	if isStatus(err, http.StatusMethodNotAllowed, http.StatusConflict) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	// The merge response is empty; read the commit back
+	pr, err := g.GetPR(ctx, number)
+	if err != nil {
+		return "", fmt.Errorf("reading merged PR: %w", err)
+	}
+	return pr.MergeCommit, nil
+}
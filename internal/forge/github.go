@@ -0,0 +1,191 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitHub implements Forge with the GitHub REST API (also GitHub Enterprise).
+type gitHub struct {
+	c    *client
+	repo string // owner/name
+}
+
+type ghPull struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Head           struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+func (g *gitHub) Name() string { return TypeGitHub }
+
+func (g *gitHub) FindPR(ctx context.Context, head, base string) (*PR, error) {
+	owner := strings.SplitN(g.repo, "/", 2)[0]
+	q := url.Values{"state": {"open"}, "head": {owner + ":" + head}, "base": {base}}
+	var pulls []ghPull
+	if err := g.c.do(ctx, http.MethodGet, "/repos/"+g.repo+"/pulls?"+q.Encode(), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return g.GetPR(ctx, pulls[0].Number)
+}
+
+func (g *gitHub) CreatePR(ctx context.Context, req PRRequest) (*PR, error) {
+	body := map[string]string{"title": req.Title, "head": req.Head, "base": req.Base, "body": req.Body}
+	var pull ghPull
+	if err := g.c.do(ctx, http.MethodPost, "/repos/"+g.repo+"/pulls", body, &pull); err != nil {
+		return nil, err
+	}
+	return g.GetPR(ctx, pull.Number)
+}
+
+func (g *gitHub) UpdatePR(ctx context.Context, number int, req PRRequest) error {
+	body := map[string]string{"title": req.Title, "body": req.Body}
+	return g.c.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), body, nil)
+}
+
+func (g *gitHub) GetPR(ctx context.Context, number int) (*PR, error) {
+	var pull ghPull
+	if err := g.c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/pulls/%d", g.repo, number), nil, &pull); err != nil {
+		return nil, err
+	}
+	pr := &PR{
+		Number:  pull.Number,
+		URL:     pull.HTMLURL,
+		HeadSHA: pull.Head.SHA,
+	}
+	switch {
+	case pull.Merged:
+		pr.State = PRMerged
+		pr.MergeCommit = pull.MergeCommitSHA
+	case pull.State == "closed":
+		pr.State = PRClosed
+	default:
+		pr.State = PROpen
+	}
+
+	switch pull.MergeableState {
+	case "clean", "unstable", "has_hooks":
+		pr.Mergeable = Mergeable
+	case "dirty":
+		pr.Mergeable = Conflicting
+	case "behind":
+		pr.Mergeable = Behind
+	case "blocked", "draft":
+		pr.Mergeable = Blocked
+	default:
+		pr.Mergeable = MergeUnknown
+		if pull.Mergeable != nil && !*pull.Mergeable {
+			pr.Mergeable = Conflicting
+		}
+	}
+
+	if pr.State == PROpen {
+		if err := g.loadChecks(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+	return pr, nil
+}
+
+// loadChecks combines commit statuses and check runs on the head commit.
+func (g *gitHub) loadChecks(ctx context.Context, pr *PR) error {
+	var status struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s/status", g.repo, pr.HeadSHA), nil, &status); err != nil {
+		return err
+	}
+	runs, err := g.checkRuns(ctx, pr.HeadSHA)
+	if err != nil {
+		return err
+	}
+
+	var states []CheckState
+	for _, s := range status.Statuses {
+		switch s.State {
+		case "success":
+			states = append(states, ChecksSuccess)
+		case "pending":
+			states = append(states, ChecksPending)
+		default: // failure, error
+			states = append(states, ChecksFailure)
+			pr.FailedChecks = append(pr.FailedChecks, s.Context)
+		}
+	}
+	for _, r := range runs {
+		switch {
+		case r.Status != "completed":
+			states = append(states, ChecksPending)
+		case r.Conclusion == "success" || r.Conclusion == "neutral" || r.Conclusion == "skipped":
+			states = append(states, ChecksSuccess)
+		default:
+			states = append(states, ChecksFailure)
+			pr.FailedChecks = append(pr.FailedChecks, r.Name)
+		}
+	}
+	pr.Checks = combineChecks(states)
+	return nil
+}
+
+// ghCheckRun is one check run on a commit.
+type ghCheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// checkRunsPerPage is the page size used when listing check runs (the API
+// maximum).
+const checkRunsPerPage = 100
+
+// checkRuns lists every check run on a commit, following pages until
+// total_count runs have been read.
+func (g *gitHub) checkRuns(ctx context.Context, sha string) ([]ghCheckRun, error) {
+	var all []ghCheckRun
+	for page := 1; ; page++ {
+		var resp struct {
+			TotalCount int          `json:"total_count"`
+			CheckRuns  []ghCheckRun `json:"check_runs"`
+		}
+		path := fmt.Sprintf("/repos/%s/commits/%s/check-runs?per_page=%d&page=%d", g.repo, sha, checkRunsPerPage, page)
+		if err := g.c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.CheckRuns...)
+		if len(resp.CheckRuns) < checkRunsPerPage || len(all) >= resp.TotalCount {
+			return all, nil
+		}
+	}
+}
+
+func (g *gitHub) MergePR(ctx context.Context, number int, method MergeMethod, headSHA string) (string, error) {
+	body := map[string]string{"merge_method": string(method), "sha": headSHA}
+	var result struct {
+		SHA     string `json:"sha"`
+		Message string `json:"message"`
+	}
+	err := g.c.do(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/pulls/%d/merge", g.repo, number), body, &result)
+	if isStatus(err, http.StatusMethodNotAllowed, http.StatusConflict) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result.SHA, nil
+}
@@ -0,0 +1,133 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitLab implements Forge with the GitLab REST API (merge requests).
+type gitLab struct {
+	c       *client
+	project string // URL-escaped project path
+}
+
+type glMergeRequest struct {
+	IID                 int    `json:"iid"`
+	WebURL              string `json:"web_url"`
+	State               string `json:"state"`
+	SHA                 string `json:"sha"`
+	MergeCommitSHA      string `json:"merge_commit_sha"`
+	SquashCommitSHA     string `json:"squash_commit_sha"`
+	HasConflicts        bool   `json:"has_conflicts"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+	HeadPipeline        *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+func (g *gitLab) Name() string { return TypeGitLab }
+
+func (g *gitLab) path(format string, args ...interface{}) string {
+	return "/projects/" + g.project + fmt.Sprintf(format, args...)
+}
+
+func (g *gitLab) FindPR(ctx context.Context, head, base string) (*PR, error) {
+	q := url.Values{"state": {"opened"}, "source_branch": {head}, "target_branch": {base}}
+	var mrs []glMergeRequest
+	if err := g.c.do(ctx, http.MethodGet, g.path("/merge_requests?%s", q.Encode()), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return g.GetPR(ctx, mrs[0].IID)
+}
+
+func (g *gitLab) CreatePR(ctx context.Context, req PRRequest) (*PR, error) {
+	body := map[string]string{
+		"source_branch": req.Head,
+		"target_branch": req.Base,
+		"title":         req.Title,
+		"description":   req.Body,
+	}
+	var mr glMergeRequest
+	if err := g.c.do(ctx, http.MethodPost, g.path("/merge_requests"), body, &mr); err != nil {
+		return nil, err
+	}
+	return g.GetPR(ctx, mr.IID)
+}
+
+func (g *gitLab) UpdatePR(ctx context.Context, number int, req PRRequest) error {
+	body := map[string]string{"title": req.Title, "description": req.Body}
+	return g.c.do(ctx, http.MethodPut, g.path("/merge_requests/%d", number), body, nil)
+}
+
+func (g *gitLab) GetPR(ctx context.Context, number int) (*PR, error) {
+	var mr glMergeRequest
+	if err := g.c.do(ctx, http.MethodGet, g.path("/merge_requests/%d", number), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPR(), nil
+}
+
+func (mr *glMergeRequest) toPR() *PR {
+	pr := &PR{Number: mr.IID, URL: mr.WebURL, HeadSHA: mr.SHA}
+	switch mr.State {
+	case "merged":
+		pr.State = PRMerged
+		pr.MergeCommit = mr.MergeCommitSHA
+		if pr.MergeCommit == "" {
+			pr.MergeCommit = mr.SquashCommitSHA
+		}
+	case "closed", "locked":
+		pr.State = PRClosed
+	default:
+		pr.State = PROpen
+	}
+
+	switch mr.DetailedMergeStatus {
+	case "mergeable", "ci_must_pass", "ci_still_running":
+		// Pipeline state is reported through Checks
+		pr.Mergeable = Mergeable
+	case "conflict":
+		pr.Mergeable = Conflicting
+	case "need_rebase":
+		pr.Mergeable = Behind
+	case "", "checking", "unchecked", "preparing", "approvals_syncing":
+		pr.Mergeable = MergeUnknown
+	default: // not_approved, discussions_not_resolved, draft_status, ...
+		pr.Mergeable = Blocked
+	}
+	if mr.HasConflicts {
+		pr.Mergeable = Conflicting
+	}
+
+	pr.Checks = ChecksNone
+	if mr.HeadPipeline != nil {
+		switch mr.HeadPipeline.Status {
+		case "success", "skipped", "manual":
+			pr.Checks = ChecksSuccess
+		case "failed", "canceled":
+			pr.Checks = ChecksFailure
+			pr.FailedChecks = []string{"pipeline"}
+		default: // created, pending, running, preparing, scheduled, ...
+			pr.Checks = ChecksPending
+		}
+	}
+	return pr
+}
+
+func (g *gitLab) MergePR(ctx context.Context, number int, method MergeMethod, headSHA string) (string, error) {
+	body := map[string]interface{}{"sha": headSHA, "squash": method == MergeSquash}
+	var mr glMergeRequest
+	err := g.c.do(ctx, http.MethodPut, g.path("/merge_requests/%d/merge", number), body, &mr)
+	if isStatus(err, http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict, http.StatusUnprocessableEntity) {
+		return "", fmt.Errorf("%w: %v", ErrNotMergeable, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return mr.toPR().MergeCommit, nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -250,6 +251,15 @@ func (g *Git) Push(remote, branch string, force bool) error {
 	return err
 }
 
+// PushWithLease force-pushes refspec to remote only if the remote's branch
+// is still at expect, so commits pushed to it by someone else since expect
+// was read are not overwritten. An empty expect requires the branch not to
+// exist on the remote.
+func (g *Git) PushWithLease(remote, refspec, branch, expect string) error {
+	_, err := g.run("push", "--force-with-lease=refs/heads/"+branch+":"+expect, remote, refspec)
+	return err
+}
+
 // Add stages files for commit.
 func (g *Git) Add(paths ...string) error {
 	args := append([]string{"add"}, paths...)
@@ -399,6 +409,32 @@ func (g *Git) MergeNoFF(branch, message string) error {
 	return err
 }
 
+// MergeNoCommit merges the given branch with --no-ff but stops before
+// committing, leaving the merge result in the index and working tree.
+func (g *Git) MergeNoCommit(branch string) error {
+	_, err := g.run("merge", "--no-ff", "--no-commit", branch)
+	return err
+}
+
+// WorktreeTree returns the hash of the tree the working directory holds:
+// the index written as a tree, provided the working files match the index
+// and there are no untracked files (ignored files don't count). It returns
+// "" when the working directory differs from the index.
+func (g *Git) WorktreeTree() (string, error) {
+	unstaged, err := g.run("diff", "--name-only")
+	if err != nil {
+		return "", err
+	}
+	untracked, err := g.run("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return "", err
+	}
+	if unstaged != "" || untracked != "" {
+		return "", nil
+	}
+	return g.run("write-tree")
+}
+
 // DeleteRemoteBranch deletes a branch on the remote.
 func (g *Git) DeleteRemoteBranch(remote, branch string) error {
 	_, err := g.run("push", remote, "--delete", branch)
@@ -890,6 +926,83 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FileDiff is one file's line counts in a diff.
+type FileDiff struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// DiffNumstat returns per-file line counts for the changes head makes since
+// it diverged from base (base...head). Renames are reported as delete + add.
+func (g *Git) DiffNumstat(base, head string) ([]FileDiff, error) {
+	out, err := g.run("diff", "--numstat", "--no-renames", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileDiff
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		fd := FileDiff{Path: parts[2]}
+		if parts[0] == "-" {
+			fd.Binary = true
+		} else {
+			fd.Added, _ = strconv.Atoi(parts[0])
+			fd.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		files = append(files, fd)
+	}
+	return files, nil
+}
+
+// AddedLine is a line added by a diff, with its line number in the new file.
+type AddedLine struct {
+	Num  int
+	Text string
+}
+
+// DiffAddedLines returns the lines head adds since it diverged from base,
+// keyed by file path.
+func (g *Git) DiffAddedLines(base, head string) (map[string][]AddedLine, error) {
+	out, err := g.run("diff", "-U0", "--no-renames", "--no-color", base+"..."+head)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string][]AddedLine)
+	file := ""
+	num := 0
+	// File headers only come between "diff --git" and the first hunk; inside
+	// a hunk, "--- " and "+++ " are removed and added lines starting "-- "
+	// and "++ ".
+	inHeader, prev := false, ""
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader = true
+		case inHeader && strings.HasPrefix(line, "+++ ") && strings.HasPrefix(prev, "--- "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@ "):
+			inHeader = false
+			// @@ -a,b +c,d @@: new-file lines start at c
+			if i := strings.Index(line, " +"); i >= 0 {
+				start := strings.SplitN(strings.Fields(line[i+2:])[0], ",", 2)[0]
+				num, _ = strconv.Atoi(start)
+			}
+		case !inHeader && strings.HasPrefix(line, "+") && file != "" && file != "/dev/null":
+			added[file] = append(added[file], AddedLine{Num: num, Text: line[1:]})
+			num++
+		}
+		prev = line
+	}
+	return added, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
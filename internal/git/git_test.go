@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -242,6 +243,87 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestDiffNumstatAndAddedLines(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, _ := g.Rev("HEAD")
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "deploy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "deploy", "app.yaml"), []byte("a: 1\nb: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("change"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := g.DiffNumstat(base, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffNumstat: %v", err)
+	}
+	got := make(map[string]FileDiff)
+	for _, f := range files {
+		got[f.Path] = f
+	}
+	if len(got) != 2 || got["README.md"].Added != 1 || got["deploy/app.yaml"].Added != 2 {
+		t.Errorf("DiffNumstat = %+v", files)
+	}
+
+	added, err := g.DiffAddedLines(base, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffAddedLines: %v", err)
+	}
+	if lines := added["deploy/app.yaml"]; len(lines) != 2 || lines[1] != (AddedLine{Num: 2, Text: "b: 2"}) {
+		t.Errorf("added[deploy/app.yaml] = %+v", lines)
+	}
+	if lines := added["README.md"]; len(lines) != 1 || lines[0] != (AddedLine{Num: 2, Text: "more"}) {
+		t.Errorf("added[README.md] = %+v", lines)
+	}
+}
+
+func TestDiffAddedLines_HeaderLookalikes(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	path := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(path, []byte("keep\n-- old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("notes"); err != nil {
+		t.Fatal(err)
+	}
+	base, _ := g.Rev("HEAD")
+
+	// Replacing "-- old" with "++ new" diffs as "--- old" then "+++ new"
+	if err := os.WriteFile(path, []byte("keep\n++ new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Commit("edit notes"); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := g.DiffAddedLines(base, "HEAD")
+	if err != nil {
+		t.Fatalf("DiffAddedLines: %v", err)
+	}
+	want := map[string][]AddedLine{"notes.md": {{Num: 2, Text: "++ new"}}}
+	if !reflect.DeepEqual(added, want) {
+		t.Errorf("DiffAddedLines = %+v, want %+v", added, want)
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -0,0 +1,232 @@
+// Package health keeps the time series of doctor check results recorded by
+// the daemon's continuous doctor runs, and tracks when each check last
+// changed state.
+package health
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Statuses as reported by `gt doctor --json`.
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+)
+
+// Sample is one check result from one run.
+type Sample struct {
+	Time    time.Time `json:"time"`
+	Check   string    `json:"check"` // Rig checks are <rig>/<check>
+	Status  string    `json:"status"`
+	Message string    `json:"message,omitempty"`
+}
+
+// CheckState is the current state of one check.
+type CheckState struct {
+	Status  string    `json:"status"`
+	Since   time.Time `json:"since"` // When the check entered Status
+	LastRun time.Time `json:"last_run"`
+	Message string    `json:"message,omitempty"`
+}
+
+// State is the latest known state of every recorded check.
+type State struct {
+	Checks  map[string]*CheckState `json:"checks"`
+	LastRun time.Time              `json:"last_run"`
+}
+
+// Transition is a check changing state between runs.
+type Transition struct {
+	Check   string    `json:"check"`
+	From    string    `json:"from"` // Empty the first time a check is seen
+	To      string    `json:"to"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Failed reports whether the check just started erroring.
+func (t Transition) Failed() bool {
+	return t.To == StatusError && t.From != StatusError
+}
+
+// Recovered reports whether an erroring check is no longer erroring.
+func (t Transition) Recovered() bool {
+	return t.From == StatusError && t.To != StatusError
+}
+
+// Dir returns where health data is kept.
+func Dir(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "doctor")
+}
+
+// HistoryFile returns the path of the sample time series (JSONL).
+func HistoryFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "history.jsonl")
+}
+
+// StateFile returns the path of the current check states.
+func StateFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "health.json")
+}
+
+// LoadState loads current check states. Returns empty state if none exists.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(StateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading health state: %w", err)
+		}
+	} else if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing health state: %w", err)
+	}
+	if state.Checks == nil {
+		state.Checks = make(map[string]*CheckState)
+	}
+	return state, nil
+}
+
+// SaveState saves current check states.
+func SaveState(townRoot string, state *State) error {
+	path := StateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating health directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling health state: %w", err)
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: check results only
+}
+
+// Record appends a run's samples to the history and updates state,
+// returning the checks whose status changed. Skipped checks are recorded
+// but don't change a check's state, so a check skipped behind a broken
+// dependency doesn't look like a recovery.
+func Record(townRoot string, samples []Sample) ([]Transition, error) {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := appendSamples(townRoot, samples); err != nil {
+		return nil, err
+	}
+
+	transitions := state.Apply(samples)
+	if err := SaveState(townRoot, state); err != nil {
+		return transitions, err
+	}
+	return transitions, nil
+}
+
+// Apply updates state with a run's samples and returns the transitions.
+func (s *State) Apply(samples []Sample) []Transition {
+	var transitions []Transition
+	for _, sample := range samples {
+		if sample.Time.After(s.LastRun) {
+			s.LastRun = sample.Time
+		}
+		cs := s.Checks[sample.Check]
+		if sample.Status == StatusSkipped {
+			if cs != nil {
+				cs.LastRun = sample.Time
+			}
+			continue
+		}
+		if cs == nil {
+			cs = &CheckState{}
+			s.Checks[sample.Check] = cs
+		}
+		if cs.Status != sample.Status {
+			transitions = append(transitions, Transition{
+				Check:   sample.Check,
+				From:    cs.Status,
+				To:      sample.Status,
+				At:      sample.Time,
+				Message: sample.Message,
+			})
+			cs.Status = sample.Status
+			cs.Since = sample.Time
+		}
+		cs.LastRun = sample.Time
+		cs.Message = sample.Message
+	}
+	return transitions
+}
+
+// appendSamples adds samples to the history file.
+func appendSamples(townRoot string, samples []Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	path := HistoryFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating health directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: check results only
+	if err != nil {
+		return fmt.Errorf("opening health history: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, sample := range samples {
+		if err := enc.Encode(sample); err != nil {
+			return fmt.Errorf("writing health history: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadHistory reads recorded samples, oldest first. With check set, only
+// that check's samples are returned. Malformed lines are skipped.
+func LoadHistory(townRoot, check string) ([]Sample, error) {
+	f, err := os.Open(HistoryFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading health history: %w", err)
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var s Sample
+		if json.Unmarshal([]byte(line), &s) != nil {
+			continue
+		}
+		if check != "" && s.Check != check {
+			continue
+		}
+		samples = append(samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading health history: %w", err)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, nil
+}
+
+// Transitions replays samples and returns every state change, oldest first.
+func Transitions(samples []Sample) []Transition {
+	state := &State{Checks: make(map[string]*CheckState)}
+	return state.Apply(samples)
+}
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordTransitions(t *testing.T) {
+	townRoot := t.TempDir()
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	run := func(at time.Time, statuses map[string]string) []Transition {
+		t.Helper()
+		var samples []Sample
+		for check, status := range statuses {
+			samples = append(samples, Sample{Time: at, Check: check, Status: status})
+		}
+		transitions, err := Record(townRoot, samples)
+		if err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		return transitions
+	}
+
+	// First run: every check is new
+	tr := run(t0, map[string]string{"a": StatusOK, "b": StatusOK})
+	if len(tr) != 2 {
+		t.Fatalf("expected 2 initial transitions, got %v", tr)
+	}
+
+	// No change
+	if tr := run(t0.Add(time.Minute), map[string]string{"a": StatusOK, "b": StatusOK}); len(tr) != 0 {
+		t.Errorf("expected no transitions, got %v", tr)
+	}
+
+	// a fails
+	tr = run(t0.Add(2*time.Minute), map[string]string{"a": StatusError, "b": StatusOK})
+	if len(tr) != 1 || tr[0].Check != "a" || !tr[0].Failed() {
+		t.Fatalf("expected a to fail, got %v", tr)
+	}
+
+	// Skipped doesn't count as recovery
+	if tr := run(t0.Add(3*time.Minute), map[string]string{"a": StatusSkipped}); len(tr) != 0 {
+		t.Errorf("expected skipped to keep state, got %v", tr)
+	}
+
+	// a recovers
+	tr = run(t0.Add(4*time.Minute), map[string]string{"a": StatusOK})
+	if len(tr) != 1 || !tr[0].Recovered() {
+		t.Fatalf("expected a to recover, got %v", tr)
+	}
+
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs := state.Checks["a"]; cs.Status != StatusOK || !cs.Since.Equal(t0.Add(4*time.Minute)) {
+		t.Errorf("state for a = %+v", cs)
+	}
+	if cs := state.Checks["b"]; !cs.Since.Equal(t0) {
+		t.Errorf("b should be OK since first run, got %+v", cs)
+	}
+	if !state.LastRun.Equal(t0.Add(4 * time.Minute)) {
+		t.Errorf("LastRun = %v", state.LastRun)
+	}
+
+	samples, err := LoadHistory(townRoot, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 5 {
+		t.Fatalf("expected 5 samples for a, got %d", len(samples))
+	}
+	if got := Transitions(samples); len(got) != 3 {
+		t.Errorf("expected 3 replayed transitions (new, fail, recover), got %v", got)
+	}
+}
+
+func TestTransitionFailed(t *testing.T) {
+	tests := []struct {
+		from, to  string
+		failed    bool
+		recovered bool
+	}{
+		{"", StatusError, true, false},
+		{StatusOK, StatusError, true, false},
+		{StatusWarning, StatusError, true, false},
+		{StatusError, StatusError, false, false},
+		{StatusError, StatusWarning, false, true},
+		{StatusOK, StatusWarning, false, false},
+	}
+	for _, tt := range tests {
+		tr := Transition{From: tt.from, To: tt.to}
+		if tr.Failed() != tt.failed || tr.Recovered() != tt.recovered {
+			t.Errorf("%q→%q: Failed=%v Recovered=%v", tt.from, tt.to, tr.Failed(), tr.Recovered())
+		}
+	}
+}
+
+func TestLoadStateMissing(t *testing.T) {
+	state, err := LoadState(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Checks) != 0 || !state.LastRun.IsZero() {
+		t.Errorf("expected empty state, got %+v", state)
+	}
+	samples, err := LoadHistory(t.TempDir(), "")
+	if err != nil || samples != nil {
+		t.Errorf("expected no history, got %v, %v", samples, err)
+	}
+}
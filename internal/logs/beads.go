@@ -0,0 +1,53 @@
+package logs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// BeadEntries returns the history recorded on a bead itself: creation,
+// dispatch, molecule attachment, merge request hold and merge, and close.
+// Entries whose timestamp is missing or unparseable are left out.
+func BeadEntries(issue *beads.Issue) []Entry {
+	var entries []Entry
+	add := func(ts, typ, actor, message string) {
+		t := parseBeadTime(ts)
+		if t.IsZero() {
+			return
+		}
+		e := NewEntry(t, SourceBeads, typ, actor, message)
+		e.Payload = map[string]interface{}{"bead": issue.ID}
+		entries = append(entries, e)
+	}
+
+	add(issue.CreatedAt, "created", issue.CreatedBy, fmt.Sprintf("%s created: %s", issue.ID, issue.Title))
+	if f := beads.ParseAttachmentFields(issue); f != nil {
+		if f.AttachedMolecule != "" {
+			add(f.AttachedAt, "attached", f.DispatchedBy, fmt.Sprintf("%s attached molecule %s", issue.ID, f.AttachedMolecule))
+		}
+	}
+	if f := beads.ParseMRFields(issue); f != nil {
+		if f.HeldCommit != "" {
+			add(f.HeldAt, "held", f.Rig+"/refinery", fmt.Sprintf("%s held at %s for convoy landing", issue.ID, f.HeldCommit))
+		}
+		if f.MergeCommit != "" {
+			add(issue.ClosedAt, "merged", f.Rig+"/refinery", fmt.Sprintf("%s merged %s to %s at %s", issue.ID, f.SourceIssue, f.Target, f.MergeCommit))
+			return entries
+		}
+	}
+	if issue.Status == "closed" {
+		add(issue.ClosedAt, "closed", issue.Assignee, fmt.Sprintf("%s closed", issue.ID))
+	}
+	return entries
+}
+
+func parseBeadTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
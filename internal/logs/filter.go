@@ -0,0 +1,114 @@
+package logs
+
+import (
+	"strings"
+	"time"
+)
+
+// Filter selects timeline entries. Zero fields match everything.
+type Filter struct {
+	Since time.Time
+	Until time.Time
+	Actor string   // Actor prefix, e.g. "gastown/" or "gastown/polecats/Toast"
+	Rig   string   // Rig name
+	Role  string   // mayor, deacon, dog, witness, refinery, crew, polecat, daemon
+	Types []string // Event types
+	Beads []string // Bead IDs the entry must mention (any of)
+}
+
+// Match reports whether the entry passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Actor != "" && !strings.HasPrefix(e.Actor, f.Actor) {
+		return false
+	}
+	if f.Rig != "" && e.Rig != f.Rig {
+		return false
+	}
+	if f.Role != "" && e.Role != f.Role {
+		return false
+	}
+	if len(f.Types) > 0 && !contains(f.Types, e.Type) {
+		return false
+	}
+	if len(f.Beads) > 0 {
+		for _, id := range f.Beads {
+			if Mentions(e, id) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Mentions reports whether an entry refers to a bead, in its message or
+// any payload value. Child beads count: "gt-abc.1" mentions "gt-abc".
+func Mentions(e Entry, id string) bool {
+	if id == "" {
+		return false
+	}
+	if mentionsID(e.Message, id) {
+		return true
+	}
+	for _, v := range e.Payload {
+		if valueMentions(v, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueMentions(v interface{}, id string) bool {
+	switch x := v.(type) {
+	case string:
+		return mentionsID(x, id)
+	case []interface{}:
+		for _, item := range x {
+			if valueMentions(item, id) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range x {
+			if valueMentions(item, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mentionsID finds id in text as a whole token, so "gt-ab" does not match
+// inside "gt-abc".
+func mentionsID(text, id string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], id)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(id)
+		if (start == 0 || !idChar(text[start-1])) && (end == len(text) || !idChar(text[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func idChar(c byte) bool {
+	return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/retention"
+)
+
+// tail reads lines appended to a source since the last poll.
+type tail struct {
+	src     Source
+	file    *os.File // Open across polls, so a rotated file can be drained
+	offset  int64
+	partial []byte // Unterminated last line, completed by a later write
+}
+
+// poll returns the entries from complete lines appended since the last
+// call. When the file is replaced (rotated), the rest of the old file is
+// read before the new one is read from the start; a file that shrank
+// (truncated) is reread from the start.
+func (t *tail) poll() []Entry {
+	data := t.partial
+	t.partial = nil
+	if t.file != nil {
+		if info, err := t.file.Stat(); err == nil && info.Size() < t.offset {
+			t.offset, data = 0, nil
+		}
+		data = append(data, t.read()...)
+		if retention.Replaced(t.file, t.src.Path) {
+			_ = t.file.Close()
+			t.file = nil
+			if len(data) > 0 && data[len(data)-1] != '\n' {
+				data = append(data, '\n') // The old file's last line is finished
+			}
+		}
+	}
+	if t.file == nil {
+		if f, err := os.Open(t.src.Path); err == nil {
+			t.file, t.offset = f, 0
+			data = append(data, t.read()...)
+		}
+	}
+
+	last := bytes.LastIndexByte(data, '\n')
+	t.partial = append([]byte(nil), data[last+1:]...)
+
+	var entries []Entry
+	for _, line := range bytes.Split(data[:last+1], []byte("\n")) {
+		if e, ok := t.src.Parse(string(line)); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// read returns what was appended to the open file since the offset.
+func (t *tail) read() []byte {
+	if _, err := t.file.Seek(t.offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, _ := io.ReadAll(t.file)
+	t.offset += int64(len(data))
+	return data
+}
+
+// Follow watches the sources from their current end, calling fn for each
+// new matching entry in time order, until ctx is done.
+func Follow(ctx context.Context, sources []Source, f Filter, interval time.Duration, fn func(Entry)) error {
+	tails := make([]*tail, len(sources))
+	for i, s := range sources {
+		tails[i] = &tail{src: s}
+		if f, err := os.Open(s.Path); err == nil {
+			tails[i].file = f
+			tails[i].offset, _ = f.Seek(0, io.SeekEnd)
+		}
+	}
+	defer func() {
+		for _, t := range tails {
+			if t.file != nil {
+				_ = t.file.Close()
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		var batch []Entry
+		for _, t := range tails {
+			for _, e := range t.poll() {
+				if f.Match(e) {
+					batch = append(batch, e)
+				}
+			}
+		}
+		Sort(batch)
+		for _, e := range batch {
+			fn(e)
+		}
+	}
+}
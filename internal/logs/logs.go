@@ -0,0 +1,290 @@
+// Package logs merges Gas Town's log sources into one queryable timeline.
+//
+// Each source is a line-oriented file under the town root:
+//   - events:  .events.jsonl (raw activity events)
+//   - townlog: logs/town.log (agent lifecycle)
+//   - daemon:  daemon/daemon.log (daemon heartbeat and recovery)
+//   - feed:    .feed.jsonl (curated feed, derived from events)
+//
+// Agents, the refinery included, run in tmux sessions and write no stdout
+// log of their own; their output is the session pane, which callers
+// capture live (SourcePane).
+//
+// Lines are parsed into Entries, filtered, and merged by time.
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/retention"
+)
+
+// Source names.
+const (
+	SourceEvents  = "events"
+	SourceTownlog = "townlog"
+	SourceDaemon  = "daemon"
+	SourceFeed    = "feed"
+	SourceBeads   = "beads" // A bead's own record (created, closed, ...)
+	SourcePane    = "pane"  // Captured agent session output
+)
+
+// Entry is one line of the merged timeline.
+type Entry struct {
+	Time    time.Time              `json:"ts"`
+	Source  string                 `json:"source"`
+	Type    string                 `json:"type,omitempty"`
+	Actor   string                 `json:"actor,omitempty"`
+	Rig     string                 `json:"rig,omitempty"`
+	Role    string                 `json:"role,omitempty"`
+	Message string                 `json:"message"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// NewEntry returns an entry with Rig and Role derived from the actor.
+func NewEntry(t time.Time, source, typ, actor, message string) Entry {
+	rig, role := ParseActor(actor)
+	return Entry{Time: t, Source: source, Type: typ, Actor: actor, Rig: rig, Role: role, Message: message}
+}
+
+// Source is a log file and the parser for its lines.
+type Source struct {
+	Name  string
+	Path  string
+	Parse func(line string) (Entry, bool)
+}
+
+// Sources returns the town's file sources. The feed is derived from
+// events, so DefaultSources leaves it out.
+func Sources(townRoot string) []Source {
+	return []Source{
+		{Name: SourceEvents, Path: filepath.Join(townRoot, events.EventsFile), Parse: ParseEvent},
+		{Name: SourceTownlog, Path: filepath.Join(townRoot, "logs", "town.log"), Parse: ParseTownlog},
+		{Name: SourceDaemon, Path: filepath.Join(townRoot, "daemon", "daemon.log"), Parse: ParseDaemon},
+		{Name: SourceFeed, Path: filepath.Join(townRoot, events.FeedFile), Parse: ParseFeed},
+	}
+}
+
+// DefaultSources are the sources queried when none are named.
+var DefaultSources = []string{SourceEvents, SourceTownlog, SourceDaemon}
+
+// SelectSources returns the named sources, or DefaultSources if names is
+// empty. Unknown names are an error.
+func SelectSources(all []Source, names []string) ([]Source, error) {
+	if len(names) == 0 {
+		names = DefaultSources
+	}
+	var out []Source
+	for _, name := range names {
+		found := false
+		for _, s := range all {
+			if s.Name == name {
+				out = append(out, s)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown log source %q", name)
+		}
+	}
+	return out, nil
+}
+
+// Read returns the source's entries that match f, including those in its
+// rotated segments. With tail > 0 it returns at most the last tail of
+// them, reading segments newest first only until it has enough; segments
+// rotated before f.Since are skipped. A missing file has no entries.
+func (s Source) Read(f Filter, tail int) ([]Entry, error) {
+	segs, err := retention.Segments(s.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if live, err := os.Open(s.Path); err == nil { //nolint:gosec // G304: path is a log under the town root
+		entries, err = s.parse(live, f)
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	for i := len(segs) - 1; i >= 0; i-- {
+		if tail > 0 && len(entries) >= tail {
+			break
+		}
+		// A segment rotated before since holds only older entries
+		if segs[i].Rotated.Before(f.Since) {
+			break
+		}
+		r, err := retention.OpenSegment(segs[i])
+		if err != nil {
+			return nil, err
+		}
+		older, err := s.parse(r, f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(older, entries...)
+	}
+	if tail > 0 && len(entries) > tail {
+		entries = entries[len(entries)-tail:]
+	}
+	return entries, nil
+}
+
+// parse returns the entries in r that match f, and closes r.
+func (s Source) parse(r io.ReadCloser, f Filter) ([]Entry, error) {
+	defer func() { _ = r.Close() }()
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if e, ok := s.Parse(scanner.Text()); ok && f.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Query reads the sources and returns the matching entries in time order.
+// With tail > 0 each source contributes at most its last tail entries,
+// enough for the caller to keep the last tail overall.
+func Query(sources []Source, f Filter, tail int) ([]Entry, error) {
+	var all []Entry
+	for _, s := range sources {
+		entries, err := s.Read(f, tail)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s log: %w", s.Name, err)
+		}
+		all = append(all, entries...)
+	}
+	Sort(all)
+	return all, nil
+}
+
+// Sort orders entries by time, keeping each source's own order for ties.
+func Sort(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+}
+
+// ParseActor derives the rig and role from an agent address:
+// "mayor", "deacon", "deacon/dogs/<name>", "<rig>/witness",
+// "<rig>/refinery", "<rig>/crew/<name>", "<rig>/polecats/<name>" or the
+// short polecat form "<rig>/<name>".
+func ParseActor(actor string) (rig, role string) {
+	parts := strings.Split(strings.TrimSuffix(actor, "/"), "/")
+	switch {
+	case actor == "":
+		return "", ""
+	case parts[0] == "mayor" || parts[0] == "daemon":
+		return "", parts[0]
+	case parts[0] == "deacon":
+		if len(parts) > 1 && parts[1] == "dogs" {
+			return "", "dog"
+		}
+		return "", "deacon"
+	case len(parts) == 1:
+		return "", ""
+	}
+	switch parts[1] {
+	case "witness", "refinery", "crew":
+		return parts[0], parts[1]
+	default:
+		return parts[0], "polecat"
+	}
+}
+
+// ParseEvent parses a line of .events.jsonl.
+func ParseEvent(line string) (Entry, bool) {
+	var ev events.Event
+	if err := json.Unmarshal([]byte(line), &ev); err != nil {
+		return Entry{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+	if err != nil {
+		return Entry{}, false
+	}
+	e := NewEntry(ts, SourceEvents, ev.Type, ev.Actor, payloadMessage(ev.Payload))
+	e.Payload = ev.Payload
+	return e, true
+}
+
+// ParseFeed parses a line of .feed.jsonl.
+func ParseFeed(line string) (Entry, bool) {
+	var ev feed.FeedEvent
+	if err := json.Unmarshal([]byte(line), &ev); err != nil {
+		return Entry{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+	if err != nil {
+		return Entry{}, false
+	}
+	e := NewEntry(ts, SourceFeed, ev.Type, ev.Actor, ev.Summary)
+	e.Payload = ev.Payload
+	return e, true
+}
+
+// ParseTownlog parses a town.log line:
+// "2025-12-26 15:30:45 [spawn] gastown/crew/max spawned for gt-xyz".
+// Timestamps are local time.
+func ParseTownlog(line string) (Entry, bool) {
+	if len(line) < 21 {
+		return Entry{}, false
+	}
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", line[:19], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	rest := line[20:]
+	end := strings.Index(rest, "] ")
+	if !strings.HasPrefix(rest, "[") || end < 0 {
+		return Entry{}, false
+	}
+	typ := rest[1:end]
+	actor, detail, _ := strings.Cut(rest[end+2:], " ")
+	return NewEntry(ts, SourceTownlog, typ, actor, detail), true
+}
+
+// ParseDaemon parses a daemon.log line, written with log.LstdFlags:
+// "2025/12/26 15:30:45 message". Timestamps are local time.
+func ParseDaemon(line string) (Entry, bool) {
+	if len(line) < 20 {
+		return Entry{}, false
+	}
+	ts, err := time.ParseInLocation("2006/01/02 15:04:05", line[:19], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	return NewEntry(ts, SourceDaemon, "", "daemon", line[20:]), true
+}
+
+// payloadMessage renders an event payload as "key=value" pairs.
+func payloadMessage(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := fmt.Sprint(payload[k])
+		if s, ok := payload[k].(string); ok && strings.ContainsAny(s, " \t") {
+			v = fmt.Sprintf("%q", s)
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
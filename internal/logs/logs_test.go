@@ -0,0 +1,319 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseActor(t *testing.T) {
+	tests := []struct {
+		actor, rig, role string
+	}{
+		{"mayor", "", "mayor"},
+		{"mayor/", "", "mayor"},
+		{"deacon", "", "deacon"},
+		{"deacon/dogs/rex", "", "dog"},
+		{"daemon", "", "daemon"},
+		{"gastown/witness", "gastown", "witness"},
+		{"gastown/refinery", "gastown", "refinery"},
+		{"gastown/crew/max", "gastown", "crew"},
+		{"gastown/polecats/Toast", "gastown", "polecat"},
+		{"gastown/Toast", "gastown", "polecat"},
+		{"", "", ""},
+		{"gt", "", ""},
+	}
+	for _, tt := range tests {
+		rig, role := ParseActor(tt.actor)
+		if rig != tt.rig || role != tt.role {
+			t.Errorf("ParseActor(%q) = %q, %q, want %q, %q", tt.actor, rig, role, tt.rig, tt.role)
+		}
+	}
+}
+
+func TestParsers(t *testing.T) {
+	e, ok := ParseEvent(`{"ts":"2025-12-26T15:30:45Z","source":"gt","type":"sling","actor":"mayor","payload":{"bead":"gt-abc","target":"gastown/polecats/Toast"},"visibility":"feed"}`)
+	if !ok || e.Type != "sling" || e.Role != "mayor" || e.Message != "bead=gt-abc target=gastown/polecats/Toast" {
+		t.Errorf("ParseEvent = %+v, %v", e, ok)
+	}
+
+	e, ok = ParseTownlog("2025-12-26 15:30:45 [spawn] gastown/polecats/Toast spawned for gt-abc")
+	if !ok || e.Type != "spawn" || e.Actor != "gastown/polecats/Toast" || e.Rig != "gastown" || e.Message != "spawned for gt-abc" {
+		t.Errorf("ParseTownlog = %+v, %v", e, ok)
+	}
+	if e.Time.Location() != time.Local || e.Time.Hour() != 15 {
+		t.Errorf("townlog time = %v, want local 15:30:45", e.Time)
+	}
+
+	e, ok = ParseDaemon("2025/12/26 15:30:45 Heartbeat complete")
+	if !ok || e.Role != "daemon" || e.Message != "Heartbeat complete" {
+		t.Errorf("ParseDaemon = %+v, %v", e, ok)
+	}
+
+	e, ok = ParseFeed(`{"ts":"2025-12-26T15:30:45Z","source":"gt","type":"done","actor":"gastown/Toast","summary":"Toast done"}`)
+	if !ok || e.Source != SourceFeed || e.Message != "Toast done" {
+		t.Errorf("ParseFeed = %+v, %v", e, ok)
+	}
+
+	for _, line := range []string{"", "not json", "2025-12-26 15:30:45 no brackets", "garbage line with no time"} {
+		if _, ok := ParseEvent(line); ok {
+			t.Errorf("ParseEvent(%q) accepted", line)
+		}
+		if _, ok := ParseTownlog(line); ok {
+			t.Errorf("ParseTownlog(%q) accepted", line)
+		}
+		if _, ok := ParseDaemon(line); ok {
+			t.Errorf("ParseDaemon(%q) accepted", line)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"gt-abc", true},
+		{"spawned for gt-abc", true},
+		{"closed gt-abc.", true},
+		{"step gt-abc.1 done", true},
+		{"gt-abcd", false},
+		{"xgt-abc", false},
+		{"gt-abc-2", false},
+		{"(gt-abc)", true},
+	}
+	for _, tt := range tests {
+		if got := Mentions(Entry{Message: tt.text}, "gt-abc"); got != tt.want {
+			t.Errorf("Mentions(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	e := Entry{Payload: map[string]interface{}{
+		"beads": []interface{}{"gt-x", "gt-abc"},
+	}}
+	if !Mentions(e, "gt-abc") {
+		t.Error("payload list not searched")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	base := time.Date(2025, 12, 26, 12, 0, 0, 0, time.UTC)
+	e := NewEntry(base, SourceEvents, "done", "gastown/polecats/Toast", "done with gt-abc")
+
+	tests := []struct {
+		name string
+		f    Filter
+		want bool
+	}{
+		{"empty", Filter{}, true},
+		{"since", Filter{Since: base.Add(time.Minute)}, false},
+		{"until", Filter{Until: base.Add(-time.Minute)}, false},
+		{"actor", Filter{Actor: "gastown/"}, true},
+		{"other actor", Filter{Actor: "beads/"}, false},
+		{"rig", Filter{Rig: "gastown"}, true},
+		{"role", Filter{Role: "refinery"}, false},
+		{"type", Filter{Types: []string{"sling", "done"}}, true},
+		{"other type", Filter{Types: []string{"sling"}}, false},
+		{"bead", Filter{Beads: []string{"gt-zzz", "gt-abc"}}, true},
+		{"other bead", Filter{Beads: []string{"gt-zzz"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_MergesSources(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, ".events.jsonl"),
+		`{"ts":"2025-12-26T10:00:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-abc"}}`+"\n"+
+			`{"ts":"2025-12-26T10:02:00Z","type":"done","actor":"gastown/Toast","payload":{"bead":"gt-abc"}}`+"\n")
+	writeFile(t, filepath.Join(town, ".feed.jsonl"),
+		`{"ts":"2025-12-26T10:02:00Z","type":"done","actor":"gastown/Toast","summary":"done gt-abc"}`+"\n")
+	spawn := time.Date(2025, 12, 26, 10, 1, 0, 0, time.UTC).Local().Format("2006-01-02 15:04:05")
+	writeFile(t, filepath.Join(town, "logs", "town.log"),
+		spawn+" [spawn] gastown/polecats/Toast spawned for gt-abc\n"+
+			spawn+" [spawn] gastown/polecats/Nux spawned for gt-other\n")
+
+	sources, err := SelectSources(Sources(town), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Query(sources, Filter{Beads: []string{"gt-abc"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, e := range got {
+		types = append(types, e.Source+":"+e.Type)
+	}
+	want := []string{"events:sling", "townlog:spawn", "events:done"}
+	if len(types) != len(want) {
+		t.Fatalf("Query = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("Query = %v, want %v", types, want)
+		}
+	}
+
+	if _, err := SelectSources(Sources(town), []string{"nope"}); err == nil {
+		t.Error("unknown source accepted")
+	}
+}
+
+func TestRead_SinceAndTail(t *testing.T) {
+	town := t.TempDir()
+	path := filepath.Join(town, ".events.jsonl")
+	line := func(minute int, actor string) string {
+		return fmt.Sprintf(`{"ts":"2025-12-26T10:%02d:00Z","type":"sling","actor":%q}`+"\n", minute, actor)
+	}
+	// An unreadable old segment shows which segments were opened
+	writeFile(t, path+".20251226T090000Z.gz", "not gzip")
+	writeFile(t, path+".20251226T100200Z", line(1, "a")+line(2, "b"))
+	writeFile(t, path, line(3, "c")+line(4, "d"))
+	sources, _ := SelectSources(Sources(town), []string{SourceEvents})
+	src := sources[0]
+
+	actors := func(entries []Entry) string {
+		var s []string
+		for _, e := range entries {
+			s = append(s, e.Actor)
+		}
+		return strings.Join(s, ",")
+	}
+	got, err := src.Read(Filter{}, 3)
+	if err != nil || actors(got) != "b,c,d" {
+		t.Errorf("Read(tail 3) = %s, %v; want b,c,d", actors(got), err)
+	}
+	since := time.Date(2025, 12, 26, 10, 0, 0, 0, time.UTC)
+	got, err = src.Read(Filter{Since: since}, 0)
+	if err != nil || actors(got) != "a,b,c,d" {
+		t.Errorf("Read(since) = %s, %v; want a,b,c,d", actors(got), err)
+	}
+	if _, err := src.Read(Filter{}, 0); err == nil {
+		t.Error("Read of all segments skipped the corrupt one")
+	}
+}
+
+func TestBeadEntries(t *testing.T) {
+	issue := &beads.Issue{
+		ID:        "gt-mr-1",
+		Title:     "Merge: gt-abc",
+		Status:    "closed",
+		CreatedAt: "2025-12-26T10:00:00Z",
+		ClosedAt:  "2025-12-26T11:00:00Z",
+		Description: "branch: polecat/Toast\ntarget: main\nsource_issue: gt-abc\nrig: gastown\n" +
+			"merge_commit: abc123\n",
+	}
+	got := BeadEntries(issue)
+	if len(got) != 2 || got[0].Type != "created" || got[1].Type != "merged" || got[1].Actor != "gastown/refinery" {
+		t.Fatalf("BeadEntries = %+v", got)
+	}
+	if !Mentions(got[1], "gt-abc") || !Mentions(got[0], "gt-mr-1") {
+		t.Error("bead entries don't mention their beads")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	town := t.TempDir()
+	path := filepath.Join(town, ".events.jsonl")
+	writeFile(t, path, `{"ts":"2025-12-26T10:00:00Z","type":"old","actor":"mayor"}`+"\n")
+	sources, _ := SelectSources(Sources(town), []string{SourceEvents})
+
+	var mu sync.Mutex
+	var got []string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = Follow(ctx, sources, Filter{Types: []string{"new"}}, 10*time.Millisecond, func(e Entry) {
+			mu.Lock()
+			got = append(got, e.Actor)
+			mu.Unlock()
+		})
+	}()
+
+	seen := func(n int) bool {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			l := len(got)
+			mu.Unlock()
+			if l >= n {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return false
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	// A line split across writes is emitted once complete
+	appendFile(t, path, `{"ts":"2025-12-26T10:01:00Z","type":"new",`)
+	time.Sleep(30 * time.Millisecond)
+	appendFile(t, path, `"actor":"a"}`+"\n"+`{"ts":"2025-12-26T10:01:00Z","type":"skip","actor":"b"}`+"\n")
+	if !seen(1) {
+		t.Fatal("appended entry not followed")
+	}
+
+	// Truncation (rotation) restarts from the top of the new file
+	writeFile(t, path, `{"ts":"2025-12-26T10:02:00Z","type":"new","actor":"c"}`+"\n")
+	if !seen(2) {
+		t.Fatal("entry after truncation not followed")
+	}
+
+	// Lines written just before a rotation are read from the old file
+	appendFile(t, path, `{"ts":"2025-12-26T10:03:00Z","type":"new","actor":"d"}`+"\n")
+	if err := os.Rename(path, path+".20251226T100300Z"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, `{"ts":"2025-12-26T10:04:00Z","type":"new","actor":"e"}`+"\n")
+	if !seen(4) {
+		t.Fatal("entries around rotation not followed")
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"a", "c", "d", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("followed %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("followed %v, want %v", got, want)
+		}
+	}
+}
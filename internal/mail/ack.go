@@ -0,0 +1,279 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Labels for acknowledgement-required mail.
+const (
+	// LabelAckRequired marks a message that needs an explicit `gt mail ack`.
+	LabelAckRequired = "ack-required"
+
+	// LabelAckPending marks an ack-required message the daemon still tracks.
+	// Removed when the message is acked or escalated.
+	LabelAckPending = "ack-pending"
+
+	// LabelAcked marks a message that has been acknowledged.
+	LabelAcked = "acked"
+)
+
+// DefaultAckTimeout is how long a recipient has to ack before re-delivery,
+// and again before escalation.
+const DefaultAckTimeout = 30 * time.Minute
+
+// ErrAlreadyAcked indicates the message was already acknowledged.
+var ErrAlreadyAcked = errors.New("message already acknowledged")
+
+// ackLabels returns the labels that put a message under ack tracking.
+func ackLabels(msg *Message) []string {
+	if !msg.AckRequired {
+		return nil
+	}
+	deadline := msg.AckDeadline
+	if deadline == nil {
+		d := timeNow().Add(DefaultAckTimeout)
+		if msg.DeliverAfter != nil && msg.DeliverAfter.After(timeNow()) {
+			d = msg.DeliverAfter.Add(DefaultAckTimeout)
+		}
+		deadline = &d
+	}
+	return []string{
+		LabelAckRequired,
+		LabelAckPending,
+		"ack-deadline:" + deadline.UTC().Format(time.RFC3339),
+	}
+}
+
+// Ack acknowledges a message: records who acked and when, stops daemon
+// tracking, and marks the message read.
+func (m *Mailbox) Ack(id string) (*Message, error) {
+	msg, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.AckedBy != "" {
+		return msg, ErrAlreadyAcked
+	}
+
+	now := timeNow()
+	ackedBy := identityToAddress(m.identity)
+	msg.AckedBy = ackedBy
+	msg.AckedAt = &now
+	msg.Read = true
+
+	if m.legacy {
+		return msg, m.ackLegacy(msg)
+	}
+
+	for _, label := range []string{
+		LabelAcked,
+		"acked-by:" + ackedBy,
+		"acked-at:" + now.UTC().Format(time.RFC3339),
+	} {
+		if _, err := runBdCommand([]string{"label", "add", id, label}, m.workDir, m.beadsDir); err != nil {
+			return nil, fmt.Errorf("recording ack: %w", err)
+		}
+	}
+	if _, err := runBdCommand([]string{"label", "remove", id, LabelAckPending}, m.workDir, m.beadsDir); err != nil {
+		if bdErr, ok := err.(*bdError); !ok || !bdErr.ContainsError("does not have label") {
+			return nil, fmt.Errorf("clearing ack tracking: %w", err)
+		}
+	}
+
+	if err := m.closeInDir(id, m.beadsDir); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (m *Mailbox) ackLegacy(acked *Message) error {
+	messages, err := m.List()
+	if err != nil {
+		return err
+	}
+	for _, msg := range messages {
+		if msg.ID == acked.ID {
+			msg.Read = true
+			msg.AckedBy = acked.AckedBy
+			msg.AckedAt = acked.AckedAt
+		}
+	}
+	return m.rewriteLegacy(messages)
+}
+
+// AckResult summarizes one pass over outstanding acks.
+type AckResult struct {
+	Redelivered []*Message // First miss: re-delivered via interrupt
+	Escalated   []*Message // Second miss: escalated to witness or mayor
+	Escalations []string   // Escalation bead IDs, parallel to Escalated
+}
+
+// ProcessAcks checks outstanding ack-required messages.
+// After the ack deadline the message is re-delivered: reopened if it was
+// archived, and the recipient is interrupted. If the message is still not
+// acked one timeout later, an escalation bead is created and the recipient's
+// witness (or the mayor, for town-level agents and witnesses) is notified.
+func (r *Router) ProcessAcks(now time.Time) (*AckResult, error) {
+	beadsDir := r.resolveBeadsDir("")
+	workDir := filepath.Dir(beadsDir)
+	result := &AckResult{}
+	var errs []error
+
+	pending, err := r.listByMarker(beadsDir, LabelAckPending, true)
+	if err != nil {
+		return nil, fmt.Errorf("listing ack-pending mail: %w", err)
+	}
+
+	for _, msg := range pending {
+		// Acked through a path that didn't clear tracking: just clean up
+		if msg.AckedBy != "" {
+			_, _ = runBdCommand([]string{"label", "remove", msg.ID, LabelAckPending}, workDir, beadsDir)
+			continue
+		}
+		if msg.AckDeadline == nil || now.Before(*msg.AckDeadline) {
+			continue
+		}
+
+		if msg.AckRedeliveredAt == nil {
+			if err := r.redeliverForAck(msg, now, workDir, beadsDir); err != nil {
+				errs = append(errs, fmt.Errorf("re-delivering %s: %w", msg.ID, err))
+				continue
+			}
+			result.Redelivered = append(result.Redelivered, msg)
+			continue
+		}
+
+		if now.Before(msg.AckRedeliveredAt.Add(ackTimeout(msg))) {
+			continue
+		}
+		escalationID, err := r.escalateMissedAck(msg, now, workDir, beadsDir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("escalating %s: %w", msg.ID, err))
+			continue
+		}
+		result.Escalated = append(result.Escalated, msg)
+		result.Escalations = append(result.Escalations, escalationID)
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%d ack operation(s) failed, first: %w", len(errs), errs[0])
+	}
+	return result, nil
+}
+
+// ackTimeout recovers the ack window from the message: the time between
+// delivery and the original deadline.
+func ackTimeout(msg *Message) time.Duration {
+	delivered := msg.Timestamp
+	if msg.DeliverAfter != nil && msg.DeliverAfter.After(delivered) {
+		delivered = *msg.DeliverAfter
+	}
+	if msg.AckDeadline == nil {
+		return DefaultAckTimeout
+	}
+	if d := msg.AckDeadline.Sub(delivered); d > 0 {
+		return d
+	}
+	return DefaultAckTimeout
+}
+
+// redeliverForAck puts a missed message back in front of the recipient.
+func (r *Router) redeliverForAck(msg *Message, now time.Time, workDir, beadsDir string) error {
+	if msg.Read {
+		// Archived (closed) or marked read: put it back in the unread inbox.
+		// Both are best-effort since only one of the two states applies.
+		_, _ = runBdCommand([]string{"reopen", msg.ID}, workDir, beadsDir)
+		_, _ = runBdCommand([]string{"label", "remove", msg.ID, "read"}, workDir, beadsDir)
+	}
+	label := "ack-redelivered:" + now.UTC().Format(time.RFC3339)
+	if _, err := runBdCommand([]string{"label", "add", msg.ID, label}, workDir, beadsDir); err != nil {
+		return fmt.Errorf("recording re-delivery: %w", err)
+	}
+
+	notification := fmt.Sprintf("📬 ACK REQUIRED: message %s from %s (%s) has not been acknowledged. Run 'gt mail read %s' then 'gt mail ack %s'.",
+		msg.ID, msg.From, msg.Subject, msg.ID, msg.ID)
+	_ = r.nudgeAddress(msg.To, notification) // best-effort: mail is back in the inbox regardless
+	return nil
+}
+
+// escalateMissedAck records an escalation for a message that missed its
+// ack deadline twice and notifies the escalation target.
+func (r *Router) escalateMissedAck(msg *Message, now time.Time, workDir, beadsDir string) (string, error) {
+	target := ackEscalationTarget(msg.To)
+	title := fmt.Sprintf("Unacknowledged mail to %s: %s", msg.To, msg.Subject)
+	reason := fmt.Sprintf("%s did not ack %s from %s after re-delivery", msg.To, msg.ID, msg.From)
+
+	severity := "medium"
+	if msg.Priority == PriorityUrgent || msg.Priority == PriorityHigh {
+		severity = "high"
+	}
+
+	bd := beads.NewWithBeadsDir(workDir, beadsDir)
+	issue, err := bd.CreateEscalationBead(title, &beads.EscalationFields{
+		Severity:    severity,
+		Reason:      reason,
+		Source:      "mail:ack",
+		EscalatedBy: "daemon",
+		EscalatedAt: now.Format(time.RFC3339),
+		RelatedBead: msg.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating escalation bead: %w", err)
+	}
+
+	// Stop tracking before notifying so a failed send doesn't re-escalate
+	if _, err := runBdCommand([]string{"label", "remove", msg.ID, LabelAckPending}, workDir, beadsDir); err != nil {
+		return issue.ID, fmt.Errorf("clearing ack tracking: %w", err)
+	}
+	_, _ = runBdCommand([]string{"label", "add", msg.ID, "ack-escalated:" + issue.ID}, workDir, beadsDir)
+
+	notice := &Message{
+		From:     "daemon",
+		To:       target,
+		Subject:  fmt.Sprintf("[%s] ACK_MISSED %s", strings.ToUpper(severity), msg.To),
+		Body:     fmt.Sprintf("Escalation: %s\nMessage: %s\nFrom: %s\nTo: %s\nSubject: %s\nDeadline: %s\nRe-delivered: %s\n\n%s is not acknowledging mail. Check the session and nudge or restart it.", issue.ID, msg.ID, msg.From, msg.To, msg.Subject, msg.AckDeadline.Format(time.RFC3339), msg.AckRedeliveredAt.Format(time.RFC3339), msg.To),
+		Type:     TypeTask,
+		Priority: msg.Priority,
+		ReplyTo:  msg.ID,
+	}
+	if notice.Priority == "" || notice.Priority == PriorityLow {
+		notice.Priority = PriorityNormal
+	}
+	if err := r.Send(notice); err != nil {
+		return issue.ID, fmt.Errorf("notifying %s: %w", target, err)
+	}
+	return issue.ID, nil
+}
+
+// ackEscalationTarget returns who hears about a missed ack: the recipient's
+// rig witness, or the mayor for town-level agents and for witnesses themselves.
+func ackEscalationTarget(to string) string {
+	if isTownLevelAddress(to) {
+		return "mayor/"
+	}
+	identity := addressToIdentity(to)
+	rig, name, ok := strings.Cut(identity, "/")
+	if !ok || rig == "" || name == "" || name == "witness" {
+		return "mayor/"
+	}
+	return rig + "/witness"
+}
+
+// nudgeAddress injects text into the session for a mail address, if one is running.
+func (r *Router) nudgeAddress(address, text string) error {
+	sessionID := addressToSessionID(address)
+	if sessionID == "" {
+		return nil
+	}
+	hasSession, err := r.tmux.HasSession(sessionID)
+	if err != nil || !hasSession {
+		return nil
+	}
+	return r.tmux.NudgeSession(sessionID, text)
+}
@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAckLabelsRoundTrip(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	msg := NewMessage("mayor/", "gastown/Toast", "Fix it", "Please")
+	if labels := ackLabels(msg); labels != nil {
+		t.Fatalf("ackLabels on plain message = %v, want nil", labels)
+	}
+
+	msg.AckRequired = true
+	msg.AckDeadline = &deadline
+	labels := ackLabels(msg)
+
+	bm := BeadsMessage{ID: "hq-1", Assignee: "gastown/Toast",
+		Labels: append(labels, "acked-by:gastown/Toast", "acked-at:2026-03-01T12:10:00Z", "ack-redelivered:2026-03-01T12:31:00Z")}
+	got := bm.ToMessage()
+	if !got.AckRequired {
+		t.Error("AckRequired not parsed")
+	}
+	if got.AckDeadline == nil || !got.AckDeadline.Equal(deadline) {
+		t.Errorf("AckDeadline = %v, want %v", got.AckDeadline, deadline)
+	}
+	if got.AckedBy != "gastown/Toast" || got.AckedAt == nil {
+		t.Errorf("AckedBy/AckedAt = %q/%v", got.AckedBy, got.AckedAt)
+	}
+	if got.AckRedeliveredAt == nil {
+		t.Error("AckRedeliveredAt not parsed")
+	}
+	if !bm.HasLabel(LabelAckPending) {
+		t.Errorf("labels %v missing %q", labels, LabelAckPending)
+	}
+}
+
+func TestAckLabelsDefaultDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	origNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = origNow }()
+
+	msg := NewMessage("mayor/", "gastown/Toast", "Fix it", "Please")
+	msg.AckRequired = true
+	want := "ack-deadline:" + now.Add(DefaultAckTimeout).Format(time.RFC3339)
+
+	found := false
+	for _, l := range ackLabels(msg) {
+		found = found || l == want
+	}
+	if !found {
+		t.Errorf("ackLabels() = %v, want %q", ackLabels(msg), want)
+	}
+}
+
+func TestAckTimeout(t *testing.T) {
+	sent := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deadline := sent.Add(10 * time.Minute)
+	msg := &Message{Timestamp: sent, AckRequired: true, AckDeadline: &deadline}
+	if got := ackTimeout(msg); got != 10*time.Minute {
+		t.Errorf("ackTimeout = %v, want 10m", got)
+	}
+
+	// Scheduled delivery: window measured from delivery time
+	deliver := sent.Add(time.Hour)
+	deadline = deliver.Add(5 * time.Minute)
+	msg.DeliverAfter = &deliver
+	if got := ackTimeout(msg); got != 5*time.Minute {
+		t.Errorf("ackTimeout (scheduled) = %v, want 5m", got)
+	}
+
+	msg.AckDeadline = nil
+	if got := ackTimeout(msg); got != DefaultAckTimeout {
+		t.Errorf("ackTimeout (no deadline) = %v, want %v", got, DefaultAckTimeout)
+	}
+}
+
+func TestAckEscalationTarget(t *testing.T) {
+	tests := []struct {
+		to   string
+		want string
+	}{
+		{"gastown/Toast", "gastown/witness"},
+		{"gastown/polecats/Toast", "gastown/witness"},
+		{"gastown/crew/max", "gastown/witness"},
+		{"gastown/refinery", "gastown/witness"},
+		{"gastown/witness", "mayor/"},
+		{"mayor/", "mayor/"},
+		{"deacon/", "mayor/"},
+		{"overseer", "mayor/"},
+	}
+	for _, tt := range tests {
+		if got := ackEscalationTarget(tt.to); got != tt.want {
+			t.Errorf("ackEscalationTarget(%q) = %q, want %q", tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestMailboxAckLegacy(t *testing.T) {
+	dir := t.TempDir()
+	mb := NewMailbox(dir)
+	mb.identity = "gastown/Toast"
+
+	msg := NewMessage("mayor/", "gastown/Toast", "Fix it", "Please")
+	msg.AckRequired = true
+	if err := mb.Append(msg); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	acked, err := mb.Ack(msg.ID)
+	if err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if acked.AckedBy != "gastown/Toast" || acked.AckedAt == nil {
+		t.Errorf("acked = %+v", acked)
+	}
+
+	stored, err := mb.Get(msg.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !stored.Read || stored.AckedBy != "gastown/Toast" {
+		t.Errorf("stored Read=%v AckedBy=%q, want read and acked", stored.Read, stored.AckedBy)
+	}
+
+	if _, err := mb.Ack(msg.ID); !errors.Is(err, ErrAlreadyAcked) {
+		t.Errorf("second Ack err = %v, want ErrAlreadyAcked", err)
+	}
+}
+
+func TestSendAckToSharedAddress(t *testing.T) {
+	r := NewRouter(t.TempDir())
+	for _, to := range []string{"queue:work", "announce:alerts", "channel:builds"} {
+		msg := NewMessage("mayor/", to, "Heads up", "")
+		msg.AckRequired = true
+		if err := r.Send(msg); !errors.Is(err, ErrAckUnsupported) {
+			t.Errorf("Send(--ack to %s) = %v, want ErrAckUnsupported", to, err)
+		}
+	}
+}
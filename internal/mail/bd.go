@@ -2,8 +2,10 @@ package mail
 
 import (
 	"bytes"
-	"os/exec"
+	"os"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // bdError represents an error from running a bd command.
@@ -40,23 +42,24 @@ func (e *bdError) ContainsError(substr string) bool {
 // extraEnv contains additional environment variables to set (e.g., "BD_IDENTITY=...").
 // Returns stdout bytes on success, or a *bdError on failure.
 func runBdCommand(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
-	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
+	cmd := beads.Command(args...)
 	cmd.Dir = workDir
 
-	env := append(cmd.Environ(), "BEADS_DIR="+beadsDir)
-	env = append(env, extraEnv...)
-	cmd.Env = env
+	// Set BEADS_DIR even when empty, so an inherited one can't point the
+	// call at another database
+	env := append(os.Environ(), "BEADS_DIR="+beadsDir)
+	cmd.Env = append(env, extraEnv...)
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	stdout, err := cmd.Output()
+	if err != nil {
 		return nil, &bdError{
 			Err:    err,
 			Stderr: strings.TrimSpace(stderr.String()),
 		}
 	}
 
-	return stdout.Bytes(), nil
+	return stdout, nil
 }
@@ -0,0 +1,309 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// mailIndexVersion is bumped when the on-disk index format or tokenizer changes.
+// An index with a different version is discarded and rebuilt.
+const mailIndexVersion = 1
+
+// fullListInterval is how often a refresh lists every message instead of
+// only those updated since the last one. Only a full listing notices
+// messages deleted from beads.
+const fullListInterval = time.Hour
+
+// MailIndexPath returns the path to the town-wide mail index.
+func MailIndexPath(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), "mail-index.json")
+}
+
+// indexDoc is one indexed message.
+type indexDoc struct {
+	Msg       *Message       `json:"msg"`
+	UpdatedAt time.Time      `json:"updated_at,omitempty"` // bd updated_at, used to skip unchanged messages
+	Archived  bool           `json:"archived,omitempty"`   // closed in beads or only present in archive.jsonl
+	Subject   map[string]int `json:"subject,omitempty"`    // subject term frequencies
+	Body      map[string]int `json:"body,omitempty"`       // body term frequencies
+}
+
+// MailIndex is an inverted index over all mail in a town.
+// The index is refreshed incrementally from beads: a refresh lists only
+// messages updated since the newest one indexed, and messages whose
+// updated_at hasn't changed are not re-tokenized.
+// Documents and their term frequencies are persisted; postings are rebuilt
+// on load.
+type MailIndex struct {
+	path string
+
+	Version     int                  `json:"version"`
+	RefreshedAt time.Time            `json:"refreshed_at"`
+	ListedAt    time.Time            `json:"listed_at,omitempty"` // Last full listing
+	Docs        map[string]*indexDoc `json:"docs"`
+
+	postings map[string]map[string]bool // term -> doc IDs
+	terms    []string                   // sorted vocabulary for prefix lookup
+}
+
+// IndexStats reports what a refresh changed.
+type IndexStats struct {
+	Total    int // Documents in the index after refresh
+	Indexed  int // Documents added or re-tokenized
+	Removed  int // Documents dropped because they no longer exist
+	Archived int // Documents sourced only from archive.jsonl
+}
+
+// LoadMailIndex loads the index from disk.
+// A missing or outdated index yields an empty one.
+func LoadMailIndex(path string) (*MailIndex, error) {
+	idx := &MailIndex{
+		path:    path,
+		Version: mailIndexVersion,
+		Docs:    make(map[string]*indexDoc),
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			idx.rebuildPostings()
+			return idx, nil
+		}
+		return nil, err
+	}
+
+	var loaded MailIndex
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != mailIndexVersion || loaded.Docs == nil {
+		// Corrupt or stale format: start over, the next refresh rebuilds it
+		idx.rebuildPostings()
+		return idx, nil
+	}
+
+	loaded.path = path
+	loaded.rebuildPostings()
+	return &loaded, nil
+}
+
+// Save writes the index to disk atomically.
+func (idx *MailIndex) Save() error {
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(idx.path, idx)
+}
+
+// Len returns the number of indexed messages.
+func (idx *MailIndex) Len() int {
+	return len(idx.Docs)
+}
+
+// Refresh brings the index up to date with the town beads database and the
+// town mail archive. It lists only messages updated since the newest one
+// indexed, except every fullListInterval (or for an empty index), when it
+// lists them all and drops messages deleted from beads. Only messages that
+// are new or changed are re-tokenized.
+func (idx *MailIndex) Refresh(beadsDir string) (*IndexStats, error) {
+	args := []string{"list",
+		"--type", "message",
+		"--all",
+		"--limit=0",
+		"--json",
+	}
+	since := idx.highWater()
+	full := since.IsZero() || timeNow().Sub(idx.ListedAt) >= fullListInterval
+	if !full {
+		// A second of overlap: bd timestamps are second-granular, and
+		// re-listed messages that haven't changed are skipped
+		args = append(args, "--updated-after="+since.Add(-time.Second).Format(time.RFC3339))
+	}
+	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("listing messages: %w", err)
+	}
+
+	var beadsMsgs []BeadsMessage
+	if len(stdout) > 0 && string(stdout) != "null" {
+		if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+			return nil, fmt.Errorf("parsing messages: %w", err)
+		}
+	}
+
+	// Archive entries outlive their beads (announce pruning, purged wisps)
+	archive := NewMailboxWithBeadsDir("", filepath.Dir(beadsDir), beadsDir)
+	archived, err := archive.ListArchived()
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+
+	stats := idx.apply(beadsMsgs, archived, full)
+	idx.RefreshedAt = timeNow()
+	if full {
+		idx.ListedAt = idx.RefreshedAt
+	}
+	return stats, nil
+}
+
+// highWater returns the newest bd updated_at in the index, or zero if no
+// indexed message came from beads.
+func (idx *MailIndex) highWater() time.Time {
+	var newest time.Time
+	for _, doc := range idx.Docs {
+		if doc.UpdatedAt.After(newest) {
+			newest = doc.UpdatedAt
+		}
+	}
+	return newest
+}
+
+// apply merges a listing from beads plus archived messages into the index.
+// Only a full listing removes messages it doesn't include.
+func (idx *MailIndex) apply(beadsMsgs []BeadsMessage, archived []*Message, full bool) *IndexStats {
+	stats := &IndexStats{}
+	live := make(map[string]bool, len(beadsMsgs))
+
+	for i := range beadsMsgs {
+		bm := &beadsMsgs[i]
+		live[bm.ID] = true
+		closed := bm.Status == "closed"
+		if doc, ok := idx.Docs[bm.ID]; ok && !bm.UpdatedAt.IsZero() &&
+			doc.UpdatedAt.Equal(bm.UpdatedAt) && doc.Archived == closed {
+			continue
+		}
+		idx.put(bm.ToMessage(), bm.UpdatedAt, closed)
+		stats.Indexed++
+	}
+
+	for _, msg := range archived {
+		if msg.ID == "" || live[msg.ID] {
+			continue
+		}
+		live[msg.ID] = true
+		if _, ok := idx.Docs[msg.ID]; ok {
+			continue
+		}
+		msg.Read = true
+		idx.put(msg, time.Time{}, true)
+		stats.Indexed++
+		stats.Archived++
+	}
+
+	for id := range idx.Docs {
+		if full && !live[id] {
+			delete(idx.Docs, id)
+			stats.Removed++
+		}
+	}
+
+	idx.rebuildPostings()
+	stats.Total = len(idx.Docs)
+	return stats
+}
+
+// put adds or replaces a document.
+func (idx *MailIndex) put(msg *Message, updatedAt time.Time, archived bool) {
+	idx.Docs[msg.ID] = &indexDoc{
+		Msg:       msg,
+		UpdatedAt: updatedAt,
+		Archived:  archived,
+		Subject:   termFrequencies(msg.Subject),
+		Body:      termFrequencies(msg.Body),
+	}
+}
+
+// rebuildPostings derives the term -> document postings and the sorted
+// vocabulary from the stored per-document term frequencies.
+func (idx *MailIndex) rebuildPostings() {
+	idx.postings = make(map[string]map[string]bool)
+	for id, doc := range idx.Docs {
+		for _, tf := range []map[string]int{doc.Subject, doc.Body} {
+			for term := range tf {
+				if idx.postings[term] == nil {
+					idx.postings[term] = make(map[string]bool)
+				}
+				idx.postings[term][id] = true
+			}
+		}
+	}
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+}
+
+// expand returns the vocabulary terms that start with prefix.
+func (idx *MailIndex) expand(prefix string) []string {
+	i := sort.SearchStrings(idx.terms, prefix)
+	var out []string
+	for ; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		out = append(out, idx.terms[i])
+	}
+	return out
+}
+
+// tokenize splits text into lowercase alphanumeric terms.
+// Characters like '-', '_' and '.' are kept inside a term so identifiers
+// such as "gt-abc12" and "MERGE_READY" survive as single tokens.
+func tokenize(text string) []string {
+	isPart := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.'
+	}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isPart(r) })
+	terms := fields[:0]
+	for _, f := range fields {
+		f = strings.Trim(f, "-_.")
+		if f != "" {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// termFrequencies counts the terms in text.
+func termFrequencies(text string) map[string]int {
+	tf := make(map[string]int)
+	for _, term := range tokenize(text) {
+		tf[term]++
+	}
+	if len(tf) == 0 {
+		return nil
+	}
+	return tf
+}
+
+// RefreshIndex loads the town mail index, refreshes it from beads and saves
+// it. With rebuild, the existing index is discarded first.
+// If the refresh fails, the loaded (possibly stale) index is returned along
+// with the error so callers can decide whether to use it.
+func (r *Router) RefreshIndex(rebuild bool) (*MailIndex, *IndexStats, error) {
+	root := r.townRoot
+	if root == "" {
+		root = r.workDir
+	}
+
+	idx, err := LoadMailIndex(MailIndexPath(root))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading mail index: %w", err)
+	}
+	if rebuild {
+		idx.Docs = make(map[string]*indexDoc)
+	}
+
+	stats, err := idx.Refresh(r.resolveBeadsDir(""))
+	if err != nil {
+		return idx, nil, fmt.Errorf("refreshing mail index: %w", err)
+	}
+	if err := idx.Save(); err != nil {
+		return idx, stats, fmt.Errorf("saving mail index: %w", err)
+	}
+	return idx, stats, nil
+}
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retention"
 	"github.com/steveyegge/gastown/internal/runtime"
 )
 
@@ -99,12 +100,50 @@ func (m *Mailbox) listBeads() ([]*Message, error) {
 		return nil, err
 	}
 
-	// Sort by timestamp (newest first)
-	sort.Slice(messages, func(i, j int) bool {
-		return messages[i].Timestamp.After(messages[j].Timestamp)
+	return visibleNewestFirst(messages), nil
+}
+
+// visibleNewestFirst drops scheduled mail that isn't due yet and mail that
+// has expired, and sorts the rest newest first. Visibility is time-based so
+// it holds even when the daemon is down.
+func visibleNewestFirst(messages []*Message) []*Message {
+	now := timeNow()
+	visible := messages[:0]
+	for _, msg := range messages {
+		if msg.IsScheduled(now) || msg.IsExpired(now) {
+			continue
+		}
+		visible = append(visible, msg)
+	}
+	sort.Slice(visible, func(i, j int) bool {
+		return visible[i].Timestamp.After(visible[j].Timestamp)
 	})
+	return visible
+}
 
-	return messages, nil
+// Select returns the mailbox's messages among msgs, as List would: open or
+// hooked mail assigned to it and open mail it is CC'd on, visible now,
+// newest first. It lets a caller that already holds the message beads
+// (the daemon's read model) skip the bd queries.
+func (m *Mailbox) Select(msgs []BeadsMessage) []*Message {
+	identities := m.identityVariants()
+	var messages []*Message
+	for i := range msgs {
+		bm := &msgs[i]
+		mine := false
+		for _, identity := range identities {
+			switch {
+			case bm.Assignee == identity && (bm.Status == "open" || bm.Status == "hooked"):
+				mine = true
+			case bm.Status == "open" && slices.Contains(bm.Labels, "cc:"+identity):
+				mine = true
+			}
+		}
+		if mine {
+			messages = append(messages, bm.ToMessage())
+		}
+	}
+	return visibleNewestFirst(messages)
 }
 
 // listFromDir queries messages from a beads directory.
@@ -551,11 +590,12 @@ func (m *Mailbox) appendToArchive(msg *Message) error {
 	return err
 }
 
-// ListArchived returns all messages in the archive file.
+// ListArchived returns all messages in the archive file, including its
+// rotated segments.
 func (m *Mailbox) ListArchived() ([]*Message, error) {
 	archivePath := m.ArchivePath()
 
-	file, err := os.Open(archivePath)
+	file, err := retention.Open(archivePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -603,7 +643,7 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		if err := os.Remove(m.ArchivePath()); err != nil && !os.IsNotExist(err) {
 			return 0, err
 		}
-		return len(messages), nil
+		return len(messages), retention.RemoveSegments(m.ArchivePath())
 	}
 
 	// Filter by age
@@ -619,7 +659,8 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		}
 	}
 
-	// Rewrite archive with remaining messages
+	// Rewrite archive with remaining messages. Kept messages from rotated
+	// segments move into the live archive.
 	if len(keep) == 0 {
 		if err := os.Remove(m.ArchivePath()); err != nil && !os.IsNotExist(err) {
 			return 0, err
@@ -630,7 +671,7 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		}
 	}
 
-	return purged, nil
+	return purged, retention.RemoveSegments(m.ArchivePath())
 }
 
 func (m *Mailbox) rewriteArchive(messages []*Message) error {
@@ -660,79 +701,6 @@ func (m *Mailbox) rewriteArchive(messages []*Message) error {
 	return os.Rename(tmpPath, archivePath)
 }
 
-// SearchOptions specifies search parameters.
-type SearchOptions struct {
-	Query       string // Regex pattern to search for
-	FromFilter  string // Optional: only match messages from this sender
-	SubjectOnly bool   // Only search subject
-	BodyOnly    bool   // Only search body
-}
-
-// Search finds messages matching the given criteria.
-// Returns messages from both inbox and archive.
-// Query and FromFilter are treated as literal strings (not regex) to prevent ReDoS.
-func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
-	// Use QuoteMeta to escape special regex chars - prevents ReDoS attacks
-	// and provides intuitive literal string matching for users
-	re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(opts.Query))
-	if err != nil {
-		return nil, fmt.Errorf("invalid search pattern: %w", err)
-	}
-
-	var fromRe *regexp.Regexp
-	if opts.FromFilter != "" {
-		fromRe, err = regexp.Compile("(?i)" + regexp.QuoteMeta(opts.FromFilter))
-		if err != nil {
-			return nil, fmt.Errorf("invalid from pattern: %w", err)
-		}
-	}
-
-	// Get inbox messages
-	inbox, err := m.List()
-	if err != nil {
-		return nil, err
-	}
-
-	// Get archived messages
-	archived, err := m.ListArchived()
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-
-	// Combine and search
-	all := append(inbox, archived...)
-	var matches []*Message
-
-	for _, msg := range all {
-		// Apply from filter
-		if fromRe != nil && !fromRe.MatchString(msg.From) {
-			continue
-		}
-
-		// Search in specified fields
-		matched := false
-		if opts.SubjectOnly {
-			matched = re.MatchString(msg.Subject)
-		} else if opts.BodyOnly {
-			matched = re.MatchString(msg.Body)
-		} else {
-			// Search in both subject and body
-			matched = re.MatchString(msg.Subject) || re.MatchString(msg.Body)
-		}
-
-		if matched {
-			matches = append(matches, msg)
-		}
-	}
-
-	// Sort by timestamp (newest first)
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Timestamp.After(matches[j].Timestamp)
-	})
-
-	return matches, nil
-}
-
 // Count returns the total and unread message counts.
 func (m *Mailbox) Count() (total, unread int, err error) {
 	messages, err := m.List()
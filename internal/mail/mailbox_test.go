@@ -507,3 +507,29 @@ func TestMailboxLegacyMarkReadTwice(t *testing.T) {
 	}
 }
 
+
+func TestMailboxSelect(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	origNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = origNow }()
+
+	later := now.Add(time.Hour).Format(time.RFC3339)
+	msgs := []BeadsMessage{
+		{ID: "m1", Title: "Old", Assignee: "mayor", Status: "open", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "m2", Title: "New", Assignee: "mayor/", Status: "hooked", CreatedAt: now.Add(-time.Hour)},
+		{ID: "m3", Title: "CC", Assignee: "deacon/", Status: "open", CreatedAt: now.Add(-3 * time.Hour), Labels: []string{"cc:mayor/"}},
+		{ID: "m4", Title: "Read", Assignee: "mayor/", Status: "closed", CreatedAt: now},
+		{ID: "m5", Title: "Other", Assignee: "deacon/", Status: "open", CreatedAt: now},
+		{ID: "m6", Title: "Scheduled", Assignee: "mayor/", Status: "open", CreatedAt: now, Labels: []string{"deliver-after:" + later}},
+	}
+
+	got := NewMailboxBeads("mayor/", "/work/dir").Select(msgs)
+	var ids []string
+	for _, msg := range got {
+		ids = append(ids, msg.ID)
+	}
+	if want := "m2 m1 m3"; fmt.Sprint(ids) != "["+want+"]" {
+		t.Errorf("Select = %v, want [%s]", ids, want)
+	}
+}
@@ -0,0 +1,345 @@
+package mail
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchQuery is a parsed `gt mail search` query.
+//
+// Syntax: free-text terms plus field filters, all ANDed together.
+//
+//	from:<addr>        sender contains addr (case-insensitive)
+//	to:<addr>          recipient or CC contains addr
+//	type:<type>        task, scavenge, notification, reply
+//	thread:<id>        exact thread ID
+//	priority:<p>       urgent, high, normal, low (or 0-3)
+//	before:<when>      sent before a date (2006-01-02, RFC3339) or age (7d, 12h)
+//	after:<when>       sent after a date or age
+//	is:<state>         unread, read, archived, inbox
+//	"exact phrase"     phrase must appear verbatim
+//
+// Free-text terms match by prefix, so "handoff" also matches "handoffs".
+type SearchQuery struct {
+	Terms    []string // Lowercased free-text terms (prefix-matched)
+	Phrases  []string // Lowercased phrases (substring-matched)
+	From     string
+	To       string
+	Type     MessageType
+	Thread   string
+	Priority Priority
+	Before   *time.Time
+	After    *time.Time
+	Unread   *bool // is:unread / is:read
+	Archived *bool // is:archived / is:inbox
+}
+
+// ParseSearchQuery parses the query syntax described on SearchQuery.
+// Relative ages in before:/after: are resolved against now.
+func ParseSearchQuery(s string, now time.Time) (*SearchQuery, error) {
+	q := &SearchQuery{}
+	for _, tok := range splitQuery(s) {
+		if tok.quoted {
+			if p := strings.ToLower(strings.TrimSpace(tok.text)); p != "" {
+				q.Phrases = append(q.Phrases, p)
+			}
+			continue
+		}
+
+		key, value, hasKey := strings.Cut(tok.text, ":")
+		if !hasKey || value == "" || !isQueryField(key) {
+			q.Terms = append(q.Terms, tokenize(tok.text)...)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "from":
+			q.From = value
+		case "to":
+			q.To = value
+		case "type":
+			t := MessageType(strings.ToLower(value))
+			if ParseMessageType(string(t)) != t {
+				return nil, fmt.Errorf("unknown message type %q", value)
+			}
+			q.Type = t
+		case "thread":
+			q.Thread = value
+		case "priority":
+			p, err := parseQueryPriority(value)
+			if err != nil {
+				return nil, err
+			}
+			q.Priority = p
+		case "before", "after":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			if strings.ToLower(key) == "before" {
+				q.Before = &t
+			} else {
+				q.After = &t
+			}
+		case "is":
+			yes, no := true, false
+			switch strings.ToLower(value) {
+			case "unread":
+				q.Unread = &yes
+			case "read":
+				q.Unread = &no
+			case "archived":
+				q.Archived = &yes
+			case "inbox":
+				q.Archived = &no
+			default:
+				return nil, fmt.Errorf("unknown is: value %q (use unread, read, archived, inbox)", value)
+			}
+		}
+	}
+	return q, nil
+}
+
+// isQueryField reports whether key is a recognized field prefix.
+// Unrecognized prefixes (e.g. "re:" in a subject) are treated as text.
+func isQueryField(key string) bool {
+	switch strings.ToLower(key) {
+	case "from", "to", "type", "thread", "priority", "before", "after", "is":
+		return true
+	}
+	return false
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits on whitespace, keeping double-quoted phrases together.
+func splitQuery(s string) []queryToken {
+	var tokens []queryToken
+	var cur strings.Builder
+	inQuote := false
+	flush := func(quoted bool) {
+		if cur.Len() > 0 || quoted {
+			tokens = append(tokens, queryToken{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if inQuote {
+				flush(true)
+			} else {
+				flush(false)
+			}
+			inQuote = !inQuote
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			flush(false)
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush(inQuote)
+	return tokens
+}
+
+// parseQueryPriority accepts priority names or beads-style integers.
+func parseQueryPriority(s string) (Priority, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return PriorityFromInt(n), nil
+	}
+	p := Priority(strings.ToLower(s))
+	if ParsePriority(string(p)) != p {
+		return "", fmt.Errorf("unknown priority %q", s)
+	}
+	return p, nil
+}
+
+// parseQueryTime accepts a date, an RFC3339 timestamp, or an age such as
+// "7d" or "12h" (meaning that long before now).
+func parseQueryTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use 2006-01-02, RFC3339, or an age like 7d)", s)
+}
+
+// IndexSearchOptions scopes a search over the mail index.
+type IndexSearchOptions struct {
+	// Mailbox restricts results to messages addressed (To or CC) to this
+	// address. Empty searches all mailboxes.
+	Mailbox string
+
+	SubjectOnly bool // Only match text terms against subjects
+	BodyOnly    bool // Only match text terms against bodies
+	Limit       int  // Maximum results (0 = unlimited)
+}
+
+// SearchHit is a ranked search result.
+type SearchHit struct {
+	Message  *Message `json:"message"`
+	Score    float64  `json:"score"`
+	Archived bool     `json:"archived,omitempty"`
+}
+
+// Subject matches count more than body matches when ranking.
+const subjectWeight = 3.0
+
+// Search runs a query against the index. Scheduled mail that is not yet
+// due is left out.
+// Results are ranked by TF-IDF over the free-text terms (subject matches
+// weighted higher), then by recency. Queries with no text terms are ordered
+// newest first.
+func (idx *MailIndex) Search(q *SearchQuery, opts IndexSearchOptions) []*SearchHit {
+	// Resolve each text term to the vocabulary terms it prefixes
+	expanded := make([][]string, len(q.Terms))
+	for i, term := range q.Terms {
+		expanded[i] = idx.expand(term)
+		if len(expanded[i]) == 0 {
+			return nil // AND semantics: an unknown term matches nothing
+		}
+	}
+
+	mailbox := ""
+	if opts.Mailbox != "" {
+		mailbox = addressToIdentity(opts.Mailbox)
+	}
+
+	now := timeNow()
+	n := float64(len(idx.Docs))
+	var hits []*SearchHit
+	for _, doc := range idx.Docs {
+		if doc.Msg.IsScheduled(now) {
+			continue // Not delivered yet
+		}
+		if !q.matchesFilters(doc) || (mailbox != "" && !doc.addressedTo(mailbox)) {
+			continue
+		}
+		if !doc.matchesPhrases(q.Phrases, opts) {
+			continue
+		}
+
+		score := 0.0
+		matched := true
+		for _, variants := range expanded {
+			termScore := 0.0
+			for _, term := range variants {
+				idf := math.Log(1 + n/float64(len(idx.postings[term])))
+				if !opts.BodyOnly {
+					termScore += subjectWeight * float64(doc.Subject[term]) * idf
+				}
+				if !opts.SubjectOnly {
+					termScore += float64(doc.Body[term]) * idf
+				}
+			}
+			if termScore == 0 {
+				matched = false
+				break
+			}
+			score += termScore
+		}
+		if !matched {
+			continue
+		}
+
+		hits = append(hits, &SearchHit{Message: doc.Msg, Score: score, Archived: doc.Archived})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Message.Timestamp.After(hits[j].Message.Timestamp)
+	})
+
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits
+}
+
+// matchesFilters applies the field filters of a query.
+func (q *SearchQuery) matchesFilters(doc *indexDoc) bool {
+	msg := doc.Msg
+	if q.From != "" && !containsFold(msg.From, q.From) {
+		return false
+	}
+	if q.To != "" {
+		found := containsFold(msg.To, q.To)
+		for _, cc := range msg.CC {
+			found = found || containsFold(cc, q.To)
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Type != "" && msg.Type != q.Type {
+		return false
+	}
+	if q.Thread != "" && msg.ThreadID != q.Thread {
+		return false
+	}
+	if q.Priority != "" && msg.Priority != q.Priority {
+		return false
+	}
+	if q.Before != nil && !msg.Timestamp.Before(*q.Before) {
+		return false
+	}
+	if q.After != nil && !msg.Timestamp.After(*q.After) {
+		return false
+	}
+	if q.Unread != nil && msg.Read == *q.Unread {
+		return false
+	}
+	if q.Archived != nil && doc.Archived != *q.Archived {
+		return false
+	}
+	return true
+}
+
+// addressedTo reports whether the message was sent (To or CC) to identity.
+func (doc *indexDoc) addressedTo(identity string) bool {
+	if addressToIdentity(doc.Msg.To) == identity {
+		return true
+	}
+	for _, cc := range doc.Msg.CC {
+		if addressToIdentity(cc) == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPhrases reports whether every phrase appears in the searched fields.
+func (doc *indexDoc) matchesPhrases(phrases []string, opts IndexSearchOptions) bool {
+	for _, p := range phrases {
+		inSubject := !opts.BodyOnly && strings.Contains(strings.ToLower(doc.Msg.Subject), p)
+		inBody := !opts.SubjectOnly && strings.Contains(strings.ToLower(doc.Msg.Body), p)
+		if !inSubject && !inBody {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold is a case-insensitive substring match.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,233 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := ParseSearchQuery(`merge "tests failed" from:witness to:gastown/Toast type:task thread:thread-1 priority:high after:7d before:2026-03-09 is:unread Re:`, now)
+	if err != nil {
+		t.Fatalf("ParseSearchQuery: %v", err)
+	}
+
+	if len(q.Terms) != 2 || q.Terms[0] != "merge" || q.Terms[1] != "re" {
+		t.Errorf("Terms = %v, want [merge re]", q.Terms)
+	}
+	if len(q.Phrases) != 1 || q.Phrases[0] != "tests failed" {
+		t.Errorf("Phrases = %v, want [tests failed]", q.Phrases)
+	}
+	if q.From != "witness" || q.To != "gastown/Toast" || q.Thread != "thread-1" {
+		t.Errorf("From/To/Thread = %q/%q/%q", q.From, q.To, q.Thread)
+	}
+	if q.Type != TypeTask {
+		t.Errorf("Type = %q, want %q", q.Type, TypeTask)
+	}
+	if q.Priority != PriorityHigh {
+		t.Errorf("Priority = %q, want %q", q.Priority, PriorityHigh)
+	}
+	if q.After == nil || !q.After.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("After = %v, want %v", q.After, now.AddDate(0, 0, -7))
+	}
+	if q.Before == nil || !q.Before.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Before = %v", q.Before)
+	}
+	if q.Unread == nil || !*q.Unread {
+		t.Errorf("Unread = %v, want true", q.Unread)
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	now := time.Now()
+	for _, s := range []string{"type:bogus", "priority:extreme", "before:someday", "is:starred"} {
+		if _, err := ParseSearchQuery(s, now); err == nil {
+			t.Errorf("ParseSearchQuery(%q) expected error", s)
+		}
+	}
+}
+
+func newTestIndex(t *testing.T, msgs ...BeadsMessage) *MailIndex {
+	t.Helper()
+	idx, err := LoadMailIndex(filepath.Join(t.TempDir(), "mail-index.json"))
+	if err != nil {
+		t.Fatalf("LoadMailIndex: %v", err)
+	}
+	idx.apply(msgs, nil, true)
+	return idx
+}
+
+func hitIDs(hits []*SearchHit) []string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.Message.ID)
+	}
+	return ids
+}
+
+func TestMailIndexSearch(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	idx := newTestIndex(t,
+		BeadsMessage{ID: "hq-1", Title: "MERGE_FAILED nux", Description: "tests failed on main",
+			Assignee: "gastown/nux", Priority: 1, Status: "open", CreatedAt: base,
+			Labels: []string{"from:gastown/refinery", "msg-type:task", "thread:thread-a"}},
+		BeadsMessage{ID: "hq-2", Title: "Status", Description: "merge went fine, handoffs complete",
+			Assignee: "mayor/", Priority: 2, Status: "closed", CreatedAt: base.Add(time.Hour),
+			Labels: []string{"from:gastown/witness", "thread:thread-b"}},
+		BeadsMessage{ID: "hq-3", Title: "Handoff", Description: "context for next session",
+			Assignee: "gastown/nux", Priority: 2, Status: "open", CreatedAt: base.Add(2 * time.Hour),
+			Labels: []string{"from:gastown/nux", "cc:mayor/"}},
+		BeadsMessage{ID: "hq-4", Title: "Merge reminder", Description: "not due yet",
+			Assignee: "gastown/nux", Priority: 2, Status: "open", CreatedAt: base,
+			Labels: []string{"from:mayor/", "deliver-after:2099-01-01T00:00:00Z"}},
+	)
+
+	search := func(query string, opts IndexSearchOptions) []string {
+		t.Helper()
+		q, err := ParseSearchQuery(query, base.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("ParseSearchQuery(%q): %v", query, err)
+		}
+		return hitIDs(idx.Search(q, opts))
+	}
+
+	// Subject matches rank above body-only matches; scheduled mail is hidden
+	if got := search("merge", IndexSearchOptions{}); len(got) != 2 || got[0] != "hq-1" {
+		t.Errorf("merge: got %v, want hq-1 first of 2", got)
+	}
+	// Prefix matching
+	if got := search("handoff", IndexSearchOptions{}); len(got) != 2 {
+		t.Errorf("handoff: got %v, want 2 hits", got)
+	}
+	// AND semantics across terms
+	if got := search("merge tests", IndexSearchOptions{}); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("merge tests: got %v, want [hq-1]", got)
+	}
+	// Field filters
+	if got := search("type:task", IndexSearchOptions{}); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("type:task: got %v", got)
+	}
+	if got := search("is:archived", IndexSearchOptions{}); len(got) != 1 || got[0] != "hq-2" {
+		t.Errorf("is:archived: got %v", got)
+	}
+	if got := search("from:witness", IndexSearchOptions{}); len(got) != 1 || got[0] != "hq-2" {
+		t.Errorf("from:witness: got %v", got)
+	}
+	if got := search("before:2026-03-01T01:30:00Z", IndexSearchOptions{}); len(got) != 2 {
+		t.Errorf("before: got %v, want 2", got)
+	}
+	// Phrase match
+	if got := search(`"next session"`, IndexSearchOptions{}); len(got) != 1 || got[0] != "hq-3" {
+		t.Errorf("phrase: got %v", got)
+	}
+	// Field restriction
+	if got := search("merge", IndexSearchOptions{SubjectOnly: true}); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("merge subject-only: got %v", got)
+	}
+	// Mailbox scope includes CC; empty query sorts newest first
+	if got := search("", IndexSearchOptions{Mailbox: "mayor"}); len(got) != 2 || got[0] != "hq-3" {
+		t.Errorf("mayor mailbox: got %v, want [hq-3 hq-2]", got)
+	}
+	// Unknown term matches nothing
+	if got := search("zebra", IndexSearchOptions{}); len(got) != 0 {
+		t.Errorf("zebra: got %v", got)
+	}
+}
+
+func TestMailIndexIncrementalApply(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	msg := BeadsMessage{ID: "hq-1", Title: "alpha", Status: "open", UpdatedAt: updated}
+
+	idx := newTestIndex(t)
+	if stats := idx.apply([]BeadsMessage{msg}, nil, true); stats.Indexed != 1 {
+		t.Fatalf("first apply Indexed = %d, want 1", stats.Indexed)
+	}
+	if stats := idx.apply([]BeadsMessage{msg}, nil, true); stats.Indexed != 0 {
+		t.Errorf("unchanged apply Indexed = %d, want 0", stats.Indexed)
+	}
+
+	msg.Title = "beta"
+	msg.UpdatedAt = updated.Add(time.Minute)
+	if stats := idx.apply([]BeadsMessage{msg}, nil, true); stats.Indexed != 1 {
+		t.Errorf("changed apply Indexed = %d, want 1", stats.Indexed)
+	}
+	if len(idx.expand("alpha")) != 0 || len(idx.expand("beta")) != 1 {
+		t.Error("re-indexed message should replace old terms")
+	}
+
+	archived := []*Message{{ID: "hq-old", Subject: "gamma"}}
+	stats := idx.apply(nil, archived, true)
+	if stats.Removed != 1 || stats.Archived != 1 || stats.Total != 1 {
+		t.Errorf("stats = %+v, want 1 removed, 1 archived, 1 total", stats)
+	}
+
+	// Round-trip through disk
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadMailIndex(idx.path)
+	if err != nil {
+		t.Fatalf("LoadMailIndex: %v", err)
+	}
+	if loaded.Len() != 1 || len(loaded.expand("gamma")) != 1 {
+		t.Errorf("loaded index has %d docs, gamma postings %v", loaded.Len(), loaded.expand("gamma"))
+	}
+}
+
+func TestMailIndexRefreshListsSinceHighWater(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bd that records its arguments and prints the listing in list.json
+	bin := t.TempDir()
+	script := `#!/bin/sh
+dir="$(dirname "$0")"
+echo "$@" > "$dir/args"
+cat "$dir/list.json"
+`
+	if err := os.WriteFile(filepath.Join(bin, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	refresh := func(idx *MailIndex, list string) (string, *IndexStats) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(bin, "list.json"), []byte(list), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stats, err := idx.Refresh(beadsDir)
+		if err != nil {
+			t.Fatalf("Refresh: %v", err)
+		}
+		args, _ := os.ReadFile(filepath.Join(bin, "args"))
+		return string(args), stats
+	}
+
+	idx := newTestIndex(t)
+	args, stats := refresh(idx, `[{"id":"hq-1","title":"alpha","status":"open","updated_at":"2026-03-01T10:00:00Z"}]`)
+	if strings.Contains(args, "--updated-after") || stats.Total != 1 {
+		t.Fatalf("first refresh: args %q, stats %+v; want a full listing of 1", args, stats)
+	}
+
+	// Later refreshes list only what changed, and keep everything else
+	args, stats = refresh(idx, `[{"id":"hq-2","title":"beta","status":"open","updated_at":"2026-03-01T11:00:00Z"}]`)
+	if !strings.Contains(args, "--updated-after=2026-03-01T09:59:59Z") {
+		t.Errorf("second refresh args = %q, want --updated-after the newest message", args)
+	}
+	if stats.Total != 2 || stats.Removed != 0 {
+		t.Errorf("second refresh stats = %+v, want 2 total, none removed", stats)
+	}
+
+	// A full listing is due again after fullListInterval, and drops deletions
+	idx.ListedAt = idx.ListedAt.Add(-fullListInterval)
+	args, stats = refresh(idx, `[{"id":"hq-2","title":"beta","status":"open","updated_at":"2026-03-01T11:00:00Z"}]`)
+	if strings.Contains(args, "--updated-after") || stats.Removed != 1 || stats.Total != 1 {
+		t.Errorf("full refresh: args %q, stats %+v; want a full listing removing hq-1", args, stats)
+	}
+}
@@ -0,0 +1,340 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// LabelDeadLetter marks a queue message that exceeded its queue's max
+// deliveries. The dlq:<queue> label records which queue it came from.
+const LabelDeadLetter = "dead-letter"
+
+// leaseDeathGrace is how long a claim must have been held before a missing
+// claimant session counts as agent death. Covers sessions that are still
+// starting when they claim.
+const leaseDeathGrace = 2 * time.Minute
+
+// ErrNotClaimant indicates the caller does not hold the claim on a message.
+var ErrNotClaimant = errors.New("message is not claimed by caller")
+
+// QueueClaimLabels returns the labels that record a claim: who holds it,
+// when it started, when the lease lapses, and the delivery count.
+func QueueClaimLabels(claimant string, now time.Time, lease time.Duration, deliveries int) []string {
+	return []string{
+		"claimed-by:" + claimant,
+		"claimed-at:" + now.UTC().Format(time.RFC3339),
+		"lease-until:" + now.Add(lease).UTC().Format(time.RFC3339),
+		"deliveries:" + strconv.Itoa(deliveries),
+	}
+}
+
+// DeliveriesExhausted reports whether a queue message has been claimed
+// maxDeliveries times. Exhausted messages are not handed out again; once any
+// current claim ends they move to the dead-letter queue. Zero means unlimited.
+func DeliveriesExhausted(msg *Message, maxDeliveries int) bool {
+	return maxDeliveries > 0 && msg.Deliveries >= maxDeliveries
+}
+
+// leaseLapsed reports whether a claim has outlived its lease.
+// Claims made before leases existed have no lease-until label; their lease
+// is measured from claimed-at.
+func leaseLapsed(msg *Message, timeout time.Duration, now time.Time) bool {
+	if msg.LeaseUntil != nil {
+		return now.After(*msg.LeaseUntil)
+	}
+	if msg.ClaimedAt != nil {
+		return now.After(msg.ClaimedAt.Add(timeout))
+	}
+	return true
+}
+
+// claimantSessionName maps a claimant address to its tmux session.
+// Returns "" for addresses without a session (e.g. the overseer).
+func claimantSessionName(address string) string {
+	address = strings.TrimSuffix(address, "/")
+	switch address {
+	case "mayor":
+		return session.MayorSessionName()
+	case "deacon":
+		return session.DeaconSessionName()
+	}
+
+	parts := strings.Split(address, "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "crew":
+		return session.CrewSessionName(parts[0], parts[2])
+	case len(parts) == 3 && parts[1] == "polecats":
+		return session.PolecatSessionName(parts[0], parts[2])
+	case len(parts) == 2 && parts[1] == "witness":
+		return session.WitnessSessionName(parts[0])
+	case len(parts) == 2 && parts[1] == "refinery":
+		return session.RefinerySessionName(parts[0])
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return session.PolecatSessionName(parts[0], parts[1])
+	}
+	return ""
+}
+
+// claimantGone reports whether the claimant's session is known to be dead.
+// Unknown sessions and tmux errors count as alive.
+func (r *Router) claimantGone(address string) bool {
+	sessionName := claimantSessionName(address)
+	if sessionName == "" {
+		return false
+	}
+	alive, err := r.tmux.HasSession(sessionName)
+	return err == nil && !alive
+}
+
+// LeaseResult summarizes one pass over claimed queue messages.
+type LeaseResult struct {
+	Released     []*Message // Lease lapsed or claimant died: back in the queue
+	DeadLettered []*Message // Exceeded max deliveries: moved to the dead-letter queue
+}
+
+// ProcessQueueLeases returns claimed queue messages to their queue when the
+// lease lapsed or the claimant's session is gone. A message that has already
+// been delivered the queue's max deliveries is moved to the queue's
+// dead-letter queue instead, as is an unclaimed message that was released
+// after its last allowed delivery.
+// Queues are swept in the town beads and every rig's beads.
+func (r *Router) ProcessQueueLeases(now time.Time) (*LeaseResult, error) {
+	result := &LeaseResult{}
+	var errs []error
+	for _, beadsDir := range r.queueBeadsDirs() {
+		errs = append(errs, r.processQueueLeasesIn(beadsDir, now, result)...)
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%d lease operation(s) failed, first: %w", len(errs), errs[0])
+	}
+	return result, nil
+}
+
+// queueBeadsDirs returns the beads directories that can hold queues: the
+// town's, then each registered rig's (following redirects), without
+// duplicates.
+func (r *Router) queueBeadsDirs() []string {
+	dirs := []string{r.resolveBeadsDir("")}
+	if r.townRoot == "" {
+		return dirs
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(r.townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return dirs
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := map[string]bool{filepath.Clean(dirs[0]): true}
+	for _, name := range names {
+		dir := filepath.Clean(beads.ResolveBeadsDir(filepath.Join(r.townRoot, name)))
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// processQueueLeasesIn sweeps the queues of one beads database.
+func (r *Router) processQueueLeasesIn(beadsDir string, now time.Time, result *LeaseResult) []error {
+	workDir := filepath.Dir(beadsDir)
+	var errs []error
+
+	bd := beads.NewWithBeadsDir(workDir, beadsDir)
+	queues, err := bd.ListQueueBeads()
+	if err != nil {
+		return []error{fmt.Errorf("listing queues in %s: %w", beadsDir, err)}
+	}
+
+	for queueID, issue := range queues {
+		fields := beads.ParseQueueFields(issue.Description)
+		if fields.Name == "" {
+			continue
+		}
+
+		messages, err := r.listByMarker(beadsDir, "queue:"+fields.Name, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing queue %s: %w", fields.Name, err))
+			continue
+		}
+
+		deadLettered := 0
+		for _, msg := range messages {
+			exhausted := DeliveriesExhausted(msg, fields.MaxDeliveries)
+			if msg.ClaimedBy == "" && !exhausted {
+				continue
+			}
+			if msg.ClaimedBy != "" {
+				lapsed := leaseLapsed(msg, fields.VisibilityTimeout, now)
+				dead := !lapsed && msg.ClaimedAt != nil &&
+					now.Sub(*msg.ClaimedAt) > leaseDeathGrace && r.claimantGone(msg.ClaimedBy)
+				if !lapsed && !dead {
+					continue
+				}
+			}
+
+			if exhausted {
+				if err := r.deadLetter(msg, fields.Name, workDir, beadsDir); err != nil {
+					errs = append(errs, fmt.Errorf("dead-lettering %s: %w", msg.ID, err))
+					continue
+				}
+				deadLettered++
+				result.DeadLettered = append(result.DeadLettered, msg)
+				continue
+			}
+
+			if err := removeClaimLabels(msg, workDir, beadsDir); err != nil {
+				errs = append(errs, fmt.Errorf("releasing %s: %w", msg.ID, err))
+				continue
+			}
+			result.Released = append(result.Released, msg)
+		}
+
+		if deadLettered > 0 {
+			fields.DeadLetterCount += deadLettered
+			if err := bd.UpdateQueueFields(queueID, fields); err != nil {
+				errs = append(errs, fmt.Errorf("updating queue %s: %w", fields.Name, err))
+			}
+		}
+	}
+	return errs
+}
+
+// removeClaimLabels drops the claim and lease labels, returning the message
+// to its queue. The delivery count is kept.
+func removeClaimLabels(msg *Message, workDir, beadsDir string) error {
+	var labels []string
+	if msg.ClaimedBy != "" {
+		labels = append(labels, "claimed-by:"+msg.ClaimedBy)
+	}
+	if msg.ClaimedAt != nil {
+		labels = append(labels, "claimed-at:"+msg.ClaimedAt.UTC().Format(time.RFC3339))
+	}
+	if msg.LeaseUntil != nil {
+		labels = append(labels, "lease-until:"+msg.LeaseUntil.UTC().Format(time.RFC3339))
+	}
+	for _, label := range labels {
+		if err := removeLabel(msg.ID, label, workDir, beadsDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deadLetter moves a message from its queue to the queue's dead-letter queue.
+// The dlq label is added before the queue label is removed so a partial
+// failure never leaves the message in neither.
+func (r *Router) deadLetter(msg *Message, queue, workDir, beadsDir string) error {
+	for _, label := range []string{"dlq:" + queue, LabelDeadLetter} {
+		if _, err := runBdCommand([]string{"label", "add", msg.ID, label}, workDir, beadsDir); err != nil {
+			return err
+		}
+	}
+	if err := removeLabel(msg.ID, "queue:"+queue, workDir, beadsDir); err != nil {
+		return err
+	}
+	return removeClaimLabels(msg, workDir, beadsDir)
+}
+
+// removeLabel removes a label, treating an already-missing label as success.
+func removeLabel(id, label, workDir, beadsDir string) error {
+	_, err := runBdCommand([]string{"label", "remove", id, label}, workDir, beadsDir)
+	if err != nil {
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("does not have label") {
+			return nil
+		}
+	}
+	return err
+}
+
+// RenewLease extends the claimant's lease on a queue message to now+lease.
+// The returned message carries the new LeaseUntil.
+func (r *Router) RenewLease(id, claimant string, lease time.Duration, now time.Time) (*Message, error) {
+	beadsDir := r.resolveBeadsDir("")
+	workDir := filepath.Dir(beadsDir)
+
+	msg, err := NewMailboxWithBeadsDir("", workDir, beadsDir).Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Queue == "" {
+		return nil, fmt.Errorf("message %s is not a queue message", id)
+	}
+	if msg.ClaimedBy != claimant {
+		return nil, fmt.Errorf("%w: %s is held by %q", ErrNotClaimant, id, msg.ClaimedBy)
+	}
+
+	until := now.Add(lease)
+	label := "lease-until:" + until.UTC().Format(time.RFC3339)
+	if _, err := runBdCommand([]string{"label", "add", id, label}, workDir, beadsDir, "BD_ACTOR="+claimant); err != nil {
+		return nil, fmt.Errorf("recording lease: %w", err)
+	}
+	if msg.LeaseUntil != nil && !msg.LeaseUntil.Equal(until) {
+		old := "lease-until:" + msg.LeaseUntil.UTC().Format(time.RFC3339)
+		if err := removeLabel(id, old, workDir, beadsDir); err != nil {
+			return nil, fmt.Errorf("clearing old lease: %w", err)
+		}
+	}
+	msg.LeaseUntil = &until
+	return msg, nil
+}
+
+// ListDeadLetters returns the messages in a queue's dead-letter queue,
+// oldest first.
+func (r *Router) ListDeadLetters(queue string) ([]*Message, error) {
+	messages, err := r.listByMarker(r.resolveBeadsDir(""), "dlq:"+queue, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	return messages, nil
+}
+
+// RequeueDeadLetter moves a message from queue's dead-letter queue back
+// into the queue with a fresh delivery count.
+func (r *Router) RequeueDeadLetter(queue, id string) (*Message, error) {
+	beadsDir := r.resolveBeadsDir("")
+	workDir := filepath.Dir(beadsDir)
+
+	msg, err := NewMailboxWithBeadsDir("", workDir, beadsDir).Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.DeadLetterQueue != queue {
+		return nil, fmt.Errorf("message %s is not in the %s dead-letter queue", id, queue)
+	}
+
+	if _, err := runBdCommand([]string{"label", "add", id, "queue:" + queue}, workDir, beadsDir); err != nil {
+		return nil, err
+	}
+	labels := []string{"dlq:" + queue, LabelDeadLetter}
+	if msg.Deliveries > 0 {
+		labels = append(labels, "deliveries:"+strconv.Itoa(msg.Deliveries))
+	}
+	for _, label := range labels {
+		if err := removeLabel(id, label, workDir, beadsDir); err != nil {
+			return nil, err
+		}
+	}
+
+	msg.Queue = queue
+	msg.DeadLetterQueue = ""
+	msg.Deliveries = 0
+	return msg, nil
+}
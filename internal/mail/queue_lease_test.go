@@ -0,0 +1,138 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueueClaimLabelsRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	labels := QueueClaimLabels("gastown/polecats/Toast", now, 15*time.Minute, 2)
+
+	bm := BeadsMessage{ID: "hq-1", Assignee: "queue:work", Labels: append(labels, "queue:work")}
+	got := bm.ToMessage()
+	if got.ClaimedBy != "gastown/polecats/Toast" {
+		t.Errorf("ClaimedBy = %q", got.ClaimedBy)
+	}
+	if got.ClaimedAt == nil || !got.ClaimedAt.Equal(now) {
+		t.Errorf("ClaimedAt = %v, want %v", got.ClaimedAt, now)
+	}
+	if got.LeaseUntil == nil || !got.LeaseUntil.Equal(now.Add(15*time.Minute)) {
+		t.Errorf("LeaseUntil = %v, want %v", got.LeaseUntil, now.Add(15*time.Minute))
+	}
+	if got.Deliveries != 2 {
+		t.Errorf("Deliveries = %d, want 2", got.Deliveries)
+	}
+	if got.Queue != "work" || got.DeadLetterQueue != "" {
+		t.Errorf("Queue/DeadLetterQueue = %q/%q", got.Queue, got.DeadLetterQueue)
+	}
+}
+
+func TestDeadLetterLabelParsed(t *testing.T) {
+	bm := BeadsMessage{ID: "hq-1", Labels: []string{"dlq:work", LabelDeadLetter, "deliveries:5"}}
+	got := bm.ToMessage()
+	if got.DeadLetterQueue != "work" || got.Queue != "" || got.Deliveries != 5 {
+		t.Errorf("DeadLetterQueue/Queue/Deliveries = %q/%q/%d", got.DeadLetterQueue, got.Queue, got.Deliveries)
+	}
+}
+
+func TestLeaseLapsed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	longAgo := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		msg  *Message
+		want bool
+	}{
+		{"lease in future", &Message{ClaimedBy: "a", ClaimedAt: &longAgo, LeaseUntil: &future}, false},
+		{"lease in past", &Message{ClaimedBy: "a", ClaimedAt: &past, LeaseUntil: &past}, true},
+		{"legacy claim within timeout", &Message{ClaimedBy: "a", ClaimedAt: &past}, false},
+		{"legacy claim past timeout", &Message{ClaimedBy: "a", ClaimedAt: &longAgo}, true},
+		{"claim without timestamps", &Message{ClaimedBy: "a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leaseLapsed(tt.msg, 15*time.Minute, now); got != tt.want {
+				t.Errorf("leaseLapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveriesExhausted(t *testing.T) {
+	tests := []struct {
+		deliveries, max int
+		want            bool
+	}{
+		{0, 5, false},
+		{4, 5, false},
+		{5, 5, true},
+		{6, 5, true},
+		{100, 0, false}, // unlimited
+	}
+	for _, tt := range tests {
+		if got := DeliveriesExhausted(&Message{Deliveries: tt.deliveries}, tt.max); got != tt.want {
+			t.Errorf("DeliveriesExhausted(%d, %d) = %v, want %v", tt.deliveries, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestClaimantSessionName(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"mayor/", "hq-mayor"},
+		{"deacon", "hq-deacon"},
+		{"gastown/witness", "gt-gastown-witness"},
+		{"gastown/refinery", "gt-gastown-refinery"},
+		{"gastown/crew/max", "gt-gastown-crew-max"},
+		{"gastown/polecats/Toast", "gt-gastown-Toast"},
+		{"gastown/Toast", "gt-gastown-Toast"},
+		{"overseer", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := claimantSessionName(tt.address); got != tt.want {
+			t.Errorf("claimantSessionName(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestQueueBeadsDirs(t *testing.T) {
+	town := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		path := filepath.Join(town, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("mayor/rigs.json", `{"version":1,"rigs":{"gastown":{},"beads":{},"shared":{}}}`)
+	write("gastown/.beads/redirect", "mayor/rig/.beads")
+	write("shared/.beads/redirect", "../.beads") // Uses the town database
+
+	r := NewRouterWithTownRoot(town, town)
+	got := r.queueBeadsDirs()
+	want := []string{
+		filepath.Join(town, ".beads"),
+		filepath.Join(town, "beads", ".beads"),
+		filepath.Join(town, "gastown", "mayor", "rig", ".beads"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("queueBeadsDirs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("queueBeadsDirs()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -23,6 +23,10 @@ var ErrUnknownQueue = errors.New("unknown queue")
 // ErrUnknownAnnounce indicates an announce channel name was not found in configuration.
 var ErrUnknownAnnounce = errors.New("unknown announce channel")
 
+// ErrAckUnsupported indicates an ack was requested for a queue, announce
+// or channel message, which has no single recipient to acknowledge it.
+var ErrAckUnsupported = errors.New("acknowledgement requires a direct, list or group recipient")
+
 // Router handles message delivery via beads.
 // It routes messages to the correct beads database based on address:
 // - Town-level (mayor/, deacon/) -> {townRoot}/.beads
@@ -86,6 +90,12 @@ func parseAnnounceName(address string) string {
 	return strings.TrimPrefix(address, "announce:")
 }
 
+// AnnounceLabel returns the label carried by messages posted to an announce
+// channel. Readers of the channel filter on it.
+func AnnounceLabel(name string) string {
+	return "announce:" + name
+}
+
 // isChannelAddress returns true if the address uses channel:name syntax (beads-native channels).
 func isChannelAddress(address string) bool {
 	return strings.HasPrefix(address, "channel:")
@@ -511,6 +521,10 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	if msg.AckRequired && isSharedAddress(msg.To) {
+		return fmt.Errorf("%w: %s", ErrAckUnsupported, msg.To)
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -540,6 +554,12 @@ func (r *Router) Send(msg *Message) error {
 	return r.sendToSingle(msg)
 }
 
+// isSharedAddress reports whether a message to the address is stored once
+// for many readers (queue, announce or channel) rather than per recipient.
+func isSharedAddress(address string) bool {
+	return isQueueAddress(address) || isAnnounceAddress(address) || isChannelAddress(address)
+}
+
 // sendToGroup resolves a @group address and sends individual messages to each member.
 func (r *Router) sendToGroup(msg *Message) error {
 	group := parseGroupAddress(msg.To)
@@ -594,6 +614,8 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
+	labels = append(labels, ackLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
 	args := []string{"create", msg.Subject,
@@ -627,7 +649,8 @@ func (r *Router) sendToSingle(msg *Message) error {
 
 	// Notify recipient if they have an active session (best-effort notification)
 	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
-	if !isSelfMail(msg.From, msg.To) {
+	// Skip notification for scheduled mail (the daemon notifies on release)
+	if !isSelfMail(msg.From, msg.To) && !msg.IsScheduled(timeNow()) {
 		_ = r.notifyRecipient(msg)
 	}
 
@@ -704,6 +727,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
@@ -742,7 +766,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 
 // sendToAnnounce delivers a message to an announce channel (bulletin board).
 // Unlike sendToQueue, no claiming is supported - messages persist until retention limit.
-// ONE copy is stored in town-level beads with announce_channel metadata.
+// ONE copy is stored in town-level beads, labeled with AnnounceLabel.
 func (r *Router) sendToAnnounce(msg *Message) error {
 	announceName := parseAnnounceName(msg.To)
 
@@ -764,7 +788,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	// Build labels for from/thread/reply-to/cc plus announce metadata
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
-	labels = append(labels, "announce:"+announceName)
+	labels = append(labels, AnnounceLabel(announceName))
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
 	}
@@ -775,6 +799,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
 	// Use announce:<name> as assignee so queries can filter by channel
@@ -847,6 +872,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=channel:<name> -d <body>
 	// Use channel:<name> as assignee so queries can filter by channel
@@ -899,7 +925,7 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 	// Use bd list with labels filter to find messages with announce:<name> label
 	args := []string{"list",
 		"--type=message",
-		"--labels=announce:" + announceName,
+		"--labels=" + AnnounceLabel(announceName),
 		"--json",
 		"--limit=0", // Get all
 		"--sort=created",
@@ -957,20 +983,12 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 // Uses NudgeSession to add the notification to the agent's conversation history.
 // Supports mayor/, rig/polecat, and rig/refinery addresses.
 func (r *Router) notifyRecipient(msg *Message) error {
-	sessionID := addressToSessionID(msg.To)
-	if sessionID == "" {
-		return nil // Unable to determine session ID
-	}
-
-	// Check if session exists
-	hasSession, err := r.tmux.HasSession(sessionID)
-	if err != nil || !hasSession {
-		return nil // No active session, skip notification
-	}
-
 	// Send notification to the agent's conversation history
 	notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
-	return r.tmux.NudgeSession(sessionID, notification)
+	if msg.AckRequired {
+		notification += fmt.Sprintf(" Acknowledgement required: run 'gt mail ack %s' once handled.", msg.ID)
+	}
+	return r.nudgeAddress(msg.To, notification)
 }
 
 // addressToSessionID converts a mail address to a tmux session ID.
@@ -813,3 +813,56 @@ func TestExpandAnnounceNoTownRoot(t *testing.T) {
 		t.Errorf("expandAnnounce error = %v, want containing 'no town root'", err)
 	}
 }
+
+func TestSendAnnounceThenListByLabel(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"type": "messaging", "version": 1, "announces": {"alerts": {"readers": ["@town"]}}}`
+	if err := os.WriteFile(filepath.Join(town, "config", "messaging.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A bd that keeps the labels of the last created message and lists it
+	// for a --label filter it carries
+	bin := t.TempDir()
+	script := `#!/bin/sh
+state="$(dirname "$0")/labels"
+cmd="$1"; want=""
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--labels) echo "$2" > "$state" ;;
+	--label) want="$2" ;;
+	esac
+	shift
+done
+case "$cmd" in
+create) echo '{"id":"hq-1"}' ;;
+list)
+	labels=$(cat "$state" 2>/dev/null)
+	case ",$labels," in
+	*",$want,"*) echo '[{"id":"hq-1","title":"Freeze","issue_type":"message","status":"open","assignee":"announce:alerts","labels":["'"$(echo "$labels" | sed 's/,/","/g')"'"]}]' ;;
+	*) echo '[]' ;;
+	esac ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	r := NewRouterWithTownRoot(town, town)
+	if err := r.Send(&Message{From: "mayor/", To: "announce:alerts", Subject: "Freeze"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// The inbox's announce folder lists by this label
+	messages, err := r.ListByLabel(AnnounceLabel("alerts"))
+	if err != nil {
+		t.Fatalf("ListByLabel: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Subject != "Freeze" {
+		t.Errorf("announce folder = %+v, want the sent announcement", messages)
+	}
+}
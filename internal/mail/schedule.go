@@ -0,0 +1,145 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Marker labels for time-based delivery.
+// Labels like deliver-after:<ts> carry the timestamp, but bd cannot filter
+// by label prefix, so a fixed marker label lets the daemon find candidates
+// with a single query.
+const (
+	// LabelScheduled marks a message whose delivery is deferred.
+	// Removed when the daemon releases the message.
+	LabelScheduled = "scheduled"
+
+	// LabelExpiring marks a message that has an expiry time.
+	LabelExpiring = "expiring"
+)
+
+// scheduleLabels returns the labels that encode a message's delivery mode,
+// deferred delivery time and expiry time.
+func scheduleLabels(msg *Message) []string {
+	var labels []string
+	if msg.Delivery != "" && msg.Delivery != DeliveryQueue {
+		labels = append(labels, "delivery:"+string(msg.Delivery))
+	}
+	if msg.DeliverAfter != nil {
+		labels = append(labels, "deliver-after:"+msg.DeliverAfter.UTC().Format(time.RFC3339))
+		if msg.IsScheduled(timeNow()) {
+			labels = append(labels, LabelScheduled)
+		}
+	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, "expires-at:"+msg.ExpiresAt.UTC().Format(time.RFC3339), LabelExpiring)
+	}
+	return labels
+}
+
+// ScheduleResult summarizes one pass over scheduled and expiring mail.
+type ScheduleResult struct {
+	Released []*Message // Scheduled messages that became due
+	Expired  []*Message // Messages archived because they expired
+}
+
+// ProcessSchedule releases scheduled messages that are due and archives
+// messages that have expired. Released messages trigger the same recipient
+// notification as an immediate send. Pinned messages are never auto-archived.
+// Errors on individual messages are collected; the pass continues.
+func (r *Router) ProcessSchedule(now time.Time) (*ScheduleResult, error) {
+	beadsDir := r.resolveBeadsDir("")
+	workDir := filepath.Dir(beadsDir)
+	result := &ScheduleResult{}
+	var errs []error
+
+	scheduled, err := r.listByMarker(beadsDir, LabelScheduled, false)
+	if err != nil {
+		return nil, fmt.Errorf("listing scheduled mail: %w", err)
+	}
+	for _, msg := range scheduled {
+		if msg.IsScheduled(now) {
+			continue
+		}
+		if _, err := runBdCommand([]string{"label", "remove", msg.ID, LabelScheduled}, workDir, beadsDir); err != nil {
+			errs = append(errs, fmt.Errorf("releasing %s: %w", msg.ID, err))
+			continue
+		}
+		if msg.IsDirectMessage() && !msg.IsExpired(now) && !isSelfMail(msg.From, msg.To) {
+			_ = r.notifyRecipient(msg)
+		}
+		result.Released = append(result.Released, msg)
+	}
+
+	expiring, err := r.listByMarker(beadsDir, LabelExpiring, false)
+	if err != nil {
+		return result, fmt.Errorf("listing expiring mail: %w", err)
+	}
+	for _, msg := range expiring {
+		if !msg.IsExpired(now) || msg.Pinned {
+			continue
+		}
+		mailbox := NewMailboxWithBeadsDir(msg.To, workDir, beadsDir)
+		if err := mailbox.Archive(msg.ID); err != nil {
+			errs = append(errs, fmt.Errorf("archiving %s: %w", msg.ID, err))
+			continue
+		}
+		result.Expired = append(result.Expired, msg)
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%d schedule operation(s) failed, first: %w", len(errs), errs[0])
+	}
+	return result, nil
+}
+
+// ListByLabel returns open messages carrying label, newest first.
+// Used to browse queues (queue:<name>), channels (channel:<name>) and
+// announce channels (AnnounceLabel).
+func (r *Router) ListByLabel(label string) ([]*Message, error) {
+	messages, err := r.listByMarker(r.resolveBeadsDir(""), label, false)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+	return messages, nil
+}
+
+// listByMarker returns messages carrying the given marker label.
+// Only open messages are returned unless includeClosed is set.
+func (r *Router) listByMarker(beadsDir, label string, includeClosed bool) ([]*Message, error) {
+	args := []string{"list",
+		"--type", "message",
+		"--label", label,
+		"--json",
+	}
+	if includeClosed {
+		args = append(args, "--all", "--limit=0")
+	} else {
+		args = append(args, "--status", "open")
+	}
+
+	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var beadsMsgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+		if len(stdout) == 0 || string(stdout) == "null" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var messages []*Message
+	for _, bm := range beadsMsgs {
+		messages = append(messages, bm.ToMessage())
+	}
+	return messages, nil
+}
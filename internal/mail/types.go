@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -123,6 +124,43 @@ type Message struct {
 	// ClaimedAt is when the queue message was claimed.
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// LeaseUntil is when the current claim lapses unless renewed.
+	// The daemon returns messages with lapsed leases to their queue.
+	LeaseUntil *time.Time `json:"lease_until,omitempty"`
+
+	// Deliveries counts how many times the queue message has been claimed.
+	Deliveries int `json:"deliveries,omitempty"`
+
+	// DeadLetterQueue is the queue whose dead-letter queue holds this message.
+	// Set once the message exceeded the queue's max deliveries.
+	DeadLetterQueue string `json:"dead_letter_queue,omitempty"`
+
+	// DeliverAfter holds the message back until this time.
+	// Scheduled messages are hidden from the inbox until due; the daemon
+	// releases them and notifies the recipient.
+	DeliverAfter *time.Time `json:"deliver_after,omitempty"`
+
+	// ExpiresAt is when the message stops being relevant.
+	// Expired messages are hidden from the inbox and auto-archived by the daemon.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// AckRequired means the recipient must explicitly acknowledge the message
+	// with `gt mail ack`. Reading or archiving does not count as an ack.
+	AckRequired bool `json:"ack_required,omitempty"`
+
+	// AckDeadline is when the daemon re-delivers an unacknowledged message.
+	// A second miss escalates to the recipient's witness (or the mayor).
+	AckDeadline *time.Time `json:"ack_deadline,omitempty"`
+
+	// AckedBy is who acknowledged the message (empty if not acked).
+	AckedBy string `json:"acked_by,omitempty"`
+
+	// AckedAt is when the message was acknowledged.
+	AckedAt *time.Time `json:"acked_at,omitempty"`
+
+	// AckRedeliveredAt is when the daemon re-delivered after the first missed deadline.
+	AckRedeliveredAt *time.Time `json:"ack_redelivered_at,omitempty"`
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -142,7 +180,12 @@ func NewMessage(from, to, subject, body string) *Message {
 }
 
 // NewReplyMessage creates a reply message that inherits the thread from the original.
+// If the original has no thread, the reply starts a new one.
 func NewReplyMessage(from, to, subject, body string, original *Message) *Message {
+	threadID := original.ThreadID
+	if threadID == "" {
+		threadID = generateThreadID()
+	}
 	return &Message{
 		ID:        generateID(),
 		From:      from,
@@ -153,7 +196,7 @@ func NewReplyMessage(from, to, subject, body string, original *Message) *Message
 		Read:      false,
 		Priority:  PriorityNormal,
 		Type:      TypeReply,
-		ThreadID:  original.ThreadID,
+		ThreadID:  threadID,
 		ReplyTo:   original.ID,
 	}
 }
@@ -212,6 +255,16 @@ func (m *Message) IsClaimed() bool {
 	return m.ClaimedBy != ""
 }
 
+// IsScheduled returns true if delivery is deferred past the given time.
+func (m *Message) IsScheduled(now time.Time) bool {
+	return m.DeliverAfter != nil && now.Before(*m.DeliverAfter)
+}
+
+// IsExpired returns true if the message has expired at the given time.
+func (m *Message) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
+
 // Validate checks that the message has a valid routing configuration.
 // Returns an error if to, queue, and channel are not mutually exclusive.
 func (m *Message) Validate() error {
@@ -241,6 +294,15 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("claimed_at is only valid for queue messages")
 	}
 
+	if m.AckDeadline != nil && !m.AckRequired {
+		return fmt.Errorf("ack_deadline is only valid for ack-required messages")
+	}
+
+	// A message that expires before it is delivered would never be seen
+	if m.DeliverAfter != nil && m.ExpiresAt != nil && !m.ExpiresAt.After(*m.DeliverAfter) {
+		return fmt.Errorf("expires_at must be after deliver_after")
+	}
+
 	return nil
 }
 
@@ -276,7 +338,8 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, lease-until:X, deliveries:X, dlq:X, deliver-after:X, expires-at:X, delivery:X, ack-deadline:X, acked-by:X, acked-at:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -290,6 +353,20 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+
+	leaseUntil *time.Time // When the current claim lapses
+	deliveries int        // Number of times the queue message was claimed
+	deadLetter string     // Queue whose dead-letter queue holds the message
+
+	deliverAfter *time.Time // When a scheduled message becomes visible
+	expiresAt    *time.Time // When the message expires
+	delivery     string     // Delivery mode (queue or interrupt)
+
+	ackRequired    bool       // Recipient must run gt mail ack
+	ackDeadline    *time.Time // When the daemon re-delivers if not acked
+	ackedBy        string     // Who acknowledged
+	ackedAt        *time.Time // When acknowledged
+	ackRedelivered *time.Time // When the daemon re-delivered after the first miss
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -316,10 +393,50 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "lease-until:") {
+			bm.leaseUntil = parseLabelTime(strings.TrimPrefix(label, "lease-until:"))
+		} else if strings.HasPrefix(label, "deliveries:") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(label, "deliveries:")); err == nil {
+				bm.deliveries = n
+			}
+		} else if strings.HasPrefix(label, "dlq:") {
+			bm.deadLetter = strings.TrimPrefix(label, "dlq:")
+		} else if strings.HasPrefix(label, "deliver-after:") {
+			ts := strings.TrimPrefix(label, "deliver-after:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.deliverAfter = &t
+			}
+		} else if strings.HasPrefix(label, "expires-at:") {
+			ts := strings.TrimPrefix(label, "expires-at:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.expiresAt = &t
+			}
+		} else if strings.HasPrefix(label, "delivery:") {
+			bm.delivery = strings.TrimPrefix(label, "delivery:")
+		} else if label == LabelAckRequired {
+			bm.ackRequired = true
+		} else if strings.HasPrefix(label, "ack-deadline:") {
+			bm.ackDeadline = parseLabelTime(strings.TrimPrefix(label, "ack-deadline:"))
+		} else if strings.HasPrefix(label, "acked-by:") {
+			bm.ackedBy = strings.TrimPrefix(label, "acked-by:")
+		} else if strings.HasPrefix(label, "acked-at:") {
+			bm.ackedAt = parseLabelTime(strings.TrimPrefix(label, "acked-at:"))
+		} else if strings.HasPrefix(label, "ack-redelivered:") {
+			bm.ackRedelivered = parseLabelTime(strings.TrimPrefix(label, "ack-redelivered:"))
 		}
 	}
 }
 
+// parseLabelTime parses an RFC3339 timestamp from a label value.
+// Returns nil if the value is malformed.
+func parseLabelTime(ts string) *time.Time {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 // GetCC returns the parsed CC recipients.
 func (bm *BeadsMessage) GetCC() []string {
 	return bm.cc
@@ -384,6 +501,22 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:   bm.channel,
 		ClaimedBy: bm.claimedBy,
 		ClaimedAt: bm.claimedAt,
+
+		LeaseUntil:      bm.leaseUntil,
+		Deliveries:      bm.deliveries,
+		DeadLetterQueue: bm.deadLetter,
+
+		Pinned:       bm.Pinned,
+		Delivery:     Delivery(bm.delivery),
+		DeliverAfter: bm.deliverAfter,
+		ExpiresAt:    bm.expiresAt,
+
+		AckRequired: bm.ackRequired,
+		AckDeadline: bm.ackDeadline,
+		AckedBy:     bm.ackedBy,
+		AckedAt:     bm.ackedAt,
+
+		AckRedeliveredAt: bm.ackRedelivered,
 	}
 }
 
@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -221,6 +222,15 @@ func TestNewReplyMessage(t *testing.T) {
 	}
 }
 
+func TestNewReplyMessageStartsThread(t *testing.T) {
+	original := &Message{ID: "orig-001", From: "gastown/Toast", To: "mayor/"}
+
+	reply := NewReplyMessage("mayor/", "gastown/Toast", "Re: x", "body", original)
+	if !strings.HasPrefix(reply.ThreadID, "thread-") {
+		t.Errorf("ThreadID = %q, want a new thread ID", reply.ThreadID)
+	}
+}
+
 func TestBeadsMessageToMessage(t *testing.T) {
 	now := time.Now()
 	bm := BeadsMessage{
@@ -712,3 +722,100 @@ func TestMessageIsClaimed(t *testing.T) {
 		t.Error("Claimed message should be claimed")
 	}
 }
+
+func TestMessageScheduleState(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	msg := NewMessage("mayor/", "gastown/Toast", "Reminder", "Check in")
+	if msg.IsScheduled(now) || msg.IsExpired(now) {
+		t.Fatal("plain message should be neither scheduled nor expired")
+	}
+
+	msg.DeliverAfter = &future
+	if !msg.IsScheduled(now) {
+		t.Error("message with future deliver_after should be scheduled")
+	}
+	msg.DeliverAfter = &past
+	if msg.IsScheduled(now) {
+		t.Error("message with past deliver_after should not be scheduled")
+	}
+
+	msg.ExpiresAt = &now
+	if !msg.IsExpired(now) {
+		t.Error("message should be expired at its expires_at time")
+	}
+	msg.ExpiresAt = &future
+	if msg.IsExpired(now) {
+		t.Error("message with future expires_at should not be expired")
+	}
+}
+
+func TestValidateScheduleOrder(t *testing.T) {
+	deliver := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	expires := deliver.Add(-time.Hour)
+
+	msg := NewMessage("mayor/", "gastown/Toast", "Reminder", "Check in")
+	msg.DeliverAfter = &deliver
+	msg.ExpiresAt = &expires
+	if err := msg.Validate(); err == nil {
+		t.Error("expected error when expires_at precedes deliver_after")
+	}
+
+	expires = deliver.Add(time.Hour)
+	if err := msg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestScheduleLabelsRoundTrip(t *testing.T) {
+	deliver := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	expires := deliver.Add(2 * time.Hour)
+
+	msg := NewMessage("mayor/", "gastown/Toast", "Reminder", "Check in")
+	msg.Delivery = DeliveryInterrupt
+	msg.DeliverAfter = &deliver
+	msg.ExpiresAt = &expires
+
+	labels := scheduleLabels(msg)
+	want := map[string]bool{
+		"delivery:interrupt":                            true,
+		"deliver-after:" + deliver.Format(time.RFC3339): true,
+		LabelScheduled:                                  true,
+		"expires-at:" + expires.Format(time.RFC3339):    true,
+		LabelExpiring:                                   true,
+	}
+	if len(labels) != len(want) {
+		t.Fatalf("scheduleLabels() = %v, want %d labels", labels, len(want))
+	}
+	for _, l := range labels {
+		if !want[l] {
+			t.Errorf("unexpected label %q", l)
+		}
+	}
+
+	bm := BeadsMessage{ID: "hq-1", Assignee: "gastown/Toast", Labels: append([]string{"from:mayor/"}, labels...)}
+	got := bm.ToMessage()
+	if got.Delivery != DeliveryInterrupt {
+		t.Errorf("Delivery = %q, want %q", got.Delivery, DeliveryInterrupt)
+	}
+	if got.DeliverAfter == nil || !got.DeliverAfter.Equal(deliver) {
+		t.Errorf("DeliverAfter = %v, want %v", got.DeliverAfter, deliver)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, expires)
+	}
+}
+
+func TestScheduleLabelsPastDeliveryNotScheduled(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	msg := NewMessage("mayor/", "gastown/Toast", "Reminder", "Check in")
+	msg.DeliverAfter = &past
+
+	for _, l := range scheduleLabels(msg) {
+		if l == LabelScheduled {
+			t.Errorf("past deliver_after should not add %q label", LabelScheduled)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package quota detects provider usage limits in agent sessions and tracks
+// which accounts are limited, so sessions can move to another account or
+// wait for the limit to reset.
+package quota
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultBackoff is assumed when a limit message doesn't say when it resets.
+const DefaultBackoff = time.Hour
+
+// paneTailLines is how many trailing non-blank pane lines are searched.
+// Runtimes show limit messages at the bottom; older output is more likely
+// to be code or logs that merely mention rate limits.
+const paneTailLines = 15
+
+// Hit is a recognized usage-limit message.
+type Hit struct {
+	Line    string    `json:"line"`     // The matching line
+	ResetAt time.Time `json:"reset_at"` // When the limit lifts
+	Stated  bool      `json:"stated"`   // ResetAt came from the message, not DefaultBackoff
+	Source  string    `json:"source"`   // "pane" or "transcript"
+
+	// Key identifies this occurrence of the message, so a message still
+	// on screen after it was acted on isn't mistaken for a new limit.
+	Key string `json:"key,omitempty"`
+}
+
+// Detector recognizes usage-limit messages for one runtime preset.
+type Detector struct {
+	patterns []*regexp.Regexp
+}
+
+// NewDetector compiles a detector from regular expressions.
+// Invalid patterns are skipped so one bad user pattern can't disable detection.
+func NewDetector(patterns []string) *Detector {
+	d := &Detector{}
+	for _, p := range patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			d.patterns = append(d.patterns, re)
+		}
+	}
+	return d
+}
+
+// DetectorForAgent returns a detector using the agent preset's patterns.
+func DetectorForAgent(agentName string) *Detector {
+	return NewDetector(config.GetUsageLimitPatterns(agentName))
+}
+
+// Match reports whether a line is a usage-limit message.
+func (d *Detector) Match(line string) bool {
+	for _, re := range d.patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectPane looks for a current usage-limit message at the bottom of
+// captured pane output. Messages whose stated reset time has passed are
+// stale and ignored.
+func (d *Detector) DetectPane(pane string, now time.Time) *Hit {
+	lines := strings.Split(pane, "\n")
+	seen := 0
+	for i := len(lines) - 1; i >= 0 && seen < paneTailLines; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		seen++
+		if !d.Match(line) {
+			continue
+		}
+
+		// The reset time is often on the line after the headline
+		text := line
+		if i+1 < len(lines) {
+			text += " " + strings.TrimSpace(lines[i+1])
+		}
+		hit := newHit(line, text, now, "pane")
+		if hit.Stated && !hit.ResetAt.After(now) {
+			return nil
+		}
+		// The whole window, so a repeat of the message after more output
+		// is a new hit
+		hit.Key = hitKey("pane", strings.Join(tailWindow(lines), "\n"))
+		return hit
+	}
+	return nil
+}
+
+// tailWindow returns the last paneTailLines non-blank lines, trimmed.
+func tailWindow(lines []string) []string {
+	var window []string
+	for i := len(lines) - 1; i >= 0 && len(window) < paneTailLines; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			window = append(window, line)
+		}
+	}
+	return window
+}
+
+// newHit builds a hit, parsing the reset time from text when present.
+func newHit(line, text string, now time.Time, source string) *Hit {
+	hit := &Hit{Line: line, ResetAt: now.Add(DefaultBackoff), Source: source}
+	if reset, ok := ParseResetTime(text, now); ok {
+		hit.ResetAt, hit.Stated = reset, true
+	}
+	return hit
+}
+
+// hitKey hashes the text that identifies a hit.
+func hitKey(source, text string) string {
+	sum := sha256.Sum256([]byte(source + "\x00" + text))
+	return hex.EncodeToString(sum[:8])
+}
+
+var (
+	// "Claude AI usage limit reached|1760000000"
+	epochPattern = regexp.MustCompile(`\|(\d{10})\b`)
+
+	// "resets 3pm", "reset at 3:30 PM (America/New_York)", "resets Oct 9, 2pm"
+	clockPattern = regexp.MustCompile(`(?i)resets?\s+(?:at\s+)?(?:([A-Z][a-z]{2})[a-z]*\.?\s+(\d{1,2}),?\s+(?:at\s+)?)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([^)]+)\))?`)
+
+	// "try again in 2 hours 13 minutes", "retry in 34.5s", "resets in 1h30m"
+	relativePattern = regexp.MustCompile(`(?i)(?:try again|retry|resets?)(?:\s+after)?\s+in\s+((?:\d+(?:\.\d+)?\s*[a-z]+[\s,]*(?:and\s+)?)+)`)
+	durationPart    = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*([a-z]+)`)
+)
+
+// ParseResetTime extracts when a usage limit resets from a limit message.
+// Understands Unix timestamps, wall-clock times ("resets 3pm (Europe/Paris)")
+// and relative delays ("try again in 2h 13m").
+func ParseResetTime(text string, now time.Time) (time.Time, bool) {
+	if m := epochPattern.FindStringSubmatch(text); m != nil {
+		if sec, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			return time.Unix(sec, 0), true
+		}
+	}
+
+	if m := relativePattern.FindStringSubmatch(text); m != nil {
+		if d, ok := parseSpokenDuration(m[1]); ok {
+			return now.Add(d), true
+		}
+	}
+
+	if m := clockPattern.FindStringSubmatch(text); m != nil && (m[4] != "" || m[5] != "") {
+		return parseClock(m, now)
+	}
+
+	return time.Time{}, false
+}
+
+// parseSpokenDuration parses "2 hours 13 minutes", "1h30m" or "34.5s".
+func parseSpokenDuration(s string) (time.Duration, bool) {
+	var total time.Duration
+	found := false
+	for _, m := range durationPart.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		var unit time.Duration
+		switch u := strings.ToLower(m[2]); {
+		case u == "d" || strings.HasPrefix(u, "day"):
+			unit = 24 * time.Hour
+		case u == "h" || strings.HasPrefix(u, "hr") || strings.HasPrefix(u, "hour"):
+			unit = time.Hour
+		case u == "m" || strings.HasPrefix(u, "min"):
+			unit = time.Minute
+		case u == "s" || strings.HasPrefix(u, "sec"):
+			unit = time.Second
+		default:
+			continue
+		}
+		total += time.Duration(n * float64(unit))
+		found = true
+	}
+	return total, found && total > 0
+}
+
+// parseClock resolves a wall-clock reset time to the next matching instant.
+// Submatches: 1 month, 2 day, 3 hour, 4 minute, 5 am/pm, 6 time zone.
+func parseClock(m []string, now time.Time) (time.Time, bool) {
+	hour, err := strconv.Atoi(m[3])
+	if err != nil || hour > 23 {
+		return time.Time{}, false
+	}
+	minute := 0
+	if m[4] != "" {
+		if minute, err = strconv.Atoi(m[4]); err != nil || minute > 59 {
+			return time.Time{}, false
+		}
+	}
+	switch strings.ToLower(m[5]) {
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+
+	loc := now.Location()
+	if m[6] != "" {
+		if l, err := time.LoadLocation(strings.TrimSpace(m[6])); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+
+	if m[1] != "" {
+		month, err := time.Parse("Jan", m[1][:1]+strings.ToLower(m[1][1:3]))
+		day, dayErr := strconv.Atoi(m[2])
+		if err != nil || dayErr != nil {
+			return time.Time{}, false
+		}
+		reset := time.Date(local.Year(), month.Month(), day, hour, minute, 0, 0, loc)
+		if reset.Before(local.Add(-24 * time.Hour)) {
+			reset = reset.AddDate(1, 0, 0) // e.g. "resets Jan 2" seen on Dec 30
+		}
+		return reset, true
+	}
+
+	reset := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !reset.After(local) {
+		reset = reset.Add(24 * time.Hour)
+	}
+	return reset, true
+}
@@ -0,0 +1,124 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseResetTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		text string
+		want time.Time
+		ok   bool
+	}{
+		{"epoch", "Claude AI usage limit reached|1773151200", time.Unix(1773151200, 0), true},
+		{"relative", "Rate limited. Try again in 2 hours 13 minutes.", now.Add(2*time.Hour + 13*time.Minute), true},
+		{"relative compact", "quota exceeded, retry in 1h30m", now.Add(90 * time.Minute), true},
+		{"clock later today", "5-hour limit reached ∙ resets 3pm (UTC)", time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC), true},
+		{"clock tomorrow", "limit reached, resets 9:30am (UTC)", time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC), true},
+		{"clock with date", "Weekly limit reached ∙ resets Mar 12, 4pm (UTC)", time.Date(2026, 3, 12, 16, 0, 0, 0, time.UTC), true},
+		{"no time", "usage limit reached", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseResetTime(tt.text, now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("reset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectPane(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	d := DetectorForAgent("claude")
+
+	t.Run("limit at bottom", func(t *testing.T) {
+		pane := "● Working on the parser\n\n  ⎿  5-hour limit reached ∙ resets 3pm (UTC)\n     /upgrade to increase your usage limit.\n\n> "
+		hit := d.DetectPane(pane, now)
+		if hit == nil {
+			t.Fatal("expected a hit")
+		}
+		if !hit.Stated || !hit.ResetAt.Equal(time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)) {
+			t.Errorf("reset = %v (stated %v)", hit.ResetAt, hit.Stated)
+		}
+	})
+
+	t.Run("old mention scrolled away", func(t *testing.T) {
+		pane := "// handle: usage limit reached\n"
+		for i := 0; i < paneTailLines+5; i++ {
+			pane += "ok line\n"
+		}
+		if hit := d.DetectPane(pane, now); hit != nil {
+			t.Errorf("unexpected hit: %+v", hit)
+		}
+	})
+
+	t.Run("unstated reset uses backoff", func(t *testing.T) {
+		hit := d.DetectPane("Claude usage limit reached.\n", now)
+		if hit == nil {
+			t.Fatal("expected a hit")
+		}
+		if hit.Stated || !hit.ResetAt.Equal(now.Add(DefaultBackoff)) {
+			t.Errorf("reset = %v (stated %v)", hit.ResetAt, hit.Stated)
+		}
+	})
+
+	t.Run("key follows the pane", func(t *testing.T) {
+		pane := "● Working\nClaude usage limit reached.\n> "
+		first := d.DetectPane(pane, now)
+		later := d.DetectPane(pane, now.Add(2*DefaultBackoff))
+		if first == nil || later == nil || first.Key == "" || first.Key != later.Key {
+			t.Fatalf("same message on screen gave keys %+v, %+v", first, later)
+		}
+		again := d.DetectPane(pane+"continue\n● Working\nClaude usage limit reached.\n> ", now)
+		if again == nil || again.Key == first.Key {
+			t.Errorf("new message after more output has the old key: %+v", again)
+		}
+	})
+}
+
+func TestDetectTranscript(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	d := DetectorForAgent("claude")
+	dir := t.TempDir()
+
+	write := func(lines string) string {
+		path := filepath.Join(dir, "session.jsonl")
+		if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	limited := `{"timestamp":"2026-03-10T09:55:00Z","message":{"content":[{"type":"text","text":"Let me fix the rate limit handling"}]}}
+{"timestamp":"2026-03-10T09:58:00Z","isApiErrorMessage":true,"message":{"content":[{"type":"text","text":"Claude AI usage limit reached|1773151200"}]}}
+`
+	hit := d.DetectTranscript(write(limited), now)
+	if hit == nil {
+		t.Fatal("expected a hit")
+	}
+	if hit.Source != "transcript" || !hit.ResetAt.Equal(time.Unix(1773151200, 0)) {
+		t.Errorf("hit = %+v", hit)
+	}
+
+	recovered := limited + `{"timestamp":"2026-03-10T09:59:00Z","message":{"content":"continue"}}
+`
+	if hit := d.DetectTranscript(write(recovered), now); hit != nil {
+		t.Errorf("newer exchange should clear the limit, got %+v", hit)
+	}
+
+	discussion := `{"timestamp":"2026-03-10T09:58:00Z","message":{"content":"the API returns usage limit reached when..."}}
+`
+	if hit := d.DetectTranscript(write(discussion), now); hit != nil {
+		t.Errorf("non-error entries should not match, got %+v", hit)
+	}
+}
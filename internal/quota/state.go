@@ -0,0 +1,278 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// AccountLimit records that an account hit a usage limit.
+type AccountLimit struct {
+	Handle    string    `json:"handle"`
+	LimitedAt time.Time `json:"limited_at"`
+	ResetAt   time.Time `json:"reset_at"`
+	Session   string    `json:"session,omitempty"` // Session that reported it
+	Message   string    `json:"message,omitempty"`
+}
+
+// ParkedSession is a limited session waiting for its account to reset
+// because no other account was available.
+type ParkedSession struct {
+	Session  string    `json:"session"`
+	Account  string    `json:"account,omitempty"`
+	ParkedAt time.Time `json:"parked_at"`
+	ResumeAt time.Time `json:"resume_at"`
+}
+
+// State tracks limited accounts and parked sessions across the town.
+type State struct {
+	Accounts    map[string]*AccountLimit  `json:"accounts"`
+	Parked      map[string]*ParkedSession `json:"parked"`
+	Handled     map[string]string         `json:"handled,omitempty"` // Session -> Key of the last hit acted on
+	LastUpdated time.Time                 `json:"last_updated"`
+}
+
+// StateFile returns the path to the usage-limit state file, kept next to
+// the accounts config.
+func StateFile(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "account-limits.json")
+}
+
+// LoadState loads usage-limit state. Returns empty state if none exists.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(StateFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading usage-limit state: %w", err)
+		}
+	} else if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing usage-limit state: %w", err)
+	}
+	if state.Accounts == nil {
+		state.Accounts = make(map[string]*AccountLimit)
+	}
+	if state.Parked == nil {
+		state.Parked = make(map[string]*ParkedSession)
+	}
+	if state.Handled == nil {
+		state.Handled = make(map[string]string)
+	}
+	return state, nil
+}
+
+// SaveState saves usage-limit state.
+func SaveState(townRoot string, state *State) error {
+	path := StateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating mayor directory: %w", err)
+	}
+	state.LastUpdated = time.Now().UTC()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling usage-limit state: %w", err)
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: no credentials, only handles and times
+}
+
+// MarkLimited records that an account is limited until hit.ResetAt.
+// A later reset time already on record is kept.
+func (s *State) MarkLimited(handle, session string, hit *Hit, now time.Time) {
+	if handle == "" {
+		return
+	}
+	if existing := s.Accounts[handle]; existing != nil && existing.ResetAt.After(hit.ResetAt) {
+		return
+	}
+	s.Accounts[handle] = &AccountLimit{
+		Handle:    handle,
+		LimitedAt: now,
+		ResetAt:   hit.ResetAt,
+		Session:   session,
+		Message:   hit.Line,
+	}
+}
+
+// IsLimited reports whether an account is limited at now.
+func (s *State) IsLimited(handle string, now time.Time) bool {
+	limit := s.Accounts[handle]
+	return limit != nil && limit.ResetAt.After(now)
+}
+
+// Expire forgets account limits that have reset.
+func (s *State) Expire(now time.Time) {
+	for handle, limit := range s.Accounts {
+		if !limit.ResetAt.After(now) {
+			delete(s.Accounts, handle)
+		}
+	}
+}
+
+// Park records that a session waits for its account to reset.
+func (s *State) Park(session, account string, resumeAt, now time.Time) {
+	s.Parked[session] = &ParkedSession{
+		Session:  session,
+		Account:  account,
+		ParkedAt: now,
+		ResumeAt: resumeAt,
+	}
+}
+
+// DueParked returns parked sessions whose resume time has come, and
+// unparks them.
+func (s *State) DueParked(now time.Time) []*ParkedSession {
+	var due []*ParkedSession
+	for name, p := range s.Parked {
+		if !p.ResumeAt.After(now) {
+			due = append(due, p)
+			delete(s.Parked, name)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Session < due[j].Session })
+	return due
+}
+
+// IsParked reports whether a session is parked.
+func (s *State) IsParked(session string) bool {
+	return s.Parked[session] != nil
+}
+
+// IsHandled reports whether hit is the one last acted on for session. A
+// limit message stays on screen (or last in the transcript) after the
+// session is parked and resumed; it must not park the session again.
+func (s *State) IsHandled(session string, hit *Hit) bool {
+	return hit.Key != "" && s.Handled[session] == hit.Key
+}
+
+// SetHandled records that hit was acted on for session.
+func (s *State) SetHandled(session string, hit *Hit) {
+	if s.Handled == nil {
+		s.Handled = make(map[string]string)
+	}
+	s.Handled[session] = hit.Key
+}
+
+// KeepHandled forgets handled hits of sessions not in live.
+func (s *State) KeepHandled(live []string) {
+	keep := make(map[string]bool, len(live))
+	for _, name := range live {
+		keep[name] = true
+	}
+	for name := range s.Handled {
+		if !keep[name] {
+			delete(s.Handled, name)
+		}
+	}
+}
+
+// NextAvailable picks the account a limited session should move to: the
+// next unlimited account after current in handle order, wrapping around.
+// Returns false if every other account is limited.
+func (s *State) NextAvailable(accounts *config.AccountsConfig, current string, now time.Time) (string, bool) {
+	if accounts == nil {
+		return "", false
+	}
+	handles := make([]string, 0, len(accounts.Accounts))
+	for handle := range accounts.Accounts {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+
+	start := 0
+	for i, h := range handles {
+		if h == current {
+			start = i + 1
+			break
+		}
+	}
+	for i := 0; i < len(handles); i++ {
+		h := handles[(start+i)%len(handles)]
+		if h != current && !s.IsLimited(h, now) {
+			return h, true
+		}
+	}
+	return "", false
+}
+
+// EarliestReset returns when the first limited account resets, or zero if
+// none is limited.
+func (s *State) EarliestReset(now time.Time) time.Time {
+	var earliest time.Time
+	for _, limit := range s.Accounts {
+		if limit.ResetAt.After(now) && (earliest.IsZero() || limit.ResetAt.Before(earliest)) {
+			earliest = limit.ResetAt
+		}
+	}
+	return earliest
+}
+
+// Action is what the rotation policy decided for a limited session.
+type Action string
+
+const (
+	ActionRotate Action = "rotate" // Restart under another account
+	ActionPark   Action = "park"   // Wait for the limit to reset
+)
+
+// Decision is the rotation policy's verdict for a limited session.
+type Decision struct {
+	Action  Action
+	Account string    // Account to rotate to (ActionRotate)
+	Until   time.Time // When to resume (ActionPark)
+}
+
+// Decide applies the rotation policy to a session limited on account
+// current: move to the next available account, or park until the earliest
+// time any account (including current) resets. Accounts are Claude Code
+// config dirs; sessions of other runtimes pass nil accounts and are parked
+// until their own limit resets.
+func (s *State) Decide(accounts *config.AccountsConfig, current string, hit *Hit, now time.Time) Decision {
+	if accounts == nil {
+		return Decision{Action: ActionPark, Until: hit.ResetAt}
+	}
+	if next, ok := s.NextAvailable(accounts, current, now); ok {
+		return Decision{Action: ActionRotate, Account: next}
+	}
+	until := hit.ResetAt
+	if earliest := s.EarliestReset(now); !earliest.IsZero() && earliest.Before(until) {
+		until = earliest
+	}
+	return Decision{Action: ActionPark, Until: until}
+}
+
+// UsesAccounts reports whether a runtime can be rotated onto another
+// account. Accounts are Claude Code config dirs, selected by
+// CLAUDE_CONFIG_DIR, which other runtimes ignore.
+func UsesAccounts(rc *config.RuntimeConfig) bool {
+	return rc != nil && filepath.Base(rc.Command) == "claude"
+}
+
+// AccountForConfigDir maps a CLAUDE_CONFIG_DIR back to its account handle.
+// Returns "" if no registered account uses that directory.
+func AccountForConfigDir(accounts *config.AccountsConfig, configDir string) string {
+	if accounts == nil || configDir == "" {
+		return ""
+	}
+	want := filepath.Clean(expandHome(configDir))
+	for handle, acct := range accounts.Accounts {
+		if filepath.Clean(expandHome(acct.ConfigDir)) == want {
+			return handle
+		}
+	}
+	return ""
+}
+
+// expandHome expands a leading ~/ to the home directory.
+func expandHome(path string) string {
+	if len(path) >= 2 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,150 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testAccounts() *config.AccountsConfig {
+	return &config.AccountsConfig{
+		Default: "personal",
+		Accounts: map[string]config.Account{
+			"personal": {ConfigDir: "/accounts/personal"},
+			"team":     {ConfigDir: "/accounts/team"},
+			"work":     {ConfigDir: "/accounts/work"},
+		},
+	}
+}
+
+func TestNextAvailable(t *testing.T) {
+	now := time.Now()
+	accounts := testAccounts()
+	state := &State{Accounts: map[string]*AccountLimit{}, Parked: map[string]*ParkedSession{}}
+
+	if got, ok := state.NextAvailable(accounts, "work", now); !ok || got != "personal" {
+		t.Errorf("after work: got %q %v, want personal (wraparound)", got, ok)
+	}
+
+	state.MarkLimited("team", "gt-gastown-Toast", &Hit{ResetAt: now.Add(time.Hour)}, now)
+	if got, ok := state.NextAvailable(accounts, "personal", now); !ok || got != "work" {
+		t.Errorf("after personal with team limited: got %q %v, want work", got, ok)
+	}
+
+	state.MarkLimited("work", "gt-gastown-Toast", &Hit{ResetAt: now.Add(2 * time.Hour)}, now)
+	if got, ok := state.NextAvailable(accounts, "personal", now); ok {
+		t.Errorf("all others limited: got %q, want none", got)
+	}
+}
+
+func TestDecideParksUntilEarliestReset(t *testing.T) {
+	now := time.Now()
+	accounts := testAccounts()
+	state := &State{Accounts: map[string]*AccountLimit{}, Parked: map[string]*ParkedSession{}}
+
+	state.MarkLimited("team", "a", &Hit{ResetAt: now.Add(30 * time.Minute)}, now)
+	state.MarkLimited("work", "b", &Hit{ResetAt: now.Add(3 * time.Hour)}, now)
+	hit := &Hit{ResetAt: now.Add(2 * time.Hour)}
+	state.MarkLimited("personal", "c", hit, now)
+
+	decision := state.Decide(accounts, "personal", hit, now)
+	if decision.Action != ActionPark {
+		t.Fatalf("action = %s, want park", decision.Action)
+	}
+	if !decision.Until.Equal(now.Add(30 * time.Minute)) {
+		t.Errorf("until = %v, want earliest reset", decision.Until)
+	}
+
+	state.Park("c", "personal", decision.Until, now)
+	if !state.IsParked("c") {
+		t.Error("session should be parked")
+	}
+	if due := state.DueParked(now.Add(31 * time.Minute)); len(due) != 1 || due[0].Session != "c" {
+		t.Errorf("due = %+v", due)
+	}
+	if state.IsParked("c") {
+		t.Error("due session should be unparked")
+	}
+}
+
+func TestDecideWithoutAccountsParksUntilOwnReset(t *testing.T) {
+	now := time.Now()
+	state := &State{Accounts: map[string]*AccountLimit{}, Parked: map[string]*ParkedSession{}}
+	state.MarkLimited("team", "a", &Hit{ResetAt: now.Add(10 * time.Minute)}, now)
+
+	// A non-Claude session can't rotate, and Claude accounts resetting
+	// doesn't lift its limit
+	hit := &Hit{ResetAt: now.Add(time.Hour)}
+	decision := state.Decide(nil, "", hit, now)
+	if decision.Action != ActionPark || !decision.Until.Equal(hit.ResetAt) {
+		t.Errorf("decision = %+v, want park until the session's own reset", decision)
+	}
+
+	if !UsesAccounts(&config.RuntimeConfig{Command: "/usr/local/bin/claude"}) || UsesAccounts(&config.RuntimeConfig{Command: "codex"}) {
+		t.Error("only Claude Code runs under accounts")
+	}
+}
+
+func TestHandled(t *testing.T) {
+	state := &State{}
+	hit := &Hit{Key: "abc"}
+	if state.IsHandled("s1", hit) {
+		t.Error("unseen hit reported handled")
+	}
+	state.SetHandled("s1", hit)
+	if !state.IsHandled("s1", hit) || state.IsHandled("s2", hit) || state.IsHandled("s1", &Hit{Key: "def"}) {
+		t.Error("handled is per session and key")
+	}
+	state.KeepHandled([]string{"s2"})
+	if state.IsHandled("s1", hit) {
+		t.Error("handled hit of a dead session kept")
+	}
+}
+
+func TestMarkLimitedKeepsLaterReset(t *testing.T) {
+	now := time.Now()
+	state := &State{Accounts: map[string]*AccountLimit{}}
+	state.MarkLimited("work", "a", &Hit{ResetAt: now.Add(5 * time.Hour)}, now)
+	state.MarkLimited("work", "b", &Hit{ResetAt: now.Add(time.Hour)}, now)
+	if got := state.Accounts["work"].ResetAt; !got.Equal(now.Add(5 * time.Hour)) {
+		t.Errorf("reset = %v, want the later one", got)
+	}
+
+	state.Expire(now.Add(6 * time.Hour))
+	if state.IsLimited("work", now.Add(6*time.Hour)) {
+		t.Error("limit should have expired")
+	}
+}
+
+func TestAccountForConfigDir(t *testing.T) {
+	accounts := testAccounts()
+	if got := AccountForConfigDir(accounts, "/accounts/team/"); got != "team" {
+		t.Errorf("got %q, want team", got)
+	}
+	if got := AccountForConfigDir(accounts, "/elsewhere"); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	state.MarkLimited("work", "gt-gastown-Toast", &Hit{Line: "limit", ResetAt: now.Add(time.Hour)}, now)
+	state.Park("gt-gastown-Toast", "work", now.Add(time.Hour), now)
+	if err := SaveState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsLimited("work", now) || !loaded.IsParked("gt-gastown-Toast") {
+		t.Errorf("loaded = %+v", loaded)
+	}
+}
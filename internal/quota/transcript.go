@@ -0,0 +1,176 @@
+package quota
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// transcriptTailBytes is how much of the end of a transcript is scanned.
+const transcriptTailBytes = 256 * 1024
+
+// ClaudeProjectDir returns where Claude Code keeps transcripts for sessions
+// started in workDir. configDir is the session's CLAUDE_CONFIG_DIR; empty
+// means the default ~/.claude.
+func ClaudeProjectDir(configDir, workDir string) string {
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configDir = filepath.Join(home, ".claude")
+	}
+	escaped := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, workDir)
+	return filepath.Join(configDir, "projects", escaped)
+}
+
+// LatestTranscript returns the most recently modified .jsonl transcript in
+// dir, or "" if there is none.
+func LatestTranscript(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var latest string
+	var latestMod time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latestMod) {
+			latest, latestMod = filepath.Join(dir, e.Name()), info.ModTime()
+		}
+	}
+	return latest
+}
+
+// transcriptEntry is the part of a Claude Code transcript line we read.
+type transcriptEntry struct {
+	Timestamp         time.Time       `json:"timestamp"`
+	IsAPIErrorMessage bool            `json:"isApiErrorMessage"`
+	Error             json.RawMessage `json:"error"`
+	Message           struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// text returns the entry's message text. Content is either a string or a
+// list of blocks, of which only text blocks matter.
+func (e *transcriptEntry) text() string {
+	var s string
+	if json.Unmarshal(e.Message.Content, &s) == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(e.Message.Content, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// DetectTranscript scans the tail of a transcript for an API error entry
+// reporting a usage limit that is still in effect. Only error entries are
+// considered: an agent discussing rate limits in its own work is not limited.
+func (d *Detector) DetectTranscript(path string, now time.Time) *Hit {
+	lines, err := tailLines(path, transcriptTailBytes)
+	if err != nil {
+		return nil
+	}
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		var entry transcriptEntry
+		if json.Unmarshal([]byte(lines[i]), &entry) != nil {
+			continue
+		}
+		if !entry.IsAPIErrorMessage && len(entry.Error) == 0 {
+			// The newest real exchange postdates any earlier limit
+			if entry.text() != "" {
+				return nil
+			}
+			continue
+		}
+
+		text := entry.text()
+		if text == "" {
+			text = string(entry.Error)
+		}
+		line := firstMatchingLine(d, text)
+		if line == "" {
+			continue
+		}
+
+		// Undated messages are measured from when they were logged
+		at := now
+		if !entry.Timestamp.IsZero() {
+			at = entry.Timestamp
+		}
+		hit := newHit(line, text, at, "transcript")
+		if !hit.ResetAt.After(now) {
+			return nil // limit has since lifted
+		}
+		hit.Key = hitKey("transcript", lines[i])
+		return hit
+	}
+	return nil
+}
+
+func firstMatchingLine(d *Detector, text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); d.Match(line) {
+			return line
+		}
+	}
+	return ""
+}
+
+// tailLines returns the complete lines in the last n bytes of a file.
+func tailLines(path string, n int64) ([]string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: transcript path derived from session config
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := int64(0)
+	if info.Size() > n {
+		offset = info.Size() - n
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:] // first line is partial
+	}
+	return lines, nil
+}
@@ -0,0 +1,105 @@
+package readmodel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DisableEnv turns off daemon queries when set to "0" or "off", so every
+// read goes straight to bd.
+const DisableEnv = "GT_READMODEL"
+
+// queryTimeout bounds a daemon query. Queries read memory only, so a slow
+// answer means a stuck daemon and the caller is better off asking bd.
+const queryTimeout = 2 * time.Second
+
+// dialTimeout is short: a missing daemon should fall back at once.
+const dialTimeout = 100 * time.Millisecond
+
+// Query asks the town's daemon for a query result and decodes it into out.
+// It fails fast when the daemon isn't serving.
+func Query(townRoot, kind string, params url.Values, out any) error {
+	if townRoot == "" {
+		return fmt.Errorf("no town root")
+	}
+	if v := os.Getenv(DisableEnv); v == "0" || v == "off" {
+		return fmt.Errorf("read model disabled by %s", DisableEnv)
+	}
+	socket := SocketPath(townRoot)
+	if _, err := os.Stat(socket); err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: queryTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				d := net.Dialer{Timeout: dialTimeout}
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	u := url.URL{Scheme: "http", Host: "readmodel", Path: "/v1/" + kind, RawQuery: params.Encode()}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("read model %s: %s", kind, string(msg))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Get returns a query result from the daemon, or from local() when the
+// daemon can't answer (not running, or the database not loaded yet).
+func Get[T any](townRoot, kind string, params url.Values, local func() (T, error)) (T, error) {
+	var v T
+	if err := Query(townRoot, kind, params, &v); err == nil {
+		return v, nil
+	}
+	return local()
+}
+
+// Hooked returns the first bead hooked to identity in beadsDir, or nil.
+func Hooked(townRoot, identity, beadsDir string) (*HookedWork, error) {
+	return Get(townRoot, KindHooked, url.Values{"identity": {identity}, "dir": {beadsDir}}, func() (*HookedWork, error) {
+		return LoadHooked(identity, beadsDir)
+	})
+}
+
+// Mail returns identity's unread count and newest unread subject.
+func Mail(townRoot, identity string) (*MailPreview, error) {
+	return Get(townRoot, KindMail, url.Values{"identity": {identity}}, func() (*MailPreview, error) {
+		return LoadMail(townRoot, identity)
+	})
+}
+
+// Queue summarizes a rig's refinery queue.
+func Queue(townRoot, rigName string) (*QueueSummary, error) {
+	return Get(townRoot, KindQueue, url.Values{"rig": {rigName}}, func() (*QueueSummary, error) {
+		return LoadQueue(townRoot, rigName)
+	})
+}
+
+// MQ counts the merge requests in a rig's beads.
+func MQ(townRoot, beadsDir string) (*MQCounts, error) {
+	return Get(townRoot, KindMQ, url.Values{"dir": {beadsDir}}, func() (*MQCounts, error) {
+		return LoadMQ(beadsDir)
+	})
+}
+
+// Agents returns every agent bead in the town and rigs, with hooked beads.
+func Agents(townRoot string) (*AgentIndex, error) {
+	return Get(townRoot, KindAgents, nil, func() (*AgentIndex, error) {
+		return LoadAgents(townRoot)
+	})
+}
@@ -0,0 +1,169 @@
+package readmodel
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// rediscoverInterval is how often the server looks for rigs added or
+// removed since it started following.
+const rediscoverInterval = 5 * time.Minute
+
+// restartDelay is the wait before restarting a failed activity stream.
+const restartDelay = 5 * time.Second
+
+// activityEvent is the part of a bd activity --json line the server uses.
+type activityEvent struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	IssueID   string `json:"issue_id"`
+	NewStatus string `json:"new_status,omitempty"`
+}
+
+// databases returns the town and rig databases by beads directory, with
+// the directory bd runs in for each.
+func (s *Server) databases() map[string]string {
+	dbs := map[string]string{beads.ResolveBeadsDir(s.townRoot): s.townRoot}
+	rigs, err := rigManager(s.townRoot).DiscoverRigs()
+	if err != nil {
+		s.logger("read model: discovering rigs: %v", err)
+		return dbs
+	}
+	for _, r := range rigs {
+		workDir := filepath.Join(r.Path, "mayor", "rig")
+		dbs[beads.ResolveBeadsDir(workDir)] = workDir
+	}
+	return dbs
+}
+
+// discover follows databases that appeared since the last call and stops
+// following ones that are gone.
+func (s *Server) discover() {
+	found := s.databases()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for beadsDir, workDir := range found {
+		if s.followers[beadsDir] != nil {
+			continue
+		}
+		ctx, cancel := context.WithCancel(s.ctx)
+		s.followers[beadsDir] = cancel
+		db := s.snap.add(workDir, beadsDir)
+		s.wg.Add(1)
+		go s.follow(ctx, db)
+	}
+	for beadsDir, cancel := range s.followers {
+		if _, ok := found[beadsDir]; !ok {
+			cancel()
+			delete(s.followers, beadsDir)
+			s.snap.remove(beadsDir)
+		}
+	}
+}
+
+// discoverLoop rediscovers databases until the server stops.
+func (s *Server) discoverLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(rediscoverInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.discover()
+		}
+	}
+}
+
+// follow keeps one database's part of the snapshot current, restarting
+// its activity stream when it fails.
+func (s *Server) follow(ctx context.Context, db *database) {
+	defer s.wg.Done()
+	for {
+		err := s.stream(ctx, db)
+		// Events may be missed until the stream restarts and reloads
+		s.snap.unload(db)
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger("read model: following %s: %v, restarting in %s", db.beadsDir, err, restartDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+// stream starts bd activity for a database, loads the database in full
+// once the stream is running (so no change falls between the two), and
+// applies events until the stream ends.
+func (s *Server) stream(ctx context.Context, db *database) error {
+	cmd := beads.CommandContext(ctx, "activity", "--follow", "--json")
+	cmd.Dir = db.workDir
+	cmd.BeadsDir = db.beadsDir
+	cmd.Timeout = 0 // Follows until canceled
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("creating stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting bd activity: %w", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	started := time.Now()
+	if err := s.snap.load(db); err != nil {
+		return fmt.Errorf("loading: %w", err)
+	}
+	s.loads.Add(1)
+	s.refreshViews()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := s.handle(db, scanner.Bytes(), started); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading bd activity: %w", err)
+	}
+	return fmt.Errorf("bd activity exited")
+}
+
+// handle applies one activity line. Events from before the load are
+// already reflected in it and are skipped. An error leaves the database
+// out of date, so the caller restarts the stream.
+func (s *Server) handle(db *database, line []byte, loaded time.Time) error {
+	var event activityEvent
+	if err := json.Unmarshal(line, &event); err != nil || event.IssueID == "" {
+		return nil // Skip malformed lines
+	}
+	if t, err := time.Parse(time.RFC3339, event.Timestamp); err == nil && t.Before(loaded) {
+		return nil
+	}
+	s.events.Add(1)
+	issue, err := s.snap.apply(db, event.IssueID)
+	if err != nil {
+		return fmt.Errorf("applying %s: %w", event.IssueID, err)
+	}
+	// Convoy views show convoys and the status of the issues they track
+	if issue == nil || issue.Type == "convoy" || event.Type == "status" {
+		s.refreshViews()
+	}
+	return nil
+}
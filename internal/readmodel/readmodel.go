@@ -0,0 +1,239 @@
+// Package readmodel serves a daemon-maintained snapshot of what status
+// displays read: agent beads and the beads they have hooked, hooked work,
+// merge requests and unread mail, from the town and every rig database.
+//
+// The daemon follows each database's bd activity stream. A stream's start
+// loads the database in full; after that, each event re-reads only the
+// issue it names (and the merge requests it blocks, or the bead an agent
+// now has hooked), so the snapshot tracks bd without listing it again.
+// Queries over the local socket are answered from the snapshot without
+// calling bd. Inputs that are not beads (refinery state files, rig
+// config) are read when a query runs.
+//
+// Views, like the convoy panels, are registered by the packages that own
+// them and recomputed by their loader off the request path when activity
+// touches a convoy or changes an issue's status.
+//
+// gt status-line runs every few seconds in every tmux session, so without
+// this each render shells out to bd several times. When the daemon isn't
+// running, or hasn't loaded a database yet, clients compute results
+// themselves with the same Load functions.
+package readmodel
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Query kinds answered from the snapshot.
+const (
+	KindHooked = "hooked" // First hooked bead for an assignee
+	KindMail   = "mail"   // Unread mail count and first subject
+	KindQueue  = "queue"  // Refinery queue: current MR and pending count
+	KindMQ     = "mq"     // Open, in-flight and blocked MR counts
+	KindAgents = "agents" // Agent beads and their hook beads, town-wide
+)
+
+// ViewLoader computes a view from bd. Results must marshal to JSON.
+type ViewLoader func(townRoot string) (any, error)
+
+var (
+	viewsMu sync.RWMutex
+	views   = map[string]ViewLoader{}
+)
+
+// RegisterView adds a view, for packages that own their loading logic
+// (e.g. the convoy panels).
+func RegisterView(kind string, load ViewLoader) {
+	viewsMu.Lock()
+	defer viewsMu.Unlock()
+	views[kind] = load
+}
+
+// registeredViews returns a copy of the view registry.
+func registeredViews() map[string]ViewLoader {
+	viewsMu.RLock()
+	defer viewsMu.RUnlock()
+	out := make(map[string]ViewLoader, len(views))
+	for k, v := range views {
+		out[k] = v
+	}
+	return out
+}
+
+// HookedWork is an agent's hooked bead.
+type HookedWork struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// MailPreview summarizes an inbox.
+type MailPreview struct {
+	Unread  int    `json:"unread"`
+	Subject string `json:"subject,omitempty"` // Newest unread message
+}
+
+// QueueSummary is a refinery's queue as its status line shows it.
+type QueueSummary struct {
+	Current string `json:"current,omitempty"` // Issue of the MR being merged
+	Pending int    `json:"pending"`
+}
+
+// MQCounts counts a rig's merge requests by state.
+type MQCounts struct {
+	Pending  int `json:"pending"`   // Open and unblocked
+	InFlight int `json:"in_flight"` // In progress
+	Blocked  int `json:"blocked"`
+}
+
+// AgentIndex holds every agent bead in the town and rigs, and the beads
+// they have hooked.
+type AgentIndex struct {
+	Agents map[string]*beads.Issue `json:"agents"`
+	Hooks  map[string]*beads.Issue `json:"hooks"`
+}
+
+// LoadHooked returns the first bead hooked to identity in beadsDir, or nil.
+func LoadHooked(identity, beadsDir string) (*HookedWork, error) {
+	if identity == "" || beadsDir == "" {
+		return nil, fmt.Errorf("identity and dir are required")
+	}
+	hooked, err := beads.New(beadsDir).List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Assignee: identity,
+		Priority: -1,
+	})
+	if err != nil || len(hooked) == 0 {
+		return nil, err
+	}
+	return &HookedWork{ID: hooked[0].ID, Title: hooked[0].Title}, nil
+}
+
+// LoadMail returns identity's unread count and newest unread subject.
+func LoadMail(townRoot, identity string) (*MailPreview, error) {
+	if identity == "" {
+		return nil, fmt.Errorf("identity is required")
+	}
+	// NewMailboxFromAddress normalizes identity (e.g., gastown/crew/gus -> gastown/gus)
+	messages, err := mail.NewMailboxFromAddress(identity, townRoot).ListUnread()
+	if err != nil {
+		return nil, err
+	}
+	preview := &MailPreview{Unread: len(messages)}
+	if len(messages) > 0 {
+		preview.Subject = messages[0].Subject
+	}
+	return preview, nil
+}
+
+// LoadQueue summarizes a rig's refinery queue.
+func LoadQueue(townRoot, rigName string) (*QueueSummary, error) {
+	r, err := loadRig(townRoot, rigName)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := refinery.NewManager(r).Queue()
+	if err != nil {
+		return nil, err
+	}
+	summary := &QueueSummary{}
+	for _, item := range queue {
+		if item.Position == 0 && item.MR != nil {
+			summary.Current = item.MR.IssueID
+		} else {
+			summary.Pending++
+		}
+	}
+	return summary, nil
+}
+
+// LoadMQ counts the merge requests in a rig's beads.
+func LoadMQ(beadsDir string) (*MQCounts, error) {
+	if beadsDir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	b := beads.New(beadsDir)
+	opts := beads.ListOptions{Type: "merge-request", Status: "open", Priority: -1}
+	open, err := b.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Status = "in_progress"
+	inProgress, err := b.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	counts := &MQCounts{InFlight: len(inProgress)}
+	for _, mr := range open {
+		if mrBlocked(mr) {
+			counts.Blocked++
+		} else {
+			counts.Pending++
+		}
+	}
+	return counts, nil
+}
+
+// LoadAgents collects agent beads from the town and every rig, with the
+// beads they have hooked.
+func LoadAgents(townRoot string) (*AgentIndex, error) {
+	index := &AgentIndex{
+		Agents: make(map[string]*beads.Issue),
+		Hooks:  make(map[string]*beads.Issue),
+	}
+	collect := func(b *beads.Beads) {
+		agents, _ := b.ListAgentBeads()
+		var hookIDs []string
+		for id, issue := range agents {
+			index.Agents[id] = issue
+			if hookID := hookOf(issue); hookID != "" {
+				hookIDs = append(hookIDs, hookID)
+			}
+		}
+		if len(hookIDs) == 0 {
+			return
+		}
+		hooks, _ := b.ShowMultiple(hookIDs)
+		for id, issue := range hooks {
+			index.Hooks[id] = issue
+		}
+	}
+
+	collect(beads.New(beads.GetTownBeadsPath(townRoot)))
+	rigs, err := rigManager(townRoot).DiscoverRigs()
+	if err != nil {
+		return index, nil
+	}
+	for _, r := range rigs {
+		collect(beads.New(filepath.Join(r.Path, "mayor", "rig")))
+	}
+	return index, nil
+}
+
+func rigManager(townRoot string) *rig.Manager {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	return rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+}
+
+func loadRig(townRoot, rigName string) (*rig.Rig, error) {
+	if rigName == "" {
+		return nil, fmt.Errorf("rig is required")
+	}
+	r, err := rigManager(townRoot).GetRig(rigName)
+	if err != nil {
+		return nil, fmt.Errorf("rig '%s' not found", rigName)
+	}
+	return r, nil
+}
@@ -0,0 +1,334 @@
+package readmodel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeBd answers the list and show calls the snapshot makes from a map of
+// issues by ID, and counts them.
+type fakeBd struct {
+	issues map[string]map[string]any
+	calls  int
+}
+
+func (f *fakeBd) run(_ *database, args ...string) ([]byte, error) {
+	f.calls++
+	if args[0] == "show" {
+		issue, ok := f.issues[args[1]]
+		if !ok {
+			return nil, beads.ErrNotFound
+		}
+		return json.Marshal([]any{issue})
+	}
+	var out []any
+	for _, issue := range f.issues {
+		if f.matches(issue, args[1:]) {
+			out = append(out, issue)
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (f *fakeBd) matches(issue map[string]any, flags []string) bool {
+	for _, flag := range flags {
+		name, value, _ := strings.Cut(flag, "=")
+		switch name {
+		case "--label":
+			labels, _ := issue["labels"].([]string)
+			if !slices.Contains(labels, value) {
+				return false
+			}
+		case "--status":
+			if issue["status"] != value {
+				return false
+			}
+		case "--type":
+			if issue["issue_type"] != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func issue(id, status string, fields ...any) map[string]any {
+	m := map[string]any{"id": id, "title": "Title " + id, "status": status, "issue_type": "task"}
+	for i := 0; i+1 < len(fields); i += 2 {
+		m[fields[i].(string)] = fields[i+1]
+	}
+	return m
+}
+
+// newTestSnapshot returns a snapshot of a town database holding agents,
+// hooked work, merge requests and mail, and the fake bd behind it.
+func newTestSnapshot(t *testing.T) (*Snapshot, *database, *fakeBd) {
+	t.Helper()
+	town := t.TempDir()
+	bd := &fakeBd{issues: map[string]map[string]any{}}
+	for _, i := range []map[string]any{
+		issue("gt-agent", "open", "labels", []string{"gt:agent"}, "hook_bead", "gt-w1"),
+		issue("gt-w1", "in_progress"),
+		issue("gt-h1", "hooked", "assignee", "gastown/polecats/nux", "priority", 2),
+		issue("gt-h2", "hooked", "assignee", "gastown/polecats/nux", "priority", 1),
+		issue("gt-mr1", "open", "labels", []string{"gt:merge-request"}),
+		issue("gt-mr2", "open", "labels", []string{"gt:merge-request"}, "blocked_by", []string{"gt-x"}),
+		issue("gt-mr3", "in_progress", "labels", []string{"gt:merge-request"}),
+		issue("gt-mr4", "closed", "labels", []string{"gt:merge-request"}),
+		issue("gt-x", "open"),
+		issue("gt-m1", "open", "issue_type", "message", "assignee", "mayor/", "title", "Older", "created_at", "2026-01-01T00:00:00Z"),
+		issue("gt-m2", "open", "issue_type", "message", "assignee", "mayor/", "title", "Newer", "created_at", "2026-01-02T00:00:00Z"),
+		issue("gt-m3", "closed", "issue_type", "message", "assignee", "mayor/", "title", "Read"),
+	} {
+		bd.issues[i["id"].(string)] = i
+	}
+
+	s := newSnapshot(town)
+	s.bd = bd.run
+	db := s.add(town, beads.ResolveBeadsDir(town))
+	if err := s.load(db); err != nil {
+		t.Fatal(err)
+	}
+	return s, db, bd
+}
+
+func TestSnapshot_Load(t *testing.T) {
+	s, db, _ := newTestSnapshot(t)
+
+	var ids []string
+	for id := range db.issues {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	want := []string{"gt-agent", "gt-h1", "gt-h2", "gt-m1", "gt-m2", "gt-mr1", "gt-mr2", "gt-mr3", "gt-w1"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("loaded %v, want %v", ids, want)
+	}
+
+	town := s.townRoot
+	if h, err := s.Hooked("gastown/polecats/nux", town); err != nil || h == nil || h.ID != "gt-h2" {
+		t.Errorf("Hooked = %+v, %v; want gt-h2 (highest priority)", h, err)
+	}
+	if h, err := s.Hooked("gastown/polecats/other", town); err != nil || h != nil {
+		t.Errorf("Hooked for an idle agent = %+v, %v", h, err)
+	}
+	if c, err := s.MQ(town); err != nil || *c != (MQCounts{Pending: 1, InFlight: 1, Blocked: 1}) {
+		t.Errorf("MQ = %+v, %v", c, err)
+	}
+	if m, err := s.Mail("mayor/"); err != nil || m.Unread != 2 || m.Subject != "Newer" {
+		t.Errorf("Mail = %+v, %v", m, err)
+	}
+	index, err := s.Agents()
+	if err != nil || index.Agents["gt-agent"] == nil || index.Hooks["gt-w1"] == nil {
+		t.Errorf("Agents = %+v, %v", index, err)
+	}
+	if dbs, loaded, issues := s.size(); dbs != 1 || loaded != 1 || issues != len(want) {
+		t.Errorf("size = %d, %d, %d", dbs, loaded, issues)
+	}
+}
+
+func TestSnapshot_Apply(t *testing.T) {
+	s, db, bd := newTestSnapshot(t)
+	town := s.townRoot
+
+	// Closing a blocker re-reads the merge requests waiting on it
+	bd.issues["gt-x"]["status"] = "closed"
+	delete(bd.issues["gt-mr2"], "blocked_by")
+	bd.issues["gt-mr2"]["dependencies"] = []map[string]any{{"id": "gt-x", "status": "closed", "dependency_type": "blocks"}}
+	bd.calls = 0
+	if _, err := s.apply(db, "gt-x"); err != nil {
+		t.Fatal(err)
+	}
+	if bd.calls != 2 {
+		t.Errorf("apply made %d bd calls, want 2 (the blocker and its MR)", bd.calls)
+	}
+	if c, _ := s.MQ(town); *c != (MQCounts{Pending: 2, InFlight: 1}) {
+		t.Errorf("MQ after unblocking = %+v", c)
+	}
+
+	// A merged MR leaves the snapshot
+	bd.issues["gt-mr1"]["status"] = "closed"
+	if _, err := s.apply(db, "gt-mr1"); err != nil {
+		t.Fatal(err)
+	}
+	if db.issues["gt-mr1"] != nil {
+		t.Error("closed MR still tracked")
+	}
+
+	// A new hook is read; the old hooked bead is dropped
+	bd.issues["gt-agent"]["hook_bead"] = "gt-w2"
+	bd.issues["gt-w2"] = issue("gt-w2", "open")
+	if _, err := s.apply(db, "gt-agent"); err != nil {
+		t.Fatal(err)
+	}
+	index, _ := s.Agents()
+	if index.Hooks["gt-w2"] == nil || index.Hooks["gt-w1"] != nil {
+		t.Errorf("hooks after rehook = %v", index.Hooks)
+	}
+
+	// Deleted issues leave the snapshot, and apply reports them gone
+	delete(bd.issues, "gt-h2")
+	if got, err := s.apply(db, "gt-h2"); err != nil || got != nil {
+		t.Errorf("apply on a deleted issue = %v, %v", got, err)
+	}
+	if h, _ := s.Hooked("gastown/polecats/nux", town); h == nil || h.ID != "gt-h1" {
+		t.Errorf("Hooked after delete = %+v, want gt-h1", h)
+	}
+
+	// Reading mail drops it
+	bd.issues["gt-m2"]["status"] = "closed"
+	if _, err := s.apply(db, "gt-m2"); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := s.Mail("mayor/"); m.Unread != 1 || m.Subject != "Older" {
+		t.Errorf("Mail after read = %+v", m)
+	}
+
+	// A failed read leaves the database to be reloaded
+	s.bd = func(*database, ...string) ([]byte, error) { return nil, fmt.Errorf("bd failed") }
+	if _, err := s.apply(db, "gt-h1"); err == nil {
+		t.Error("apply with bd failing returned no error")
+	}
+}
+
+func TestServer_Get(t *testing.T) {
+	snap, db, _ := newTestSnapshot(t)
+	s := NewServer(snap.townRoot, t.Logf)
+	s.snap = snap
+
+	if _, err := s.Get("no-such-kind", nil); err != errUnknownKind {
+		t.Errorf("unknown kind err = %v", err)
+	}
+	if data, err := s.Get(KindMQ, map[string][]string{"dir": {snap.townRoot}}); err != nil || !strings.Contains(string(data), `"pending":1`) {
+		t.Errorf("Get mq = %s, %v", data, err)
+	}
+
+	// Databases not loaded (or not followed) are left to the client
+	if _, err := s.Get(KindMQ, map[string][]string{"dir": {t.TempDir()}}); err != errNotLoaded {
+		t.Errorf("Get for an unknown database err = %v", err)
+	}
+	snap.unload(db)
+	if _, err := s.Get(KindAgents, nil); err != errNotLoaded {
+		t.Errorf("Get while unloaded err = %v", err)
+	}
+
+	// Views are served as last computed
+	n := 0
+	RegisterView("test-view", func(string) (any, error) {
+		n++
+		return n, nil
+	})
+	if _, err := s.Get("test-view", nil); err != errNotLoaded {
+		t.Errorf("Get before the view is computed err = %v", err)
+	}
+	s.computeViews()
+	for i := 0; i < 2; i++ {
+		if data, err := s.Get("test-view", nil); err != nil || string(data) != "1" {
+			t.Errorf("Get view = %s, %v; want 1", data, err)
+		}
+	}
+	if st := s.Stats(); st.Databases != 1 || st.Loaded != 0 || st.Views == 0 || st.Queries != 7 {
+		t.Errorf("Stats = %+v", st)
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	snap, db, bd := newTestSnapshot(t)
+	s := NewServer(snap.townRoot, t.Logf)
+	s.snap = snap
+	loaded, err := time.Parse(time.RFC3339, "2026-03-01T12:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bd.issues["gt-mr1"]["status"] = "closed"
+	old := `{"timestamp":"2026-03-01T11:59:59Z","type":"status","issue_id":"gt-mr1","new_status":"closed"}`
+	if err := s.handle(db, []byte(old), loaded); err != nil || db.issues["gt-mr1"] == nil {
+		t.Errorf("event from before the load was applied (err %v)", err)
+	}
+	if len(s.recompute) != 0 {
+		t.Error("skipped event asked for a view recompute")
+	}
+
+	event := `{"timestamp":"2026-03-01T12:00:01Z","type":"status","issue_id":"gt-mr1","new_status":"closed"}`
+	if err := s.handle(db, []byte(event), loaded); err != nil || db.issues["gt-mr1"] != nil {
+		t.Errorf("event was not applied (err %v)", err)
+	}
+	if len(s.recompute) != 1 {
+		t.Error("status event didn't ask for a view recompute")
+	}
+	if err := s.handle(db, []byte("not json"), loaded); err != nil {
+		t.Errorf("malformed line err = %v", err)
+	}
+	if st := s.Stats(); st.Events != 1 {
+		t.Errorf("Events = %d, want 1", st.Events)
+	}
+}
+
+func TestQuery_OverSocket(t *testing.T) {
+	snap, _, _ := newTestSnapshot(t)
+	town := snap.townRoot
+	s := NewServer(town, t.Logf)
+	s.snap = snap
+
+	path := SocketPath(town)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: s.Handler()}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	var counts MQCounts
+	if err := Query(town, KindMQ, map[string][]string{"dir": {town}}, &counts); err != nil || counts.Pending != 1 {
+		t.Fatalf("Query = %+v, %v", counts, err)
+	}
+	if err := Query(town, "no-such-kind", nil, &counts); err == nil {
+		t.Error("Query for an unknown kind succeeded")
+	}
+
+	// Unfollowed databases fall back to the local result
+	v, err := Get(town, KindMQ, map[string][]string{"dir": {t.TempDir()}}, func() (*MQCounts, error) {
+		return &MQCounts{Pending: -2}, nil
+	})
+	if err != nil || v.Pending != -2 {
+		t.Errorf("Get for an unfollowed database = %+v, %v; want the local result", v, err)
+	}
+
+	var st Stats
+	if err := Query(town, "stats", nil, &st); err != nil || st.Loaded != 1 {
+		t.Errorf("stats = %+v, %v", st, err)
+	}
+
+	// Disabled clients load locally even with a daemon serving
+	t.Setenv(DisableEnv, "off")
+	got, err := Get(town, KindMQ, map[string][]string{"dir": {town}}, func() (*MQCounts, error) {
+		return &MQCounts{Pending: -1}, nil
+	})
+	if err != nil || got.Pending != -1 {
+		t.Errorf("Get with %s=off = %+v, %v; want the local result", DisableEnv, got, err)
+	}
+}
+
+func TestGet_FallsBackWithoutDaemon(t *testing.T) {
+	v, err := Get(t.TempDir(), KindMQ, nil, func() (*MQCounts, error) {
+		return &MQCounts{Pending: 2}, nil
+	})
+	if err != nil || v.Pending != 2 {
+		t.Errorf("Get = %+v, %v; want the local result", v, err)
+	}
+}
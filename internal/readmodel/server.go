@@ -0,0 +1,240 @@
+package readmodel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SocketPath is where the daemon serves the read model.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "readmodel.sock")
+}
+
+// Server follows bd activity in the town's databases and answers queries
+// from its snapshot.
+type Server struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	snap     *Snapshot
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	followers map[string]context.CancelFunc // By beads directory
+
+	viewsMu   sync.RWMutex
+	viewData  map[string]json.RawMessage
+	recompute chan struct{} // Coalesces view recompute requests
+
+	events  atomic.Uint64
+	loads   atomic.Uint64
+	queries atomic.Uint64
+
+	srv *http.Server
+}
+
+// Stats describes the server's snapshot.
+type Stats struct {
+	Databases int    `json:"databases"`
+	Loaded    int    `json:"loaded"` // Databases answering from the snapshot
+	Issues    int    `json:"issues"`
+	Events    uint64 `json:"events"` // Activity events applied
+	Loads     uint64 `json:"loads"`  // Full database loads
+	Queries   uint64 `json:"queries"`
+	Views     int    `json:"views"` // Views computed
+}
+
+// NewServer creates a read model server for the town.
+func NewServer(townRoot string, logger func(format string, args ...interface{})) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		townRoot:  townRoot,
+		logger:    logger,
+		snap:      newSnapshot(townRoot),
+		ctx:       ctx,
+		cancel:    cancel,
+		followers: make(map[string]context.CancelFunc),
+		viewData:  make(map[string]json.RawMessage),
+		recompute: make(chan struct{}, 1),
+	}
+}
+
+// Start listens on the town's socket, starts following the town's beads
+// databases and serves until Stop. Queries for a database that hasn't
+// loaded yet are answered 503, and clients compute them themselves.
+func (s *Server) Start() error {
+	path := SocketPath(s.townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// A socket left by a crashed daemon blocks Listen. The daemon lock
+	// guarantees no other server owns it.
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	_ = os.Chmod(path, 0600)
+	s.srv = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := s.srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger("read model: serve error: %v", err)
+		}
+	}()
+
+	s.discover()
+	s.wg.Add(2)
+	go s.discoverLoop()
+	go s.viewLoop()
+	return nil
+}
+
+// Stop closes the socket and stops following bd.
+func (s *Server) Stop() {
+	if s.srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = s.srv.Shutdown(ctx)
+	_ = os.Remove(SocketPath(s.townRoot))
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Handler serves GET /v1/<kind>?<params> and GET /v1/stats.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		kind := strings.TrimPrefix(r.URL.Path, "/v1/")
+		if r.Method != http.MethodGet || kind == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if kind == "stats" {
+			_ = json.NewEncoder(w).Encode(s.Stats())
+			return
+		}
+		data, err := s.Get(kind, r.URL.Query())
+		if err != nil {
+			switch {
+			case errors.Is(err, errUnknownKind):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, errNotLoaded):
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		_, _ = w.Write(data)
+	})
+}
+
+var errUnknownKind = errors.New("unknown query kind")
+
+// Get returns the JSON result of a query, computed from the snapshot or,
+// for a view, as last recomputed.
+func (s *Server) Get(kind string, params url.Values) (json.RawMessage, error) {
+	s.queries.Add(1)
+	var v any
+	var err error
+	switch kind {
+	case KindHooked:
+		v, err = s.snap.Hooked(params.Get("identity"), params.Get("dir"))
+	case KindMail:
+		v, err = s.snap.Mail(params.Get("identity"))
+	case KindQueue:
+		v, err = s.snap.Queue(params.Get("rig"))
+	case KindMQ:
+		v, err = s.snap.MQ(params.Get("dir"))
+	case KindAgents:
+		v, err = s.snap.Agents()
+	default:
+		return s.view(kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// view returns a view's last computed result.
+func (s *Server) view(kind string) (json.RawMessage, error) {
+	if _, ok := registeredViews()[kind]; !ok {
+		return nil, errUnknownKind
+	}
+	s.viewsMu.RLock()
+	defer s.viewsMu.RUnlock()
+	data, ok := s.viewData[kind]
+	if !ok {
+		return nil, errNotLoaded
+	}
+	return data, nil
+}
+
+// refreshViews asks for the views to be recomputed. Requests made while
+// a recompute is pending are merged into it.
+func (s *Server) refreshViews() {
+	select {
+	case s.recompute <- struct{}{}:
+	default:
+	}
+}
+
+// viewLoop recomputes views off the request path until the server stops.
+func (s *Server) viewLoop() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.recompute:
+			s.computeViews()
+		}
+	}
+}
+
+// computeViews runs every view loader. A failed loader keeps its last
+// result.
+func (s *Server) computeViews() {
+	for kind, load := range registeredViews() {
+		v, err := load(s.townRoot)
+		if err == nil {
+			var data json.RawMessage
+			if data, err = json.Marshal(v); err == nil {
+				s.viewsMu.Lock()
+				s.viewData[kind] = data
+				s.viewsMu.Unlock()
+				continue
+			}
+		}
+		s.logger("read model: computing %s: %v", kind, err)
+	}
+}
+
+// Stats reports snapshot counters.
+func (s *Server) Stats() Stats {
+	stats := Stats{
+		Events:  s.events.Load(),
+		Loads:   s.loads.Load(),
+		Queries: s.queries.Load(),
+	}
+	stats.Databases, stats.Loaded, stats.Issues = s.snap.size()
+	s.viewsMu.RLock()
+	stats.Views = len(s.viewData)
+	s.viewsMu.RUnlock()
+	return stats
+}
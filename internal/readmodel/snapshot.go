@@ -0,0 +1,481 @@
+package readmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+// errNotLoaded answers queries for a database the snapshot doesn't hold
+// (yet); clients compute those results themselves.
+var errNotLoaded = errors.New("not in the read model")
+
+// loadQueries list everything the snapshot tracks (see tracked), less the
+// beads agents have hooked, which are read by ID.
+var loadQueries = [][]string{
+	{"list", "--json", "--limit=0", "--label=gt:agent"},
+	{"list", "--json", "--limit=0", "--status=" + beads.StatusHooked},
+	{"list", "--json", "--limit=0", "--label=gt:merge-request", "--status=open"},
+	{"list", "--json", "--limit=0", "--label=gt:merge-request", "--status=in_progress"},
+	{"list", "--json", "--limit=0", "--type=message", "--status=open"},
+	{"list", "--json", "--limit=0", "--type=message", "--status=" + beads.StatusHooked},
+}
+
+// database is one beads database's part of the snapshot. Only the
+// database's follower writes it, under the snapshot's lock.
+type database struct {
+	workDir  string // Where bd runs for this database
+	beadsDir string
+	loaded   bool // A full load has completed
+	issues   map[string]*record
+}
+
+// record is a tracked issue as bd last reported it. The raw JSON is kept
+// for readers that decode more than beads.Issue holds (mail).
+type record struct {
+	issue *beads.Issue
+	raw   json.RawMessage
+}
+
+// Snapshot holds the tracked issues of the town and rig databases.
+type Snapshot struct {
+	townRoot string
+
+	// bd runs a bd command against a database; tests replace it.
+	bd func(db *database, args ...string) ([]byte, error)
+
+	mu  sync.RWMutex
+	dbs map[string]*database // By beads directory
+}
+
+func newSnapshot(townRoot string) *Snapshot {
+	return &Snapshot{
+		townRoot: townRoot,
+		bd:       runBd,
+		dbs:      make(map[string]*database),
+	}
+}
+
+func runBd(db *database, args ...string) ([]byte, error) {
+	return beads.NewWithBeadsDir(db.workDir, db.beadsDir).Run(args...)
+}
+
+// tracked reports whether the snapshot keeps an issue: agent beads, hooked
+// work, merge requests not yet merged, and mail not yet read. Beads hooked
+// by an agent are kept too, whatever their state.
+func tracked(issue *beads.Issue) bool {
+	switch {
+	case issue.Status == beads.StatusHooked:
+		return true
+	case beads.HasLabel(issue, "gt:agent"):
+		return issue.Status != "closed"
+	case issue.Type == "message":
+		return issue.Status == "open"
+	case beads.HasLabel(issue, "gt:merge-request"):
+		return issue.Status == "open" || issue.Status == "in_progress"
+	}
+	return false
+}
+
+// hookOf returns the bead an agent bead has hooked, if any.
+func hookOf(issue *beads.Issue) string {
+	if !beads.HasLabel(issue, "gt:agent") {
+		return ""
+	}
+	// Use the HookBead column; fall back to description fields for legacy beads
+	if issue.HookBead != "" {
+		return issue.HookBead
+	}
+	if fields := beads.ParseAgentFields(issue.Description); fields != nil {
+		return fields.HookBead
+	}
+	return ""
+}
+
+// mrBlocked reports whether an open merge request waits on another issue.
+// List output counts blockers; show output lists dependencies.
+func mrBlocked(mr *beads.Issue) bool {
+	if len(mr.BlockedBy) > 0 || mr.BlockedByCount > 0 {
+		return true
+	}
+	for _, dep := range mr.Dependencies {
+		if (dep.DependencyType == "" || dep.DependencyType == "blocks") && dep.Status != "closed" {
+			return true
+		}
+	}
+	return false
+}
+
+// waitsOn reports whether a merge request lists id as a blocker, so a
+// change to id can change whether it is blocked.
+func waitsOn(mr *beads.Issue, id string) bool {
+	if !beads.HasLabel(mr, "gt:merge-request") {
+		return false
+	}
+	if slices.Contains(mr.BlockedBy, id) {
+		return true
+	}
+	for _, dep := range mr.Dependencies {
+		if dep.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// decode parses a bd list or show JSON array.
+func decode(out []byte) ([]*record, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(out, &raws); err != nil {
+		return nil, err
+	}
+	recs := make([]*record, 0, len(raws))
+	for _, raw := range raws {
+		var issue beads.Issue
+		if err := json.Unmarshal(raw, &issue); err != nil {
+			return nil, err
+		}
+		recs = append(recs, &record{issue: &issue, raw: raw})
+	}
+	return recs, nil
+}
+
+// add registers a database, unloaded, and returns it. A known database is
+// returned as is.
+func (s *Snapshot) add(workDir, beadsDir string) *database {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if db := s.dbs[beadsDir]; db != nil {
+		return db
+	}
+	db := &database{workDir: workDir, beadsDir: beadsDir, issues: make(map[string]*record)}
+	s.dbs[beadsDir] = db
+	return db
+}
+
+// remove drops a database that no longer exists.
+func (s *Snapshot) remove(beadsDir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dbs, beadsDir)
+}
+
+// unload marks a database as out of date, so its queries fall back to bd
+// until the next load.
+func (s *Snapshot) unload(db *database) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	db.loaded = false
+}
+
+// size counts databases, loaded databases and tracked issues.
+func (s *Snapshot) size() (dbs, loaded, issues int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, db := range s.dbs {
+		dbs++
+		if db.loaded {
+			loaded++
+			issues += len(db.issues)
+		}
+	}
+	return dbs, loaded, issues
+}
+
+// load replaces a database's issues with a full read from bd.
+func (s *Snapshot) load(db *database) error {
+	issues := make(map[string]*record)
+	for _, args := range loadQueries {
+		out, err := s.bd(db, args...)
+		if err != nil {
+			return err
+		}
+		recs, err := decode(out)
+		if err != nil {
+			return fmt.Errorf("parsing bd %s: %w", args[0], err)
+		}
+		for _, r := range recs {
+			issues[r.issue.ID] = r
+		}
+	}
+	var hooks []string
+	for _, r := range issues {
+		if h := hookOf(r.issue); h != "" && issues[h] == nil {
+			hooks = append(hooks, h)
+		}
+	}
+	hooked, err := s.show(db, hooks...)
+	if err != nil {
+		return err
+	}
+	for id, r := range hooked {
+		issues[id] = r
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	db.issues = issues
+	db.loaded = true
+	return nil
+}
+
+// apply brings a database's part of the snapshot up to date after an
+// activity event on issue id: the issue is re-read, with the merge
+// requests it blocks and any bead an agent newly has hooked. It returns
+// the issue as re-read, or nil if it is gone.
+func (s *Snapshot) apply(db *database, id string) (*beads.Issue, error) {
+	ids := []string{id}
+	s.mu.RLock()
+	for other, r := range db.issues {
+		if other != id && waitsOn(r.issue, id) {
+			ids = append(ids, other)
+		}
+	}
+	s.mu.RUnlock()
+	fetched, err := s.show(db, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []string
+	s.mu.RLock()
+	for _, r := range fetched {
+		if h := hookOf(r.issue); h != "" && db.issues[h] == nil && fetched[h] == nil {
+			hooks = append(hooks, h)
+		}
+	}
+	s.mu.RUnlock()
+	hooked, err := s.show(db, hooks...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, i := range ids {
+		if r := fetched[i]; r != nil {
+			db.issues[i] = r
+		} else {
+			delete(db.issues, i)
+		}
+	}
+	for h, r := range hooked {
+		db.issues[h] = r
+	}
+	db.prune()
+	if r := fetched[id]; r != nil {
+		return r.issue, nil
+	}
+	return nil, nil
+}
+
+// show reads issues by ID. Issues that no longer exist are left out.
+func (s *Snapshot) show(db *database, ids ...string) (map[string]*record, error) {
+	out := make(map[string]*record, len(ids))
+	for _, id := range ids {
+		data, err := s.bd(db, "show", id, "--json")
+		if errors.Is(err, beads.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		recs, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing bd show: %w", err)
+		}
+		for _, r := range recs {
+			out[r.issue.ID] = r
+		}
+	}
+	return out, nil
+}
+
+// prune drops issues that are neither tracked nor hooked by an agent.
+// The caller holds the snapshot's lock.
+func (db *database) prune() {
+	hooked := make(map[string]bool)
+	for _, r := range db.issues {
+		if h := hookOf(r.issue); h != "" {
+			hooked[h] = true
+		}
+	}
+	for id, r := range db.issues {
+		if !tracked(r.issue) && !hooked[id] {
+			delete(db.issues, id)
+		}
+	}
+}
+
+// database returns the loaded database workDir uses. The caller holds
+// the snapshot's lock.
+func (s *Snapshot) database(workDir string) (*database, error) {
+	db := s.dbs[beads.ResolveBeadsDir(workDir)]
+	if db == nil || !db.loaded {
+		return nil, errNotLoaded
+	}
+	return db, nil
+}
+
+// Hooked returns the first bead hooked to identity in workDir's database,
+// or nil.
+func (s *Snapshot) Hooked(identity, workDir string) (*HookedWork, error) {
+	if identity == "" || workDir == "" {
+		return nil, fmt.Errorf("identity and dir are required")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.database(workDir)
+	if err != nil {
+		return nil, err
+	}
+	var hooked []*beads.Issue
+	for _, r := range db.issues {
+		if r.issue.Status == beads.StatusHooked && r.issue.Assignee == identity {
+			hooked = append(hooked, r.issue)
+		}
+	}
+	if len(hooked) == 0 {
+		return nil, nil
+	}
+	// bd lists by priority, then age
+	sort.Slice(hooked, func(i, j int) bool {
+		if hooked[i].Priority != hooked[j].Priority {
+			return hooked[i].Priority < hooked[j].Priority
+		}
+		return hooked[i].CreatedAt < hooked[j].CreatedAt
+	})
+	return &HookedWork{ID: hooked[0].ID, Title: hooked[0].Title}, nil
+}
+
+// Mail returns identity's unread count and newest unread subject.
+func (s *Snapshot) Mail(identity string) (*MailPreview, error) {
+	if identity == "" {
+		return nil, fmt.Errorf("identity is required")
+	}
+	s.mu.RLock()
+	db, err := s.database(s.townRoot)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, err
+	}
+	var msgs []mail.BeadsMessage
+	for _, r := range db.issues {
+		if r.issue.Type != "message" {
+			continue
+		}
+		var bm mail.BeadsMessage
+		if json.Unmarshal(r.raw, &bm) == nil {
+			msgs = append(msgs, bm)
+		}
+	}
+	s.mu.RUnlock()
+
+	// NewMailboxFromAddress normalizes identity (e.g., gastown/crew/gus -> gastown/gus)
+	messages := mail.NewMailboxFromAddress(identity, s.townRoot).Select(msgs)
+	preview := &MailPreview{Unread: len(messages)}
+	if len(messages) > 0 {
+		preview.Subject = messages[0].Subject
+	}
+	return preview, nil
+}
+
+// MQ counts the merge requests in workDir's database.
+func (s *Snapshot) MQ(workDir string) (*MQCounts, error) {
+	if workDir == "" {
+		return nil, fmt.Errorf("dir is required")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.database(workDir)
+	if err != nil {
+		return nil, err
+	}
+	counts := &MQCounts{}
+	for _, r := range db.issues {
+		if !beads.HasLabel(r.issue, "gt:merge-request") {
+			continue
+		}
+		switch {
+		case r.issue.Status == "in_progress":
+			counts.InFlight++
+		case r.issue.Status != "open":
+		case mrBlocked(r.issue):
+			counts.Blocked++
+		default:
+			counts.Pending++
+		}
+	}
+	return counts, nil
+}
+
+// Queue summarizes a rig's refinery queue: the MR the refinery's state file
+// says it is merging, and the other open MRs.
+func (s *Snapshot) Queue(rigName string) (*QueueSummary, error) {
+	r, err := loadRig(s.townRoot, rigName)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := refinery.NewManager(r).Status()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	db, err := s.database(r.BeadsPath())
+	if err != nil {
+		return nil, err
+	}
+	summary := &QueueSummary{}
+	if ref.CurrentMR != nil {
+		summary.Current = ref.CurrentMR.IssueID
+	}
+	for id, rec := range db.issues {
+		if !beads.HasLabel(rec.issue, "gt:merge-request") || rec.issue.Status != "open" {
+			continue
+		}
+		if ref.CurrentMR != nil && ref.CurrentMR.ID == id {
+			continue
+		}
+		summary.Pending++
+	}
+	return summary, nil
+}
+
+// Agents returns every agent bead in the town and rigs, with the beads
+// they have hooked. It needs every database loaded.
+func (s *Snapshot) Agents() (*AgentIndex, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	index := &AgentIndex{
+		Agents: make(map[string]*beads.Issue),
+		Hooks:  make(map[string]*beads.Issue),
+	}
+	for _, db := range s.dbs {
+		if !db.loaded {
+			return nil, errNotLoaded
+		}
+		for id, r := range db.issues {
+			if !beads.HasLabel(r.issue, "gt:agent") {
+				continue
+			}
+			index.Agents[id] = r.issue
+			if h := hookOf(r.issue); h != "" && db.issues[h] != nil {
+				index.Hooks[h] = db.issues[h].issue
+			}
+		}
+	}
+	return index, nil
+}
@@ -0,0 +1,172 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"` // Unix seconds at start
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is one timed chunk of a recording. Type "o" is terminal output.
+type Event struct {
+	Time time.Duration // Since the start of the recording
+	Type string
+	Data string
+}
+
+// MarshalJSON encodes the event as asciicast's [seconds, type, data].
+func (e Event) MarshalJSON() ([]byte, error) {
+	secs := strconv.FormatFloat(e.Time.Seconds(), 'f', 6, 64)
+	return json.Marshal([]interface{}{json.Number(secs), e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes an asciicast [seconds, type, data] event.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) < 3 {
+		return fmt.Errorf("asciicast event has %d fields, want 3", len(raw))
+	}
+	var secs float64
+	if err := json.Unmarshal(raw[0], &secs); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[2], &e.Data); err != nil {
+		return err
+	}
+	e.Time = time.Duration(secs * float64(time.Second))
+	return nil
+}
+
+// Writer writes an asciicast v2 recording. Each output chunk is written
+// as its own line straight away, so a recording cut short by a crash is
+// still readable up to the last chunk.
+type Writer struct {
+	w       io.Writer
+	start   time.Time
+	pending []byte // Incomplete UTF-8 sequence, completed by the next chunk
+}
+
+// NewWriter writes the header and returns a Writer whose event times are
+// measured from now.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	h.Version = 2
+	line, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, start: time.Now()}, nil
+}
+
+// Output records terminal output at the given offset. A multi-byte
+// character split across chunks is held back until it is complete, since
+// asciicast data must be valid UTF-8.
+func (w *Writer) Output(at time.Duration, data []byte) error {
+	data = append(w.pending, data...)
+	data, w.pending = splitIncomplete(data)
+	if len(data) == 0 {
+		return nil
+	}
+	line, err := json.Marshal(Event{Time: at, Type: "o", Data: string(data)})
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
+
+// Record copies r into the recording until EOF, timing each chunk as it
+// arrives.
+func (w *Writer) Record(r io.Reader) error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if werr := w.Output(time.Since(w.start), buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// splitIncomplete splits off a trailing partial UTF-8 sequence.
+func splitIncomplete(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i > len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], append([]byte(nil), b[i:]...)
+			}
+			break
+		}
+	}
+	return b, nil
+}
+
+// Decoder reads an asciicast v2 recording.
+type Decoder struct {
+	Header  Header
+	scanner *bufio.Scanner
+}
+
+// NewDecoder reads the header of a recording.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("empty recording")
+	}
+	d := &Decoder{scanner: scanner}
+	if err := json.Unmarshal(scanner.Bytes(), &d.Header); err != nil {
+		return nil, fmt.Errorf("reading asciicast header: %w", err)
+	}
+	if d.Header.Version != 2 {
+		return nil, fmt.Errorf("unsupported asciicast version %d", d.Header.Version)
+	}
+	return d, nil
+}
+
+// Next returns the next event, or io.EOF at the end. Malformed lines, such
+// as a last line cut short, are skipped.
+func (d *Decoder) Next() (Event, error) {
+	for d.scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(d.scanner.Bytes(), &e); err == nil {
+			return e, nil
+		}
+	}
+	if err := d.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
@@ -0,0 +1,179 @@
+package recording
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// PlayOptions control playback.
+type PlayOptions struct {
+	Speed     float64       // Playback rate (2 = twice as fast); <= 0 means 1
+	IdleLimit time.Duration // Longest pause between events (0 = as recorded)
+	From      time.Duration // Output before this offset is written at once
+}
+
+// Play writes the recording's output to out with its original timing,
+// until the recording ends or ctx is done.
+func Play(ctx context.Context, d *Decoder, out io.Writer, opts PlayOptions) error {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	last := opts.From
+	for {
+		e, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if e.Type != "o" {
+			continue
+		}
+		if e.Time > last {
+			wait := e.Time - last
+			if opts.IdleLimit > 0 && wait > opts.IdleLimit {
+				wait = opts.IdleLimit
+			}
+			timer := time.NewTimer(time.Duration(float64(wait) / speed))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+			last = e.Time
+		}
+		if _, err := io.WriteString(out, e.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// Match is a line of recorded output containing a search term.
+type Match struct {
+	Time time.Duration // When the line started to be written
+	Line string
+}
+
+// Search returns the lines of recorded output containing query, ignoring
+// case and terminal escape sequences. A line repeated by a screen redraw
+// is reported once.
+func Search(d *Decoder, query string) ([]Match, error) {
+	query = strings.ToLower(query)
+	var matches []Match
+	var text textLines
+	emit := func(m Match) {
+		if !strings.Contains(strings.ToLower(m.Line), query) {
+			return
+		}
+		if n := len(matches); n > 0 && matches[n-1].Line == m.Line {
+			return
+		}
+		matches = append(matches, m)
+	}
+	for {
+		e, err := d.Next()
+		if err == io.EOF {
+			text.flush(emit)
+			return matches, nil
+		}
+		if err != nil {
+			return matches, err
+		}
+		if e.Type == "o" {
+			text.feed(e.Data, e.Time, emit)
+		}
+	}
+}
+
+// maxLineLen bounds a line of text; longer runs are split.
+const maxLineLen = 4096
+
+// textLines turns terminal output into plain lines of text, dropping
+// escape sequences (which may be split across events) and control
+// characters.
+type textLines struct {
+	state int
+	line  strings.Builder
+	start time.Duration
+}
+
+const (
+	stateText    = iota
+	stateEsc     // After ESC
+	stateCSI     // ESC [ ... final byte
+	stateOSC     // ESC ] ... BEL or ESC \
+	stateOSCEsc  // ESC inside an OSC
+	stateCharset // ESC ( or ESC ) and its designator
+)
+
+func (l *textLines) feed(data string, t time.Duration, emit func(Match)) {
+	for _, c := range data {
+		switch l.state {
+		case stateEsc:
+			switch c {
+			case '[':
+				l.state = stateCSI
+			case ']':
+				l.state = stateOSC
+			case '(', ')':
+				l.state = stateCharset
+			default:
+				l.state = stateText
+			}
+		case stateCSI:
+			if c >= 0x40 && c <= 0x7e {
+				l.state = stateText
+			}
+		case stateOSC:
+			if c == 0x07 {
+				l.state = stateText
+			} else if c == 0x1b {
+				l.state = stateOSCEsc
+			}
+		case stateOSCEsc:
+			if c == '\\' {
+				l.state = stateText
+			} else {
+				l.state = stateOSC
+			}
+		case stateCharset:
+			l.state = stateText
+		default:
+			switch {
+			case c == 0x1b:
+				l.state = stateEsc
+			case c == '\n':
+				l.flush(emit)
+			case c == '\t':
+				l.add(' ', t, emit)
+			case c < 0x20 || c == 0x7f:
+				// Other control characters (\r, bell, backspace) are dropped
+			default:
+				l.add(c, t, emit)
+			}
+		}
+	}
+}
+
+func (l *textLines) add(c rune, t time.Duration, emit func(Match)) {
+	if l.line.Len() == 0 {
+		l.start = t
+	}
+	l.line.WriteRune(c)
+	if l.line.Len() >= maxLineLen {
+		l.flush(emit)
+	}
+}
+
+func (l *textLines) flush(emit func(Match)) {
+	line := strings.TrimSpace(l.line.String())
+	l.line.Reset()
+	if line != "" {
+		emit(Match{Time: l.start, Line: line})
+	}
+}
@@ -0,0 +1,221 @@
+// Package recording records agent panes as asciicast v2 files and plays
+// them back.
+//
+// A recording is started from 'gt prime' by piping the agent's tmux pane
+// (pipe-pane) into 'gt replay recorder', which timestamps every chunk of
+// output. Recordings live in <town>/logs/recordings/<id>.cast, gzipped
+// once finished, and are indexed in index.jsonl by tmux session, agent
+// session ID and bead. Both are pruned per the "recordings" retention
+// policy.
+package recording
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/retention"
+)
+
+// IndexFile is the recordings index, in Dir.
+const IndexFile = "index.jsonl"
+
+// stampLayout suffixes recording IDs; it sorts lexically in time order.
+const stampLayout = "20060102T150405Z"
+
+// Dir returns the town's recordings directory.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "recordings")
+}
+
+// Recording is an index entry. The first entry for an ID registers the
+// recording; later ones add the agent sessions and beads seen in it.
+type Recording struct {
+	ID         string    `json:"id"`
+	Session    string    `json:"session,omitempty"` // tmux session
+	Actor      string    `json:"actor,omitempty"`
+	Started    time.Time `json:"started,omitempty"`
+	SessionIDs []string  `json:"session_ids,omitempty"` // Agent runtime sessions, as in gt seance
+	Beads      []string  `json:"beads,omitempty"`
+
+	// Set by List from the file on disk
+	Path      string    `json:"-"`
+	LastWrite time.Time `json:"-"`
+}
+
+// New returns a recording of a tmux session starting now.
+func New(townRoot, session, actor string, now time.Time) Recording {
+	id := session + "-" + now.UTC().Format(stampLayout)
+	return Recording{
+		ID:      id,
+		Session: session,
+		Actor:   actor,
+		Started: now,
+		Path:    filepath.Join(Dir(townRoot), id+".cast"),
+	}
+}
+
+// Append adds an entry to the index.
+func Append(townRoot string, rec Recording) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(Dir(townRoot), IndexFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is not sensitive
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// List returns the town's recordings that still exist on disk, oldest
+// first, with their index entries merged.
+func List(townRoot string) ([]Recording, error) {
+	f, err := retention.Open(filepath.Join(Dir(townRoot), IndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	byID := make(map[string]*Recording)
+	var order []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Recording
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.ID == "" {
+			continue
+		}
+		rec := byID[entry.ID]
+		if rec == nil {
+			rec = &Recording{ID: entry.ID}
+			byID[entry.ID] = rec
+			order = append(order, entry.ID)
+		}
+		rec.merge(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var recs []Recording
+	for _, id := range order {
+		rec := byID[id]
+		base := filepath.Join(Dir(townRoot), id+".cast")
+		for _, path := range []string{base, base + ".gz"} {
+			if info, err := os.Stat(path); err == nil {
+				rec.Path, rec.LastWrite = path, info.ModTime()
+				break
+			}
+		}
+		if rec.Path != "" {
+			recs = append(recs, *rec)
+		}
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Started.Before(recs[j].Started) })
+	return recs, nil
+}
+
+func (r *Recording) merge(entry Recording) {
+	if r.Session == "" {
+		r.Session = entry.Session
+	}
+	if r.Actor == "" {
+		r.Actor = entry.Actor
+	}
+	if r.Started.IsZero() {
+		r.Started = entry.Started
+	}
+	r.SessionIDs = appendUnique(r.SessionIDs, entry.SessionIDs...)
+	r.Beads = appendUnique(r.Beads, entry.Beads...)
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found && item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Matches reports whether query names the recording: its ID, tmux
+// session, an agent session ID, its agent's address, or a bead worked in
+// it.
+func (r Recording) Matches(query string) bool {
+	if query == r.ID || query == r.Session || SameAgent(query, r.Actor) {
+		return true
+	}
+	for _, list := range [][]string{r.SessionIDs, r.Beads} {
+		for _, s := range list {
+			if s == query {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Covers reports whether the recording was running at t.
+func (r Recording) Covers(t time.Time) bool {
+	return !t.Before(r.Started) && !t.After(r.LastWrite)
+}
+
+// SameAgent reports whether two addresses name the same agent, allowing
+// for the short polecat form "<rig>/<name>".
+func SameAgent(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	norm := func(s string) string {
+		return strings.Replace(strings.TrimSuffix(s, "/"), "/polecats/", "/", 1)
+	}
+	return norm(a) == norm(b)
+}
+
+// Open opens the recording file, decompressing it if it was gzipped.
+func (r Recording) Open() (io.ReadCloser, error) {
+	f, err := os.Open(r.Path) //nolint:gosec // G304: path is under the town root
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(r.Path, ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: gz, f: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzipFile) Close() error {
+	_ = g.Reader.Close()
+	return g.f.Close()
+}
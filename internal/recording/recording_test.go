@@ -0,0 +1,178 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/retention"
+)
+
+func TestWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{Width: 120, Height: 40, Title: "gt-gastown-Toast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	euro := []byte("€") // Three bytes, split across two chunks
+	chunks := []struct {
+		at   time.Duration
+		data []byte
+	}{
+		{0, []byte("$ ls\r\n")},
+		{1500 * time.Millisecond, append([]byte("cost "), euro[:2]...)},
+		{1600 * time.Millisecond, append(euro[2:], '\n')},
+	}
+	for _, c := range chunks {
+		if err := w.Output(c.at, c.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := NewDecoder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Header.Version != 2 || d.Header.Width != 120 || d.Header.Title != "gt-gastown-Toast" {
+		t.Errorf("header = %+v", d.Header)
+	}
+	var events []Event
+	for {
+		e, err := d.Next()
+		if err != nil {
+			break
+		}
+		events = append(events, e)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(events), events)
+	}
+	if events[1].Data != "cost " || events[2].Data != "€\n" {
+		t.Errorf("split character not held back: %q, %q", events[1].Data, events[2].Data)
+	}
+	if events[1].Time != 1500*time.Millisecond || events[1].Type != "o" {
+		t.Errorf("event = %+v", events[1])
+	}
+}
+
+func TestDecoder_SkipsTruncatedLine(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24}
+[0.1, "o", "one\n"]
+[0.2, "o", "tw`
+	d, err := NewDecoder(strings.NewReader(cast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err := d.Next(); err != nil || e.Data != "one\n" {
+		t.Fatalf("Next = %+v, %v", e, err)
+	}
+	if _, err := d.Next(); err == nil {
+		t.Error("truncated event decoded")
+	}
+
+	if _, err := NewDecoder(strings.NewReader(`{"version":1}`)); err == nil {
+		t.Error("version 1 accepted")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24}
+[1.0, "o", "\u001b[1mBuilding\u001b[0m...\r\n"]
+[2.0, "o", "\u001b]0;title\u0007$ rm -rf "]
+[2.5, "o", "build/\r\n"]
+[3.0, "o", "\u001b[2K$ rm -rf build/\r\n"]
+[9.0, "o", "done\n"]
+`
+	d, err := NewDecoder(strings.NewReader(cast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := Search(d, "RM -RF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The redraw at 3.0 repeats the line and is reported once
+	if len(matches) != 1 {
+		t.Fatalf("matches = %+v, want 1", matches)
+	}
+	if matches[0].Line != "$ rm -rf build/" || matches[0].Time != 2*time.Second {
+		t.Errorf("match = %+v", matches[0])
+	}
+}
+
+func TestPlay(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24}
+[0.5, "o", "a"]
+[30.0, "o", "b"]
+[31.0, "o", "c"]
+`
+	d, _ := NewDecoder(strings.NewReader(cast))
+	var out bytes.Buffer
+	start := time.Now()
+	err := Play(context.Background(), d, &out, PlayOptions{Speed: 100, IdleLimit: time.Second, From: 30 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "abc" {
+		t.Errorf("played %q, want abc", out.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("playback took %v; --from and idle limit not applied", elapsed)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	town := t.TempDir()
+	if recs, err := List(town); err != nil || recs != nil {
+		t.Fatalf("List(empty) = %v, %v", recs, err)
+	}
+
+	t0 := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	rec := New(town, "gt-gastown-Toast", "gastown/polecats/Toast", t0)
+	rec.SessionIDs = []string{"sess-1"}
+	if err := Append(town, rec); err != nil {
+		t.Fatal(err)
+	}
+	_ = Append(town, Recording{ID: rec.ID, Beads: []string{"gt-abc"}})
+	_ = Append(town, Recording{ID: rec.ID, SessionIDs: []string{"sess-2", "sess-1"}})
+
+	// Indexed but never written: not listed
+	if recs, _ := List(town); len(recs) != 0 {
+		t.Fatalf("listed a recording with no file: %+v", recs)
+	}
+
+	if err := os.WriteFile(rec.Path, []byte(`{"version":2}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retention.Compress(rec.Path); err != nil {
+		t.Fatal(err)
+	}
+	recs, err := List(town)
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("List = %+v, %v", recs, err)
+	}
+	got := recs[0]
+	if !strings.HasSuffix(got.Path, ".cast.gz") || len(got.SessionIDs) != 2 || got.Actor != "gastown/polecats/Toast" {
+		t.Errorf("merged = %+v", got)
+	}
+	for _, q := range []string{rec.ID, "gt-gastown-Toast", "sess-2", "gt-abc", "gastown/Toast"} {
+		if !got.Matches(q) {
+			t.Errorf("Matches(%q) = false", q)
+		}
+	}
+	if got.Matches("gt-ab") || got.Matches("gastown/Nux") {
+		t.Error("matched a different bead or agent")
+	}
+
+	f, err := got.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if d, err := NewDecoder(f); err != nil || d.Header.Version != 2 {
+		t.Errorf("reading gzipped recording: %v", err)
+	}
+}
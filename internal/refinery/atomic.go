@@ -0,0 +1,274 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/protocol"
+	"github.com/steveyegge/gastown/internal/telemetry"
+)
+
+// Atomic convoy landing.
+//
+// MRs tracked by an atomic convoy are not pushed when their tests pass.
+// The refinery records the verified merge result on a local held/<mr-id>
+// branch and parks the MR (claimed, with held_commit set) so it leaves the
+// ready queue. Once every tracked MR across rigs is held, the convoy
+// coordinator (gt convoy land) checks each rig's held tip still
+// fast-forwards its target and pushes them together.
+
+// HeldBranchPrefix prefixes the local branches holding verified MRs.
+const HeldBranchPrefix = "held/"
+
+// HeldBranch returns the local branch holding an MR's verified result.
+func HeldBranch(mrID string) string {
+	return HeldBranchPrefix + mrID
+}
+
+// HeldMR is a parked MR awaiting coordinated landing.
+type HeldMR struct {
+	Issue  *beads.Issue
+	Fields *beads.MRFields
+}
+
+// HoldMR parks a verified MR for coordinated landing: ref (the tested merge
+// result, e.g. "temp") is saved on the MR's held branch, the MR records the
+// commit, and the MR stays claimed by this refinery so it leaves the ready
+// queue. Returns the held commit SHA.
+func (e *Engineer) HoldMR(mrID, ref string) (_ string, err error) {
+	sha, err := e.git.Rev(ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	mr, err := e.beads.Show(mrID)
+	if err != nil {
+		return "", fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		return "", fmt.Errorf("%s has no MR fields", mrID)
+	}
+	span := telemetry.StartRemote(fields.TraceParent, "refinery.hold",
+		telemetry.String("gt.mr", mrID), telemetry.String("gt.held_commit", sha))
+	defer func() { span.End(err) }()
+	bd := e.beads.WithContext(telemetry.ContextWithSpan(context.Background(), span))
+
+	if err := e.git.ResetBranch(HeldBranch(mrID), sha); err != nil {
+		return "", fmt.Errorf("saving held branch: %w", err)
+	}
+
+	fields.HeldCommit = sha
+	fields.HeldAt = time.Now().UTC().Format(time.RFC3339)
+	desc := beads.SetMRFields(mr, fields)
+	holder := e.rig.Name + "/refinery"
+	if err := bd.Update(mrID, beads.UpdateOptions{Description: &desc, Assignee: &holder}); err != nil {
+		return "", fmt.Errorf("updating MR %s: %w", mrID, err)
+	}
+	return sha, nil
+}
+
+// ReleaseHeldMR un-parks an MR: the held branch is deleted, the held commit
+// cleared, and the MR unclaimed so the refinery verifies it again against
+// the current target.
+func (e *Engineer) ReleaseHeldMR(mrID string) error {
+	mr, err := e.beads.Show(mrID)
+	if err != nil {
+		return fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	if fields := beads.ParseMRFields(mr); fields != nil {
+		fields.HeldCommit = ""
+		fields.HeldAt = ""
+		desc := beads.SetMRFields(mr, fields)
+		empty := ""
+		if err := e.beads.Update(mrID, beads.UpdateOptions{Description: &desc, Assignee: &empty}); err != nil {
+			return fmt.Errorf("updating MR %s: %w", mrID, err)
+		}
+	}
+	_ = e.git.DeleteBranch(HeldBranch(mrID), true)
+	return nil
+}
+
+// ListMRsForIssues returns open MRs whose source issue is in the given set.
+func (e *Engineer) ListMRsForIssues(sourceIssues map[string]bool) ([]*HeldMR, error) {
+	issues, err := e.beads.List(beads.ListOptions{
+		Status:   "open",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	var mrs []*HeldMR
+	for _, issue := range issues {
+		fields := beads.ParseMRFields(issue)
+		if fields == nil || !sourceIssues[fields.SourceIssue] {
+			continue
+		}
+		mrs = append(mrs, &HeldMR{Issue: issue, Fields: fields})
+	}
+	return mrs, nil
+}
+
+// SelectMRs picks one MR per source issue: the held one if there is one,
+// otherwise the most recently created. An issue resubmitted without its
+// earlier MR being closed has several open MRs, and only one of them may
+// land. Order follows mrs.
+func SelectMRs(mrs []*HeldMR) []*HeldMR {
+	best := make(map[string]*HeldMR)
+	for _, mr := range mrs {
+		cur := best[mr.Fields.SourceIssue]
+		if cur == nil || preferMR(mr, cur) {
+			best[mr.Fields.SourceIssue] = mr
+		}
+	}
+	var selected []*HeldMR
+	for _, mr := range mrs {
+		if best[mr.Fields.SourceIssue] == mr {
+			selected = append(selected, mr)
+		}
+	}
+	return selected
+}
+
+// preferMR reports whether a should land instead of b for the same issue.
+func preferMR(a, b *HeldMR) bool {
+	if aHeld, bHeld := a.Fields.HeldCommit != "", b.Fields.HeldCommit != ""; aHeld != bHeld {
+		return aHeld
+	}
+	aCreated, errA := time.Parse(time.RFC3339, a.Issue.CreatedAt)
+	bCreated, errB := time.Parse(time.RFC3339, b.Issue.CreatedAt)
+	if errA != nil || errB != nil {
+		return a.Issue.CreatedAt > b.Issue.CreatedAt
+	}
+	return aCreated.After(bCreated)
+}
+
+// HeldBase returns what an atomic convoy's next MR in this rig should be
+// rebased onto: the convoy's held tip when another of its MRs is already
+// held here (so the held commits stack and land together), otherwise
+// origin/<target>.
+func (e *Engineer) HeldBase(held []*HeldMR, target string) string {
+	var shas []string
+	for _, mr := range held {
+		if mr.Fields.HeldCommit != "" && mr.Fields.Target == target {
+			shas = append(shas, mr.Fields.HeldCommit)
+		}
+	}
+	if len(shas) > 0 {
+		if tip, err := e.heldTip(shas); err == nil {
+			return tip
+		}
+	}
+	return "origin/" + target
+}
+
+// CheckHeld verifies a rig's held MRs can land: they must share a target,
+// form a single line of history, and fast-forward the current remote
+// target. Returns the tip commit to push.
+func (e *Engineer) CheckHeld(held []*HeldMR) (tip, target string, err error) {
+	var shas []string
+	for _, mr := range held {
+		if mr.Fields.HeldCommit == "" {
+			return "", "", fmt.Errorf("%s is not held", mr.Issue.ID)
+		}
+		if target == "" {
+			target = mr.Fields.Target
+		} else if mr.Fields.Target != target {
+			return "", "", fmt.Errorf("held MRs target both %s and %s", target, mr.Fields.Target)
+		}
+		shas = append(shas, mr.Fields.HeldCommit)
+	}
+	if len(shas) == 0 {
+		return "", "", fmt.Errorf("no held MRs")
+	}
+
+	tip, err = e.heldTip(shas)
+	if err != nil {
+		return "", "", err
+	}
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		return "", "", fmt.Errorf("fetching origin/%s: %w", target, err)
+	}
+	ok, err := e.git.IsAncestor("origin/"+target, tip)
+	if err != nil {
+		return "", "", fmt.Errorf("checking origin/%s: %w", target, err)
+	}
+	if !ok {
+		return "", "", fmt.Errorf("origin/%s moved since verification", target)
+	}
+	return tip, target, nil
+}
+
+// heldTip returns the commit among shas that contains all the others, or an
+// error if they diverge.
+func (e *Engineer) heldTip(shas []string) (string, error) {
+	tip := shas[0]
+	for _, sha := range shas[1:] {
+		if sha == tip {
+			continue
+		}
+		if ok, err := e.git.IsAncestor(tip, sha); err != nil {
+			return "", err
+		} else if ok {
+			tip = sha
+			continue
+		}
+		if ok, err := e.git.IsAncestor(sha, tip); err != nil {
+			return "", err
+		} else if !ok {
+			return "", fmt.Errorf("held commits %s and %s diverge", short(tip), short(sha))
+		}
+	}
+	return tip, nil
+}
+
+// PushHeld pushes a held tip to the remote target. The push is not forced:
+// if the target moved after CheckHeld, git rejects it.
+func (e *Engineer) PushHeld(tip, target string) error {
+	if err := e.git.Push("origin", tip+":refs/heads/"+target, false); err != nil {
+		return fmt.Errorf("pushing to origin/%s: %w", target, err)
+	}
+	return nil
+}
+
+// CompleteHeldMR finishes an MR after its held commit landed: the MR and
+// source issue are closed as for a normal merge, the witness is told the
+// branch merged, and the held branch is removed.
+func (e *Engineer) CompleteHeldMR(mr *HeldMR) {
+	f := mr.Fields
+	// The merge span runs from when the MR was held to its landing
+	span := telemetry.StartRemote(f.TraceParent, "refinery.merge",
+		telemetry.String("gt.mr", mr.Issue.ID), telemetry.String("gt.target", f.Target),
+		telemetry.String("gt.merge_commit", f.HeldCommit), telemetry.Bool("gt.atomic", true))
+	if held, err := time.Parse(time.RFC3339, f.HeldAt); err == nil {
+		span.SetStart(held)
+	}
+	defer span.End(nil)
+	info := &MRInfo{
+		ID:          mr.Issue.ID,
+		Branch:      f.Branch,
+		Target:      f.Target,
+		SourceIssue: f.SourceIssue,
+		Worker:      f.Worker,
+		Rig:         f.Rig,
+		AgentBead:   f.AgentBead,
+	}
+	e.HandleMRInfoSuccess(info, ProcessResult{Success: true, MergeCommit: f.HeldCommit})
+
+	if f.Worker != "" {
+		msg := protocol.NewMergedMessage(e.rig.Name, f.Worker, f.Branch, f.SourceIssue, f.Target, f.HeldCommit)
+		if err := e.router.Send(msg); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGED to witness: %v\n", err)
+		}
+	}
+	_ = e.git.DeleteBranch(HeldBranch(mr.Issue.ID), true)
+}
+
+func short(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
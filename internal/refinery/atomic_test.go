@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupHeldRig creates an origin repo with one commit on main and a rig
+// whose refinery worktree is a clone of it.
+func setupHeldRig(t *testing.T) (*Engineer, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	origin := filepath.Join(tmp, "origin.git")
+	rigPath := filepath.Join(tmp, "rig")
+	work := filepath.Join(rigPath, "refinery", "rig")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(tmp, "init", "--bare", "-b", "main", origin)
+	run(tmp, "clone", origin, work)
+	run(work, "config", "user.email", "test@test.com")
+	run(work, "config", "user.name", "Test User")
+	run(work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-m", "initial")
+	run(work, "push", "origin", "main")
+
+	return NewEngineer(&rig.Rig{Name: "test-rig", Path: rigPath}), work
+}
+
+// commit adds a commit on top of base and returns its SHA.
+func commit(t *testing.T, work, base, file string) string {
+	t.Helper()
+	for _, args := range [][]string{
+		{"checkout", "-q", "--detach", base},
+		{"commit", "-q", "--allow-empty", "-m", file},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	out, err := exec.Command("git", "-C", work, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func heldMR(id, sha string) *HeldMR {
+	return &HeldMR{
+		Issue:  &beads.Issue{ID: id},
+		Fields: &beads.MRFields{Target: "main", HeldCommit: sha},
+	}
+}
+
+func TestSelectMRs(t *testing.T) {
+	mr := func(id, source, created, held string) *HeldMR {
+		return &HeldMR{
+			Issue:  &beads.Issue{ID: id, CreatedAt: created},
+			Fields: &beads.MRFields{SourceIssue: source, HeldCommit: held},
+		}
+	}
+	mrs := []*HeldMR{
+		mr("mr-1", "gt-a", "2026-01-01T10:00:00Z", ""),
+		mr("mr-2", "gt-a", "2026-01-01T12:00:00Z", ""), // Newest wins
+		mr("mr-3", "gt-b", "2026-01-01T10:00:00Z", "abc123"),
+		mr("mr-4", "gt-b", "2026-01-01T12:00:00Z", ""), // Held one wins over newer
+		mr("mr-5", "gt-c", "2026-01-01T10:00:00Z", ""),
+	}
+	var got []string
+	for _, m := range SelectMRs(mrs) {
+		got = append(got, m.Issue.ID)
+	}
+	if strings.Join(got, ",") != "mr-2,mr-3,mr-5" {
+		t.Errorf("SelectMRs = %v, want [mr-2 mr-3 mr-5]", got)
+	}
+}
+
+func TestCheckHeld_StackedCommitsLandTogether(t *testing.T) {
+	e, work := setupHeldRig(t)
+	first := commit(t, work, "origin/main", "api")
+	second := commit(t, work, first, "client")
+
+	held := []*HeldMR{heldMR("mr-2", second), heldMR("mr-1", first)}
+	if base := e.HeldBase(held[1:], "main"); base != first {
+		t.Errorf("HeldBase = %s, want first held commit", base)
+	}
+
+	tip, target, err := e.CheckHeld(held)
+	if err != nil {
+		t.Fatalf("CheckHeld: %v", err)
+	}
+	if tip != second || target != "main" {
+		t.Errorf("tip = %s (%s), want %s", tip, target, second)
+	}
+
+	if err := e.PushHeld(tip, target); err != nil {
+		t.Fatalf("PushHeld: %v", err)
+	}
+	out, _ := exec.Command("git", "-C", work, "ls-remote", "origin", "main").Output()
+	if !strings.HasPrefix(string(out), second) {
+		t.Errorf("origin/main = %s, want %s", out, second)
+	}
+}
+
+func TestCheckHeld_DivergedCommits(t *testing.T) {
+	e, work := setupHeldRig(t)
+	a := commit(t, work, "origin/main", "a")
+	b := commit(t, work, "origin/main", "b")
+
+	_, _, err := e.CheckHeld([]*HeldMR{heldMR("mr-a", a), heldMR("mr-b", b)})
+	if err == nil || !strings.Contains(err.Error(), "diverge") {
+		t.Errorf("err = %v, want diverge", err)
+	}
+	if base := e.HeldBase([]*HeldMR{heldMR("mr-a", a), heldMR("mr-b", b)}, "main"); base != "origin/main" {
+		t.Errorf("HeldBase = %s, want origin/main", base)
+	}
+}
+
+func TestCheckHeld_TargetMoved(t *testing.T) {
+	e, work := setupHeldRig(t)
+	held := commit(t, work, "origin/main", "held")
+
+	// Someone else lands on main after verification
+	other := commit(t, work, "origin/main", "other")
+	if out, err := exec.Command("git", "-C", work, "push", "origin", other+":refs/heads/main").CombinedOutput(); err != nil {
+		t.Fatalf("push: %v\n%s", err, out)
+	}
+
+	_, _, err := e.CheckHeld([]*HeldMR{heldMR("mr-1", held)})
+	if err == nil || !strings.Contains(err.Error(), "moved since verification") {
+		t.Errorf("err = %v, want moved", err)
+	}
+	if err := e.PushHeld(held, "main"); err == nil {
+		t.Error("non-fast-forward push should be rejected")
+	}
+}
+
+func TestCheckHeld_NotHeld(t *testing.T) {
+	e, _ := setupHeldRig(t)
+	if _, _, err := e.CheckHeld([]*HeldMR{heldMR("mr-1", "")}); err == nil {
+		t.Error("expected error for MR without held commit")
+	}
+}
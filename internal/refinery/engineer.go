@@ -2,22 +2,23 @@
 package refinery
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/telemetry"
 )
 
 // MergeQueueConfig holds configuration for the merge queue processor.
@@ -51,8 +52,47 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// MergeMode is "direct" (merge locally and push to the target) or
+	// "pull_request" (open a PR through the forge and merge it via the API
+	// once its checks pass).
+	MergeMode string `json:"merge_mode"`
+
+	// Forge configures the forge API for pull_request mode.
+	Forge forge.Config `json:"forge"`
+
+	// PRMergeMethod is how the forge merges PRs: merge, squash or rebase.
+	PRMergeMethod forge.MergeMethod `json:"pr_merge_method"`
+
+	// PRTimeout is how long one pass waits on a PR's checks and reviews
+	// before returning the MR to the queue to check again later.
+	PRTimeout time.Duration `json:"pr_timeout"`
+
+	// Gates is the ordered pre-merge gate pipeline. When empty, the only
+	// gate is TestCommand (if RunTests is set).
+	Gates []GateConfig `json:"gates"`
+
+	// TestCache lets tests gates reuse a passing run of an identical merged
+	// tree (same test command and environment fingerprint).
+	TestCache bool `json:"test_cache"`
+
+	// TestCacheTTL is how long a cached pass stays valid.
+	TestCacheTTL time.Duration `json:"test_cache_ttl"`
+
+	// TestCacheEnv names the environment variables in the cache key.
+	// Default: DefaultTestCacheEnv.
+	TestCacheEnv []string `json:"test_cache_env"`
+
+	// Flaky tunes how tests gates classify flaky tests from their history.
+	Flaky FlakyConfig `json:"flaky"`
 }
 
+// Merge modes.
+const (
+	MergeModeDirect      = "direct"
+	MergeModePullRequest = "pull_request"
+)
+
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
@@ -66,6 +106,12 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		MergeMode:            MergeModeDirect,
+		PRMergeMethod:        forge.MergeCommit,
+		PRTimeout:            10 * time.Minute,
+		TestCache:            true,
+		TestCacheTTL:         DefaultTestCacheTTL,
+		Flaky:                DefaultFlakyConfig(),
 	}
 }
 
@@ -86,6 +132,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	TraceParent     string     // Traceparent of the work's trace (from gt done)
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -98,6 +145,7 @@ type Engineer struct {
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
 	router  *mail.Router // Mail router for sending protocol messages
+	forge   forge.Forge  // Forge client for pull_request mode (created on first use)
 
 	// stopCh is used for graceful shutdown
 	stopCh chan struct{}
@@ -163,18 +211,31 @@ func (e *Engineer) LoadConfig() error {
 	// Parse merge_queue section into our config struct
 	// We need special handling for poll_interval (string -> Duration)
 	var mqRaw struct {
-		Enabled              *bool   `json:"enabled"`
-		TargetBranch         *string `json:"target_branch"`
-		IntegrationBranches  *bool   `json:"integration_branches"`
-		OnConflict           *string `json:"on_conflict"`
-		RunTests             *bool   `json:"run_tests"`
-		TestCommand          *string `json:"test_command"`
-		DeleteMergedBranches *bool   `json:"delete_merged_branches"`
-		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
-		PollInterval         *string `json:"poll_interval"`
-		MaxConcurrent        *int    `json:"max_concurrent"`
+		Enabled              *bool         `json:"enabled"`
+		TargetBranch         *string       `json:"target_branch"`
+		IntegrationBranches  *bool         `json:"integration_branches"`
+		OnConflict           *string       `json:"on_conflict"`
+		RunTests             *bool         `json:"run_tests"`
+		TestCommand          *string       `json:"test_command"`
+		DeleteMergedBranches *bool         `json:"delete_merged_branches"`
+		RetryFlakyTests      *int          `json:"retry_flaky_tests"`
+		PollInterval         *string       `json:"poll_interval"`
+		MaxConcurrent        *int          `json:"max_concurrent"`
+		MergeMode            *string       `json:"merge_mode"`
+		Forge                *forge.Config `json:"forge"`
+		PRMergeMethod        *string       `json:"pr_merge_method"`
+		PRTimeout            *string       `json:"pr_timeout"`
+		Gates                []GateConfig  `json:"gates"`
+		TestCache            *bool         `json:"test_cache"`
+		TestCacheTTL         *string       `json:"test_cache_ttl"`
+		TestCacheEnv         []string      `json:"test_cache_env"`
+		Flaky                *FlakyConfig  `json:"flaky"`
 	}
 
+	// Decode flaky over the defaults so unset fields keep them
+	flaky := e.config.Flaky
+	mqRaw.Flaky = &flaky
+
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
 		return fmt.Errorf("parsing merge_queue config: %w", err)
 	}
@@ -214,6 +275,55 @@ func (e *Engineer) LoadConfig() error {
 		}
 		e.config.PollInterval = dur
 	}
+	if mqRaw.MergeMode != nil {
+		if *mqRaw.MergeMode != MergeModeDirect && *mqRaw.MergeMode != MergeModePullRequest {
+			return fmt.Errorf("invalid merge_mode %q: want %q or %q", *mqRaw.MergeMode, MergeModeDirect, MergeModePullRequest)
+		}
+		e.config.MergeMode = *mqRaw.MergeMode
+	}
+	if mqRaw.Forge != nil {
+		e.config.Forge = *mqRaw.Forge
+	}
+	if mqRaw.PRMergeMethod != nil {
+		switch m := forge.MergeMethod(*mqRaw.PRMergeMethod); m {
+		case forge.MergeCommit, forge.MergeSquash, forge.MergeRebase:
+			e.config.PRMergeMethod = m
+		default:
+			return fmt.Errorf("invalid pr_merge_method %q: want merge, squash or rebase", *mqRaw.PRMergeMethod)
+		}
+	}
+	if mqRaw.PRTimeout != nil {
+		dur, err := time.ParseDuration(*mqRaw.PRTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid pr_timeout %q: %w", *mqRaw.PRTimeout, err)
+		}
+		e.config.PRTimeout = dur
+	}
+	if mqRaw.TestCache != nil {
+		e.config.TestCache = *mqRaw.TestCache
+	}
+	if mqRaw.TestCacheTTL != nil {
+		dur, err := time.ParseDuration(*mqRaw.TestCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid test_cache_ttl %q: %w", *mqRaw.TestCacheTTL, err)
+		}
+		e.config.TestCacheTTL = dur
+	}
+	if mqRaw.TestCacheEnv != nil {
+		e.config.TestCacheEnv = mqRaw.TestCacheEnv
+	}
+	if mqRaw.Flaky != nil {
+		if t := mqRaw.Flaky.Threshold; t < 0 || t > 1 {
+			return fmt.Errorf("invalid flaky threshold %v: want 0-1", t)
+		}
+		e.config.Flaky = *mqRaw.Flaky
+	}
+	if mqRaw.Gates != nil {
+		e.config.Gates = mqRaw.Gates
+		if _, err := BuildGates(e.config); err != nil {
+			return fmt.Errorf("invalid gates: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -230,6 +340,31 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// Failure classifies failures that don't fit Conflict or TestsFailed
+	// (e.g. a forge refusing a push); empty otherwise.
+	Failure FailureType
+
+	// Pending means the MR is waiting on its PR's checks or reviews. It is
+	// not a failure: the MR goes back to the queue and is checked again.
+	Pending bool
+
+	// PRURL is the pull request the MR landed through, in pull_request mode.
+	PRURL string
+
+	// Gates holds the pre-merge gate results, recorded on the MR bead.
+	Gates []GateResult
+}
+
+// err returns the failure as an error, for ending a trace span.
+func (r ProcessResult) err() error {
+	if r.Success {
+		return nil
+	}
+	if r.Error == "" {
+		return errors.New("merge failed")
+	}
+	return errors.New(r.Error)
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -249,7 +384,11 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
-	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+	span := telemetry.StartRemote(mrFields.TraceParent, "refinery.process",
+		telemetry.String("gt.mr", mr.ID), telemetry.String("gt.branch", mrFields.Branch))
+	result := e.doMerge(telemetry.ContextWithSpan(ctx, span), mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
+	span.End(result.err())
+	return result
 }
 
 // doMerge performs the actual git merge operation.
@@ -271,6 +410,13 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// In pull_request mode the verified branch is force-pushed later; note
+	// where origin has it now so pushes made meanwhile aren't overwritten
+	var lease string
+	if e.config.MergeMode == MergeModePullRequest {
+		lease = e.remoteBranchSHA(branch)
+	}
+
 	// Step 2: Checkout the target branch
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking out target branch %s...\n", target)
 	if err := e.git.Checkout(target); err != nil {
@@ -304,18 +450,35 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 4: Run tests if configured
-	if e.config.RunTests && e.config.TestCommand != "" {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
-		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	// Step 4: Run the pre-merge gates (tests, lint, policy checks) on a
+	// trial merge, so tests see (and the test cache is keyed by) the tree
+	// the merge will produce. The trial is undone afterwards; merging the
+	// same commits again yields the same tree.
+	gates, err := BuildGates(e.config)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("invalid gates: %v", err)}
+	}
+	if err := e.git.MergeNoCommit(branch); err != nil {
+		_ = e.git.AbortMerge()
+		return ProcessResult{Error: fmt.Sprintf("trial merge of %s failed: %v", branch, err)}
+	}
+	env := e.gateEnv(target, branch, branch)
+	gateResults, result := RunGates(ctx, gates, env, e.output)
+	e.fileFlakyBugs(env.Flaky)
+	_ = e.git.AbortMerge() // Fails harmlessly when the branch was already merged
+	if !result.Success {
+		result.Gates = gateResults
+		return result
+	}
+	if len(gates) > 0 {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Gates passed")
+	}
+
+	// In pull_request mode the forge merges once its own checks pass
+	if e.config.MergeMode == MergeModePullRequest {
+		result = e.mergeViaPR(ctx, branch, branch, target, sourceIssue, lease)
+		result.Gates = gateResults
+		return result
 	}
 
 	// Step 5: Perform the actual merge
@@ -364,54 +527,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	return ProcessResult{
 		Success:     true,
 		MergeCommit: mergeCommit,
-	}
-}
-
-// runTests runs the configured test command and returns the result.
-func (e *Engineer) runTests(ctx context.Context) ProcessResult {
-	if e.config.TestCommand == "" {
-		return ProcessResult{Success: true}
-	}
-
-	// Run the test command with retries for flaky tests
-	maxRetries := e.config.RetryFlakyTests
-	if maxRetries < 1 {
-		maxRetries = 1
-	}
-
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if attempt > 1 {
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
-		}
-
-		// Note: TestCommand comes from rig's config.json (trusted infrastructure config),
-		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
-		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-
-		err := cmd.Run()
-		if err == nil {
-			return ProcessResult{Success: true}
-		}
-		lastErr = err
-
-		// Check if context was canceled
-		if ctx.Err() != nil {
-			return ProcessResult{
-				Success: false,
-				Error:   "test run canceled",
-			}
-		}
-	}
-
-	return ProcessResult{
-		Success:     false,
-		TestsFailed: true,
-		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		Gates:       gateResults,
 	}
 }
 
@@ -503,7 +619,55 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	span := telemetry.StartRemote(mr.TraceParent, "refinery.process",
+		telemetry.String("gt.mr", mr.ID), telemetry.String("gt.branch", mr.Branch))
+	result := e.doMerge(telemetry.ContextWithSpan(ctx, span), mr.Branch, mr.Target, mr.SourceIssue)
+	span.End(result.err())
+	return result
+}
+
+// MarkMerged records that the refinery merged and pushed an MR itself (the
+// agent-driven flow): ref's commit becomes the MR's merge_commit and the MR
+// is closed. Its refinery.merge span runs from the MR's last update (gate
+// results) to now. Returns the merge commit and the close reason.
+func (e *Engineer) MarkMerged(mrID, ref string) (_, _ string, err error) {
+	sha, err := e.git.Rev(ref)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving %s: %w", ref, err)
+	}
+	mr, err := e.beads.Show(mrID)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching MR %s: %w", mrID, err)
+	}
+	fields := beads.ParseMRFields(mr)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	target := fields.Target
+	if target == "" {
+		target = e.config.TargetBranch
+	}
+
+	span := telemetry.StartRemote(fields.TraceParent, "refinery.merge",
+		telemetry.String("gt.mr", mrID), telemetry.String("gt.target", target),
+		telemetry.String("gt.merge_commit", sha))
+	if updated, err := time.Parse(time.RFC3339, mr.UpdatedAt); err == nil {
+		span.SetStart(updated)
+	}
+	defer func() { span.End(err) }()
+	bd := e.beads.WithContext(telemetry.ContextWithSpan(context.Background(), span))
+
+	fields.MergeCommit = sha
+	fields.CloseReason = string(CloseReasonMerged)
+	desc := beads.SetMRFields(mr, fields)
+	if err := bd.Update(mrID, beads.UpdateOptions{Description: &desc}); err != nil {
+		return "", "", fmt.Errorf("recording merge commit on %s: %w", mrID, err)
+	}
+	reason := fmt.Sprintf("Merged to %s at %s", target, short(sha))
+	if err := bd.CloseWithReason(reason, mrID); err != nil {
+		return "", "", fmt.Errorf("closing MR %s: %w", mrID, err)
+	}
+	return sha, reason, nil
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -536,6 +700,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			}
 			mrFields.MergeCommit = result.MergeCommit
 			mrFields.CloseReason = "merged"
+			if len(result.Gates) > 0 {
+				mrFields.Gates = FormatGateResults(result.Gates)
+			}
 			newDesc := beads.SetMRFields(mrBead, mrFields)
 			if err := e.beads.Update(mr.ID, beads.UpdateOptions{Description: &newDesc}); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to update MR %s with merge commit: %v\n", mr.ID, err)
@@ -584,6 +751,17 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
+	// A PR still waiting on checks or reviews isn't a failure
+	if result.Pending {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ⏳ Waiting: %s - %s\n", mr.ID, result.Error)
+		return
+	}
+
+	// Record which gates ran and how they fared
+	if len(result.Gates) > 0 {
+		e.recordGates(mr.ID, result.Gates)
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -591,6 +769,8 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		failureType = "conflict"
 	} else if result.TestsFailed {
 		failureType = "tests"
+	} else if result.Failure != FailureNone {
+		failureType = string(result.Failure)
 	}
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
@@ -809,6 +989,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			ConvoyID:        fields.ConvoyID,
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
+			TraceParent:     fields.TraceParent,
 		}
 		mrs = append(mrs, mr)
 	}
@@ -897,6 +1078,7 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 			ConvoyID:        fields.ConvoyID,
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
+			TraceParent:     fields.TraceParent,
 			BlockedBy:       blockedBy,
 		}
 		mrs = append(mrs, mr)
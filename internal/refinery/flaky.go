@@ -0,0 +1,393 @@
+package refinery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/testreport"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FlakyConfig tunes flaky test classification (merge_queue.flaky).
+type FlakyConfig struct {
+	// Threshold is the flip rate (outcome changes per run, 0-1) at or above
+	// which a test counts as flaky.
+	Threshold float64 `json:"threshold"`
+
+	// MinRuns is how many recorded runs a test needs before it is classified.
+	MinRuns int `json:"min_runs"`
+
+	// Window is how many recent runs are kept per test.
+	Window int `json:"window"`
+
+	// BugAfter files a bug once a flaky test has failed this many times
+	// (0 disables bug filing).
+	BugAfter int `json:"bug_after"`
+}
+
+// DefaultFlakyConfig returns the default classification settings.
+func DefaultFlakyConfig() FlakyConfig {
+	return FlakyConfig{Threshold: 0.3, MinRuns: 5, Window: 30, BugAfter: 3}
+}
+
+// TestRun is one recorded outcome of a test.
+type TestRun struct {
+	At     time.Time         `json:"at"`
+	MR     string            `json:"mr,omitempty"` // MR (or branch) being tested
+	Status testreport.Status `json:"status"`
+	Rerun  bool              `json:"rerun,omitempty"` // Outcome of a retry of a failure
+}
+
+// TestHistory is one test's recent outcomes and quarantine state.
+type TestHistory struct {
+	ID   string    `json:"id"`
+	Runs []TestRun `json:"runs"`
+
+	// Flaky and FlipRate are recomputed whenever a run is recorded.
+	Flaky    bool    `json:"flaky,omitempty"`
+	FlipRate float64 `json:"flip_rate"`
+
+	// FlakyFailures counts failures recorded while the test was flaky.
+	FlakyFailures int `json:"flaky_failures,omitempty"`
+
+	Quarantined      bool      `json:"quarantined,omitempty"`
+	QuarantinedAt    time.Time `json:"quarantined_at,omitempty"`
+	QuarantineReason string    `json:"quarantine_reason,omitempty"`
+
+	// BugID is the bug filed for this test, if any.
+	BugID string `json:"bug_id,omitempty"`
+
+	// BugClaimedAt is when a refinery started filing the test's bug.
+	// Others leave the test alone until bugClaimTTL has passed.
+	BugClaimedAt time.Time `json:"bug_claimed_at,omitempty"`
+}
+
+// bugClaimTTL is how long a claim to file a flaky test's bug holds, so a
+// refinery that died while filing doesn't block the bug forever.
+const bugClaimTTL = 10 * time.Minute
+
+// bugClaimed reports whether another filing of the test's bug is under way.
+func (h *TestHistory) bugClaimed(now time.Time) bool {
+	return !h.BugClaimedAt.IsZero() && now.Sub(h.BugClaimedAt) < bugClaimTTL
+}
+
+// Failures counts failing runs in the window.
+func (h *TestHistory) Failures() int {
+	n := 0
+	for _, r := range h.Runs {
+		if r.Status == testreport.Fail {
+			n++
+		}
+	}
+	return n
+}
+
+// flipRate is the fraction of consecutive non-skipped runs whose outcome
+// differs, and the number of runs it was computed over.
+func (h *TestHistory) flipRate() (float64, int) {
+	var last testreport.Status
+	runs, flips := 0, 0
+	for _, r := range h.Runs {
+		if r.Status == testreport.Skip {
+			continue
+		}
+		if runs > 0 && r.Status != last {
+			flips++
+		}
+		last = r.Status
+		runs++
+	}
+	if runs < 2 {
+		return 0, runs
+	}
+	return float64(flips) / float64(runs-1), runs
+}
+
+// FlakyDB is the on-disk test history for a rig.
+type FlakyDB struct {
+	Tests map[string]*TestHistory `json:"tests"`
+}
+
+// FlakyStore records per-test history across refinery runs in
+// <rig>/.runtime/test-history.json and classifies tests as flaky by how
+// often their outcome flips. Updates take a file lock, so a quarantine from
+// the CLI isn't lost to a concurrent gate run.
+type FlakyStore struct {
+	path string
+	cfg  FlakyConfig
+	now  func() time.Time
+}
+
+// NewFlakyStore returns the rig's test history store.
+func NewFlakyStore(rigPath string, cfg FlakyConfig) *FlakyStore {
+	def := DefaultFlakyConfig()
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = def.Threshold
+	}
+	if cfg.MinRuns <= 0 {
+		cfg.MinRuns = def.MinRuns
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = def.Window
+	}
+	return &FlakyStore{
+		path: filepath.Join(rigPath, ".runtime", "test-history.json"),
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// Load reads the history. A missing file is an empty history.
+func (s *FlakyStore) Load() (*FlakyDB, error) {
+	db := &FlakyDB{Tests: make(map[string]*TestHistory)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return db, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	if db.Tests == nil {
+		db.Tests = make(map[string]*TestHistory)
+	}
+	return db, nil
+}
+
+// Update loads the history under the lock, applies fn and saves it.
+func (s *FlakyStore) Update(fn func(db *FlakyDB) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	lock := flock.New(s.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking test history: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	db, err := s.Load()
+	if err != nil {
+		return err
+	}
+	if err := fn(db); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(s.path, db)
+}
+
+// Record adds one run's results. rerun marks results from retrying failures,
+// so a failure that passes on retry shows up as a flip.
+func (s *FlakyStore) Record(mr string, results []testreport.Result, rerun bool) error {
+	if len(results) == 0 {
+		return nil
+	}
+	now := s.now()
+	return s.Update(func(db *FlakyDB) error {
+		for _, r := range results {
+			if r.Name == "" {
+				continue // Suite-level failures (e.g. build errors) aren't tests
+			}
+			h := db.Tests[r.ID()]
+			if h == nil {
+				h = &TestHistory{ID: r.ID()}
+				db.Tests[h.ID] = h
+			}
+			h.Runs = append(h.Runs, TestRun{At: now, MR: mr, Status: r.Status, Rerun: rerun})
+			if len(h.Runs) > s.cfg.Window {
+				h.Runs = h.Runs[len(h.Runs)-s.cfg.Window:]
+			}
+			s.classify(h)
+			if h.Flaky && r.Status == testreport.Fail {
+				h.FlakyFailures++
+			}
+		}
+		return nil
+	})
+}
+
+// classify recomputes a test's flip rate and flaky flag.
+func (s *FlakyStore) classify(h *TestHistory) {
+	rate, runs := h.flipRate()
+	h.FlipRate = rate
+	h.Flaky = runs >= s.cfg.MinRuns && rate >= s.cfg.Threshold
+}
+
+// Ignorable reports whether a failure of the test may be ignored: always
+// when quarantined, and when classified flaky if ignoreFlaky is set.
+func (db *FlakyDB) Ignorable(id string, ignoreFlaky bool) bool {
+	h := db.Tests[id]
+	if h == nil {
+		return false
+	}
+	return h.Quarantined || (ignoreFlaky && h.Flaky)
+}
+
+// Quarantine marks a test's failures as ignorable. The test need not have
+// any history yet.
+func (s *FlakyStore) Quarantine(id, reason string) error {
+	now := s.now()
+	return s.Update(func(db *FlakyDB) error {
+		h := db.Tests[id]
+		if h == nil {
+			h = &TestHistory{ID: id}
+			db.Tests[id] = h
+		}
+		h.Quarantined = true
+		h.QuarantinedAt = now
+		h.QuarantineReason = reason
+		return nil
+	})
+}
+
+// Unquarantine makes a test's failures block merges again.
+func (s *FlakyStore) Unquarantine(id string) error {
+	return s.Update(func(db *FlakyDB) error {
+		h := db.Tests[id]
+		if h == nil || !h.Quarantined {
+			return fmt.Errorf("test %q is not quarantined", id)
+		}
+		h.Quarantined = false
+		h.QuarantinedAt = time.Time{}
+		h.QuarantineReason = ""
+		return nil
+	})
+}
+
+// List returns flaky and quarantined tests (every tracked test with all),
+// worst flip rate first.
+func (db *FlakyDB) List(all bool) []*TestHistory {
+	var list []*TestHistory
+	for _, h := range db.Tests {
+		if all || h.Flaky || h.Quarantined {
+			list = append(list, h)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].FlipRate != list[j].FlipRate {
+			return list[i].FlipRate > list[j].FlipRate
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// BugCandidates returns flaky tests that have failed BugAfter times and
+// have no bug filed or being filed.
+func (s *FlakyStore) BugCandidates(db *FlakyDB) []*TestHistory {
+	if s.cfg.BugAfter <= 0 {
+		return nil
+	}
+	now := s.now()
+	var list []*TestHistory
+	for _, h := range db.List(false) {
+		if h.Flaky && h.BugID == "" && !h.bugClaimed(now) && h.FlakyFailures >= s.cfg.BugAfter {
+			list = append(list, h)
+		}
+	}
+	return list
+}
+
+// ClaimBug claims the filing of a test's bug under the lock, so concurrent
+// refineries sharing the rig's history don't both file it. It returns the
+// test's history as of the claim, or nil if the test already has a bug or
+// another claim holds.
+func (s *FlakyStore) ClaimBug(id string) (*TestHistory, error) {
+	var claimed *TestHistory
+	err := s.Update(func(db *FlakyDB) error {
+		h := db.Tests[id]
+		now := s.now()
+		if h == nil || h.BugID != "" || h.bugClaimed(now) {
+			return nil
+		}
+		h.BugClaimedAt = now
+		claimed = h
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// SetBug records the bug filed for a test and releases the claim. An empty
+// bugID just releases the claim, so the bug can be filed again later.
+func (s *FlakyStore) SetBug(id, bugID string) error {
+	return s.Update(func(db *FlakyDB) error {
+		if h := db.Tests[id]; h != nil {
+			h.BugID = bugID
+			h.BugClaimedAt = time.Time{}
+		}
+		return nil
+	})
+}
+
+// fileFlakyBugs files a bug for each repeat-offender flaky test and
+// records its ID so each test is only filed once. Each test is claimed in
+// the history before its bug is created.
+func (e *Engineer) fileFlakyBugs(store *FlakyStore) {
+	if store == nil {
+		return
+	}
+	db, err := store.Load()
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to load test history for flaky bugs: %v\n", err)
+		return
+	}
+	for _, cand := range store.BugCandidates(db) {
+		h, err := store.ClaimBug(cand.ID)
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to claim bug filing for flaky test %s: %v\n", cand.ID, err)
+			continue
+		}
+		if h == nil {
+			continue // Filed or being filed elsewhere
+		}
+		bug, err := e.beads.Create(beads.CreateOptions{
+			Title:       "Flaky test: " + h.ID,
+			Type:        "bug",
+			Priority:    2,
+			Description: flakyBugDescription(h),
+			Actor:       e.rig.Name + "/refinery",
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to file bug for flaky test %s: %v\n", h.ID, err)
+			if err := store.SetBug(h.ID, ""); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release bug claim for flaky test %s: %v\n", h.ID, err)
+			}
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Filed %s for flaky test %s\n", bug.ID, h.ID)
+		if err := store.SetBug(h.ID, bug.ID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: filed %s for flaky test %s but failed to record it: %v\n", bug.ID, h.ID, err)
+		}
+	}
+}
+
+// flakyBugDescription summarizes a flaky test's recent history.
+func flakyBugDescription(h *TestHistory) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "The refinery classified %s as flaky: its outcome flipped in %.0f%% of its last %d runs, and it has failed %d times while flaky.\n\n",
+		h.ID, h.FlipRate*100, len(h.Runs), h.FlakyFailures)
+	sb.WriteString("## Recent runs\n")
+	for _, r := range h.Runs {
+		fmt.Fprintf(&sb, "- %s %s", r.At.Format(time.RFC3339), r.Status)
+		if r.Rerun {
+			sb.WriteString(" (rerun)")
+		}
+		if r.MR != "" {
+			fmt.Fprintf(&sb, " %s", r.MR)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\nFix the test. If it was quarantined, `gt mq flaky unquarantine` makes its failures block merges again.")
+	return sb.String()
+}
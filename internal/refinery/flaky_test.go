@@ -0,0 +1,209 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/testreport"
+)
+
+func result(id string, s testreport.Status) []testreport.Result {
+	return []testreport.Result{{Suite: "pkg", Name: id, Status: s}}
+}
+
+func TestFlakyStore_Classifies(t *testing.T) {
+	s := NewFlakyStore(t.TempDir(), FlakyConfig{Threshold: 0.3, MinRuns: 4, Window: 10, BugAfter: 2})
+
+	// A steady test never becomes flaky, however often it fails
+	for i := 0; i < 6; i++ {
+		if err := s.Record("mr-1", result("TestBroken", testreport.Fail), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Alternating outcomes flip every run
+	for i, st := range []testreport.Status{testreport.Pass, testreport.Fail, testreport.Pass, testreport.Fail, testreport.Pass, testreport.Fail} {
+		if err := s.Record("mr-2", result("TestFlaky", st), i%2 == 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := db.Tests["pkg.TestBroken"]; h.Flaky || h.FlipRate != 0 {
+		t.Errorf("TestBroken = %+v, want stable", h)
+	}
+	h := db.Tests["pkg.TestFlaky"]
+	if !h.Flaky || h.FlipRate != 1 {
+		t.Fatalf("TestFlaky = %+v, want flaky", h)
+	}
+	if h.FlakyFailures != 2 {
+		t.Errorf("FlakyFailures = %d, want the 2 failures after classification", h.FlakyFailures)
+	}
+
+	if list := db.List(false); len(list) != 1 || list[0].ID != "pkg.TestFlaky" {
+		t.Errorf("List = %+v", list)
+	}
+	if cands := s.BugCandidates(db); len(cands) != 1 {
+		t.Errorf("BugCandidates = %+v, want TestFlaky", cands)
+	}
+
+	// A claimed bug is filed once; a released claim can be taken again
+	if h, err := s.ClaimBug("pkg.TestFlaky"); err != nil || h == nil {
+		t.Fatalf("ClaimBug = %v, %v", h, err)
+	}
+	if h, err := s.ClaimBug("pkg.TestFlaky"); err != nil || h != nil {
+		t.Errorf("second ClaimBug = %v, %v; want the claim held", h, err)
+	}
+	if db, _ := s.Load(); len(s.BugCandidates(db)) != 0 {
+		t.Error("a claimed test is still a bug candidate")
+	}
+	if err := s.SetBug("pkg.TestFlaky", ""); err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := s.ClaimBug("pkg.TestFlaky"); h == nil {
+		t.Error("released claim could not be taken again")
+	}
+	if err := s.SetBug("pkg.TestFlaky", "gt-bug1"); err != nil {
+		t.Fatal(err)
+	}
+	if db, _ := s.Load(); db.Tests["pkg.TestFlaky"].BugID != "gt-bug1" || len(s.BugCandidates(db)) != 0 {
+		t.Errorf("after SetBug: %+v", db.Tests["pkg.TestFlaky"])
+	}
+
+	// Ignored only when asked to ignore flaky tests
+	if db.Ignorable("pkg.TestFlaky", false) || !db.Ignorable("pkg.TestFlaky", true) {
+		t.Error("Ignorable should follow ignore_flaky for flaky tests")
+	}
+	if db.Ignorable("pkg.TestBroken", true) {
+		t.Error("a stable failure is ignorable")
+	}
+}
+
+func TestFlakyStore_WindowAndQuarantine(t *testing.T) {
+	s := NewFlakyStore(t.TempDir(), FlakyConfig{Window: 3})
+	for i := 0; i < 5; i++ {
+		_ = s.Record("mr", result("TestA", testreport.Pass), false)
+	}
+	if err := s.Quarantine("pkg.TestB", "hangs on CI"); err != nil {
+		t.Fatal(err)
+	}
+
+	db, _ := s.Load()
+	if n := len(db.Tests["pkg.TestA"].Runs); n != 3 {
+		t.Errorf("kept %d runs, want the window of 3", n)
+	}
+	if !db.Ignorable("pkg.TestB", false) {
+		t.Error("quarantined test not ignorable")
+	}
+
+	if err := s.Unquarantine("pkg.TestB"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unquarantine("pkg.TestB"); err == nil {
+		t.Error("unquarantining twice succeeded")
+	}
+	db, _ = s.Load()
+	if db.Ignorable("pkg.TestB", true) {
+		t.Error("unquarantined test still ignorable")
+	}
+}
+
+// tapScript writes a test runner that prints TAP, failing "flaky" on its
+// first run only and "broken" always, and returns the gate env.
+func tapScript(t *testing.T) (*GateEnv, string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+echo "run $*" >> calls
+echo "1..3"
+if [ $# -eq 0 ] || echo "$@" | grep -q stable; then echo "ok 1 - stable"; fi
+if [ $# -eq 0 ] || echo "$@" | grep -q flaky; then
+  if [ -f ran ]; then echo "ok 2 - flaky"; else echo "not ok 2 - flaky"; fi
+fi
+if [ $# -eq 0 ] || echo "$@" | grep -q broken; then echo "not ok 3 - broken"; fi
+touch ran
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "run.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &GateEnv{WorkDir: dir, Flaky: NewFlakyStore(t.TempDir(), FlakyConfig{}), MR: "mr-1"}, dir
+}
+
+func TestTestsGate_RerunsOnlyFailingTests(t *testing.T) {
+	env, dir := tapScript(t)
+	mq := DefaultMergeQueueConfig()
+	mq.RetryFlakyTests = 2
+	g, err := newTestsGate(GateConfig{Name: "tests", Command: "./run.sh", RerunCommand: "./run.sh {names}"}, mq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.Run(context.Background(), env)
+	if r.Status != GateFail || !strings.Contains(r.Summary, "1 test(s) failed") || !strings.Contains(r.Summary, "broken") {
+		t.Fatalf("Run = %+v, want only broken to fail", r)
+	}
+	calls, _ := os.ReadFile(filepath.Join(dir, "calls"))
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	if len(lines) != 2 || lines[1] != "run flaky broken" {
+		t.Errorf("calls = %q, want a full run then a rerun of flaky and broken", lines)
+	}
+
+	db, _ := env.Flaky.Load()
+	flaky := db.Tests["flaky"]
+	if flaky == nil || len(flaky.Runs) != 2 || !flaky.Runs[1].Rerun || flaky.Runs[1].Status != testreport.Pass {
+		t.Errorf("flaky history = %+v, want a fail then a rerun pass", flaky)
+	}
+
+	// Quarantining the remaining failure lets the gate pass
+	if err := env.Flaky.Quarantine("broken", ""); err != nil {
+		t.Fatal(err)
+	}
+	r = g.Run(context.Background(), env)
+	if r.Status != GatePass || !strings.Contains(r.Summary, "broken") {
+		t.Errorf("Run with broken quarantined = %+v, want a pass noting it", r)
+	}
+}
+
+func TestTestsGate_QuarantineAndPlainFailures(t *testing.T) {
+	env := &GateEnv{WorkDir: t.TempDir(), Flaky: NewFlakyStore(t.TempDir(), FlakyConfig{})}
+	g, _ := newTestsGate(GateConfig{Name: "tests", Command: "echo 1..1; echo 'not ok 1 - x'; echo crashed; exit 2", RerunCommand: "exit 0"}, DefaultMergeQueueConfig())
+	if err := env.Flaky.Quarantine("x", ""); err != nil {
+		t.Fatal(err)
+	}
+	// Only "x" failed and it's quarantined, so the gate passes
+	if r := g.Run(context.Background(), env); r.Status != GatePass {
+		t.Errorf("Run = %+v", r)
+	}
+
+	// Without a report there's nothing to attribute: a plain failure
+	g, _ = newTestsGate(GateConfig{Name: "tests", Command: "echo crashed; exit 2", Report: "none"}, DefaultMergeQueueConfig())
+	if r := g.Run(context.Background(), env); r.Status != GateFail || !strings.Contains(r.Summary, "tests failed after 1 attempts") {
+		t.Errorf("Run = %+v", r)
+	}
+}
+
+func TestRerunFor(t *testing.T) {
+	g := &testsGate{command: "go test -json ./..."}
+	failing := []testreport.Result{
+		{Suite: "example.com/a", Name: "TestOne/sub", Status: testreport.Fail},
+		{Suite: "example.com/a", Name: "TestOne", Status: testreport.Fail},
+		{Suite: "example.com/b", Name: "TestTwo", Status: testreport.Fail},
+	}
+	cmd, targeted := g.rerunFor(failing, testreport.FormatGoJSON)
+	want := "go test -json -count=1 -run '^(TestOne|TestTwo)$' 'example.com/a' 'example.com/b'"
+	if !targeted || cmd != want {
+		t.Errorf("rerunFor = %q, %v; want %q", cmd, targeted, want)
+	}
+
+	// A build failure can't be targeted
+	failing = append(failing, testreport.Result{Suite: "example.com/c", Status: testreport.Fail})
+	if cmd, targeted := g.rerunFor(failing, testreport.FormatGoJSON); targeted || cmd != g.command {
+		t.Errorf("rerunFor with build failure = %q, %v; want the full command", cmd, targeted)
+	}
+}
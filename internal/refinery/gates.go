@@ -0,0 +1,998 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"github.com/steveyegge/gastown/internal/testreport"
+)
+
+// Gate types.
+const (
+	GateCommand        = "command"
+	GateTests          = "tests"
+	GateDiffSize       = "diff_size"
+	GateForbiddenPaths = "forbidden_paths"
+	GateSecrets        = "secrets"
+	GateCoverage       = "coverage"
+)
+
+// DefaultGateTimeout bounds a gate with no timeout of its own.
+const DefaultGateTimeout = 10 * time.Minute
+
+// GateConfig configures one pre-merge gate in the rig's merge_queue.gates list.
+// Gates run in order; the first blocking failure stops the pipeline.
+type GateConfig struct {
+	// Name identifies the gate in results and failure messages.
+	Name string `json:"name"`
+
+	// Type selects the gate implementation (default "command").
+	Type string `json:"type,omitempty"`
+
+	// Command is the shell command for command, tests and coverage gates.
+	Command string `json:"command,omitempty"`
+
+	// Timeout bounds the gate (e.g., "5m"). Default: DefaultGateTimeout.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Advisory gates record failures without blocking the merge.
+	Advisory bool `json:"advisory,omitempty"`
+
+	// Paths are the glob patterns a forbidden_paths gate rejects
+	// ("deploy/**" matches everything under deploy/).
+	Paths []string `json:"paths,omitempty"`
+
+	// MaxLines and MaxFiles limit a diff_size gate (0 = unlimited).
+	MaxLines int `json:"max_lines,omitempty"`
+	MaxFiles int `json:"max_files,omitempty"`
+
+	// MinCoverage is the coverage gate's threshold in percent.
+	MinCoverage float64 `json:"min_coverage,omitempty"`
+
+	// Patterns are extra regular expressions for a secrets gate.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Report is the tests gate's output format: go-json, junit or tap,
+	// "none" to disable per-test tracking, or empty to detect it.
+	Report string `json:"report,omitempty"`
+
+	// ReportFile reads the report from a file in the worktree (e.g. JUnit
+	// XML) instead of the command's output.
+	ReportFile string `json:"report_file,omitempty"`
+
+	// RerunCommand reruns only the failing tests. {tests} expands to an
+	// anchored regex of their names, {names} to the quoted names and
+	// {packages} to their suites. go-json reports default to a go test -run.
+	RerunCommand string `json:"rerun_command,omitempty"`
+
+	// IgnoreFlaky lets failures of tests classified as flaky pass the gate.
+	// Quarantined tests are always ignored.
+	IgnoreFlaky bool `json:"ignore_flaky,omitempty"`
+}
+
+// GateEnv is what a gate runs against: the merge worktree and the range of
+// commits being merged.
+type GateEnv struct {
+	WorkDir string
+	Base    string // Target the branch merges into
+	Head    string // Branch (or commit) being merged
+	Git     *git.Git
+
+	// TestCache, when set, lets tests gates skip trees that already passed.
+	TestCache *TestCache
+
+	// Flaky, when set, records per-test history and supplies the
+	// quarantine list. MR labels the runs it records.
+	Flaky *FlakyStore
+	MR    string
+}
+
+// GateStatus is the outcome of one gate.
+type GateStatus string
+
+const (
+	GatePass GateStatus = "pass"
+	GateFail GateStatus = "fail"
+	GateWarn GateStatus = "warn" // Advisory gate failed
+)
+
+// GateResult is one gate's outcome, recorded on the MR bead.
+type GateResult struct {
+	Gate     string      `json:"gate"`
+	Status   GateStatus  `json:"status"`
+	Failure  FailureType `json:"failure,omitempty"`
+	Summary  string      `json:"summary,omitempty"`
+	Duration int64       `json:"ms"`
+	Cached   bool        `json:"cached,omitempty"` // Reused a passing run of the same tree
+
+	// Output is the tail of the gate's output, sent to the worker on
+	// failure. Too large for the bead, so not recorded there.
+	Output string `json:"-"`
+}
+
+// Gate is one pre-merge check.
+type Gate interface {
+	Name() string
+	Run(ctx context.Context, env *GateEnv) GateResult
+}
+
+// GateFactory builds a gate from its config. mq is the rig's merge queue
+// config, for gates that fall back on rig-wide settings.
+type GateFactory func(cfg GateConfig, mq *MergeQueueConfig) (Gate, error)
+
+var gateTypes = map[string]GateFactory{
+	GateCommand:        newCommandGate,
+	GateTests:          newTestsGate,
+	GateCoverage:       newCoverageGate,
+	GateDiffSize:       newDiffSizeGate,
+	GateForbiddenPaths: newForbiddenPathsGate,
+	GateSecrets:        newSecretsGate,
+}
+
+// RegisterGateType adds a gate type usable from merge_queue.gates.
+func RegisterGateType(typ string, factory GateFactory) {
+	gateTypes[typ] = factory
+}
+
+// BuildGates returns the rig's gate pipeline. With no gates configured it
+// is a single tests gate running TestCommand (when RunTests is set).
+func BuildGates(mq *MergeQueueConfig) ([]Gate, error) {
+	if len(mq.Gates) == 0 {
+		if !mq.RunTests || mq.TestCommand == "" {
+			return nil, nil
+		}
+		g, err := newTestsGate(GateConfig{Name: "tests"}, mq)
+		if err != nil {
+			return nil, err
+		}
+		return []Gate{g}, nil
+	}
+
+	gates := make([]Gate, 0, len(mq.Gates))
+	seen := make(map[string]bool)
+	for i, cfg := range mq.Gates {
+		if cfg.Type == "" {
+			cfg.Type = GateCommand
+		}
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("gate %d: duplicate name %q", i+1, cfg.Name)
+		}
+		seen[cfg.Name] = true
+		if cfg.Type == GateTests && !mq.RunTests {
+			continue
+		}
+		factory, ok := gateTypes[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("gate %q: unknown type %q", cfg.Name, cfg.Type)
+		}
+		g, err := factory(cfg, mq)
+		if err != nil {
+			return nil, fmt.Errorf("gate %q: %w", cfg.Name, err)
+		}
+		gates = append(gates, g)
+	}
+	return gates, nil
+}
+
+// RunGates runs the gates in order, each under its own timeout, and returns
+// every result. The returned ProcessResult carries the first blocking
+// failure's type and actionable output, or Success.
+func RunGates(ctx context.Context, gates []Gate, env *GateEnv, out io.Writer) ([]GateResult, ProcessResult) {
+	var results []GateResult
+	for _, g := range gates {
+		_, _ = fmt.Fprintf(out, "[Engineer] Gate %s...\n", g.Name())
+		start := time.Now()
+		r := g.Run(ctx, env)
+		r.Gate = g.Name()
+		r.Duration = time.Since(start).Milliseconds()
+		results = append(results, r)
+		traceGate(ctx, r, start)
+
+		switch r.Status {
+		case GatePass:
+			_, _ = fmt.Fprintf(out, "[Engineer] Gate %s passed\n", g.Name())
+		case GateWarn:
+			_, _ = fmt.Fprintf(out, "[Engineer] Gate %s failed (advisory): %s\n", g.Name(), r.Summary)
+		default:
+			_, _ = fmt.Fprintf(out, "[Engineer] Gate %s failed: %s\n", g.Name(), r.Summary)
+			return results, gateFailure(r)
+		}
+		if ctx.Err() != nil {
+			return results, ProcessResult{Error: "gates canceled"}
+		}
+	}
+	return results, ProcessResult{Success: true}
+}
+
+// traceGate records a gate run as a child of the MR's span, if it has one.
+func traceGate(ctx context.Context, r GateResult, start time.Time) {
+	if telemetry.SpanFromContext(ctx) == nil {
+		return
+	}
+	_, span := telemetry.Start(ctx, "gate "+r.Gate,
+		telemetry.String("gt.gate.status", string(r.Status)),
+		telemetry.Bool("gt.gate.cached", r.Cached))
+	span.SetStart(start)
+	if r.Failure != "" {
+		span.SetAttributes(telemetry.String("gt.gate.failure", string(r.Failure)))
+	}
+	if r.Status == GatePass || r.Status == GateWarn {
+		span.End(nil)
+		return
+	}
+	span.End(errors.New(r.Summary))
+}
+
+// gateFailure turns a failed gate into a ProcessResult for the worker.
+func gateFailure(r GateResult) ProcessResult {
+	msg := fmt.Sprintf("gate %s failed: %s", r.Gate, r.Summary)
+	if r.Output != "" {
+		msg += "\n\n" + r.Output
+	}
+	return ProcessResult{
+		TestsFailed: r.Failure == FailureTestsFail,
+		Failure:     r.Failure,
+		Error:       msg,
+	}
+}
+
+// FormatGateResults encodes results for the MR bead's gates field.
+func FormatGateResults(results []GateResult) string {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseGateResults decodes an MR bead's gates field.
+func ParseGateResults(s string) ([]GateResult, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var results []GateResult
+	if err := json.Unmarshal([]byte(s), &results); err != nil {
+		return nil, fmt.Errorf("parsing gate results: %w", err)
+	}
+	return results, nil
+}
+
+// CheckGates runs the rig's gate pipeline in the refinery worktree against
+// the changes head makes on base. The refinery agent uses it in place of a
+// bare test run.
+// mr labels the run in the rig's test history (the MR ID, or empty for head).
+func (e *Engineer) CheckGates(ctx context.Context, base, head, mr string) ([]GateResult, ProcessResult, error) {
+	gates, err := BuildGates(e.config)
+	if err != nil {
+		return nil, ProcessResult{}, err
+	}
+	if mr == "" {
+		mr = head
+	}
+	env := e.gateEnv(base, head, mr)
+	results, result := RunGates(ctx, gates, env, e.output)
+	e.fileFlakyBugs(env.Flaky)
+	return results, result, nil
+}
+
+// gateEnv returns the environment gates run in: the refinery worktree,
+// with the rig's test history and (when enabled) test cache.
+func (e *Engineer) gateEnv(base, head, mr string) *GateEnv {
+	env := &GateEnv{
+		WorkDir: e.workDir,
+		Base:    base,
+		Head:    head,
+		Git:     e.git,
+		Flaky:   NewFlakyStore(e.rig.Path, e.config.Flaky),
+		MR:      mr,
+	}
+	if e.config.TestCache {
+		env.TestCache = NewTestCache(e.rig.Path, e.config.TestCacheTTL)
+	}
+	return env
+}
+
+// recordGates stores gate results on the MR bead.
+func (e *Engineer) recordGates(mrID string, results []GateResult) {
+	if mrID == "" {
+		return
+	}
+	if err := RecordGateResults(e.beads, mrID, results); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record gate results on %s: %v\n", mrID, err)
+	}
+}
+
+// RecordGateResults stores gate results in an MR bead's gates field.
+func RecordGateResults(b *beads.Beads, mrID string, results []GateResult) error {
+	issue, err := b.Show(mrID)
+	if err != nil {
+		return err
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		fields = &beads.MRFields{}
+	}
+	fields.Gates = FormatGateResults(results)
+	desc := beads.SetMRFields(issue, fields)
+	return b.Update(mrID, beads.UpdateOptions{Description: &desc})
+}
+
+// gateBase is shared by the built-in gates.
+type gateBase struct {
+	name     string
+	timeout  time.Duration
+	advisory bool
+}
+
+func newGateBase(cfg GateConfig) (gateBase, error) {
+	b := gateBase{name: cfg.Name, timeout: DefaultGateTimeout, advisory: cfg.Advisory}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return b, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+		b.timeout = d
+	}
+	return b, nil
+}
+
+func (b gateBase) Name() string { return b.name }
+
+// fail builds a failing result, downgraded to a warning for advisory gates.
+func (b gateBase) fail(failure FailureType, summary, output string) GateResult {
+	status := GateFail
+	if b.advisory {
+		status = GateWarn
+	}
+	return GateResult{Status: status, Failure: failure, Summary: summary, Output: output}
+}
+
+// maxGateOutput bounds the output kept for the worker.
+const maxGateOutput = 4000
+
+// runShell runs a gate command in the worktree under the gate's timeout,
+// returning the combined output tail.
+func (b gateBase) runShell(ctx context.Context, dir, command string) (string, error) {
+	out, err := b.runShellFull(ctx, dir, command)
+	return tail(out, maxGateOutput), err
+}
+
+// runShellFull is runShell returning all of the output, for parsing.
+func (b gateBase) runShellFull(ctx context.Context, dir, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	// Note: gate commands come from rig's config.json (trusted infrastructure
+	// config), not from PR branches.
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command is from trusted rig config
+	cmd.Dir = dir
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	killProcessTree(cmd)
+	cmd.WaitDelay = time.Second // Don't wait on pipes held by orphaned helpers
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", b.timeout)
+	}
+	return buf.String(), err
+}
+
+// tail returns the last n bytes of s, starting at a line boundary.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return "...\n" + s
+}
+
+// commandGate runs a shell command; a non-zero exit fails the gate.
+type commandGate struct {
+	gateBase
+	command string
+}
+
+func newCommandGate(cfg GateConfig, _ *MergeQueueConfig) (Gate, error) {
+	if cfg.Command == "" {
+		return nil, errors.New("command is required")
+	}
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &commandGate{gateBase: b, command: cfg.Command}, nil
+}
+
+func (g *commandGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	out, err := g.runShell(ctx, env.WorkDir, g.command)
+	if err != nil {
+		return g.fail(FailureGate, fmt.Sprintf("%s: %v", g.command, err), out)
+	}
+	return GateResult{Status: GatePass}
+}
+
+// testsGate runs the test command, retrying flaky failures. When the
+// output parses as a test report it records per-test history, retries only
+// the failing tests and lets quarantined (and optionally flaky) failures
+// through. Passing runs are cached by the tree they ran on when the env has
+// a TestCache.
+type testsGate struct {
+	gateBase
+	command      string
+	retries      int
+	cacheEnv     string            // Environment fingerprint for cache keys
+	report       testreport.Format // "" detects; reportNone disables parsing
+	reportFile   string
+	rerunCommand string
+	ignoreFlaky  bool
+}
+
+// reportNone disables per-test parsing for a tests gate.
+const reportNone = "none"
+
+// defaultGoRerun reruns failing tests from a go test -json report.
+const defaultGoRerun = "go test -json -count=1 -run '{tests}' {packages}"
+
+func newTestsGate(cfg GateConfig, mq *MergeQueueConfig) (Gate, error) {
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	command := cfg.Command
+	if command == "" {
+		command = mq.TestCommand
+	}
+	if command == "" {
+		return nil, errors.New("command is required (or set test_command)")
+	}
+	retries := mq.RetryFlakyTests
+	if retries < 1 {
+		retries = 1
+	}
+	cacheVars := mq.TestCacheEnv
+	if cacheVars == nil {
+		cacheVars = DefaultTestCacheEnv
+	}
+	g := &testsGate{
+		gateBase:     b,
+		command:      command,
+		retries:      retries,
+		cacheEnv:     EnvFingerprint(cacheVars),
+		reportFile:   cfg.ReportFile,
+		rerunCommand: cfg.RerunCommand,
+		ignoreFlaky:  cfg.IgnoreFlaky,
+	}
+	if cfg.Report != "" {
+		if cfg.Report == reportNone {
+			g.report = reportNone
+		} else if g.report, err = testreport.ParseFormat(cfg.Report); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (g *testsGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	tree := g.cacheableTree(env)
+	if tree != "" {
+		if hit, ok := env.TestCache.Lookup(tree, g.command, g.cacheEnv); ok {
+			return GateResult{
+				Status:  GatePass,
+				Cached:  true,
+				Summary: fmt.Sprintf("cached pass of tree %s from %s", short(tree), hit.PassedAt.Format(time.RFC3339)),
+			}
+		}
+	}
+
+	var out string
+	var err error
+	var failing []testreport.Result
+	var format testreport.Format // Format of the full run's report
+	for attempt := 1; attempt <= g.retries; attempt++ {
+		command, targeted := g.command, false
+		if attempt > 1 {
+			command, targeted = g.rerunFor(failing, format)
+		}
+		start := time.Now()
+		var runErr error
+		out, runErr = g.runShellFull(ctx, env.WorkDir, command)
+		results, parsed := g.parse(env, out)
+		g.record(env, results, attempt > 1)
+
+		if !targeted {
+			err, format = runErr, parsed
+			failing = testreport.Failed(results)
+			if err == nil {
+				if tree != "" {
+					_ = env.TestCache.Store(TestCacheEntry{
+						Tree:     tree,
+						Command:  g.command,
+						Env:      g.cacheEnv,
+						MR:       env.MR,
+						Duration: time.Since(start).Milliseconds(),
+					})
+				}
+				return GateResult{Status: GatePass}
+			}
+		} else {
+			failing = stillFailing(failing, results)
+			if len(failing) == 0 {
+				return GateResult{Status: GatePass, Summary: "failing tests passed on rerun"}
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if !attributable(failing) {
+		return g.fail(FailureTestsFail, fmt.Sprintf("tests failed after %d attempts: %v", g.retries, err), tail(out, maxGateOutput))
+	}
+
+	// Let quarantined (and, if configured, flaky) failures through
+	var blocking, ignored []testreport.Result
+	var db *FlakyDB
+	if env.Flaky != nil {
+		db, _ = env.Flaky.Load()
+	}
+	for _, r := range failing {
+		if db != nil && db.Ignorable(r.ID(), g.ignoreFlaky) {
+			ignored = append(ignored, r)
+		} else {
+			blocking = append(blocking, r)
+		}
+	}
+	if len(blocking) == 0 {
+		return GateResult{Status: GatePass, Summary: "ignored known-flaky failures: " + testIDs(ignored)}
+	}
+	summary := fmt.Sprintf("%d test(s) failed after %d attempts: %s", len(blocking), g.retries, testIDs(blocking))
+	return g.fail(FailureTestsFail, summary, failureOutput(blocking, out))
+}
+
+// parse extracts per-test results from the run's output (or report file).
+// It returns the format parsed, or "" when nothing parsed.
+func (g *testsGate) parse(env *GateEnv, out string) ([]testreport.Result, testreport.Format) {
+	if g.report == reportNone {
+		return nil, ""
+	}
+	data := []byte(out)
+	if g.reportFile != "" {
+		path := g.reportFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(env.WorkDir, path)
+		}
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, ""
+		}
+		// Don't let the next run read a stale report
+		_ = os.Remove(path)
+	}
+	format := g.report
+	if format == "" {
+		if format = testreport.Detect(data); format == "" {
+			return nil, ""
+		}
+	}
+	results, err := testreport.Parse(format, data)
+	if err != nil {
+		return nil, ""
+	}
+	return results, format
+}
+
+// record adds results to the rig's test history.
+func (g *testsGate) record(env *GateEnv, results []testreport.Result, rerun bool) {
+	if env.Flaky == nil {
+		return
+	}
+	_ = env.Flaky.Record(env.MR, results, rerun)
+}
+
+// rerunFor returns the command that reruns only the failing tests, and
+// whether it is targeted. Without a rerun command (or attributable
+// failures) it is the full test command.
+func (g *testsGate) rerunFor(failing []testreport.Result, format testreport.Format) (string, bool) {
+	template := g.rerunCommand
+	if template == "" && format == testreport.FormatGoJSON {
+		template = defaultGoRerun
+	}
+	if template == "" || !attributable(failing) {
+		return g.command, false
+	}
+
+	var names, quoted, suites []string
+	seenName := make(map[string]bool)
+	seenSuite := make(map[string]bool)
+	for _, r := range failing {
+		// go subtests rerun through their top-level test
+		name := strings.SplitN(r.Name, "/", 2)[0]
+		if !seenName[name] {
+			seenName[name] = true
+			names = append(names, regexp.QuoteMeta(name))
+			quoted = append(quoted, shellQuote(name))
+		}
+		if r.Suite != "" && !seenSuite[r.Suite] {
+			seenSuite[r.Suite] = true
+			suites = append(suites, shellQuote(r.Suite))
+		}
+	}
+	cmd := strings.NewReplacer(
+		"{tests}", "^("+strings.Join(names, "|")+")$",
+		"{names}", strings.Join(quoted, " "),
+		"{packages}", strings.Join(suites, " "),
+	).Replace(template)
+	return cmd, true
+}
+
+// attributable reports whether the failures are all named tests, i.e. the
+// run didn't also fail in a way no test accounts for.
+func attributable(failing []testreport.Result) bool {
+	for _, r := range failing {
+		if r.Name == "" {
+			return false
+		}
+	}
+	return len(failing) > 0
+}
+
+// stillFailing returns the failures the rerun didn't report as passing,
+// with the rerun's output where it has some. A go parent test passing
+// covers its subtests.
+func stillFailing(failing, rerun []testreport.Result) []testreport.Result {
+	latest := make(map[string]testreport.Result)
+	for _, r := range rerun {
+		latest[r.ID()] = r
+	}
+	var still []testreport.Result
+	for _, r := range failing {
+		parent := testreport.Result{Suite: r.Suite, Name: strings.SplitN(r.Name, "/", 2)[0]}
+		if latest[r.ID()].Status == testreport.Pass || latest[parent.ID()].Status == testreport.Pass {
+			continue
+		}
+		if again, ok := latest[r.ID()]; ok && again.Output != "" {
+			r = again
+		}
+		still = append(still, r)
+	}
+	return still
+}
+
+// testIDs lists up to five test IDs for a summary.
+func testIDs(results []testreport.Result) string {
+	var ids []string
+	for i, r := range results {
+		if i == 5 {
+			ids = append(ids, fmt.Sprintf("and %d more", len(results)-5))
+			break
+		}
+		ids = append(ids, r.ID())
+	}
+	return strings.Join(ids, ", ")
+}
+
+// failureOutput joins the failing tests' own output, falling back on the
+// run's output when the report carried none.
+func failureOutput(failing []testreport.Result, out string) string {
+	var sb strings.Builder
+	for _, r := range failing {
+		if r.Output != "" {
+			fmt.Fprintf(&sb, "--- %s\n%s\n", r.ID(), r.Output)
+		}
+	}
+	if sb.Len() == 0 {
+		return tail(out, maxGateOutput)
+	}
+	return tail(sb.String(), maxGateOutput)
+}
+
+// shellQuote single-quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cacheableTree returns the tree the tests run against, which keys the
+// test cache: the worktree's index written as a tree (in doMerge, the trial
+// merge of the branch into its target). It returns "" when there's no
+// cache or the working files differ from the index or include untracked
+// files, since the tree wouldn't describe what ran.
+func (g *testsGate) cacheableTree(env *GateEnv) string {
+	if env.TestCache == nil || env.Git == nil {
+		return ""
+	}
+	tree, err := env.Git.WorktreeTree()
+	if err != nil {
+		return ""
+	}
+	return tree
+}
+
+// coverageGate runs a coverage command and checks the reported percentage.
+type coverageGate struct {
+	gateBase
+	command string
+	min     float64
+}
+
+func newCoverageGate(cfg GateConfig, _ *MergeQueueConfig) (Gate, error) {
+	if cfg.MinCoverage <= 0 || cfg.MinCoverage > 100 {
+		return nil, errors.New("min_coverage must be between 0 and 100")
+	}
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	command := cfg.Command
+	if command == "" {
+		command = "go test -cover ./..."
+	}
+	return &coverageGate{gateBase: b, command: command, min: cfg.MinCoverage}, nil
+}
+
+func (g *coverageGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	out, err := g.runShell(ctx, env.WorkDir, g.command)
+	if err != nil {
+		return g.fail(FailureTestsFail, fmt.Sprintf("%s: %v", g.command, err), out)
+	}
+	pct, ok := parseCoverage(out)
+	if !ok {
+		return g.fail(FailureCoverage, "no coverage figure in output", out)
+	}
+	if pct < g.min {
+		return g.fail(FailureCoverage, fmt.Sprintf("coverage %.1f%% is below %.1f%%", pct, g.min), out)
+	}
+	return GateResult{Status: GatePass, Summary: fmt.Sprintf("coverage %.1f%%", pct)}
+}
+
+var (
+	coverageTotalRe = regexp.MustCompile(`(?m)^total:.*?([0-9.]+)%`)
+	coverageLineRe  = regexp.MustCompile(`coverage: ([0-9.]+)% of statements`)
+)
+
+// parseCoverage reads a coverage percentage from command output: the
+// "total:" line of `go tool cover -func`, else the mean of the per-package
+// figures `go test -cover` prints.
+func parseCoverage(out string) (float64, bool) {
+	if m := coverageTotalRe.FindAllStringSubmatch(out, -1); len(m) > 0 {
+		pct, err := strconv.ParseFloat(m[len(m)-1][1], 64)
+		return pct, err == nil
+	}
+	var sum float64
+	var n int
+	for _, m := range coverageLineRe.FindAllStringSubmatch(out, -1) {
+		if pct, err := strconv.ParseFloat(m[1], 64); err == nil {
+			sum += pct
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// diffSizeGate limits how many lines and files a branch may change.
+type diffSizeGate struct {
+	gateBase
+	maxLines, maxFiles int
+}
+
+func newDiffSizeGate(cfg GateConfig, _ *MergeQueueConfig) (Gate, error) {
+	if cfg.MaxLines <= 0 && cfg.MaxFiles <= 0 {
+		return nil, errors.New("max_lines or max_files is required")
+	}
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &diffSizeGate{gateBase: b, maxLines: cfg.MaxLines, maxFiles: cfg.MaxFiles}, nil
+}
+
+func (g *diffSizeGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	files, err := env.Git.DiffNumstat(env.Base, env.Head)
+	if err != nil {
+		return g.fail(FailureGate, fmt.Sprintf("reading diff: %v", err), "")
+	}
+	lines := 0
+	for _, f := range files {
+		lines += f.Added + f.Deleted
+	}
+	summary := fmt.Sprintf("%d lines in %d files", lines, len(files))
+	if (g.maxLines > 0 && lines > g.maxLines) || (g.maxFiles > 0 && len(files) > g.maxFiles) {
+		return g.fail(FailureDiffTooLarge,
+			fmt.Sprintf("%s exceeds the limit (%s); split the change into smaller MRs", summary, g.limits()),
+			largestFiles(files, 10))
+	}
+	return GateResult{Status: GatePass, Summary: summary}
+}
+
+func (g *diffSizeGate) limits() string {
+	var parts []string
+	if g.maxLines > 0 {
+		parts = append(parts, fmt.Sprintf("%d lines", g.maxLines))
+	}
+	if g.maxFiles > 0 {
+		parts = append(parts, fmt.Sprintf("%d files", g.maxFiles))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// largestFiles lists the n biggest changes, for the worker to split up.
+func largestFiles(files []git.FileDiff, n int) string {
+	sorted := append([]git.FileDiff(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Added+sorted[i].Deleted > sorted[j].Added+sorted[j].Deleted
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	var sb strings.Builder
+	sb.WriteString("Largest changes:\n")
+	for _, f := range sorted {
+		fmt.Fprintf(&sb, "  +%d -%d  %s\n", f.Added, f.Deleted, f.Path)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// forbiddenPathsGate rejects branches touching paths the rig reserves.
+type forbiddenPathsGate struct {
+	gateBase
+	patterns []string
+}
+
+func newForbiddenPathsGate(cfg GateConfig, _ *MergeQueueConfig) (Gate, error) {
+	if len(cfg.Paths) == 0 {
+		return nil, errors.New("paths is required")
+	}
+	for _, p := range cfg.Paths {
+		if _, err := path.Match(strings.TrimSuffix(p, "/**"), ""); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", p, err)
+		}
+	}
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &forbiddenPathsGate{gateBase: b, patterns: cfg.Paths}, nil
+}
+
+func (g *forbiddenPathsGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	files, err := env.Git.DiffNumstat(env.Base, env.Head)
+	if err != nil {
+		return g.fail(FailureGate, fmt.Sprintf("reading diff: %v", err), "")
+	}
+	var hits []string
+	for _, f := range files {
+		for _, p := range g.patterns {
+			if matchPath(p, f.Path) {
+				hits = append(hits, fmt.Sprintf("  %s (matches %s)", f.Path, p))
+				break
+			}
+		}
+	}
+	if len(hits) > 0 {
+		return g.fail(FailureForbiddenPath,
+			fmt.Sprintf("%d file(s) in forbidden paths; revert those changes", len(hits)),
+			"Forbidden:\n"+strings.Join(hits, "\n"))
+	}
+	return GateResult{Status: GatePass}
+}
+
+// matchPath matches a repo-relative file against a gate pattern. "dir/**"
+// (or "dir/") matches everything under dir; a pattern without a slash
+// matches the file's base name anywhere; otherwise path.Match applies.
+func matchPath(pattern, file string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return file == prefix || strings.HasPrefix(file, prefix+"/")
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/"); ok {
+		return strings.HasPrefix(file, prefix+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
+
+// secretPatterns are the secrets gate's built-in detectors.
+var secretPatterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"credential assignment", regexp.MustCompile(`(?i)\b(api[_-]?key|secret|passw(or)?d|access[_-]?token)\b["']?\s*[:=]\s*["'][^"'\s]{12,}["']`)},
+}
+
+// secretsGate scans the lines a branch adds for credentials.
+type secretsGate struct {
+	gateBase
+	extra []*regexp.Regexp
+}
+
+func newSecretsGate(cfg GateConfig, _ *MergeQueueConfig) (Gate, error) {
+	b, err := newGateBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	g := &secretsGate{gateBase: b}
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		g.extra = append(g.extra, re)
+	}
+	return g, nil
+}
+
+func (g *secretsGate) Run(ctx context.Context, env *GateEnv) GateResult {
+	added, err := env.Git.DiffAddedLines(env.Base, env.Head)
+	if err != nil {
+		return g.fail(FailureGate, fmt.Sprintf("reading diff: %v", err), "")
+	}
+	files := make([]string, 0, len(added))
+	for f := range added {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	// Report where, never what: the output goes out in mail
+	var hits []string
+	for _, f := range files {
+		for _, line := range added[f] {
+			if kind := g.detect(line.Text); kind != "" {
+				hits = append(hits, fmt.Sprintf("  %s:%d looks like a %s", f, line.Num, kind))
+			}
+		}
+	}
+	if len(hits) > 0 {
+		return g.fail(FailureSecret,
+			fmt.Sprintf("%d possible secret(s) added; remove them and rotate any real credentials", len(hits)),
+			strings.Join(hits, "\n"))
+	}
+	return GateResult{Status: GatePass}
+}
+
+func (g *secretsGate) detect(line string) string {
+	for _, p := range secretPatterns {
+		if p.re.MatchString(line) {
+			return p.name
+		}
+	}
+	for _, re := range g.extra {
+		if re.MatchString(line) {
+			return "custom pattern match"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package refinery
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessTree puts a gate command in its own process group and kills
+// the whole group on timeout, so test binaries and linters it spawned die too.
+func killProcessTree(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package refinery
+
+import "os/exec"
+
+// killProcessTree is a no-op on Windows; the gate's shell itself is still
+// killed on timeout.
+func killProcessTree(cmd *exec.Cmd) {}
@@ -0,0 +1,205 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// SetForge overrides the forge used in pull_request mode.
+// This is useful for testing against a fake forge.
+func (e *Engineer) SetForge(f forge.Forge) {
+	e.forge = f
+}
+
+// getForge returns the rig's forge, creating it from config and the origin
+// remote on first use.
+func (e *Engineer) getForge() (forge.Forge, error) {
+	if e.forge != nil {
+		return e.forge, nil
+	}
+	remote, _ := e.git.RemoteURL("origin")
+	f, err := forge.New(e.config.Forge, remote)
+	if err != nil {
+		return nil, err
+	}
+	e.forge = f
+	return f, nil
+}
+
+// prAction is what to do with a PR after inspecting it.
+type prAction int
+
+const (
+	prWait  prAction = iota // Checks or reviews outstanding
+	prMerge                 // Green: merge it
+	prDone                  // Resolved: merged, or failed in a way needing rework
+)
+
+// judgePR decides the next step for a PR, mapping forge outcomes onto the
+// refinery's failure types. The returned result is meaningful for prDone,
+// and its Error explains the wait for prWait.
+func judgePR(pr *forge.PR) (prAction, ProcessResult) {
+	switch pr.State {
+	case forge.PRMerged:
+		return prDone, ProcessResult{Success: true, MergeCommit: pr.MergeCommit, PRURL: pr.URL}
+	case forge.PRClosed:
+		return prDone, ProcessResult{
+			Failure: FailureRejected,
+			Error:   fmt.Sprintf("PR #%d was closed without merging", pr.Number),
+			PRURL:   pr.URL,
+		}
+	}
+
+	if pr.Mergeable == forge.Conflicting {
+		return prDone, ProcessResult{
+			Conflict: true,
+			Failure:  FailureConflict,
+			Error:    fmt.Sprintf("PR #%d conflicts with its base branch", pr.Number),
+			PRURL:    pr.URL,
+		}
+	}
+	switch pr.Checks {
+	case forge.ChecksFailure:
+		msg := fmt.Sprintf("PR #%d checks failed", pr.Number)
+		if len(pr.FailedChecks) > 0 {
+			msg += ": " + strings.Join(pr.FailedChecks, ", ")
+		}
+		return prDone, ProcessResult{TestsFailed: true, Failure: FailureTestsFail, Error: msg, PRURL: pr.URL}
+	case forge.ChecksPending:
+		return prWait, ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d checks running", pr.Number), PRURL: pr.URL}
+	}
+
+	switch pr.Mergeable {
+	case forge.Blocked:
+		return prWait, ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d awaiting required reviews", pr.Number), PRURL: pr.URL}
+	case forge.Behind:
+		return prWait, ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d must be brought up to date with its base branch", pr.Number), PRURL: pr.URL}
+	case forge.MergeUnknown:
+		return prWait, ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d mergeability not computed yet", pr.Number), PRURL: pr.URL}
+	}
+	return prMerge, ProcessResult{PRURL: pr.URL}
+}
+
+// mergeViaPR lands a branch through the forge: it pushes ref as the branch,
+// opens or updates its PR, polls checks and mergeability, and merges via the
+// API when green. If the PR is still waiting when PRTimeout runs out, the
+// result is Pending and the MR is retried on a later pass (the PR is reused).
+//
+// lease is the branch's SHA on origin when the refinery started on the MR
+// (see remoteBranchSHA); the push is refused if the branch has moved since.
+func (e *Engineer) mergeViaPR(ctx context.Context, ref, branch, target, sourceIssue, lease string) ProcessResult {
+	f, err := e.getForge()
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("forge: %v", err)}
+	}
+
+	// The refinery owns a submitted branch and may have rebased it, so the
+	// PR head is replaced with the verified ref, unless someone pushed to
+	// the branch while it was being verified.
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing %s to origin...\n", branch)
+	if err := e.git.PushWithLease("origin", ref+":refs/heads/"+branch, branch, lease); err != nil {
+		return ProcessResult{Failure: FailurePushFail, Error: fmt.Sprintf("failed to push %s: %v", branch, err)}
+	}
+
+	pr, created, err := forge.EnsurePR(ctx, f, e.prRequest(branch, target, sourceIssue))
+	if err != nil {
+		return ProcessResult{Failure: FailurePushFail, Error: err.Error()}
+	}
+	verb := "Updated"
+	if created {
+		verb = "Opened"
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %s PR #%d: %s\n", verb, pr.Number, pr.URL)
+
+	deadline := time.Now().Add(e.config.PRTimeout)
+	lastWait := ""
+	for {
+		pr, err = f.GetPR(ctx, pr.Number)
+		if err != nil {
+			return ProcessResult{Error: fmt.Sprintf("reading PR: %v", err)}
+		}
+		action, result := judgePR(pr)
+		switch action {
+		case prDone:
+			return result
+		case prMerge:
+			_, _ = fmt.Fprintf(e.output, "[Engineer] PR #%d is green, merging (%s)...\n", pr.Number, e.config.PRMergeMethod)
+			sha, err := f.MergePR(ctx, pr.Number, e.config.PRMergeMethod, pr.HeadSHA)
+			if err == nil {
+				return ProcessResult{Success: true, MergeCommit: sha, PRURL: pr.URL}
+			}
+			if !errors.Is(err, forge.ErrNotMergeable) {
+				return ProcessResult{Failure: FailurePushFail, Error: fmt.Sprintf("merging PR #%d: %v", pr.Number, err), PRURL: pr.URL}
+			}
+			// Branch protection the API didn't report; keep waiting
+			result = ProcessResult{Pending: true, Error: fmt.Sprintf("PR #%d refused: %v", pr.Number, err), PRURL: pr.URL}
+		}
+
+		if result.Error != lastWait {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s\n", result.Error)
+			lastWait = result.Error
+		}
+		if !time.Now().Before(deadline) {
+			return result
+		}
+		select {
+		case <-ctx.Done():
+			return result
+		case <-e.stopCh:
+			return result
+		case <-time.After(e.prPollInterval()):
+		}
+	}
+}
+
+// remoteBranchSHA fetches branch from origin and returns its SHA there, or
+// "" if origin has no such branch. mergeViaPR leases its force-push on it.
+func (e *Engineer) remoteBranchSHA(branch string) string {
+	if err := e.git.FetchBranch("origin", branch); err != nil {
+		return ""
+	}
+	sha, err := e.git.Rev("refs/remotes/origin/" + branch)
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+// prPollInterval is how often a waiting PR is re-checked.
+func (e *Engineer) prPollInterval() time.Duration {
+	if e.config.PollInterval > 0 {
+		return e.config.PollInterval
+	}
+	return 30 * time.Second
+}
+
+// LandViaPR lands an MR through its forge PR without the local merge
+// steps. The refinery agent uses it after its own rebase and tests; ref is
+// the verified commit to push as the MR branch (empty means the branch).
+func (e *Engineer) LandViaPR(ctx context.Context, mr *MRInfo, ref string) ProcessResult {
+	if ref == "" {
+		ref = mr.Branch
+	}
+	return e.mergeViaPR(ctx, ref, mr.Branch, mr.Target, mr.SourceIssue, e.remoteBranchSHA(mr.Branch))
+}
+
+// prRequest builds the PR title and body for a branch.
+func (e *Engineer) prRequest(branch, target, sourceIssue string) forge.PRRequest {
+	title := fmt.Sprintf("Merge %s", branch)
+	if sourceIssue != "" {
+		title = sourceIssue
+		if issue, err := e.beads.Show(sourceIssue); err == nil && issue.Title != "" {
+			title = fmt.Sprintf("%s (%s)", issue.Title, sourceIssue)
+		}
+	}
+	body := fmt.Sprintf("Submitted by the %s refinery.\n\nBranch: %s\n", e.rig.Name, branch)
+	if sourceIssue != "" {
+		body += fmt.Sprintf("Issue: %s\n", sourceIssue)
+	}
+	return forge.PRRequest{Head: branch, Base: target, Title: title, Body: body}
+}
@@ -0,0 +1,254 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// fakeForge serves a scripted sequence of PR snapshots from GetPR.
+type fakeForge struct {
+	existing *forge.PR   // Returned by FindPR
+	states   []*forge.PR // GetPR returns these in order, repeating the last
+	mergeErr error
+	created  []forge.PRRequest
+	updated  int
+	merged   int
+	calls    int
+}
+
+func (f *fakeForge) Name() string { return "fake" }
+
+func (f *fakeForge) FindPR(ctx context.Context, head, base string) (*forge.PR, error) {
+	return f.existing, nil
+}
+
+func (f *fakeForge) CreatePR(ctx context.Context, req forge.PRRequest) (*forge.PR, error) {
+	f.created = append(f.created, req)
+	return &forge.PR{Number: 7, URL: "https://forge.test/pr/7", State: forge.PROpen}, nil
+}
+
+func (f *fakeForge) UpdatePR(ctx context.Context, number int, req forge.PRRequest) error {
+	f.updated++
+	return nil
+}
+
+func (f *fakeForge) GetPR(ctx context.Context, number int) (*forge.PR, error) {
+	i := f.calls
+	if i >= len(f.states) {
+		i = len(f.states) - 1
+	}
+	f.calls++
+	pr := *f.states[i]
+	pr.Number = number
+	return &pr, nil
+}
+
+func (f *fakeForge) MergePR(ctx context.Context, number int, method forge.MergeMethod, headSHA string) (string, error) {
+	f.merged++
+	if f.mergeErr != nil {
+		return "", f.mergeErr
+	}
+	return "abc123", nil
+}
+
+func openPR(mergeable forge.Mergeability, checks forge.CheckState) *forge.PR {
+	return &forge.PR{State: forge.PROpen, Mergeable: mergeable, Checks: checks, HeadSHA: "head"}
+}
+
+func TestJudgePR(t *testing.T) {
+	tests := []struct {
+		name    string
+		pr      *forge.PR
+		action  prAction
+		failure FailureType
+	}{
+		{"merged", &forge.PR{State: forge.PRMerged, MergeCommit: "x"}, prDone, ""},
+		{"closed", &forge.PR{State: forge.PRClosed}, prDone, FailureRejected},
+		{"conflict", openPR(forge.Conflicting, forge.ChecksSuccess), prDone, FailureConflict},
+		{"checks failed", openPR(forge.Mergeable, forge.ChecksFailure), prDone, FailureTestsFail},
+		{"checks pending", openPR(forge.Mergeable, forge.ChecksPending), prWait, ""},
+		{"blocked", openPR(forge.Blocked, forge.ChecksSuccess), prWait, ""},
+		{"behind", openPR(forge.Behind, forge.ChecksSuccess), prWait, ""},
+		{"unknown", openPR(forge.MergeUnknown, forge.ChecksNone), prWait, ""},
+		{"green", openPR(forge.Mergeable, forge.ChecksSuccess), prMerge, ""},
+		{"no checks", openPR(forge.Mergeable, forge.ChecksNone), prMerge, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, result := judgePR(tt.pr)
+			if action != tt.action {
+				t.Errorf("action = %v, want %v", action, tt.action)
+			}
+			if result.Failure != tt.failure {
+				t.Errorf("failure = %q, want %q", result.Failure, tt.failure)
+			}
+			if action == prWait && !result.Pending {
+				t.Error("waiting result should be Pending")
+			}
+		})
+	}
+}
+
+// setupPRRig returns an engineer on a temp rig with a pushed-ready branch
+// and a fake forge that short-polls.
+func setupPRRig(t *testing.T, f *fakeForge) *Engineer {
+	t.Helper()
+	e, work := setupHeldRig(t)
+	cmd := exec.Command("git", "checkout", "-q", "-b", "polecat/nux/gt-1", "main")
+	cmd.Dir = work
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git checkout: %v\n%s", err, out)
+	}
+	e.SetOutput(io.Discard)
+	e.SetForge(f)
+	e.config.MergeMode = MergeModePullRequest
+	e.config.PollInterval = time.Millisecond
+	e.config.PRTimeout = 50 * time.Millisecond
+	return e
+}
+
+func TestMergeViaPR(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tests := []struct {
+		name    string
+		forge   *fakeForge
+		success bool
+		pending bool
+		failure FailureType
+		merges  int
+		wantSHA string
+		wantErr string
+	}{
+		{
+			name: "merges once green",
+			forge: &fakeForge{states: []*forge.PR{
+				openPR(forge.Mergeable, forge.ChecksPending),
+				openPR(forge.Mergeable, forge.ChecksSuccess),
+			}},
+			success: true, merges: 1, wantSHA: "abc123",
+		},
+		{
+			name:    "checks fail",
+			forge:   &fakeForge{states: []*forge.PR{{State: forge.PROpen, Mergeable: forge.Mergeable, Checks: forge.ChecksFailure, FailedChecks: []string{"ci/test"}}}},
+			failure: FailureTestsFail, wantErr: "ci/test",
+		},
+		{
+			name:    "closed",
+			forge:   &fakeForge{states: []*forge.PR{{State: forge.PRClosed}}},
+			failure: FailureRejected,
+		},
+		{
+			name:    "still pending at timeout",
+			forge:   &fakeForge{states: []*forge.PR{openPR(forge.Blocked, forge.ChecksSuccess)}},
+			pending: true, wantErr: "reviews",
+		},
+		{
+			name: "refused merge keeps waiting",
+			forge: &fakeForge{
+				states:   []*forge.PR{openPR(forge.Mergeable, forge.ChecksSuccess)},
+				mergeErr: fmt.Errorf("%w: protected", forge.ErrNotMergeable),
+			},
+			pending: true, wantErr: "refused",
+		},
+		{
+			name: "merge API error",
+			forge: &fakeForge{
+				states:   []*forge.PR{openPR(forge.Mergeable, forge.ChecksSuccess)},
+				mergeErr: &forge.APIError{Status: 500},
+			},
+			failure: FailurePushFail, merges: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := setupPRRig(t, tt.forge)
+			result := e.mergeViaPR(context.Background(), "HEAD", "polecat/nux/gt-1", "main", "", "")
+
+			if result.Success != tt.success || result.Pending != tt.pending || result.Failure != tt.failure {
+				t.Fatalf("result = %+v, want success=%v pending=%v failure=%q", result, tt.success, tt.pending, tt.failure)
+			}
+			if tt.merges > 0 && tt.forge.merged != tt.merges {
+				t.Errorf("merged %d times, want %d", tt.forge.merged, tt.merges)
+			}
+			if result.MergeCommit != tt.wantSHA {
+				t.Errorf("MergeCommit = %q, want %q", result.MergeCommit, tt.wantSHA)
+			}
+			if !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantErr)
+			}
+			if len(tt.forge.created) != 1 || tt.forge.created[0].Head != "polecat/nux/gt-1" || tt.forge.created[0].Base != "main" {
+				t.Errorf("created = %+v, want one PR from the branch into main", tt.forge.created)
+			}
+		})
+	}
+}
+
+func TestMergeViaPR_ReusesExistingPR(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	f := &fakeForge{
+		existing: &forge.PR{Number: 3, State: forge.PROpen},
+		states:   []*forge.PR{{State: forge.PRMerged, MergeCommit: "def456"}},
+	}
+	e := setupPRRig(t, f)
+
+	result := e.mergeViaPR(context.Background(), "HEAD", "polecat/nux/gt-1", "main", "", "")
+	if !result.Success || result.MergeCommit != "def456" {
+		t.Fatalf("result = %+v, want success with def456", result)
+	}
+	if len(f.created) != 0 || f.updated != 1 {
+		t.Errorf("created %d, updated %d; want the existing PR updated", len(f.created), f.updated)
+	}
+	if f.merged != 0 {
+		t.Error("already-merged PR should not be merged again")
+	}
+}
+
+func TestMergeViaPR_LeaseRefusesConcurrentPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	f := &fakeForge{states: []*forge.PR{{State: forge.PRMerged, MergeCommit: "def456"}}}
+	e := setupPRRig(t, f)
+
+	// The branch is on origin when the refinery starts on the MR...
+	if err := e.git.Push("origin", "polecat/nux/gt-1", false); err != nil {
+		t.Fatal(err)
+	}
+	lease := e.remoteBranchSHA("polecat/nux/gt-1")
+	if lease == "" {
+		t.Fatal("remoteBranchSHA found no branch on origin")
+	}
+
+	// ...and someone pushes a review fix to it while the MR is verified
+	work := e.git.WorkDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	fix := commit(t, work, "polecat/nux/gt-1", "review fix")
+	run("push", "origin", fix+":refs/heads/polecat/nux/gt-1")
+
+	result := e.mergeViaPR(context.Background(), "polecat/nux/gt-1", "polecat/nux/gt-1", "main", "", lease)
+	if result.Failure != FailurePushFail {
+		t.Fatalf("result = %+v, want the push refused", result)
+	}
+	if len(f.created) != 0 || f.merged != 0 {
+		t.Error("no PR should be opened or merged after a refused push")
+	}
+}
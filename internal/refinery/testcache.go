@@ -0,0 +1,144 @@
+package refinery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultTestCacheTTL is how long a passing test run stays reusable.
+const DefaultTestCacheTTL = 7 * 24 * time.Hour
+
+// DefaultTestCacheEnv lists the environment variables folded into the
+// cache key when the rig doesn't name its own.
+var DefaultTestCacheEnv = []string{"PATH", "GOFLAGS", "GOOS", "GOARCH", "CGO_ENABLED", "GOEXPERIMENT", "GOTOOLCHAIN"}
+
+// TestCache remembers passing test runs by the tree they ran on, so an MR
+// retried after an unrelated conflict or infra failure skips tests when the
+// merged tree is identical. Only passes are cached: a failure may be flaky
+// and must always be rerun.
+type TestCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// TestCacheEntry is one cached passing run.
+type TestCacheEntry struct {
+	Tree     string    `json:"tree"`
+	Command  string    `json:"command"`
+	Env      string    `json:"env"`
+	MR       string    `json:"mr,omitempty"`
+	PassedAt time.Time `json:"passed_at"`
+	Duration int64     `json:"ms"` // How long the run took, i.e. what a hit saves
+}
+
+// NewTestCache returns the rig's test cache, stored under .runtime/test-cache.
+func NewTestCache(rigPath string, ttl time.Duration) *TestCache {
+	if ttl <= 0 {
+		ttl = DefaultTestCacheTTL
+	}
+	return &TestCache{
+		dir: filepath.Join(rigPath, ".runtime", "test-cache"),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// EnvFingerprint hashes the named environment variables (and the host
+// platform) so runs under a different toolchain or flags don't share results.
+func EnvFingerprint(vars []string) string {
+	names := append([]string(nil), vars...)
+	sort.Strings(names)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s/%s\n", runtime.GOOS, runtime.GOARCH)
+	for _, name := range names {
+		fmt.Fprintf(h, "%s=%s\n", name, os.Getenv(name))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// testCacheKey is the cache key for a tree, command and environment.
+func testCacheKey(tree, command, env string) string {
+	sum := sha256.Sum256([]byte(tree + "\x00" + command + "\x00" + env))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *TestCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Lookup returns the unexpired passing run for tree, command and env.
+func (c *TestCache) Lookup(tree, command, env string) (*TestCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(testCacheKey(tree, command, env)))
+	if err != nil {
+		return nil, false
+	}
+	var entry TestCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	// Guard against collisions and hand-edited files
+	if entry.Tree != tree || entry.Command != command || entry.Env != env {
+		return nil, false
+	}
+	if c.now().Sub(entry.PassedAt) > c.ttl {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Store records a passing run and prunes expired entries.
+func (c *TestCache) Store(entry TestCacheEntry) error {
+	if entry.PassedAt.IsZero() {
+		entry.PassedAt = c.now()
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	if err := util.AtomicWriteJSON(c.path(testCacheKey(entry.Tree, entry.Command, entry.Env)), entry); err != nil {
+		return err
+	}
+	_, _ = c.Prune()
+	return nil
+}
+
+// Prune removes expired entries and returns how many it removed.
+func (c *TestCache) Prune() (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	removed := 0
+	cutoff := c.now().Add(-c.ttl)
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(c.dir, e.Name())) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Clear removes every entry.
+func (c *TestCache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
@@ -0,0 +1,198 @@
+package refinery
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTestCache_StoreAndLookup(t *testing.T) {
+	c := NewTestCache(t.TempDir(), time.Hour)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	if _, ok := c.Lookup("tree1", "go test ./...", "env"); ok {
+		t.Fatal("hit on empty cache")
+	}
+	if err := c.Store(TestCacheEntry{Tree: "tree1", Command: "go test ./...", Env: "env", Duration: 90000}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	hit, ok := c.Lookup("tree1", "go test ./...", "env")
+	if !ok || hit.Duration != 90000 || !hit.PassedAt.Equal(now) {
+		t.Fatalf("Lookup = %+v, %v", hit, ok)
+	}
+
+	// Any part of the key changing is a miss
+	for _, miss := range [][3]string{
+		{"tree2", "go test ./...", "env"},
+		{"tree1", "go test -race ./...", "env"},
+		{"tree1", "go test ./...", "other-env"},
+	} {
+		if _, ok := c.Lookup(miss[0], miss[1], miss[2]); ok {
+			t.Errorf("Lookup(%v) hit, want miss", miss)
+		}
+	}
+
+	// Expired after the TTL
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.Lookup("tree1", "go test ./...", "env"); ok {
+		t.Error("hit after TTL")
+	}
+}
+
+func TestTestCache_PruneAndClear(t *testing.T) {
+	rig := t.TempDir()
+	c := NewTestCache(rig, time.Hour)
+	if err := c.Store(TestCacheEntry{Tree: "a", Command: "t", Env: "e"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := c.Prune(); n != 0 {
+		t.Errorf("pruned %d fresh entries", n)
+	}
+
+	c.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if n, _ := c.Prune(); n != 1 {
+		t.Errorf("pruned %d, want the expired entry", n)
+	}
+
+	if err := c.Store(TestCacheEntry{Tree: "b", Command: "t", Env: "e"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(rig, ".runtime", "test-cache")); !os.IsNotExist(err) {
+		t.Error("Clear left the cache directory")
+	}
+}
+
+func TestEnvFingerprint(t *testing.T) {
+	t.Setenv("GT_TEST_CACHE_VAR", "one")
+	a := EnvFingerprint([]string{"GT_TEST_CACHE_VAR", "PATH"})
+	if b := EnvFingerprint([]string{"PATH", "GT_TEST_CACHE_VAR"}); a != b {
+		t.Error("fingerprint depends on variable order")
+	}
+	t.Setenv("GT_TEST_CACHE_VAR", "two")
+	if b := EnvFingerprint([]string{"GT_TEST_CACHE_VAR", "PATH"}); a == b {
+		t.Error("fingerprint ignores variable values")
+	}
+}
+
+func TestTestsGate_UsesCache(t *testing.T) {
+	env, dir := setupGateRepo(t, map[string]string{"main.go": "package main\n"})
+	env.TestCache = NewTestCache(t.TempDir(), time.Hour)
+
+	// Counts runs in a file outside the repo so the tree stays clean
+	counter := filepath.Join(t.TempDir(), "runs")
+	mq := DefaultMergeQueueConfig()
+	g, err := newTestsGate(GateConfig{Name: "tests", Command: "echo run >> " + counter}, mq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "run")
+	}
+
+	if r := g.Run(context.Background(), env); r.Status != GatePass || r.Cached {
+		t.Fatalf("first run: %+v", r)
+	}
+	r := g.Run(context.Background(), env)
+	if r.Status != GatePass || !r.Cached || runs() != 1 {
+		t.Fatalf("second run: %+v after %d runs, want a cache hit", r, runs())
+	}
+
+	// Uncommitted tracked changes bypass the cache
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := g.Run(context.Background(), env); r.Cached || runs() != 2 {
+		t.Errorf("dirty tree: %+v after %d runs, want a real run", r, runs())
+	}
+
+	// A new tree is a miss
+	cmd := exec.Command("git", "commit", "-qam", "more")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+	if r := g.Run(context.Background(), env); r.Cached || runs() != 3 {
+		t.Errorf("new tree: %+v after %d runs, want a real run", r, runs())
+	}
+
+	// Untracked files bypass the cache too
+	if err := os.WriteFile(filepath.Join(dir, "extra_test.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := g.Run(context.Background(), env); r.Cached || runs() != 4 {
+		t.Errorf("untracked file: %+v after %d runs, want a real run", r, runs())
+	}
+}
+
+func TestTestsGate_CachesTrialMergeTree(t *testing.T) {
+	env, dir := setupGateRepo(t, map[string]string{"main.go": "package main\n"})
+	env.TestCache = NewTestCache(t.TempDir(), time.Hour)
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("checkout", "-q", "-b", "polecat/nux")
+	if err := os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "feature")
+	run("checkout", "-q", "main")
+	run("commit", "-q", "--allow-empty", "-m", "main moved on")
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	g, err := newTestsGate(GateConfig{Name: "tests", Command: "echo run >> " + counter}, DefaultMergeQueueConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tests run on the trial merge, neither the branch nor the target alone
+	if err := env.Git.MergeNoCommit("polecat/nux"); err != nil {
+		t.Fatal(err)
+	}
+	if r := g.Run(context.Background(), env); r.Status != GatePass || r.Cached {
+		t.Fatalf("trial merge run: %+v", r)
+	}
+	if err := env.Git.AbortMerge(); err != nil {
+		t.Fatal(err)
+	}
+	if r := g.Run(context.Background(), env); r.Cached {
+		t.Error("the target alone hit the cache entry of the merge")
+	}
+
+	// The real merge produces the tested tree
+	if err := env.Git.MergeNoFF("polecat/nux", "Merge polecat/nux"); err != nil {
+		t.Fatal(err)
+	}
+	if r := g.Run(context.Background(), env); !r.Cached {
+		t.Errorf("merge commit: %+v, want a hit on the trial merge's tree", r)
+	}
+}
+
+func TestTestsGate_FailuresNotCached(t *testing.T) {
+	env, _ := setupGateRepo(t, map[string]string{"main.go": "package main\n"})
+	env.TestCache = NewTestCache(t.TempDir(), time.Hour)
+	mq := DefaultMergeQueueConfig()
+
+	fail, _ := newTestsGate(GateConfig{Name: "tests", Command: "exit 1"}, mq)
+	if r := fail.Run(context.Background(), env); r.Status != GateFail {
+		t.Fatalf("failing run: %+v", r)
+	}
+	if r := fail.Run(context.Background(), env); r.Cached {
+		t.Error("a failure was served from the cache")
+	}
+}
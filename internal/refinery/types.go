@@ -282,6 +282,25 @@ const (
 
 	// FailureCheckout indicates checkout of target branch failed.
 	FailureCheckout FailureType = "checkout_fail"
+
+	// FailureRejected indicates a reviewer closed the MR's pull request
+	// without merging it.
+	FailureRejected FailureType = "rejected"
+
+	// FailureGate indicates a command gate (lint, vet, license scan, ...) failed.
+	FailureGate FailureType = "gate_fail"
+
+	// FailureForbiddenPath indicates the branch touches a path the rig forbids.
+	FailureForbiddenPath FailureType = "forbidden_path"
+
+	// FailureDiffTooLarge indicates the branch exceeds the rig's diff-size limits.
+	FailureDiffTooLarge FailureType = "diff_too_large"
+
+	// FailureSecret indicates the branch adds something that looks like a secret.
+	FailureSecret FailureType = "secret_detected"
+
+	// FailureCoverage indicates test coverage fell below the rig's threshold.
+	FailureCoverage FailureType = "coverage_low"
 )
 
 // FailureLabel returns the beads label for this failure type.
@@ -289,7 +308,8 @@ func (f FailureType) FailureLabel() string {
 	switch f {
 	case FailureConflict:
 		return "needs-rebase"
-	case FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureRejected,
+		FailureGate, FailureForbiddenPath, FailureDiffTooLarge, FailureSecret, FailureCoverage:
 		return "needs-fix"
 	case FailurePushFail:
 		return "needs-retry"
@@ -301,7 +321,8 @@ func (f FailureType) FailureLabel() string {
 // ShouldAssignToWorker returns true if this failure should be assigned back to the worker.
 func (f FailureType) ShouldAssignToWorker() bool {
 	switch f {
-	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureRejected,
+		FailureGate, FailureForbiddenPath, FailureDiffTooLarge, FailureSecret, FailureCoverage:
 		return true
 	default:
 		return false
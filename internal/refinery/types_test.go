@@ -269,6 +269,12 @@ func TestFailureType_FailureLabel(t *testing.T) {
 		{FailurePushFail, "needs-retry"},
 		{FailureFetch, ""},
 		{FailureCheckout, ""},
+		{FailureRejected, "needs-fix"},
+		{FailureGate, "needs-fix"},
+		{FailureForbiddenPath, "needs-fix"},
+		{FailureDiffTooLarge, "needs-fix"},
+		{FailureSecret, "needs-fix"},
+		{FailureCoverage, "needs-fix"},
 	}
 
 	for _, tt := range tests {
@@ -294,6 +300,12 @@ func TestFailureType_ShouldAssignToWorker(t *testing.T) {
 		{FailurePushFail, false},
 		{FailureFetch, false},
 		{FailureCheckout, false},
+		{FailureRejected, true},
+		{FailureGate, true},
+		{FailureForbiddenPath, true},
+		{FailureDiffTooLarge, true},
+		{FailureSecret, true},
+		{FailureCoverage, true},
 	}
 
 	for _, tt := range tests {
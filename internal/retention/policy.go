@@ -0,0 +1,275 @@
+package retention
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Log sources, as named in the town settings' retention map.
+const (
+	SourceEvents  = "events"
+	SourceFeed    = "feed"
+	SourceTownlog = "townlog"
+	SourceDaemon  = "daemon"
+	SourceMail    = "mail"
+
+	// SourceRecordings is session recordings: whole files pruned by last
+	// write (see PruneFiles), plus the index that is rotated like a log.
+	SourceRecordings = "recordings"
+)
+
+// Policy is a resolved retention policy.
+type Policy struct {
+	MaxSize      int64         // Rotate once the live file is this large (0 = never)
+	MaxAge       time.Duration // Rotate once the first live entry is this old (0 = never)
+	Keep         time.Duration // Delete segments rotated longer ago (0 = forever)
+	KeepSegments int           // Keep at most this many segments (0 = no limit)
+	Compress     bool
+}
+
+// PolicyFor resolves a source's policy. Fields set in the town settings
+// override the built-in default for that source.
+func PolicyFor(settings *config.TownSettings, source string) (Policy, error) {
+	merged := config.RetentionPolicy{}
+	if def := config.DefaultRetentionPolicies()[source]; def != nil {
+		merged = *def
+	}
+	if settings != nil {
+		if p := settings.Retention[source]; p != nil {
+			if p.MaxSizeMB != 0 {
+				merged.MaxSizeMB = p.MaxSizeMB
+			}
+			if p.MaxAge != "" {
+				merged.MaxAge = p.MaxAge
+			}
+			if p.Keep != "" {
+				merged.Keep = p.Keep
+			}
+			if p.KeepSegments != 0 {
+				merged.KeepSegments = p.KeepSegments
+			}
+			if p.Compress != nil {
+				merged.Compress = p.Compress
+			}
+		}
+	}
+
+	policy := Policy{
+		MaxSize:      int64(merged.MaxSizeMB) * 1024 * 1024,
+		KeepSegments: merged.KeepSegments,
+		Compress:     merged.Compress == nil || *merged.Compress,
+	}
+	var err error
+	if policy.MaxAge, err = parseDuration(merged.MaxAge); err != nil {
+		return Policy{}, fmt.Errorf("retention.%s.max_age: %w", source, err)
+	}
+	if policy.Keep, err = parseDuration(merged.Keep); err != nil {
+		return Policy{}, fmt.Errorf("retention.%s.keep: %w", source, err)
+	}
+	return policy, nil
+}
+
+// parseDuration parses a duration with support for days (d). Empty is 0.
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Result reports what Apply did to one log.
+type Result struct {
+	Source     string
+	Path       string
+	Rotated    string   // New segment, if the live file was rotated
+	Compressed []string // Segments gzipped
+	Removed    []string // Segments deleted
+}
+
+// Changed reports whether Apply did anything.
+func (r Result) Changed() bool {
+	return r.Rotated != "" || len(r.Compressed) > 0 || len(r.Removed) > 0
+}
+
+// Apply rotates the live file if it is due (or if force is set), gzips
+// segments no longer being written, and deletes expired segments.
+func Apply(path string, p Policy, now time.Time, force bool) (Result, error) {
+	res := Result{Path: path}
+
+	if force || rotationDue(path, p, now) {
+		seg, err := Rotate(path, now)
+		if err != nil {
+			return res, err
+		}
+		res.Rotated = seg
+	}
+
+	segs, err := Segments(path)
+	if err != nil {
+		return res, err
+	}
+	var kept []Segment
+	for i, s := range segs {
+		expired := p.Keep > 0 && now.Sub(s.Rotated) > p.Keep
+		excess := p.KeepSegments > 0 && len(segs)-i > p.KeepSegments
+		if expired || excess {
+			if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+				return res, err
+			}
+			res.Removed = append(res.Removed, s.Path)
+			continue
+		}
+		kept = append(kept, s)
+	}
+
+	if !p.Compress {
+		return res, nil
+	}
+	for _, s := range kept {
+		if s.Compressed {
+			continue
+		}
+		info, err := os.Stat(s.Path)
+		if err != nil || now.Sub(info.ModTime()) < compressGrace {
+			continue
+		}
+		gz, err := Compress(s.Path)
+		if err != nil {
+			return res, fmt.Errorf("compressing %s: %w", filepath.Base(s.Path), err)
+		}
+		res.Compressed = append(res.Compressed, gz)
+	}
+	return res, nil
+}
+
+func rotationDue(path string, p Policy, now time.Time) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	if p.MaxSize > 0 && info.Size() >= p.MaxSize {
+		return true
+	}
+	if p.MaxAge > 0 {
+		if first := firstEntryTime(path); !first.IsZero() && now.Sub(first) >= p.MaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneFiles applies a policy to files that are each complete, such as
+// session recordings, rather than to a rotated log. Files last written
+// longer ago than Keep are deleted, then the oldest beyond KeepSegments.
+func PruneFiles(pattern string, p Policy, now time.Time) (Result, error) {
+	res := Result{Path: pattern}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return res, err
+	}
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	var files []file
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+			files = append(files, file{m, info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	for i, f := range files {
+		expired := p.Keep > 0 && now.Sub(f.modTime) > p.Keep
+		excess := p.KeepSegments > 0 && len(files)-i > p.KeepSegments
+		if !expired && !excess {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return res, err
+		}
+		res.Removed = append(res.Removed, f.path)
+	}
+	return res, nil
+}
+
+// Target is a log under retention.
+type Target struct {
+	Source string
+	Path   string
+	Files  bool // Path is a glob of complete files, for PruneFiles
+}
+
+// Targets lists the town's logs: the raw events, the curated feed, the
+// town log, the daemon log, every mail archive, and session recordings
+// with their index.
+func Targets(townRoot string) []Target {
+	recordings := filepath.Join(townRoot, "logs", "recordings")
+	targets := []Target{
+		{Source: SourceEvents, Path: filepath.Join(townRoot, events.EventsFile)},
+		{Source: SourceFeed, Path: filepath.Join(townRoot, events.FeedFile)},
+		{Source: SourceTownlog, Path: filepath.Join(townRoot, "logs", "town.log")},
+		{Source: SourceDaemon, Path: filepath.Join(townRoot, "daemon", "daemon.log")},
+		{Source: SourceRecordings, Path: filepath.Join(recordings, "index.jsonl")},
+		{Source: SourceRecordings, Path: filepath.Join(globEscape(recordings), "*.cast*"), Files: true},
+	}
+	// Beads-backed mailboxes archive to <beads dir>/archive.jsonl; legacy
+	// mailboxes to <mail dir>/inbox.jsonl.archive.
+	for _, pattern := range []string{
+		".beads/archive.jsonl",
+		"*/.beads/archive.jsonl",
+		"*/mayor/rig/.beads/archive.jsonl",
+		"*/mail/inbox.jsonl.archive",
+		"*/*/mail/inbox.jsonl.archive",
+		"*/*/*/mail/inbox.jsonl.archive",
+	} {
+		matches, _ := filepath.Glob(filepath.Join(globEscape(townRoot), pattern))
+		for _, m := range matches {
+			targets = append(targets, Target{Source: SourceMail, Path: m})
+		}
+	}
+	return targets
+}
+
+// Run applies each of the town's retention policies. Errors for one log
+// don't stop the others; the first is returned.
+func Run(townRoot string, now time.Time, force bool) ([]Result, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	var results []Result
+	var firstErr error
+	for _, t := range Targets(townRoot) {
+		p, err := PolicyFor(settings, t.Source)
+		if err == nil {
+			var res Result
+			if t.Files {
+				res, err = PruneFiles(t.Path, p, now)
+			} else {
+				res, err = Apply(t.Path, p, now, force)
+			}
+			res.Source = t.Source
+			results = append(results, res)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", t.Path, err)
+		}
+	}
+	return results, firstErr
+}
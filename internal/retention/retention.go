@@ -0,0 +1,315 @@
+// Package retention rotates, compresses and prunes Gas Town's append-only
+// logs.
+//
+// A log is a live file that writers append to. Rotation renames it to a
+// segment, <path>.<UTC stamp>, and the next write starts a fresh live
+// file. Segments are gzipped (<path>.<stamp>.gz) once writers that opened
+// the old file have had time to finish, and deleted when older than the
+// source's retention. Open reads a log's segments and live file as one
+// stream, so readers see the whole history across rotations.
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stampLayout names segments; it sorts lexically in time order.
+const stampLayout = "20060102T150405Z"
+
+// compressGrace is how long a segment must go unwritten before it is
+// compressed. Writers that opened the live file before it was rotated
+// (the daemon's logger, until its next heartbeat) still append to it.
+const compressGrace = 10 * time.Minute
+
+// Segment is a rotated piece of a log.
+type Segment struct {
+	Path       string
+	Rotated    time.Time // When the segment was rotated out
+	Compressed bool
+}
+
+// Segments returns a log's rotated segments, oldest first.
+func Segments(path string) ([]Segment, error) {
+	matches, err := filepath.Glob(globEscape(path) + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var segs []Segment
+	for _, m := range matches {
+		suffix := strings.TrimPrefix(m, path+".")
+		compressed := strings.HasSuffix(suffix, ".gz")
+		t, err := time.Parse(stampLayout, strings.TrimSuffix(suffix, ".gz"))
+		if err != nil {
+			continue // Not a segment (e.g. a .tmp file)
+		}
+		segs = append(segs, Segment{Path: m, Rotated: t, Compressed: compressed})
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i].Rotated.Before(segs[j].Rotated) })
+	return segs, nil
+}
+
+// globEscape quotes glob metacharacters in a literal path.
+func globEscape(path string) string {
+	var b strings.Builder
+	for _, c := range path {
+		if strings.ContainsRune(`*?[\`, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Open returns a reader over a log's segments, oldest first, then its live
+// file. If neither exists the error satisfies os.IsNotExist.
+func Open(path string) (io.ReadCloser, error) {
+	return OpenSince(path, time.Time{})
+}
+
+// OpenSince is Open without the segments rotated before since, which hold
+// only older entries.
+func OpenSince(path string, since time.Time) (io.ReadCloser, error) {
+	segs, err := Segments(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(segs)+1)
+	for _, s := range segs {
+		if !s.Rotated.Before(since) {
+			paths = append(paths, s.Path)
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		paths = append(paths, path)
+	} else if len(paths) == 0 {
+		return nil, err
+	}
+	return &multiReader{paths: paths}, nil
+}
+
+// OpenSegment returns a reader over one segment, decompressing it if it
+// is gzipped.
+func OpenSegment(s Segment) (io.ReadCloser, error) {
+	return &multiReader{paths: []string{s.Path}}, nil
+}
+
+// RemoveSegments deletes all of a log's rotated segments.
+func RemoveSegments(path string) error {
+	segs, err := Segments(path)
+	if err != nil {
+		return err
+	}
+	for _, s := range segs {
+		if err := os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replaced reports whether the file at path is no longer f, i.e. f was
+// rotated away. Long-lived readers and writers reopen path when it is.
+func Replaced(f *os.File, path string) bool {
+	cur, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	open, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(open, cur)
+}
+
+// multiReader reads files in sequence, decompressing .gz segments. A
+// newline is inserted between files whose last line was unterminated so
+// lines from adjacent segments never run together.
+type multiReader struct {
+	paths []string
+	file  *os.File
+	r     io.Reader
+	last  byte
+}
+
+func (m *multiReader) Read(p []byte) (int, error) {
+	for {
+		if m.r == nil {
+			if len(m.paths) == 0 {
+				return 0, io.EOF
+			}
+			if err := m.next(); err != nil {
+				return 0, err
+			}
+			if m.last != 0 && m.last != '\n' && len(p) > 0 {
+				m.last = '\n'
+				p[0] = '\n'
+				return 1, nil
+			}
+		}
+		n, err := m.r.Read(p)
+		if n > 0 {
+			m.last = p[n-1]
+		}
+		if err == io.EOF {
+			m.closeFile()
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (m *multiReader) next() error {
+	path := m.paths[0]
+	m.paths = m.paths[1:]
+	f, err := os.Open(path) //nolint:gosec // G304: path is a log under the town root
+	if os.IsNotExist(err) && !strings.HasSuffix(path, ".gz") {
+		// Compressed since it was listed
+		path += ".gz"
+		f, err = os.Open(path) //nolint:gosec // G304: path is a log under the town root
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			m.r = strings.NewReader("") // Pruned since it was listed
+			return nil
+		}
+		return err
+	}
+	m.file = f
+	m.r = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			m.closeFile()
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		m.r = gz
+	}
+	return nil
+}
+
+func (m *multiReader) closeFile() {
+	if m.file != nil {
+		_ = m.file.Close()
+		m.file = nil
+	}
+	m.r = nil
+}
+
+func (m *multiReader) Close() error {
+	m.closeFile()
+	m.paths = nil
+	return nil
+}
+
+// Rotate renames the live file to a new segment and returns its path. An
+// empty or missing live file is not rotated.
+func Rotate(path string, now time.Time) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 {
+		return "", nil
+	}
+	seg := path + "." + now.UTC().Format(stampLayout)
+	if _, err := os.Stat(seg); err == nil {
+		return "", fmt.Errorf("%s already rotated at %s", filepath.Base(path), now.UTC().Format(stampLayout))
+	}
+	if err := os.Rename(path, seg); err != nil {
+		return "", err
+	}
+	return seg, nil
+}
+
+// Compress gzips a segment (or any finished file) to <path>.gz and
+// removes the original.
+func Compress(seg string) (string, error) {
+	in, err := os.Open(seg) //nolint:gosec // G304: path is a log under the town root
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = in.Close() }()
+	info, err := in.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	// The segment keeps the log's permissions (the town and daemon logs
+	// are private)
+	dst := seg + ".gz"
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if cerr := gz.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+	return dst, os.Remove(seg)
+}
+
+// firstEntryTime returns the timestamp of a log's first line, or zero if
+// it has none that is recognized.
+func firstEntryTime(path string) time.Time {
+	f, err := os.Open(path) //nolint:gosec // G304: path is a log under the town root
+	if err != nil {
+		return time.Time{}
+	}
+	defer func() { _ = f.Close() }()
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return time.Time{}
+	}
+	return lineTime(line)
+}
+
+// lineTime extracts a line's timestamp: the "ts" or "timestamp" field of a
+// JSON line, or a leading "2006-01-02 15:04:05" (town log) or
+// "2006/01/02 15:04:05" (daemon log) local time.
+func lineTime(line string) time.Time {
+	if strings.HasPrefix(line, "{") {
+		var v struct {
+			TS        string `json:"ts"`
+			Timestamp string `json:"timestamp"`
+		}
+		if json.Unmarshal([]byte(line), &v) == nil {
+			for _, s := range []string{v.TS, v.Timestamp} {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					return t
+				}
+			}
+		}
+		return time.Time{}
+	}
+	if len(line) < 19 {
+		return time.Time{}
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, line[:19], time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
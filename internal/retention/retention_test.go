@@ -0,0 +1,257 @@
+package retention
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readAll(t *testing.T, path string) string {
+	t.Helper()
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// age backdates a file's mtime so it is past the compress grace.
+func age(t *testing.T, path string, d time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-d)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen_ReadsAcrossSegments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if _, err := Open(path); !os.IsNotExist(err) {
+		t.Fatalf("Open(missing) err = %v, want not-exist", err)
+	}
+
+	t0 := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(t, path, "a\n")
+	seg1, err := Rotate(path, t0)
+	if err != nil || seg1 == "" {
+		t.Fatalf("Rotate = %q, %v", seg1, err)
+	}
+	writeFile(t, path, "b") // Unterminated last line
+	seg2, _ := Rotate(path, t0.Add(time.Hour))
+	age(t, seg2, time.Hour)
+	if _, err := Compress(seg2); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "c\n")
+
+	if got := readAll(t, path); got != "a\nb\nc\n" {
+		t.Errorf("Open read %q, want a, b, c", got)
+	}
+
+	// Only the live file once it has been rotated away
+	r, err := OpenSince(path, t0.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "c\n" {
+		t.Errorf("OpenSince read %q, want c", data)
+	}
+
+	// Segments alone, with no live file
+	os.Remove(path)
+	if got := readAll(t, path); got != "a\nb" {
+		t.Errorf("Open without live file read %q", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "town.log")
+	now := time.Now()
+
+	// Below every limit: nothing happens
+	writeFile(t, path, now.Format("2006-01-02 15:04:05")+" [spawn] gastown/Toast spawned\n")
+	p := Policy{MaxSize: 1024, MaxAge: 24 * time.Hour, Keep: 30 * 24 * time.Hour, Compress: true}
+	res, err := Apply(path, p, now, false)
+	if err != nil || res.Changed() {
+		t.Fatalf("Apply under limits = %+v, %v", res, err)
+	}
+
+	// Size limit
+	writeFile(t, path, strings.Repeat("2025-12-01 00:00:00 [spawn] x y\n", 100))
+	p.MaxAge = 0
+	res, err = Apply(path, p, now, false)
+	if err != nil || res.Rotated == "" {
+		t.Fatalf("Apply over size = %+v, %v", res, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("live file not rotated away")
+	}
+
+	// Age limit, by the first entry's timestamp
+	writeFile(t, path, now.Add(-48*time.Hour).Format("2006-01-02 15:04:05")+" [spawn] x y\n")
+	p.MaxAge = 24 * time.Hour
+	later := now.Add(time.Second)
+	res, err = Apply(path, p, later, false)
+	if err != nil || res.Rotated == "" {
+		t.Fatalf("Apply over age = %+v, %v", res, err)
+	}
+	if len(res.Compressed) != 0 {
+		t.Errorf("compressed segments still being written: %v", res.Compressed)
+	}
+
+	// Settled segments are compressed; expired and excess ones removed
+	segs, _ := Segments(path)
+	for _, s := range segs {
+		age(t, s.Path, time.Hour)
+	}
+	res, err = Apply(path, p, later, false)
+	if err != nil || len(res.Compressed) != 2 {
+		t.Fatalf("Apply compress = %+v, %v", res, err)
+	}
+	res, err = Apply(path, Policy{KeepSegments: 1}, later, false)
+	if err != nil || len(res.Removed) != 1 {
+		t.Fatalf("Apply keep_segments = %+v, %v", res, err)
+	}
+	res, err = Apply(path, Policy{Keep: time.Hour}, later.Add(2*time.Hour), false)
+	if err != nil || len(res.Removed) != 1 {
+		t.Fatalf("Apply keep = %+v, %v", res, err)
+	}
+	if segs, _ := Segments(path); len(segs) != 0 {
+		t.Errorf("segments left: %v", segs)
+	}
+
+	// Force rotates regardless of limits, but never an empty log
+	writeFile(t, path, "x\n")
+	if res, _ := Apply(path, Policy{}, later, true); res.Rotated == "" {
+		t.Error("force did not rotate")
+	}
+	if res, _ := Apply(path, Policy{}, later.Add(time.Second), true); res.Rotated != "" {
+		t.Error("rotated a missing log")
+	}
+}
+
+func TestCompress_KeepsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	if err := os.WriteFile(path, []byte("2025/12/01 00:00:00 hi\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	seg, _ := Rotate(path, time.Now())
+	gz, err := Compress(seg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("compressed mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestLineTime(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`{"ts":"2025-12-01T10:00:00Z","type":"sling"}`, true},
+		{`{"id":"m1","timestamp":"2025-12-01T10:00:00.123456789Z"}`, true},
+		{"2025-12-01 10:00:00 [spawn] gastown/Toast spawned", true},
+		{"2025/12/01 10:00:00 Heartbeat", true},
+		{`{"type":"no time"}`, false},
+		{"garbage", false},
+	}
+	for _, tt := range tests {
+		if got := lineTime(tt.line); got.IsZero() == tt.want {
+			t.Errorf("lineTime(%q) = %v", tt.line, got)
+		}
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	off := false
+	settings := config.NewTownSettings()
+	settings.Retention = map[string]*config.RetentionPolicy{
+		"events": {Keep: "14d", Compress: &off},
+		"feed":   {MaxAge: "bogus"},
+	}
+
+	p, err := PolicyFor(settings, SourceEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Keep != 14*24*time.Hour || p.Compress || p.MaxSize != 50*1024*1024 || p.MaxAge != 7*24*time.Hour {
+		t.Errorf("events policy = %+v", p)
+	}
+	if p, _ := PolicyFor(nil, SourceDaemon); !p.Compress || p.Keep != 30*24*time.Hour {
+		t.Errorf("default daemon policy = %+v", p)
+	}
+	if _, err := PolicyFor(settings, SourceFeed); err == nil {
+		t.Error("bad max_age accepted")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	town := t.TempDir()
+	writeFile(t, filepath.Join(town, ".beads", "archive.jsonl"), "")
+	writeFile(t, filepath.Join(town, "gastown", ".beads", "archive.jsonl"), "")
+	writeFile(t, filepath.Join(town, "gastown", "witness", "mail", "inbox.jsonl.archive"), "")
+
+	var mail []string
+	for _, target := range Targets(town) {
+		if target.Source == SourceMail {
+			rel, _ := filepath.Rel(town, target.Path)
+			mail = append(mail, rel)
+		}
+	}
+	if len(mail) != 3 {
+		t.Errorf("mail targets = %v, want 3", mail)
+	}
+}
+
+func TestPruneFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"a.cast.gz", "b.cast.gz", "c.cast"} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, "x")
+		age(t, path, time.Duration(3-i)*24*time.Hour)
+	}
+	writeFile(t, filepath.Join(dir, "index.jsonl"), "{}\n")
+	pattern := filepath.Join(dir, "*.cast*")
+
+	res, err := PruneFiles(pattern, Policy{Keep: 60 * time.Hour}, now)
+	if err != nil || len(res.Removed) != 1 || filepath.Base(res.Removed[0]) != "a.cast.gz" {
+		t.Fatalf("PruneFiles keep = %+v, %v", res, err)
+	}
+	res, err = PruneFiles(pattern, Policy{KeepSegments: 1}, now)
+	if err != nil || len(res.Removed) != 1 || filepath.Base(res.Removed[0]) != "b.cast.gz" {
+		t.Fatalf("PruneFiles keep_segments = %+v, %v", res, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.jsonl")); err != nil {
+		t.Error("pruned a file outside the pattern")
+	}
+}
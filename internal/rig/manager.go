@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -371,15 +371,12 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		// beads.db is gitignored so it won't exist after clone - we need to create it.
 		// bd init --prefix will create the database and auto-import from issues.jsonl.
 		if _, err := os.Stat(sourceBeadsDB); os.IsNotExist(err) {
-			cmd := exec.Command("bd", "init", "--prefix", opts.BeadsPrefix) // opts.BeadsPrefix validated earlier
-			cmd.Dir = mayorRigPath
-			if output, err := cmd.CombinedOutput(); err != nil {
-				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
+			bd := beads.New(mayorRigPath)
+			if err := bd.Init(opts.BeadsPrefix); err != nil { // opts.BeadsPrefix validated earlier
+				fmt.Printf("  Warning: Could not init bd database: %v\n", err)
 			}
 			// Configure custom types for Gas Town (beads v0.46.0+)
-			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-			configCmd.Dir = mayorRigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_ = bd.ConfigSet("types.custom", constants.BeadsCustomTypes) // Ignore errors - older beads don't need this
 		}
 	}
 
@@ -592,23 +589,10 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 		return err
 	}
 
-	// Build environment with explicit BEADS_DIR to prevent bd from
-	// finding a parent directory's .beads/ database
-	env := os.Environ()
-	filteredEnv := make([]string, 0, len(env)+1)
-	for _, e := range env {
-		if !strings.HasPrefix(e, "BEADS_DIR=") {
-			filteredEnv = append(filteredEnv, e)
-		}
-	}
-	filteredEnv = append(filteredEnv, "BEADS_DIR="+beadsDir)
-
-	// Run bd init if available
-	cmd := exec.Command("bd", "init", "--prefix", prefix)
-	cmd.Dir = rigPath
-	cmd.Env = filteredEnv
-	_, err := cmd.CombinedOutput()
-	if err != nil {
+	// Run bd init if available. BEADS_DIR is explicit to prevent bd from
+	// finding a parent directory's .beads/ database.
+	bd := beads.NewWithBeadsDir(rigPath, beadsDir)
+	if err := bd.Init(prefix); err != nil {
 		// bd might not be installed or failed, create minimal structure
 		// Note: beads currently expects YAML format for config
 		configPath := filepath.Join(beadsDir, "config.yaml")
@@ -620,20 +604,14 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 
 	// Configure custom types for Gas Town (agent, role, rig, convoy).
 	// These were extracted from beads core in v0.46.0 and now require explicit config.
-	configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
-	configCmd.Dir = rigPath
-	configCmd.Env = filteredEnv
 	// Ignore errors - older beads versions don't need this
-	_, _ = configCmd.CombinedOutput()
+	_ = bd.ConfigSet("types.custom", constants.BeadsCustomTypes)
 
 	// Ensure database has repository fingerprint (GH #25).
 	// This is idempotent - safe on both new and legacy (pre-0.17.5) databases.
 	// Without fingerprint, the bd daemon fails to start silently.
-	migrateCmd := exec.Command("bd", "migrate", "--update-repo-id")
-	migrateCmd.Dir = rigPath
-	migrateCmd.Env = filteredEnv
 	// Ignore errors - fingerprint is optional for functionality
-	_, _ = migrateCmd.CombinedOutput()
+	_ = bd.UpdateRepoID()
 
 	// Ensure issues.jsonl exists to prevent bd auto-export from corrupting other files.
 	// bd init creates beads.db but not issues.jsonl in SQLite mode.
@@ -1046,9 +1024,7 @@ func (m *Manager) createPatrolHooks(workspacePath string, runtimeConfig *config.
 // These molecules define the work loops for Deacon, Witness, and Refinery roles.
 func (m *Manager) seedPatrolMolecules(rigPath string) error {
 	// Use bd command to seed molecules (more reliable than internal API)
-	cmd := exec.Command("bd", "mol", "seed", "--patrol")
-	cmd.Dir = rigPath
-	if err := cmd.Run(); err != nil {
+	if _, err := beads.New(rigPath).Run("mol", "seed", "--patrol"); err != nil {
 		// Fallback: bd mol seed might not support --patrol yet
 		// Try creating them individually via bd create
 		return m.seedPatrolMoleculesManually(rigPath)
@@ -1077,27 +1053,21 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 		},
 	}
 
+	bd := beads.New(rigPath)
 	for _, mol := range patrolMols {
 		// Check if already exists by title
-		checkCmd := exec.Command("bd", "list", "--type=molecule", "--format=json")
-		checkCmd.Dir = rigPath
-		output, _ := checkCmd.Output()
-		if strings.Contains(string(output), mol.title) {
+		existing, _ := bd.List(beads.ListOptions{IssueType: "molecule", Priority: -1})
+		if slices.ContainsFunc(existing, func(issue *beads.Issue) bool { return issue.Title == mol.title }) {
 			continue // Already exists
 		}
 
-		// Create the molecule
-		cmd := exec.Command("bd", "create", //nolint:gosec // G204: bd is a trusted internal tool
-			"--type=molecule",
-			"--title="+mol.title,
-			"--description="+mol.desc,
-			"--priority=2",
-		)
-		cmd.Dir = rigPath
-		if err := cmd.Run(); err != nil {
-			// Non-fatal, continue with others
-			continue
-		}
+		// Create the molecule; non-fatal, continue with others
+		_, _ = bd.Create(beads.CreateOptions{
+			Title:       mol.title,
+			IssueType:   "molecule",
+			Priority:    2,
+			Description: mol.desc,
+		})
 	}
 	return nil
 }
@@ -405,6 +405,9 @@ set -e
 if [[ -n "$BEADS_DIR_LOG" ]]; then
   echo "${BEADS_DIR:-<unset>}" >> "$BEADS_DIR_LOG"
 fi
+while [[ "$1" == "--no-daemon" || "$1" == "--allow-stale" ]]; do
+  shift
+done
 cmd="$1"
 shift
 if [[ "$cmd" == "init" ]]; then
@@ -2,12 +2,12 @@ package swarm
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -41,28 +41,9 @@ func NewManager(r *rig.Rig) *Manager {
 // This is the canonical way to get swarm state - no in-memory caching.
 func (m *Manager) LoadSwarm(epicID string) (*Swarm, error) {
 	// Query beads for the epic
-	cmd := exec.Command("bd", "show", epicID, "--json")
-	cmd.Dir = m.beadsDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("bd show: %s", strings.TrimSpace(stderr.String()))
-	}
-
-	// Parse the epic
-	var epic struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		MolType   string `json:"mol_type"`
-		CreatedAt string `json:"created_at"`
-		UpdatedAt string `json:"updated_at"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &epic); err != nil {
-		return nil, fmt.Errorf("parsing epic: %w", err)
+	epic, err := m.bd().Show(epicID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Verify it's a swarm molecule
@@ -119,6 +100,11 @@ func appendUnique(slice []string, s string) []string {
 	return append(slice, s)
 }
 
+// bd returns a client for the rig's git-synced beads.
+func (m *Manager) bd() *beads.Beads {
+	return beads.New(m.beadsDir)
+}
+
 // GetSwarm loads a swarm from beads. Alias for LoadSwarm for compatibility.
 func (m *Manager) GetSwarm(id string) (*Swarm, error) {
 	return m.LoadSwarm(id)
@@ -127,26 +113,11 @@ func (m *Manager) GetSwarm(id string) (*Swarm, error) {
 // GetReadyTasks returns tasks ready to be assigned by querying beads.
 func (m *Manager) GetReadyTasks(swarmID string) ([]SwarmTask, error) {
 	// Use bd swarm status to get ready front
-	cmd := exec.Command("bd", "swarm", "status", swarmID, "--json")
-	cmd.Dir = m.beadsDir
-
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	status, err := m.bd().SwarmStatus(swarmID)
+	if err != nil {
 		return nil, ErrSwarmNotFound
 	}
 
-	var status struct {
-		Ready []struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-		} `json:"ready"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
-		return nil, fmt.Errorf("parsing status: %w", err)
-	}
-
 	if len(status.Ready) == 0 {
 		return nil, ErrNoReadyTasks
 	}
@@ -164,25 +135,11 @@ func (m *Manager) GetReadyTasks(swarmID string) ([]SwarmTask, error) {
 
 // IsComplete checks if all tasks are closed by querying beads.
 func (m *Manager) IsComplete(swarmID string) (bool, error) {
-	cmd := exec.Command("bd", "swarm", "status", swarmID, "--json")
-	cmd.Dir = m.beadsDir
-
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	status, err := m.bd().SwarmStatus(swarmID)
+	if err != nil {
 		return false, ErrSwarmNotFound
 	}
 
-	var status struct {
-		Ready   []struct{ ID string } `json:"ready"`
-		Active  []struct{ ID string } `json:"active"`
-		Blocked []struct{ ID string } `json:"blocked"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
-		return false, fmt.Errorf("parsing status: %w", err)
-	}
-
 	// Complete if nothing is ready, active, or blocked
 	return len(status.Ready) == 0 && len(status.Active) == 0 && len(status.Blocked) == 0, nil
 }
@@ -213,44 +170,16 @@ func isValidTransition(from, to SwarmState) bool {
 
 // loadTasksFromBeads loads child issues from beads CLI.
 func (m *Manager) loadTasksFromBeads(epicID string) ([]SwarmTask, error) {
-	// Run: bd show <epicID> --json to get epic with children
-	cmd := exec.Command("bd", "show", epicID, "--json")
-	cmd.Dir = m.beadsDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("bd show: %s", strings.TrimSpace(stderr.String()))
-	}
-
-	// Parse JSON output - bd show returns an array
-	var issues []struct {
-		ID         string `json:"id"`
-		Title      string `json:"title"`
-		Status     string `json:"status"`
-		Dependents []struct {
-			ID             string `json:"id"`
-			Title          string `json:"title"`
-			Status         string `json:"status"`
-			Assignee       string `json:"assignee"`
-			DependencyType string `json:"dependency_type"`
-		} `json:"dependents"`
-	}
-
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd output: %w", err)
-	}
-
-	if len(issues) == 0 {
-		return nil, fmt.Errorf("epic not found: %s", epicID)
+	// The epic's dependents are its tasks
+	epic, err := m.bd().Show(epicID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract dependents as tasks (issues that depend on/are blocked by this epic)
 	// Accept both "parent-child" and "blocks" relationships
 	var tasks []SwarmTask
-	for _, dep := range issues[0].Dependents {
+	for _, dep := range epic.Dependents {
 		if dep.DependencyType != "parent-child" && dep.DependencyType != "blocks" {
 			continue
 		}
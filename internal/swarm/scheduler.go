@@ -0,0 +1,76 @@
+package swarm
+
+// Limits caps how many workers a swarm may keep busy at once. Zero means
+// no cap.
+type Limits struct {
+	// MaxPerSwarm caps in-flight tasks for this swarm.
+	MaxPerSwarm int `json:"max_per_swarm,omitempty"`
+
+	// MaxPerRig caps working polecats in the swarm's rig, counting polecats
+	// busy with work outside the swarm.
+	MaxPerRig int `json:"max_per_rig,omitempty"`
+}
+
+// InFlight counts tasks that have been handed to a worker but aren't done.
+func (p *WavePlan) InFlight() int {
+	n := 0
+	for _, t := range p.tasks {
+		if t.State.IsComplete() {
+			continue
+		}
+		if t.Assignee != "" || t.State != TaskPending {
+			n++
+		}
+	}
+	return n
+}
+
+// SelectDispatch returns the ready tasks to dispatch now without exceeding
+// either cap. rigWorking is the number of polecats currently working in the
+// swarm's rig. Critical-path tasks are chosen first.
+func SelectDispatch(plan *WavePlan, rigWorking int, limits Limits) []SwarmTask {
+	ready := plan.Ready()
+	slots := len(ready)
+	if limits.MaxPerSwarm > 0 {
+		slots = min(slots, limits.MaxPerSwarm-plan.InFlight())
+	}
+	if limits.MaxPerRig > 0 {
+		slots = min(slots, limits.MaxPerRig-rigWorking)
+	}
+	if slots <= 0 {
+		return nil
+	}
+	return ready[:slots]
+}
+
+// IsFinished reports whether no further dispatch can happen: every task is
+// complete, or the only unfinished tasks are blocked behind failed ones.
+func (p *WavePlan) IsFinished() bool {
+	for _, t := range p.tasks {
+		if t.State.IsComplete() {
+			continue
+		}
+		if t.Assignee != "" || t.State != TaskPending {
+			return false // Still being worked
+		}
+		if !p.blockedByFailure(t.IssueID, map[string]bool{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// blockedByFailure reports whether a task can never become ready because a
+// task it (transitively) depends on failed.
+func (p *WavePlan) blockedByFailure(id string, seen map[string]bool) bool {
+	if seen[id] {
+		return false
+	}
+	seen[id] = true
+	for _, dep := range p.graph.Deps(id) {
+		if p.tasks[dep].State == TaskFailed || p.blockedByFailure(dep, seen) {
+			return true
+		}
+	}
+	return false
+}
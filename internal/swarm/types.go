@@ -94,6 +94,9 @@ type SwarmTask struct {
 	// State mirrors the beads issue status.
 	State TaskState `json:"state"`
 
+	// DependsOn lists the issues this task is blocked by.
+	DependsOn []string `json:"depends_on,omitempty"`
+
 	// MergedAt is when the task branch was merged (if merged).
 	MergedAt *time.Time `json:"merged_at,omitempty"`
 }
@@ -0,0 +1,148 @@
+package swarm
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/depgraph"
+)
+
+// WavePlan groups a swarm's tasks into dependency waves. Wave 0 holds tasks
+// with no dependencies inside the swarm; every task in wave n depends only
+// on tasks in earlier waves, so a wave can run fully in parallel once the
+// waves before it have merged.
+type WavePlan struct {
+	// Waves lists task IDs per wave, in wave order.
+	Waves [][]string `json:"waves"`
+
+	// CriticalPath is the longest chain of unfinished tasks, first to last.
+	// Its length bounds how many more dispatch rounds the swarm needs.
+	CriticalPath depgraph.Path `json:"critical_path"`
+
+	graph *depgraph.Graph
+	tasks map[string]SwarmTask // Task ID → task
+	chain map[string]int       // Task ID → unfinished tasks on its longest downstream chain
+}
+
+// PlanWaves builds the wave plan for a swarm's tasks from their DependsOn
+// edges. Dependencies on issues outside the swarm are ignored. A
+// dependency cycle is an error naming the tasks involved.
+func PlanWaves(tasks []SwarmTask) (*WavePlan, error) {
+	plan := &WavePlan{tasks: make(map[string]SwarmTask, len(tasks))}
+	ids := make([]string, len(tasks))
+	for i, t := range tasks {
+		plan.tasks[t.IssueID] = t
+		ids[i] = t.IssueID
+	}
+
+	g, err := depgraph.New(ids, func(id string) []string { return plan.tasks[id].DependsOn })
+	if err != nil {
+		return nil, err
+	}
+	plan.graph, plan.Waves = g, g.Layers
+
+	// Merged work still links a chain but adds no dispatch round
+	path, chain := depgraph.CriticalPath(g, func(id string) int {
+		if plan.tasks[id].State.IsComplete() {
+			return 0
+		}
+		return 1
+	})
+	plan.chain = chain
+	for _, id := range path {
+		if !plan.tasks[id].State.IsComplete() {
+			plan.CriticalPath = append(plan.CriticalPath, id)
+		}
+	}
+	return plan, nil
+}
+
+// WaveOf returns the wave a task belongs to, or -1 if it isn't planned.
+func (p *WavePlan) WaveOf(id string) int {
+	return p.graph.LayerOf(id)
+}
+
+// ChainLength returns how many unfinished tasks lie on the longest chain
+// starting at a task (including the task itself if unfinished).
+func (p *WavePlan) ChainLength(id string) int {
+	return p.chain[id]
+}
+
+// BlockedBy returns the unfinished swarm tasks a task is waiting on.
+func (p *WavePlan) BlockedBy(id string) []string {
+	var blockers []string
+	for _, dep := range p.graph.Deps(id) {
+		if p.tasks[dep].State != TaskMerged {
+			blockers = append(blockers, dep)
+		}
+	}
+	return blockers
+}
+
+// Ready returns tasks that can be dispatched now: pending, unassigned, and
+// with every swarm dependency merged. Tasks on longer remaining chains come
+// first so the critical path is never starved by a worker cap.
+func (p *WavePlan) Ready() []SwarmTask {
+	var ready []SwarmTask
+	for _, wave := range p.Waves {
+		for _, id := range wave {
+			t := p.tasks[id]
+			if t.State != TaskPending || t.Assignee != "" || len(p.BlockedBy(id)) > 0 {
+				continue
+			}
+			ready = append(ready, t)
+		}
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return p.chain[ready[i].IssueID] > p.chain[ready[j].IssueID]
+	})
+	return ready
+}
+
+// CurrentWave returns the lowest wave with unfinished tasks, or -1 when
+// every task is complete.
+func (p *WavePlan) CurrentWave() int {
+	for w, wave := range p.Waves {
+		for _, id := range wave {
+			if !p.tasks[id].State.IsComplete() {
+				return w
+			}
+		}
+	}
+	return -1
+}
+
+// LoadTaskDependencies fills in each task's DependsOn with the blocking
+// dependencies recorded in beads.
+func (m *Manager) LoadTaskDependencies(sw *Swarm) error {
+	if len(sw.Tasks) == 0 {
+		return nil
+	}
+	ids := make([]string, len(sw.Tasks))
+	for i, t := range sw.Tasks {
+		ids[i] = t.IssueID
+	}
+
+	issues, err := beads.New(m.beadsDir).ShowMultiple(ids)
+	if err != nil {
+		return fmt.Errorf("loading task dependencies: %w", err)
+	}
+	for i := range sw.Tasks {
+		issue := issues[sw.Tasks[i].IssueID]
+		if issue == nil {
+			continue
+		}
+		var deps []string
+		for _, dep := range issue.Dependencies {
+			if dep.DependencyType == "" || dep.DependencyType == "blocks" {
+				deps = appendUnique(deps, dep.ID)
+			}
+		}
+		for _, dep := range issue.DependsOn {
+			deps = appendUnique(deps, dep)
+		}
+		sw.Tasks[i].DependsOn = deps
+	}
+	return nil
+}
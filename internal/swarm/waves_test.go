@@ -0,0 +1,140 @@
+package swarm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/depgraph"
+)
+
+// diamond: a → b, a → c, b → d, c → d, plus an independent e.
+func diamondTasks() []SwarmTask {
+	return []SwarmTask{
+		{IssueID: "d", State: TaskPending, DependsOn: []string{"b", "c"}},
+		{IssueID: "b", State: TaskPending, DependsOn: []string{"a"}},
+		{IssueID: "c", State: TaskPending, DependsOn: []string{"a", "outside-epic"}},
+		{IssueID: "a", State: TaskPending},
+		{IssueID: "e", State: TaskPending},
+	}
+}
+
+func TestPlanWaves(t *testing.T) {
+	plan, err := PlanWaves(diamondTasks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"a", "e"}, {"b", "c"}, {"d"}}
+	if !reflect.DeepEqual(plan.Waves, want) {
+		t.Errorf("Waves = %v, want %v", plan.Waves, want)
+	}
+	if w := plan.WaveOf("d"); w != 2 {
+		t.Errorf("WaveOf(d) = %d, want 2", w)
+	}
+	if !reflect.DeepEqual(plan.CriticalPath, depgraph.Path{"a", "b", "d"}) {
+		t.Errorf("CriticalPath = %v", plan.CriticalPath)
+	}
+	if got := plan.BlockedBy("d"); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("BlockedBy(d) = %v", got)
+	}
+
+	// a is on a 3-long chain, e stands alone: a goes first
+	ready := plan.Ready()
+	if len(ready) != 2 || ready[0].IssueID != "a" || ready[1].IssueID != "e" {
+		t.Errorf("Ready = %v", ready)
+	}
+}
+
+func TestPlanWaves_Progress(t *testing.T) {
+	tasks := diamondTasks()
+	for i := range tasks {
+		switch tasks[i].IssueID {
+		case "a", "e":
+			tasks[i].State = TaskMerged
+		case "b":
+			tasks[i].State = TaskInProgress
+			tasks[i].Assignee = "Toast"
+		}
+	}
+	plan, err := PlanWaves(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := plan.CurrentWave(); w != 1 {
+		t.Errorf("CurrentWave = %d, want 1", w)
+	}
+	ready := plan.Ready()
+	if len(ready) != 1 || ready[0].IssueID != "c" {
+		t.Errorf("Ready = %v, want [c]", ready)
+	}
+	if n := plan.InFlight(); n != 1 {
+		t.Errorf("InFlight = %d, want 1", n)
+	}
+	// Merged tasks drop off the critical path
+	if !reflect.DeepEqual(plan.CriticalPath, depgraph.Path{"b", "d"}) {
+		t.Errorf("CriticalPath = %v", plan.CriticalPath)
+	}
+}
+
+func TestPlanWaves_Cycle(t *testing.T) {
+	_, err := PlanWaves([]SwarmTask{
+		{IssueID: "x", DependsOn: []string{"y"}},
+		{IssueID: "y", DependsOn: []string{"x"}},
+		{IssueID: "z"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "x, y") {
+		t.Errorf("expected cycle error naming x and y, got %v", err)
+	}
+}
+
+func TestSelectDispatch(t *testing.T) {
+	tasks := []SwarmTask{
+		{IssueID: "a", State: TaskPending},
+		{IssueID: "b", State: TaskPending},
+		{IssueID: "c", State: TaskPending},
+		{IssueID: "d", State: TaskInProgress, Assignee: "Nux"},
+	}
+	plan, err := PlanWaves(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		limits     Limits
+		rigWorking int
+		want       int
+	}{
+		{"uncapped", Limits{}, 0, 3},
+		{"swarm cap counts in-flight", Limits{MaxPerSwarm: 3}, 0, 2},
+		{"rig cap counts other work", Limits{MaxPerRig: 4}, 3, 1},
+		{"tightest cap wins", Limits{MaxPerSwarm: 10, MaxPerRig: 2}, 0, 2},
+		{"rig full", Limits{MaxPerRig: 2}, 5, 0},
+	}
+	for _, tt := range tests {
+		if got := SelectDispatch(plan, tt.rigWorking, tt.limits); len(got) != tt.want {
+			t.Errorf("%s: dispatched %d, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestIsFinished(t *testing.T) {
+	plan, _ := PlanWaves([]SwarmTask{
+		{IssueID: "a", State: TaskFailed},
+		{IssueID: "b", State: TaskPending, DependsOn: []string{"a"}},
+		{IssueID: "c", State: TaskMerged},
+	})
+	if !plan.IsFinished() {
+		t.Error("expected swarm blocked only by a failure to be finished")
+	}
+
+	plan, _ = PlanWaves([]SwarmTask{
+		{IssueID: "a", State: TaskMerged},
+		{IssueID: "b", State: TaskPending, DependsOn: []string{"a"}},
+	})
+	if plan.IsFinished() {
+		t.Error("expected swarm with a ready task to be unfinished")
+	}
+}
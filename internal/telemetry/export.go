@@ -0,0 +1,322 @@
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// exportTimeout bounds a collector request.
+const exportTimeout = 2 * time.Second
+
+// Ended spans wait in a queue and a background goroutine exports them in
+// batches, so a slow collector never holds up the work being traced.
+// Flush sends what's left when gt exits.
+const (
+	queueSize     = 1024 // Spans beyond this are dropped
+	batchSize     = 128
+	batchInterval = time.Second
+	flushTimeout  = 3 * time.Second
+)
+
+// exporter ships finished spans. Failures are dropped: tracing must never
+// break the work it observes.
+type exporter interface {
+	export(body []byte)
+}
+
+// fileExporter appends one OTLP/JSON request per line, the format the
+// collector's otlpjsonfile receiver reads.
+type fileExporter struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (e *fileExporter) export(body []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(e.path), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(e.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.Write(append(body, '\n'))
+}
+
+// httpExporter posts to an OTLP/HTTP traces endpoint with JSON encoding.
+type httpExporter struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPExporter(url string) *httpExporter {
+	return &httpExporter{url: url, client: &http.Client{Timeout: exportTimeout}}
+}
+
+func (e *httpExporter) export(body []byte) {
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+var (
+	queueOnce sync.Once
+	queue     chan otlpSpan
+	flushes   chan chan struct{}
+)
+
+// export queues s for the background exporter.
+func export(s *Span) {
+	if exp == nil {
+		return
+	}
+	queueOnce.Do(startExporter)
+	select {
+	case queue <- s.otlp():
+	default:
+	}
+}
+
+// Flush exports the spans still queued, waiting at most a few seconds.
+// Call it before the process exits.
+func Flush() {
+	if queue == nil {
+		return
+	}
+	done := make(chan struct{})
+	select {
+	case flushes <- done:
+	case <-time.After(flushTimeout):
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(flushTimeout):
+	}
+}
+
+func startExporter() {
+	queue = make(chan otlpSpan, queueSize)
+	flushes = make(chan chan struct{})
+	go runExporter()
+}
+
+// runExporter batches queued spans into one request per batchInterval,
+// or sooner once batchSize spans are waiting.
+func runExporter() {
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+	var batch []otlpSpan
+	send := func() {
+		if len(batch) > 0 {
+			exportBatch(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case s := <-queue:
+			if batch = append(batch, s); len(batch) >= batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case done := <-flushes:
+			for drained := false; !drained; {
+				select {
+				case s := <-queue:
+					batch = append(batch, s)
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(done)
+		}
+	}
+}
+
+func exportBatch(spans []otlpSpan) {
+	if exp == nil {
+		return
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resourceAttrs()},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "gastown"}, Spans: spans}},
+	}}})
+	if err != nil {
+		return
+	}
+	exp.export(body)
+}
+
+// resourceAttrs describe the process: which agent, in which rig.
+func resourceAttrs() []otlpKeyValue {
+	attrs := []Attr{
+		String("service.name", "gastown"),
+		Int("process.pid", os.Getpid()),
+	}
+	if len(os.Args) > 1 {
+		attrs = append(attrs, String("process.command", "gt "+os.Args[1]))
+	}
+	for key, env := range map[string]string{"gt.role": "GT_ROLE", "gt.rig": "GT_RIG", "gt.actor": "BD_ACTOR"} {
+		if v := os.Getenv(env); v != "" {
+			attrs = append(attrs, String(key, v))
+		}
+	}
+	return otlpAttrs(attrs)
+}
+
+// OTLP/JSON encoding of ExportTraceServiceRequest. IDs are hex and
+// 64-bit integers are strings, per the OTLP JSON mapping.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"` // 1 ok, 2 error
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	statusOK         = 1
+	statusError      = 2
+)
+
+func (s *Span) otlp() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := otlpSpan{
+		TraceID:           hex.EncodeToString(s.sc.TraceID[:]),
+		SpanID:            hex.EncodeToString(s.sc.SpanID[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        otlpAttrs(s.attrs),
+		Status:            otlpStatus{Code: statusOK},
+	}
+	if s.parent != [8]byte{} {
+		out.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	if s.err != "" {
+		out.Status = otlpStatus{Code: statusError, Message: s.err}
+	}
+	return out
+}
+
+func otlpAttrs(attrs []Attr) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch x := a.Value.(type) {
+		case string:
+			v.StringValue = &x
+		case int:
+			s := strconv.Itoa(x)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(x, 10)
+			v.IntValue = &s
+		case bool:
+			v.BoolValue = &x
+		default:
+			continue
+		}
+		out = append(out, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return out
+}
+
+// recordBdCall turns a bd call whose context carries a span into a child
+// span, so slow bd calls show up in the work's trace.
+func recordBdCall(call beads.Call) {
+	parent := SpanFromContext(call.Context)
+	if parent == nil {
+		return
+	}
+	name := "bd"
+	for _, arg := range call.Args {
+		if !strings.HasPrefix(arg, "-") {
+			name += " " + arg
+			break
+		}
+	}
+	args := strings.Join(call.Args, " ")
+	if len(args) > 200 {
+		args = args[:200] + "..."
+	}
+	s := &Span{
+		name:   name,
+		start:  call.Start,
+		end:    call.Start.Add(call.Duration),
+		parent: parent.sc.SpanID,
+		attrs: []Attr{
+			String("bd.args", args),
+			Int("bd.exit_code", call.ExitCode),
+			Int("bd.attempts", call.Attempts),
+		},
+		err:   call.Error,
+		ended: true,
+	}
+	s.sc.TraceID = parent.sc.TraceID
+	_, _ = rand.Read(s.sc.SpanID[:])
+	export(s)
+}
@@ -0,0 +1,300 @@
+// Package telemetry traces a unit of work across the gt processes that
+// handle it: gt sling, the polecat spawn, gt prime, molecule steps, gt done,
+// and the refinery's gates and merge. One trace view then shows where a
+// bead's time went.
+//
+// Spans are exported as OTLP/JSON in the background, to a collector over
+// HTTP or appended to a file; gt calls Flush before it exits. A bd call
+// becomes a child span when its context carries a span (see
+// beads.Beads.WithContext). The W3C traceparent of the work travels
+// between processes in GT_TRACE_PARENT and on beads (the trace_parent
+// field), since most hops are separate gt invocations in separate sessions.
+//
+// Tracing is off unless an exporter is configured, by environment
+// (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,
+// GT_TRACE_FILE) or by the "tracing" block of settings/config.json. When
+// off, Start returns nil spans, and every Span method accepts nil.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Environment variables read by the tracer.
+const (
+	// ParentEnv carries the traceparent of the work an agent was spawned for.
+	ParentEnv = "GT_TRACE_PARENT"
+
+	// FileEnv appends spans to a file, one OTLP/JSON request per line.
+	FileEnv = "GT_TRACE_FILE"
+
+	// EndpointEnv and TracesEndpointEnv are the standard OTLP/HTTP exporter
+	// settings: a collector base URL, or the full traces URL.
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+)
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// IsValid reports whether both IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// TraceParent formats sc as a W3C traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%x-%x-01", sc.TraceID, sc.SpanID)
+}
+
+// ParseTraceParent parses a W3C traceparent ("00-<trace>-<span>-<flags>").
+func ParseTraceParent(s string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	return sc, sc.IsValid()
+}
+
+// Attr is a span attribute. Values are strings, ints, int64s or bools.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{key, value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr { return Attr{key, value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// Span is one timed operation. A nil *Span is valid and does nothing.
+type Span struct {
+	mu     sync.Mutex
+	sc     SpanContext
+	parent [8]byte
+	name   string
+	start  time.Time
+	end    time.Time
+	attrs  []Attr
+	err    string
+	ended  bool
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns ctx carrying span as the parent of later spans.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span in ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start begins a span under the span in ctx, or else under GT_TRACE_PARENT,
+// or else as the root of a new trace. It returns ctx carrying the span.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span := newSpan(name, parent.sc, attrs)
+		return ContextWithSpan(ctx, span), span
+	}
+	span := StartRemote(os.Getenv(ParentEnv), name, attrs...)
+	return ContextWithSpan(ctx, span), span
+}
+
+// StartRemote begins a span under a traceparent recorded elsewhere (on a
+// bead, or in another process's environment). An empty or invalid
+// traceparent starts a new trace.
+func StartRemote(traceParent, name string, attrs ...Attr) *Span {
+	if !Enabled() {
+		return nil
+	}
+	parent, _ := ParseTraceParent(traceParent)
+	return newSpan(name, parent, attrs)
+}
+
+// StartRoot begins a new trace, ignoring any inherited parent. gt sling
+// uses it: each slung bead is its own unit of work.
+func StartRoot(name string, attrs ...Attr) *Span {
+	return StartRemote("", name, attrs...)
+}
+
+func newSpan(name string, parent SpanContext, attrs []Attr) *Span {
+	s := &Span{name: name, start: time.Now(), attrs: attrs}
+	if parent.IsValid() {
+		s.sc.TraceID = parent.TraceID
+		s.parent = parent.SpanID
+	} else {
+		_, _ = rand.Read(s.sc.TraceID[:])
+	}
+	_, _ = rand.Read(s.sc.SpanID[:])
+	return s
+}
+
+// TraceParent returns the span's traceparent for recording on a bead or
+// passing to another process, or "" for a nil span.
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return s.sc.TraceParent()
+}
+
+// TraceID returns the span's trace ID in hex, or "".
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.sc.TraceID[:])
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetStart moves the span's start back, for operations that began before
+// the process recording them (e.g. a molecule step, timed from when it
+// was claimed).
+func (s *Span) SetStart(t time.Time) {
+	if s == nil || t.IsZero() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Before(s.start) {
+		s.start = t
+	}
+}
+
+// End finishes the span, marking it failed if err is non-nil, and queues
+// it for export. Later calls do nothing.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	if err != nil {
+		s.err = err.Error()
+	}
+	s.mu.Unlock()
+	export(s)
+}
+
+// Env returns the environment an agent spawned for the work traced by
+// traceParent needs: GT_TRACE_PARENT, plus any exporter settings gt got
+// from its own environment.
+func Env(traceParent string) map[string]string {
+	env := make(map[string]string)
+	if traceParent == "" || !Enabled() {
+		return env
+	}
+	env[ParentEnv] = traceParent
+	for _, key := range []string{FileEnv, EndpointEnv, TracesEndpointEnv} {
+		if v := os.Getenv(key); v != "" {
+			env[key] = v
+		}
+	}
+	return env
+}
+
+var (
+	setupOnce sync.Once
+	exp       exporter
+)
+
+// Enabled reports whether spans are exported.
+func Enabled() bool {
+	setupOnce.Do(func() {
+		exp = exporterFromConfig()
+		if exp != nil {
+			beads.OnCall(recordBdCall)
+		}
+	})
+	return exp != nil
+}
+
+// exporterFromConfig picks the exporter: environment first, then the
+// town's settings.
+func exporterFromConfig() exporter {
+	if url := os.Getenv(TracesEndpointEnv); url != "" {
+		return newHTTPExporter(url)
+	}
+	if base := os.Getenv(EndpointEnv); base != "" {
+		return newHTTPExporter(strings.TrimRight(base, "/") + "/v1/traces")
+	}
+	if path := os.Getenv(FileEnv); path != "" {
+		return &fileExporter{path: path}
+	}
+
+	townRoot := os.Getenv("GT_ROOT")
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	}
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.Tracing == nil {
+		return nil
+	}
+	switch {
+	case settings.Tracing.Endpoint != "":
+		return newHTTPExporter(strings.TrimRight(settings.Tracing.Endpoint, "/") + "/v1/traces")
+	case settings.Tracing.File != "":
+		path := settings.Tracing.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		return &fileExporter{path: path}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// exportToFile sends spans to a temp file for the test and returns a
+// reader that flushes and returns the exported spans, in export order.
+func exportToFile(t *testing.T) func() []otlpSpan {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	setupOnce.Do(func() {})
+	exp = &fileExporter{path: path}
+	t.Cleanup(func() { exp = nil })
+
+	return func() []otlpSpan {
+		Flush()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spans []otlpSpan
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var req otlpRequest
+			if err := json.Unmarshal([]byte(line), &req); err != nil {
+				t.Fatalf("bad OTLP line %q: %v", line, err)
+			}
+			for _, rs := range req.ResourceSpans {
+				for _, ss := range rs.ScopeSpans {
+					spans = append(spans, ss.Spans...)
+				}
+			}
+		}
+		return spans
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceParent(tp)
+	if !ok || sc.TraceParent() != tp {
+		t.Errorf("ParseTraceParent(%q) = %v, %v", tp, sc.TraceParent(), ok)
+	}
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceParent(bad); ok {
+			t.Errorf("ParseTraceParent(%q) accepted", bad)
+		}
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	var s *Span
+	s.SetAttributes(String("k", "v"))
+	s.SetStart(time.Now())
+	s.End(errors.New("ignored"))
+	if s.TraceParent() != "" || s.TraceID() != "" {
+		t.Error("nil span has IDs")
+	}
+	ctx := ContextWithSpan(context.Background(), s)
+	if SpanFromContext(ctx) != nil {
+		t.Error("nil span stored in context")
+	}
+}
+
+func TestExport_TraceAcrossProcesses(t *testing.T) {
+	spans := exportToFile(t)
+
+	root := StartRoot("sling", String("gt.bead", "gt-1"))
+	ctx := ContextWithSpan(context.Background(), root)
+	_, child := Start(ctx, "gate tests", Int("n", 3), Bool("cached", true))
+	child.End(errors.New("tests failed"))
+
+	// Another process continues the trace from the bead's trace_parent
+	remote := StartRemote(root.TraceParent(), "polecat.done")
+	remote.SetStart(time.Now().Add(-time.Minute))
+	remote.End(nil)
+	root.End(nil)
+
+	got := spans()
+	if len(got) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(got))
+	}
+	byName := make(map[string]otlpSpan)
+	for _, s := range got {
+		byName[s.Name] = s
+		if s.TraceID != root.TraceID() {
+			t.Errorf("%s in trace %s, want %s", s.Name, s.TraceID, root.TraceID())
+		}
+	}
+	rootID := byName["sling"].SpanID
+	if byName["sling"].ParentSpanID != "" {
+		t.Error("root span has a parent")
+	}
+	if byName["gate tests"].ParentSpanID != rootID || byName["polecat.done"].ParentSpanID != rootID {
+		t.Errorf("children not parented to root %s: %+v", rootID, got)
+	}
+
+	gate := byName["gate tests"]
+	if gate.Status.Code != statusError || gate.Status.Message != "tests failed" {
+		t.Errorf("gate status = %+v", gate.Status)
+	}
+	if v := gate.Attributes[0].Value.IntValue; v == nil || *v != "3" {
+		t.Errorf("int attribute = %+v", gate.Attributes[0])
+	}
+	done := byName["polecat.done"]
+	if done.StartTimeUnixNano >= done.EndTimeUnixNano || len(done.StartTimeUnixNano) != len(done.EndTimeUnixNano) {
+		t.Errorf("SetStart not applied: %s..%s", done.StartTimeUnixNano, done.EndTimeUnixNano)
+	}
+}
+
+func TestRecordBdCall(t *testing.T) {
+	spans := exportToFile(t)
+
+	// Outside any span, bd calls aren't traced
+	recordBdCall(beads.Call{Context: context.Background(), Args: []string{"list"}})
+
+	// A span open elsewhere (another goroutine's work) doesn't adopt calls
+	// whose context doesn't carry it
+	span := StartRoot("polecat.done")
+	recordBdCall(beads.Call{Context: context.Background(), Args: []string{"show", "gt-2"}})
+
+	recordBdCall(beads.Call{
+		Context:  ContextWithSpan(context.Background(), span),
+		Args:     []string{"--no-daemon", "close", "gt-1"},
+		Start:    time.Now(),
+		Duration: 20 * time.Millisecond,
+		Attempts: 2,
+		ExitCode: 1,
+		Error:    "exit status 1",
+	})
+	span.End(nil)
+
+	got := spans()
+	if len(got) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(got))
+	}
+	bd := got[0]
+	if bd.Name != "bd close" || bd.ParentSpanID != got[1].SpanID || bd.Status.Code != statusError {
+		t.Errorf("bd span = %+v", bd)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	exportToFile(t)
+	t.Setenv(FileEnv, "/tmp/spans.jsonl")
+
+	if env := Env(""); len(env) != 0 {
+		t.Errorf("Env(\"\") = %v", env)
+	}
+	tp := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	env := Env(tp)
+	if env[ParentEnv] != tp || env[FileEnv] != "/tmp/spans.jsonl" {
+		t.Errorf("Env = %v", env)
+	}
+}
+
+func TestExport_Batches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	setupOnce.Do(func() {})
+	exp = &fileExporter{path: path}
+	t.Cleanup(func() { exp = nil })
+
+	for i := 0; i < 5; i++ {
+		StartRoot("step").End(nil)
+	}
+	Flush()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	spans := 0
+	for _, line := range lines {
+		var req otlpRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			t.Fatalf("bad OTLP line %q: %v", line, err)
+		}
+		spans += len(req.ResourceSpans[0].ScopeSpans[0].Spans)
+	}
+	if spans != 5 || len(lines) >= spans {
+		t.Errorf("exported %d spans in %d requests, want 5 batched", spans, len(lines))
+	}
+}
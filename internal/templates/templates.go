@@ -139,11 +139,22 @@ func (t *Templates) MessageNames() []string {
 // CreateMayorCLAUDEmd creates the Mayor's CLAUDE.md file at the specified directory.
 // This is used by both gt install and gt doctor --fix.
 func CreateMayorCLAUDEmd(mayorDir, townRoot, townName, mayorSession, deaconSession string) error {
-	tmpl, err := New()
+	content, err := RenderMayorCLAUDEmd(mayorDir, townRoot, townName, mayorSession, deaconSession)
 	if err != nil {
 		return err
 	}
 
+	claudePath := filepath.Join(mayorDir, "CLAUDE.md")
+	return os.WriteFile(claudePath, []byte(content), 0644)
+}
+
+// RenderMayorCLAUDEmd renders the Mayor's CLAUDE.md without writing it.
+func RenderMayorCLAUDEmd(mayorDir, townRoot, townName, mayorSession, deaconSession string) (string, error) {
+	tmpl, err := New()
+	if err != nil {
+		return "", err
+	}
+
 	data := RoleData{
 		Role:          "mayor",
 		TownRoot:      townRoot,
@@ -153,13 +164,7 @@ func CreateMayorCLAUDEmd(mayorDir, townRoot, townName, mayorSession, deaconSessi
 		DeaconSession: deaconSession,
 	}
 
-	content, err := tmpl.RenderRole("mayor", data)
-	if err != nil {
-		return err
-	}
-
-	claudePath := filepath.Join(mayorDir, "CLAUDE.md")
-	return os.WriteFile(claudePath, []byte(content), 0644)
+	return tmpl.RenderRole("mayor", data)
 }
 
 // GetAllRoleTemplates returns all role templates as a map of filename to content.
@@ -0,0 +1,316 @@
+// Package testreport parses test runner output (go test -json, JUnit XML,
+// TAP) into per-test results, so the refinery can rerun only failing tests
+// and track each test's history.
+package testreport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a test report format.
+type Format string
+
+const (
+	FormatGoJSON Format = "go-json" // go test -json
+	FormatJUnit  Format = "junit"   // JUnit XML
+	FormatTAP    Format = "tap"     // Test Anything Protocol
+)
+
+// Status is a test outcome.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	Skip Status = "skip"
+)
+
+// maxOutput bounds the output kept per test.
+const maxOutput = 2000
+
+// Result is one test's outcome.
+type Result struct {
+	// Suite is the package (go), class or suite name; may be empty.
+	Suite string `json:"suite,omitempty"`
+
+	// Name is the test name. Empty for a suite-level failure with no
+	// failing test (e.g. a go package that doesn't build).
+	Name string `json:"name,omitempty"`
+
+	Status  Status        `json:"status"`
+	Elapsed time.Duration `json:"elapsed,omitempty"`
+	Output  string        `json:"output,omitempty"`
+}
+
+// ID identifies the test across runs: "<suite>.<name>", or whichever is set.
+func (r Result) ID() string {
+	switch {
+	case r.Suite == "":
+		return r.Name
+	case r.Name == "":
+		return r.Suite
+	default:
+		return r.Suite + "." + r.Name
+	}
+}
+
+// Failed returns the failing results.
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Status == Fail {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// ParseFormat validates a format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatGoJSON, FormatJUnit, FormatTAP:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown test report format %q (want go-json, junit or tap)", s)
+}
+
+// Detect guesses the format of test output, or returns "" if unknown.
+func Detect(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<?xml")), bytes.HasPrefix(trimmed, []byte("<testsuite")):
+		return FormatJUnit
+	case bytes.HasPrefix(trimmed, []byte("TAP version")), tapPlanRe.Match(trimmed):
+		return FormatTAP
+	}
+	for _, line := range bytes.SplitN(trimmed, []byte("\n"), 20) {
+		if bytes.HasPrefix(line, []byte(`{"Time":`)) || bytes.HasPrefix(line, []byte(`{"Action":`)) {
+			return FormatGoJSON
+		}
+	}
+	return ""
+}
+
+// Parse parses data in the given format.
+func Parse(format Format, data []byte) ([]Result, error) {
+	switch format {
+	case FormatGoJSON:
+		return ParseGoJSON(data)
+	case FormatJUnit:
+		return ParseJUnit(data)
+	case FormatTAP:
+		return ParseTAP(data)
+	}
+	return nil, fmt.Errorf("unknown test report format %q", format)
+}
+
+// goEvent is one line of go test -json output.
+type goEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+// ParseGoJSON parses go test -json output. Non-JSON lines (e.g. build
+// errors on a merged stderr) are ignored. A package that fails without a
+// failing test is reported as a suite-level failure.
+func ParseGoJSON(data []byte) ([]Result, error) {
+	type key struct{ pkg, test string }
+	outputs := make(map[key]*strings.Builder)
+	var results []Result
+	failedTests := make(map[string]bool) // package -> has a failing test
+	var pkgFails []Result
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	parsed := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goEvent
+		if json.Unmarshal(line, &ev) != nil || ev.Action == "" {
+			continue
+		}
+		parsed++
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			b := outputs[k]
+			if b == nil {
+				b = &strings.Builder{}
+				outputs[k] = b
+			}
+			b.WriteString(ev.Output)
+		case "pass", "fail", "skip":
+			status := Status(ev.Action)
+			r := Result{Suite: ev.Package, Name: ev.Test, Status: status, Elapsed: time.Duration(ev.Elapsed * float64(time.Second))}
+			if status == Fail {
+				if b := outputs[k]; b != nil {
+					r.Output = tail(b.String())
+				}
+			}
+			delete(outputs, k)
+			if ev.Test == "" {
+				if status == Fail {
+					pkgFails = append(pkgFails, r)
+				}
+				continue
+			}
+			if status == Fail {
+				failedTests[ev.Package] = true
+			}
+			results = append(results, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading go test -json output: %w", err)
+	}
+	if parsed == 0 {
+		return nil, fmt.Errorf("no go test -json events found")
+	}
+	for _, r := range pkgFails {
+		if !failedTests[r.Suite] {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Cases  []junitCase  `xml:"testcase"`
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure"`
+	Error     *junitProblem `xml:"error"`
+	Skipped   *struct{}     `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// ParseJUnit parses JUnit XML (a <testsuites> or <testsuite> root).
+func ParseJUnit(data []byte) ([]Result, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing JUnit XML: %w", err)
+	}
+	var results []Result
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			suite := c.Classname
+			if suite == "" {
+				suite = s.Name
+			}
+			r := Result{Suite: suite, Name: c.Name, Status: Pass}
+			if secs, err := strconv.ParseFloat(c.Time, 64); err == nil {
+				r.Elapsed = time.Duration(secs * float64(time.Second))
+			}
+			switch {
+			case c.Failure != nil:
+				r.Status = Fail
+				r.Output = tail(strings.TrimSpace(c.Failure.Message + "\n" + c.Failure.Body))
+			case c.Error != nil:
+				r.Status = Fail
+				r.Output = tail(strings.TrimSpace(c.Error.Message + "\n" + c.Error.Body))
+			case c.Skipped != nil:
+				r.Status = Skip
+			}
+			results = append(results, r)
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return results, nil
+}
+
+var (
+	tapPlanRe = regexp.MustCompile(`^1\.\.\d+`)
+	tapLineRe = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+).*)?$`)
+)
+
+// ParseTAP parses TAP output. Indented subtests are ignored; a failure's
+// YAML diagnostics become its output. TODO failures don't count as failures.
+func ParseTAP(data []byte) ([]Result, error) {
+	var results []Result
+	inDiag := false
+	var diag strings.Builder
+	flushDiag := func() {
+		if n := len(results); n > 0 && results[n-1].Status == Fail && diag.Len() > 0 {
+			results[n-1].Output = tail(diag.String())
+		}
+		diag.Reset()
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inDiag {
+			if trimmed == "..." {
+				inDiag = false
+				flushDiag()
+			} else {
+				diag.WriteString(trimmed + "\n")
+			}
+			continue
+		}
+		if trimmed == "---" && len(results) > 0 {
+			inDiag = true
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue // Subtest
+		}
+		m := tapLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		r := Result{Name: m[3], Status: Pass}
+		if r.Name == "" {
+			r.Name = "test " + m[2]
+		}
+		directive := strings.ToUpper(m[4])
+		switch {
+		case directive == "SKIP":
+			r.Status = Skip
+		case m[1] == "not ok" && directive != "TODO":
+			r.Status = Fail
+		}
+		results = append(results, r)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no TAP test lines found")
+	}
+	return results, nil
+}
+
+// tail keeps the end of long output, where failures usually are.
+func tail(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= maxOutput {
+		return s
+	}
+	return "..." + s[len(s)-maxOutput:]
+}
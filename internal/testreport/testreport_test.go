@@ -0,0 +1,145 @@
+package testreport
+
+import (
+	"strings"
+	"testing"
+)
+
+const goJSON = `{"Action":"start","Package":"example.com/a"}
+{"Action":"run","Package":"example.com/a","Test":"TestOK"}
+{"Action":"pass","Package":"example.com/a","Test":"TestOK","Elapsed":0.01}
+{"Action":"run","Package":"example.com/a","Test":"TestBad"}
+{"Action":"output","Package":"example.com/a","Test":"TestBad","Output":"    a_test.go:9: boom\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestBad","Elapsed":0.02}
+{"Action":"skip","Package":"example.com/a","Test":"TestLater"}
+{"Action":"fail","Package":"example.com/a","Elapsed":0.1}
+# example.com/b
+b.go:3:1: syntax error
+{"Action":"fail","Package":"example.com/b","Elapsed":0}
+`
+
+func TestParseGoJSON(t *testing.T) {
+	if f := Detect([]byte(goJSON)); f != FormatGoJSON {
+		t.Fatalf("Detect = %q", f)
+	}
+	results, err := ParseGoJSON([]byte(goJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]Status)
+	for _, r := range results {
+		got[r.ID()] = r.Status
+	}
+	want := map[string]Status{
+		"example.com/a.TestOK":    Pass,
+		"example.com/a.TestBad":   Fail,
+		"example.com/a.TestLater": Skip,
+		"example.com/b":           Fail, // Build failure, no failing test
+	}
+	if len(got) != len(want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	for id, s := range want {
+		if got[id] != s {
+			t.Errorf("%s = %q, want %q", id, got[id], s)
+		}
+	}
+
+	failed := Failed(results)
+	if len(failed) != 2 || !strings.Contains(failed[0].Output, "boom") {
+		t.Errorf("Failed = %+v", failed)
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	data := `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="calc">
+    <testcase classname="calc.AddTest" name="adds" time="0.5"/>
+    <testcase classname="calc.AddTest" name="overflows"><failure message="expected 0">at line 12</failure></testcase>
+    <testsuite name="nested">
+      <testcase name="errors"><error message="NPE"/></testcase>
+      <testcase name="later"><skipped/></testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>`
+	if f := Detect([]byte(data)); f != FormatJUnit {
+		t.Fatalf("Detect = %q", f)
+	}
+	results, err := ParseJUnit([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		id     string
+		status Status
+	}{
+		{"calc.AddTest.adds", Pass},
+		{"calc.AddTest.overflows", Fail},
+		{"nested.errors", Fail},
+		{"nested.later", Skip},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results: %+v", len(results), results)
+	}
+	for i, w := range want {
+		if results[i].ID() != w.id || results[i].Status != w.status {
+			t.Errorf("result %d = %s %s, want %s %s", i, results[i].ID(), results[i].Status, w.id, w.status)
+		}
+	}
+	if !strings.Contains(results[1].Output, "expected 0") {
+		t.Errorf("failure output = %q", results[1].Output)
+	}
+}
+
+func TestParseTAP(t *testing.T) {
+	data := `TAP version 13
+1..5
+ok 1 - parses input
+not ok 2 - handles unicode
+  ---
+  message: mismatch
+  ...
+ok 3 - network # SKIP offline
+not ok 4 - future feature # TODO not done
+    ok 1 - subtest ignored
+ok 5
+`
+	if f := Detect([]byte(data)); f != FormatTAP {
+		t.Fatalf("Detect = %q", f)
+	}
+	results, err := ParseTAP([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name   string
+		status Status
+	}{
+		{"parses input", Pass},
+		{"handles unicode", Fail},
+		{"network", Skip},
+		{"future feature", Pass},
+		{"test 5", Pass},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results: %+v", len(results), results)
+	}
+	for i, w := range want {
+		if results[i].Name != w.name || results[i].Status != w.status {
+			t.Errorf("result %d = %q %s, want %q %s", i, results[i].Name, results[i].Status, w.name, w.status)
+		}
+	}
+	if !strings.Contains(results[1].Output, "mismatch") {
+		t.Errorf("diagnostics = %q", results[1].Output)
+	}
+}
+
+func TestDetectUnknown(t *testing.T) {
+	if f := Detect([]byte("PASS\nok  \texample.com/a\t0.01s\n")); f != "" {
+		t.Errorf("Detect(plain go test) = %q, want unknown", f)
+	}
+	if _, err := ParseFormat("xunit"); err == nil {
+		t.Error("ParseFormat accepted an unknown format")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -566,6 +567,46 @@ func (t *Tmux) GetPanePID(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// PaneInfo describes a pane for session recording.
+type PaneInfo struct {
+	Session string // Session the pane belongs to
+	Width   int
+	Height  int
+	Piped   bool // Output is already piped (pipe-pane) to a command
+}
+
+// GetPaneInfo returns details of a pane, addressed by pane ID (e.g. "%3").
+func (t *Tmux) GetPaneInfo(pane string) (*PaneInfo, error) {
+	out, err := t.run("display-message", "-p", "-t", pane,
+		"#{session_name}|#{pane_width}|#{pane_height}|#{pane_pipe}")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(strings.TrimSpace(out), "|")
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("unexpected pane info for %s: %q", pane, out)
+	}
+	n := len(parts)
+	info := &PaneInfo{
+		Session: strings.Join(parts[:n-3], "|"),
+		Piped:   parts[n-1] == "1",
+	}
+	info.Width, _ = strconv.Atoi(parts[n-3])
+	info.Height, _ = strconv.Atoi(parts[n-2])
+	return info, nil
+}
+
+// PipePane sends everything a pane outputs to a shell command's stdin,
+// replacing any existing pipe. An empty command stops piping.
+func (t *Tmux) PipePane(pane, command string) error {
+	args := []string{"pipe-pane", "-t", pane}
+	if command != "" {
+		args = append(args, command)
+	}
+	_, err := t.run(args...)
+	return err
+}
+
 // hasClaudeChild checks if a process has a child running claude/node.
 // Used when the pane command is a shell (bash, zsh) that launched claude.
 func hasClaudeChild(pid string) bool {
@@ -3,10 +3,13 @@ package townlog
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/retention"
 )
 
 // EventType represents the type of agent lifecycle event.
@@ -222,18 +225,23 @@ func truncate(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-// ReadEvents reads all events from the log file.
+// ReadEvents reads all events from the log file and its rotated segments.
 // Useful for filtering and analysis.
 func ReadEvents(townRoot string) ([]Event, error) {
 	path := logPath(townRoot)
 
-	content, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	f, err := retention.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No log file yet
 		}
 		return nil, fmt.Errorf("reading log file: %w", err)
 	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading log file: %w", err)
+	}
 
 	return ParseLogLines(string(content))
 }
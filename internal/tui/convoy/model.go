@@ -15,6 +15,9 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/estimate"
+	"github.com/steveyegge/gastown/internal/readmodel"
 )
 
 // convoyIDPattern validates convoy IDs to prevent SQL injection.
@@ -23,11 +26,16 @@ var convoyIDPattern = regexp.MustCompile(`^hq-[a-zA-Z0-9-]+$`)
 // subprocessTimeout is the timeout for bd and sqlite3 calls.
 const subprocessTimeout = 5 * time.Second
 
+// refreshInterval is how often convoys are reloaded so progress and ETAs
+// follow work as it completes.
+const refreshInterval = 30 * time.Second
+
 // IssueItem represents a tracked issue within a convoy.
 type IssueItem struct {
-	ID     string
-	Title  string
-	Status string
+	ID       string
+	Title    string
+	Status   string
+	Critical bool // On the convoy's critical path
 }
 
 // ConvoyItem represents a convoy with its tracked issues.
@@ -37,6 +45,7 @@ type ConvoyItem struct {
 	Status   string
 	Issues   []IssueItem
 	Progress string // e.g., "2/5"
+	ETA      string // e.g., "~3h (90%: 7h)"; empty when unknown or landed
 	Expanded bool
 }
 
@@ -67,7 +76,14 @@ func New(townBeads string) Model {
 
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return m.fetchConvoys
+	return tea.Batch(m.fetchConvoys, tick())
+}
+
+// tickMsg triggers a periodic reload.
+type tickMsg time.Time
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg { return tickMsg(t) })
 }
 
 // fetchConvoysMsg is the result of fetching convoys.
@@ -76,9 +92,21 @@ type fetchConvoysMsg struct {
 	err     error
 }
 
-// fetchConvoys fetches convoy data from beads.
+// readModelConvoys is the read model view serving loadConvoys.
+const readModelConvoys = "convoy-tui"
+
+func init() {
+	readmodel.RegisterView(readModelConvoys, func(townRoot string) (any, error) {
+		return loadConvoys(filepath.Join(townRoot, ".beads"))
+	})
+}
+
+// fetchConvoys fetches convoy data from the daemon's read model, or from
+// beads when the daemon isn't running.
 func (m Model) fetchConvoys() tea.Msg {
-	convoys, err := loadConvoys(m.townBeads)
+	convoys, err := readmodel.Get(filepath.Dir(m.townBeads), readModelConvoys, nil, func() ([]ConvoyItem, error) {
+		return loadConvoys(m.townBeads)
+	})
 	return fetchConvoysMsg{convoys: convoys, err: err}
 }
 
@@ -88,36 +116,29 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	defer cancel()
 
 	// Get list of open convoys
-	listArgs := []string{"list", "--type=convoy", "--json"}
-	listCmd := exec.CommandContext(ctx, "bd", listArgs...)
-	listCmd.Dir = townBeads
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
-
-	if err := listCmd.Run(); err != nil {
+	rawConvoys, err := beads.NewWithBeadsDir(filepath.Dir(townBeads), townBeads).WithContext(ctx).List(beads.ListOptions{
+		IssueType: "convoy",
+		Priority:  -1,
+	})
+	if err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
-	var rawConvoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &rawConvoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy list: %w", err)
-	}
-
 	convoys := make([]ConvoyItem, 0, len(rawConvoys))
 	for _, rc := range rawConvoys {
 		issues, completed, total := loadTrackedIssues(townBeads, rc.ID)
-		convoys = append(convoys, ConvoyItem{
+		item := ConvoyItem{
 			ID:       rc.ID,
 			Title:    rc.Title,
 			Status:   rc.Status,
 			Issues:   issues,
 			Progress: fmt.Sprintf("%d/%d", completed, total),
 			Expanded: false,
-		})
+		}
+		if rc.Status != "closed" && completed < total {
+			item.applyEstimate(filepath.Dir(townBeads))
+		}
+		convoys = append(convoys, item)
 	}
 
 	return convoys, nil
@@ -195,6 +216,23 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	return issues, completed, len(issues)
 }
 
+// applyEstimate fills in the convoy's ETA and marks critical-path issues.
+// Estimation is best-effort; failures leave the ETA blank.
+func (c *ConvoyItem) applyEstimate(townRoot string) {
+	ids := make([]string, len(c.Issues))
+	for i, issue := range c.Issues {
+		ids[i] = issue.ID
+	}
+	eta, err := estimate.ForIssues(townRoot, ids)
+	if err != nil || eta.Done() {
+		return
+	}
+	c.ETA = eta.Summary()
+	for i := range c.Issues {
+		c.Issues[i].Critical = eta.CriticalPath.Contains(c.Issues[i].ID)
+	}
+}
+
 // getIssueDetailsBatch fetches details for multiple issues in a single bd show call.
 // Returns a map from issue ID to details.
 func getIssueDetailsBatch(townBeads string, issueIDs []string) map[string]IssueItem {
@@ -206,28 +244,11 @@ func getIssueDetailsBatch(townBeads string, issueIDs []string) map[string]IssueI
 	ctx, cancel := context.WithTimeout(context.Background(), subprocessTimeout)
 	defer cancel()
 
-	// Build args: bd show id1 id2 id3 ... --json
-	args := append([]string{"show"}, issueIDs...)
-	args = append(args, "--json")
-
-	cmd := exec.CommandContext(ctx, "bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = townBeads
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	issues, err := beads.NewRouted(townBeads).WithContext(ctx).ShowMultiple(issueIDs)
+	if err != nil {
 		return result // Return empty map on error
 	}
 
-	var issues []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
-		return result
-	}
-
 	for _, issue := range issues {
 		result[issue.ID] = IssueItem{
 			ID:     issue.ID,
@@ -250,9 +271,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case fetchConvoysMsg:
 		m.err = msg.err
+		// Keep expansion state across refreshes
+		expanded := make(map[string]bool)
+		for _, c := range m.convoys {
+			expanded[c.ID] = c.Expanded
+		}
+		for i := range msg.convoys {
+			msg.convoys[i].Expanded = expanded[msg.convoys[i].ID]
+		}
 		m.convoys = msg.convoys
+		if max := m.maxCursor(); m.cursor > max {
+			m.cursor = max
+		}
 		return m, nil
 
+	case tickMsg:
+		return m, tea.Batch(m.fetchConvoys, tick())
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.keys.Quit):
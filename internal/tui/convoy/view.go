@@ -77,6 +77,9 @@ func (m Model) renderView() string {
 			c.Title,
 			progressStyle.Render(fmt.Sprintf("(%s)", c.Progress)),
 		)
+		if c.ETA != "" {
+			line += " " + progressStyle.Render("ETA "+c.ETA)
+		}
 
 		if isSelected {
 			b.WriteString(selectedStyle.Render(line))
@@ -110,6 +113,9 @@ func (m Model) renderView() string {
 					issue.ID,
 					truncate(issue.Title, 50),
 				)
+				if issue.Critical {
+					issueLine += " ◆"
+				}
 
 				if isIssueSelected {
 					b.WriteString(selectedStyle.Render(issueLine))
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/readmodel"
 )
 
 // convoyIDPattern validates convoy IDs to prevent SQL injection
@@ -22,6 +24,16 @@ var convoyIDPattern = regexp.MustCompile(`^hq-[a-zA-Z0-9-]+$`)
 // Prevents TUI freezing if these commands hang.
 const convoySubprocessTimeout = 5 * time.Second
 
+// readModelConvoys is the read model view serving FetchConvoys, so open
+// feeds share one result, recomputed by the daemon as convoys change.
+const readModelConvoys = "feed-convoys"
+
+func init() {
+	readmodel.RegisterView(readModelConvoys, func(townRoot string) (any, error) {
+		return FetchConvoys(townRoot)
+	})
+}
+
 // Convoy represents a convoy's status for the dashboard
 type Convoy struct {
 	ID        string    `json:"id"`
@@ -88,25 +100,28 @@ func FetchConvoys(townRoot string) (*ConvoyState, error) {
 
 // listConvoys returns convoys with the given status
 func listConvoys(beadsDir, status string) ([]convoyListItem, error) {
-	listArgs := []string{"list", "--type=convoy", "--status=" + status, "--json"}
-
 	ctx, cancel := context.WithTimeout(context.Background(), convoySubprocessTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bd", listArgs...) //nolint:gosec // G204: args are constructed internally
-	cmd.Dir = beadsDir
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
+	issues, err := beads.NewWithBeadsDir(filepath.Dir(beadsDir), beadsDir).WithContext(ctx).List(beads.ListOptions{
+		IssueType: "convoy",
+		Status:    status,
+		Priority:  -1,
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	var items []convoyListItem
-	if err := json.Unmarshal(stdout.Bytes(), &items); err != nil {
-		return nil, err
+	items := make([]convoyListItem, 0, len(issues))
+	for _, issue := range issues {
+		items = append(items, convoyListItem{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			CreatedAt: issue.CreatedAt,
+			ClosedAt:  issue.ClosedAt,
+		})
 	}
-
 	return items, nil
 }
 
@@ -210,22 +225,11 @@ func getIssueStatus(issueID string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), convoySubprocessTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bd", "show", issueID, "--json")
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-
-	if err := cmd.Run(); err != nil {
-		return "unknown"
-	}
-
-	var issues []struct {
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
+	issue, err := beads.NewRouted("").WithContext(ctx).Show(issueID)
+	if err != nil {
 		return "unknown"
 	}
-
-	return issues[0].Status
+	return issue.Status
 }
 
 // Convoy panel styles
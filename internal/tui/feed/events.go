@@ -6,13 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retention"
 )
 
 // EventSource represents a source of events
@@ -23,7 +23,7 @@ type EventSource interface {
 
 // BdActivitySource reads events from bd activity --follow
 type BdActivitySource struct {
-	cmd     *exec.Cmd
+	cmd     *beads.Cmd
 	events  chan Event
 	cancel  context.CancelFunc
 	workDir string
@@ -33,8 +33,9 @@ type BdActivitySource struct {
 func NewBdActivitySource(workDir string) (*BdActivitySource, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	cmd := exec.CommandContext(ctx, "bd", "activity", "--follow")
+	cmd := beads.CommandContext(ctx, "activity", "--follow")
 	cmd.Dir = workDir
+	cmd.Timeout = 0 // Follows until canceled
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -214,6 +215,7 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 
 // GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
 type GtEventsSource struct {
+	path   string
 	file   *os.File
 	events chan Event
 	cancel context.CancelFunc
@@ -240,6 +242,7 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
+		path:   eventsPath,
 		file:   file,
 		events: make(chan Event, 100),
 		cancel: cancel,
@@ -253,6 +256,7 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 // tail follows the file and sends events
 func (s *GtEventsSource) tail(ctx context.Context) {
 	defer close(s.events)
+	defer func() { _ = s.file.Close() }()
 
 	// Seek to end for live tailing
 	_, _ = s.file.Seek(0, 2)
@@ -275,6 +279,15 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 					}
 				}
 			}
+
+			// The log was rotated: continue from the top of the new file
+			if retention.Replaced(s.file, s.path) {
+				if next, err := os.Open(s.path); err == nil {
+					_ = s.file.Close()
+					s.file = next
+					scanner = bufio.NewScanner(s.file)
+				}
+			}
 		}
 	}
 }
@@ -284,10 +297,11 @@ func (s *GtEventsSource) Events() <-chan Event {
 	return s.events
 }
 
-// Close stops the source
+// Close stops the source. The tail goroutine closes the file, which it
+// reopens when the log is rotated.
 func (s *GtEventsSource) Close() error {
 	s.cancel()
-	return s.file.Close()
+	return nil
 }
 
 // parseGtEventLine parses a line from .events.jsonl
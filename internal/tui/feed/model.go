@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/readmodel"
 )
 
 // Panel represents which panel has focus
@@ -159,7 +160,9 @@ func (m *Model) fetchConvoys() tea.Cmd {
 	}
 	townRoot := m.townRoot
 	return func() tea.Msg {
-		state, _ := FetchConvoys(townRoot)
+		state, _ := readmodel.Get(townRoot, readModelConvoys, nil, func() (*ConvoyState, error) {
+			return FetchConvoys(townRoot)
+		})
 		return convoyUpdateMsg{state: state}
 	}
 }
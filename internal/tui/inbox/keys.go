@@ -0,0 +1,128 @@
+package inbox
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the mail TUI.
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+	Switch   key.Binding // folders <-> messages
+	Open     key.Binding
+	Back     key.Binding
+	Mark     key.Binding // select for bulk actions
+	MarkAll  key.Binding
+	Archive  key.Binding
+	Read     key.Binding
+	Reply    key.Binding
+	Compose  key.Binding
+	Refresh  key.Binding
+	Send     key.Binding
+	NextFld  key.Binding // next compose field
+	Help     key.Binding
+	Quit     key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "ctrl+u"),
+			key.WithHelp("pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "ctrl+d"),
+			key.WithHelp("pgdn", "page down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g", "top"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G", "bottom"),
+		),
+		Switch: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "folders/messages"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "select"),
+		),
+		MarkAll: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "select all"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "archive"),
+		),
+		Read: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "mark read"),
+		),
+		Reply: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reply"),
+		),
+		Compose: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "compose"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("R", "ctrl+r"),
+			key.WithHelp("R", "refresh"),
+		),
+		Send: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "send"),
+		),
+		NextFld: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Open, k.Reply, k.Archive, k.Quit, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
+		{k.Switch, k.Open, k.Back, k.Refresh},
+		{k.Mark, k.MarkAll, k.Archive, k.Read},
+		{k.Reply, k.Compose, k.Send, k.Help, k.Quit},
+	}
+}
@@ -0,0 +1,668 @@
+// Package inbox provides an interactive mail client TUI.
+package inbox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// refreshInterval is how often folders and the open folder are re-read.
+const refreshInterval = 10 * time.Second
+
+// Pane is the browse-mode pane that has focus.
+type Pane int
+
+const (
+	PaneFolders Pane = iota
+	PaneMessages
+)
+
+// Mode is what the TUI is showing.
+type Mode int
+
+const (
+	ModeBrowse  Mode = iota // folder list + message list
+	ModeThread              // conversation view
+	ModeCompose             // reply/compose editor
+)
+
+// Compose fields, in tab order.
+const (
+	fieldTo = iota
+	fieldSubject
+	fieldBody
+	fieldCount
+)
+
+// Model is the bubbletea model for the mail TUI.
+type Model struct {
+	source *Source
+
+	// Data
+	folders  []Folder
+	folder   int // index into folders
+	messages []*mail.Message
+	cursor   int             // index into messages
+	selected map[string]bool // message IDs selected for bulk actions
+	thread   []*mail.Message
+
+	// Compose state
+	to       textinput.Model
+	subject  textinput.Model
+	body     textarea.Model
+	field    int
+	replyTo  *mail.Message // nil for a new message
+	prevMode Mode          // mode to return to after compose
+
+	// UI state
+	mode       Mode
+	focus      Pane
+	threadView viewport.Model
+	status     string
+	err        error
+	loading    bool
+	refreshed  time.Time
+
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates a mail TUI model reading the mailbox at address.
+func New(townRoot, address string) Model {
+	to := textinput.New()
+	to.Prompt = "To:      "
+	to.Placeholder = "mayor/, gastown/witness, queue:work, ..."
+	subject := textinput.New()
+	subject.Prompt = "Subject: "
+	body := textarea.New()
+	body.Placeholder = "Message body"
+	body.ShowLineNumbers = false
+
+	return Model{
+		source:     NewSource(townRoot, address),
+		selected:   make(map[string]bool),
+		to:         to,
+		subject:    subject,
+		body:       body,
+		threadView: viewport.New(0, 0),
+		keys:       DefaultKeyMap(),
+		help:       help.New(),
+		loading:    true,
+	}
+}
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(
+		m.fetchFolders(),
+		m.fetchMessages(Folder{Kind: FolderInbox}),
+		refreshTick(),
+		tea.SetWindowTitle("GT Mail"),
+	)
+}
+
+// foldersMsg carries a refreshed folder list.
+type foldersMsg struct {
+	folders []Folder
+	err     error
+}
+
+// messagesMsg carries the messages of one folder.
+type messagesMsg struct {
+	folder   Folder
+	messages []*mail.Message
+	err      error
+}
+
+// threadMsg carries a loaded conversation.
+type threadMsg struct {
+	thread []*mail.Message
+	err    error
+}
+
+// actionMsg reports the outcome of an archive, mark-read or send.
+type actionMsg struct {
+	status string
+	err    error
+	sent   bool // compose succeeded; leave the editor
+}
+
+// refreshMsg triggers a periodic refresh.
+type refreshMsg time.Time
+
+func refreshTick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+		return refreshMsg(t)
+	})
+}
+
+func (m Model) fetchFolders() tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		folders, err := source.Folders()
+		return foldersMsg{folders: folders, err: err}
+	}
+}
+
+func (m Model) fetchMessages(f Folder) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		messages, err := source.Messages(f)
+		return messagesMsg{folder: f, messages: messages, err: err}
+	}
+}
+
+func (m Model) fetchThread(msg *mail.Message, markRead bool) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		if markRead && !msg.Read {
+			_, _ = source.MarkRead([]string{msg.ID})
+		}
+		thread, err := source.Thread(msg)
+		return threadMsg{thread: thread, err: err}
+	}
+}
+
+// currentFolder returns the folder being browsed.
+func (m Model) currentFolder() Folder {
+	if m.folder < len(m.folders) {
+		return m.folders[m.folder]
+	}
+	return Folder{Kind: FolderInbox}
+}
+
+// currentMessage returns the message under the cursor, or nil.
+func (m Model) currentMessage() *mail.Message {
+	if m.cursor < len(m.messages) {
+		return m.messages[m.cursor]
+	}
+	return nil
+}
+
+// targets returns the IDs a bulk action applies to: the selection if any,
+// otherwise the message under the cursor (or the open thread's last message).
+func (m Model) targets() []string {
+	if len(m.selected) > 0 {
+		ids := make([]string, 0, len(m.selected))
+		for _, msg := range m.messages {
+			if m.selected[msg.ID] {
+				ids = append(ids, msg.ID)
+			}
+		}
+		return ids
+	}
+	if msg := m.currentMessage(); msg != nil {
+		return []string{msg.ID}
+	}
+	return nil
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.resize()
+		return m, nil
+
+	case foldersMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.applyFolders(msg.folders)
+		return m, nil
+
+	case messagesMsg:
+		m.loading = false
+		if msg.folder.key() != m.currentFolder().key() {
+			return m, nil // stale: user moved to another folder
+		}
+		m.err = msg.err
+		if msg.err == nil {
+			m.applyMessages(msg.messages)
+		}
+		return m, nil
+
+	case threadMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.thread = msg.thread
+			m.threadView.SetContent(m.renderThread())
+			m.threadView.GotoBottom()
+		}
+		return m, nil
+
+	case actionMsg:
+		m.status = msg.status
+		m.err = msg.err
+		if msg.sent {
+			m.mode = m.prevMode
+		}
+		return m, tea.Batch(m.fetchFolders(), m.fetchMessages(m.currentFolder()))
+
+	case refreshMsg:
+		m.refreshed = time.Time(msg)
+		cmds := []tea.Cmd{refreshTick(), m.fetchFolders()}
+		if m.mode == ModeBrowse {
+			cmds = append(cmds, m.fetchMessages(m.currentFolder()))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case ModeCompose:
+			return m.updateCompose(msg)
+		case ModeThread:
+			return m.updateThread(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	if m.mode == ModeCompose {
+		return m.updateComposeInputs(msg)
+	}
+	return m, nil
+}
+
+// applyFolders installs a refreshed folder list, keeping the current folder
+// selected and reporting newly arrived inbox mail.
+func (m *Model) applyFolders(folders []Folder) {
+	current := m.currentFolder().key()
+	var oldUnread int
+	if len(m.folders) > 0 {
+		oldUnread = m.folders[0].Unread
+	}
+
+	m.folders = folders
+	m.folder = 0
+	for i, f := range folders {
+		if f.key() == current {
+			m.folder = i
+			break
+		}
+	}
+
+	if len(folders) > 0 && !m.refreshed.IsZero() && folders[0].Unread > oldUnread {
+		n := folders[0].Unread - oldUnread
+		m.status = fmt.Sprintf("📬 %d new message%s", n, plural(n))
+	}
+}
+
+// applyMessages installs a refreshed message list, keeping the cursor on
+// the same message when it is still present and dropping stale selections.
+func (m *Model) applyMessages(messages []*mail.Message) {
+	var currentID string
+	if cur := m.currentMessage(); cur != nil {
+		currentID = cur.ID
+	}
+
+	m.messages = messages
+	present := make(map[string]bool, len(messages))
+	for i, msg := range messages {
+		present[msg.ID] = true
+		if msg.ID == currentID {
+			m.cursor = i
+		}
+	}
+	for id := range m.selected {
+		if !present[id] {
+			delete(m.selected, id)
+		}
+	}
+	if m.cursor >= len(messages) {
+		m.cursor = max(len(messages)-1, 0)
+	}
+}
+
+// updateBrowse handles keys in the folder/message view.
+func (m Model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+		m.help.ShowAll = m.showHelp
+		return m, nil
+
+	case key.Matches(msg, m.keys.Switch):
+		if m.focus == PaneFolders {
+			m.focus = PaneMessages
+		} else {
+			m.focus = PaneFolders
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.loading = true
+		return m, tea.Batch(m.fetchFolders(), m.fetchMessages(m.currentFolder()))
+
+	case key.Matches(msg, m.keys.Compose):
+		return m.startCompose(nil)
+	}
+
+	if m.focus == PaneFolders {
+		return m.updateFolders(msg)
+	}
+	return m.updateMessages(msg)
+}
+
+// updateFolders handles navigation in the folder pane.
+func (m Model) updateFolders(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prev := m.folder
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.folder > 0 {
+			m.folder--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.folder < len(m.folders)-1 {
+			m.folder++
+		}
+	case key.Matches(msg, m.keys.Top):
+		m.folder = 0
+	case key.Matches(msg, m.keys.Bottom):
+		m.folder = max(len(m.folders)-1, 0)
+	case key.Matches(msg, m.keys.Open):
+		m.focus = PaneMessages
+		return m, nil
+	}
+
+	if m.folder != prev {
+		m.messages = nil
+		m.cursor = 0
+		m.selected = make(map[string]bool)
+		m.loading = true
+		m.status = ""
+		return m, m.fetchMessages(m.currentFolder())
+	}
+	return m, nil
+}
+
+// updateMessages handles navigation and actions in the message pane.
+func (m Model) updateMessages(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := max(m.listHeight()-1, 1)
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.messages)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.PageUp):
+		m.cursor = max(m.cursor-page, 0)
+	case key.Matches(msg, m.keys.PageDown):
+		m.cursor = min(m.cursor+page, max(len(m.messages)-1, 0))
+	case key.Matches(msg, m.keys.Top):
+		m.cursor = 0
+	case key.Matches(msg, m.keys.Bottom):
+		m.cursor = max(len(m.messages)-1, 0)
+
+	case key.Matches(msg, m.keys.Mark):
+		if cur := m.currentMessage(); cur != nil {
+			if m.selected[cur.ID] {
+				delete(m.selected, cur.ID)
+			} else {
+				m.selected[cur.ID] = true
+			}
+			if m.cursor < len(m.messages)-1 {
+				m.cursor++
+			}
+		}
+	case key.Matches(msg, m.keys.MarkAll):
+		if len(m.selected) == len(m.messages) {
+			m.selected = make(map[string]bool)
+		} else {
+			for _, msg := range m.messages {
+				m.selected[msg.ID] = true
+			}
+		}
+
+	case key.Matches(msg, m.keys.Open):
+		cur := m.currentMessage()
+		if cur == nil {
+			return m, nil
+		}
+		m.mode = ModeThread
+		m.thread = []*mail.Message{cur}
+		m.threadView.SetContent(m.renderThread())
+		return m, m.fetchThread(cur, m.currentFolder().Kind == FolderInbox)
+
+	case key.Matches(msg, m.keys.Reply):
+		if cur := m.currentMessage(); cur != nil {
+			return m.startCompose(cur)
+		}
+
+	case key.Matches(msg, m.keys.Archive):
+		return m.bulkArchive()
+
+	case key.Matches(msg, m.keys.Read):
+		return m.bulkMarkRead()
+	}
+	return m, nil
+}
+
+// updateThread handles keys in the conversation view.
+func (m Model) updateThread(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeBrowse
+		return m, m.fetchMessages(m.currentFolder())
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+		m.help.ShowAll = m.showHelp
+		return m, nil
+	case key.Matches(msg, m.keys.Reply):
+		// Reply to the newest message in the conversation
+		if len(m.thread) > 0 {
+			return m.startCompose(m.thread[len(m.thread)-1])
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Archive):
+		m.mode = ModeBrowse
+		return m.bulkArchive()
+	case key.Matches(msg, m.keys.Top):
+		m.threadView.GotoTop()
+		return m, nil
+	case key.Matches(msg, m.keys.Bottom):
+		m.threadView.GotoBottom()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.threadView, cmd = m.threadView.Update(msg)
+	return m, cmd
+}
+
+// bulkArchive archives the selection (or the current message).
+func (m Model) bulkArchive() (tea.Model, tea.Cmd) {
+	if m.currentFolder().Kind != FolderInbox {
+		m.status = "Archive only applies to the inbox"
+		return m, nil
+	}
+	ids := m.targets()
+	if len(ids) == 0 {
+		return m, nil
+	}
+	m.selected = make(map[string]bool)
+	source := m.source
+	return m, func() tea.Msg {
+		n, err := source.Archive(ids)
+		return actionMsg{status: fmt.Sprintf("Archived %d/%d message%s", n, len(ids), plural(len(ids))), err: err}
+	}
+}
+
+// bulkMarkRead marks the selection (or the current message) read.
+func (m Model) bulkMarkRead() (tea.Model, tea.Cmd) {
+	if m.currentFolder().Kind != FolderInbox {
+		m.status = "Mark read only applies to the inbox"
+		return m, nil
+	}
+	ids := m.targets()
+	if len(ids) == 0 {
+		return m, nil
+	}
+	m.selected = make(map[string]bool)
+	source := m.source
+	return m, func() tea.Msg {
+		n, err := source.MarkRead(ids)
+		return actionMsg{status: fmt.Sprintf("Marked %d/%d message%s read", n, len(ids), plural(len(ids))), err: err}
+	}
+}
+
+// startCompose opens the editor, prefilled for a reply when replyTo is set.
+func (m Model) startCompose(replyTo *mail.Message) (tea.Model, tea.Cmd) {
+	m.prevMode = m.mode
+	m.mode = ModeCompose
+	m.replyTo = replyTo
+	m.to.Reset()
+	m.subject.Reset()
+	m.body.Reset()
+	m.status = ""
+
+	if replyTo != nil {
+		m.to.SetValue(replyTo.From)
+		m.subject.SetValue(replySubject(replyTo.Subject))
+		m.field = fieldBody
+	} else {
+		m.field = fieldTo
+	}
+	m.focusField()
+	return m, textinput.Blink
+}
+
+// focusField moves input focus to m.field.
+func (m *Model) focusField() {
+	m.to.Blur()
+	m.subject.Blur()
+	m.body.Blur()
+	switch m.field {
+	case fieldTo:
+		m.to.Focus()
+	case fieldSubject:
+		m.subject.Focus()
+	case fieldBody:
+		m.body.Focus()
+	}
+}
+
+// updateCompose handles keys in the editor.
+func (m Model) updateCompose(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case msg.String() == "esc":
+		m.mode = m.prevMode
+		m.status = "Discarded draft"
+		return m, nil
+	case key.Matches(msg, m.keys.NextFld):
+		m.field = (m.field + 1) % fieldCount
+		m.focusField()
+		return m, nil
+	case msg.String() == "shift+tab":
+		m.field = (m.field + fieldCount - 1) % fieldCount
+		m.focusField()
+		return m, nil
+	case key.Matches(msg, m.keys.Send):
+		return m.send()
+	}
+	return m.updateComposeInputs(msg)
+}
+
+// updateComposeInputs forwards a message to the focused input.
+func (m Model) updateComposeInputs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.field {
+	case fieldTo:
+		m.to, cmd = m.to.Update(msg)
+	case fieldSubject:
+		m.subject, cmd = m.subject.Update(msg)
+	case fieldBody:
+		m.body, cmd = m.body.Update(msg)
+	}
+	return m, cmd
+}
+
+// send validates the draft and sends it.
+func (m Model) send() (tea.Model, tea.Cmd) {
+	to := strings.TrimSpace(m.to.Value())
+	subject := strings.TrimSpace(m.subject.Value())
+	body := m.body.Value()
+	if to == "" || subject == "" {
+		m.err = fmt.Errorf("recipient and subject are required")
+		return m, nil
+	}
+
+	var out *mail.Message
+	if m.replyTo != nil {
+		out = mail.NewReplyMessage(m.source.Address(), to, subject, body, m.replyTo)
+	} else {
+		out = mail.NewMessage(m.source.Address(), to, subject, body)
+	}
+
+	source := m.source
+	return m, func() tea.Msg {
+		if err := source.Send(out); err != nil {
+			return actionMsg{err: fmt.Errorf("sending: %w", err)}
+		}
+		return actionMsg{status: "Sent to " + to, sent: true}
+	}
+}
+
+// replySubject prefixes "Re: " unless the subject already has it.
+func replySubject(subject string) string {
+	if strings.HasPrefix(subject, "Re: ") {
+		return subject
+	}
+	return "Re: " + subject
+}
+
+// resize lays out size-dependent components.
+func (m *Model) resize() {
+	m.threadView.Width = max(m.width-2, 20)
+	m.threadView.Height = max(m.height-4, 5)
+	m.to.Width = max(m.width-12, 20)
+	m.subject.Width = max(m.width-12, 20)
+	m.body.SetWidth(max(m.width-2, 20))
+	m.body.SetHeight(max(m.height-8, 3))
+	if len(m.thread) > 0 {
+		m.threadView.SetContent(m.renderThread())
+	}
+}
+
+// listHeight is the number of message rows that fit on screen.
+func (m Model) listHeight() int {
+	return max(m.height-5, 3)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
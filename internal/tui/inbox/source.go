@@ -0,0 +1,237 @@
+package inbox
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// archiveLimit caps how many archived messages the archive folder shows.
+const archiveLimit = 200
+
+// FolderKind identifies what a folder lists.
+type FolderKind int
+
+const (
+	FolderInbox FolderKind = iota
+	FolderArchive
+	FolderQueue
+	FolderChannel
+	FolderAnnounce
+)
+
+// Folder is one entry in the folder pane.
+type Folder struct {
+	Kind   FolderKind
+	Name   string // queue, channel or announce name (empty for inbox/archive)
+	Count  int    // messages in the folder, if known
+	Unread int    // unread messages (inbox only)
+}
+
+// Label returns the folder's display name.
+func (f Folder) Label() string {
+	switch f.Kind {
+	case FolderInbox:
+		return "Inbox"
+	case FolderArchive:
+		return "Archive"
+	case FolderQueue:
+		return "queue:" + f.Name
+	case FolderChannel:
+		return "channel:" + f.Name
+	case FolderAnnounce:
+		return "announce:" + f.Name
+	}
+	return f.Name
+}
+
+// key identifies a folder independently of its counts.
+func (f Folder) key() string {
+	return fmt.Sprintf("%d/%s", f.Kind, f.Name)
+}
+
+// Source loads and mutates mail on behalf of the TUI.
+// All mail lives in town beads, so one source serves every folder.
+type Source struct {
+	townRoot string
+	address  string
+	router   *mail.Router
+}
+
+// NewSource creates a mail source for the given town and mailbox address.
+func NewSource(townRoot, address string) *Source {
+	return &Source{
+		townRoot: townRoot,
+		address:  address,
+		router:   mail.NewRouterWithTownRoot(townRoot, townRoot),
+	}
+}
+
+// Address returns the mailbox address the source reads for.
+func (s *Source) Address() string {
+	return s.address
+}
+
+func (s *Source) mailbox() (*mail.Mailbox, error) {
+	return s.router.GetMailbox(s.address)
+}
+
+// Folders returns the inbox and archive followed by every queue, channel
+// and announce channel in the town. Failures to enumerate one kind of
+// folder don't hide the others.
+func (s *Source) Folders() ([]Folder, error) {
+	inbox := Folder{Kind: FolderInbox}
+	mailbox, err := s.mailbox()
+	if err != nil {
+		return nil, err
+	}
+	total, unread, err := mailbox.Count()
+	if err != nil {
+		return nil, fmt.Errorf("reading inbox: %w", err)
+	}
+	inbox.Count, inbox.Unread = total, unread
+
+	folders := []Folder{inbox, {Kind: FolderArchive}}
+
+	bd := beads.NewWithBeadsDir(s.townRoot, beads.ResolveBeadsDir(s.townRoot))
+	if queues, err := bd.ListQueueBeads(); err == nil {
+		var names []string
+		for _, issue := range queues {
+			if name := beads.ParseQueueFields(issue.Description).Name; name != "" {
+				names = append(names, name)
+			}
+		}
+		folders = append(folders, namedFolders(FolderQueue, names)...)
+	}
+
+	if channels, err := bd.ListChannelBeads(); err == nil {
+		var names []string
+		for name := range channels {
+			names = append(names, name)
+		}
+		folders = append(folders, namedFolders(FolderChannel, names)...)
+	}
+
+	if cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(s.townRoot)); err == nil {
+		var names []string
+		for name := range cfg.Announces {
+			names = append(names, name)
+		}
+		folders = append(folders, namedFolders(FolderAnnounce, names)...)
+	}
+
+	return folders, nil
+}
+
+// namedFolders builds folders of one kind, sorted by name.
+func namedFolders(kind FolderKind, names []string) []Folder {
+	sort.Strings(names)
+	folders := make([]Folder, 0, len(names))
+	for _, name := range names {
+		folders = append(folders, Folder{Kind: kind, Name: name})
+	}
+	return folders
+}
+
+// Messages returns the messages in a folder, newest first.
+func (s *Source) Messages(f Folder) ([]*mail.Message, error) {
+	switch f.Kind {
+	case FolderInbox:
+		mailbox, err := s.mailbox()
+		if err != nil {
+			return nil, err
+		}
+		return mailbox.List()
+
+	case FolderArchive:
+		// Archived mail is closed in beads; the search index already
+		// tracks it per mailbox, so reuse it rather than re-querying.
+		idx, _, err := s.router.RefreshIndex(false)
+		if idx == nil {
+			return nil, err
+		}
+		archived := true
+		hits := idx.Search(&mail.SearchQuery{Archived: &archived}, mail.IndexSearchOptions{
+			Mailbox: s.address,
+			Limit:   archiveLimit,
+		})
+		messages := make([]*mail.Message, 0, len(hits))
+		for _, hit := range hits {
+			messages = append(messages, hit.Message)
+		}
+		return messages, nil
+
+	case FolderQueue:
+		return s.router.ListByLabel("queue:" + f.Name)
+	case FolderChannel:
+		return s.router.ListByLabel("channel:" + f.Name)
+	case FolderAnnounce:
+		return s.router.ListByLabel(mail.AnnounceLabel(f.Name))
+	}
+	return nil, fmt.Errorf("unknown folder %q", f.Label())
+}
+
+// Thread returns the conversation msg belongs to, oldest first.
+// Messages without a thread are shown on their own.
+func (s *Source) Thread(msg *mail.Message) ([]*mail.Message, error) {
+	if msg.ThreadID == "" {
+		return []*mail.Message{msg}, nil
+	}
+	mailbox, err := s.mailbox()
+	if err != nil {
+		return nil, err
+	}
+	thread, err := mailbox.ListByThread(msg.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if len(thread) == 0 {
+		return []*mail.Message{msg}, nil
+	}
+	return thread, nil
+}
+
+// MarkRead marks messages read without archiving them.
+// Returns how many succeeded and the first error.
+func (s *Source) MarkRead(ids []string) (int, error) {
+	mailbox, err := s.mailbox()
+	if err != nil {
+		return 0, err
+	}
+	return forEach(ids, mailbox.MarkReadOnly)
+}
+
+// Archive archives messages, the same as `gt mail archive`.
+// Returns how many succeeded and the first error.
+func (s *Source) Archive(ids []string) (int, error) {
+	mailbox, err := s.mailbox()
+	if err != nil {
+		return 0, err
+	}
+	return forEach(ids, mailbox.Delete)
+}
+
+// Send sends a message from the source's address.
+func (s *Source) Send(msg *mail.Message) error {
+	msg.From = s.address
+	return s.router.Send(msg)
+}
+
+// forEach applies fn to every ID, continuing past failures.
+func forEach(ids []string, fn func(string) error) (int, error) {
+	done := 0
+	var firstErr error
+	for _, id := range ids {
+		if err := fn(id); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", id, err)
+			}
+			continue
+		}
+		done++
+	}
+	return done, firstErr
+}
@@ -0,0 +1,287 @@
+package inbox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// folderPaneWidth is the width of the folder list in browse mode.
+const folderPaneWidth = 24
+
+// Styles for the mail TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	unreadStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("15"))
+
+	readStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("7"))
+
+	markStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	urgentStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	headerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("14")) // cyan
+
+	paneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("8"))
+
+	focusedPaneStyle = paneStyle.
+				BorderForeground(lipgloss.Color("12"))
+
+	statusStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")) // green
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	if m.width == 0 {
+		return "Loading mail..."
+	}
+
+	var body string
+	switch m.mode {
+	case ModeThread:
+		body = m.renderThreadView()
+	case ModeCompose:
+		body = m.renderCompose()
+	default:
+		body = m.renderBrowse()
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("📬 Mail: " + m.source.Address()))
+	b.WriteString("\n")
+	b.WriteString(body)
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
+
+// renderBrowse renders the folder and message panes side by side.
+func (m Model) renderBrowse() string {
+	height := m.listHeight()
+
+	folderStyle, messageStyle := paneStyle, focusedPaneStyle
+	if m.focus == PaneFolders {
+		folderStyle, messageStyle = focusedPaneStyle, paneStyle
+	}
+
+	folders := folderStyle.
+		Width(folderPaneWidth).
+		Height(height).
+		Render(m.renderFolders(height))
+
+	messageWidth := max(m.width-folderPaneWidth-4, 20)
+	messages := messageStyle.
+		Width(messageWidth).
+		Height(height).
+		Render(m.renderMessages(messageWidth, height))
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, folders, messages)
+}
+
+// renderFolders renders the folder list.
+func (m Model) renderFolders(height int) string {
+	var lines []string
+	for i, f := range m.folders {
+		label := f.Label()
+		if f.Kind == FolderInbox && f.Unread > 0 {
+			label = fmt.Sprintf("%s (%d)", label, f.Unread)
+		}
+		line := " " + truncate(label, folderPaneWidth-2)
+
+		switch {
+		case i == m.folder:
+			lines = append(lines, selectedStyle.Width(folderPaneWidth).Render(line))
+		case f.Kind == FolderInbox && f.Unread > 0:
+			lines = append(lines, unreadStyle.Render(line))
+		default:
+			lines = append(lines, readStyle.Render(line))
+		}
+	}
+	return strings.Join(window(lines, m.folder, height), "\n")
+}
+
+// renderMessages renders the message list of the current folder.
+func (m Model) renderMessages(width, height int) string {
+	if len(m.messages) == 0 {
+		if m.loading {
+			return dimStyle.Render(" Loading...")
+		}
+		return dimStyle.Render(" No messages in " + m.currentFolder().Label())
+	}
+
+	const fromWidth, ageWidth = 22, 5
+	subjectWidth := max(width-fromWidth-ageWidth-7, 10)
+
+	lines := make([]string, 0, len(m.messages))
+	for i, msg := range m.messages {
+		mark := " "
+		if m.selected[msg.ID] {
+			mark = markStyle.Render("✓")
+		}
+		dot := " "
+		if !msg.Read {
+			dot = "●"
+		}
+		if msg.Priority == mail.PriorityUrgent || msg.Priority == mail.PriorityHigh {
+			dot = urgentStyle.Render("!")
+		}
+
+		text := fmt.Sprintf("%-*s %-*s %*s",
+			fromWidth, truncate(msg.From, fromWidth),
+			subjectWidth, truncate(msg.Subject, subjectWidth),
+			ageWidth, age(msg.Timestamp))
+
+		switch {
+		case i == m.cursor && m.focus == PaneMessages:
+			text = selectedStyle.Render(text)
+		case i == m.cursor:
+			text = selectedStyle.Foreground(lipgloss.Color("7")).Render(text)
+		case !msg.Read:
+			text = unreadStyle.Render(text)
+		default:
+			text = readStyle.Render(text)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", mark, dot, text))
+	}
+	return strings.Join(window(lines, m.cursor, height), "\n")
+}
+
+// renderThreadView renders the conversation viewport.
+func (m Model) renderThreadView() string {
+	return paneStyle.Width(max(m.width-2, 20)).Render(m.threadView.View())
+}
+
+// renderThread renders every message in the conversation, oldest first.
+func (m Model) renderThread() string {
+	width := max(m.threadView.Width-2, 20)
+	var b strings.Builder
+	for i, msg := range m.thread {
+		if i > 0 {
+			b.WriteString(dimStyle.Render(strings.Repeat("─", width)))
+			b.WriteString("\n")
+		}
+		b.WriteString(headerStyle.Render(msg.Subject))
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render(fmt.Sprintf("From: %s  To: %s  %s",
+			msg.From, msg.To, msg.Timestamp.Local().Format("2006-01-02 15:04"))))
+		b.WriteString("\n")
+		if len(msg.CC) > 0 {
+			b.WriteString(dimStyle.Render("CC: " + strings.Join(msg.CC, ", ")))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(msg.Body))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// renderCompose renders the reply/compose editor.
+func (m Model) renderCompose() string {
+	heading := "New message"
+	if m.replyTo != nil {
+		heading = "Reply to " + m.replyTo.From
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(heading))
+	b.WriteString("\n")
+	b.WriteString(m.to.View())
+	b.WriteString("\n")
+	b.WriteString(m.subject.View())
+	b.WriteString("\n\n")
+	b.WriteString(m.body.View())
+	return b.String()
+}
+
+// renderFooter renders status, errors and help.
+func (m Model) renderFooter() string {
+	var parts []string
+	if m.err != nil {
+		parts = append(parts, errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	} else if m.status != "" {
+		parts = append(parts, statusStyle.Render(m.status))
+	}
+	if n := len(m.selected); n > 0 && m.mode == ModeBrowse {
+		parts = append(parts, markStyle.Render(fmt.Sprintf("%d selected", n)))
+	}
+
+	switch {
+	case m.mode == ModeCompose:
+		parts = append(parts, helpStyle.Render("tab:next field  ctrl+s:send  esc:cancel"))
+	case m.showHelp:
+		parts = append(parts, m.help.View(m.keys))
+	case m.mode == ModeThread:
+		parts = append(parts, helpStyle.Render("j/k:scroll  r:reply  a:archive  esc:back  q:quit"))
+	default:
+		parts = append(parts, helpStyle.Render("tab:pane  j/k:navigate  enter:open  space:select  a:archive  m:read  r:reply  c:compose  ?:help"))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// window returns the slice of lines that fits in height, keeping cursor visible.
+func window(lines []string, cursor, height int) []string {
+	if len(lines) <= height {
+		return lines
+	}
+	start := max(cursor-height+1, 0)
+	end := min(start+height, len(lines))
+	return lines[start:end]
+}
+
+// age formats how long ago t was, compactly.
+func age(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
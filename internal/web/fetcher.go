@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/estimate"
+	"github.com/steveyegge/gastown/internal/readmodel"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -31,28 +34,32 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 }
 
 
-// FetchConvoys fetches all open convoys with their activity data.
-func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
-	// List all open convoy-type issues
-	listArgs := []string{"list", "--type=convoy", "--status=open", "--json"}
-	listCmd := exec.Command("bd", listArgs...)
-	listCmd.Dir = f.townBeads
+// readModelConvoys is the read model view serving the dashboard.
+const readModelConvoys = "dashboard-convoys"
 
-	var stdout bytes.Buffer
-	listCmd.Stdout = &stdout
+func init() {
+	readmodel.RegisterView(readModelConvoys, func(townRoot string) (any, error) {
+		f := &LiveConvoyFetcher{townBeads: filepath.Join(townRoot, ".beads")}
+		return f.fetchConvoys()
+	})
+}
 
-	if err := listCmd.Run(); err != nil {
-		return nil, fmt.Errorf("listing convoys: %w", err)
-	}
+// FetchConvoys fetches all open convoys with their activity data, from the
+// daemon's read model when it is running.
+func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	return readmodel.Get(filepath.Dir(f.townBeads), readModelConvoys, nil, f.fetchConvoys)
+}
 
-	var convoys []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		CreatedAt string `json:"created_at"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
-		return nil, fmt.Errorf("parsing convoy list: %w", err)
+// fetchConvoys queries beads for open convoys and their tracked issues.
+func (f *LiveConvoyFetcher) fetchConvoys() ([]ConvoyRow, error) {
+	// List all open convoy-type issues
+	convoys, err := beads.NewWithBeadsDir(filepath.Dir(f.townBeads), f.townBeads).List(beads.ListOptions{
+		IssueType: "convoy",
+		Status:    "open",
+		Priority:  -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
 	// Build convoy rows with activity data
@@ -123,6 +130,10 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 		// Calculate work status based on progress and activity
 		row.WorkStatus = calculateWorkStatus(row.Completed, row.Total, row.LastActivity.ColorClass)
 
+		if row.Completed < row.Total {
+			f.applyEstimate(&row, tracked)
+		}
+
 		// Get tracked issues for expandable view
 		row.TrackedIssues = make([]TrackedIssue, len(tracked))
 		for i, t := range tracked {
@@ -140,6 +151,23 @@ func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	return rows, nil
 }
 
+// applyEstimate fills in a convoy row's ETA. Estimation is best-effort;
+// failures leave the ETA blank.
+func (f *LiveConvoyFetcher) applyEstimate(row *ConvoyRow, tracked []trackedIssueInfo) {
+	ids := make([]string, len(tracked))
+	for i, t := range tracked {
+		ids[i] = t.ID
+	}
+	eta, err := estimate.ForIssues(filepath.Dir(f.townBeads), ids)
+	if err != nil || eta.Done() {
+		return
+	}
+	row.ETA = eta.Summary()
+	row.ETADetail = fmt.Sprintf("50%% by %s, 90%% by %s (%s confidence). Critical path: %s",
+		eta.ETA50.Local().Format("Mon 15:04"), eta.ETA90.Local().Format("Mon 15:04"),
+		eta.Confidence(), strings.Join(eta.CriticalPath, " → "))
+}
+
 // trackedIssueInfo holds info about an issue being tracked by a convoy.
 type trackedIssueInfo struct {
 	ID           string
@@ -234,26 +262,8 @@ func (f *LiveConvoyFetcher) getIssueDetailsBatch(issueIDs []string) map[string]*
 		return result
 	}
 
-	args := append([]string{"show"}, issueIDs...)
-	args = append(args, "--json")
-
-	// #nosec G204 -- bd is a trusted internal tool, args are issue IDs
-	showCmd := exec.Command("bd", args...)
-	var stdout bytes.Buffer
-	showCmd.Stdout = &stdout
-
-	if err := showCmd.Run(); err != nil {
-		return result
-	}
-
-	var issues []struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		Assignee  string `json:"assignee"`
-		UpdatedAt string `json:"updated_at"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
+	issues, err := beads.NewRouted("").ShowMultiple(issueIDs)
+	if err != nil {
 		return result
 	}
 
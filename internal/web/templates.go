@@ -49,6 +49,8 @@ type ConvoyRow struct {
 	Completed     int
 	Total         int
 	LastActivity  activity.Info
+	ETA           string // e.g., "~3h (90%: 7h)"; empty when unknown or complete
+	ETADetail     string // Landing times, confidence and critical path
 	TrackedIssues []TrackedIssue
 }
 
@@ -175,6 +175,43 @@ func TestConvoyTemplate_ProgressDisplay(t *testing.T) {
 	}
 }
 
+func TestConvoyTemplate_ETADisplay(t *testing.T) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		t.Fatalf("LoadTemplates() error = %v", err)
+	}
+
+	data := ConvoyData{
+		Convoys: []ConvoyRow{
+			{
+				ID:        "hq-cv-eta",
+				Title:     "Test",
+				Status:    "open",
+				Progress:  "1/4",
+				Completed: 1,
+				Total:     4,
+				ETA:       "~3h (90%: 7h)",
+				ETADetail: "Critical path: gt-a → gt-b",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, "convoy.html", data)
+	if err != nil {
+		t.Fatalf("ExecuteTemplate() error = %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "~3h (90%: 7h)") {
+		t.Error("Template should display the ETA")
+	}
+	if !strings.Contains(output, "Critical path: gt-a → gt-b") {
+		t.Error("Template should include the critical path in the ETA tooltip")
+	}
+}
+
 func TestConvoyTemplate_StatusIndicators(t *testing.T) {
 	tmpl, err := LoadTemplates()
 	if err != nil {
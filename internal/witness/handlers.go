@@ -1,7 +1,6 @@
 package witness
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -395,33 +394,17 @@ func createCleanupWisp(workDir, polecatName, issueID, branch string) (string, er
 		description += fmt.Sprintf("\nBranch: %s", branch)
 	}
 
-	labels := strings.Join(CleanupWispLabels(polecatName, "pending"), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	wisp, err := beads.New(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Labels:      CleanupWispLabels(polecatName, "pending"),
+		Priority:    -1,
+		Ephemeral:   true,
+	})
 	if err != nil {
 		return "", err
 	}
-
-	// Extract wisp ID from output (bd create outputs "Created: <id>")
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	// Try to extract ID from output
-	for _, line := range strings.Split(output, "\n") {
-		line = strings.TrimSpace(line)
-		// Look for bead ID pattern (e.g., "gt-abc123")
-		if strings.Contains(line, "-") && len(line) < 20 {
-			return line, nil
-		}
-	}
-
-	return output, nil
+	return wisp.ID, nil
 }
 
 // createSwarmWisp creates a wisp to track swarm (batch) work.
@@ -429,33 +412,27 @@ func createSwarmWisp(workDir string, payload *SwarmStartPayload) (string, error)
 	title := fmt.Sprintf("swarm:%s", payload.SwarmID)
 	description := fmt.Sprintf("Tracking batch: %s\nTotal: %d polecats", payload.SwarmID, payload.Total)
 
-	labels := strings.Join(SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt), ",")
-
-	output, err := util.ExecWithOutput(workDir, "bd", "create",
-		"--ephemeral",
-		"--title", title,
-		"--description", description,
-		"--labels", labels,
-	)
+	wisp, err := beads.New(workDir).Create(beads.CreateOptions{
+		Title:       title,
+		Description: description,
+		Labels:      SwarmWispLabels(payload.SwarmID, payload.Total, 0, payload.StartedAt),
+		Priority:    -1,
+		Ephemeral:   true,
+	})
 	if err != nil {
 		return "", err
 	}
-
-	if strings.HasPrefix(output, "Created:") {
-		return strings.TrimSpace(strings.TrimPrefix(output, "Created:")), nil
-	}
-
-	return output, nil
+	return wisp.ID, nil
 }
 
 // findCleanupWisp finds an existing cleanup wisp for a polecat.
 func findCleanupWisp(workDir, polecatName string) (string, error) {
-	output, err := util.ExecWithOutput(workDir, "bd", "list",
-		"--ephemeral",
-		"--labels", fmt.Sprintf("polecat:%s,state:merge-requested", polecatName),
-		"--status", "open",
-		"--json",
-	)
+	wisps, err := beads.New(workDir).List(beads.ListOptions{
+		Status:    "open",
+		Labels:    []string{"polecat:" + polecatName, "state:merge-requested"},
+		Priority:  -1,
+		Ephemeral: true,
+	})
 	if err != nil {
 		// Empty result is fine
 		if strings.Contains(err.Error(), "no issues found") {
@@ -463,28 +440,10 @@ func findCleanupWisp(workDir, polecatName string) (string, error) {
 		}
 		return "", err
 	}
-
-	// Parse JSON to get the wisp ID
-	if output == "" || output == "[]" || output == "null" {
+	if len(wisps) == 0 {
 		return "", nil
 	}
-
-	// Simple extraction - look for "id" field
-	// Full JSON parsing would add dependency on encoding/json
-	if idx := strings.Index(output, `"id":`); idx >= 0 {
-		rest := output[idx+5:]
-		rest = strings.TrimLeft(rest, ` "`)
-		if endIdx := strings.IndexAny(rest, `",}`); endIdx > 0 {
-			return rest[:endIdx], nil
-		}
-	}
-
-	return "", nil
-}
-
-// agentBeadResponse is used to parse the bd show --json response for agent beads.
-type agentBeadResponse struct {
-	Description string `json:"description"`
+	return wisps[0].ID, nil
 }
 
 // getCleanupStatus retrieves the cleanup_status from a polecat's agent bead.
@@ -504,25 +463,15 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 	prefix := beads.GetPrefixForRig(townRoot, rigName)
 	agentBeadID := beads.PolecatBeadIDWithPrefix(prefix, rigName, polecatName)
 
-	output, err := util.ExecWithOutput(workDir, "bd", "show", agentBeadID, "--json")
+	issue, err := beads.New(workDir).Show(agentBeadID)
 	if err != nil {
 		// Agent bead doesn't exist or bd failed - return empty (unknown status)
 		return ""
 	}
 
-	if output == "" {
-		return ""
-	}
-
-	// Parse the JSON response
-	var resp agentBeadResponse
-	if err := json.Unmarshal([]byte(output), &resp); err != nil {
-		return ""
-	}
-
 	// Parse cleanup_status from description
 	// Description format has "cleanup_status: <value>" line
-	for _, line := range strings.Split(resp.Description, "\n") {
+	for _, line := range strings.Split(issue.Description, "\n") {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(strings.ToLower(line), "cleanup_status:") {
 			value := strings.TrimSpace(strings.TrimPrefix(line, "cleanup_status:"))
@@ -619,17 +568,18 @@ DO NOT nuke without --force after recovery.`,
 // UpdateCleanupWispState updates a cleanup wisp's state label.
 func UpdateCleanupWispState(workDir, wispID, newState string) error {
 	// Get current labels to preserve other labels
-	output, err := util.ExecWithOutput(workDir, "bd", "show", wispID, "--json")
+	b := beads.New(workDir)
+	wisp, err := b.Show(wispID)
 	if err != nil {
 		return fmt.Errorf("getting wisp: %w", err)
 	}
 
 	// Extract polecat name from existing labels for the update
 	var polecatName string
-	if idx := strings.Index(output, `polecat:`); idx >= 0 {
-		rest := output[idx+8:]
-		if endIdx := strings.IndexAny(rest, `",]}`); endIdx > 0 {
-			polecatName = rest[:endIdx]
+	for _, label := range wisp.Labels {
+		if name, ok := strings.CutPrefix(label, "polecat:"); ok && name != "" {
+			polecatName = name
+			break
 		}
 	}
 
@@ -638,9 +588,7 @@ func UpdateCleanupWispState(workDir, wispID, newState string) error {
 	}
 
 	// Update with new state
-	newLabels := strings.Join(CleanupWispLabels(polecatName, newState), ",")
-
-	return util.ExecRun(workDir, "bd", "update", wispID, "--labels", newLabels)
+	return b.SetLabels(wispID, CleanupWispLabels(polecatName, newState)...)
 }
 
 // NukePolecat executes the actual nuke operation for a polecat.